@@ -0,0 +1,681 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/history"
+	"github.com/linkflow/engine/internal/history/ndc"
+)
+
+type admin struct {
+	historyAddr     string
+	historyHTTPAddr string
+	matchingAddr    string
+	controlPlane    string
+	token           string
+	jsonOutput      bool
+	timeout         time.Duration
+}
+
+func main() {
+	var a admin
+	flag.StringVar(&a.historyAddr, "history-addr", getEnv("HISTORY_ADDR", "localhost:7234"), "History service address")
+	flag.StringVar(&a.historyHTTPAddr, "history-http-addr", getEnv("HISTORY_HTTP_ADDR", "http://localhost:8080"), "History service HTTP address")
+	flag.StringVar(&a.matchingAddr, "matching-addr", getEnv("MATCHING_ADDR", "localhost:7235"), "Matching service address")
+	flag.StringVar(&a.controlPlane, "control-plane-addr", getEnv("CONTROL_PLANE_ADDR", "localhost:7240"), "Control plane address")
+	flag.StringVar(&a.token, "token", os.Getenv("LINKFLOW_TOKEN"), "Bearer token identifying the caller to admin RPCs")
+	flag.BoolVar(&a.jsonOutput, "json", false, "Print responses as JSON")
+	flag.DurationVar(&a.timeout, "timeout", 30*time.Second, "Timeout for each RPC")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+	if a.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+a.token)
+	}
+
+	var err error
+	switch args[0] + " " + args[1] {
+	case "dlq list":
+		err = a.dlqList(ctx)
+	case "dlq replay":
+		err = a.dlqReplay(ctx, args[2:])
+	case "dlq purge":
+		err = a.dlqPurge(ctx, args[2:])
+	case "partitions describe":
+		err = a.partitionsDescribe(ctx, args[2:])
+	case "shards describe":
+		err = a.shardsDescribe(ctx)
+	case "shards rebalance":
+		err = a.shardsRebalance(ctx)
+	case "execution force-terminate":
+		err = a.forceTerminate(ctx, args[2:])
+	case "execution mutable-state":
+		err = a.describeMutableState(ctx, args[2:])
+	case "execution verify":
+		err = a.checkConsistency(ctx, "execution verify", args[2:])
+	case "execution repair":
+		err = a.checkConsistency(ctx, "execution repair", args[2:])
+	case "roles list":
+		err = a.rolesList(ctx)
+	case "roles assign":
+		err = a.rolesAssign(ctx, args[2:])
+	case "roles revoke":
+		err = a.rolesRevoke(ctx, args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", strings.Join(args, " "))
+		printUsage()
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatalf("%s %s failed: %v", args[0], args[1], err)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, `Usage: admin [options] <command> [args]
+
+Commands:
+  dlq list                               List tasks in the matching dead letter queue
+  dlq replay <task-id>... | --all        Move dead-lettered tasks back to their queue
+  dlq purge --yes                        Delete every task in the dead letter queue
+  partitions describe [flags]            Show which matching partition a task queue routes to
+      --namespace    Namespace (default: default)
+      --task-queue   Task queue (required)
+      --workflow-id  Workflow ID whose sticky bindings to show
+  shards describe                        Show history shard ownership and load
+  shards rebalance                       Acquire history shards this host is missing
+  execution force-terminate [flags]      Terminate a stuck execution
+      --namespace    Namespace (default: default)
+      --workflow-id  Workflow ID (required)
+      --run-id       Run ID (default: current run)
+      --reason       Reason recorded in history (required)
+  execution mutable-state [flags]        Show an execution's mutable state and version history
+      --namespace    Namespace (default: default)
+      --workflow-id  Workflow ID (required)
+      --run-id       Run ID (required)
+      --clusters     Comma-separated history HTTP addresses to compare (default: --history-http-addr)
+  execution verify [flags]               Compare an execution's mutable state with its history
+      --namespace    Namespace (default: default)
+      --workflow-id  Workflow ID (required)
+      --run-id       Run ID (required)
+  execution repair [flags]               Rebuild an execution's mutable state from history if it drifted
+      --namespace    Namespace (default: default)
+      --workflow-id  Workflow ID (required)
+      --run-id       Run ID (required)
+  roles list                             List control plane role assignments
+  roles assign <identity> <role>         Give an identity the admin, operator or viewer role
+  roles revoke <identity>                Remove an identity's role
+
+Options:
+  --history-addr       History service address (or set HISTORY_ADDR env var)
+  --history-http-addr  History service HTTP address (or set HISTORY_HTTP_ADDR env var)
+  --matching-addr  Matching service address (or set MATCHING_ADDR env var)
+  --control-plane-addr  Control plane address (or set CONTROL_PLANE_ADDR env var)
+  --token          Bearer token admin RPCs are authorized with (or set LINKFLOW_TOKEN env var)
+  --json           Print responses as JSON
+  --timeout        Timeout for each RPC (default: 30s)
+
+Examples:
+  admin dlq list
+  admin dlq replay default:wf-1:run-1:2:5
+  admin partitions describe --task-queue orders --workflow-id wf-1
+  admin --json shards describe
+  admin execution force-terminate --workflow-id wf-1 --reason "stuck after deploy"
+  admin execution mutable-state --workflow-id wf-1 --run-id run-1 --clusters http://dc1:8080,http://dc2:8080
+  admin execution verify --workflow-id wf-1 --run-id run-1
+  admin --token $TOKEN roles assign alice operator`)
+}
+
+func (a *admin) matchingClient() (matchingv1.MatchingServiceClient, func(), error) {
+	conn, err := grpc.NewClient(a.matchingAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to matching service: %w", err)
+	}
+	return matchingv1.NewMatchingServiceClient(conn), func() { conn.Close() }, nil
+}
+
+func (a *admin) accessClient() (*controlplane.AccessClient, func(), error) {
+	conn, err := grpc.NewClient(a.controlPlane, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to control plane: %w", err)
+	}
+	return controlplane.NewAccessClient(conn), func() { conn.Close() }, nil
+}
+
+func (a *admin) historyClient() (historyv1.HistoryServiceClient, func(), error) {
+	conn, err := grpc.NewClient(a.historyAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to history service: %w", err)
+	}
+	return historyv1.NewHistoryServiceClient(conn), func() { conn.Close() }, nil
+}
+
+// printJSON writes msg with the proto field names used in the API docs.
+func printJSON(msg proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, UseProtoNames: true, EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func newTable() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
+
+func (a *admin) dlqList(ctx context.Context) error {
+	client, closeConn, err := a.matchingClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.ListDLQTasks(ctx, &matchingv1.ListDLQTasksRequest{})
+	if err != nil {
+		return err
+	}
+	if a.jsonOutput {
+		return printJSON(resp)
+	}
+
+	if len(resp.GetTasks()) == 0 {
+		fmt.Println("Dead letter queue is empty")
+		return nil
+	}
+	w := newTable()
+	fmt.Fprintln(w, "TASK ID\tWORKFLOW ID\tRUN ID\tATTEMPTS\tFAILED AT\tREASON")
+	for _, task := range resp.GetTasks() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			task.GetTaskId(),
+			task.GetWorkflowExecution().GetWorkflowId(),
+			task.GetWorkflowExecution().GetRunId(),
+			task.GetAttempts(),
+			task.GetFailedTime().AsTime().Local().Format(time.RFC3339),
+			task.GetReason(),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d task(s)\n", len(resp.GetTasks()))
+	return nil
+}
+
+func (a *admin) dlqReplay(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("dlq replay", flag.ExitOnError)
+	all := fs.Bool("all", false, "Replay every task in the dead letter queue")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*all && fs.NArg() == 0 {
+		return fmt.Errorf("pass one or more task IDs, or --all")
+	}
+
+	client, closeConn, err := a.matchingClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.ReplayDLQTasks(ctx, &matchingv1.ReplayDLQTasksRequest{
+		TaskIds: fs.Args(),
+		All:     *all,
+	})
+	if err != nil {
+		return err
+	}
+	if a.jsonOutput {
+		return printJSON(resp)
+	}
+
+	for _, taskID := range resp.GetReplayedTaskIds() {
+		fmt.Printf("replayed  %s\n", taskID)
+	}
+	failed := make([]string, 0, len(resp.GetFailures()))
+	for taskID := range resp.GetFailures() {
+		failed = append(failed, taskID)
+	}
+	sort.Strings(failed)
+	for _, taskID := range failed {
+		fmt.Printf("failed    %s: %s\n", taskID, resp.GetFailures()[taskID])
+	}
+	fmt.Printf("\n%d replayed, %d failed\n", len(resp.GetReplayedTaskIds()), len(failed))
+	if len(failed) > 0 {
+		return fmt.Errorf("%d task(s) could not be replayed", len(failed))
+	}
+	return nil
+}
+
+func (a *admin) dlqPurge(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("dlq purge", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "Confirm deleting every dead-lettered task")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*yes {
+		return fmt.Errorf("purging deletes every dead-lettered task; pass --yes to confirm")
+	}
+
+	client, closeConn, err := a.matchingClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.PurgeDLQ(ctx, &matchingv1.PurgeDLQRequest{})
+	if err != nil {
+		return err
+	}
+	if a.jsonOutput {
+		return printJSON(resp)
+	}
+	fmt.Printf("Purged %d task(s) from the dead letter queue\n", resp.GetPurged())
+	return nil
+}
+
+func (a *admin) partitionsDescribe(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("partitions describe", flag.ExitOnError)
+	namespace := fs.String("namespace", "default", "Namespace")
+	taskQueue := fs.String("task-queue", "", "Task queue")
+	workflowID := fs.String("workflow-id", "", "Workflow ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *taskQueue == "" {
+		return fmt.Errorf("--task-queue is required")
+	}
+
+	client, closeConn, err := a.matchingClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	desc, err := client.DescribeTaskQueuePartitions(ctx, &matchingv1.DescribeTaskQueuePartitionsRequest{
+		Namespace:  *namespace,
+		TaskQueue:  *taskQueue,
+		WorkflowId: *workflowID,
+	})
+	if err != nil {
+		return err
+	}
+	if a.jsonOutput {
+		return printJSON(desc)
+	}
+
+	w := newTable()
+	fmt.Fprintln(w, "PARTITION\tWEIGHT\tOWNER\tPENDING\tPOLLERS\tTASK QUEUES\tTASKS ADDED")
+	for _, p := range desc.GetPartitions() {
+		fmt.Fprintf(w, "%d\t%d\t%v\t%d\t%d\t%d\t%d\n", p.GetId(), p.GetWeight(), p.GetOwner(), p.GetPendingTasks(), p.GetPollers(), p.GetTaskQueues(), p.GetTasksAdded())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if desc.GetPlaced() {
+		fmt.Printf("\n%s/%s is on partition %d", desc.GetNamespace(), desc.GetTaskQueue(), desc.GetPartition())
+	} else {
+		fmt.Printf("\n%s/%s does not exist yet; it would be placed on partition %d", desc.GetNamespace(), desc.GetTaskQueue(), desc.GetPartition())
+	}
+	if desc.GetRingPartition() != desc.GetPartition() {
+		fmt.Printf(" (the hash ring now selects %d)", desc.GetRingPartition())
+	}
+	fmt.Println()
+	for _, binding := range desc.GetStickyQueues() {
+		fmt.Printf("%s is bound to worker %s on sticky queue %s, partition %d\n", desc.GetWorkflowId(), binding.GetWorker(), binding.GetTaskQueue(), binding.GetPartition())
+	}
+	return nil
+}
+
+func (a *admin) shardsDescribe(ctx context.Context) error {
+	client, closeConn, err := a.historyClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.DescribeShards(ctx, &historyv1.DescribeShardsRequest{})
+	if err != nil {
+		return err
+	}
+	if a.jsonOutput {
+		return printJSON(resp)
+	}
+
+	owned := 0
+	w := newTable()
+	fmt.Fprintln(w, "SHARD\tOWNED\tRUNNING EXECUTIONS")
+	for _, shard := range resp.GetShards() {
+		if shard.GetOwned() {
+			owned++
+		}
+		fmt.Fprintf(w, "%d\t%v\t%d\n", shard.GetShardId(), shard.GetOwned(), shard.GetRunningExecutions())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d of %d shard(s) owned\n", owned, resp.GetNumShards())
+	return nil
+}
+
+func (a *admin) shardsRebalance(ctx context.Context) error {
+	client, closeConn, err := a.historyClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.RebalanceShards(ctx, &historyv1.RebalanceShardsRequest{})
+	if err != nil {
+		return err
+	}
+	if a.jsonOutput {
+		return printJSON(resp)
+	}
+
+	if len(resp.GetAcquiredShardIds()) == 0 {
+		fmt.Println("All shards already owned; nothing to rebalance")
+		return nil
+	}
+	ids := make([]string, 0, len(resp.GetAcquiredShardIds()))
+	for _, id := range resp.GetAcquiredShardIds() {
+		ids = append(ids, fmt.Sprint(id))
+	}
+	fmt.Printf("Acquired %d shard(s): %s\n", len(ids), strings.Join(ids, ", "))
+	return nil
+}
+
+func (a *admin) forceTerminate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("execution force-terminate", flag.ExitOnError)
+	namespace := fs.String("namespace", "default", "Namespace")
+	workflowID := fs.String("workflow-id", "", "Workflow ID")
+	runID := fs.String("run-id", "", "Run ID (default: current run)")
+	reason := fs.String("reason", "", "Reason recorded in history")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *workflowID == "" || *reason == "" {
+		return fmt.Errorf("--workflow-id and --reason are required")
+	}
+
+	client, closeConn, err := a.historyClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	resp, err := client.ForceTerminateExecution(ctx, &historyv1.ForceTerminateExecutionRequest{
+		Namespace: *namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: *workflowID,
+			RunId:      *runID,
+		},
+		Reason: *reason,
+	})
+	if err != nil {
+		return err
+	}
+	if a.jsonOutput {
+		return printJSON(resp)
+	}
+	fmt.Printf("Terminated %s/%s\n", *namespace, *workflowID)
+	return nil
+}
+
+func (a *admin) describeMutableState(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("execution mutable-state", flag.ExitOnError)
+	namespace := fs.String("namespace", "default", "Namespace")
+	workflowID := fs.String("workflow-id", "", "Workflow ID")
+	runID := fs.String("run-id", "", "Run ID")
+	clusters := fs.String("clusters", a.historyHTTPAddr, "Comma-separated history HTTP addresses")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *workflowID == "" || *runID == "" {
+		return fmt.Errorf("--workflow-id and --run-id are required")
+	}
+
+	addrs := strings.Split(*clusters, ",")
+	views := make([]*history.MutableStateView, 0, len(addrs))
+	for _, addr := range addrs {
+		view, err := fetchMutableState(ctx, strings.TrimSpace(addr), *namespace, *workflowID, *runID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", addr, err)
+		}
+		views = append(views, view)
+	}
+
+	if a.jsonOutput {
+		out := make(map[string]*history.MutableStateView, len(views))
+		for i, view := range views {
+			out[strings.TrimSpace(addrs[i])] = view
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	w := newTable()
+	fmt.Fprintln(w, "CLUSTER\tSTATUS\tNEXT EVENT\tDB VERSION\tBRANCH TOKEN\tVERSIONS\tPENDING")
+	for i, view := range views {
+		versions := make([]string, 0, len(view.VersionHistory.Items))
+		for _, item := range view.VersionHistory.Items {
+			versions = append(versions, fmt.Sprintf("%d@%d", item.Version, item.EventID))
+		}
+		pending := view.PendingTasks
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%d activities, %d nodes, %d timers, %d children\n",
+			strings.TrimSpace(addrs[i]), view.Status, view.NextEventID, view.DBVersion, view.BranchToken,
+			strings.Join(versions, " "),
+			len(pending.Activities), len(pending.Nodes), len(pending.Timers), len(pending.Children))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for i := 1; i < len(views); i++ {
+		lca, ok := ndc.FindLCAItem(views[0].VersionHistory, views[i].VersionHistory)
+		if !ok {
+			fmt.Printf("%s and %s share no history\n", strings.TrimSpace(addrs[0]), strings.TrimSpace(addrs[i]))
+			continue
+		}
+		fmt.Printf("%s and %s agree up to event %d (version %d)\n", strings.TrimSpace(addrs[0]), strings.TrimSpace(addrs[i]), lca.EventID, lca.Version)
+	}
+	return nil
+}
+
+// checkConsistency runs "execution verify", or "execution repair" when
+// command says so, and prints the drift found.
+func (a *admin) checkConsistency(ctx context.Context, command string, args []string) error {
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	namespace := fs.String("namespace", "default", "Namespace")
+	workflowID := fs.String("workflow-id", "", "Workflow ID")
+	runID := fs.String("run-id", "", "Run ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *workflowID == "" || *runID == "" {
+		return fmt.Errorf("--workflow-id and --run-id are required")
+	}
+
+	client, closeConn, err := a.historyClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	execution := &commonv1.WorkflowExecution{WorkflowId: *workflowID, RunId: *runID}
+	var result *historyv1.ExecutionConsistency
+	if command == "execution repair" {
+		resp, err := client.RepairExecution(ctx, &historyv1.RepairExecutionRequest{Namespace: *namespace, WorkflowExecution: execution})
+		if err != nil {
+			return err
+		}
+		result = resp.GetConsistency()
+	} else {
+		resp, err := client.VerifyExecution(ctx, &historyv1.VerifyExecutionRequest{Namespace: *namespace, WorkflowExecution: execution})
+		if err != nil {
+			return err
+		}
+		result = resp.GetConsistency()
+	}
+	if a.jsonOutput {
+		return printJSON(result)
+	}
+
+	if result.GetConsistent() {
+		fmt.Printf("%s/%s is consistent with its history up to event %d\n", *workflowID, *runID, result.GetReplayedNextEventId()-1)
+		return nil
+	}
+	w := newTable()
+	fmt.Fprintln(w, "FIELD\tSTORED\tREPLAYED")
+	for _, drift := range result.GetDrift() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", drift.GetField(), drift.GetStored(), drift.GetReplayed())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if result.GetRepaired() {
+		fmt.Printf("\nRebuilt the state of %s/%s from history\n", *workflowID, *runID)
+	} else {
+		fmt.Printf("\n%s/%s has drifted from its history; run execution repair to rebuild it\n", *workflowID, *runID)
+	}
+	return nil
+}
+
+func fetchMutableState(ctx context.Context, addr, namespace, workflowID, runID string) (*history.MutableStateView, error) {
+	endpoint := fmt.Sprintf("%s/admin/v1/namespaces/%s/executions/%s/%s/mutable-state",
+		strings.TrimSuffix(addr, "/"), url.PathEscape(namespace), url.PathEscape(workflowID), url.PathEscape(runID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var view history.MutableStateView
+	if err := json.NewDecoder(resp.Body).Decode(&view); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (a *admin) rolesList(ctx context.Context) error {
+	client, closeConn, err := a.accessClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	assignments, err := client.ListRoleAssignments(ctx)
+	if err != nil {
+		return err
+	}
+	if a.jsonOutput {
+		data, err := json.MarshalIndent(assignments, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	w := newTable()
+	fmt.Fprintln(w, "IDENTITY\tROLE\tASSIGNED BY\tASSIGNED AT")
+	for _, assignment := range assignments {
+		assignedAt := "-"
+		if !assignment.AssignedAt.IsZero() {
+			assignedAt = assignment.AssignedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", assignment.Identity, assignment.Role, assignment.AssignedBy, assignedAt)
+	}
+	return w.Flush()
+}
+
+func (a *admin) rolesAssign(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: roles assign <identity> <admin|operator|viewer>")
+	}
+	role, ok := controlplane.ParseRole(args[1])
+	if !ok {
+		return fmt.Errorf("unknown role %q; expected admin, operator or viewer", args[1])
+	}
+
+	client, closeConn, err := a.accessClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	assignment, err := client.AssignRole(ctx, args[0], role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s now has the %s role\n", assignment.Identity, assignment.Role)
+	return nil
+}
+
+func (a *admin) rolesRevoke(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: roles revoke <identity>")
+	}
+
+	client, closeConn, err := a.accessClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	if err := client.RevokeRole(ctx, args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("Revoked the role of %s\n", args[0])
+	return nil
+}
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+)
+
+// fakeMatching serves the dead letter queue RPCs.
+type fakeMatching struct {
+	matchingv1.UnimplementedMatchingServiceServer
+	replayed *matchingv1.ReplayDLQTasksRequest
+	purged   bool
+}
+
+func (m *fakeMatching) ListDLQTasks(context.Context, *matchingv1.ListDLQTasksRequest) (*matchingv1.ListDLQTasksResponse, error) {
+	return &matchingv1.ListDLQTasksResponse{Tasks: []*matchingv1.DLQTask{{TaskId: "default:wf-1:run-1:2:5", Attempts: 3, Reason: "boom"}}}, nil
+}
+
+func (m *fakeMatching) ReplayDLQTasks(_ context.Context, req *matchingv1.ReplayDLQTasksRequest) (*matchingv1.ReplayDLQTasksResponse, error) {
+	m.replayed = req
+	resp := &matchingv1.ReplayDLQTasksResponse{Failures: map[string]string{}}
+	for _, id := range req.GetTaskIds() {
+		if id == "missing" {
+			resp.Failures[id] = "task not found"
+			continue
+		}
+		resp.ReplayedTaskIds = append(resp.ReplayedTaskIds, id)
+	}
+	return resp, nil
+}
+
+func (m *fakeMatching) PurgeDLQ(context.Context, *matchingv1.PurgeDLQRequest) (*matchingv1.PurgeDLQResponse, error) {
+	m.purged = true
+	return &matchingv1.PurgeDLQResponse{Purged: 1}, nil
+}
+
+// fakeHistory serves the shard and execution RPCs.
+type fakeHistory struct {
+	historyv1.UnimplementedHistoryServiceServer
+	terminated *historyv1.ForceTerminateExecutionRequest
+	rebalanced bool
+}
+
+func (h *fakeHistory) DescribeShards(context.Context, *historyv1.DescribeShardsRequest) (*historyv1.DescribeShardsResponse, error) {
+	return &historyv1.DescribeShardsResponse{NumShards: 2, Shards: []*historyv1.ShardInfo{{ShardId: 0, Owned: true}, {ShardId: 1}}}, nil
+}
+
+func (h *fakeHistory) RebalanceShards(context.Context, *historyv1.RebalanceShardsRequest) (*historyv1.RebalanceShardsResponse, error) {
+	h.rebalanced = true
+	return &historyv1.RebalanceShardsResponse{AcquiredShardIds: []int32{1}}, nil
+}
+
+func (h *fakeHistory) ForceTerminateExecution(_ context.Context, req *historyv1.ForceTerminateExecutionRequest) (*historyv1.ForceTerminateExecutionResponse, error) {
+	h.terminated = req
+	return &historyv1.ForceTerminateExecutionResponse{}, nil
+}
+
+// serve starts a gRPC server on a local port and returns its address.
+func serve(t *testing.T, register func(*grpc.Server)) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func TestAdminCommands(t *testing.T) {
+	matching := &fakeMatching{}
+	history := &fakeHistory{}
+	a := &admin{
+		matchingAddr: serve(t, func(s *grpc.Server) { matchingv1.RegisterMatchingServiceServer(s, matching) }),
+		historyAddr:  serve(t, func(s *grpc.Server) { historyv1.RegisterHistoryServiceServer(s, history) }),
+	}
+
+	tests := []struct {
+		name    string
+		run     func(ctx context.Context) error
+		wantErr string
+		check   func(t *testing.T)
+	}{
+		{name: "dlq list", run: a.dlqList},
+		{
+			name: "dlq replay",
+			run:  func(ctx context.Context) error { return a.dlqReplay(ctx, []string{"t1", "t2"}) },
+			check: func(t *testing.T) {
+				if ids := matching.replayed.GetTaskIds(); len(ids) != 2 || matching.replayed.GetAll() {
+					t.Fatalf("replay request = %v", matching.replayed)
+				}
+			},
+		},
+		{
+			name: "dlq replay all",
+			run:  func(ctx context.Context) error { return a.dlqReplay(ctx, []string{"--all"}) },
+			check: func(t *testing.T) {
+				if !matching.replayed.GetAll() {
+					t.Fatalf("replay request = %v, want all", matching.replayed)
+				}
+			},
+		},
+		{
+			name:    "dlq replay reports failures",
+			run:     func(ctx context.Context) error { return a.dlqReplay(ctx, []string{"t1", "missing"}) },
+			wantErr: "1 task(s) could not be replayed",
+		},
+		{name: "dlq replay without tasks", run: func(ctx context.Context) error { return a.dlqReplay(ctx, nil) }, wantErr: "--all"},
+		{name: "dlq purge without confirmation", run: func(ctx context.Context) error { return a.dlqPurge(ctx, nil) }, wantErr: "--yes"},
+		{
+			name: "dlq purge",
+			run:  func(ctx context.Context) error { return a.dlqPurge(ctx, []string{"--yes"}) },
+			check: func(t *testing.T) {
+				if !matching.purged {
+					t.Fatal("dead letter queue not purged")
+				}
+			},
+		},
+		{name: "partitions describe without task queue", run: func(ctx context.Context) error { return a.partitionsDescribe(ctx, nil) }, wantErr: "--task-queue"},
+		{name: "shards describe", run: a.shardsDescribe},
+		{
+			name: "shards rebalance",
+			run:  a.shardsRebalance,
+			check: func(t *testing.T) {
+				if !history.rebalanced {
+					t.Fatal("shards not rebalanced")
+				}
+			},
+		},
+		{
+			name: "force terminate",
+			run: func(ctx context.Context) error {
+				return a.forceTerminate(ctx, []string{"--workflow-id", "wf-1", "--reason", "stuck"})
+			},
+			check: func(t *testing.T) {
+				req := history.terminated
+				if req.GetNamespace() != "default" || req.GetWorkflowExecution().GetWorkflowId() != "wf-1" || req.GetWorkflowExecution().GetRunId() != "" || req.GetReason() != "stuck" {
+					t.Fatalf("terminate request = %v", req)
+				}
+			},
+		},
+		{
+			name:    "force terminate without reason",
+			run:     func(ctx context.Context) error { return a.forceTerminate(ctx, []string{"--workflow-id", "wf-1"}) },
+			wantErr: "--reason",
+		},
+		{
+			name:    "mutable state without run",
+			run:     func(ctx context.Context) error { return a.describeMutableState(ctx, []string{"--workflow-id", "wf-1"}) },
+			wantErr: "--run-id",
+		},
+		{
+			name: "verify without workflow",
+			run: func(ctx context.Context) error {
+				return a.checkConsistency(ctx, "execution verify", []string{"--run-id", "run-1"})
+			},
+			wantErr: "--workflow-id",
+		},
+		{name: "roles assign unknown role", run: func(ctx context.Context) error { return a.rolesAssign(ctx, []string{"alice", "root"}) }, wantErr: "unknown role"},
+		{name: "roles assign without role", run: func(ctx context.Context) error { return a.rolesAssign(ctx, []string{"alice"}) }, wantErr: "usage"},
+		{name: "roles revoke without identity", run: func(ctx context.Context) error { return a.rolesRevoke(ctx, nil) }, wantErr: "usage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err := tt.run(ctx)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+		})
+	}
+}
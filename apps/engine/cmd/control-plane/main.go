@@ -2,15 +2,25 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	controlplanev1 "github.com/linkflow/engine/api/gen/linkflow/controlplane/v1"
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
 	"github.com/linkflow/engine/internal/version"
 )
 
@@ -18,6 +28,14 @@ func main() {
 	var (
 		port     = flag.Int("port", 7240, "Control plane port")
 		httpPort = flag.Int("http-port", 8080, "HTTP server port")
+
+		clusterID         = flag.String("cluster-id", "default", "Cluster ID")
+		region            = flag.String("region", "", "Cluster region")
+		maxConfigVersions = flag.Int("max-config-versions", controlplane.DefaultMaxConfigVersions, "Versions kept per dynamic config key")
+		dbURL             = flag.String("db-url", os.Getenv("DATABASE_URL"), "Postgres URL for leader election across replicas (empty: single replica)")
+		lbPolicy          = flag.String("lb-policy", string(controlplane.LoadBalancingRoundRobin), "Instance selection for ResolveService: round_robin or least_connections")
+		dnsDomain         = flag.String("dns-domain", "", "Domain of the SRV records used when DNS discovery is enabled")
+		bootstrapAdmins   = flag.String("bootstrap-admins", os.Getenv("RBAC_BOOTSTRAP_ADMINS"), "Comma-separated identities that always hold the admin role")
 	)
 	flag.Parse()
 
@@ -25,6 +43,13 @@ func main() {
 
 	printBanner("Control Plane", logger)
 
+	switch controlplane.LoadBalancingPolicy(*lbPolicy) {
+	case controlplane.LoadBalancingRoundRobin, controlplane.LoadBalancingLeastConnections:
+	default:
+		logger.Error("unknown load-balancing policy", slog.String("lb_policy", *lbPolicy))
+		os.Exit(1)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -37,6 +62,92 @@ func main() {
 		cancel()
 	}()
 
+	var leaderElector controlplane.LeaderElector
+	var roleStore controlplane.RoleStore
+	if *dbURL != "" {
+		dbpool, err := pgxpool.New(ctx, *dbURL)
+		if err != nil {
+			logger.Error("failed to connect to database", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer dbpool.Close()
+		leaderElector = controlplane.NewPostgresLeaderElector(dbpool)
+		roleStore = controlplane.NewPostgresRoleStore(dbpool)
+	} else {
+		logger.Warn("no database configured; this replica runs background tasks without leader election and keeps role assignments in memory")
+	}
+
+	var admins []string
+	for _, identity := range strings.Split(*bootstrapAdmins, ",") {
+		if identity = strings.TrimSpace(identity); identity != "" {
+			admins = append(admins, identity)
+		}
+	}
+
+	svc := controlplane.NewService(controlplane.Config{
+		ClusterID:         *clusterID,
+		ClusterName:       *clusterID,
+		Region:            *region,
+		Endpoint:          fmt.Sprintf(":%d", *port),
+		Logger:            logger,
+		MaxConfigVersions: *maxConfigVersions,
+		LeaderElector:     leaderElector,
+
+		LoadBalancingPolicy: controlplane.LoadBalancingPolicy(*lbPolicy),
+		DNSDomain:           *dnsDomain,
+
+		RoleStore:       roleStore,
+		BootstrapAdmins: admins,
+	})
+	if err := svc.Start(ctx); err != nil {
+		logger.Error("failed to start control plane", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer func() {
+		if err := svc.Stop(context.Background()); err != nil {
+			logger.Error("failed to stop control plane", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Namespace settings served to the frontend and matching services
+	if raw := os.Getenv("NAMESPACE_CONFIGS"); raw != "" {
+		if err := loadNamespaceConfigs(ctx, svc, raw); err != nil {
+			logger.Error("failed to load NAMESPACE_CONFIGS", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	}
+
+	// Admin RPCs, role management included, are authorized against the
+	// caller's role when tokens can be validated
+	var serverOpts []grpc.ServerOption
+	if validator, err := interceptor.NewAuthInterceptor(interceptor.AuthConfig{}); err == nil {
+		rbac := controlplane.NewRBACInterceptor(svc, validator, controlplane.AdminMethodPermissions, logger)
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(rbac.UnaryInterceptor))
+	} else {
+		logger.Warn("JWT_SECRET not set; admin RPCs are not authorized", slog.String("error", err.Error()))
+	}
+
+	server := grpc.NewServer(serverOpts...)
+	controlplanev1.RegisterConfigServiceServer(server, controlplane.NewGRPCServer(svc))
+	controlplanev1.RegisterDiscoveryServiceServer(server, controlplane.NewDiscoveryGRPCServer(svc))
+	controlplanev1.RegisterAccessServiceServer(server, controlplane.NewAccessGRPCServer(svc))
+	controlplanev1.RegisterNamespaceServiceServer(server, controlplane.NewNamespaceGRPCServer(svc))
+	reflection.Register(server)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+	if err != nil {
+		logger.Error("failed to listen", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	go func() {
+		logger.Info("starting gRPC server", slog.Int("port", *port))
+		if err := server.Serve(lis); err != nil {
+			logger.Error("gRPC server failed", slog.String("error", err.Error()))
+			cancel()
+		}
+	}()
+
 	// Start HTTP Server for Health Checks
 	go func() {
 		mux := http.NewServeMux()
@@ -64,9 +175,43 @@ func main() {
 	logger.Info("control plane started", slog.Int("port", *port))
 
 	<-ctx.Done()
+	server.GracefulStop()
 	logger.Info("control plane stopped")
 }
 
+// loadNamespaceConfigs registers namespace settings from a JSON array such as
+// [{"name":"workspace-1","max_concurrent_executions":50,"task_queue_soft_limit":500,"task_queue_hard_limit":2000}].
+func loadNamespaceConfigs(ctx context.Context, svc *controlplane.Service, raw string) error {
+	var entries []struct {
+		Name                    string `json:"name"`
+		Description             string `json:"description"`
+		OwnerEmail              string `json:"owner_email"`
+		RetentionDays           int    `json:"retention_days"`
+		MaxConcurrentExecutions int    `json:"max_concurrent_executions"`
+		TaskQueueSoftLimit      int    `json:"task_queue_soft_limit"`
+		TaskQueueHardLimit      int    `json:"task_queue_hard_limit"`
+	}
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := svc.CreateNamespace(ctx, &controlplane.NamespaceConfig{
+			ID:                      entry.Name,
+			Name:                    entry.Name,
+			Description:             entry.Description,
+			OwnerEmail:              entry.OwnerEmail,
+			RetentionDays:           entry.RetentionDays,
+			MaxConcurrentExecutions: entry.MaxConcurrentExecutions,
+			TaskQueueSoftLimit:      entry.TaskQueueSoftLimit,
+			TaskQueueHardLimit:      entry.TaskQueueHardLimit,
+		}); err != nil {
+			return fmt.Errorf("namespace %s: %w", entry.Name, err)
+		}
+	}
+	return nil
+}
+
 func printBanner(service string, logger *slog.Logger) {
 	logger.Info(fmt.Sprintf("LinkFlow %s Service", service),
 		slog.String("version", version.Version),
@@ -13,16 +13,21 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/linkflow/engine/internal/approval"
+	"github.com/linkflow/engine/internal/controlplane"
 	"github.com/linkflow/engine/internal/frontend"
 	"github.com/linkflow/engine/internal/frontend/adapter"
 	"github.com/linkflow/engine/internal/frontend/handler"
 	"github.com/linkflow/engine/internal/frontend/interceptor"
+	"github.com/linkflow/engine/internal/frontend/progress"
+	"github.com/linkflow/engine/internal/frontend/searchquery"
 	"github.com/linkflow/engine/internal/version"
 )
 
@@ -76,6 +81,10 @@ func main() {
 	matchingClient := adapter.NewMatchingClient(matchingConn)
 
 	loggingInterceptor := interceptor.NewLoggingInterceptor(logger)
+	deadlineInterceptor := interceptor.NewDeadlineInterceptor(interceptor.DeadlineConfig{
+		DefaultTimeout: getEnvDuration("REQUEST_DEFAULT_TIMEOUT", 0),
+		MaxTimeout:     getEnvDuration("REQUEST_MAX_TIMEOUT", 0),
+	})
 	authInterceptor, err := interceptor.NewAuthInterceptor(interceptor.AuthConfig{
 		SkipMethods: []string{"/grpc.health.v1.Health/Check"},
 	})
@@ -84,8 +93,49 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Admin routes are authorized against control plane roles when a control
+	// plane is configured, and against the token's admin role otherwise
+	var authorizer controlplane.Authorizer
+	var namespaces *controlplane.NamespaceClient
+	if cpAddr := os.Getenv("CONTROL_PLANE_ADDR"); cpAddr != "" {
+		cpConn, err := grpc.NewClient(cpAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			logger.Error("failed to connect to control plane", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer cpConn.Close()
+		authorizer = controlplane.NewAccessClient(cpConn)
+		namespaces = controlplane.NewNamespaceClient(cpConn, controlplane.DefaultNamespaceCacheTTL)
+	}
+
 	svc := frontend.NewService(historyClient, matchingClient, logger, frontend.DefaultServiceConfig())
 
+	// Namespace concurrency quotas are read from the control plane
+	if namespaces != nil {
+		svc.WithConcurrencyLimits(namespaces, controlplane.NewExecutionCounter(rdb))
+	}
+
+	// Saved search queries and approvals are stored in Postgres when a
+	// database is configured
+	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
+		dbpool, err := pgxpool.New(context.Background(), dbURL)
+		if err != nil {
+			logger.Error("failed to connect to database", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer dbpool.Close()
+		svc.WithSearchQueries(searchquery.NewPostgresStore(dbpool))
+		svc.WithApprovals(approval.NewPostgresStore(dbpool))
+	}
+
+	// Timers held by the timer service are included when describing executions
+	if timerURL := os.Getenv("TIMER_URL"); timerURL != "" {
+		svc.WithTimerClient(adapter.NewTimerClient(timerURL, os.Getenv("TIMER_API_TOKEN")))
+	}
+
+	// Worker progress is relayed to UI clients through Redis
+	svc.WithProgressStore(progress.NewRedisStore(rdb))
+
 	// Start Redis Consumer
 	consumer := frontend.NewRedisConsumerWithConfig(rdb, svc, logger, frontend.ConsumerConfig{
 		Retry:          frontend.DefaultConsumerConfig().Retry,
@@ -104,10 +154,12 @@ func main() {
 	server := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			loggingInterceptor.UnaryInterceptor,
+			deadlineInterceptor.UnaryInterceptor,
 			authInterceptor.UnaryInterceptor,
 		),
 		grpc.ChainStreamInterceptor(
 			loggingInterceptor.StreamInterceptor,
+			deadlineInterceptor.StreamInterceptor,
 			authInterceptor.StreamInterceptor,
 		),
 	)
@@ -146,7 +198,14 @@ func main() {
 		mux := http.NewServeMux()
 
 		// Register Engine API routes
-		frontendHandler := handler.NewHTTPHandler(svc, logger)
+		frontendHandler := handler.NewHTTPHandler(svc, logger).
+			WithTokenValidator(authInterceptor).
+			WithAuthorizer(authorizer).
+			WithCallbackSecret(os.Getenv("CALLBACK_SECRET")).
+			WithDependencyCheck("history", handler.GRPCHealthCheck(historyConn)).
+			WithDependencyCheck("matching", handler.GRPCHealthCheck(matchingConn)).
+			WithDependencyCheck("redis", handler.RedisPingCheck(rdb)).
+			WithProbeTimeout(getEnvDuration("READINESS_PROBE_TIMEOUT", handler.DefaultProbeTimeout))
 		frontendHandler.RegisterRoutes(mux)
 
 		httpServer := &http.Server{
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -9,21 +10,31 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
 	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
 	"github.com/linkflow/engine/internal/history"
+	"github.com/linkflow/engine/internal/history/audit"
+	"github.com/linkflow/engine/internal/history/events"
 	"github.com/linkflow/engine/internal/history/shard"
 	"github.com/linkflow/engine/internal/history/store"
 	"github.com/linkflow/engine/internal/history/visibility"
+	"github.com/linkflow/engine/internal/observability/metrics"
 	"github.com/linkflow/engine/internal/version"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
@@ -40,6 +51,8 @@ func run() error {
 		shardCount   = flag.Int("shard-count", 16, "Number of shards")
 		dbUrl        = flag.String("db-url", getEnv("DATABASE_URL", "postgres://linkflow-postgres:5432/linkflow"), "Database URL")
 		matchingAddr = flag.String("matching-addr", getEnv("MATCHING_ADDR", "localhost:7235"), "Matching service address")
+
+		maxConflictRetries = flag.Int("max-state-conflict-retries", getEnvInt("HISTORY_MAX_STATE_CONFLICT_RETRIES", history.DefaultMaxStateConflictRetries), "Times events are re-applied after a mutable state version conflict")
 	)
 	flag.Parse()
 
@@ -77,17 +90,115 @@ func run() error {
 	stateStore := store.NewPostgresMutableStateStore(dbpool, int32(*shardCount))
 	visibilityStore := visibility.NewPostgresStore(dbpool)
 
-	svc := history.NewService(
-		shardController,
-		eventStore,
-		stateStore,
-		visibilityStore,
-		matchingClient,
-		logger,
-	)
+	// Namespace concurrency counters are shared with the frontend through Redis
+	var executionCounter *controlplane.ExecutionCounter
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		redisOpt, err := redis.ParseURL(redisURL)
+		if err != nil {
+			return fmt.Errorf("failed to parse REDIS_URL: %w", err)
+		}
+		rdb := redis.NewClient(redisOpt)
+		defer rdb.Close()
+		executionCounter = controlplane.NewExecutionCounter(rdb)
+	}
+
+	reconcileInterval, err := time.ParseDuration(getEnv("CONCURRENCY_RECONCILE_INTERVAL", "1m"))
+	if err != nil {
+		return fmt.Errorf("invalid CONCURRENCY_RECONCILE_INTERVAL: %w", err)
+	}
+
+	payloadEncoding, err := events.ParsePayloadEncoding(getEnv("HISTORY_PAYLOAD_ENCODING", "json"))
+	if err != nil {
+		return fmt.Errorf("invalid HISTORY_PAYLOAD_ENCODING: %w", err)
+	}
+
+	// Event compaction is opt-in per namespace, e.g.
+	// HISTORY_EVENT_RETENTION=default=720h,billing=2160h
+	eventRetention, err := parseEventRetention(getEnv("HISTORY_EVENT_RETENTION", ""))
+	if err != nil {
+		return fmt.Errorf("invalid HISTORY_EVENT_RETENTION: %w", err)
+	}
+	compactionInterval, err := time.ParseDuration(getEnv("HISTORY_EVENT_COMPACTION_INTERVAL", "1h"))
+	if err != nil {
+		return fmt.Errorf("invalid HISTORY_EVENT_COMPACTION_INTERVAL: %w", err)
+	}
+
+	// Event hash chains are opt-in per namespace, e.g.
+	// HISTORY_HASH_CHAIN_NAMESPACES=billing,payroll
+	var hashChainNamespaces []string
+	for _, ns := range strings.Split(getEnv("HISTORY_HASH_CHAIN_NAMESPACES", ""), ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			hashChainNamespaces = append(hashChainNamespaces, ns)
+		}
+	}
+
+	// Lifecycle audit trail (AUDIT_SINK=postgres writes to workflow_audit_log)
+	var auditSink audit.Sink
+	switch sinkName := getEnv("AUDIT_SINK", "none"); sinkName {
+	case "none":
+	case "postgres":
+		auditSink = audit.NewPostgresSink(dbpool)
+		logger.Info("audit log enabled", slog.String("sink", sinkName))
+	default:
+		return fmt.Errorf("invalid AUDIT_SINK: %q", sinkName)
+	}
+
+	// Per-execution signal rate limits, in the same shape as the control-plane
+	// "history_signal_rate_limits" config key
+	var signalRateLimits *controlplane.SignalRateLimitConfig
+	if raw := getEnv("HISTORY_SIGNAL_RATE_LIMITS", ""); raw != "" {
+		signalRateLimits = &controlplane.SignalRateLimitConfig{}
+		if err := json.Unmarshal([]byte(raw), signalRateLimits); err != nil {
+			return fmt.Errorf("invalid HISTORY_SIGNAL_RATE_LIMITS: %w", err)
+		}
+	}
+
+	svc := history.NewServiceWithConfig(history.Config{
+		ShardController:              shardController,
+		EventStore:                   eventStore,
+		StateStore:                   stateStore,
+		VisibilityStore:              visibilityStore,
+		MatchingClient:               matchingClient,
+		Logger:                       logger,
+		ExecutionCounter:             executionCounter,
+		ConcurrencyReconcileInterval: reconcileInterval,
+		DefaultEncoding:              payloadEncoding,
+		AuditSink:                    auditSink,
+		Metrics:                      history.NewPrometheusMetrics(metrics.DefaultRegistry),
+		SignalRateLimits:             signalRateLimits,
+		HashChainNamespaces:          hashChainNamespaces,
+		MaxStateConflictRetries:      *maxConflictRetries,
+		EventCompaction: history.EventCompactionConfig{
+			Retention: eventRetention,
+			Interval:  compactionInterval,
+		},
+	})
 
-	server := grpc.NewServer()
-	historyv1.RegisterHistoryServiceServer(server, history.NewGRPCServer(svc))
+	// Admin RPCs are authorized against control plane roles when a control
+	// plane is configured
+	var serverOpts []grpc.ServerOption
+	if cpAddr := os.Getenv("CONTROL_PLANE_ADDR"); cpAddr != "" {
+		validator, err := interceptor.NewAuthInterceptor(interceptor.AuthConfig{})
+		if err != nil {
+			return fmt.Errorf("failed to create token validator: %w", err)
+		}
+		cpConn, err := grpc.NewClient(cpAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return fmt.Errorf("failed to connect to control plane: %w", err)
+		}
+		defer cpConn.Close()
+		rbac := controlplane.NewRBACInterceptor(controlplane.NewAccessClient(cpConn), validator, controlplane.AdminMethodPermissions, logger)
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(rbac.UnaryInterceptor))
+	} else {
+		logger.Warn("CONTROL_PLANE_ADDR not set; admin RPCs are not authorized")
+	}
+
+	server := grpc.NewServer(serverOpts...)
+	grpcServer := history.NewGRPCServer(svc)
+	historyv1.RegisterHistoryServiceServer(server, grpcServer)
+	// The frontend's readiness check probes this service
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
 	reflection.Register(server)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
@@ -112,6 +223,7 @@ func run() error {
 		if err := svc.Stop(ctx); err != nil {
 			logger.Error("failed to stop service", slog.String("error", err.Error()))
 		}
+		healthServer.Shutdown()
 		server.GracefulStop()
 	}()
 
@@ -130,6 +242,8 @@ func run() error {
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte("OK"))
 		})
+		mux.Handle("/metrics", metrics.DefaultRegistry.Handler())
+		mux.Handle("/admin/", svc.AdminHandler())
 
 		httpServer := &http.Server{
 			Addr:              fmt.Sprintf(":%d", *httpPort),
@@ -160,9 +274,48 @@ func printBanner(service string, logger *slog.Logger) {
 	)
 }
 
+// parseEventRetention parses a comma-separated list of namespace=duration
+// pairs.
+func parseEventRetention(value string) (map[string]time.Duration, error) {
+	retention := make(map[string]time.Duration)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		namespace, age, ok := strings.Cut(entry, "=")
+		if !ok || namespace == "" {
+			return nil, fmt.Errorf("expected namespace=duration, got %q", entry)
+		}
+		d, err := time.ParseDuration(age)
+		if err != nil {
+			return nil, fmt.Errorf("namespace %s: %w", namespace, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("namespace %s: retention must be positive", namespace)
+		}
+		retention[namespace] = d
+	}
+	return retention, nil
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+
+	return parsed
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -14,10 +15,17 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
 	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
 	"github.com/linkflow/engine/internal/matching"
+	"github.com/linkflow/engine/internal/observability/metrics"
 	"github.com/linkflow/engine/internal/version"
 	"github.com/redis/go-redis/v9"
 )
@@ -28,6 +36,14 @@ func main() {
 		httpPort       = flag.Int("http-port", 8080, "HTTP server port")
 		partitionCount = flag.Int("partition-count", 4, "Number of partitions")
 		redisAddr      = flag.String("redis-addr", getEnv("REDIS_ADDR", "localhost:6379"), "Redis address")
+		softLimit      = flag.Int("backpressure-soft-limit", 0, "Queue depth at which producers are warned (0 = default)")
+		hardLimit      = flag.Int("backpressure-hard-limit", 0, "Queue depth at which new tasks are rejected (0 = default)")
+		poisonLimit    = flag.Int("poison-pill-threshold", 0, "Unacked deliveries within the window before a task is sent to the DLQ (0 = default)")
+		poisonWindow   = flag.Duration("poison-pill-window", 0, "Window over which unacked deliveries are counted (0 = default)")
+		longPoll       = flag.Duration("long-poll-timeout", 0, "How long a poll waits for a task before returning empty (0 = default)")
+		startTimeout   = flag.Duration("schedule-to-start-timeout", 0, "How long an activity task waits for a worker before it is failed (0 = no limit)")
+		stickyGrace    = flag.Duration("sticky-grace-period", 0, "How long a sticky task waits for its bound worker before it falls back to the normal queue (0 = default)")
+		historyAddr    = flag.String("history-addr", getEnv("HISTORY_ADDR", "localhost:7234"), "History service address, for reporting task timeouts")
 	)
 	flag.Parse()
 
@@ -44,11 +60,67 @@ func main() {
 		Addr: *redisAddr,
 	})
 
+	// Per-namespace task queue limits and admin RPC authorization come from
+	// the control plane when one is configured
+	var cpConn *grpc.ClientConn
+	var namespaces matching.NamespaceConfigProvider
+	if cpAddr := os.Getenv("CONTROL_PLANE_ADDR"); cpAddr != "" {
+		conn, err := grpc.NewClient(cpAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			logger.Error("failed to connect to control plane", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer conn.Close()
+		cpConn = conn
+		namespaces = controlplane.NewNamespaceClient(cpConn, controlplane.DefaultNamespaceCacheTTL)
+	}
+
+	// Partition weights, in the shape of the control-plane
+	// "matching_partition_weights" config key: {"weights":{"0":4,"1":1}}
+	var weights controlplane.PartitionWeightConfig
+	if raw := os.Getenv("PARTITION_WEIGHTS"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &weights); err != nil {
+			logger.Error("invalid PARTITION_WEIGHTS", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	}
+
+	// Tasks dropped past their schedule-to-start timeout fail their activity
+	// in history.
+	var taskTimeouts matching.TaskTimeoutReporter
+	if *startTimeout > 0 {
+		historyConn, err := grpc.NewClient(*historyAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			logger.Error("failed to create history client", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer historyConn.Close()
+		taskTimeouts = matching.NewHistoryTimeoutReporter(historyv1.NewHistoryServiceClient(historyConn))
+	}
+
 	svc := matching.NewService(matching.Config{
 		NumPartitions: int32(*partitionCount),
 		Replicas:      100,
 		Logger:        logger,
 		RedisClient:   redisClient,
+
+		BackpressureSoftLimit: *softLimit,
+		BackpressureHardLimit: *hardLimit,
+
+		PoisonPillThreshold: int32(*poisonLimit),
+		PoisonPillWindow:    *poisonWindow,
+
+		LongPollTimeout: *longPoll,
+
+		ScheduleToStartTimeout: *startTimeout,
+		TaskTimeouts:           taskTimeouts,
+
+		StickyGracePeriod: *stickyGrace,
+
+		Namespaces: namespaces,
+
+		PartitionWeights: weights.Weights,
+		Metrics:          metrics.DefaultRegistry,
 	})
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -64,8 +136,27 @@ func main() {
 		}
 	}()
 
-	server := grpc.NewServer()
-	matchingv1.RegisterMatchingServiceServer(server, matching.NewGRPCServer(svc))
+	// Admin RPCs are authorized against control plane roles when a control
+	// plane is configured
+	var serverOpts []grpc.ServerOption
+	if cpConn != nil {
+		validator, err := interceptor.NewAuthInterceptor(interceptor.AuthConfig{})
+		if err != nil {
+			logger.Error("failed to create token validator", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		rbac := controlplane.NewRBACInterceptor(controlplane.NewAccessClient(cpConn), validator, controlplane.AdminMethodPermissions, logger)
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(rbac.UnaryInterceptor))
+	} else {
+		logger.Warn("CONTROL_PLANE_ADDR not set; admin RPCs are not authorized")
+	}
+
+	server := grpc.NewServer(serverOpts...)
+	grpcServer := matching.NewGRPCServer(svc)
+	matchingv1.RegisterMatchingServiceServer(server, grpcServer)
+	// The frontend's readiness check probes this service
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
 	reflection.Register(server)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
@@ -84,6 +175,8 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
 	})
+	mux.Handle("/metrics", metrics.DefaultRegistry.Handler())
+	mux.HandleFunc("/partitions", partitionsHandler(svc))
 
 	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%d", *httpPort),
@@ -127,7 +220,8 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
-	// Stop accepting new connections
+	// Report NOT_SERVING to health probes and stop accepting new connections
+	healthServer.Shutdown()
 	server.GracefulStop()
 	logger.Info("gRPC server stopped")
 
@@ -150,6 +244,44 @@ func printBanner(service string, logger *slog.Logger) {
 	)
 }
 
+// partitionsHandler reports each partition's weight and task counts on GET
+// and replaces the partition weights on PUT with a body in the shape of
+// PARTITION_WEIGHTS.
+func partitionsHandler(svc *matching.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var cfg controlplane.PartitionWeightConfig
+			if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := svc.SetPartitionWeights(cfg.Weights); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		type partitionInfo struct {
+			ID         int32 `json:"id"`
+			Weight     int   `json:"weight"`
+			TaskQueues int   `json:"task_queues"`
+			TasksAdded int64 `json:"tasks_added"`
+		}
+		partitions := make([]partitionInfo, 0)
+		for _, p := range svc.Partitions() {
+			partitions = append(partitions, partitionInfo(p))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"partitions": partitions})
+	}
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -93,6 +93,7 @@ func main() {
 	mux.HandleFunc("GET /api/v1/executions/{namespaceId}/{workflowId}/{runId}", handler.getExecution)
 	mux.HandleFunc("GET /api/v1/executions", handler.listExecutions)
 	mux.HandleFunc("GET /api/v1/executions/count", handler.countExecutions)
+	mux.HandleFunc("GET /api/v1/executions/export", handler.exportExecutions)
 
 	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%d", *httpPort),
@@ -308,6 +309,61 @@ func (h *visibilityHandler) countExecutions(w http.ResponseWriter, r *http.Reque
 	writeJSON(w, http.StatusOK, map[string]int64{"count": resp.Count})
 }
 
+func (h *visibilityHandler) exportExecutions(w http.ResponseWriter, r *http.Request) {
+	namespaceID := r.URL.Query().Get("namespace_id")
+	if namespaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "namespace_id is required"})
+		return
+	}
+
+	query := visibility.ExportQuery{
+		Query: r.URL.Query().Get("query"),
+	}
+	for param, dst := range map[string]*time.Time{"from": &query.StartTimeFrom, "to": &query.StartTimeTo} {
+		raw := r.URL.Query().Get(param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("%s must be an RFC3339 timestamp", param)})
+			return
+		}
+		*dst = parsed
+	}
+	if !query.StartTimeFrom.IsZero() && !query.StartTimeTo.IsZero() && !query.StartTimeFrom.Before(query.StartTimeTo) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "from must be before to"})
+		return
+	}
+	if _, err := visibility.ParseQuery(query.Query); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	// Exports can outlive the server's write timeout; the request context
+	// still bounds the stream if the client goes away.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	written, err := h.svc.ExportExecutions(r.Context(), namespaceID, query, w)
+	if err != nil {
+		// Headers are already sent; the truncated stream is the only signal.
+		h.logger.Error("execution export aborted",
+			slog.String("namespace_id", namespaceID),
+			slog.Int64("written", written),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	h.logger.Info("execution export completed",
+		slog.String("namespace_id", namespaceID),
+		slog.Int64("written", written),
+	)
+}
+
 type executionResponse struct {
 	NamespaceID      string                 `json:"namespace_id"`
 	WorkflowID       string                 `json:"workflow_id"`
@@ -3,20 +3,29 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/linkflow/engine/internal/approval"
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/observability/tracing"
+	"github.com/linkflow/engine/internal/sandbox"
 	"github.com/linkflow/engine/internal/version"
 	"github.com/linkflow/engine/internal/worker"
 	"github.com/linkflow/engine/internal/worker/adapter"
@@ -72,21 +81,105 @@ func run() error {
 	defer historyConn.Close()
 	historyClient := adapter.NewHistoryClient(historyConn)
 
+	var asyncActivityTimeout time.Duration
+	if raw := getEnv("ASYNC_ACTIVITY_TIMEOUT", ""); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid ASYNC_ACTIVITY_TIMEOUT: %w", err)
+		}
+		asyncActivityTimeout = parsed
+	}
+
+	completionBatching := worker.CompletionBatchConfig{
+		Enabled: getEnv("ACTIVITY_COMPLETION_BATCHING", "false") == "true",
+	}
+	if raw := getEnv("ACTIVITY_COMPLETION_BATCH_WINDOW", ""); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid ACTIVITY_COMPLETION_BATCH_WINDOW: %w", err)
+		}
+		completionBatching.Window = parsed
+	}
+	if raw := getEnv("ACTIVITY_COMPLETION_BATCH_SIZE", ""); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid ACTIVITY_COMPLETION_BATCH_SIZE: %w", err)
+		}
+		completionBatching.MaxBatch = parsed
+	}
+
+	// Secret references in node configs ({"$secret": name}) resolve from
+	// LINKFLOW_SECRET_* variables unless Vault is configured.
+	var secretStore executor.SecretStore
+	switch backend := getEnv("SECRET_STORE", "env"); backend {
+	case "env":
+		envStore := executor.NewEnvSecretStore()
+		if prefix := getEnv("SECRET_ENV_PREFIX", ""); prefix != "" {
+			envStore.Prefix = prefix
+		}
+		secretStore = envStore
+	case "vault":
+		if getEnv("VAULT_ADDR", "") == "" {
+			return fmt.Errorf("VAULT_ADDR is required when SECRET_STORE=vault")
+		}
+		secretStore = executor.NewVaultSecretStore(executor.VaultSecretStoreConfig{
+			Addr:  getEnv("VAULT_ADDR", ""),
+			Token: getEnv("VAULT_TOKEN", ""),
+			Mount: getEnv("VAULT_KV_MOUNT", "secret"),
+		})
+	default:
+		return fmt.Errorf("invalid SECRET_STORE %q: must be env or vault", backend)
+	}
+
+	// Node spans are only recorded when an exporter is chosen.
+	var tracer *tracing.Tracer
+	switch exporter := getEnv("NODE_TRACE_EXPORTER", ""); exporter {
+	case "":
+	case "log":
+		tracer = tracing.NewTracer(tracing.TracerConfig{
+			Name: "linkflow-worker",
+			Exporter: tracing.NewLogExporter(func(format string, args ...interface{}) {
+				logger.Info(fmt.Sprintf(format, args...))
+			}),
+		})
+	default:
+		return fmt.Errorf("invalid NODE_TRACE_EXPORTER %q: must be log or empty", exporter)
+	}
+
 	svc, err := worker.NewService(worker.Config{
-		TaskQueues:      strings.Split(*taskQueue, ","),
-		NumPollers:      *numWorkers,
-		Identity:        fmt.Sprintf("worker-%d", os.Getpid()),
-		MatchingAddr:    *matchingAddr,
-		PollInterval:    time.Second,
-		Logger:          logger,
-		CallbackKey:     getEnv("CALLBACK_SECRET", ""),
-		CallbackTimeout: 10 * time.Second,
-		HistoryClient:   historyClient,
+		TaskQueues:           strings.Split(*taskQueue, ","),
+		NumPollers:           *numWorkers,
+		Identity:             fmt.Sprintf("worker-%d", os.Getpid()),
+		MatchingAddr:         *matchingAddr,
+		PollInterval:         time.Second,
+		Logger:               logger,
+		CallbackKey:          getEnv("CALLBACK_SECRET", ""),
+		CallbackTimeout:      10 * time.Second,
+		HistoryClient:        historyClient,
+		AsyncActivityTimeout: asyncActivityTimeout,
+		CompletionBatching:   completionBatching,
+		SecretStore:          secretStore,
+		Tracer:               tracer,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create worker service: %w", err)
 	}
 
+	// Per-provider connector rate limits are read from the control plane's
+	// "connector_rate_limits" config key when a control plane is configured
+	executor.DefaultConnectorRateLimiter().ApplyConfig(connectorRateLimits(controlplane.DefaultConnectorRateLimitConfig()))
+	var connectorConfig executor.ConnectorRateLimitSource
+	if cpAddr := getEnv("CONTROL_PLANE_ADDR", ""); cpAddr != "" {
+		cpConn, err := grpc.NewClient(cpAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return fmt.Errorf("failed to connect to control plane: %w", err)
+		}
+		defer cpConn.Close()
+		connectorConfig = controlPlaneRateLimits{client: controlplane.NewConfigClient(cpConn)}
+	} else {
+		logger.Warn("CONTROL_PLANE_ADDR not set; using default connector rate limits")
+	}
+
 	// Create executor registry for node execution
 	nodeRegistry := executor.NewRegistry()
 
@@ -98,11 +191,31 @@ func run() error {
 	svc.RegisterExecutor(httpExecutor)
 	nodeRegistry.MustRegister(httpExecutor)
 
+	// Batch HTTP executor shares the HTTP executor's connection pool
+	batchHTTPExecutor := executor.NewBatchHTTPExecutor(httpExecutor)
+	svc.RegisterExecutor(batchHTTPExecutor)
+	nodeRegistry.MustRegister(batchHTTPExecutor)
+
 	// Register additional executors
 	transformExecutor := executor.NewTransformExecutor()
 	svc.RegisterExecutor(transformExecutor)
 	nodeRegistry.MustRegister(transformExecutor)
 
+	// CSV executor for transform_csv nodes
+	csvExecutor := executor.NewCSVExecutor()
+	svc.RegisterExecutor(csvExecutor)
+	nodeRegistry.MustRegister(csvExecutor)
+
+	// Encoding and hashing executor for crypto_util nodes
+	cryptoUtilExecutor := executor.NewCryptoUtilExecutor()
+	svc.RegisterExecutor(cryptoUtilExecutor)
+	nodeRegistry.MustRegister(cryptoUtilExecutor)
+
+	// PII masking executor for redact nodes
+	redactExecutor := executor.NewRedactExecutor()
+	svc.RegisterExecutor(redactExecutor)
+	nodeRegistry.MustRegister(redactExecutor)
+
 	loopExecutor := executor.NewLoopExecutor()
 	svc.RegisterExecutor(loopExecutor)
 	nodeRegistry.MustRegister(loopExecutor)
@@ -115,10 +228,33 @@ func run() error {
 	svc.RegisterExecutor(emailExecutor)
 	nodeRegistry.MustRegister(emailExecutor)
 
-	delayExecutor := executor.NewDelayExecutor()
+	var timerScheduler executor.TimerScheduler
+	if timerURL := getEnv("TIMER_URL", ""); timerURL != "" {
+		timerScheduler = executor.NewHTTPTimerScheduler(timerURL, getEnv("TIMER_API_TOKEN", ""))
+	}
+
+	delayExecutor := executor.NewDelayExecutor().WithLogger(logger)
+	if timerScheduler != nil {
+		delayExecutor.WithTimerScheduler(timerScheduler)
+	}
+	if raw := getEnv("DELAY_INLINE_THRESHOLD", ""); raw != "" {
+		threshold, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid DELAY_INLINE_THRESHOLD: %w", err)
+		}
+		delayExecutor.WithInlineThreshold(threshold)
+	}
 	svc.RegisterExecutor(delayExecutor)
 	nodeRegistry.MustRegister(delayExecutor)
 
+	// Poll-until executor hands long intervals between attempts to the timer service
+	pollUntilExecutor := executor.NewPollUntilExecutor(httpExecutor)
+	if timerScheduler != nil {
+		pollUntilExecutor.WithTimerScheduler(timerScheduler)
+	}
+	svc.RegisterExecutor(pollUntilExecutor)
+	nodeRegistry.MustRegister(pollUntilExecutor)
+
 	aiExecutor := executor.NewAIExecutor()
 	svc.RegisterExecutor(aiExecutor)
 	nodeRegistry.MustRegister(aiExecutor)
@@ -143,8 +279,44 @@ func run() error {
 	svc.RegisterExecutor(twilioExecutor)
 	nodeRegistry.MustRegister(twilioExecutor)
 
+	teamsExecutor := executor.NewTeamsExecutor()
+	svc.RegisterExecutor(teamsExecutor)
+	nodeRegistry.MustRegister(teamsExecutor)
+
+	sendGridExecutor := executor.NewSendGridExecutor()
+	svc.RegisterExecutor(sendGridExecutor)
+	nodeRegistry.MustRegister(sendGridExecutor)
+
+	mailgunExecutor := executor.NewMailgunExecutor()
+	svc.RegisterExecutor(mailgunExecutor)
+	nodeRegistry.MustRegister(mailgunExecutor)
+
+	// Incident executors for on-call automation
+	pagerDutyExecutor := executor.NewPagerDutyExecutor()
+	svc.RegisterExecutor(pagerDutyExecutor)
+	nodeRegistry.MustRegister(pagerDutyExecutor)
+
+	opsgenieExecutor := executor.NewOpsgenieExecutor()
+	svc.RegisterExecutor(opsgenieExecutor)
+	nodeRegistry.MustRegister(opsgenieExecutor)
+
+	// Google Sheets executor for google_sheets nodes
+	sheetsExecutor := executor.NewSheetsExecutor()
+	svc.RegisterExecutor(sheetsExecutor)
+	nodeRegistry.MustRegister(sheetsExecutor)
+
+	// XML executor for transform_xml nodes
+	xmlExecutor := executor.NewXMLExecutor()
+	svc.RegisterExecutor(xmlExecutor)
+	nodeRegistry.MustRegister(xmlExecutor)
+
 	// Script executor for action_script nodes
 	scriptExecutor := executor.NewScriptExecutor()
+	if sb, err := sandbox.NewSandbox(sandbox.Config{Logger: logger}); err != nil {
+		logger.Warn("script sandbox unavailable", slog.String("error", err.Error()))
+	} else {
+		scriptExecutor.WithSandbox(sb)
+	}
 	svc.RegisterExecutor(scriptExecutor)
 	nodeRegistry.MustRegister(scriptExecutor)
 
@@ -153,16 +325,52 @@ func run() error {
 	svc.RegisterExecutor(outputExecutor)
 	nodeRegistry.MustRegister(outputExecutor)
 
-	// Logic condition alias (handles logic_condition node type)
-	logicConditionExecutor := executor.NewLogicConditionExecutor()
-	svc.RegisterExecutor(logicConditionExecutor)
-	nodeRegistry.MustRegister(logicConditionExecutor)
-
 	// Database executor for action_database nodes
 	databaseExecutor := executor.NewDatabaseExecutor()
 	svc.RegisterExecutor(databaseExecutor)
 	nodeRegistry.MustRegister(databaseExecutor)
 
+	// MySQL executor for action_mysql nodes
+	mysqlExecutor := executor.NewMySQLExecutor()
+	defer mysqlExecutor.Close()
+	svc.RegisterExecutor(mysqlExecutor)
+	nodeRegistry.MustRegister(mysqlExecutor)
+
+	// AMQP executor for action_amqp nodes
+	amqpExecutor := executor.NewAMQPExecutor()
+	defer amqpExecutor.Close()
+	svc.RegisterExecutor(amqpExecutor)
+	nodeRegistry.MustRegister(amqpExecutor)
+
+	// Dedupe and throttle executors for dedupe and throttle nodes; all nodes
+	// share one pooled Redis client. Pool settings come from REDIS_URL (e.g.
+	// ?pool_size=20) or REDIS_POOL_SIZE.
+	dedupeExecutor := executor.NewDedupeExecutor()
+	throttleExecutor := executor.NewThrottleExecutor()
+	if redisURL := getEnv("REDIS_URL", ""); redisURL != "" {
+		redisOpt, err := redis.ParseURL(redisURL)
+		if err != nil {
+			return fmt.Errorf("failed to parse REDIS_URL: %w", err)
+		}
+		if raw := getEnv("REDIS_POOL_SIZE", ""); raw != "" {
+			poolSize, err := strconv.Atoi(raw)
+			if err != nil || poolSize <= 0 {
+				return fmt.Errorf("invalid REDIS_POOL_SIZE: %q", raw)
+			}
+			redisOpt.PoolSize = poolSize
+		}
+		rdb := redis.NewClient(redisOpt)
+		defer rdb.Close()
+		dedupeExecutor.WithRedis(rdb)
+		throttleExecutor.WithRedis(rdb)
+	} else {
+		logger.Warn("REDIS_URL is not set; dedupe and throttle nodes will fail")
+	}
+	svc.RegisterExecutor(dedupeExecutor)
+	nodeRegistry.MustRegister(dedupeExecutor)
+	svc.RegisterExecutor(throttleExecutor)
+	nodeRegistry.MustRegister(throttleExecutor)
+
 	// Storage executor for action_storage nodes
 	storageExecutor := executor.NewStorageExecutor()
 	svc.RegisterExecutor(storageExecutor)
@@ -173,11 +381,77 @@ func run() error {
 	svc.RegisterExecutor(codeExecutor)
 	nodeRegistry.MustRegister(codeExecutor)
 
-	// Approval executor for action_approval nodes
+	// Approval executor for approval nodes. Approval requests are stored in
+	// Postgres, where the frontend resolves them; without a database the
+	// node fails with APPROVAL_REQUIRED as before.
 	approvalExecutor := executor.NewApprovalExecutor()
+	if dbURL := getEnv("DATABASE_URL", ""); dbURL != "" {
+		dbpool, err := pgxpool.New(context.Background(), dbURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer dbpool.Close()
+		approvalExecutor.WithStore(approval.NewPostgresStore(dbpool))
+	}
 	svc.RegisterExecutor(approvalExecutor)
 	nodeRegistry.MustRegister(approvalExecutor)
 
+	// Async callback executor for wait_callback nodes
+	asyncCallbackExecutor := executor.NewAsyncCallbackExecutor()
+	svc.RegisterExecutor(asyncCallbackExecutor)
+	nodeRegistry.MustRegister(asyncCallbackExecutor)
+
+	// Wait signal executor for wait_signal nodes
+	waitSignalExecutor := executor.NewWaitSignalExecutor()
+	svc.RegisterExecutor(waitSignalExecutor)
+	nodeRegistry.MustRegister(waitSignalExecutor)
+
+	// Sub-workflow executor for sub_workflow nodes
+	subWorkflowExecutor := executor.NewSubWorkflowExecutor(historyClient)
+	svc.RegisterExecutor(subWorkflowExecutor)
+	nodeRegistry.MustRegister(subWorkflowExecutor)
+
+	// Set variable executor for set_variable nodes
+	setVariableExecutor := executor.NewSetVariableExecutor()
+	svc.RegisterExecutor(setVariableExecutor)
+	nodeRegistry.MustRegister(setVariableExecutor)
+
+	// Schema validation executor for validate_schema nodes
+	schemaValidateExecutor := executor.NewSchemaValidateExecutor()
+	svc.RegisterExecutor(schemaValidateExecutor)
+	nodeRegistry.MustRegister(schemaValidateExecutor)
+
+	// Render executor for render_document nodes; PDF output needs
+	// wkhtmltopdf or headless Chrome, from RENDER_PDF_BINARY or PATH.
+	renderExecutor := executor.NewRenderExecutor()
+	if pdfBinary, err := executor.FindPDFBinary(getEnv("RENDER_PDF_BINARY", "")); err != nil {
+		logger.Warn("PDF renderer unavailable", slog.String("error", err.Error()))
+	} else if pdfBinary == "" {
+		logger.Warn("no PDF renderer found; render_document nodes can only produce HTML")
+	} else {
+		renderExecutor.WithPDFBinary(pdfBinary)
+		logger.Info("PDF renderer found", slog.String("path", pdfBinary))
+	}
+	svc.RegisterExecutor(renderExecutor)
+	nodeRegistry.MustRegister(renderExecutor)
+
+	// Media executor for media_process nodes; needs ImageMagick and ffmpeg,
+	// from MEDIA_CONVERT_BINARY and MEDIA_FFMPEG_BINARY or PATH.
+	mediaExecutor := executor.NewMediaExecutor()
+	if imageBinary, videoBinary, err := executor.FindMediaBinaries(getEnv("MEDIA_CONVERT_BINARY", ""), getEnv("MEDIA_FFMPEG_BINARY", "")); err != nil {
+		logger.Warn("media binaries unavailable", slog.String("error", err.Error()))
+	} else {
+		if imageBinary == "" {
+			logger.Warn("ImageMagick not found; media_process image operations are disabled")
+		}
+		if videoBinary == "" {
+			logger.Warn("ffmpeg not found; media_process video operations are disabled")
+		}
+		mediaExecutor.WithBinaries(imageBinary, videoBinary)
+	}
+	svc.RegisterExecutor(mediaExecutor)
+	nodeRegistry.MustRegister(mediaExecutor)
+
 	// Set the registry on workflow executor so it can execute individual nodes
 	workflowExecutor.SetRegistry(nodeRegistry)
 
@@ -188,6 +462,10 @@ func run() error {
 		return fmt.Errorf("failed to start worker service: %w", err)
 	}
 
+	if connectorConfig != nil {
+		go executor.DefaultConnectorRateLimiter().Watch(ctx, connectorConfig, executor.DefaultConnectorRateLimitRefreshInterval, logger)
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
@@ -207,6 +485,17 @@ func run() error {
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte("OK"))
 		})
+		nodeTypes := func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"node_types": nodeRegistry.Describe(),
+			})
+		}
+		mux.HandleFunc("GET /api/v1/node-types", nodeTypes)
+		mux.HandleFunc("GET /node-types", nodeTypes)
+
+		// History's replay validation sends recorded workflow tasks here.
+		mux.Handle("POST /replay/decide", worker.ReplayDecisionHandler(workflowExecutor))
 
 		httpServer := &http.Server{
 			Addr:              fmt.Sprintf(":%d", *httpPort),
@@ -235,6 +524,50 @@ func run() error {
 	return nil
 }
 
+// controlPlaneRateLimits reads the connector rate limits from the control
+// plane's controlplane.ConnectorRateLimitsConfigKey.
+type controlPlaneRateLimits struct {
+	client *controlplane.ConfigClient
+}
+
+func (s controlPlaneRateLimits) ConnectorRateLimits(ctx context.Context) (*executor.ConnectorRateLimitConfig, error) {
+	raw, err := s.client.GetConfig(ctx, controlplane.ConnectorRateLimitsConfigKey)
+	if errors.Is(err, controlplane.ErrConfigKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg controlplane.ConnectorRateLimitConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid connector rate limits: %w", err)
+	}
+	return connectorRateLimits(&cfg), nil
+}
+
+// connectorRateLimits converts control plane connector limits to the
+// executor's.
+func connectorRateLimits(cfg *controlplane.ConnectorRateLimitConfig) *executor.ConnectorRateLimitConfig {
+	limits := &executor.ConnectorRateLimitConfig{
+		Providers: make(map[string]executor.ConnectorRateLimit, len(cfg.Providers)),
+		MaxWait:   cfg.MaxWait,
+	}
+	if cfg.Default != nil {
+		limits.Default = &executor.ConnectorRateLimit{
+			RequestsPerSecond: cfg.Default.RequestsPerSecond,
+			BurstSize:         cfg.Default.BurstSize,
+		}
+	}
+	for provider, limit := range cfg.Providers {
+		limits.Providers[provider] = executor.ConnectorRateLimit{
+			RequestsPerSecond: limit.RequestsPerSecond,
+			BurstSize:         limit.BurstSize,
+		}
+	}
+	return limits
+}
+
 func printBanner(service string, logger *slog.Logger) {
 	logger.Info(fmt.Sprintf("LinkFlow %s Service", service),
 		slog.String("version", version.Version),
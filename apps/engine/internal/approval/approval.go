@@ -0,0 +1,164 @@
+// Package approval stores the approval requests of approval nodes. The worker
+// creates a request when a node starts waiting and the frontend resolves the
+// node once an approver decides.
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("approval not found")
+	ErrNotPending = errors.New("approval is no longer pending")
+)
+
+// Status is the state of an approval request.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	// StatusExpired is an approval whose node stopped waiting before anyone
+	// decided, usually because it timed out.
+	StatusExpired Status = "expired"
+)
+
+// ParseStatus returns the status named s and whether it is known.
+func ParseStatus(s string) (Status, bool) {
+	switch status := Status(s); status {
+	case StatusPending, StatusApproved, StatusRejected, StatusExpired:
+		return status, true
+	}
+	return "", false
+}
+
+// Reject policies decide what a rejection does to the waiting node.
+const (
+	// OnRejectFail fails the node, so the workflow's error policy applies.
+	OnRejectFail = "fail"
+	// OnRejectContinue completes the node, which takes its "rejected" branch.
+	OnRejectContinue = "continue"
+)
+
+// Approval is one request for a decision on an approval node.
+type Approval struct {
+	ID         string
+	Namespace  string
+	WorkflowID string
+	RunID      string
+	NodeID     string
+	// TaskToken completes the node's pending activity.
+	TaskToken string
+
+	Title  string
+	Reason string
+	// Approvers may decide; anyone may when it is empty.
+	Approvers []string
+	Payload   json.RawMessage
+	OnReject  string
+
+	Status    Status
+	CreatedAt time.Time
+	// ExpiresAt is when the node stops waiting; zero if it waits for the
+	// worker's default async timeout.
+	ExpiresAt time.Time
+
+	DecidedBy string
+	Comment   string
+	DecidedAt time.Time
+}
+
+// Expired reports whether the approval was still pending when its node
+// stopped waiting.
+func (a *Approval) Expired(now time.Time) bool {
+	return a.Status == StatusExpired ||
+		(a.Status == StatusPending && !a.ExpiresAt.IsZero() && !now.Before(a.ExpiresAt))
+}
+
+// CanDecide reports whether approver is allowed to decide.
+func (a *Approval) CanDecide(approver string) bool {
+	if len(a.Approvers) == 0 {
+		return true
+	}
+	for _, allowed := range a.Approvers {
+		if allowed == approver {
+			return true
+		}
+	}
+	return false
+}
+
+// Decision is an approver's answer to an approval.
+type Decision struct {
+	Status    Status // StatusApproved or StatusRejected
+	DecidedBy string
+	Comment   string
+	DecidedAt time.Time
+}
+
+// ListFilter selects approvals. Empty fields match every approval.
+type ListFilter struct {
+	Namespace string
+	Status    Status
+	Limit     int
+}
+
+// Store persists approvals.
+type Store interface {
+	CreateApproval(ctx context.Context, a *Approval) error
+	// GetApproval returns ErrNotFound if no approval has the ID.
+	GetApproval(ctx context.Context, id string) (*Approval, error)
+	// ListApprovals returns approvals oldest first. Pending approvals past
+	// their expiry are listed as expired.
+	ListApprovals(ctx context.Context, filter ListFilter) ([]*Approval, error)
+	// DecideApproval records d on a pending approval that has not expired,
+	// and returns ErrNotPending otherwise.
+	DecideApproval(ctx context.Context, id string, d Decision) (*Approval, error)
+	// ReopenApproval clears the decision of an approval whose node could not
+	// be resolved and sets its status to StatusPending, so the decision can
+	// be retried, or StatusExpired when the node no longer waits.
+	ReopenApproval(ctx context.Context, id string, status Status) error
+}
+
+// Branches an approval node takes, named in the "output" field of its result.
+const (
+	BranchApproved = "approved"
+	BranchRejected = "rejected"
+)
+
+// Result is the output an approval node completes with.
+type Result struct {
+	Output     string    `json:"output"`
+	ApprovalID string    `json:"approval_id"`
+	Approved   bool      `json:"approved"`
+	DecidedBy  string    `json:"decided_by,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	DecidedAt  time.Time `json:"decided_at,omitzero"`
+	TimedOut   bool      `json:"timed_out,omitempty"`
+}
+
+// DecisionResult returns the node result recording a's decision.
+func DecisionResult(a *Approval) Result {
+	result := Result{
+		Output:     BranchRejected,
+		ApprovalID: a.ID,
+		Approved:   a.Status == StatusApproved,
+		DecidedBy:  a.DecidedBy,
+		Comment:    a.Comment,
+		DecidedAt:  a.DecidedAt,
+	}
+	if result.Approved {
+		result.Output = BranchApproved
+	}
+	return result
+}
+
+// TimeoutResult returns the node result of an approval nobody decided in
+// time, which counts as a rejection.
+func TimeoutResult(id string) Result {
+	return Result{Output: BranchRejected, ApprovalID: id, TimedOut: true}
+}
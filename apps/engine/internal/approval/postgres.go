@@ -0,0 +1,173 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore implements Store on the approvals table (see
+// scripts/migrations).
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+const approvalColumns = `id, namespace_id, workflow_id, run_id, node_id, task_token, title, reason,
+	approvers, payload, on_reject, status, created_at, expires_at, decided_by, comment, decided_at`
+
+func (s *PostgresStore) CreateApproval(ctx context.Context, a *Approval) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO approvals (id, namespace_id, workflow_id, run_id, node_id, task_token, title, reason,
+			approvers, payload, on_reject, status, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, a.ID, a.Namespace, a.WorkflowID, a.RunID, a.NodeID, a.TaskToken, a.Title, a.Reason,
+		nonNilStrings(a.Approvers), nullableJSON(a.Payload), a.OnReject, a.Status, a.CreatedAt, nullableTime(a.ExpiresAt))
+	if err != nil {
+		return fmt.Errorf("failed to create approval: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetApproval(ctx context.Context, id string) (*Approval, error) {
+	a, err := scanApproval(s.pool.QueryRow(ctx, `SELECT `+approvalColumns+` FROM approvals WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval: %w", err)
+	}
+	return a, nil
+}
+
+func (s *PostgresStore) ListApprovals(ctx context.Context, filter ListFilter) ([]*Approval, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Namespace != "" {
+		args = append(args, filter.Namespace)
+		conditions = append(conditions, fmt.Sprintf("namespace_id = $%d", len(args)))
+	}
+	switch filter.Status {
+	case "":
+	case StatusPending:
+		conditions = append(conditions, "status = 'pending' AND (expires_at IS NULL OR expires_at > NOW())")
+	case StatusExpired:
+		conditions = append(conditions, "(status = 'expired' OR (status = 'pending' AND expires_at <= NOW()))")
+	default:
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := `SELECT ` + approvalColumns + ` FROM approvals`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at, id`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := []*Approval{}
+	now := time.Now()
+	for rows.Next() {
+		a, err := scanApproval(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan approval: %w", err)
+		}
+		if a.Expired(now) {
+			a.Status = StatusExpired
+		}
+		approvals = append(approvals, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	return approvals, nil
+}
+
+func (s *PostgresStore) DecideApproval(ctx context.Context, id string, d Decision) (*Approval, error) {
+	a, err := scanApproval(s.pool.QueryRow(ctx, `
+		UPDATE approvals SET status = $2, decided_by = $3, comment = $4, decided_at = $5
+		WHERE id = $1 AND status = 'pending' AND (expires_at IS NULL OR expires_at > $5)
+		RETURNING `+approvalColumns,
+		id, d.Status, d.DecidedBy, d.Comment, d.DecidedAt))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Either there is no such approval or it is not pending.
+		if _, getErr := s.GetApproval(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrNotPending
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decide approval: %w", err)
+	}
+	return a, nil
+}
+
+func (s *PostgresStore) ReopenApproval(ctx context.Context, id string, status Status) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE approvals SET status = $2, decided_by = '', comment = '', decided_at = NULL
+		WHERE id = $1
+	`, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to reopen approval: %w", err)
+	}
+	return nil
+}
+
+func scanApproval(row pgx.Row) (*Approval, error) {
+	var a Approval
+	var payload []byte
+	var expiresAt, decidedAt *time.Time
+	err := row.Scan(&a.ID, &a.Namespace, &a.WorkflowID, &a.RunID, &a.NodeID, &a.TaskToken, &a.Title, &a.Reason,
+		&a.Approvers, &payload, &a.OnReject, &a.Status, &a.CreatedAt, &expiresAt, &a.DecidedBy, &a.Comment, &decidedAt)
+	if err != nil {
+		return nil, err
+	}
+	a.Payload = payload
+	if expiresAt != nil {
+		a.ExpiresAt = *expiresAt
+	}
+	if decidedAt != nil {
+		a.DecidedAt = *decidedAt
+	}
+	return &a, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
+
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
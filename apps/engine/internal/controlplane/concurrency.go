@@ -0,0 +1,101 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrConcurrencyLimitExceeded is returned when a namespace already runs its
+// maximum number of concurrent executions.
+var ErrConcurrencyLimitExceeded = errors.New("concurrency limit exceeded")
+
+const runningExecutionsKeyPrefix = "linkflow:running_executions:"
+
+// acquireScript increments the counter only while it is below the limit and
+// returns {running, acquired}.
+var acquireScript = redis.NewScript(`
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+local limit = tonumber(ARGV[1])
+if current >= limit then
+	return {current, 0}
+end
+return {redis.call("INCR", KEYS[1]), 1}
+`)
+
+// releaseScript decrements the counter without letting it go negative.
+var releaseScript = redis.NewScript(`
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+if current <= 0 then
+	redis.call("SET", KEYS[1], 0)
+	return 0
+end
+return redis.call("DECR", KEYS[1])
+`)
+
+// ExecutionCounter tracks running top-level executions per namespace in Redis.
+// The frontend increments it on start, history decrements it on close, and
+// history periodically resets it from visibility to correct drift.
+type ExecutionCounter struct {
+	client *redis.Client
+}
+
+// NewExecutionCounter creates a Redis-backed execution counter.
+func NewExecutionCounter(client *redis.Client) *ExecutionCounter {
+	return &ExecutionCounter{client: client}
+}
+
+func runningExecutionsKey(namespace string) string {
+	return runningExecutionsKeyPrefix + namespace
+}
+
+// TryAcquire reserves a slot for a new execution if the namespace runs fewer
+// than limit executions. It returns the running count after the attempt.
+func (c *ExecutionCounter) TryAcquire(ctx context.Context, namespace string, limit int) (int64, bool, error) {
+	res, err := acquireScript.Run(ctx, c.client, []string{runningExecutionsKey(namespace)}, limit).Int64Slice()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to acquire execution slot: %w", err)
+	}
+	if len(res) != 2 {
+		return 0, false, fmt.Errorf("unexpected acquire result: %v", res)
+	}
+	return res[0], res[1] == 1, nil
+}
+
+// Release frees the slot of an execution that closed or failed to start.
+func (c *ExecutionCounter) Release(ctx context.Context, namespace string) error {
+	if err := releaseScript.Run(ctx, c.client, []string{runningExecutionsKey(namespace)}).Err(); err != nil {
+		return fmt.Errorf("failed to release execution slot: %w", err)
+	}
+	return nil
+}
+
+// Get returns the current running count of a namespace.
+func (c *ExecutionCounter) Get(ctx context.Context, namespace string) (int64, error) {
+	n, err := c.client.Get(ctx, runningExecutionsKey(namespace)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
+
+// Set overwrites the running count of a namespace.
+func (c *ExecutionCounter) Set(ctx context.Context, namespace string, running int64) error {
+	return c.client.Set(ctx, runningExecutionsKey(namespace), running, 0).Err()
+}
+
+// Namespaces returns the namespaces that currently have a counter.
+func (c *ExecutionCounter) Namespaces(ctx context.Context) ([]string, error) {
+	var namespaces []string
+	iter := c.client.Scan(ctx, 0, runningExecutionsKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		namespaces = append(namespaces, strings.TrimPrefix(iter.Val(), runningExecutionsKeyPrefix))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan execution counters: %w", err)
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,36 @@
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	controlplanev1 "github.com/linkflow/engine/api/gen/linkflow/controlplane/v1"
+)
+
+// ConfigClient reads dynamic configuration from a control plane. It is the
+// dynamic config provider of services that refresh their limits remotely.
+type ConfigClient struct {
+	client controlplanev1.ConfigServiceClient
+}
+
+func NewConfigClient(conn grpc.ClientConnInterface) *ConfigClient {
+	return &ConfigClient{client: controlplanev1.NewConfigServiceClient(conn)}
+}
+
+// GetConfig returns the current JSON value of key, or ErrConfigKeyNotFound
+// if the control plane has none.
+func (c *ConfigClient) GetConfig(ctx context.Context, key string) (json.RawMessage, error) {
+	resp, err := c.client.GetConfig(ctx, &controlplanev1.GetConfigRequest{Key: key})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: %s", ErrConfigKeyNotFound, key)
+		}
+		return nil, err
+	}
+	return resp.GetValue(), nil
+}
@@ -0,0 +1,271 @@
+package controlplane
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// DefaultMaxConfigVersions is the number of versions kept per config key when
+// Config.MaxConfigVersions is not set.
+const DefaultMaxConfigVersions = 50
+
+const (
+	unknownConfigAuthor = "unknown"
+	systemConfigAuthor  = "system"
+)
+
+// ConfigVersion is one recorded value of a config key. Versions are
+// append-only; a rollback adds a new version carrying the restored value.
+type ConfigVersion struct {
+	Key            string          `json:"key"`
+	Version        int64           `json:"version"`
+	Value          json.RawMessage `json:"value"`
+	Author         string          `json:"author"`
+	CreatedAt      time.Time       `json:"created_at"`
+	RolledBackFrom int64           `json:"rolled_back_from,omitempty"`
+}
+
+// ConfigChange is a single field that differs between two config values.
+// Path is the dot-separated location of the field; Old or New is nil when the
+// field is absent on that side.
+type ConfigChange struct {
+	Path string          `json:"path"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+type configAuthorKey struct{}
+
+// WithConfigAuthor returns a copy of ctx attributing config changes to author.
+func WithConfigAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, configAuthorKey{}, author)
+}
+
+// ConfigAuthorFromContext returns the config author carried by ctx.
+func ConfigAuthorFromContext(ctx context.Context) string {
+	if author, ok := ctx.Value(configAuthorKey{}).(string); ok && author != "" {
+		return author
+	}
+	return unknownConfigAuthor
+}
+
+// GetConfigVersion returns a retained version of key.
+func (s *Service) GetConfigVersion(ctx context.Context, key string, version int64) (*ConfigVersion, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	v, err := s.findConfigVersionLocked(key, version)
+	if err != nil {
+		return nil, err
+	}
+	return v.clone(), nil
+}
+
+// ListConfigVersions returns the retained versions of key, oldest first.
+func (s *Service) ListConfigVersions(ctx context.Context, key string) []*ConfigVersion {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	history := s.configHistory[key]
+	versions := make([]*ConfigVersion, 0, len(history))
+	for _, v := range history {
+		versions = append(versions, v.clone())
+	}
+	return versions
+}
+
+// RollbackConfig restores the value of version and records it as a new
+// version.
+func (s *Service) RollbackConfig(ctx context.Context, key string, version int64) (*ConfigVersion, error) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	target, err := s.findConfigVersionLocked(key, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateConfigLocked(ctx, key, target.Value, target.Version)
+}
+
+// DiffConfigVersions returns the fields that changed between two versions of
+// key.
+func (s *Service) DiffConfigVersions(ctx context.Context, key string, from, to int64) ([]ConfigChange, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	fromVersion, err := s.findConfigVersionLocked(key, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.findConfigVersionLocked(key, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffConfigValues(fromVersion.Value, toVersion.Value)
+}
+
+func (s *Service) findConfigVersionLocked(key string, version int64) (*ConfigVersion, error) {
+	for _, v := range s.configHistory[key] {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s@%d", ErrConfigVersionNotFound, key, version)
+}
+
+// updateConfigLocked applies value to key and appends the resulting version.
+// The first change to a key that already has a value also records that value,
+// so the defaults can be rolled back to.
+func (s *Service) updateConfigLocked(ctx context.Context, key string, value json.RawMessage, rolledBackFrom int64) (*ConfigVersion, error) {
+	previous, prevErr := s.getConfigLocked(key)
+
+	if err := s.applyConfigLocked(key, value); err != nil {
+		return nil, err
+	}
+	// Record the effective value so partial updates of built-in keys diff and
+	// roll back exactly.
+	current, err := s.getConfigLocked(key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	history := s.configHistory[key]
+	if len(history) == 0 && prevErr == nil {
+		history = append(history, &ConfigVersion{
+			Key:       key,
+			Version:   1,
+			Value:     cloneRawMessage(previous),
+			Author:    systemConfigAuthor,
+			CreatedAt: now,
+		})
+	}
+
+	var previousVersion int64
+	if len(history) > 0 {
+		previousVersion = history[len(history)-1].Version
+	}
+	version := &ConfigVersion{
+		Key:            key,
+		Version:        previousVersion + 1,
+		Value:          cloneRawMessage(current),
+		Author:         ConfigAuthorFromContext(ctx),
+		CreatedAt:      now,
+		RolledBackFrom: rolledBackFrom,
+	}
+	history = append(history, version)
+	if excess := len(history) - s.config.MaxConfigVersions; excess > 0 {
+		history = append([]*ConfigVersion(nil), history[excess:]...)
+	}
+	s.configHistory[key] = history
+
+	changed := []string{}
+	if changes, err := diffConfigValues(previous, current); err == nil {
+		for _, c := range changes {
+			changed = append(changed, c.Path)
+		}
+	}
+	attrs := []any{
+		slog.String("key", key),
+		slog.Int64("version", version.Version),
+		slog.Int64("previous_version", previousVersion),
+		slog.String("author", version.Author),
+		slog.Any("changed", changed),
+	}
+	if rolledBackFrom > 0 {
+		attrs = append(attrs, slog.Int64("rolled_back_from", rolledBackFrom))
+	}
+	s.logger.Info("config updated", attrs...)
+
+	return version.clone(), nil
+}
+
+func (v *ConfigVersion) clone() *ConfigVersion {
+	c := *v
+	c.Value = cloneRawMessage(v.Value)
+	return &c
+}
+
+func cloneRawMessage(raw json.RawMessage) json.RawMessage {
+	if raw == nil {
+		return nil
+	}
+	return append(json.RawMessage(nil), raw...)
+}
+
+// diffConfigValues compares two JSON documents field by field. Objects are
+// walked recursively; arrays and scalars are compared as a whole.
+func diffConfigValues(oldValue, newValue json.RawMessage) ([]ConfigChange, error) {
+	oldFields, err := flattenConfigValue(oldValue)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenConfigValue(newValue)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]struct{}, len(oldFields)+len(newFields))
+	for p := range oldFields {
+		paths[p] = struct{}{}
+	}
+	for p := range newFields {
+		paths[p] = struct{}{}
+	}
+
+	changes := []ConfigChange{}
+	for p := range paths {
+		o, n := oldFields[p], newFields[p]
+		if o != nil && n != nil && bytes.Equal(o, n) {
+			continue
+		}
+		changes = append(changes, ConfigChange{Path: p, Old: o, New: n})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func flattenConfigValue(raw json.RawMessage) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return fields, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid config value: %w", err)
+	}
+	if err := flattenInto(fields, "", doc); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func flattenInto(fields map[string]json.RawMessage, path string, value interface{}) error {
+	if obj, ok := value.(map[string]interface{}); ok && len(obj) > 0 {
+		for k, v := range obj {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			if err := flattenInto(fields, child, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	fields[path] = encoded
+	return nil
+}
@@ -0,0 +1,66 @@
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestConfigVersionsRollback(t *testing.T) {
+	svc := NewService(Config{
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+		MaxConfigVersions: 3,
+	})
+	ctx := WithConfigAuthor(context.Background(), "alice")
+
+	for _, rps := range []int{10, 20, 30} {
+		value, _ := json.Marshal(RateLimitConfig{RequestsPerSecond: rps, BurstSize: 5})
+		if err := svc.SetConfig(ctx, "rate_limits", value); err != nil {
+			t.Fatalf("SetConfig: %v", err)
+		}
+	}
+
+	// The defaults were recorded as version 1 and then trimmed away.
+	versions := svc.ListConfigVersions(ctx, "rate_limits")
+	if len(versions) != 3 || versions[0].Version != 2 || versions[2].Version != 4 {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+	if versions[2].Author != "alice" {
+		t.Fatalf("author = %q, want alice", versions[2].Author)
+	}
+	if _, err := svc.GetConfigVersion(ctx, "rate_limits", 1); !errors.Is(err, ErrConfigVersionNotFound) {
+		t.Fatalf("expected trimmed version to be gone, got %v", err)
+	}
+
+	changes, err := svc.DiffConfigVersions(ctx, "rate_limits", 2, 4)
+	if err != nil {
+		t.Fatalf("DiffConfigVersions: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Path != "requests_per_second" ||
+		string(changes[0].Old) != "10" || string(changes[0].New) != "30" {
+		t.Fatalf("unexpected diff %+v", changes)
+	}
+
+	rolledBack, err := svc.RollbackConfig(WithConfigAuthor(context.Background(), "bob"), "rate_limits", 2)
+	if err != nil {
+		t.Fatalf("RollbackConfig: %v", err)
+	}
+	if rolledBack.Version != 5 || rolledBack.RolledBackFrom != 2 || rolledBack.Author != "bob" {
+		t.Fatalf("unexpected rollback version %+v", rolledBack)
+	}
+
+	current, err := svc.GetConfig(ctx, "rate_limits")
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	var cfg RateLimitConfig
+	if err := json.Unmarshal(current, &cfg); err != nil {
+		t.Fatalf("decode config: %v", err)
+	}
+	if cfg.RequestsPerSecond != 10 {
+		t.Fatalf("requests_per_second = %d after rollback, want 10", cfg.RequestsPerSecond)
+	}
+}
@@ -0,0 +1,147 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// ErrNoHealthyInstance is returned by ResolveService when a service has no
+// instance to route to.
+var ErrNoHealthyInstance = errors.New("no healthy service instance")
+
+// LoadBalancingPolicy selects one of a service's healthy instances.
+type LoadBalancingPolicy string
+
+const (
+	// LoadBalancingRoundRobin cycles through the healthy instances.
+	LoadBalancingRoundRobin LoadBalancingPolicy = "round_robin"
+	// LoadBalancingLeastConnections picks the instance reporting the fewest
+	// active connections, cycling through ties.
+	LoadBalancingLeastConnections LoadBalancingPolicy = "least_connections"
+)
+
+// SRVResolver looks up DNS SRV records. *net.Resolver implements it.
+type SRVResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// ResolveService returns one healthy instance of service, chosen by the
+// configured load-balancing policy. When the service has no healthy
+// registered instance and the enable_dns_discovery feature flag is set, the
+// instance is chosen from its SRV records instead, among the targets with
+// the lowest priority.
+func (s *Service) ResolveService(ctx context.Context, service string) (*ServiceInstance, error) {
+	s.mu.Lock()
+	instances := make([]*ServiceInstance, 0, len(s.services[service]))
+	for _, inst := range s.services[service] {
+		if inst.Health == HealthStatusServing {
+			instances = append(instances, inst)
+		}
+	}
+	_, registered := s.services[service]
+	if len(instances) > 0 {
+		inst := s.pickInstanceLocked(service, instances)
+		s.mu.Unlock()
+		return inst, nil
+	}
+	s.mu.Unlock()
+
+	if !s.dnsDiscoveryEnabled() {
+		if !registered {
+			return nil, ErrServiceNotFound
+		}
+		return nil, ErrNoHealthyInstance
+	}
+
+	instances, err := s.lookupSRVInstances(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return nil, ErrNoHealthyInstance
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pickInstanceLocked(service, instances), nil
+}
+
+// pickInstanceLocked applies the load-balancing policy to a non-empty list
+// of candidates. The caller must hold s.mu.
+func (s *Service) pickInstanceLocked(service string, instances []*ServiceInstance) *ServiceInstance {
+	candidates := instances
+	if s.config.LoadBalancingPolicy == LoadBalancingLeastConnections {
+		least := instances[0].ActiveConnections
+		for _, inst := range instances[1:] {
+			if inst.ActiveConnections < least {
+				least = inst.ActiveConnections
+			}
+		}
+		candidates = make([]*ServiceInstance, 0, len(instances))
+		for _, inst := range instances {
+			if inst.ActiveConnections == least {
+				candidates = append(candidates, inst)
+			}
+		}
+	}
+
+	next := s.nextInstance[service]
+	s.nextInstance[service] = next + 1
+	return candidates[next%uint64(len(candidates))]
+}
+
+func (s *Service) dnsDiscoveryEnabled() bool {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.dynamicConfig.FeatureFlags != nil && s.dynamicConfig.FeatureFlags.EnableDNSDiscovery
+}
+
+// lookupSRVInstances returns the targets of the service's SRV records with
+// the lowest priority, ordered by target so round-robin is stable across
+// lookups.
+func (s *Service) lookupSRVInstances(ctx context.Context, service string) ([]*ServiceInstance, error) {
+	if s.config.DNSDomain == "" {
+		return nil, ErrNoHealthyInstance
+	}
+
+	_, records, err := s.config.SRVResolver.LookupSRV(ctx, service, "tcp", s.config.DNSDomain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("failed to resolve SRV records for %s: %w", service, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	lowest := records[0].Priority
+	for _, srv := range records[1:] {
+		if srv.Priority < lowest {
+			lowest = srv.Priority
+		}
+	}
+
+	instances := make([]*ServiceInstance, 0, len(records))
+	for _, srv := range records {
+		if srv.Priority != lowest {
+			continue
+		}
+		target := strings.TrimSuffix(srv.Target, ".")
+		instances = append(instances, &ServiceInstance{
+			ID:       fmt.Sprintf("dns:%s:%d", target, srv.Port),
+			Service:  service,
+			Address:  target,
+			Port:     int(srv.Port),
+			Metadata: map[string]string{"source": "dns"},
+			Health:   HealthStatusServing,
+		})
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].ID < instances[j].ID })
+	return instances, nil
+}
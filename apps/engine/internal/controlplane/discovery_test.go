@@ -0,0 +1,108 @@
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+)
+
+type fakeSRVResolver struct {
+	records []*net.SRV
+	lookups []string
+}
+
+func (r *fakeSRVResolver) LookupSRV(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.lookups = append(r.lookups, "_"+service+"._"+proto+"."+name)
+	return "", r.records, nil
+}
+
+func TestResolveServicePolicies(t *testing.T) {
+	ctx := context.Background()
+	newService := func(policy LoadBalancingPolicy) *Service {
+		svc := NewService(Config{
+			Logger:              slog.New(slog.NewTextHandler(io.Discard, nil)),
+			LoadBalancingPolicy: policy,
+		})
+		for i, id := range []string{"a", "b", "c"} {
+			_ = svc.RegisterService(ctx, &ServiceInstance{ID: id, Service: "frontend", Address: id, ActiveConnections: []int{4, 1, 1}[i]})
+		}
+		return svc
+	}
+	resolve := func(svc *Service, n int) []string {
+		var ids []string
+		for i := 0; i < n; i++ {
+			inst, err := svc.ResolveService(ctx, "frontend")
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			ids = append(ids, inst.ID)
+		}
+		return ids
+	}
+
+	if got := resolve(newService(""), 4); got[0] != "a" || got[1] != "b" || got[2] != "c" || got[3] != "a" {
+		t.Fatalf("round robin = %v, want a b c a", got)
+	}
+
+	svc := newService(LoadBalancingLeastConnections)
+	if got := resolve(svc, 3); got[0] != "b" || got[1] != "c" || got[2] != "b" {
+		t.Fatalf("least connections = %v, want b c b", got)
+	}
+
+	svc.services["frontend"][1].Health = HealthStatusNotServing
+	svc.services["frontend"][2].Health = HealthStatusNotServing
+	if got := resolve(svc, 1); got[0] != "a" {
+		t.Fatalf("with b and c unhealthy got %v, want a", got)
+	}
+
+	if _, err := svc.ResolveService(ctx, "matching"); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("unknown service error = %v, want ErrServiceNotFound", err)
+	}
+}
+
+func TestResolveServiceFallsBackToSRV(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeSRVResolver{records: []*net.SRV{
+		{Target: "backup.example.com.", Port: 7233, Priority: 20},
+		{Target: "fe-2.example.com.", Port: 7233, Priority: 10},
+		{Target: "fe-1.example.com.", Port: 7233, Priority: 10},
+	}}
+	svc := NewService(Config{
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		DNSDomain:   "example.com",
+		SRVResolver: resolver,
+	})
+
+	if _, err := svc.ResolveService(ctx, "frontend"); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("resolve without DNS discovery error = %v, want ErrServiceNotFound", err)
+	}
+
+	flags, _ := json.Marshal(FeatureFlags{EnableDNSDiscovery: true})
+	if err := svc.SetConfig(ctx, "feature_flags", flags); err != nil {
+		t.Fatalf("set feature flags: %v", err)
+	}
+	var got []string
+	for i := 0; i < 3; i++ {
+		inst, err := svc.ResolveService(ctx, "frontend")
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		got = append(got, inst.Address)
+	}
+	if got[0] != "fe-1.example.com" || got[1] != "fe-2.example.com" || got[2] != "fe-1.example.com" {
+		t.Fatalf("SRV instances = %v, want fe-1 and fe-2 in turn", got)
+	}
+	if resolver.lookups[0] != "_frontend._tcp.example.com" {
+		t.Fatalf("looked up %q", resolver.lookups[0])
+	}
+
+	// Registered instances take precedence over DNS.
+	_ = svc.RegisterService(ctx, &ServiceInstance{ID: "local", Service: "frontend", Address: "10.0.0.1"})
+	if inst, err := svc.ResolveService(ctx, "frontend"); err != nil || inst.ID != "local" {
+		t.Fatalf("resolve = %+v, %v, want the registered instance", inst, err)
+	}
+}
@@ -0,0 +1,156 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	controlplanev1 "github.com/linkflow/engine/api/gen/linkflow/controlplane/v1"
+)
+
+// GRPCServer exposes dynamic configuration and its version history over gRPC.
+type GRPCServer struct {
+	controlplanev1.UnimplementedConfigServiceServer
+	service *Service
+}
+
+func NewGRPCServer(service *Service) *GRPCServer {
+	return &GRPCServer{service: service}
+}
+
+func (s *GRPCServer) GetConfig(ctx context.Context, req *controlplanev1.GetConfigRequest) (*controlplanev1.GetConfigResponse, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	value, err := s.service.GetConfig(ctx, req.Key)
+	if err != nil {
+		return nil, toConfigGRPCError(err)
+	}
+	return &controlplanev1.GetConfigResponse{Value: value}, nil
+}
+
+func (s *GRPCServer) ListConfigVersions(ctx context.Context, req *controlplanev1.ListConfigVersionsRequest) (*controlplanev1.ListConfigVersionsResponse, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	versions := s.service.ListConfigVersions(ctx, req.Key)
+	resp := &controlplanev1.ListConfigVersionsResponse{
+		Versions: make([]*controlplanev1.ConfigVersion, 0, len(versions)),
+	}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, toProtoConfigVersion(v))
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) GetConfigVersion(ctx context.Context, req *controlplanev1.GetConfigVersionRequest) (*controlplanev1.GetConfigVersionResponse, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	v, err := s.service.GetConfigVersion(ctx, req.Key, req.Version)
+	if err != nil {
+		return nil, toConfigGRPCError(err)
+	}
+	return &controlplanev1.GetConfigVersionResponse{Version: toProtoConfigVersion(v)}, nil
+}
+
+func (s *GRPCServer) DiffConfigVersions(ctx context.Context, req *controlplanev1.DiffConfigVersionsRequest) (*controlplanev1.DiffConfigVersionsResponse, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	changes, err := s.service.DiffConfigVersions(ctx, req.Key, req.FromVersion, req.ToVersion)
+	if err != nil {
+		return nil, toConfigGRPCError(err)
+	}
+	resp := &controlplanev1.DiffConfigVersionsResponse{
+		Changes: make([]*controlplanev1.ConfigChange, 0, len(changes)),
+	}
+	for _, c := range changes {
+		resp.Changes = append(resp.Changes, &controlplanev1.ConfigChange{
+			Path:     c.Path,
+			OldValue: c.Old,
+			NewValue: c.New,
+		})
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) RollbackConfig(ctx context.Context, req *controlplanev1.RollbackConfigRequest) (*controlplanev1.RollbackConfigResponse, error) {
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	v, err := s.service.RollbackConfig(withCallerAuthor(ctx), req.Key, req.Version)
+	if err != nil {
+		return nil, toConfigGRPCError(err)
+	}
+	return &controlplanev1.RollbackConfigResponse{Version: toProtoConfigVersion(v)}, nil
+}
+
+// DiscoveryGRPCServer exposes service resolution over gRPC, so proxies can
+// pick upstream instances instead of using a static address.
+type DiscoveryGRPCServer struct {
+	controlplanev1.UnimplementedDiscoveryServiceServer
+	service *Service
+}
+
+func NewDiscoveryGRPCServer(service *Service) *DiscoveryGRPCServer {
+	return &DiscoveryGRPCServer{service: service}
+}
+
+func (s *DiscoveryGRPCServer) ResolveService(ctx context.Context, req *controlplanev1.ResolveServiceRequest) (*controlplanev1.ResolveServiceResponse, error) {
+	if req.Service == "" {
+		return nil, status.Error(codes.InvalidArgument, "service is required")
+	}
+
+	inst, err := s.service.ResolveService(ctx, req.Service)
+	if err != nil {
+		return nil, toDiscoveryGRPCError(err)
+	}
+	return &controlplanev1.ResolveServiceResponse{
+		Instance: &controlplanev1.ServiceInstance{
+			Id:                inst.ID,
+			Service:           inst.Service,
+			Address:           inst.Address,
+			Port:              int32(inst.Port),
+			Metadata:          inst.Metadata,
+			Version:           inst.Version,
+			ActiveConnections: int32(inst.ActiveConnections),
+		},
+	}, nil
+}
+
+func toProtoConfigVersion(v *ConfigVersion) *controlplanev1.ConfigVersion {
+	return &controlplanev1.ConfigVersion{
+		Key:            v.Key,
+		Version:        v.Version,
+		Value:          v.Value,
+		Author:         v.Author,
+		CreateTime:     timestamppb.New(v.CreatedAt),
+		RolledBackFrom: v.RolledBackFrom,
+	}
+}
+
+func toConfigGRPCError(err error) error {
+	if errors.Is(err, ErrConfigVersionNotFound) || errors.Is(err, ErrConfigKeyNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func toDiscoveryGRPCError(err error) error {
+	switch {
+	case errors.Is(err, ErrServiceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrNoHealthyInstance):
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,168 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultLeaderElectionInterval is how often a replica retries for leadership
+// and the leader re-checks that it still holds it.
+const DefaultLeaderElectionInterval = 5 * time.Second
+
+// controlPlaneLeaderLockID is the Postgres advisory lock key that elects the
+// control plane leader.
+const controlPlaneLeaderLockID int64 = 0x6c666370 // "lfcp"
+
+// LeaderElector decides which control plane replica runs the background
+// loops. TryAcquire is called periodically by every replica: it returns true
+// while this replica holds leadership, acquiring it if it is free.
+type LeaderElector interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+// PostgresLeaderElector elects a leader with a session-level Postgres
+// advisory lock held on a dedicated connection. When the leader dies its
+// connection closes, Postgres releases the lock, and the next replica to try
+// takes over.
+type PostgresLeaderElector struct {
+	pool   *pgxpool.Pool
+	lockID int64
+
+	mu   sync.Mutex
+	conn *pgxpool.Conn // Held while this replica is leader
+}
+
+// NewPostgresLeaderElector creates a leader elector backed by pool.
+func NewPostgresLeaderElector(pool *pgxpool.Pool) *PostgresLeaderElector {
+	return &PostgresLeaderElector{pool: pool, lockID: controlPlaneLeaderLockID}
+}
+
+func (e *PostgresLeaderElector) TryAcquire(ctx context.Context) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		// The lock lives as long as the session; a failed ping means the
+		// connection, and with it leadership, is gone.
+		if err := e.conn.Ping(ctx); err != nil {
+			e.conn.Conn().Close(context.Background())
+			e.conn.Release()
+			e.conn = nil
+			return false, fmt.Errorf("leader connection lost: %w", err)
+		}
+		return true, nil
+	}
+
+	conn, err := e.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", e.lockID).Scan(&acquired); err != nil {
+		conn.Release()
+		return false, fmt.Errorf("failed to try advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Release()
+		return false, nil
+	}
+	e.conn = conn
+	return true, nil
+}
+
+func (e *PostgresLeaderElector) Release(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+	conn := e.conn
+	e.conn = nil
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", e.lockID); err != nil {
+		// Closing the session releases the lock regardless.
+		conn.Conn().Close(context.Background())
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	return nil
+}
+
+// IsLeader reports whether this replica currently runs the background loops.
+// Without a LeaderElector every started replica is the leader.
+func (s *Service) IsLeader() bool {
+	return s.leader.Load()
+}
+
+// runLeaderElection campaigns for leadership until ctx is done or the service
+// stops, running the background loops only while this replica is leader.
+func (s *Service) runLeaderElection(ctx context.Context) {
+	ticker := time.NewTicker(s.config.LeaderElectionInterval)
+	defer ticker.Stop()
+
+	var stopLeading context.CancelFunc
+	defer func() {
+		if stopLeading != nil {
+			stopLeading()
+		}
+		releaseCtx, cancel := context.WithTimeout(context.Background(), s.config.LeaderElectionInterval)
+		defer cancel()
+		if err := s.config.LeaderElector.Release(releaseCtx); err != nil {
+			s.logger.Warn("failed to release control plane leadership", slog.String("error", err.Error()))
+		}
+		s.setLeader(false, nil)
+	}()
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, s.config.LeaderElectionInterval)
+		leader, err := s.config.LeaderElector.TryAcquire(checkCtx)
+		cancel()
+		if err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.Warn("leader election check failed", slog.String("error", err.Error()))
+		}
+
+		switch {
+		case leader && stopLeading == nil:
+			leaderCtx, cancelLeader := context.WithCancel(ctx)
+			stopLeading = cancelLeader
+			s.setLeader(true, nil)
+			s.startBackgroundTasks(leaderCtx)
+		case !leader && stopLeading != nil:
+			stopLeading()
+			stopLeading = nil
+			s.setLeader(false, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setLeader records a leadership transition and logs it.
+func (s *Service) setLeader(leader bool, cause error) {
+	if s.leader.Swap(leader) == leader {
+		return
+	}
+	if leader {
+		s.logger.Info("acquired control plane leadership", slog.String("cluster_id", s.config.ClusterID))
+		return
+	}
+	attrs := []any{slog.String("cluster_id", s.config.ClusterID)}
+	if cause != nil {
+		attrs = append(attrs, slog.String("error", cause.Error()))
+	}
+	s.logger.Warn("lost control plane leadership", attrs...)
+}
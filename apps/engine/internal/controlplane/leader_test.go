@@ -0,0 +1,97 @@
+package controlplane
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeElector grants leadership while held is true.
+type fakeElector struct {
+	mu       sync.Mutex
+	held     bool
+	released bool
+}
+
+func (e *fakeElector) TryAcquire(context.Context) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.held, nil
+}
+
+func (e *fakeElector) Release(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.released = true
+	return nil
+}
+
+func (e *fakeElector) set(held bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.held = held
+}
+
+func TestLeaderElectionFailover(t *testing.T) {
+	elector := &fakeElector{}
+	svc := NewService(Config{
+		ClusterID:              "cp-1",
+		Logger:                 slog.New(slog.NewTextHandler(io.Discard, nil)),
+		LeaderElector:          elector,
+		LeaderElectionInterval: 5 * time.Millisecond,
+	})
+	ctx := context.Background()
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	waitFor := func(want bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for svc.IsLeader() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("IsLeader() = %v, want %v", !want, want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// A follower still serves registrations.
+	if svc.IsLeader() {
+		t.Fatal("replica became leader without holding the lock")
+	}
+	if _, err := svc.GetCluster(ctx, "cp-1"); err != nil {
+		t.Fatalf("follower did not register itself: %v", err)
+	}
+
+	elector.set(true)
+	waitFor(true)
+	elector.set(false)
+	waitFor(false)
+	elector.set(true)
+	waitFor(true)
+
+	if err := svc.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	waitFor(false)
+	elector.mu.Lock()
+	defer elector.mu.Unlock()
+	if !elector.released {
+		t.Fatal("leadership was not released on stop")
+	}
+}
+
+func TestServiceWithoutElectorIsLeader(t *testing.T) {
+	svc := NewService(Config{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer svc.Stop(context.Background())
+	if !svc.IsLeader() {
+		t.Fatal("a replica without leader election should lead")
+	}
+}
@@ -0,0 +1,134 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	controlplanev1 "github.com/linkflow/engine/api/gen/linkflow/controlplane/v1"
+)
+
+// DefaultNamespaceCacheTTL is how long a NamespaceClient reuses a namespace
+// it fetched, so quota checks do not call the control plane on every start.
+const DefaultNamespaceCacheTTL = 30 * time.Second
+
+// NamespaceGRPCServer serves namespace settings over gRPC.
+type NamespaceGRPCServer struct {
+	controlplanev1.UnimplementedNamespaceServiceServer
+	service *Service
+}
+
+func NewNamespaceGRPCServer(service *Service) *NamespaceGRPCServer {
+	return &NamespaceGRPCServer{service: service}
+}
+
+func (s *NamespaceGRPCServer) GetNamespace(ctx context.Context, req *controlplanev1.GetNamespaceRequest) (*controlplanev1.GetNamespaceResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	ns, err := s.service.GetNamespace(ctx, req.GetName())
+	if err != nil {
+		if errors.Is(err, ErrNamespaceNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &controlplanev1.GetNamespaceResponse{Namespace: namespaceToProto(ns)}, nil
+}
+
+func namespaceToProto(ns *NamespaceConfig) *controlplanev1.Namespace {
+	return &controlplanev1.Namespace{
+		Name:                    ns.Name,
+		Description:             ns.Description,
+		OwnerEmail:              ns.OwnerEmail,
+		RetentionDays:           int32(ns.RetentionDays),
+		MaxConcurrentExecutions: int32(ns.MaxConcurrentExecutions),
+		TaskQueueSoftLimit:      int32(ns.TaskQueueSoftLimit),
+		TaskQueueHardLimit:      int32(ns.TaskQueueHardLimit),
+	}
+}
+
+func namespaceFromProto(ns *controlplanev1.Namespace) *NamespaceConfig {
+	return &NamespaceConfig{
+		ID:                      ns.GetName(),
+		Name:                    ns.GetName(),
+		Description:             ns.GetDescription(),
+		OwnerEmail:              ns.GetOwnerEmail(),
+		RetentionDays:           int(ns.GetRetentionDays()),
+		MaxConcurrentExecutions: int(ns.GetMaxConcurrentExecutions()),
+		TaskQueueSoftLimit:      int(ns.GetTaskQueueSoftLimit()),
+		TaskQueueHardLimit:      int(ns.GetTaskQueueHardLimit()),
+	}
+}
+
+// NamespaceClient reads namespace settings from a control plane. It is the
+// namespace config provider of services that enforce namespace quotas.
+// Namespaces, including unknown ones, are cached for the cache TTL.
+type NamespaceClient struct {
+	client controlplanev1.NamespaceServiceClient
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedNamespace
+}
+
+type cachedNamespace struct {
+	ns        *NamespaceConfig
+	expiresAt time.Time
+}
+
+// NewNamespaceClient creates a client that caches namespaces for ttl, or
+// DefaultNamespaceCacheTTL if ttl is not positive.
+func NewNamespaceClient(conn grpc.ClientConnInterface, ttl time.Duration) *NamespaceClient {
+	if ttl <= 0 {
+		ttl = DefaultNamespaceCacheTTL
+	}
+	return &NamespaceClient{
+		client: controlplanev1.NewNamespaceServiceClient(conn),
+		ttl:    ttl,
+		cache:  make(map[string]cachedNamespace),
+	}
+}
+
+// GetNamespace returns the namespace's settings, or ErrNamespaceNotFound if
+// the control plane has no such namespace.
+func (c *NamespaceClient) GetNamespace(ctx context.Context, name string) (*NamespaceConfig, error) {
+	now := time.Now()
+	c.mu.Lock()
+	cached, ok := c.cache[name]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		if cached.ns == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNamespaceNotFound, name)
+		}
+		return cached.ns, nil
+	}
+
+	var ns *NamespaceConfig
+	resp, err := c.client.GetNamespace(ctx, &controlplanev1.GetNamespaceRequest{Name: name})
+	switch {
+	case err == nil:
+		ns = namespaceFromProto(resp.GetNamespace())
+	case status.Code(err) == codes.NotFound:
+		err = fmt.Errorf("%w: %s", ErrNamespaceNotFound, name)
+	default:
+		// Other failures are not cached so the next call retries.
+		return nil, err
+	}
+
+	c.mu.Lock()
+	for key, entry := range c.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(c.cache, key)
+		}
+	}
+	c.cache[name] = cachedNamespace{ns: ns, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return ns, err
+}
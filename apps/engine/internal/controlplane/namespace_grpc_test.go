@@ -0,0 +1,61 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	controlplanev1 "github.com/linkflow/engine/api/gen/linkflow/controlplane/v1"
+)
+
+func TestNamespaceClientReadsControlPlaneNamespaces(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(Config{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err := svc.CreateNamespace(ctx, &NamespaceConfig{ID: "ws-1", Name: "ws-1", MaxConcurrentExecutions: 50, TaskQueueSoftLimit: 500, TaskQueueHardLimit: 2000}); err != nil {
+		t.Fatalf("CreateNamespace: %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	controlplanev1.RegisterNamespaceServiceServer(server, NewNamespaceGRPCServer(svc))
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := NewNamespaceClient(conn, time.Hour)
+
+	ns, err := client.GetNamespace(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("GetNamespace: %v", err)
+	}
+	if ns.MaxConcurrentExecutions != 50 || ns.TaskQueueSoftLimit != 500 || ns.TaskQueueHardLimit != 2000 {
+		t.Fatalf("namespace = %+v, want the control plane's limits", ns)
+	}
+	if _, err := client.GetNamespace(ctx, "missing"); !errors.Is(err, ErrNamespaceNotFound) {
+		t.Fatalf("GetNamespace(missing) = %v, want ErrNamespaceNotFound", err)
+	}
+
+	// Cached namespaces, unknown ones included, are served without the
+	// control plane until they expire.
+	server.Stop()
+	if ns, err := client.GetNamespace(ctx, "ws-1"); err != nil || ns.MaxConcurrentExecutions != 50 {
+		t.Fatalf("cached GetNamespace = %+v, %v", ns, err)
+	}
+	if _, err := client.GetNamespace(ctx, "missing"); !errors.Is(err, ErrNamespaceNotFound) {
+		t.Fatalf("cached GetNamespace(missing) = %v, want ErrNamespaceNotFound", err)
+	}
+}
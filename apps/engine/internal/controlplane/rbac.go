@@ -0,0 +1,261 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPermissionDenied       = errors.New("permission denied")
+	ErrInvalidRole            = errors.New("invalid role")
+	ErrRoleAssignmentNotFound = errors.New("role assignment not found")
+)
+
+// bootstrapRoleAuthor is recorded as the assigner of Config.BootstrapAdmins.
+const bootstrapRoleAuthor = "bootstrap"
+
+// Role is a named set of permissions held by an identity.
+type Role string
+
+const (
+	// RoleViewer may read admin state such as config history.
+	RoleViewer Role = "viewer"
+	// RoleOperator may also run the operational admin actions.
+	RoleOperator Role = "operator"
+	// RoleAdmin holds every permission, including managing roles.
+	RoleAdmin Role = "admin"
+)
+
+// ParseRole returns the role named s and whether it is known.
+func ParseRole(s string) (Role, bool) {
+	switch role := Role(s); role {
+	case RoleViewer, RoleOperator, RoleAdmin:
+		return role, true
+	}
+	return "", false
+}
+
+// Permission is the right to call one kind of admin endpoint.
+type Permission string
+
+const (
+	PermissionViewAdmin       Permission = "admin.view"
+	PermissionForceTerminate  Permission = "execution.force_terminate"
+	PermissionDeleteExecution Permission = "execution.delete"
+	PermissionPurgeDLQ        Permission = "dlq.purge"
+	PermissionRebalanceShards Permission = "shards.rebalance"
+	PermissionRollbackConfig  Permission = "config.rollback"
+	PermissionManageRoles     Permission = "roles.manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {PermissionViewAdmin},
+	RoleOperator: {
+		PermissionViewAdmin,
+		PermissionForceTerminate,
+		PermissionPurgeDLQ,
+		PermissionRebalanceShards,
+	},
+	RoleAdmin: {
+		PermissionViewAdmin,
+		PermissionForceTerminate,
+		PermissionDeleteExecution,
+		PermissionPurgeDLQ,
+		PermissionRebalanceShards,
+		PermissionRollbackConfig,
+		PermissionManageRoles,
+	},
+}
+
+// Allows reports whether the role grants p.
+func (r Role) Allows(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAssignment gives an identity a role. Identities are the subject of
+// the caller's auth token.
+type RoleAssignment struct {
+	Identity   string    `json:"identity"`
+	Role       Role      `json:"role"`
+	AssignedBy string    `json:"assigned_by"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// RoleStore persists role assignments. An identity holds at most one role.
+type RoleStore interface {
+	// GetRoleAssignment returns ErrRoleAssignmentNotFound if identity has no
+	// role.
+	GetRoleAssignment(ctx context.Context, identity string) (*RoleAssignment, error)
+	ListRoleAssignments(ctx context.Context) ([]*RoleAssignment, error)
+	// PutRoleAssignment creates or replaces the assignment of a.Identity.
+	PutRoleAssignment(ctx context.Context, a *RoleAssignment) error
+	// DeleteRoleAssignment returns ErrRoleAssignmentNotFound if identity has
+	// no role.
+	DeleteRoleAssignment(ctx context.Context, identity string) error
+}
+
+// MemoryRoleStore keeps role assignments in memory, for single-replica
+// deployments without a database.
+type MemoryRoleStore struct {
+	mu          sync.RWMutex
+	assignments map[string]*RoleAssignment
+}
+
+func NewMemoryRoleStore() *MemoryRoleStore {
+	return &MemoryRoleStore{assignments: make(map[string]*RoleAssignment)}
+}
+
+var _ RoleStore = (*MemoryRoleStore)(nil)
+
+func (m *MemoryRoleStore) GetRoleAssignment(_ context.Context, identity string) (*RoleAssignment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.assignments[identity]
+	if !ok {
+		return nil, ErrRoleAssignmentNotFound
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (m *MemoryRoleStore) ListRoleAssignments(_ context.Context) ([]*RoleAssignment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	assignments := make([]*RoleAssignment, 0, len(m.assignments))
+	for _, a := range m.assignments {
+		copied := *a
+		assignments = append(assignments, &copied)
+	}
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].Identity < assignments[j].Identity })
+	return assignments, nil
+}
+
+func (m *MemoryRoleStore) PutRoleAssignment(_ context.Context, a *RoleAssignment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *a
+	m.assignments[a.Identity] = &copied
+	return nil
+}
+
+func (m *MemoryRoleStore) DeleteRoleAssignment(_ context.Context, identity string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.assignments[identity]; !ok {
+		return ErrRoleAssignmentNotFound
+	}
+	delete(m.assignments, identity)
+	return nil
+}
+
+// AssignRole gives identity role, replacing any role it held. The change is
+// attributed to the config author carried by ctx.
+func (s *Service) AssignRole(ctx context.Context, identity string, role Role) (*RoleAssignment, error) {
+	if identity == "" {
+		return nil, fmt.Errorf("%w: identity is required", ErrInvalidRole)
+	}
+	if _, ok := ParseRole(string(role)); !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRole, role)
+	}
+	if s.isBootstrapAdmin(identity) {
+		return nil, fmt.Errorf("%w: %s is a bootstrap admin", ErrInvalidRole, identity)
+	}
+
+	a := &RoleAssignment{
+		Identity:   identity,
+		Role:       role,
+		AssignedBy: ConfigAuthorFromContext(ctx),
+		AssignedAt: time.Now().UTC(),
+	}
+	if err := s.roles.PutRoleAssignment(ctx, a); err != nil {
+		return nil, err
+	}
+	s.logger.Info("role assigned",
+		slog.String("identity", identity),
+		slog.String("role", string(role)),
+		slog.String("assigned_by", a.AssignedBy),
+	)
+	return a, nil
+}
+
+// RevokeRole removes identity's role.
+func (s *Service) RevokeRole(ctx context.Context, identity string) error {
+	if s.isBootstrapAdmin(identity) {
+		return fmt.Errorf("%w: %s is a bootstrap admin", ErrInvalidRole, identity)
+	}
+	if err := s.roles.DeleteRoleAssignment(ctx, identity); err != nil {
+		return err
+	}
+	s.logger.Info("role revoked",
+		slog.String("identity", identity),
+		slog.String("revoked_by", ConfigAuthorFromContext(ctx)),
+	)
+	return nil
+}
+
+// ListRoleAssignments returns every role assignment, bootstrap admins
+// included, ordered by identity.
+func (s *Service) ListRoleAssignments(ctx context.Context) ([]*RoleAssignment, error) {
+	assignments, err := s.roles.ListRoleAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, identity := range s.config.BootstrapAdmins {
+		assignments = append(assignments, &RoleAssignment{
+			Identity:   identity,
+			Role:       RoleAdmin,
+			AssignedBy: bootstrapRoleAuthor,
+		})
+	}
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].Identity < assignments[j].Identity })
+	return assignments, nil
+}
+
+// RoleOf returns identity's role, or "" if it has none.
+func (s *Service) RoleOf(ctx context.Context, identity string) (Role, error) {
+	if s.isBootstrapAdmin(identity) {
+		return RoleAdmin, nil
+	}
+	a, err := s.roles.GetRoleAssignment(ctx, identity)
+	if errors.Is(err, ErrRoleAssignmentNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return a.Role, nil
+}
+
+// Authorize returns ErrPermissionDenied unless identity's role grants p.
+func (s *Service) Authorize(ctx context.Context, identity string, p Permission) error {
+	if identity == "" {
+		return fmt.Errorf("%w: no identity", ErrPermissionDenied)
+	}
+	role, err := s.RoleOf(ctx, identity)
+	if err != nil {
+		return err
+	}
+	if !role.Allows(p) {
+		return fmt.Errorf("%w: %s lacks %s", ErrPermissionDenied, identity, p)
+	}
+	return nil
+}
+
+func (s *Service) isBootstrapAdmin(identity string) bool {
+	for _, admin := range s.config.BootstrapAdmins {
+		if admin == identity {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	controlplanev1 "github.com/linkflow/engine/api/gen/linkflow/controlplane/v1"
+)
+
+// AccessGRPCServer exposes role management and permission checks over gRPC.
+// Role management is meant to be limited to admins with an RBACInterceptor;
+// CheckPermission is how other services authorize their admin endpoints.
+type AccessGRPCServer struct {
+	controlplanev1.UnimplementedAccessServiceServer
+	service *Service
+}
+
+func NewAccessGRPCServer(service *Service) *AccessGRPCServer {
+	return &AccessGRPCServer{service: service}
+}
+
+// PermissionDecision is the reply to a permission check.
+type PermissionDecision struct {
+	Allowed bool `json:"allowed"`
+	Role    Role `json:"role,omitempty"`
+}
+
+func (s *AccessGRPCServer) AssignRole(ctx context.Context, req *controlplanev1.AssignRoleRequest) (*controlplanev1.AssignRoleResponse, error) {
+	a, err := s.service.AssignRole(withCallerAuthor(ctx), req.GetIdentity(), Role(req.GetRole()))
+	if err != nil {
+		return nil, toAccessGRPCError(err)
+	}
+	return &controlplanev1.AssignRoleResponse{Assignment: roleAssignmentToProto(a)}, nil
+}
+
+func (s *AccessGRPCServer) RevokeRole(ctx context.Context, req *controlplanev1.RevokeRoleRequest) (*controlplanev1.RevokeRoleResponse, error) {
+	if req.GetIdentity() == "" {
+		return nil, status.Error(codes.InvalidArgument, "identity is required")
+	}
+	if err := s.service.RevokeRole(withCallerAuthor(ctx), req.GetIdentity()); err != nil {
+		return nil, toAccessGRPCError(err)
+	}
+	return &controlplanev1.RevokeRoleResponse{}, nil
+}
+
+func (s *AccessGRPCServer) ListRoleAssignments(ctx context.Context, _ *controlplanev1.ListRoleAssignmentsRequest) (*controlplanev1.ListRoleAssignmentsResponse, error) {
+	assignments, err := s.service.ListRoleAssignments(ctx)
+	if err != nil {
+		return nil, toAccessGRPCError(err)
+	}
+	resp := &controlplanev1.ListRoleAssignmentsResponse{
+		Assignments: make([]*controlplanev1.RoleAssignment, len(assignments)),
+	}
+	for i, a := range assignments {
+		resp.Assignments[i] = roleAssignmentToProto(a)
+	}
+	return resp, nil
+}
+
+func (s *AccessGRPCServer) CheckPermission(ctx context.Context, req *controlplanev1.CheckPermissionRequest) (*controlplanev1.CheckPermissionResponse, error) {
+	if req.GetIdentity() == "" || req.GetPermission() == "" {
+		return nil, status.Error(codes.InvalidArgument, "identity and permission are required")
+	}
+	role, err := s.service.RoleOf(ctx, req.GetIdentity())
+	if err != nil {
+		return nil, toAccessGRPCError(err)
+	}
+	return &controlplanev1.CheckPermissionResponse{
+		Allowed: role.Allows(Permission(req.GetPermission())),
+		Role:    string(role),
+	}, nil
+}
+
+func toAccessGRPCError(err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrRoleAssignmentNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func roleAssignmentToProto(a *RoleAssignment) *controlplanev1.RoleAssignment {
+	return &controlplanev1.RoleAssignment{
+		Identity:   a.Identity,
+		Role:       string(a.Role),
+		AssignedBy: a.AssignedBy,
+		AssignTime: timestamppb.New(a.AssignedAt),
+	}
+}
+
+func roleAssignmentFromProto(a *controlplanev1.RoleAssignment) *RoleAssignment {
+	return &RoleAssignment{
+		Identity:   a.GetIdentity(),
+		Role:       Role(a.GetRole()),
+		AssignedBy: a.GetAssignedBy(),
+		AssignedAt: a.GetAssignTime().AsTime(),
+	}
+}
+
+// AccessClient calls the access RPCs of a control plane. It is the
+// Authorizer of services that authorize admin endpoints remotely.
+type AccessClient struct {
+	client controlplanev1.AccessServiceClient
+}
+
+func NewAccessClient(conn grpc.ClientConnInterface) *AccessClient {
+	return &AccessClient{client: controlplanev1.NewAccessServiceClient(conn)}
+}
+
+var _ Authorizer = (*AccessClient)(nil)
+
+// AssignRole gives identity role. The caller must hold PermissionManageRoles.
+func (c *AccessClient) AssignRole(ctx context.Context, identity string, role Role) (*RoleAssignment, error) {
+	resp, err := c.client.AssignRole(ctx, &controlplanev1.AssignRoleRequest{Identity: identity, Role: string(role)})
+	if err != nil {
+		return nil, err
+	}
+	return roleAssignmentFromProto(resp.GetAssignment()), nil
+}
+
+// RevokeRole removes identity's role. The caller must hold
+// PermissionManageRoles.
+func (c *AccessClient) RevokeRole(ctx context.Context, identity string) error {
+	_, err := c.client.RevokeRole(ctx, &controlplanev1.RevokeRoleRequest{Identity: identity})
+	return err
+}
+
+// ListRoleAssignments returns every role assignment. The caller must hold
+// PermissionManageRoles.
+func (c *AccessClient) ListRoleAssignments(ctx context.Context) ([]*RoleAssignment, error) {
+	resp, err := c.client.ListRoleAssignments(ctx, &controlplanev1.ListRoleAssignmentsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	assignments := make([]*RoleAssignment, len(resp.GetAssignments()))
+	for i, a := range resp.GetAssignments() {
+		assignments[i] = roleAssignmentFromProto(a)
+	}
+	return assignments, nil
+}
+
+// CheckPermission reports whether identity's role grants p.
+func (c *AccessClient) CheckPermission(ctx context.Context, identity string, p Permission) (*PermissionDecision, error) {
+	resp, err := c.client.CheckPermission(ctx, &controlplanev1.CheckPermissionRequest{Identity: identity, Permission: string(p)})
+	if err != nil {
+		return nil, err
+	}
+	return &PermissionDecision{Allowed: resp.GetAllowed(), Role: Role(resp.GetRole())}, nil
+}
+
+// Authorize returns ErrPermissionDenied unless the control plane grants
+// identity p.
+func (c *AccessClient) Authorize(ctx context.Context, identity string, p Permission) error {
+	if identity == "" {
+		return fmt.Errorf("%w: no identity", ErrPermissionDenied)
+	}
+	decision, err := c.CheckPermission(ctx, identity, p)
+	if err != nil {
+		return fmt.Errorf("failed to check permission: %w", err)
+	}
+	if !decision.Allowed {
+		return fmt.Errorf("%w: %s lacks %s", ErrPermissionDenied, identity, p)
+	}
+	return nil
+}
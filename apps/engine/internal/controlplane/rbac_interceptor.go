@@ -0,0 +1,150 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	controlplanev1 "github.com/linkflow/engine/api/gen/linkflow/controlplane/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
+)
+
+// AdminMethodPermissions maps the admin RPCs of every service to the
+// permission needed to call them. Methods not listed are not authorized by
+// an RBACInterceptor.
+var AdminMethodPermissions = map[string]Permission{
+	historyv1.HistoryService_ForceTerminateExecution_FullMethodName: PermissionForceTerminate,
+	historyv1.HistoryService_DeleteWorkflowExecution_FullMethodName: PermissionDeleteExecution,
+	historyv1.HistoryService_RebalanceShards_FullMethodName:         PermissionRebalanceShards,
+	matchingv1.MatchingService_PurgeDLQ_FullMethodName:              PermissionPurgeDLQ,
+
+	controlplanev1.ConfigService_ListConfigVersions_FullMethodName: PermissionViewAdmin,
+	controlplanev1.ConfigService_GetConfigVersion_FullMethodName:   PermissionViewAdmin,
+	controlplanev1.ConfigService_DiffConfigVersions_FullMethodName: PermissionViewAdmin,
+	controlplanev1.ConfigService_RollbackConfig_FullMethodName:     PermissionRollbackConfig,
+
+	controlplanev1.AccessService_AssignRole_FullMethodName:          PermissionManageRoles,
+	controlplanev1.AccessService_RevokeRole_FullMethodName:          PermissionManageRoles,
+	controlplanev1.AccessService_ListRoleAssignments_FullMethodName: PermissionManageRoles,
+}
+
+// Authorizer decides whether an identity holds a permission. It returns an
+// error wrapping ErrPermissionDenied when it does not.
+type Authorizer interface {
+	Authorize(ctx context.Context, identity string, p Permission) error
+}
+
+// TokenValidator validates bearer tokens. *interceptor.AuthInterceptor
+// implements it.
+type TokenValidator interface {
+	ValidateToken(token string) (*interceptor.Claims, error)
+}
+
+// RBACInterceptor authorizes calls to admin RPCs against the caller's role.
+// The caller is the subject of the claims an auth interceptor put in the
+// context, or, on servers without one, of the request's bearer token. Calls
+// to other methods pass through untouched, so internal traffic needs no
+// token.
+type RBACInterceptor struct {
+	authorizer Authorizer
+	validator  TokenValidator
+	methods    map[string]Permission
+	logger     *slog.Logger
+}
+
+// NewRBACInterceptor creates an interceptor requiring methods' permissions.
+// validator may be nil when an auth interceptor runs first.
+func NewRBACInterceptor(authorizer Authorizer, validator TokenValidator, methods map[string]Permission, logger *slog.Logger) *RBACInterceptor {
+	return &RBACInterceptor{
+		authorizer: authorizer,
+		validator:  validator,
+		methods:    methods,
+		logger:     logger,
+	}
+}
+
+func (i *RBACInterceptor) UnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	permission, ok := i.methods[info.FullMethod]
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	claims, err := i.claims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	identity := ClaimsIdentity(claims)
+
+	if err := i.authorizer.Authorize(ctx, identity, permission); err != nil {
+		if errors.Is(err, ErrPermissionDenied) {
+			i.logger.Warn("admin call denied",
+				slog.String("method", info.FullMethod),
+				slog.String("identity", identity),
+				slog.String("permission", string(permission)),
+			)
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		i.logger.Error("admin call authorization failed",
+			slog.String("method", info.FullMethod),
+			slog.String("identity", identity),
+			slog.String("error", err.Error()),
+		)
+		return nil, status.Error(codes.Unavailable, "authorization unavailable")
+	}
+
+	return handler(interceptor.ContextWithClaims(ctx, claims), req)
+}
+
+func (i *RBACInterceptor) claims(ctx context.Context) (*interceptor.Claims, error) {
+	if claims, ok := interceptor.ClaimsFromContext(ctx); ok {
+		return claims, nil
+	}
+	if i.validator == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing credentials")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	authHeaders := md.Get("authorization")
+	if len(authHeaders) == 0 || !strings.HasPrefix(authHeaders[0], "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	claims, err := i.validator.ValidateToken(strings.TrimPrefix(authHeaders[0], "Bearer "))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return claims, nil
+}
+
+// ClaimsIdentity returns the identity roles are assigned to: the token's
+// subject, or its user ID when it has no subject.
+func ClaimsIdentity(claims *interceptor.Claims) string {
+	if claims.Subject != "" {
+		return claims.Subject
+	}
+	return claims.UserID
+}
+
+// withCallerAuthor attributes control plane changes made in ctx to the
+// authenticated caller. Changes by unauthenticated callers are attributed to
+// no one rather than to an identity the caller claims.
+func withCallerAuthor(ctx context.Context) context.Context {
+	if claims, ok := interceptor.ClaimsFromContext(ctx); ok {
+		if identity := ClaimsIdentity(claims); identity != "" {
+			return WithConfigAuthor(ctx, identity)
+		}
+	}
+	return ctx
+}
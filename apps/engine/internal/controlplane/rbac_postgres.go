@@ -0,0 +1,83 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRoleStore implements RoleStore on the role_assignments table (see
+// scripts/migrations), so every control plane replica authorizes alike.
+type PostgresRoleStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresRoleStore(pool *pgxpool.Pool) *PostgresRoleStore {
+	return &PostgresRoleStore{pool: pool}
+}
+
+var _ RoleStore = (*PostgresRoleStore)(nil)
+
+func (s *PostgresRoleStore) GetRoleAssignment(ctx context.Context, identity string) (*RoleAssignment, error) {
+	var a RoleAssignment
+	err := s.pool.QueryRow(ctx, `
+		SELECT identity, role, assigned_by, assigned_at FROM role_assignments WHERE identity = $1
+	`, identity).Scan(&a.Identity, &a.Role, &a.AssignedBy, &a.AssignedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRoleAssignmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role assignment: %w", err)
+	}
+	return &a, nil
+}
+
+func (s *PostgresRoleStore) ListRoleAssignments(ctx context.Context) ([]*RoleAssignment, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT identity, role, assigned_by, assigned_at FROM role_assignments ORDER BY identity
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []*RoleAssignment{}
+	for rows.Next() {
+		var a RoleAssignment
+		if err := rows.Scan(&a.Identity, &a.Role, &a.AssignedBy, &a.AssignedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan role assignment: %w", err)
+		}
+		assignments = append(assignments, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list role assignments: %w", err)
+	}
+	return assignments, nil
+}
+
+func (s *PostgresRoleStore) PutRoleAssignment(ctx context.Context, a *RoleAssignment) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO role_assignments (identity, role, assigned_by, assigned_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (identity) DO UPDATE
+		SET role = EXCLUDED.role, assigned_by = EXCLUDED.assigned_by, assigned_at = EXCLUDED.assigned_at
+	`, a.Identity, a.Role, a.AssignedBy, a.AssignedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save role assignment: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresRoleStore) DeleteRoleAssignment(ctx context.Context, identity string) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM role_assignments WHERE identity = $1`, identity)
+	if err != nil {
+		return fmt.Errorf("failed to delete role assignment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRoleAssignmentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package controlplane
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	controlplanev1 "github.com/linkflow/engine/api/gen/linkflow/controlplane/v1"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
+)
+
+const testJWTSecret = "this-is-a-very-long-secret-key-for-testing-purposes"
+
+func newRBACTestService() *Service {
+	return NewService(Config{
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		BootstrapAdmins: []string{"root"},
+	})
+}
+
+func TestRolesAuthorizeAdminPermissions(t *testing.T) {
+	svc := newRBACTestService()
+	ctx := WithConfigAuthor(context.Background(), "root")
+
+	if _, err := svc.AssignRole(ctx, "olive", RoleOperator); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	if _, err := svc.AssignRole(ctx, "vic", RoleViewer); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	for _, tc := range []struct {
+		identity   string
+		permission Permission
+		allowed    bool
+	}{
+		{"root", PermissionManageRoles, true},
+		{"olive", PermissionForceTerminate, true},
+		{"olive", PermissionPurgeDLQ, true},
+		{"olive", PermissionRollbackConfig, false},
+		{"olive", PermissionManageRoles, false},
+		{"vic", PermissionViewAdmin, true},
+		{"vic", PermissionRebalanceShards, false},
+		{"nobody", PermissionViewAdmin, false},
+		{"", PermissionViewAdmin, false},
+	} {
+		err := svc.Authorize(ctx, tc.identity, tc.permission)
+		if tc.allowed && err != nil {
+			t.Errorf("Authorize(%q, %s) = %v, want allowed", tc.identity, tc.permission, err)
+		}
+		if !tc.allowed && !errors.Is(err, ErrPermissionDenied) {
+			t.Errorf("Authorize(%q, %s) = %v, want ErrPermissionDenied", tc.identity, tc.permission, err)
+		}
+	}
+
+	if err := svc.RevokeRole(ctx, "olive"); err != nil {
+		t.Fatalf("RevokeRole: %v", err)
+	}
+	if err := svc.Authorize(ctx, "olive", PermissionForceTerminate); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("revoked operator still authorized: %v", err)
+	}
+	if err := svc.RevokeRole(ctx, "olive"); !errors.Is(err, ErrRoleAssignmentNotFound) {
+		t.Errorf("second revoke = %v, want ErrRoleAssignmentNotFound", err)
+	}
+
+	// Bootstrap admins cannot be locked out, and roles must be known.
+	if err := svc.RevokeRole(ctx, "root"); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("revoking a bootstrap admin = %v, want ErrInvalidRole", err)
+	}
+	if _, err := svc.AssignRole(ctx, "vic", Role("owner")); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("unknown role = %v, want ErrInvalidRole", err)
+	}
+
+	assignments, err := svc.ListRoleAssignments(ctx)
+	if err != nil {
+		t.Fatalf("ListRoleAssignments: %v", err)
+	}
+	if len(assignments) != 2 || assignments[0].Identity != "root" || assignments[1].Identity != "vic" {
+		t.Fatalf("assignments = %+v, want root and vic", assignments)
+	}
+	if assignments[1].AssignedBy != "root" {
+		t.Errorf("assigned by %q, want root", assignments[1].AssignedBy)
+	}
+}
+
+func TestRBACInterceptorGuardsAdminRPCs(t *testing.T) {
+	ctx := context.Background()
+	svc := newRBACTestService()
+	validator, err := interceptor.NewAuthInterceptor(interceptor.AuthConfig{SecretKey: testJWTSecret})
+	if err != nil {
+		t.Fatalf("NewAuthInterceptor: %v", err)
+	}
+	rbac := NewRBACInterceptor(svc, validator, AdminMethodPermissions, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(rbac.UnaryInterceptor))
+	controlplanev1.RegisterConfigServiceServer(server, NewGRPCServer(svc))
+	controlplanev1.RegisterAccessServiceServer(server, NewAccessGRPCServer(svc))
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := NewAccessClient(conn)
+	as := func(subject string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+signTestToken(t, subject))
+	}
+
+	if _, err := client.AssignRole(ctx, "vic", RoleViewer); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unauthenticated assign = %v, want Unauthenticated", err)
+	}
+	if _, err := client.AssignRole(as("root"), "vic", RoleViewer); err != nil {
+		t.Fatalf("admin assign: %v", err)
+	}
+	if _, err := client.AssignRole(as("vic"), "vic", RoleAdmin); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("viewer assign = %v, want PermissionDenied", err)
+	}
+
+	// A viewer may read config history but not roll it back.
+	configClient := controlplanev1.NewConfigServiceClient(conn)
+	if _, err := configClient.ListConfigVersions(as("vic"), &controlplanev1.ListConfigVersionsRequest{Key: "rate_limits"}); err != nil {
+		t.Fatalf("viewer list config versions: %v", err)
+	}
+	if _, err := configClient.RollbackConfig(as("vic"), &controlplanev1.RollbackConfigRequest{Key: "rate_limits", Version: 1}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("viewer rollback = %v, want PermissionDenied", err)
+	}
+
+	// Rollbacks are attributed to the authenticated caller, not to an
+	// identity the caller claims in metadata.
+	for _, rps := range []int{10, 20} {
+		value, _ := json.Marshal(RateLimitConfig{RequestsPerSecond: rps, BurstSize: 5})
+		if err := svc.SetConfig(ctx, "rate_limits", value); err != nil {
+			t.Fatalf("SetConfig: %v", err)
+		}
+	}
+	versions := svc.ListConfigVersions(ctx, "rate_limits")
+	claimed := metadata.AppendToOutgoingContext(as("root"), "x-author", "mallory")
+	rollback, err := configClient.RollbackConfig(claimed, &controlplanev1.RollbackConfigRequest{Key: "rate_limits", Version: versions[0].Version})
+	if err != nil {
+		t.Fatalf("admin rollback: %v", err)
+	}
+	if author := rollback.GetVersion().GetAuthor(); author != "root" {
+		t.Fatalf("rollback author = %q, want root", author)
+	}
+
+	// Other services check permissions without a token of their own.
+	if err := client.Authorize(ctx, "vic", PermissionViewAdmin); err != nil {
+		t.Errorf("remote Authorize(vic, view) = %v", err)
+	}
+	if err := client.Authorize(ctx, "vic", PermissionPurgeDLQ); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("remote Authorize(vic, purge) = %v, want ErrPermissionDenied", err)
+	}
+	// ...and read dynamic config the same way.
+	remoteConfig := NewConfigClient(conn)
+	if _, err := remoteConfig.GetConfig(ctx, ConnectorRateLimitsConfigKey); err != nil {
+		t.Errorf("remote GetConfig(%s) = %v", ConnectorRateLimitsConfigKey, err)
+	}
+	if _, err := remoteConfig.GetConfig(ctx, "missing"); !errors.Is(err, ErrConfigKeyNotFound) {
+		t.Errorf("remote GetConfig(missing) = %v, want ErrConfigKeyNotFound", err)
+	}
+
+	assignments, err := client.ListRoleAssignments(as("root"))
+	if err != nil {
+		t.Fatalf("ListRoleAssignments: %v", err)
+	}
+	if len(assignments) != 2 || assignments[1].Identity != "vic" || assignments[1].AssignedBy != "root" {
+		t.Fatalf("assignments = %+v, want vic assigned by root", assignments)
+	}
+}
+
+func signTestToken(t *testing.T, subject string) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	claims, _ := json.Marshal(interceptor.Claims{Subject: subject})
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	mac := hmac.New(sha256.New, []byte(testJWTSecret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -5,15 +5,19 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
-	ErrClusterNotFound   = errors.New("cluster not found")
-	ErrNamespaceExists   = errors.New("namespace already exists")
-	ErrServiceNotFound   = errors.New("service not found")
-	ErrConfigKeyNotFound = errors.New("config key not found")
+	ErrClusterNotFound       = errors.New("cluster not found")
+	ErrNamespaceExists       = errors.New("namespace already exists")
+	ErrNamespaceNotFound     = errors.New("namespace not found")
+	ErrServiceNotFound       = errors.New("service not found")
+	ErrConfigKeyNotFound     = errors.New("config key not found")
+	ErrConfigVersionNotFound = errors.New("config version not found")
 )
 
 type RateLimitConfig struct {
@@ -22,11 +26,75 @@ type RateLimitConfig struct {
 	WindowDuration    time.Duration `json:"window_duration"`
 }
 
+// ConnectorRateLimit is a token bucket for outbound calls to a single provider.
+type ConnectorRateLimit struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	BurstSize         int     `json:"burst_size"`
+}
+
+// ConnectorRateLimitConfig configures per-provider outbound rate limits for
+// worker connectors. Providers are keyed by name ("twilio", "slack", "discord")
+// or "http:<host>" for the generic HTTP executor.
+type ConnectorRateLimitConfig struct {
+	Default   *ConnectorRateLimit           `json:"default,omitempty"`
+	Providers map[string]ConnectorRateLimit `json:"providers,omitempty"`
+	MaxWait   time.Duration                 `json:"max_wait"`
+}
+
+// DefaultConnectorRateLimitConfig returns conservative limits for the built-in providers.
+func DefaultConnectorRateLimitConfig() *ConnectorRateLimitConfig {
+	return &ConnectorRateLimitConfig{
+		Providers: map[string]ConnectorRateLimit{
+			"twilio":  {RequestsPerSecond: 1, BurstSize: 5},
+			"slack":   {RequestsPerSecond: 1, BurstSize: 3},
+			"discord": {RequestsPerSecond: 5, BurstSize: 5},
+		},
+		MaxWait: 2 * time.Second,
+	}
+}
+
+// ConnectorRateLimitsConfigKey is the config key holding the workers'
+// ConnectorRateLimitConfig.
+const ConnectorRateLimitsConfigKey = "connector_rate_limits"
+
+// PartitionWeightsConfigKey is the config key holding the matching service's
+// PartitionWeightConfig.
+const PartitionWeightsConfigKey = "matching_partition_weights"
+
+// PartitionWeightConfig weights the matching service's task queue partitions
+// so partitions served by bigger worker pools receive proportionally more
+// task queues. Partitions without a weight get 1.
+type PartitionWeightConfig struct {
+	Weights map[int32]int `json:"weights"`
+}
+
+// SignalRateLimitsConfigKey is the config key holding the history service's
+// SignalRateLimitConfig.
+const SignalRateLimitsConfigKey = "history_signal_rate_limits"
+
+// SignalRateLimit is a token bucket for the signals sent to one execution.
+// A zero rate means signals are not limited.
+type SignalRateLimit struct {
+	SignalsPerSecond float64 `json:"signals_per_second"`
+	BurstSize        int     `json:"burst_size"`
+}
+
+// SignalRateLimitConfig limits how fast each execution accepts signals.
+// Namespaces without an entry use Default.
+type SignalRateLimitConfig struct {
+	Default    SignalRateLimit            `json:"default"`
+	Namespaces map[string]SignalRateLimit `json:"namespaces,omitempty"`
+}
+
 type FeatureFlags struct {
 	EnableBetaFeatures     bool `json:"enable_beta_features"`
 	EnableMetrics          bool `json:"enable_metrics"`
 	EnableTracing          bool `json:"enable_tracing"`
 	EnableCrossClusterSync bool `json:"enable_cross_cluster_sync"`
+
+	// EnableDNSDiscovery lets ResolveService fall back to DNS SRV records
+	// for services without healthy registered instances.
+	EnableDNSDiscovery bool `json:"enable_dns_discovery"`
 }
 
 type RetentionPolicy struct {
@@ -36,10 +104,11 @@ type RetentionPolicy struct {
 }
 
 type DynamicConfig struct {
-	RateLimits        *RateLimitConfig           `json:"rate_limits,omitempty"`
-	FeatureFlags      *FeatureFlags              `json:"feature_flags,omitempty"`
-	RetentionPolicies *RetentionPolicy           `json:"retention_policies,omitempty"`
-	Custom            map[string]json.RawMessage `json:"custom,omitempty"`
+	RateLimits          *RateLimitConfig           `json:"rate_limits,omitempty"`
+	ConnectorRateLimits *ConnectorRateLimitConfig  `json:"connector_rate_limits,omitempty"`
+	FeatureFlags        *FeatureFlags              `json:"feature_flags,omitempty"`
+	RetentionPolicies   *RetentionPolicy           `json:"retention_policies,omitempty"`
+	Custom              map[string]json.RawMessage `json:"custom,omitempty"`
 }
 
 type PeerCluster struct {
@@ -110,6 +179,15 @@ type NamespaceConfig struct {
 	DefaultCluster       string
 	SearchAttributes     map[string]SearchAttributeType
 	ArchivalConfig       *ArchivalConfig
+
+	// MaxConcurrentExecutions caps running top-level executions (0 = unlimited).
+	MaxConcurrentExecutions int
+
+	// TaskQueueSoftLimit and TaskQueueHardLimit override matching's per-queue
+	// backpressure limits for the namespace's task queues (0 = matching
+	// default).
+	TaskQueueSoftLimit int
+	TaskQueueHardLimit int
 }
 
 // SearchAttributeType defines the type of a search attribute.
@@ -142,6 +220,10 @@ type ServiceInstance struct {
 	Health    HealthStatus
 	LastCheck time.Time
 	Version   string
+
+	// ActiveConnections is the load the instance reported when it last
+	// registered, used by the least-connections policy.
+	ActiveConnections int
 }
 
 // HealthStatus represents service health.
@@ -161,6 +243,28 @@ type Config struct {
 	Endpoint          string
 	Logger            *slog.Logger
 	ClusterSyncConfig *ClusterSyncConfig
+	MaxConfigVersions int // Versions retained per config key (0 = DefaultMaxConfigVersions)
+
+	// LeaderElector picks the one replica that runs the health checker and
+	// cluster sync. Nil runs them on every replica, for single-replica
+	// deployments.
+	LeaderElector          LeaderElector
+	LeaderElectionInterval time.Duration // 0 = DefaultLeaderElectionInterval
+
+	// LoadBalancingPolicy picks the instance ResolveService returns
+	// ("" = LoadBalancingRoundRobin).
+	LoadBalancingPolicy LoadBalancingPolicy
+
+	// DNSDomain is the domain SRV records are looked up in when DNS
+	// discovery is enabled, as _<service>._tcp.<DNSDomain>.
+	DNSDomain   string
+	SRVResolver SRVResolver // nil = net.DefaultResolver
+
+	// RoleStore holds the role assignments that authorize admin endpoints
+	// (nil = NewMemoryRoleStore()). BootstrapAdmins always hold the admin
+	// role, so roles can be managed before any are assigned.
+	RoleStore       RoleStore
+	BootstrapAdmins []string
 }
 
 // Service is the control plane service.
@@ -173,12 +277,16 @@ type Service struct {
 	services      map[string][]*ServiceInstance
 	dynamicConfig *DynamicConfig
 	configStore   map[string]json.RawMessage
+	configHistory map[string][]*ConfigVersion
 	syncClient    ClusterSyncClient
+	nextInstance  map[string]uint64
+	roles         RoleStore
 
 	mu       sync.RWMutex
 	configMu sync.RWMutex
 	stopCh   chan struct{}
 	running  bool
+	leader   atomic.Bool
 }
 
 // NewService creates a new control plane service.
@@ -193,18 +301,36 @@ func NewService(config Config) *Service {
 			MaxRetries:       3,
 		}
 	}
+	if config.MaxConfigVersions <= 0 {
+		config.MaxConfigVersions = DefaultMaxConfigVersions
+	}
+	if config.LeaderElectionInterval <= 0 {
+		config.LeaderElectionInterval = DefaultLeaderElectionInterval
+	}
+	if config.LoadBalancingPolicy == "" {
+		config.LoadBalancingPolicy = LoadBalancingRoundRobin
+	}
+	if config.SRVResolver == nil {
+		config.SRVResolver = net.DefaultResolver
+	}
+	if config.RoleStore == nil {
+		config.RoleStore = NewMemoryRoleStore()
+	}
 	return &Service{
-		config:     config,
-		logger:     config.Logger,
-		clusters:   make(map[string]*ClusterInfo),
-		namespaces: make(map[string]*NamespaceConfig),
-		services:   make(map[string][]*ServiceInstance),
+		config:       config,
+		logger:       config.Logger,
+		clusters:     make(map[string]*ClusterInfo),
+		namespaces:   make(map[string]*NamespaceConfig),
+		services:     make(map[string][]*ServiceInstance),
+		nextInstance: make(map[string]uint64),
+		roles:        config.RoleStore,
 		dynamicConfig: &DynamicConfig{
 			RateLimits: &RateLimitConfig{
 				RequestsPerSecond: 1000,
 				BurstSize:         100,
 				WindowDuration:    time.Second,
 			},
+			ConnectorRateLimits: DefaultConnectorRateLimitConfig(),
 			FeatureFlags: &FeatureFlags{
 				EnableMetrics:          true,
 				EnableTracing:          true,
@@ -217,8 +343,9 @@ func NewService(config Config) *Service {
 			},
 			Custom: make(map[string]json.RawMessage),
 		},
-		configStore: make(map[string]json.RawMessage),
-		stopCh:      make(chan struct{}),
+		configStore:   make(map[string]json.RawMessage),
+		configHistory: make(map[string][]*ConfigVersion),
+		stopCh:        make(chan struct{}),
 	}
 }
 
@@ -249,9 +376,14 @@ func (s *Service) Start(ctx context.Context) error {
 		Metadata: map[string]string{"role": "primary"},
 	})
 
-	// Start background tasks
-	go s.runHealthChecker(ctx)
-	go s.runClusterSync(ctx)
+	// Background tasks run only on the leader; other replicas keep serving
+	// reads and registrations.
+	if s.config.LeaderElector == nil {
+		s.leader.Store(true)
+		s.startBackgroundTasks(ctx)
+	} else {
+		go s.runLeaderElection(ctx)
+	}
 
 	s.logger.Info("control plane started",
 		slog.String("cluster_id", s.config.ClusterID),
@@ -272,6 +404,10 @@ func (s *Service) Stop(ctx context.Context) error {
 	close(s.stopCh)
 	s.mu.Unlock()
 
+	if s.config.LeaderElector == nil {
+		s.leader.Store(false)
+	}
+
 	s.logger.Info("control plane stopped")
 	return nil
 }
@@ -341,7 +477,7 @@ func (s *Service) GetNamespace(ctx context.Context, name string) (*NamespaceConf
 
 	ns, exists := s.namespaces[name]
 	if !exists {
-		return nil, errors.New("namespace not found")
+		return nil, ErrNamespaceNotFound
 	}
 	return ns, nil
 }
@@ -352,7 +488,7 @@ func (s *Service) UpdateNamespace(ctx context.Context, ns *NamespaceConfig) erro
 	defer s.mu.Unlock()
 
 	if _, exists := s.namespaces[ns.Name]; !exists {
-		return errors.New("namespace not found")
+		return ErrNamespaceNotFound
 	}
 
 	s.namespaces[ns.Name] = ns
@@ -483,12 +619,21 @@ func (s *Service) GetConfig(ctx context.Context, key string) (json.RawMessage, e
 	s.configMu.RLock()
 	defer s.configMu.RUnlock()
 
+	return s.getConfigLocked(key)
+}
+
+func (s *Service) getConfigLocked(key string) (json.RawMessage, error) {
 	switch key {
 	case "rate_limits":
 		if s.dynamicConfig.RateLimits == nil {
 			return nil, ErrConfigKeyNotFound
 		}
 		return json.Marshal(s.dynamicConfig.RateLimits)
+	case ConnectorRateLimitsConfigKey:
+		if s.dynamicConfig.ConnectorRateLimits == nil {
+			return nil, ErrConfigKeyNotFound
+		}
+		return json.Marshal(s.dynamicConfig.ConnectorRateLimits)
 	case "feature_flags":
 		if s.dynamicConfig.FeatureFlags == nil {
 			return nil, ErrConfigKeyNotFound
@@ -512,11 +657,17 @@ func (s *Service) GetConfig(ctx context.Context, key string) (json.RawMessage, e
 	}
 }
 
-// SetConfig sets configuration for the specified key.
+// SetConfig sets configuration for the specified key and records the change
+// as a new version attributed to the author in ctx.
 func (s *Service) SetConfig(ctx context.Context, key string, value json.RawMessage) error {
 	s.configMu.Lock()
 	defer s.configMu.Unlock()
 
+	_, err := s.updateConfigLocked(ctx, key, value, 0)
+	return err
+}
+
+func (s *Service) applyConfigLocked(key string, value json.RawMessage) error {
 	switch key {
 	case "rate_limits":
 		var cfg RateLimitConfig
@@ -524,6 +675,12 @@ func (s *Service) SetConfig(ctx context.Context, key string, value json.RawMessa
 			return err
 		}
 		s.dynamicConfig.RateLimits = &cfg
+	case ConnectorRateLimitsConfigKey:
+		var cfg ConnectorRateLimitConfig
+		if err := json.Unmarshal(value, &cfg); err != nil {
+			return err
+		}
+		s.dynamicConfig.ConnectorRateLimits = &cfg
 	case "feature_flags":
 		var cfg FeatureFlags
 		if err := json.Unmarshal(value, &cfg); err != nil {
@@ -543,11 +700,6 @@ func (s *Service) SetConfig(ctx context.Context, key string, value json.RawMessa
 		}
 		s.dynamicConfig.Custom[key] = value
 	}
-
-	s.logger.Info("config updated",
-		slog.String("key", key),
-	)
-
 	return nil
 }
 
@@ -557,7 +709,7 @@ func (s *Service) DeleteConfig(ctx context.Context, key string) error {
 	defer s.configMu.Unlock()
 
 	switch key {
-	case "rate_limits", "feature_flags", "retention_policies":
+	case "rate_limits", "connector_rate_limits", "feature_flags", "retention_policies":
 		return errors.New("cannot delete built-in config keys")
 	default:
 		delete(s.configStore, key)
@@ -585,6 +737,14 @@ func (s *Service) GetAllConfig(ctx context.Context) (*DynamicConfig, error) {
 		cfg := *s.dynamicConfig.RateLimits
 		configCopy.RateLimits = &cfg
 	}
+	if s.dynamicConfig.ConnectorRateLimits != nil {
+		cfg := *s.dynamicConfig.ConnectorRateLimits
+		cfg.Providers = make(map[string]ConnectorRateLimit, len(s.dynamicConfig.ConnectorRateLimits.Providers))
+		for k, v := range s.dynamicConfig.ConnectorRateLimits.Providers {
+			cfg.Providers[k] = v
+		}
+		configCopy.ConnectorRateLimits = &cfg
+	}
 	if s.dynamicConfig.FeatureFlags != nil {
 		cfg := *s.dynamicConfig.FeatureFlags
 		configCopy.FeatureFlags = &cfg
@@ -605,13 +765,18 @@ func (s *Service) ListConfigKeys(ctx context.Context) []string {
 	s.configMu.RLock()
 	defer s.configMu.RUnlock()
 
-	keys := []string{"rate_limits", "feature_flags", "retention_policies"}
+	keys := []string{"rate_limits", "connector_rate_limits", "feature_flags", "retention_policies"}
 	for k := range s.configStore {
 		keys = append(keys, k)
 	}
 	return keys
 }
 
+func (s *Service) startBackgroundTasks(ctx context.Context) {
+	go s.runHealthChecker(ctx)
+	go s.runClusterSync(ctx)
+}
+
 func (s *Service) runHealthChecker(ctx context.Context) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
@@ -0,0 +1,133 @@
+package timeline
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+)
+
+// Node statuses.
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusTimedOut  = "timed_out"
+)
+
+// Node is one workflow node as seen in an execution's history. A node that
+// was scheduled more than once reports its latest attempt. StartedAt is when
+// a worker last began executing it, or ScheduledAt when no start was
+// recorded.
+type Node struct {
+	NodeID      string
+	NodeType    string
+	Name        string
+	Status      string
+	Sequence    int
+	Attempts    int
+	ScheduledAt time.Time
+	StartedAt   time.Time
+	CompletedAt *time.Time
+	Output      json.RawMessage
+	Error       string
+}
+
+// Build reconstructs the per-node timeline of an execution from its history
+// events, ordered by when each node was first scheduled. Nodes still running
+// have no CompletedAt.
+func Build(events []*historyv1.HistoryEvent) []*Node {
+	byScheduledEventID := make(map[int64]*Node)
+	byNodeID := make(map[string]*Node)
+
+	sequence := 0
+	for _, event := range events {
+		switch event.GetEventType() {
+		case commonv1.EventType_EVENT_TYPE_NODE_SCHEDULED:
+			attr := event.GetNodeScheduledAttributes()
+			if attr == nil {
+				continue
+			}
+
+			node, ok := byNodeID[attr.GetNodeId()]
+			if !ok {
+				sequence++
+				node = &Node{NodeID: attr.GetNodeId(), Sequence: sequence}
+				byNodeID[attr.GetNodeId()] = node
+			}
+			node.NodeType = attr.GetNodeType()
+			node.Name = attr.GetName()
+			node.Status = StatusRunning
+			node.Attempts++
+			node.ScheduledAt = event.GetEventTime().AsTime().UTC()
+			node.StartedAt = node.ScheduledAt
+			node.CompletedAt = nil
+			node.Output = nil
+			node.Error = ""
+			byScheduledEventID[event.GetEventId()] = node
+
+		case commonv1.EventType_EVENT_TYPE_NODE_STARTED:
+			attr := event.GetNodeStartedAttributes()
+			node, ok := byScheduledEventID[attr.GetScheduledEventId()]
+			if !ok {
+				continue
+			}
+			node.StartedAt = event.GetEventTime().AsTime().UTC()
+			if int(attr.GetAttempt()) > node.Attempts {
+				node.Attempts = int(attr.GetAttempt())
+			}
+
+		case commonv1.EventType_EVENT_TYPE_NODE_COMPLETED:
+			attr := event.GetNodeCompletedAttributes()
+			node, ok := byScheduledEventID[attr.GetScheduledEventId()]
+			if !ok {
+				continue
+			}
+			closeNode(node, StatusCompleted, event)
+			if payloads := attr.GetResult().GetPayloads(); len(payloads) > 0 {
+				node.Output = json.RawMessage(payloads[0].GetData())
+			}
+
+		case commonv1.EventType_EVENT_TYPE_NODE_FAILED:
+			attr := event.GetNodeFailedAttributes()
+			node, ok := byScheduledEventID[attr.GetScheduledEventId()]
+			if !ok {
+				continue
+			}
+			closeNode(node, StatusFailed, event)
+			node.Error = "node execution failed"
+			if msg := attr.GetFailure().GetMessage(); msg != "" {
+				node.Error = msg
+			}
+
+		case commonv1.EventType_EVENT_TYPE_NODE_TIMED_OUT:
+			attr := event.GetNodeTimedOutAttributes()
+			node, ok := byScheduledEventID[attr.GetScheduledEventId()]
+			if !ok {
+				continue
+			}
+			closeNode(node, StatusTimedOut, event)
+			node.Error = "node execution timed out"
+			if msg := attr.GetFailure().GetMessage(); msg != "" {
+				node.Error = msg
+			}
+		}
+	}
+
+	nodes := make([]*Node, 0, len(byNodeID))
+	for _, node := range byNodeID {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Sequence < nodes[j].Sequence
+	})
+	return nodes
+}
+
+func closeNode(node *Node, status string, event *historyv1.HistoryEvent) {
+	completedAt := event.GetEventTime().AsTime().UTC()
+	node.Status = status
+	node.CompletedAt = &completedAt
+}
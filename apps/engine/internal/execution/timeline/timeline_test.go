@@ -0,0 +1,82 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func scheduled(eventID int64, nodeID string, at time.Time) *historyv1.HistoryEvent {
+	return &historyv1.HistoryEvent{
+		EventId:   eventID,
+		EventType: commonv1.EventType_EVENT_TYPE_NODE_SCHEDULED,
+		EventTime: timestamppb.New(at),
+		Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+			NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{NodeId: nodeID, NodeType: "http"},
+		},
+	}
+}
+
+func TestBuildTracksAttemptsAndStatus(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []*historyv1.HistoryEvent{
+		scheduled(1, "fetch", base),
+		{
+			EventId:   2,
+			EventType: commonv1.EventType_EVENT_TYPE_NODE_FAILED,
+			EventTime: timestamppb.New(base.Add(time.Second)),
+			Attributes: &historyv1.HistoryEvent_NodeFailedAttributes{
+				NodeFailedAttributes: &historyv1.NodeFailedEventAttributes{
+					ScheduledEventId: 1,
+					Failure:          &commonv1.Failure{Message: "503"},
+				},
+			},
+		},
+		scheduled(3, "fetch", base.Add(2*time.Second)),
+		scheduled(4, "notify", base.Add(2*time.Second)),
+		{
+			EventId:   5,
+			EventType: commonv1.EventType_EVENT_TYPE_NODE_STARTED,
+			EventTime: timestamppb.New(base.Add(2500 * time.Millisecond)),
+			Attributes: &historyv1.HistoryEvent_NodeStartedAttributes{
+				NodeStartedAttributes: &historyv1.NodeStartedEventAttributes{ScheduledEventId: 4, Attempt: 1},
+			},
+		},
+		{
+			EventId:   6,
+			EventType: commonv1.EventType_EVENT_TYPE_NODE_COMPLETED,
+			EventTime: timestamppb.New(base.Add(3 * time.Second)),
+			Attributes: &historyv1.HistoryEvent_NodeCompletedAttributes{
+				NodeCompletedAttributes: &historyv1.NodeCompletedEventAttributes{
+					ScheduledEventId: 3,
+					Result:           &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: []byte(`{"ok":true}`)}}},
+				},
+			},
+		},
+	}
+
+	nodes := Build(events)
+	if len(nodes) != 2 || nodes[0].NodeID != "fetch" || nodes[1].NodeID != "notify" {
+		t.Fatalf("nodes = %+v, want fetch then notify", nodes)
+	}
+
+	fetch := nodes[0]
+	if fetch.Status != StatusCompleted || fetch.Attempts != 2 || fetch.Error != "" || string(fetch.Output) != `{"ok":true}` {
+		t.Fatalf("fetch = %+v, want completed on the second attempt", fetch)
+	}
+	if !fetch.StartedAt.Equal(base.Add(2*time.Second)) || fetch.CompletedAt == nil || !fetch.CompletedAt.Equal(base.Add(3*time.Second)) {
+		t.Fatalf("fetch times = %v - %v, want the second attempt", fetch.StartedAt, fetch.CompletedAt)
+	}
+
+	notify := nodes[1]
+	if notify.Status != StatusRunning || notify.CompletedAt != nil || notify.Attempts != 1 {
+		t.Fatalf("notify = %+v, want running", notify)
+	}
+	// A worker picked notify up half a second after it was scheduled.
+	if !notify.ScheduledAt.Equal(base.Add(2*time.Second)) || !notify.StartedAt.Equal(base.Add(2500*time.Millisecond)) {
+		t.Fatalf("notify times = scheduled %v, started %v", notify.ScheduledAt, notify.StartedAt)
+	}
+}
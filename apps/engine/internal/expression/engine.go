@@ -1,6 +1,7 @@
 package expression
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,26 +9,104 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
 var (
 	ErrInvalidExpression = errors.New("invalid expression")
 	ErrUnsupportedType   = errors.New("unsupported type")
 	ErrPathNotFound      = errors.New("path not found")
+
+	// ErrEvaluationLimitExceeded is wrapped by every LimitError.
+	ErrEvaluationLimitExceeded = errors.New("evaluation_limit_exceeded")
+)
+
+const (
+	DefaultMaxDepth        = 64
+	DefaultMaxOutputLength = 1 << 20 // 1 MiB
+	DefaultTimeout         = time.Second
+)
+
+// Names of the limits a LimitError reports.
+const (
+	LimitDepth        = "depth"
+	LimitOutputLength = "output_length"
+	LimitTimeout      = "timeout"
 )
 
+// Limits bound the work a single evaluation may do, since expressions come
+// from user-supplied workflow definitions. Zero fields use the defaults.
+type Limits struct {
+	// MaxDepth is how deeply expressions may nest, e.g. through AND/OR
+	// chains, operands, filters and collection functions. Each segment of a
+	// path counts as one more level.
+	MaxDepth int
+	// MaxOutputLength is the longest string, in bytes, a template or string
+	// concatenation may produce.
+	MaxOutputLength int
+	// Timeout bounds each Evaluate call. EvaluateContext is additionally
+	// bounded by its context.
+	Timeout time.Duration
+}
+
+// LimitError is returned when an evaluation exceeds one of the engine's
+// Limits.
+type LimitError struct {
+	Limit string // LimitDepth, LimitOutputLength or LimitTimeout
+	Max   int64  // the configured limit; nanoseconds for LimitTimeout
+}
+
+func (e *LimitError) Error() string {
+	if e.Limit == LimitTimeout {
+		return fmt.Sprintf("%s: evaluation took longer than %s", ErrEvaluationLimitExceeded, time.Duration(e.Max))
+	}
+	return fmt.Sprintf("%s: %s exceeds %d", ErrEvaluationLimitExceeded, e.Limit, e.Max)
+}
+
+func (e *LimitError) Unwrap() error { return ErrEvaluationLimitExceeded }
+
 // Engine evaluates expressions against data.
 type Engine struct {
 	functions map[string]Function
+	// iterators are the builtins that evaluate a sub-expression per item,
+	// which need the limits of the evaluation calling them.
+	iterators map[string]iterator
+	limits    Limits
 }
 
 // Function represents a custom function.
 type Function func(args ...interface{}) (interface{}, error)
 
-// NewEngine creates a new expression engine.
+type iterator func(ev *evaluation, args ...interface{}) (interface{}, error)
+
+// evaluation tracks one Evaluate call against the engine's limits.
+type evaluation struct {
+	ctx   context.Context
+	depth int
+}
+
+// NewEngine creates a new expression engine with the default limits.
 func NewEngine() *Engine {
+	return NewEngineWithLimits(Limits{})
+}
+
+// NewEngineWithLimits creates a new expression engine with custom limits.
+func NewEngineWithLimits(limits Limits) *Engine {
+	if limits.MaxDepth <= 0 {
+		limits.MaxDepth = DefaultMaxDepth
+	}
+	if limits.MaxOutputLength <= 0 {
+		limits.MaxOutputLength = DefaultMaxOutputLength
+	}
+	if limits.Timeout <= 0 {
+		limits.Timeout = DefaultTimeout
+	}
+
 	e := &Engine{
 		functions: make(map[string]Function),
+		iterators: make(map[string]iterator),
+		limits:    limits,
 	}
 	e.registerBuiltins()
 	return e
@@ -40,34 +119,94 @@ func (e *Engine) RegisterFunction(name string, fn Function) {
 
 // Evaluate evaluates an expression against data.
 func (e *Engine) Evaluate(expr string, data interface{}) (interface{}, error) {
+	return e.EvaluateContext(context.Background(), expr, data)
+}
+
+// EvaluateContext evaluates an expression against data, giving up when ctx
+// ends or the engine's timeout passes.
+func (e *Engine) EvaluateContext(ctx context.Context, expr string, data interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.limits.Timeout)
+	defer cancel()
+	return e.evaluate(&evaluation{ctx: ctx}, expr, data)
+}
+
+// evaluate is the recursive step of Evaluate; every nested expression goes
+// through it, so it is where depth and the deadline are checked.
+func (e *Engine) evaluate(ev *evaluation, expr string, data interface{}) (interface{}, error) {
+	if err := e.check(ev); err != nil {
+		return nil, err
+	}
+	ev.depth++
+	defer func() { ev.depth-- }()
+	if ev.depth > e.limits.MaxDepth {
+		return nil, &LimitError{Limit: LimitDepth, Max: int64(e.limits.MaxDepth)}
+	}
+
 	expr = strings.TrimSpace(expr)
 	if expr == "" {
 		return nil, ErrInvalidExpression
 	}
 
 	// Handle different expression types
-	if strings.HasPrefix(expr, "$.") || strings.HasPrefix(expr, "$[") {
+	if expr == "$" || strings.HasPrefix(expr, "$.") || strings.HasPrefix(expr, "$[") {
 		// JSONPath expression
-		return e.evaluateJSONPath(expr, data)
+		return e.evaluateJSONPath(ev, expr, data)
 	}
 
 	if strings.Contains(expr, "{{") && strings.Contains(expr, "}}") {
 		// Template expression
-		return e.evaluateTemplate(expr, data)
+		return e.evaluateTemplate(ev, expr, data)
+	}
+
+	// Function call, e.g. map($.items, '@.price')
+	if name, args, ok := parseFunctionCall(expr); ok {
+		return e.evaluateFunctionCall(ev, name, args, data)
 	}
 
 	// Check if it's a comparison or logical expression
 	if containsOperator(expr) {
-		return e.evaluateComparison(expr, data)
+		return e.evaluateComparison(ev, expr, data)
 	}
 
 	// Simple path expression
-	return e.evaluatePath(expr, data)
+	return e.evaluatePath(ev, expr, data)
+}
+
+// check reports a LimitError once the evaluation's deadline has passed, or
+// the context's error when it was canceled.
+func (e *Engine) check(ev *evaluation) error {
+	switch err := ev.ctx.Err(); {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &LimitError{Limit: LimitTimeout, Max: int64(e.limits.Timeout)}
+	case err != nil:
+		return err
+	}
+	return nil
+}
+
+// checkOutput reports a LimitError when s is longer than the engine allows
+// produced strings to be.
+func (e *Engine) checkOutput(s string) error {
+	if len(s) > e.limits.MaxOutputLength {
+		return &LimitError{Limit: LimitOutputLength, Max: int64(e.limits.MaxOutputLength)}
+	}
+	return nil
 }
 
 // EvaluateBool evaluates an expression and returns a boolean.
 func (e *Engine) EvaluateBool(expr string, data interface{}) (bool, error) {
-	result, err := e.Evaluate(expr, data)
+	return e.EvaluateBoolContext(context.Background(), expr, data)
+}
+
+// EvaluateBoolContext is EvaluateBool bounded by ctx like EvaluateContext.
+func (e *Engine) EvaluateBoolContext(ctx context.Context, expr string, data interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.limits.Timeout)
+	defer cancel()
+	return e.evaluateBool(&evaluation{ctx: ctx}, expr, data)
+}
+
+func (e *Engine) evaluateBool(ev *evaluation, expr string, data interface{}) (bool, error) {
+	result, err := e.evaluate(ev, expr, data)
 	if err != nil {
 		return false, err
 	}
@@ -89,26 +228,30 @@ func (e *Engine) EvaluateBool(expr string, data interface{}) (bool, error) {
 }
 
 // evaluateJSONPath evaluates a JSONPath expression.
-func (e *Engine) evaluateJSONPath(expr string, data interface{}) (interface{}, error) {
+func (e *Engine) evaluateJSONPath(ev *evaluation, expr string, data interface{}) (interface{}, error) {
 	// Remove leading $
 	path := strings.TrimPrefix(expr, "$")
 
-	return e.resolvePath(path, data)
+	return e.resolvePath(ev, path, data)
 }
 
 // evaluatePath evaluates a simple path expression.
-func (e *Engine) evaluatePath(expr string, data interface{}) (interface{}, error) {
-	return e.resolvePath("."+expr, data)
+func (e *Engine) evaluatePath(ev *evaluation, expr string, data interface{}) (interface{}, error) {
+	return e.resolvePath(ev, "."+expr, data)
 }
 
-// resolvePath resolves a path in the data.
-func (e *Engine) resolvePath(path string, data interface{}) (interface{}, error) {
+// resolvePath resolves a path in the data. Each segment counts towards the
+// depth limit.
+func (e *Engine) resolvePath(ev *evaluation, path string, data interface{}) (interface{}, error) {
 	if path == "" || path == "." {
 		return data, nil
 	}
 
 	current := data
 	parts := parsePath(path)
+	if ev.depth+len(parts) > e.limits.MaxDepth {
+		return nil, &LimitError{Limit: LimitDepth, Max: int64(e.limits.MaxDepth)}
+	}
 
 	for _, part := range parts {
 		if part == "" {
@@ -116,7 +259,7 @@ func (e *Engine) resolvePath(path string, data interface{}) (interface{}, error)
 		}
 
 		var err error
-		current, err = e.resolvePathPart(current, part)
+		current, err = e.resolvePathPart(ev, current, part)
 		if err != nil {
 			return nil, err
 		}
@@ -125,7 +268,7 @@ func (e *Engine) resolvePath(path string, data interface{}) (interface{}, error)
 	return current, nil
 }
 
-func (e *Engine) resolvePathPart(data interface{}, part string) (interface{}, error) {
+func (e *Engine) resolvePathPart(ev *evaluation, data interface{}, part string) (interface{}, error) {
 	// Handle array index
 	if strings.HasPrefix(part, "[") && strings.HasSuffix(part, "]") {
 		indexStr := part[1 : len(part)-1]
@@ -137,7 +280,7 @@ func (e *Engine) resolvePathPart(data interface{}, part string) (interface{}, er
 
 		// Handle filters
 		if strings.HasPrefix(indexStr, "?") {
-			return e.applyFilter(data, indexStr[1:])
+			return e.applyFilter(ev, data, indexStr[1:])
 		}
 
 		// Handle numeric index
@@ -146,7 +289,7 @@ func (e *Engine) resolvePathPart(data interface{}, part string) (interface{}, er
 			// Try as a quoted string key
 			if strings.HasPrefix(indexStr, "'") && strings.HasSuffix(indexStr, "'") {
 				key := indexStr[1 : len(indexStr)-1]
-				return e.resolvePathPart(data, key)
+				return e.resolvePathPart(ev, data, key)
 			}
 			return nil, fmt.Errorf("invalid index: %s", indexStr)
 		}
@@ -210,7 +353,7 @@ func (e *Engine) resolvePathPart(data interface{}, part string) (interface{}, er
 	}
 }
 
-func (e *Engine) applyFilter(data interface{}, filter string) (interface{}, error) {
+func (e *Engine) applyFilter(ev *evaluation, data interface{}, filter string) (interface{}, error) {
 	arr, ok := data.([]interface{})
 	if !ok {
 		return nil, fmt.Errorf("filter can only be applied to arrays")
@@ -223,7 +366,10 @@ func (e *Engine) applyFilter(data interface{}, filter string) (interface{}, erro
 
 	var results []interface{}
 	for _, item := range arr {
-		match, err := e.evaluateFilterCondition(filter, item)
+		match, err := e.evaluateFilterCondition(ev, filter, item)
+		if errors.Is(err, ErrEvaluationLimitExceeded) {
+			return nil, err
+		}
 		if err != nil {
 			continue
 		}
@@ -235,10 +381,8 @@ func (e *Engine) applyFilter(data interface{}, filter string) (interface{}, erro
 	return results, nil
 }
 
-func (e *Engine) evaluateFilterCondition(condition string, data interface{}) (bool, error) {
-	// Replace @ with the actual data reference
-	condition = strings.ReplaceAll(condition, "@", "$")
-	result, err := e.Evaluate(condition, data)
+func (e *Engine) evaluateFilterCondition(ev *evaluation, condition string, data interface{}) (bool, error) {
+	result, err := e.evaluate(ev, substituteItemRef(condition, ""), data)
 	if err != nil {
 		return false, err
 	}
@@ -247,8 +391,20 @@ func (e *Engine) evaluateFilterCondition(condition string, data interface{}) (bo
 	return ok && b, nil
 }
 
-// evaluateTemplate evaluates a template expression.
-func (e *Engine) evaluateTemplate(template string, data interface{}) (interface{}, error) {
+// substituteItemRef replaces the @ item reference with a path to the item,
+// or with the root when path is empty. Fields of the root item become simple
+// paths, so "@.price > 10" can be told apart from a JSONPath.
+func substituteItemRef(expr, path string) string {
+	if path == "" {
+		expr = strings.ReplaceAll(expr, "@.", "")
+		return strings.ReplaceAll(expr, "@", "$")
+	}
+	return strings.ReplaceAll(expr, "@", path)
+}
+
+// evaluateTemplate evaluates a template expression. The rendered string may
+// not grow beyond the output length limit.
+func (e *Engine) evaluateTemplate(ev *evaluation, template string, data interface{}) (interface{}, error) {
 	result := template
 
 	// Find all {{ ... }} patterns
@@ -260,47 +416,60 @@ func (e *Engine) evaluateTemplate(template string, data interface{}) (interface{
 			continue
 		}
 		expr := strings.TrimSpace(match[1])
-		val, err := e.Evaluate(expr, data)
+		val, err := e.evaluate(ev, expr, data)
+		if errors.Is(err, ErrEvaluationLimitExceeded) {
+			return nil, err
+		}
 		if err != nil {
 			val = ""
 		}
-		result = strings.Replace(result, match[0], fmt.Sprintf("%v", val), 1)
+		rendered := fmt.Sprintf("%v", val)
+		if err := e.checkOutput(rendered); err != nil {
+			return nil, err
+		}
+		result = strings.Replace(result, match[0], rendered, 1)
+		if err := e.checkOutput(result); err != nil {
+			return nil, err
+		}
 	}
 
 	// If the entire template was a single expression, return the typed value
 	if len(matches) == 1 && matches[0][0] == template {
-		return e.Evaluate(strings.TrimSpace(matches[0][1]), data)
+		return e.evaluate(ev, strings.TrimSpace(matches[0][1]), data)
 	}
 
 	return result, nil
 }
 
 // evaluateComparison evaluates a comparison expression.
-func (e *Engine) evaluateComparison(expr string, data interface{}) (interface{}, error) {
+func (e *Engine) evaluateComparison(ev *evaluation, expr string, data interface{}) (interface{}, error) {
 	// Handle AND/OR
 	if idx := strings.Index(strings.ToUpper(expr), " AND "); idx > 0 {
 		left := strings.TrimSpace(expr[:idx])
 		right := strings.TrimSpace(expr[idx+5:])
 
-		leftResult, err := e.EvaluateBool(left, data)
+		leftResult, err := e.evaluateBool(ev, left, data)
 		if err != nil {
 			return false, err
 		}
 		if !leftResult {
 			return false, nil
 		}
-		return e.EvaluateBool(right, data)
+		return e.evaluateBool(ev, right, data)
 	}
 
 	if idx := strings.Index(strings.ToUpper(expr), " OR "); idx > 0 {
 		left := strings.TrimSpace(expr[:idx])
 		right := strings.TrimSpace(expr[idx+4:])
 
-		leftResult, err := e.EvaluateBool(left, data)
+		leftResult, err := e.evaluateBool(ev, left, data)
+		if errors.Is(err, ErrEvaluationLimitExceeded) {
+			return false, err
+		}
 		if err == nil && leftResult {
 			return true, nil
 		}
-		return e.EvaluateBool(right, data)
+		return e.evaluateBool(ev, right, data)
 	}
 
 	// Handle comparison operators
@@ -323,11 +492,11 @@ func (e *Engine) evaluateComparison(expr string, data interface{}) (interface{},
 			left := strings.TrimSpace(expr[:idx])
 			right := strings.TrimSpace(expr[idx+len(op.op):])
 
-			leftVal, err := e.evaluateOperand(left, data)
+			leftVal, err := e.evaluateOperand(ev, left, data)
 			if err != nil {
 				return nil, err
 			}
-			rightVal, err := e.evaluateOperand(right, data)
+			rightVal, err := e.evaluateOperand(ev, right, data)
 			if err != nil {
 				return nil, err
 			}
@@ -339,7 +508,30 @@ func (e *Engine) evaluateComparison(expr string, data interface{}) (interface{},
 	return nil, ErrInvalidExpression
 }
 
-func (e *Engine) evaluateOperand(operand string, data interface{}) (interface{}, error) {
+// evaluateFunctionCall evaluates the arguments and calls a registered function.
+// Registered functions take precedence over the builtin iterators.
+func (e *Engine) evaluateFunctionCall(ev *evaluation, name string, rawArgs []string, data interface{}) (interface{}, error) {
+	fn, ok := e.functions[name]
+	it, isIterator := e.iterators[name]
+	if !ok && !isIterator {
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
+
+	args := make([]interface{}, len(rawArgs))
+	for i, raw := range rawArgs {
+		val, err := e.evaluateOperand(ev, raw, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s argument %d: %w", name, i+1, err)
+		}
+		args[i] = val
+	}
+	if ok {
+		return fn(args...)
+	}
+	return it(ev, args...)
+}
+
+func (e *Engine) evaluateOperand(ev *evaluation, operand string, data interface{}) (interface{}, error) {
 	operand = strings.TrimSpace(operand)
 
 	// Check for string literal
@@ -367,7 +559,7 @@ func (e *Engine) evaluateOperand(operand string, data interface{}) (interface{},
 	}
 
 	// Evaluate as path
-	return e.Evaluate(operand, data)
+	return e.evaluate(ev, operand, data)
 }
 
 func (e *Engine) registerBuiltins() {
@@ -486,6 +678,78 @@ func (e *Engine) registerBuiltins() {
 		return strings.Join(strs, sep), nil
 	}
 
+	// map($.items, '@.price') evaluates the sub-expression for every item,
+	// with @ referring to the item.
+	e.iterators["map"] = func(ev *evaluation, args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, errors.New("map requires exactly 2 arguments")
+		}
+		items, ok1 := toSlice(args[0])
+		expr, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, ErrUnsupportedType
+		}
+		expr = substituteItemRef(expr, "")
+		result := make([]interface{}, 0, len(items))
+		for i, item := range items {
+			val, err := e.evaluate(ev, expr, item)
+			if errors.Is(err, ErrPathNotFound) {
+				val, err = nil, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("map item %d: %w", i, err)
+			}
+			result = append(result, val)
+		}
+		return result, nil
+	}
+
+	// filter($.items, '@.active == true') keeps the items matching the condition.
+	e.iterators["filter"] = func(ev *evaluation, args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, errors.New("filter requires exactly 2 arguments")
+		}
+		items, ok1 := toSlice(args[0])
+		condition, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, ErrUnsupportedType
+		}
+		result := make([]interface{}, 0, len(items))
+		for i, item := range items {
+			match, err := e.evaluateFilterCondition(ev, condition, item)
+			if err != nil {
+				return nil, fmt.Errorf("filter item %d: %w", i, err)
+			}
+			if match {
+				result = append(result, item)
+			}
+		}
+		return result, nil
+	}
+
+	// reduce($.items, 'acc OR @.active', false) folds the items into a single value,
+	// with acc referring to the accumulator and @ to the current item.
+	e.iterators["reduce"] = func(ev *evaluation, args ...interface{}) (interface{}, error) {
+		if len(args) != 3 {
+			return nil, errors.New("reduce requires exactly 3 arguments")
+		}
+		items, ok1 := toSlice(args[0])
+		expr, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, ErrUnsupportedType
+		}
+		expr = substituteItemRef(expr, "item")
+		acc := args[2]
+		for i, item := range items {
+			val, err := e.evaluate(ev, expr, map[string]interface{}{"acc": acc, "item": item})
+			if err != nil {
+				return nil, fmt.Errorf("reduce item %d: %w", i, err)
+			}
+			acc = val
+		}
+		return acc, nil
+	}
+
 	e.functions["toJson"] = func(args ...interface{}) (interface{}, error) {
 		if len(args) != 1 {
 			return nil, errors.New("toJson requires exactly 1 argument")
@@ -513,6 +777,94 @@ func (e *Engine) registerBuiltins() {
 	}
 }
 
+// toSlice converts any slice value to []interface{}.
+func toSlice(v interface{}) ([]interface{}, bool) {
+	switch arr := v.(type) {
+	case []interface{}:
+		return arr, true
+	case nil:
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	result := make([]interface{}, rv.Len())
+	for i := range result {
+		result[i] = rv.Index(i).Interface()
+	}
+	return result, true
+}
+
+// parseFunctionCall splits "name(arg, ...)" into its name and raw arguments.
+// The parenthesis after the name must close at the end of the expression.
+func parseFunctionCall(expr string) (string, []string, bool) {
+	open := strings.IndexByte(expr, '(')
+	if open <= 0 || !strings.HasSuffix(expr, ")") {
+		return "", nil, false
+	}
+	name := expr[:open]
+	for i, ch := range name {
+		if !(ch == '_' || unicode.IsLetter(ch) || (i > 0 && unicode.IsDigit(ch))) {
+			return "", nil, false
+		}
+	}
+
+	// The opening parenthesis must not close before the end, as in
+	// "len(a) + len(b)".
+	if maskNested(expr)[open+1:len(expr)-1] != strings.Repeat("_", len(expr)-open-2) {
+		return "", nil, false
+	}
+
+	inner := expr[open+1 : len(expr)-1]
+	var args []string
+	masked := maskNested(inner)
+	start := 0
+	for i := 0; i < len(masked); i++ {
+		if masked[i] == ',' {
+			args = append(args, strings.TrimSpace(inner[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(inner[start:]); last != "" || len(args) > 0 {
+		args = append(args, last)
+	}
+	return name, args, true
+}
+
+// maskNested returns expr with the contents of quotes, parentheses and
+// brackets replaced by underscores, so operators and separators can be found
+// at the top level only. Unbalanced closers are left in place.
+func maskNested(expr string) string {
+	masked := []byte(expr)
+	depth := 0
+	var quote byte
+	for i, ch := range masked {
+		nested := depth > 0 || quote != 0
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+			nested = true
+		case ch == '(' || ch == '[':
+			depth++
+			nested = depth > 1
+		case ch == ')' || ch == ']':
+			if depth > 0 {
+				depth--
+			}
+			nested = depth > 0
+		}
+		if nested {
+			masked[i] = '_'
+		}
+	}
+	return string(masked)
+}
+
 func parsePath(path string) []string {
 	var parts []string
 	var current strings.Builder
@@ -0,0 +1,226 @@
+package expression
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testOrderData() map[string]interface{} {
+	return map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{
+				"name":   "pen",
+				"price":  2.5,
+				"active": true,
+				"vendor": map[string]interface{}{"country": "DE"},
+			},
+			map[string]interface{}{
+				"name":   "book",
+				"price":  12.0,
+				"active": false,
+				"vendor": map[string]interface{}{"country": "US"},
+			},
+			map[string]interface{}{
+				"name":   "lamp",
+				"price":  30.0,
+				"active": true,
+				"vendor": map[string]interface{}{"country": "US"},
+			},
+		},
+		"empty": []interface{}{},
+	}
+}
+
+func TestCollectionBuiltins(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected interface{}
+	}{
+		{
+			name:     "map field",
+			expr:     "{{ map($.items, '@.price') }}",
+			expected: []interface{}{2.5, 12.0, 30.0},
+		},
+		{
+			name:     "map nested field",
+			expr:     "{{ map($.items, '@.vendor.country') }}",
+			expected: []interface{}{"DE", "US", "US"},
+		},
+		{
+			name:     "map missing field",
+			expr:     "map($.items, '@.discount')",
+			expected: []interface{}{nil, nil, nil},
+		},
+		{
+			name:     "map empty array",
+			expr:     "{{ map($.empty, '@.price') }}",
+			expected: []interface{}{},
+		},
+		{
+			name:     "filter boolean",
+			expr:     "{{ map(filter($.items, '@.active == true'), '@.name') }}",
+			expected: []interface{}{"pen", "lamp"},
+		},
+		{
+			name:     "filter nested object",
+			expr:     "map(filter($.items, \"@.vendor.country == 'US'\"), '@.name')",
+			expected: []interface{}{"book", "lamp"},
+		},
+		{
+			name:     "filter numeric",
+			expr:     "len(filter($.items, '@.price > 10'))",
+			expected: 2,
+		},
+		{
+			name:     "filter compound condition",
+			expr:     "map(filter($.items, '@.active == true AND @.vendor.country == \"US\"'), '@.name')",
+			expected: []interface{}{"lamp"},
+		},
+		{
+			name:     "filter empty array",
+			expr:     "filter($.empty, '@.active == true')",
+			expected: []interface{}{},
+		},
+		{
+			name:     "reduce any",
+			expr:     "{{ reduce($.items, 'acc OR @.price > 20', false) }}",
+			expected: true,
+		},
+		{
+			name:     "reduce filtered",
+			expr:     "reduce(filter($.items, '@.active == true'), 'acc OR @.vendor.country == \"DE\"', false)",
+			expected: true,
+		},
+		{
+			name:     "reduce with registered function",
+			expr:     "reduce($.items, 'add(acc, @.price)', 0)",
+			expected: 44.5,
+		},
+		{
+			name:     "reduce empty array returns initial",
+			expr:     "reduce($.empty, 'add(acc, @.price)', 7)",
+			expected: 7.0,
+		},
+	}
+
+	engine := NewEngine()
+	engine.RegisterFunction("add", func(args ...interface{}) (interface{}, error) {
+		return toFloat(args[0]) + toFloat(args[1]), nil
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Evaluate(tt.expr, testOrderData())
+			if err != nil {
+				t.Fatalf("Evaluate(%q) error: %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Fatalf("Evaluate(%q) = %#v, want %#v", tt.expr, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollectionBuiltinResultsSupportPathResolution(t *testing.T) {
+	engine := NewEngine()
+	data := testOrderData()
+
+	active, err := engine.Evaluate("filter($.items, '@.active == true')", data)
+	if err != nil {
+		t.Fatalf("filter error: %v", err)
+	}
+
+	name, err := engine.Evaluate("$.active[1].vendor.country", map[string]interface{}{"active": active})
+	if err != nil {
+		t.Fatalf("path resolution on filter result failed: %v", err)
+	}
+	if name != "US" {
+		t.Fatalf("expected US, got %v", name)
+	}
+}
+
+func TestCollectionBuiltinErrors(t *testing.T) {
+	engine := NewEngine()
+	data := testOrderData()
+
+	for _, expr := range []string{
+		"map($.items)",
+		"map($.items[0], '@.price')",
+		"reduce($.items, 'acc OR @.active')",
+		"filter($.items, '@.discount > 1')",
+		"unknown($.items)",
+	} {
+		if _, err := engine.Evaluate(expr, data); err == nil {
+			t.Errorf("Evaluate(%q) expected error", expr)
+		}
+	}
+}
+
+// requireLimit fails unless err is a LimitError for limit.
+func requireLimit(t *testing.T, err error, limit string) {
+	t.Helper()
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != limit {
+		t.Fatalf("expected %s limit error, got %v", limit, err)
+	}
+	if !errors.Is(err, ErrEvaluationLimitExceeded) {
+		t.Fatalf("error %v does not wrap ErrEvaluationLimitExceeded", err)
+	}
+}
+
+func TestEvaluationDepthLimit(t *testing.T) {
+	engine := NewEngineWithLimits(Limits{MaxDepth: 10})
+	data := map[string]interface{}{"a": 1.0}
+
+	chain := strings.TrimSuffix(strings.Repeat("a == 1 AND ", 20), " AND ")
+	_, err := engine.EvaluateBool(chain, data)
+	requireLimit(t, err, LimitDepth)
+
+	if ok, err := engine.EvaluateBool("a == 1 AND a == 1", data); err != nil || !ok {
+		t.Fatalf("short chain = %v, %v; want true", ok, err)
+	}
+
+	_, err = engine.Evaluate("$"+strings.Repeat(".a", 20), data)
+	requireLimit(t, err, LimitDepth)
+}
+
+func TestEvaluationOutputLengthLimit(t *testing.T) {
+	engine := NewEngineWithLimits(Limits{MaxOutputLength: 1000})
+	data := map[string]interface{}{"s": strings.Repeat("x", 600)}
+
+	_, err := engine.Evaluate("{{ $.s }}{{ $.s }}", data)
+	requireLimit(t, err, LimitOutputLength)
+
+	if result, err := engine.Evaluate("<{{ $.s }}>", data); err != nil || len(result.(string)) != 602 {
+		t.Fatalf("template within the limit failed: %v", err)
+	}
+}
+
+func TestEvaluationTimeout(t *testing.T) {
+	engine := NewEngineWithLimits(Limits{Timeout: 20 * time.Millisecond})
+	engine.RegisterFunction("slow", func(args ...interface{}) (interface{}, error) {
+		time.Sleep(5 * time.Millisecond)
+		return args[0], nil
+	})
+	items := make([]interface{}, 100)
+	for i := range items {
+		items[i] = float64(i)
+	}
+
+	start := time.Now()
+	_, err := engine.Evaluate("map($.items, 'slow(@)')", map[string]interface{}{"items": items})
+	requireLimit(t, err, LimitTimeout)
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("evaluation ran for %v after its deadline", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := engine.EvaluateContext(ctx, "$.items", map[string]interface{}{"items": items}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled evaluation error = %v, want context.Canceled", err)
+	}
+}
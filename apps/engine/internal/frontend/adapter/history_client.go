@@ -3,12 +3,16 @@ package adapter
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
 	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
 	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/execution/timeline"
 	"github.com/linkflow/engine/internal/frontend"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -22,6 +26,11 @@ func NewHistoryClient(conn *grpc.ClientConn) *HistoryClient {
 	}
 }
 
+// correlationIDHeaderField mirrors history.CorrelationIDHeaderField, which
+// this package cannot import: the ExecutionStarted header field carrying the
+// correlation ID.
+const correlationIDHeaderField = "correlation_id"
+
 func (c *HistoryClient) RecordEvent(ctx context.Context, req *frontend.RecordEventRequest) error {
 	event := &historyv1.HistoryEvent{
 		EventId:   1,
@@ -32,12 +41,31 @@ func (c *HistoryClient) RecordEvent(ctx context.Context, req *frontend.RecordEve
 	switch req.EventType {
 	case "WorkflowExecutionStarted":
 		if attrs, ok := req.Attributes.(*frontend.ExecutionStartedAttributes); ok {
+			started := &historyv1.ExecutionStartedEventAttributes{
+				WorkflowType: &apiv1.WorkflowType{Name: attrs.WorkflowType},
+				TaskQueue:    &apiv1.TaskQueue{Name: attrs.TaskQueue},
+				Input:        &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: attrs.Input}}},
+			}
+			if attrs.CorrelationID != "" {
+				started.Header = &commonv1.Header{Fields: map[string]*commonv1.Payload{
+					correlationIDHeaderField: {Data: []byte(attrs.CorrelationID)},
+				}}
+			}
 			event.Attributes = &historyv1.HistoryEvent_ExecutionStartedAttributes{
-				ExecutionStartedAttributes: &historyv1.ExecutionStartedEventAttributes{
-					WorkflowType: &apiv1.WorkflowType{Name: attrs.WorkflowType},
-					TaskQueue:    &apiv1.TaskQueue{Name: attrs.TaskQueue},
-					Input:        &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: attrs.Input}}},
-				},
+				ExecutionStartedAttributes: started,
+			}
+		}
+	case "WorkflowExecutionSignaled":
+		if attrs, ok := req.Attributes.(*frontend.SignalReceivedAttributes); ok {
+			signal := &historyv1.SignalReceivedEventAttributes{
+				SignalName: attrs.SignalName,
+				Identity:   attrs.Identity,
+			}
+			if len(attrs.Input) > 0 {
+				signal.Input = &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: attrs.Input}}}
+			}
+			event.Attributes = &historyv1.HistoryEvent_SignalReceivedAttributes{
+				SignalReceivedAttributes: signal,
 			}
 		}
 	}
@@ -131,6 +159,20 @@ func extractAttributes(e *historyv1.HistoryEvent) ([]byte, error) {
 		if a := e.GetTimerFiredAttributes(); a != nil {
 			attrs = a
 		}
+	case commonv1.EventType_EVENT_TYPE_SIGNAL_RECEIVED:
+		if a := e.GetSignalReceivedAttributes(); a != nil {
+			attrs = a
+		}
+	case commonv1.EventType_EVENT_TYPE_MARKER_RECORDED:
+		if a := e.GetMarkerRecordedAttributes(); a != nil {
+			// Marker details are short text values such as a change ID and
+			// version; show them as text rather than base64.
+			details := make(map[string]string, len(a.GetDetails()))
+			for k, v := range a.GetDetails() {
+				details[k] = string(v)
+			}
+			attrs = map[string]interface{}{"marker_name": a.GetMarkerName(), "details": details}
+		}
 	}
 
 	if attrs == nil {
@@ -168,6 +210,427 @@ func (c *HistoryClient) GetMutableState(ctx context.Context, key frontend.Execut
 	}, nil
 }
 
+func (c *HistoryClient) ForceTerminateExecution(ctx context.Context, req *frontend.ForceTerminateExecutionRequest) error {
+	protoReq := &historyv1.ForceTerminateExecutionRequest{
+		Namespace: req.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: req.WorkflowID,
+			RunId:      req.RunID,
+		},
+		Reason:   req.Reason,
+		Identity: req.Identity,
+	}
+
+	_, err := c.client.ForceTerminateExecution(ctx, protoReq)
+	return err
+}
+
+func (c *HistoryClient) DeleteExecution(ctx context.Context, req *frontend.DeleteExecutionRequest) (string, error) {
+	resp, err := c.client.DeleteWorkflowExecution(ctx, &historyv1.DeleteWorkflowExecutionRequest{
+		Namespace: req.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: req.WorkflowID,
+			RunId:      req.RunID,
+		},
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		return resp.GetRunId(), nil
+	case codes.NotFound:
+		return "", frontend.ErrExecutionNotFound
+	case codes.FailedPrecondition:
+		return "", frontend.ErrExecutionRunning
+	default:
+		return "", err
+	}
+}
+
+func (c *HistoryClient) VerifyHistoryIntegrity(ctx context.Context, key frontend.ExecutionKey) (*frontend.HistoryIntegrity, error) {
+	resp, err := c.client.VerifyHistoryIntegrity(ctx, &historyv1.VerifyHistoryIntegrityRequest{
+		Namespace: key.NamespaceID,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: key.WorkflowID,
+			RunId:      key.RunID,
+		},
+	})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.NotFound:
+		return nil, frontend.ErrExecutionNotFound
+	case codes.Unimplemented:
+		return nil, frontend.ErrIntegrityUnsupported
+	default:
+		return nil, err
+	}
+
+	return &frontend.HistoryIntegrity{
+		NamespaceID:       resp.GetNamespace(),
+		WorkflowID:        resp.GetWorkflowExecution().GetWorkflowId(),
+		RunID:             resp.GetWorkflowExecution().GetRunId(),
+		Events:            resp.GetEvents(),
+		HashedEvents:      resp.GetHashedEvents(),
+		ChainStartEventID: resp.GetChainStartEventId(),
+		Hashed:            resp.GetHashed(),
+		Intact:            resp.GetIntact(),
+		BrokenEventID:     resp.GetBrokenEventId(),
+		Reason:            resp.GetReason(),
+	}, nil
+}
+
+func (c *HistoryClient) CompleteAsyncActivity(ctx context.Context, req *frontend.CompleteAsyncActivityRequest) error {
+	_, err := c.client.RespondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{
+		TaskToken: []byte(req.TaskToken),
+		Result:    &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: req.Result}}},
+		Identity:  req.Identity,
+	})
+	return mapAsyncActivityError(err)
+}
+
+func (c *HistoryClient) FailAsyncActivity(ctx context.Context, req *frontend.FailAsyncActivityRequest) error {
+	_, err := c.client.RespondActivityTaskFailed(ctx, &historyv1.RespondActivityTaskFailedRequest{
+		TaskToken: []byte(req.TaskToken),
+		Failure: &commonv1.Failure{
+			Message:     req.Reason,
+			StackTrace:  req.Details,
+			FailureType: commonv1.FailureType_FAILURE_TYPE_APPLICATION,
+		},
+		Identity: req.Identity,
+	})
+	return mapAsyncActivityError(err)
+}
+
+func (c *HistoryClient) GetExecutionStats(ctx context.Context, req *frontend.GetExecutionStatsRequest) (*frontend.ExecutionStats, error) {
+	resp, err := c.client.GetWorkflowExecutionStats(ctx, &historyv1.GetWorkflowExecutionStatsRequest{
+		Namespace: req.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: req.WorkflowID,
+			RunId:      req.RunID,
+		},
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, frontend.ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &frontend.ExecutionStats{
+		WorkflowID: resp.GetWorkflowExecution().GetWorkflowId(),
+		RunID:      resp.GetWorkflowExecution().GetRunId(),
+		Status:     mapExecutionStatus(resp.GetStatus()),
+		Duration:   resp.GetDuration().AsDuration(),
+		EventCount: resp.GetEventCount(),
+		NodeCounts: frontend.NodeStatusCounts{
+			Scheduled: resp.GetNodeCounts().GetScheduled(),
+			Running:   resp.GetNodeCounts().GetRunning(),
+			Completed: resp.GetNodeCounts().GetCompleted(),
+			Failed:    resp.GetNodeCounts().GetFailed(),
+			TimedOut:  resp.GetNodeCounts().GetTimedOut(),
+		},
+	}
+	if resp.GetStartTime() != nil {
+		stats.StartTime = resp.GetStartTime().AsTime()
+	}
+	if resp.GetCloseTime() != nil {
+		closeTime := resp.GetCloseTime().AsTime()
+		stats.CloseTime = &closeTime
+	}
+	return stats, nil
+}
+
+func (c *HistoryClient) ListResetPoints(ctx context.Context, req *frontend.ListResetPointsRequest) ([]frontend.ResetPoint, error) {
+	resp, err := c.client.ListResetPoints(ctx, &historyv1.ListResetPointsRequest{
+		Namespace: req.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: req.WorkflowID,
+			RunId:      req.RunID,
+		},
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, frontend.ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]frontend.ResetPoint, 0, len(resp.GetResetPoints()))
+	for _, point := range resp.GetResetPoints() {
+		points = append(points, frontend.ResetPoint{
+			EventID:   point.GetEventId(),
+			Label:     point.GetLabel(),
+			Timestamp: point.GetEventTime().AsTime(),
+		})
+	}
+	return points, nil
+}
+
+func (c *HistoryClient) GetExecutionTree(ctx context.Context, req *frontend.GetExecutionTreeRequest) (*frontend.ExecutionTree, error) {
+	resp, err := c.client.GetExecutionTree(ctx, &historyv1.GetExecutionTreeRequest{
+		Namespace: req.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: req.WorkflowID,
+			RunId:      req.RunID,
+		},
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, frontend.ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &frontend.ExecutionTree{
+		Root:      mapExecutionTreeNode(resp.GetRoot()),
+		NodeCount: int(resp.GetNodeCount()),
+		Truncated: resp.GetTruncated(),
+	}, nil
+}
+
+func mapExecutionTreeNode(node *historyv1.ExecutionTreeNode) *frontend.ExecutionTreeNode {
+	info := node.GetExecutionInfo()
+	execution := &frontend.WorkflowExecution{
+		WorkflowID:   info.GetExecution().GetWorkflowId(),
+		RunID:        info.GetExecution().GetRunId(),
+		WorkflowType: info.GetType().GetName(),
+		Status:       mapExecutionStatus(info.GetStatus()),
+	}
+	if info.GetStartTime() != nil {
+		execution.StartTime = info.GetStartTime().AsTime()
+	}
+	if info.GetCloseTime() != nil {
+		closeTime := info.GetCloseTime().AsTime()
+		execution.CloseTime = &closeTime
+	}
+
+	out := &frontend.ExecutionTreeNode{
+		Execution: execution,
+		Children:  make([]*frontend.ExecutionTreeNode, 0, len(node.GetChildren())),
+	}
+	for _, child := range node.GetChildren() {
+		out.Children = append(out.Children, mapExecutionTreeNode(child))
+	}
+	return out
+}
+
+func (c *HistoryClient) GetExecutionTimeline(ctx context.Context, req *frontend.GetExecutionTimelineRequest) ([]*frontend.TimelineNode, error) {
+	resp, err := c.client.GetHistory(ctx, &historyv1.GetHistoryRequest{
+		Namespace: req.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: req.WorkflowID,
+			RunId:      req.RunID,
+		},
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, frontend.ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events := resp.GetHistory().GetEvents()
+	if len(events) == 0 {
+		return nil, frontend.ErrExecutionNotFound
+	}
+
+	nodes := timeline.Build(events)
+	out := make([]*frontend.TimelineNode, 0, len(nodes))
+	for _, node := range nodes {
+		out = append(out, &frontend.TimelineNode{
+			NodeID:      node.NodeID,
+			NodeType:    node.NodeType,
+			Name:        node.Name,
+			Status:      node.Status,
+			Sequence:    node.Sequence,
+			Attempts:    node.Attempts,
+			ScheduledAt: node.ScheduledAt,
+			StartedAt:   node.StartedAt,
+			CompletedAt: node.CompletedAt,
+			Output:      node.Output,
+			Error:       node.Error,
+		})
+	}
+	return out, nil
+}
+
+func (c *HistoryClient) DescribeExecution(ctx context.Context, req *frontend.DescribeExecutionRequest) (*frontend.DescribeExecutionResponse, error) {
+	resp, err := c.client.DescribeWorkflowExecution(ctx, &historyv1.DescribeWorkflowExecutionRequest{
+		Namespace: req.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: req.WorkflowID,
+			RunId:      req.RunID,
+		},
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, frontend.ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := resp.GetExecutionInfo()
+	execution := &frontend.WorkflowExecution{
+		WorkflowID:   info.GetExecution().GetWorkflowId(),
+		RunID:        info.GetExecution().GetRunId(),
+		WorkflowType: info.GetType().GetName(),
+		TaskQueue:    resp.GetTaskQueue(),
+		Status:       mapExecutionStatus(info.GetStatus()),
+	}
+	if info.GetStartTime() != nil {
+		execution.StartTime = info.GetStartTime().AsTime()
+	}
+	if info.GetCloseTime() != nil {
+		closeTime := info.GetCloseTime().AsTime()
+		execution.CloseTime = &closeTime
+	}
+
+	desc := &frontend.DescribeExecutionResponse{
+		Execution:         execution,
+		PendingActivities: make([]*frontend.PendingActivity, 0, len(resp.GetPendingActivities())),
+		PendingTimers:     make([]*frontend.PendingTimer, 0, len(resp.GetPendingTimers())),
+		PendingChildExecs: []*frontend.PendingChildExecution{},
+		VersionMarkers:    make([]*frontend.VersionMarker, 0, len(resp.GetVersionMarkers())),
+	}
+	for _, a := range resp.GetPendingActivities() {
+		activity := &frontend.PendingActivity{
+			ActivityID:   a.GetActivityId(),
+			ActivityType: a.GetActivityType(),
+			State:        frontend.PendingActivityStateScheduled,
+			Attempt:      a.GetAttempt(),
+		}
+		if a.GetState() == historyv1.PendingActivityState_PENDING_ACTIVITY_STATE_STARTED {
+			activity.State = frontend.PendingActivityStateStarted
+		}
+		if a.GetScheduledTime() != nil {
+			activity.ScheduledTime = a.GetScheduledTime().AsTime()
+		}
+		activity.LastStartedTime = optionalTime(a.GetLastStartedTime())
+		activity.LastHeartbeatTime = optionalTime(a.GetLastHeartbeatTime())
+		if failure := a.GetLastFailure(); failure != nil {
+			activity.LastFailure = &frontend.Failure{
+				Message:    failure.GetMessage(),
+				Source:     failure.GetSource(),
+				StackTrace: failure.GetStackTrace(),
+			}
+			activity.LastFailureTime = optionalTime(a.GetLastFailureTime())
+		}
+		desc.PendingActivities = append(desc.PendingActivities, activity)
+	}
+	for _, t := range resp.GetPendingTimers() {
+		desc.PendingTimers = append(desc.PendingTimers, &frontend.PendingTimer{
+			TimerID:        t.GetTimerId(),
+			StartedEventID: t.GetStartedEventId(),
+			FireTime:       t.GetFireTime().AsTime(),
+		})
+	}
+	for _, m := range resp.GetVersionMarkers() {
+		desc.VersionMarkers = append(desc.VersionMarkers, &frontend.VersionMarker{
+			ChangeID: m.GetChangeId(),
+			Version:  m.GetVersion(),
+			EventID:  m.GetEventId(),
+		})
+	}
+	return desc, nil
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func mapAsyncActivityError(err error) error {
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.InvalidArgument:
+		return frontend.ErrInvalidTaskToken
+	case codes.NotFound, codes.FailedPrecondition:
+		return frontend.ErrActivityNotPending
+	default:
+		return err
+	}
+}
+
+func (c *HistoryClient) ListExecutions(ctx context.Context, req *frontend.ListExecutionsRequest) (*frontend.ListExecutionsResponse, error) {
+	protoReq := &historyv1.ListWorkflowExecutionsRequest{
+		Namespace:     req.Namespace,
+		PageSize:      req.PageSize,
+		NextPageToken: req.NextPageToken,
+		Query:         req.Query,
+	}
+	switch req.Status {
+	case "", "open":
+		protoReq.StatusFilter = historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_OPEN
+	case "closed":
+		protoReq.StatusFilter = historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_CLOSED
+	case "all":
+		protoReq.StatusFilter = historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_ALL
+	case "running":
+		protoReq.Status = commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING
+	case "completed":
+		protoReq.Status = commonv1.ExecutionStatus_EXECUTION_STATUS_COMPLETED
+	case "failed":
+		protoReq.Status = commonv1.ExecutionStatus_EXECUTION_STATUS_FAILED
+	case "canceled":
+		protoReq.Status = commonv1.ExecutionStatus_EXECUTION_STATUS_CANCELLED
+	case "terminated":
+		protoReq.Status = commonv1.ExecutionStatus_EXECUTION_STATUS_TERMINATED
+	case "timed_out":
+		protoReq.Status = commonv1.ExecutionStatus_EXECUTION_STATUS_TIMED_OUT
+	case "continued_as_new":
+		protoReq.Status = commonv1.ExecutionStatus_EXECUTION_STATUS_CONTINUED_AS_NEW
+	default:
+		return nil, frontend.ErrInvalidStatus
+	}
+
+	resp, err := c.client.ListWorkflowExecutions(ctx, protoReq)
+	if err != nil {
+		return nil, err
+	}
+	return listExecutionsResponse(resp.GetExecutions(), resp.GetNextPageToken()), nil
+}
+
+func (c *HistoryClient) ListExecutionsByCorrelationID(ctx context.Context, req *frontend.ListExecutionsByCorrelationIDRequest) (*frontend.ListExecutionsResponse, error) {
+	resp, err := c.client.ListExecutionsByCorrelationID(ctx, &historyv1.ListExecutionsByCorrelationIDRequest{
+		Namespace:     req.Namespace,
+		CorrelationId: req.CorrelationID,
+		PageSize:      req.PageSize,
+		NextPageToken: req.NextPageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return listExecutionsResponse(resp.GetExecutions(), resp.GetNextPageToken()), nil
+}
+
+func listExecutionsResponse(infos []*historyv1.WorkflowExecutionInfo, nextPageToken []byte) *frontend.ListExecutionsResponse {
+	executions := make([]*frontend.WorkflowExecution, 0, len(infos))
+	for _, info := range infos {
+		execution := &frontend.WorkflowExecution{
+			WorkflowID:    info.GetExecution().GetWorkflowId(),
+			RunID:         info.GetExecution().GetRunId(),
+			WorkflowType:  info.GetType().GetName(),
+			Status:        mapExecutionStatus(info.GetStatus()),
+			HistoryLength: info.GetHistoryLength(),
+		}
+		if info.GetStartTime() != nil {
+			execution.StartTime = info.GetStartTime().AsTime()
+		}
+		if closeTime := info.GetCloseTime(); closeTime != nil && !closeTime.AsTime().IsZero() {
+			t := closeTime.AsTime()
+			execution.CloseTime = &t
+		}
+		executions = append(executions, execution)
+	}
+
+	return &frontend.ListExecutionsResponse{
+		Executions:    executions,
+		NextPageToken: nextPageToken,
+	}
+}
+
 func (c *HistoryClient) ListWorkflowExecutions(ctx context.Context, req *historyv1.ListWorkflowExecutionsRequest) (*historyv1.ListWorkflowExecutionsResponse, error) {
 	return c.client.ListWorkflowExecutions(ctx, req)
 }
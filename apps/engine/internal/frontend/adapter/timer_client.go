@@ -0,0 +1,73 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+// TimerClient reads pending timers through the timer service's HTTP API.
+type TimerClient struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewTimerClient creates a client for the timer service at baseURL that
+// authenticates with the service's bearer token.
+func NewTimerClient(baseURL, token string) *TimerClient {
+	return &TimerClient{
+		client:  &http.Client{Timeout: 5 * time.Second},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+	}
+}
+
+type listTimersResponse struct {
+	Timers []struct {
+		TimerID string    `json:"timer_id"`
+		FireAt  time.Time `json:"fire_at"`
+	} `json:"timers"`
+}
+
+func (c *TimerClient) ListPendingTimers(ctx context.Context, key frontend.ExecutionKey) ([]*frontend.PendingTimer, error) {
+	query := url.Values{}
+	query.Set("namespace", key.NamespaceID)
+	query.Set("workflow_id", key.WorkflowID)
+	query.Set("run_id", key.RunID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/timers?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create timer request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("timer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("timer service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var decoded listTimersResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode timer response: %w", err)
+	}
+
+	timers := make([]*frontend.PendingTimer, 0, len(decoded.Timers))
+	for _, t := range decoded.Timers {
+		timers = append(timers, &frontend.PendingTimer{TimerID: t.TimerID, FireTime: t.FireAt})
+	}
+	return timers, nil
+}
@@ -0,0 +1,135 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/linkflow/engine/internal/approval"
+)
+
+// DecideApprovalRequest is an approver's decision on an approval.
+type DecideApprovalRequest struct {
+	ID        string
+	Namespace string // the approver's namespace; other namespaces' approvals are not found
+	Decision  string // approve or reject
+	Approver  string
+	Comment   string
+}
+
+// WithApprovals enables the approval endpoints backed by store.
+func (s *Service) WithApprovals(store approval.Store) *Service {
+	s.approvals = store
+	return s
+}
+
+// ListApprovals returns the approvals matching filter, oldest first.
+func (s *Service) ListApprovals(ctx context.Context, filter approval.ListFilter) ([]*approval.Approval, error) {
+	if s.approvals == nil {
+		return nil, ErrApprovalsDisabled
+	}
+	return s.approvals.ListApprovals(ctx, filter)
+}
+
+// DecideApproval records an approver's decision and resolves the approval
+// node waiting on it. Approval completes the node; rejection fails it, or
+// completes it on its rejected branch if the node continues on rejection.
+// Either way history records the decision with who made it and when. If the
+// node cannot be resolved the decision is undone, so it can be retried, or
+// the approval expires when the node no longer waits.
+func (s *Service) DecideApproval(ctx context.Context, req *DecideApprovalRequest) (*approval.Approval, error) {
+	if s.approvals == nil {
+		return nil, ErrApprovalsDisabled
+	}
+
+	var status approval.Status
+	switch strings.ToLower(req.Decision) {
+	case "approve", "approved":
+		status = approval.StatusApproved
+	case "reject", "rejected":
+		status = approval.StatusRejected
+	default:
+		return nil, fmt.Errorf("%w: decision must be approve or reject", ErrInvalidApprovalDecision)
+	}
+	approver := strings.TrimSpace(req.Approver)
+	if approver == "" {
+		return nil, fmt.Errorf("%w: approver is required", ErrInvalidApprovalDecision)
+	}
+
+	existing, err := s.approvals.GetApproval(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Namespace != req.Namespace {
+		return nil, approval.ErrNotFound
+	}
+	if existing.Expired(time.Now()) {
+		return nil, approval.ErrNotPending
+	}
+	if !existing.CanDecide(approver) {
+		return nil, ErrApproverNotAllowed
+	}
+
+	decided, err := s.approvals.DecideApproval(ctx, req.ID, approval.Decision{
+		Status:    status,
+		DecidedBy: approver,
+		Comment:   req.Comment,
+		DecidedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveApprovalNode(ctx, decided); err != nil {
+		reopenAs := approval.StatusPending
+		if errors.Is(err, ErrActivityNotPending) || errors.Is(err, ErrInvalidTaskToken) {
+			reopenAs = approval.StatusExpired
+			err = approval.ErrNotPending
+		}
+		if reopenErr := s.approvals.ReopenApproval(ctx, decided.ID, reopenAs); reopenErr != nil {
+			s.logger.Error("failed to reopen approval",
+				slog.String("approval_id", decided.ID),
+				slog.String("error", reopenErr.Error()),
+			)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("approval decided",
+		slog.String("approval_id", decided.ID),
+		slog.String("workflow_id", decided.WorkflowID),
+		slog.String("node_id", decided.NodeID),
+		slog.String("status", string(decided.Status)),
+		slog.String("decided_by", decided.DecidedBy),
+	)
+	return decided, nil
+}
+
+func (s *Service) resolveApprovalNode(ctx context.Context, a *approval.Approval) error {
+	result, err := json.Marshal(approval.DecisionResult(a))
+	if err != nil {
+		return err
+	}
+	if a.Status == approval.StatusApproved || a.OnReject == approval.OnRejectContinue {
+		return s.CompleteAsyncActivity(ctx, &CompleteAsyncActivityRequest{
+			TaskToken: a.TaskToken,
+			Result:    result,
+			Identity:  a.DecidedBy,
+		})
+	}
+
+	reason := fmt.Sprintf("approval rejected by %s", a.DecidedBy)
+	if a.Comment != "" {
+		reason = fmt.Sprintf("%s: %s", reason, a.Comment)
+	}
+	return s.FailAsyncActivity(ctx, &FailAsyncActivityRequest{
+		TaskToken: a.TaskToken,
+		Reason:    reason,
+		Details:   string(result),
+		Identity:  a.DecidedBy,
+	})
+}
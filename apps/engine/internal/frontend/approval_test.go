@@ -0,0 +1,222 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/approval"
+)
+
+type memoryApprovalStore struct {
+	approvals map[string]*approval.Approval
+}
+
+func (m *memoryApprovalStore) CreateApproval(_ context.Context, a *approval.Approval) error {
+	m.approvals[a.ID] = a
+	return nil
+}
+
+func (m *memoryApprovalStore) GetApproval(_ context.Context, id string) (*approval.Approval, error) {
+	a, ok := m.approvals[id]
+	if !ok {
+		return nil, approval.ErrNotFound
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (m *memoryApprovalStore) ListApprovals(_ context.Context, filter approval.ListFilter) ([]*approval.Approval, error) {
+	var out []*approval.Approval
+	for _, a := range m.approvals {
+		if filter.Status == "" || a.Status == filter.Status {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryApprovalStore) DecideApproval(_ context.Context, id string, d approval.Decision) (*approval.Approval, error) {
+	a, ok := m.approvals[id]
+	if !ok {
+		return nil, approval.ErrNotFound
+	}
+	if a.Status != approval.StatusPending {
+		return nil, approval.ErrNotPending
+	}
+	a.Status, a.DecidedBy, a.Comment, a.DecidedAt = d.Status, d.DecidedBy, d.Comment, d.DecidedAt
+	copied := *a
+	return &copied, nil
+}
+
+func (m *memoryApprovalStore) ReopenApproval(_ context.Context, id string, status approval.Status) error {
+	a := m.approvals[id]
+	a.Status, a.DecidedBy, a.Comment, a.DecidedAt = status, "", "", time.Time{}
+	return nil
+}
+
+// asyncRecordingHistoryClient records how async activities are resolved.
+type asyncRecordingHistoryClient struct {
+	StubHistoryClient
+	completed []*CompleteAsyncActivityRequest
+	failed    []*FailAsyncActivityRequest
+	err       error
+}
+
+func (c *asyncRecordingHistoryClient) CompleteAsyncActivity(_ context.Context, req *CompleteAsyncActivityRequest) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.completed = append(c.completed, req)
+	return nil
+}
+
+func (c *asyncRecordingHistoryClient) FailAsyncActivity(_ context.Context, req *FailAsyncActivityRequest) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.failed = append(c.failed, req)
+	return nil
+}
+
+func newApprovalTestService(approvals ...*approval.Approval) (*Service, *asyncRecordingHistoryClient, *memoryApprovalStore) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	history := &asyncRecordingHistoryClient{StubHistoryClient: StubHistoryClient{Logger: logger}}
+	store := &memoryApprovalStore{approvals: map[string]*approval.Approval{}}
+	for _, a := range approvals {
+		store.approvals[a.ID] = a
+	}
+	svc := NewService(history, nil, logger, DefaultServiceConfig()).WithApprovals(store)
+	return svc, history, store
+}
+
+func pendingApproval(id, onReject string, approvers ...string) *approval.Approval {
+	return &approval.Approval{
+		ID:        id,
+		Namespace: "ws-1",
+		TaskToken: "token-" + id,
+		Title:     "Ship it?",
+		Approvers: approvers,
+		OnReject:  onReject,
+		Status:    approval.StatusPending,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestDecideApprovalCompletesNode(t *testing.T) {
+	svc, history, _ := newApprovalTestService(pendingApproval("a1", approval.OnRejectFail, "alice"))
+
+	decided, err := svc.DecideApproval(context.Background(), &DecideApprovalRequest{
+		ID: "a1", Namespace: "ws-1", Decision: "approve", Approver: "alice", Comment: "lgtm",
+	})
+	if err != nil {
+		t.Fatalf("DecideApproval() error = %v", err)
+	}
+	if decided.Status != approval.StatusApproved || decided.DecidedBy != "alice" {
+		t.Fatalf("decided = %+v, want approved by alice", decided)
+	}
+	if len(history.completed) != 1 || len(history.failed) != 0 {
+		t.Fatalf("completed %d, failed %d activities, want 1 completed", len(history.completed), len(history.failed))
+	}
+	completed := history.completed[0]
+	if completed.TaskToken != "token-a1" || completed.Identity != "alice" {
+		t.Errorf("completed = %+v, want token-a1 by alice", completed)
+	}
+	var result approval.Result
+	if err := json.Unmarshal(completed.Result, &result); err != nil {
+		t.Fatalf("result is not JSON: %v", err)
+	}
+	if result.Output != approval.BranchApproved || !result.Approved || result.Comment != "lgtm" || result.DecidedAt.IsZero() {
+		t.Errorf("result = %+v, want approved branch with comment and decision time", result)
+	}
+}
+
+func TestDecideApprovalRejection(t *testing.T) {
+	svc, history, _ := newApprovalTestService(
+		pendingApproval("fail", approval.OnRejectFail),
+		pendingApproval("continue", approval.OnRejectContinue),
+	)
+	ctx := context.Background()
+
+	if _, err := svc.DecideApproval(ctx, &DecideApprovalRequest{ID: "fail", Namespace: "ws-1", Decision: "reject", Approver: "bob", Comment: "too risky"}); err != nil {
+		t.Fatalf("DecideApproval(fail) error = %v", err)
+	}
+	if len(history.failed) != 1 || history.failed[0].Reason != "approval rejected by bob: too risky" {
+		t.Fatalf("failed = %+v, want node failed with rejection reason", history.failed)
+	}
+
+	if _, err := svc.DecideApproval(ctx, &DecideApprovalRequest{ID: "continue", Namespace: "ws-1", Decision: "reject", Approver: "bob"}); err != nil {
+		t.Fatalf("DecideApproval(continue) error = %v", err)
+	}
+	if len(history.completed) != 1 {
+		t.Fatalf("completed %d activities, want the continue node completed", len(history.completed))
+	}
+	var result approval.Result
+	if err := json.Unmarshal(history.completed[0].Result, &result); err != nil {
+		t.Fatalf("result is not JSON: %v", err)
+	}
+	if result.Output != approval.BranchRejected || result.Approved {
+		t.Errorf("result = %+v, want rejected branch", result)
+	}
+}
+
+func TestDecideApprovalRejectsInvalidDecisions(t *testing.T) {
+	expired := pendingApproval("expired", approval.OnRejectFail)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	svc, history, _ := newApprovalTestService(pendingApproval("a1", approval.OnRejectFail, "alice"), expired)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		req  *DecideApprovalRequest
+		want error
+	}{
+		{&DecideApprovalRequest{ID: "a1", Namespace: "ws-1", Decision: "maybe", Approver: "alice"}, ErrInvalidApprovalDecision},
+		{&DecideApprovalRequest{ID: "a1", Namespace: "ws-1", Decision: "approve", Approver: " "}, ErrInvalidApprovalDecision},
+		{&DecideApprovalRequest{ID: "a1", Namespace: "ws-1", Decision: "approve", Approver: "mallory"}, ErrApproverNotAllowed},
+		{&DecideApprovalRequest{ID: "missing", Namespace: "ws-1", Decision: "approve", Approver: "alice"}, approval.ErrNotFound},
+		{&DecideApprovalRequest{ID: "a1", Namespace: "ws-2", Decision: "approve", Approver: "alice"}, approval.ErrNotFound},
+		{&DecideApprovalRequest{ID: "expired", Namespace: "ws-1", Decision: "approve", Approver: "alice"}, approval.ErrNotPending},
+	} {
+		if _, err := svc.DecideApproval(ctx, tc.req); !errors.Is(err, tc.want) {
+			t.Errorf("DecideApproval(%+v) error = %v, want %v", tc.req, err, tc.want)
+		}
+	}
+	if len(history.completed)+len(history.failed) != 0 {
+		t.Errorf("invalid decisions resolved %d nodes, want none", len(history.completed)+len(history.failed))
+	}
+}
+
+func TestDecideApprovalReopensWhenNodeUnresolved(t *testing.T) {
+	svc, history, store := newApprovalTestService(pendingApproval("a1", approval.OnRejectFail))
+	ctx := context.Background()
+
+	history.err = errors.New("history unavailable")
+	if _, err := svc.DecideApproval(ctx, &DecideApprovalRequest{ID: "a1", Namespace: "ws-1", Decision: "approve", Approver: "alice"}); err == nil {
+		t.Fatal("DecideApproval() succeeded with history unavailable")
+	}
+	if got := store.approvals["a1"]; got.Status != approval.StatusPending || got.DecidedBy != "" {
+		t.Fatalf("approval = %+v, want reopened as pending", got)
+	}
+
+	history.err = ErrActivityNotPending
+	if _, err := svc.DecideApproval(ctx, &DecideApprovalRequest{ID: "a1", Namespace: "ws-1", Decision: "approve", Approver: "alice"}); !errors.Is(err, approval.ErrNotPending) {
+		t.Fatalf("DecideApproval() error = %v, want ErrNotPending", err)
+	}
+	if got := store.approvals["a1"].Status; got != approval.StatusExpired {
+		t.Errorf("status = %s, want expired once the node stopped waiting", got)
+	}
+}
+
+func TestApprovalsDisabledWithoutStore(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := NewService(&StubHistoryClient{Logger: logger}, nil, logger, DefaultServiceConfig())
+
+	if _, err := svc.ListApprovals(context.Background(), approval.ListFilter{}); !errors.Is(err, ErrApprovalsDisabled) {
+		t.Errorf("ListApprovals() error = %v, want ErrApprovalsDisabled", err)
+	}
+}
@@ -0,0 +1,85 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/linkflow/engine/internal/controlplane"
+)
+
+// NamespaceConfigProvider resolves namespace configuration, e.g. the control plane.
+type NamespaceConfigProvider interface {
+	GetNamespace(ctx context.Context, name string) (*controlplane.NamespaceConfig, error)
+}
+
+// ExecutionSlots counts a namespace's running executions, e.g. the control
+// plane's Redis-backed ExecutionCounter.
+type ExecutionSlots interface {
+	TryAcquire(ctx context.Context, namespace string, limit int) (int64, bool, error)
+	Release(ctx context.Context, namespace string) error
+}
+
+// ConcurrencyLimitError is returned when starting an execution would exceed
+// the namespace's MaxConcurrentExecutions.
+type ConcurrencyLimitError struct {
+	Namespace string
+	Limit     int
+	Running   int64
+}
+
+func (e *ConcurrencyLimitError) Error() string {
+	return fmt.Sprintf("namespace %s has %d running executions (limit %d)", e.Namespace, e.Running, e.Limit)
+}
+
+func (e *ConcurrencyLimitError) Unwrap() error {
+	return controlplane.ErrConcurrencyLimitExceeded
+}
+
+// WithConcurrencyLimits enables per-namespace MaxConcurrentExecutions
+// enforcement on workflow starts.
+func (s *Service) WithConcurrencyLimits(namespaces NamespaceConfigProvider, counter ExecutionSlots) *Service {
+	s.namespaceConfigs = namespaces
+	s.executionCounter = counter
+	return s
+}
+
+// acquireExecutionSlot reserves a running-execution slot for the namespace.
+// It reports whether a slot was taken so a failed start can give it back.
+// Counter errors fail open so a Redis outage does not block all starts.
+func (s *Service) acquireExecutionSlot(ctx context.Context, namespace string) (bool, error) {
+	if s.namespaceConfigs == nil || s.executionCounter == nil {
+		return false, nil
+	}
+
+	cfg, err := s.namespaceConfigs.GetNamespace(ctx, namespace)
+	if err != nil || cfg.MaxConcurrentExecutions <= 0 {
+		return false, nil
+	}
+
+	running, ok, err := s.executionCounter.TryAcquire(ctx, namespace, cfg.MaxConcurrentExecutions)
+	if err != nil {
+		s.logger.Warn("concurrency check failed, allowing start",
+			slog.String("namespace", namespace),
+			slog.String("error", err.Error()),
+		)
+		return false, nil
+	}
+	if !ok {
+		return false, &ConcurrencyLimitError{
+			Namespace: namespace,
+			Limit:     cfg.MaxConcurrentExecutions,
+			Running:   running,
+		}
+	}
+	return true, nil
+}
+
+func (s *Service) releaseExecutionSlot(ctx context.Context, namespace string) {
+	if err := s.executionCounter.Release(ctx, namespace); err != nil {
+		s.logger.Warn("failed to release execution slot",
+			slog.String("namespace", namespace),
+			slog.String("error", err.Error()),
+		)
+	}
+}
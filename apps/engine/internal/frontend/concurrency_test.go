@@ -0,0 +1,117 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/linkflow/engine/internal/controlplane"
+)
+
+type memoryNamespaceConfigs map[string]*controlplane.NamespaceConfig
+
+func (m memoryNamespaceConfigs) GetNamespace(_ context.Context, name string) (*controlplane.NamespaceConfig, error) {
+	ns, ok := m[name]
+	if !ok {
+		return nil, controlplane.ErrNamespaceNotFound
+	}
+	return ns, nil
+}
+
+// memoryExecutionSlots counts running executions like ExecutionCounter.
+type memoryExecutionSlots struct {
+	running map[string]int64
+	err     error
+}
+
+func (m *memoryExecutionSlots) TryAcquire(_ context.Context, namespace string, limit int) (int64, bool, error) {
+	if m.err != nil {
+		return 0, false, m.err
+	}
+	if m.running[namespace] >= int64(limit) {
+		return m.running[namespace], false, nil
+	}
+	m.running[namespace]++
+	return m.running[namespace], true, nil
+}
+
+func (m *memoryExecutionSlots) Release(_ context.Context, namespace string) error {
+	if m.running[namespace] > 0 {
+		m.running[namespace]--
+	}
+	return nil
+}
+
+// failingStartHistoryClient fails to record the started event.
+type failingStartHistoryClient struct {
+	StubHistoryClient
+}
+
+func (c *failingStartHistoryClient) RecordEvent(context.Context, *RecordEventRequest) error {
+	return errors.New("history unavailable")
+}
+
+func TestStartWorkflowExecutionEnforcesConcurrencyLimit(t *testing.T) {
+	namespaces := memoryNamespaceConfigs{
+		"limited":   {Name: "limited", MaxConcurrentExecutions: 2},
+		"unlimited": {Name: "unlimited"},
+	}
+	tests := []struct {
+		name      string
+		namespace string
+		running   int64
+		slotsErr  error
+		wantErr   bool
+		wantAfter int64
+	}{
+		{name: "under limit", namespace: "limited", running: 1, wantAfter: 2},
+		{name: "at limit", namespace: "limited", running: 2, wantErr: true, wantAfter: 2},
+		{name: "no limit", namespace: "unlimited", running: 5, wantAfter: 5},
+		{name: "unknown namespace", namespace: "other", running: 5, wantAfter: 5},
+		{name: "counter outage fails open", namespace: "limited", running: 2, slotsErr: errors.New("redis down"), wantAfter: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			slots := &memoryExecutionSlots{running: map[string]int64{tt.namespace: tt.running}, err: tt.slotsErr}
+			svc := NewService(&StubHistoryClient{Logger: logger}, &StubMatchingClient{Logger: logger}, logger, DefaultServiceConfig()).
+				WithConcurrencyLimits(namespaces, slots)
+
+			_, err := svc.StartWorkflowExecution(context.Background(), &StartWorkflowExecutionRequest{
+				Namespace:  tt.namespace,
+				WorkflowID: "wf-1",
+				TaskQueue:  "default",
+			})
+			var limitErr *ConcurrencyLimitError
+			if tt.wantErr {
+				if !errors.As(err, &limitErr) || !errors.Is(err, controlplane.ErrConcurrencyLimitExceeded) {
+					t.Fatalf("StartWorkflowExecution error = %v, want ConcurrencyLimitError", err)
+				}
+				if limitErr.Limit != 2 || limitErr.Running != tt.running {
+					t.Fatalf("limit error = %+v, want limit 2 with %d running", limitErr, tt.running)
+				}
+			} else if err != nil {
+				t.Fatalf("StartWorkflowExecution: %v", err)
+			}
+			if got := slots.running[tt.namespace]; got != tt.wantAfter {
+				t.Fatalf("running = %d, want %d", got, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestStartWorkflowExecutionReleasesSlotWhenStartFails(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	slots := &memoryExecutionSlots{running: map[string]int64{}}
+	svc := NewService(&failingStartHistoryClient{StubHistoryClient{Logger: logger}}, &StubMatchingClient{Logger: logger}, logger, DefaultServiceConfig()).
+		WithConcurrencyLimits(memoryNamespaceConfigs{"limited": {Name: "limited", MaxConcurrentExecutions: 1}}, slots)
+
+	if _, err := svc.StartWorkflowExecution(context.Background(), &StartWorkflowExecutionRequest{Namespace: "limited", WorkflowID: "wf-1"}); err == nil {
+		t.Fatal("expected the failed start to return an error")
+	}
+	if got := slots.running["limited"]; got != 0 {
+		t.Fatalf("running = %d after a failed start, want the slot released", got)
+	}
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/linkflow/engine/internal/approval"
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/frontend"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
+)
+
+// maxApprovalPageSize bounds the approvals one list request returns.
+const maxApprovalPageSize = 500
+
+// DecideApprovalBody is the request body for deciding an approval. The
+// approver is the authenticated caller.
+type DecideApprovalBody struct {
+	Decision string `json:"decision"` // approve or reject
+	Comment  string `json:"comment,omitempty"`
+}
+
+// ApprovalResponse is an approval as returned by the API.
+type ApprovalResponse struct {
+	ID          string          `json:"id"`
+	WorkspaceID string          `json:"workspace_id"`
+	WorkflowID  string          `json:"workflow_id"`
+	RunID       string          `json:"run_id"`
+	NodeID      string          `json:"node_id"`
+	Title       string          `json:"title"`
+	Reason      string          `json:"reason,omitempty"`
+	Approvers   []string        `json:"approvers"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Status      string          `json:"status"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   *time.Time      `json:"expires_at,omitempty"`
+	DecidedBy   string          `json:"decided_by,omitempty"`
+	Comment     string          `json:"comment,omitempty"`
+	DecidedAt   *time.Time      `json:"decided_at,omitempty"`
+}
+
+func toApprovalResponse(a *approval.Approval) ApprovalResponse {
+	resp := ApprovalResponse{
+		ID:          a.ID,
+		WorkspaceID: a.Namespace,
+		WorkflowID:  a.WorkflowID,
+		RunID:       a.RunID,
+		NodeID:      a.NodeID,
+		Title:       a.Title,
+		Reason:      a.Reason,
+		Approvers:   a.Approvers,
+		Payload:     a.Payload,
+		Status:      string(a.Status),
+		CreatedAt:   a.CreatedAt,
+		DecidedBy:   a.DecidedBy,
+		Comment:     a.Comment,
+	}
+	if resp.Approvers == nil {
+		resp.Approvers = []string{}
+	}
+	if !a.ExpiresAt.IsZero() {
+		expiresAt := a.ExpiresAt
+		resp.ExpiresAt = &expiresAt
+	}
+	if !a.DecidedAt.IsZero() {
+		decidedAt := a.DecidedAt
+		resp.DecidedAt = &decidedAt
+	}
+	return resp
+}
+
+// GET /api/v1/approvals?workspace_id=... Lists the approvals of the caller's
+// workspace, filtered by status when given; limit caps the number returned.
+func (h *HTTPHandler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	workspaceID := query.Get("workspace_id")
+	if workspaceID == "" {
+		h.writeError(w, http.StatusBadRequest, "workspace_id is required")
+		return
+	}
+	if claims, ok := interceptor.ClaimsFromContext(r.Context()); !ok || claims.WorkspaceID != workspaceID {
+		h.writeError(w, http.StatusForbidden, "workspace_id is not the caller's workspace")
+		return
+	}
+
+	filter := approval.ListFilter{
+		Namespace: workspaceID,
+		Limit:     maxApprovalPageSize,
+	}
+	if raw := query.Get("status"); raw != "" {
+		status, ok := approval.ParseStatus(raw)
+		if !ok {
+			h.writeError(w, http.StatusBadRequest, "status must be pending, approved, rejected or expired")
+			return
+		}
+		filter.Status = status
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxApprovalPageSize {
+			h.writeError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	approvals, err := h.service.ListApprovals(r.Context(), filter)
+	if err != nil {
+		h.writeApprovalError(w, "", err)
+		return
+	}
+
+	resp := make([]ApprovalResponse, 0, len(approvals))
+	for _, a := range approvals {
+		resp = append(resp, toApprovalResponse(a))
+	}
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"approvals": resp})
+}
+
+// POST /api/v1/approvals/{id}/decide. Decides as the authenticated caller on
+// an approval of the caller's workspace.
+func (h *HTTPHandler) DecideApproval(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	claims, ok := interceptor.ClaimsFromContext(r.Context())
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "Missing bearer token")
+		return
+	}
+
+	var body DecideApprovalBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	decided, err := h.service.DecideApproval(r.Context(), &frontend.DecideApprovalRequest{
+		ID:        id,
+		Namespace: claims.WorkspaceID,
+		Decision:  body.Decision,
+		Approver:  controlplane.ClaimsIdentity(claims),
+		Comment:   body.Comment,
+	})
+	if err != nil {
+		h.writeApprovalError(w, id, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, toApprovalResponse(decided))
+}
+
+func (h *HTTPHandler) writeApprovalError(w http.ResponseWriter, id string, err error) {
+	switch {
+	case errors.Is(err, frontend.ErrInvalidApprovalDecision):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, frontend.ErrApproverNotAllowed):
+		h.writeError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, approval.ErrNotFound):
+		h.writeError(w, http.StatusNotFound, "approval not found")
+	case errors.Is(err, approval.ErrNotPending):
+		h.writeError(w, http.StatusConflict, "approval is no longer pending")
+	case errors.Is(err, frontend.ErrApprovalsDisabled):
+		h.writeError(w, http.StatusNotImplemented, err.Error())
+	default:
+		h.logger.Error("approval request failed",
+			slog.String("approval_id", id),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, "failed to process approval")
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/approval"
+	"github.com/linkflow/engine/internal/frontend"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
+)
+
+// staticTokenValidator accepts the tokens it holds claims for.
+type staticTokenValidator map[string]*interceptor.Claims
+
+func (v staticTokenValidator) ValidateToken(token string) (*interceptor.Claims, error) {
+	if claims, ok := v[token]; ok {
+		return claims, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// singleApprovalStore holds one pending approval and records list filters.
+type singleApprovalStore struct {
+	approval *approval.Approval
+	filter   approval.ListFilter
+}
+
+func (s *singleApprovalStore) CreateApproval(context.Context, *approval.Approval) error { return nil }
+
+func (s *singleApprovalStore) GetApproval(_ context.Context, id string) (*approval.Approval, error) {
+	if id != s.approval.ID {
+		return nil, approval.ErrNotFound
+	}
+	copied := *s.approval
+	return &copied, nil
+}
+
+func (s *singleApprovalStore) ListApprovals(_ context.Context, filter approval.ListFilter) ([]*approval.Approval, error) {
+	s.filter = filter
+	return []*approval.Approval{s.approval}, nil
+}
+
+func (s *singleApprovalStore) DecideApproval(_ context.Context, _ string, d approval.Decision) (*approval.Approval, error) {
+	s.approval.Status, s.approval.DecidedBy, s.approval.DecidedAt = d.Status, d.DecidedBy, d.DecidedAt
+	copied := *s.approval
+	return &copied, nil
+}
+
+func (s *singleApprovalStore) ReopenApproval(context.Context, string, approval.Status) error {
+	return nil
+}
+
+func TestApprovalRoutesAuthenticateTheCaller(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		token     string
+		body      string
+		want      int
+		decidedBy string
+	}{
+		{name: "list without token", method: http.MethodGet, path: "/api/v1/approvals?workspace_id=ws-1", want: http.StatusUnauthorized},
+		{name: "list with invalid token", method: http.MethodGet, path: "/api/v1/approvals?workspace_id=ws-1", token: "forged", want: http.StatusUnauthorized},
+		{name: "list without workspace", method: http.MethodGet, path: "/api/v1/approvals", token: "alice", want: http.StatusBadRequest},
+		{name: "list another workspace", method: http.MethodGet, path: "/api/v1/approvals?workspace_id=ws-2", token: "alice", want: http.StatusForbidden},
+		{name: "list own workspace", method: http.MethodGet, path: "/api/v1/approvals?workspace_id=ws-1", token: "alice", want: http.StatusOK},
+		{name: "decide without token", method: http.MethodPost, path: "/api/v1/approvals/a1/decide", body: `{"decision":"approve","approver":"alice"}`, want: http.StatusUnauthorized},
+		{name: "decide as someone not allowed", method: http.MethodPost, path: "/api/v1/approvals/a1/decide", token: "mallory", body: `{"decision":"approve","approver":"alice"}`, want: http.StatusForbidden},
+		{name: "decide in another workspace", method: http.MethodPost, path: "/api/v1/approvals/a1/decide", token: "eve", body: `{"decision":"approve"}`, want: http.StatusNotFound},
+		{name: "decide as the caller", method: http.MethodPost, path: "/api/v1/approvals/a1/decide", token: "alice", body: `{"decision":"approve","approver":"bob"}`, want: http.StatusOK, decidedBy: "alice"},
+	}
+	validator := staticTokenValidator{
+		"alice":   {Subject: "alice", WorkspaceID: "ws-1"},
+		"mallory": {Subject: "mallory", WorkspaceID: "ws-1"},
+		"eve":     {Subject: "alice", WorkspaceID: "ws-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			store := &singleApprovalStore{approval: &approval.Approval{
+				ID:        "a1",
+				Namespace: "ws-1",
+				TaskToken: "token-a1",
+				Approvers: []string{"alice", "bob"},
+				Status:    approval.StatusPending,
+				CreatedAt: time.Now(),
+				ExpiresAt: time.Now().Add(time.Hour),
+			}}
+			svc := frontend.NewService(&frontend.StubHistoryClient{Logger: logger}, &frontend.StubMatchingClient{Logger: logger}, logger, frontend.DefaultServiceConfig()).WithApprovals(store)
+			mux := http.NewServeMux()
+			NewHTTPHandler(svc, logger).WithTokenValidator(validator).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+			if tt.method == http.MethodGet && tt.want == http.StatusOK && store.filter.Namespace != "ws-1" {
+				t.Fatalf("listed namespace %q, want the caller's workspace", store.filter.Namespace)
+			}
+			if tt.decidedBy != "" {
+				var resp ApprovalResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if resp.DecidedBy != tt.decidedBy {
+					t.Fatalf("decided by %q, want %q", resp.DecidedBy, tt.decidedBy)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+const (
+	// MaxBatchStartItems caps the workflows one batch start request may
+	// carry.
+	MaxBatchStartItems = 1000
+
+	// batchStartConcurrency bounds the starts of one batch in flight at once.
+	batchStartConcurrency = 16
+
+	// batchCompensationTimeout bounds terminating the started items of a
+	// failed atomic batch. It runs even if the client has gone away.
+	batchCompensationTimeout = 30 * time.Second
+)
+
+// BatchStartItemResult is the outcome of one item of a batch start, in the
+// order the items were sent.
+type BatchStartItemResult struct {
+	Index       int    `json:"index"`
+	WorkflowID  string `json:"workflow_id"`
+	ExecutionID string `json:"execution_id,omitempty"`
+	RunID       string `json:"run_id,omitempty"`
+	Started     bool   `json:"started"`
+	// Status is the HTTP status a single start of the item would have got.
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Compensated reports that the item was started and then terminated
+	// because another item of an atomic batch failed.
+	Compensated bool `json:"compensated,omitempty"`
+}
+
+// BatchStartResponse is the multi-status response of a batch start.
+type BatchStartResponse struct {
+	Results     []BatchStartItemResult `json:"results"`
+	Started     int                    `json:"started"`
+	Failed      int                    `json:"failed"`
+	Compensated int                    `json:"compensated,omitempty"`
+}
+
+// POST /api/v1/workflows/execute/batch.
+//
+// The body is a JSON array of StartWorkflowRequest. Each item is started as
+// POST /api/v1/workflows/execute would start it, with its own idempotency key
+// and subject to its namespace's rate and concurrency limits, and the reply is
+// a 207 with one result per item.
+//
+// With ?atomic=true no item is started unless all are valid, no new item is
+// started once one fails, and the items already started are terminated. This
+// is best effort, not a transaction: started runs may have begun executing
+// before they are terminated, and a run whose termination fails is left
+// running and reported as started.
+func (h *HTTPHandler) StartWorkflowBatch(w http.ResponseWriter, r *http.Request) {
+	var items []StartWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		if err.Error() == "http: request body too large" {
+			h.writeError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+			return
+		}
+		h.writeError(w, http.StatusBadRequest, "Invalid request body: expected an array of workflow start requests")
+		return
+	}
+	if len(items) == 0 {
+		h.writeError(w, http.StatusBadRequest, "At least one workflow is required")
+		return
+	}
+	if len(items) > MaxBatchStartItems {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("A batch may start at most %d workflows", MaxBatchStartItems))
+		return
+	}
+	atomicBatch := false
+	if raw := r.URL.Query().Get("atomic"); raw != "" {
+		var err error
+		if atomicBatch, err = strconv.ParseBool(raw); err != nil {
+			h.writeError(w, http.StatusBadRequest, "atomic must be true or false")
+			return
+		}
+	}
+
+	results := make([]BatchStartItemResult, len(items))
+	valid := true
+	for i := range items {
+		results[i] = BatchStartItemResult{Index: i, WorkflowID: items[i].WorkflowID}
+		if msg := validateBatchStartItem(items, i); msg != "" {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = msg
+			valid = false
+		}
+	}
+	if atomicBatch && !valid {
+		for i := range results {
+			if results[i].Error == "" {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Error = "not started: another item of the atomic batch is invalid"
+			}
+		}
+		h.writeBatchStartResponse(w, results)
+		return
+	}
+
+	var aborted atomic.Bool
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchStartConcurrency)
+	for i := range items {
+		if results[i].Error != "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if atomicBatch && aborted.Load() {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Error = "not started: another item of the atomic batch failed"
+				return
+			}
+			h.startBatchItem(r.Context(), &items[i], &results[i])
+			if !results[i].Started {
+				aborted.Store(true)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if atomicBatch && aborted.Load() {
+		h.compensateBatch(r.Context(), items, results)
+	}
+	h.writeBatchStartResponse(w, results)
+}
+
+// validateBatchStartItem checks item i of a batch, returning why it cannot be
+// started or "". An idempotency key repeated within the batch is rejected
+// rather than deduplicated, since the items may differ.
+func validateBatchStartItem(items []StartWorkflowRequest, i int) string {
+	item := items[i]
+	if item.WorkspaceID == "" {
+		return "workspace_id is required"
+	}
+	if item.WorkflowID == "" {
+		return "workflow_id is required"
+	}
+	if item.IdempotencyKey != "" {
+		for j := 0; j < i; j++ {
+			if items[j].WorkspaceID == item.WorkspaceID && items[j].IdempotencyKey == item.IdempotencyKey {
+				return fmt.Sprintf("idempotency_key repeats item %d", j)
+			}
+		}
+	}
+	return ""
+}
+
+// startBatchItem starts one item the way StartWorkflow does and records the
+// outcome in result.
+func (h *HTTPHandler) startBatchItem(ctx context.Context, item *StartWorkflowRequest, result *BatchStartItemResult) {
+	if !h.service.RateLimiter().Allow(item.WorkspaceID) {
+		result.Status = http.StatusTooManyRequests
+		result.Error = "rate limit exceeded"
+		return
+	}
+
+	if item.ExecutionID == "" {
+		item.ExecutionID = generateExecutionID()
+	}
+	result.ExecutionID = item.ExecutionID
+	inputBytes, _ := json.Marshal(item.Input)
+	resp, err := h.service.StartWorkflowExecution(ctx, &frontend.StartWorkflowExecutionRequest{
+		Namespace:     item.WorkspaceID,
+		WorkflowID:    item.WorkflowID,
+		TaskQueue:     item.TaskQueue,
+		RequestID:     item.IdempotencyKey,
+		Input:         inputBytes,
+		CorrelationID: item.CorrelationID,
+	})
+	var limitErr *frontend.ConcurrencyLimitError
+	switch {
+	case errors.As(err, &limitErr):
+		result.Status = http.StatusTooManyRequests
+		result.Error = limitErr.Error()
+	case err != nil:
+		h.logger.Error("failed to start workflow in batch",
+			slog.String("workspace_id", item.WorkspaceID),
+			slog.String("workflow_id", item.WorkflowID),
+			slog.String("error", err.Error()),
+		)
+		result.Status = http.StatusInternalServerError
+		result.Error = err.Error()
+	default:
+		result.RunID = resp.RunID
+		result.Started = true
+		result.Status = http.StatusOK
+	}
+}
+
+// compensateBatch terminates the started items of a failed atomic batch.
+func (h *HTTPHandler) compensateBatch(ctx context.Context, items []StartWorkflowRequest, results []BatchStartItemResult) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), batchCompensationTimeout)
+	defer cancel()
+
+	for i := range results {
+		if !results[i].Started {
+			continue
+		}
+		err := h.service.TerminateWorkflowExecution(ctx, &frontend.TerminateWorkflowExecutionRequest{
+			Namespace:  items[i].WorkspaceID,
+			WorkflowID: items[i].WorkflowID,
+			RunID:      results[i].RunID,
+			Reason:     "compensated: another item of the atomic batch failed",
+		})
+		if err != nil {
+			h.logger.Error("failed to terminate workflow of failed atomic batch",
+				slog.String("workspace_id", items[i].WorkspaceID),
+				slog.String("workflow_id", items[i].WorkflowID),
+				slog.String("run_id", results[i].RunID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		results[i].Started = false
+		results[i].Compensated = true
+		results[i].Status = http.StatusFailedDependency
+	}
+}
+
+func (h *HTTPHandler) writeBatchStartResponse(w http.ResponseWriter, results []BatchStartItemResult) {
+	resp := BatchStartResponse{Results: results}
+	for _, result := range results {
+		switch {
+		case result.Started:
+			resp.Started++
+		case result.Compensated:
+			resp.Compensated++
+		default:
+			resp.Failed++
+		}
+	}
+	h.logger.Info("workflow batch started",
+		slog.Int("items", len(results)),
+		slog.Int("started", resp.Started),
+		slog.Int("failed", resp.Failed),
+		slog.Int("compensated", resp.Compensated),
+	)
+	h.writeJSON(w, http.StatusMultiStatus, resp)
+}
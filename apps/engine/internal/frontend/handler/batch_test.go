@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+// batchHistoryClient fails to start the workflows in fail and records the
+// runs it terminates.
+type batchHistoryClient struct {
+	frontend.StubHistoryClient
+	fail map[string]bool
+
+	mu         sync.Mutex
+	started    []string
+	terminated []string
+}
+
+func (c *batchHistoryClient) RecordEvent(_ context.Context, req *frontend.RecordEventRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case req.EventType == "WorkflowExecutionTerminated":
+		c.terminated = append(c.terminated, req.WorkflowID+"/"+req.RunID)
+	case c.fail[req.WorkflowID]:
+		return errors.New("history unavailable")
+	default:
+		c.started = append(c.started, req.WorkflowID+"/"+req.RunID)
+	}
+	return nil
+}
+
+func serveBatchStart(t *testing.T, history *batchHistoryClient, query, body string) (int, BatchStartResponse) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	history.Logger = logger
+	svc := frontend.NewService(history, &frontend.StubMatchingClient{Logger: logger}, logger, frontend.DefaultServiceConfig())
+	mux := http.NewServeMux()
+	NewHTTPHandler(svc, logger).RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/workflows/execute/batch"+query, strings.NewReader(body)))
+	var resp BatchStartResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec.Code, resp
+}
+
+func TestStartWorkflowBatch(t *testing.T) {
+	body := `[
+		{"workspace_id":"ws-1","workflow_id":"wf-a","idempotency_key":"key-a"},
+		{"workspace_id":"ws-1","workflow_id":"wf-b"},
+		{"workspace_id":"ws-1","workflow_id":"wf-c"},
+		{"workspace_id":"ws-1","workflow_id":"wf-d","idempotency_key":"key-a"},
+		{"workflow_id":"wf-e"}
+	]`
+	history := &batchHistoryClient{fail: map[string]bool{"wf-b": true}}
+	code, resp := serveBatchStart(t, history, "", body)
+	if code != http.StatusMultiStatus || len(resp.Results) != 5 {
+		t.Fatalf("status %d, results %+v", code, resp.Results)
+	}
+	if resp.Started != 2 || resp.Failed != 3 {
+		t.Fatalf("started %d, failed %d; want 2 and 3", resp.Started, resp.Failed)
+	}
+	// The idempotency key becomes the run ID, as for a single start.
+	if a := resp.Results[0]; !a.Started || a.RunID != "key-a" || a.Status != http.StatusOK {
+		t.Fatalf("item 0 = %+v", a)
+	}
+	if b := resp.Results[1]; b.Started || b.Status != http.StatusInternalServerError || b.Error == "" {
+		t.Fatalf("item 1 = %+v", b)
+	}
+	if d := resp.Results[3]; d.Status != http.StatusBadRequest || !strings.Contains(d.Error, "item 0") {
+		t.Fatalf("item 3 = %+v, want a repeated idempotency key", d)
+	}
+	if e := resp.Results[4]; e.Status != http.StatusBadRequest {
+		t.Fatalf("item 4 = %+v, want workspace_id required", e)
+	}
+	if len(history.terminated) != 0 {
+		t.Fatalf("a non-atomic batch terminated %v", history.terminated)
+	}
+}
+
+func TestStartWorkflowBatchAtomic(t *testing.T) {
+	// An invalid item fails the batch before anything starts.
+	history := &batchHistoryClient{}
+	code, resp := serveBatchStart(t, history, "?atomic=true", `[{"workspace_id":"ws-1","workflow_id":"wf-a"},{"workspace_id":"ws-1"}]`)
+	if code != http.StatusMultiStatus || resp.Started != 0 || resp.Failed != 2 || len(history.started) != 0 {
+		t.Fatalf("status %d, resp %+v, started %v", code, resp, history.started)
+	}
+	if a := resp.Results[0]; a.Status != http.StatusFailedDependency {
+		t.Fatalf("valid item = %+v, want failed dependency", a)
+	}
+
+	// A failed start terminates the items that did start. Items run
+	// concurrently, so the others either started and were terminated or
+	// were never started.
+	history = &batchHistoryClient{fail: map[string]bool{"wf-c": true}}
+	_, resp = serveBatchStart(t, history, "?atomic=true", `[
+		{"workspace_id":"ws-1","workflow_id":"wf-a"},
+		{"workspace_id":"ws-1","workflow_id":"wf-b"},
+		{"workspace_id":"ws-1","workflow_id":"wf-c"}
+	]`)
+	if resp.Started != 0 || resp.Failed+resp.Compensated != 3 || len(history.terminated) != len(history.started) || resp.Compensated != len(history.started) {
+		t.Fatalf("resp = %+v, started %v, terminated %v", resp, history.started, history.terminated)
+	}
+	if c := resp.Results[2]; c.Status != http.StatusInternalServerError {
+		t.Fatalf("failing item = %+v", c)
+	}
+	for _, result := range resp.Results[:2] {
+		compensated := result.Compensated && result.RunID != ""
+		skipped := !result.Compensated && result.RunID == ""
+		if result.Status != http.StatusFailedDependency || !compensated && !skipped {
+			t.Fatalf("item %d = %+v, want compensated or not started", result.Index, result)
+		}
+	}
+
+	if code, _ := serveBatchStart(t, &batchHistoryClient{}, "", `{"workspace_id":"ws-1"}`); code != http.StatusBadRequest {
+		t.Fatalf("object body status = %d, want 400", code)
+	}
+}
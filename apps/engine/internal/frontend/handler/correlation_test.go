@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+type correlationHistoryClient struct {
+	frontend.StubHistoryClient
+	req *frontend.ListExecutionsByCorrelationIDRequest
+}
+
+func (c *correlationHistoryClient) ListExecutionsByCorrelationID(_ context.Context, req *frontend.ListExecutionsByCorrelationIDRequest) (*frontend.ListExecutionsResponse, error) {
+	c.req = req
+	return &frontend.ListExecutionsResponse{}, nil
+}
+
+func TestListExecutionsByCorrelationIDRoute(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{name: "by correlation", path: "/api/v1/workspaces/ws-1/by-correlation/order-42", want: http.StatusOK},
+		{name: "unknown execution sub-resource", path: "/api/v1/workspaces/ws-1/executions/order/order-42", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			history := &correlationHistoryClient{StubHistoryClient: frontend.StubHistoryClient{Logger: logger}}
+			svc := frontend.NewService(history, &frontend.StubMatchingClient{Logger: logger}, logger, frontend.DefaultServiceConfig())
+			mux := http.NewServeMux()
+			NewHTTPHandler(svc, logger).RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+			if tt.want == http.StatusOK && (history.req.Namespace != "ws-1" || history.req.CorrelationID != "order-42") {
+				t.Fatalf("request = %+v, want ws-1/order-42", history.req)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultProbeTimeout bounds each dependency probe made by the readiness
+// check, so a hung dependency cannot hang the check itself.
+const DefaultProbeTimeout = 2 * time.Second
+
+// DependencyCheck probes a downstream dependency and returns an error when
+// it is unavailable.
+type DependencyCheck func(ctx context.Context) error
+
+// DependencyStatus is the result of probing one dependency.
+type DependencyStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessResponse is the body of the readiness check.
+type ReadinessResponse struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// GRPCHealthCheck probes a server's overall status through the standard
+// gRPC health service.
+func GRPCHealthCheck(conn grpc.ClientConnInterface) DependencyCheck {
+	client := healthpb.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("status %s", resp.GetStatus())
+		}
+		return nil
+	}
+}
+
+// RedisPingCheck probes Redis with PING.
+func RedisPingCheck(client redis.UniversalClient) DependencyCheck {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+}
+
+// WithDependencyCheck adds a dependency probed by the readiness check.
+func (h *HTTPHandler) WithDependencyCheck(name string, check DependencyCheck) *HTTPHandler {
+	if h.dependencies == nil {
+		h.dependencies = make(map[string]DependencyCheck)
+	}
+	h.dependencies[name] = check
+	return h
+}
+
+// WithProbeTimeout sets how long each dependency probe may take. Zero uses
+// DefaultProbeTimeout.
+func (h *HTTPHandler) WithProbeTimeout(timeout time.Duration) *HTTPHandler {
+	h.probeTimeout = timeout
+	return h
+}
+
+// Health is a cheap liveness check: it reports the process is serving
+// without touching any dependency.
+func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "healthy"})
+}
+
+// Ready probes every dependency concurrently and returns 503 with the
+// status of each when any of them is unavailable.
+func (h *HTTPHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	timeout := h.probeTimeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+
+	names := make([]string, 0, len(h.dependencies))
+	for name := range h.dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, check DependencyCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			results[i] = check(ctx)
+		}(i, h.dependencies[name])
+	}
+	wg.Wait()
+
+	resp := ReadinessResponse{
+		Status:       "ready",
+		Dependencies: make(map[string]DependencyStatus, len(names)),
+	}
+	status := http.StatusOK
+	for i, name := range names {
+		if err := results[i]; err != nil {
+			resp.Dependencies[name] = DependencyStatus{Status: "unavailable", Error: err.Error()}
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			h.logger.Warn("readiness probe failed",
+				slog.String("dependency", name),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		resp.Dependencies[name] = DependencyStatus{Status: "ok"}
+	}
+
+	h.writeJSON(w, status, resp)
+}
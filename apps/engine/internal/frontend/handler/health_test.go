@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newHealthTestHandler() *HTTPHandler {
+	return NewHTTPHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func serveReady(t *testing.T, h *HTTPHandler, path string) (int, ReadinessResponse) {
+	t.Helper()
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var resp ReadinessResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return rec.Code, resp
+}
+
+func TestReadyReportsEachDependency(t *testing.T) {
+	h := newHealthTestHandler().
+		WithDependencyCheck("history", func(context.Context) error { return nil }).
+		WithDependencyCheck("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	code, resp := serveReady(t, h, "/api/v1/healthz")
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", code)
+	}
+	if resp.Status != "unavailable" {
+		t.Fatalf("expected status unavailable, got %q", resp.Status)
+	}
+	if got := resp.Dependencies["history"]; got.Status != "ok" {
+		t.Fatalf("expected history ok, got %+v", got)
+	}
+	if got := resp.Dependencies["redis"]; got.Status != "unavailable" || got.Error != "connection refused" {
+		t.Fatalf("expected redis unavailable, got %+v", got)
+	}
+}
+
+func TestReadyTimesOutHungProbe(t *testing.T) {
+	h := newHealthTestHandler().
+		WithProbeTimeout(50*time.Millisecond).
+		WithDependencyCheck("matching", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	start := time.Now()
+	code, resp := serveReady(t, h, "/ready")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("readiness check took %s", elapsed)
+	}
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", code)
+	}
+	if got := resp.Dependencies["matching"]; got.Status != "unavailable" {
+		t.Fatalf("expected matching unavailable, got %+v", got)
+	}
+}
+
+func TestGRPCHealthCheck(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	h := newHealthTestHandler().WithDependencyCheck("history", GRPCHealthCheck(conn))
+	if code, resp := serveReady(t, h, "/api/v1/healthz"); code != http.StatusOK || resp.Status != "ready" {
+		t.Fatalf("expected ready, got %d %+v", code, resp)
+	}
+
+	healthServer.Shutdown()
+	code, resp := serveReady(t, h, "/api/v1/healthz")
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after shutdown, got %d", code)
+	}
+	if got := resp.Dependencies["history"]; got.Error != "status NOT_SERVING" {
+		t.Fatalf("expected NOT_SERVING, got %+v", got)
+	}
+}
@@ -2,23 +2,47 @@ package handler
 
 import (
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/linkflow/engine/internal/controlplane"
 	"github.com/linkflow/engine/internal/frontend"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	// MaxRequestBodySize limits request body to 1MB to prevent memory exhaustion.
 	MaxRequestBodySize = 1 << 20 // 1 MB
+
+	// AdminRole is the JWT role required to call admin endpoints when no
+	// Authorizer is configured.
+	AdminRole = "admin"
 )
 
+// TokenValidator validates bearer tokens for authenticated routes.
+type TokenValidator interface {
+	ValidateToken(token string) (*interceptor.Claims, error)
+}
+
 // Laravel will call these endpoints to interact with the engine.
 type HTTPHandler struct {
-	service *frontend.Service
-	logger  *slog.Logger
+	service        *frontend.Service
+	logger         *slog.Logger
+	tokenValidator TokenValidator
+	authorizer     controlplane.Authorizer
+	callbackSecret string
+	dependencies   map[string]DependencyCheck
+	probeTimeout   time.Duration
 }
 
 // NewHTTPHandler creates a new HTTP handler.
@@ -29,21 +53,67 @@ func NewHTTPHandler(service *frontend.Service, logger *slog.Logger) *HTTPHandler
 	}
 }
 
+// WithTokenValidator enables bearer-token authentication for admin and
+// approval routes, which reject all requests until a validator is configured.
+func (h *HTTPHandler) WithTokenValidator(validator TokenValidator) *HTTPHandler {
+	h.tokenValidator = validator
+	return h
+}
+
+// WithAuthorizer checks admin routes against the caller's control plane role
+// instead of the admin role in its token.
+func (h *HTTPHandler) WithAuthorizer(authorizer controlplane.Authorizer) *HTTPHandler {
+	h.authorizer = authorizer
+	return h
+}
+
 // RegisterRoutes registers all HTTP routes.
 func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	// Workflow execution endpoints - all wrapped with security middleware
 	mux.HandleFunc("POST /api/v1/workflows/execute", h.securityMiddleware(h.StartWorkflow))
+	mux.HandleFunc("POST /api/v1/workflows/execute/batch", h.securityMiddleware(h.StartWorkflowBatch))
 	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}", h.securityMiddleware(h.GetExecution))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/stats", h.securityMiddleware(h.GetExecutionStats))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/reset-points", h.securityMiddleware(h.ListResetPoints))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/describe", h.securityMiddleware(h.DescribeExecution))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/tree", h.securityMiddleware(h.GetExecutionTree))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/timeline", h.securityMiddleware(h.GetExecutionTimeline))
 	mux.HandleFunc("POST /api/v1/workspaces/{workspace_id}/executions/{execution_id}/cancel", h.securityMiddleware(h.CancelExecution))
 	mux.HandleFunc("POST /api/v1/workspaces/{workspace_id}/executions/{execution_id}/retry", h.securityMiddleware(h.RetryExecution))
 	mux.HandleFunc("POST /api/v1/workspaces/{workspace_id}/executions/{execution_id}/signal", h.securityMiddleware(h.SendSignal))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/progress/stream", h.securityMiddleware(h.StreamProgress))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/by-correlation/{correlation_id}", h.securityMiddleware(h.ListExecutionsByCorrelationID))
 
 	// List executions
 	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/executions", h.securityMiddleware(h.ListExecutions))
 
+	// Saved search queries
+	mux.HandleFunc("POST /api/v1/workspaces/{workspace_id}/search-queries", h.securityMiddleware(h.SaveSearchQuery))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/search-queries", h.securityMiddleware(h.ListSearchQueries))
+	mux.HandleFunc("GET /api/v1/workspaces/{workspace_id}/search-queries/{name}/executions", h.securityMiddleware(h.RunSearchQuery))
+
+	// Async activity callbacks - the task token authorizes the call
+	mux.HandleFunc("POST /api/v1/async-activities/{token}/complete", h.securityMiddleware(h.CompleteAsyncActivity))
+	mux.HandleFunc("POST /api/v1/async-activities/{token}/fail", h.securityMiddleware(h.FailAsyncActivity))
+
+	// Approval requests created by approval nodes - the caller's token names
+	// the approver and the workspace
+	mux.HandleFunc("GET /api/v1/approvals", h.securityMiddleware(h.authMiddleware(h.ListApprovals)))
+	mux.HandleFunc("POST /api/v1/approvals/{id}/decide", h.securityMiddleware(h.authMiddleware(h.DecideApproval)))
+
+	// Worker progress and completion posts - the callback signature authorizes the call
+	mux.HandleFunc("POST /api/v1/executions/progress", h.securityMiddleware(h.ReportProgress))
+	mux.HandleFunc("POST /api/v1/executions/callback", h.securityMiddleware(h.ReportProgress))
+
+	// Admin endpoints - require an authenticated admin token
+	mux.HandleFunc("POST /api/v1/admin/executions/{workspace_id}/{execution_id}/force-terminate", h.securityMiddleware(h.adminMiddleware(controlplane.PermissionForceTerminate, h.ForceTerminateExecution)))
+	mux.HandleFunc("DELETE /api/v1/admin/executions/{workspace_id}/{execution_id}", h.securityMiddleware(h.adminMiddleware(controlplane.PermissionDeleteExecution, h.DeleteExecution)))
+	mux.HandleFunc("GET /api/v1/admin/executions/{workspace_id}/{execution_id}/integrity", h.securityMiddleware(h.adminMiddleware(controlplane.PermissionViewAdmin, h.VerifyHistoryIntegrity)))
+
 	// Health check (no security middleware needed for health endpoints)
 	mux.HandleFunc("GET /health", h.Health)
 	mux.HandleFunc("GET /ready", h.Ready)
+	mux.HandleFunc("GET /api/v1/healthz", h.Ready)
 }
 
 // securityMiddleware adds security headers and request limits to handlers.
@@ -67,6 +137,101 @@ func (h *HTTPHandler) securityMiddleware(next http.HandlerFunc) http.HandlerFunc
 	}
 }
 
+// authMiddleware requires a valid bearer token and passes its claims to the
+// handler, which authorizes the caller from them. Any authenticated user may
+// reach the route.
+func (h *HTTPHandler) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.tokenValidator == nil {
+			h.writeError(w, http.StatusForbidden, "Authentication is not enabled")
+			return
+		}
+
+		claims, ok := h.authenticate(w, r)
+		if !ok {
+			return
+		}
+		next(w, r.WithContext(interceptor.ContextWithClaims(r.Context(), claims)))
+	}
+}
+
+// authenticate validates the request's bearer token, writing a 401 response
+// when it is missing or invalid.
+func (h *HTTPHandler) authenticate(w http.ResponseWriter, r *http.Request) (*interceptor.Claims, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		h.writeError(w, http.StatusUnauthorized, "Missing bearer token")
+		return nil, false
+	}
+
+	claims, err := h.tokenValidator.ValidateToken(strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, "Invalid token")
+		return nil, false
+	}
+	return claims, true
+}
+
+// adminMiddleware requires a valid bearer token whose identity holds
+// permission, or without an Authorizer, whose token carries the admin role.
+// The token is forwarded to the services the route calls, which authorize
+// their admin RPCs themselves.
+func (h *HTTPHandler) adminMiddleware(permission controlplane.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.tokenValidator == nil {
+			h.writeError(w, http.StatusForbidden, "Admin API is not enabled")
+			return
+		}
+
+		claims, ok := h.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		if h.authorizer != nil {
+			identity := controlplane.ClaimsIdentity(claims)
+			err := h.authorizer.Authorize(r.Context(), identity, permission)
+			if errors.Is(err, controlplane.ErrPermissionDenied) {
+				h.logger.Warn("admin endpoint access denied",
+					slog.String("subject", identity),
+					slog.String("path", r.URL.Path),
+					slog.String("permission", string(permission)),
+				)
+				h.writeError(w, http.StatusForbidden, "PERMISSION_DENIED: "+string(permission)+" required")
+				return
+			}
+			if err != nil {
+				h.logger.Error("admin endpoint authorization failed",
+					slog.String("subject", identity),
+					slog.String("error", err.Error()),
+				)
+				h.writeError(w, http.StatusServiceUnavailable, "Authorization unavailable")
+				return
+			}
+		} else if !hasRole(claims, AdminRole) {
+			h.logger.Warn("admin endpoint access denied",
+				slog.String("subject", claims.Subject),
+				slog.String("path", r.URL.Path),
+			)
+			h.writeError(w, http.StatusForbidden, "Admin role required")
+			return
+		}
+
+		ctx := interceptor.ContextWithClaims(r.Context(), claims)
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", r.Header.Get("Authorization"))
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func hasRole(claims *interceptor.Claims, role string) bool {
+	for _, r := range claims.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 // StartWorkflowRequest is the request to start a workflow.
 type StartWorkflowRequest struct {
 	WorkspaceID    string                 `json:"workspace_id"`
@@ -77,6 +242,7 @@ type StartWorkflowRequest struct {
 	TaskQueue      string                 `json:"task_queue,omitempty"`
 	Priority       int                    `json:"priority,omitempty"`
 	CallbackURL    string                 `json:"callback_url,omitempty"`
+	CorrelationID  string                 `json:"correlation_id,omitempty"`
 }
 
 // StartWorkflowResponse is the response from starting a workflow.
@@ -119,14 +285,18 @@ func (h *HTTPHandler) StartWorkflow(w http.ResponseWriter, r *http.Request) {
 	// Start the workflow
 	inputBytes, _ := json.Marshal(req.Input)
 	frontendReq := &frontend.StartWorkflowExecutionRequest{
-		Namespace:  req.WorkspaceID,
-		WorkflowID: req.WorkflowID,
-		TaskQueue:  req.TaskQueue,
-		RequestID:  req.IdempotencyKey,
-		Input:      inputBytes,
+		Namespace:     req.WorkspaceID,
+		WorkflowID:    req.WorkflowID,
+		TaskQueue:     req.TaskQueue,
+		RequestID:     req.IdempotencyKey,
+		Input:         inputBytes,
+		CorrelationID: req.CorrelationID,
 	}
 
 	resp, err := h.service.StartWorkflowExecution(ctx, frontendReq)
+	if h.writeConcurrencyLimitError(w, err) {
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to start workflow",
 			slog.String("workspace_id", req.WorkspaceID),
@@ -193,25 +363,463 @@ func (h *HTTPHandler) GetExecution(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, info)
 }
 
+// ExecutionStatsInfo is the HTTP representation of execution statistics.
+type ExecutionStatsInfo struct {
+	ExecutionID string           `json:"execution_id"`
+	RunID       string           `json:"run_id"`
+	Status      string           `json:"status"`
+	StartedAt   time.Time        `json:"started_at"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
+	DurationMS  int64            `json:"duration_ms"`
+	EventCount  int64            `json:"event_count"`
+	NodeCounts  map[string]int64 `json:"node_counts"`
+}
+
+// GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/stats.
+func (h *HTTPHandler) GetExecutionStats(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+
+	stats, err := h.service.GetExecutionStats(r.Context(), &frontend.GetExecutionStatsRequest{
+		Namespace:  workspaceID,
+		WorkflowID: executionID,
+		RunID:      r.URL.Query().Get("run_id"),
+	})
+	switch {
+	case errors.Is(err, frontend.ErrExecutionNotFound):
+		h.writeError(w, http.StatusNotFound, "execution not found")
+		return
+	case err != nil:
+		h.logger.Error("get execution stats failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, "failed to get execution stats")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, ExecutionStatsInfo{
+		ExecutionID: executionID,
+		RunID:       stats.RunID,
+		Status:      statusToString(stats.Status),
+		StartedAt:   stats.StartTime,
+		FinishedAt:  stats.CloseTime,
+		DurationMS:  stats.Duration.Milliseconds(),
+		EventCount:  stats.EventCount,
+		NodeCounts: map[string]int64{
+			"scheduled": stats.NodeCounts.Scheduled,
+			"running":   stats.NodeCounts.Running,
+			"completed": stats.NodeCounts.Completed,
+			"failed":    stats.NodeCounts.Failed,
+			"timed_out": stats.NodeCounts.TimedOut,
+		},
+	})
+}
+
+type ResetPointInfo struct {
+	EventID   int64     `json:"event_id"`
+	Label     string    `json:"label"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/reset-points.
+func (h *HTTPHandler) ListResetPoints(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+
+	points, err := h.service.ListResetPoints(r.Context(), &frontend.ListResetPointsRequest{
+		Namespace:  workspaceID,
+		WorkflowID: executionID,
+		RunID:      r.URL.Query().Get("run_id"),
+	})
+	switch {
+	case errors.Is(err, frontend.ErrExecutionNotFound):
+		h.writeError(w, http.StatusNotFound, "execution not found")
+		return
+	case err != nil:
+		h.logger.Error("list reset points failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, "failed to list reset points")
+		return
+	}
+
+	infos := make([]ResetPointInfo, 0, len(points))
+	for _, point := range points {
+		infos = append(infos, ResetPointInfo{
+			EventID:   point.EventID,
+			Label:     point.Label,
+			Timestamp: point.Timestamp,
+		})
+	}
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"execution_id": executionID,
+		"reset_points": infos,
+	})
+}
+
+type PendingActivityInfo struct {
+	ActivityID      string     `json:"activity_id"`
+	ActivityType    string     `json:"activity_type,omitempty"`
+	State           string     `json:"state"`
+	Attempt         int32      `json:"attempt"`
+	ScheduledAt     time.Time  `json:"scheduled_at"`
+	LastStartedAt   *time.Time `json:"last_started_at,omitempty"`
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at,omitempty"`
+	LastFailure     string     `json:"last_failure,omitempty"`
+	LastFailedAt    *time.Time `json:"last_failed_at,omitempty"`
+}
+
+type PendingTimerInfo struct {
+	TimerID string    `json:"timer_id"`
+	FiresAt time.Time `json:"fires_at"`
+}
+
+type VersionMarkerInfo struct {
+	ChangeID string `json:"change_id"`
+	Version  int32  `json:"version"`
+	EventID  int64  `json:"event_id"`
+}
+
+// ExecutionTreeNodeInfo is one execution in the workflow hierarchy view.
+type ExecutionTreeNodeInfo struct {
+	ExecutionID  string                  `json:"execution_id"`
+	RunID        string                  `json:"run_id"`
+	WorkflowType string                  `json:"workflow_type,omitempty"`
+	Status       string                  `json:"status"`
+	StartedAt    time.Time               `json:"started_at"`
+	FinishedAt   *time.Time              `json:"finished_at,omitempty"`
+	DurationMS   int64                   `json:"duration_ms,omitempty"`
+	Children     []ExecutionTreeNodeInfo `json:"children"`
+}
+
+// GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/tree.
+func (h *HTTPHandler) GetExecutionTree(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+
+	tree, err := h.service.GetExecutionTree(r.Context(), &frontend.GetExecutionTreeRequest{
+		Namespace:  workspaceID,
+		WorkflowID: executionID,
+		RunID:      r.URL.Query().Get("run_id"),
+	})
+	switch {
+	case errors.Is(err, frontend.ErrExecutionNotFound):
+		h.writeError(w, http.StatusNotFound, "execution not found")
+		return
+	case err != nil:
+		h.logger.Error("get execution tree failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, "failed to get execution tree")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"root":       executionTreeNodeInfo(tree.Root),
+		"node_count": tree.NodeCount,
+		"truncated":  tree.Truncated,
+	})
+}
+
+func executionTreeNodeInfo(node *frontend.ExecutionTreeNode) ExecutionTreeNodeInfo {
+	info := ExecutionTreeNodeInfo{
+		ExecutionID:  node.Execution.WorkflowID,
+		RunID:        node.Execution.RunID,
+		WorkflowType: node.Execution.WorkflowType,
+		Status:       statusToString(node.Execution.Status),
+		StartedAt:    node.Execution.StartTime,
+		FinishedAt:   node.Execution.CloseTime,
+		Children:     make([]ExecutionTreeNodeInfo, 0, len(node.Children)),
+	}
+	if node.Execution.CloseTime != nil {
+		info.DurationMS = node.Execution.CloseTime.Sub(node.Execution.StartTime).Milliseconds()
+	}
+	for _, child := range node.Children {
+		info.Children = append(info.Children, executionTreeNodeInfo(child))
+	}
+	return info
+}
+
+// TimelineNodeInfo is one node in an execution timeline. ScheduleToStartMS
+// is how long the node waited for a worker and DurationMS runs from its
+// start to its close.
+type TimelineNodeInfo struct {
+	NodeID            string          `json:"node_id"`
+	NodeType          string          `json:"node_type"`
+	NodeName          string          `json:"node_name,omitempty"`
+	Status            string          `json:"status"`
+	Sequence          int             `json:"sequence"`
+	Attempts          int             `json:"attempts"`
+	ScheduledAt       time.Time       `json:"scheduled_at"`
+	StartedAt         time.Time       `json:"started_at"`
+	CompletedAt       *time.Time      `json:"completed_at,omitempty"`
+	ScheduleToStartMS int64           `json:"schedule_to_start_ms"`
+	DurationMS        int64           `json:"duration_ms,omitempty"`
+	Output            json.RawMessage `json:"output,omitempty"`
+	Error             string          `json:"error,omitempty"`
+}
+
+// GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/timeline.
+func (h *HTTPHandler) GetExecutionTimeline(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+	runID := r.URL.Query().Get("run_id")
+
+	nodes, err := h.service.GetExecutionTimeline(r.Context(), workspaceID, executionID, runID)
+	switch {
+	case errors.Is(err, frontend.ErrExecutionNotFound):
+		h.writeError(w, http.StatusNotFound, "execution not found")
+		return
+	case err != nil:
+		h.logger.Error("get execution timeline failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, "failed to get execution timeline")
+		return
+	}
+
+	infos := make([]TimelineNodeInfo, 0, len(nodes))
+	for _, node := range nodes {
+		info := TimelineNodeInfo{
+			NodeID:      node.NodeID,
+			NodeType:    node.NodeType,
+			NodeName:    node.Name,
+			Status:      node.Status,
+			Sequence:    node.Sequence,
+			Attempts:    node.Attempts,
+			ScheduledAt: node.ScheduledAt,
+			StartedAt:   node.StartedAt,
+			CompletedAt: node.CompletedAt,
+			Error:       node.Error,
+
+			ScheduleToStartMS: node.StartedAt.Sub(node.ScheduledAt).Milliseconds(),
+		}
+		if node.CompletedAt != nil {
+			info.DurationMS = node.CompletedAt.Sub(node.StartedAt).Milliseconds()
+		}
+		if json.Valid(node.Output) {
+			info.Output = node.Output
+		}
+		infos = append(infos, info)
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"execution_id": executionID,
+		"run_id":       runID,
+		"nodes":        infos,
+	})
+}
+
+type ExecutionDescriptionInfo struct {
+	ExecutionID       string                `json:"execution_id"`
+	RunID             string                `json:"run_id"`
+	WorkflowType      string                `json:"workflow_type,omitempty"`
+	TaskQueue         string                `json:"task_queue,omitempty"`
+	Status            string                `json:"status"`
+	StartedAt         time.Time             `json:"started_at"`
+	FinishedAt        *time.Time            `json:"finished_at,omitempty"`
+	PendingActivities []PendingActivityInfo `json:"pending_activities"`
+	PendingTimers     []PendingTimerInfo    `json:"pending_timers"`
+	VersionMarkers    []VersionMarkerInfo   `json:"version_markers"`
+	// WaitingOn summarizes the pending work in plain sentences.
+	WaitingOn []string `json:"waiting_on"`
+}
+
+// GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/describe.
+func (h *HTTPHandler) DescribeExecution(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+
+	desc, err := h.service.DescribeExecution(r.Context(), &frontend.DescribeExecutionRequest{
+		Namespace:  workspaceID,
+		WorkflowID: executionID,
+		RunID:      r.URL.Query().Get("run_id"),
+	})
+	switch {
+	case errors.Is(err, frontend.ErrExecutionNotFound):
+		h.writeError(w, http.StatusNotFound, "execution not found")
+		return
+	case err != nil:
+		h.logger.Error("describe execution failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, "failed to describe execution")
+		return
+	}
+
+	info := ExecutionDescriptionInfo{
+		ExecutionID:       executionID,
+		RunID:             desc.Execution.RunID,
+		WorkflowType:      desc.Execution.WorkflowType,
+		TaskQueue:         desc.Execution.TaskQueue,
+		Status:            statusToString(desc.Execution.Status),
+		StartedAt:         desc.Execution.StartTime,
+		FinishedAt:        desc.Execution.CloseTime,
+		PendingActivities: make([]PendingActivityInfo, 0, len(desc.PendingActivities)),
+		PendingTimers:     make([]PendingTimerInfo, 0, len(desc.PendingTimers)),
+		VersionMarkers:    make([]VersionMarkerInfo, 0, len(desc.VersionMarkers)),
+		WaitingOn:         []string{},
+	}
+	for _, marker := range desc.VersionMarkers {
+		info.VersionMarkers = append(info.VersionMarkers, VersionMarkerInfo{
+			ChangeID: marker.ChangeID,
+			Version:  marker.Version,
+			EventID:  marker.EventID,
+		})
+	}
+	for _, timer := range desc.PendingTimers {
+		info.PendingTimers = append(info.PendingTimers, PendingTimerInfo{
+			TimerID: timer.TimerID,
+			FiresAt: timer.FireTime,
+		})
+		info.WaitingOn = append(info.WaitingOn, fmt.Sprintf("waiting on timer %s (fires at %s)",
+			timer.TimerID, timer.FireTime.UTC().Format(time.RFC3339)))
+	}
+	for _, activity := range desc.PendingActivities {
+		pending := PendingActivityInfo{
+			ActivityID:      activity.ActivityID,
+			ActivityType:    activity.ActivityType,
+			State:           "scheduled",
+			Attempt:         activity.Attempt,
+			ScheduledAt:     activity.ScheduledTime,
+			LastStartedAt:   activity.LastStartedTime,
+			LastHeartbeatAt: activity.LastHeartbeatTime,
+			LastFailedAt:    activity.LastFailureTime,
+		}
+		if activity.State == frontend.PendingActivityStateStarted {
+			pending.State = "running"
+		}
+		if activity.LastFailure != nil {
+			pending.LastFailure = activity.LastFailure.Message
+		}
+		info.PendingActivities = append(info.PendingActivities, pending)
+		info.WaitingOn = append(info.WaitingOn, describePendingActivity(pending))
+	}
+
+	h.writeJSON(w, http.StatusOK, info)
+}
+
+// describePendingActivity phrases a pending activity for the waiting_on list,
+// calling out activities that are being retried after failures.
+func describePendingActivity(activity PendingActivityInfo) string {
+	if activity.Attempt > 1 && activity.LastFailure != "" {
+		return fmt.Sprintf("activity %s failing repeatedly (attempt %d, last failure: %s)",
+			activity.ActivityID, activity.Attempt, activity.LastFailure)
+	}
+	return fmt.Sprintf("activity %s %s (attempt %d)", activity.ActivityID, activity.State, activity.Attempt)
+}
+
 // GET /api/v1/workspaces/{workspace_id}/executions.
+// Filtered by status and a visibility query; pages are requested with
+// page_size (capped at frontend.MaxListExecutionsPageSize) and the previous
+// next_page_token.
 func (h *HTTPHandler) ListExecutions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workspaceID := r.PathValue("workspace_id")
 
 	req := &frontend.ListExecutionsRequest{
 		Namespace: workspaceID,
-		PageSize:  100,
+		PageSize:  frontend.DefaultListExecutionsPageSize,
+		Status:    r.URL.Query().Get("status"),
+		Query:     r.URL.Query().Get("query"),
+	}
+	if raw := r.URL.Query().Get("page_size"); raw != "" {
+		pageSize, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || pageSize <= 0 {
+			h.writeError(w, http.StatusBadRequest, "page_size must be a positive integer")
+			return
+		}
+		req.PageSize = int32(min(pageSize, frontend.MaxListExecutionsPageSize))
+	}
+	if raw := r.URL.Query().Get("next_page_token"); raw != "" {
+		token, err := base64.URLEncoding.DecodeString(raw)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid next_page_token")
+			return
+		}
+		req.NextPageToken = token
 	}
 
 	resp, err := h.service.ListExecutions(ctx, req)
+	if errors.Is(err, frontend.ErrInvalidStatus) {
+		h.writeError(w, http.StatusBadRequest, "status must be one of: "+strings.Join(frontend.ListStatusFilters, ", "))
+		return
+	}
+	if errors.Is(err, frontend.ErrInvalidSearchQuery) {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"executions":      resp.Executions,
+		"next_page_token": base64.URLEncoding.EncodeToString(resp.NextPageToken),
+		"has_more":        len(resp.NextPageToken) > 0,
+	})
+}
+
+// GET /api/v1/workspaces/{workspace_id}/by-correlation/{correlation_id}.
+// Lists every run started with the correlation ID, in any status and most
+// recently started first; paged like ListExecutions.
+func (h *HTTPHandler) ListExecutionsByCorrelationID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceID := r.PathValue("workspace_id")
+
+	req := &frontend.ListExecutionsByCorrelationIDRequest{
+		Namespace:     workspaceID,
+		CorrelationID: r.PathValue("correlation_id"),
+		PageSize:      frontend.DefaultListExecutionsPageSize,
+	}
+	if raw := r.URL.Query().Get("page_size"); raw != "" {
+		pageSize, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || pageSize <= 0 {
+			h.writeError(w, http.StatusBadRequest, "page_size must be a positive integer")
+			return
+		}
+		req.PageSize = int32(min(pageSize, frontend.MaxListExecutionsPageSize))
+	}
+	if raw := r.URL.Query().Get("next_page_token"); raw != "" {
+		token, err := base64.URLEncoding.DecodeString(raw)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid next_page_token")
+			return
+		}
+		req.NextPageToken = token
+	}
+
+	resp, err := h.service.ListExecutionsByCorrelationID(ctx, req)
+	if errors.Is(err, frontend.ErrCorrelationIDRequired) {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
+		h.logger.Error("failed to list executions by correlation ID",
+			slog.String("workspace_id", workspaceID),
+			slog.String("correlation_id", req.CorrelationID),
+			slog.String("error", err.Error()),
+		)
 		h.writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	h.writeJSON(w, http.StatusOK, map[string]interface{}{
-		"executions": resp.Executions,
-		"has_more":   len(resp.NextPageToken) > 0,
+		"correlation_id":  req.CorrelationID,
+		"executions":      resp.Executions,
+		"next_page_token": base64.URLEncoding.EncodeToString(resp.NextPageToken),
+		"has_more":        len(resp.NextPageToken) > 0,
 	})
 }
 
@@ -240,6 +848,246 @@ func (h *HTTPHandler) CancelExecution(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, map[string]string{"status": "canceled"})
 }
 
+// ForceTerminateRequest is the request body for force-terminating an execution.
+type ForceTerminateRequest struct {
+	RunID  string `json:"run_id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// POST /api/v1/admin/executions/{workspace_id}/{execution_id}/force-terminate.
+func (h *HTTPHandler) ForceTerminateExecution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+
+	var body ForceTerminateRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if body.Reason == "" {
+		h.writeError(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+
+	identity := ""
+	if claims, ok := interceptor.ClaimsFromContext(ctx); ok {
+		identity = claims.Subject
+	}
+
+	h.logger.Warn("FORCE TERMINATE admin request",
+		slog.String("workspace_id", workspaceID),
+		slog.String("execution_id", executionID),
+		slog.String("run_id", body.RunID),
+		slog.String("identity", identity),
+		slog.String("reason", body.Reason),
+	)
+
+	req := &frontend.ForceTerminateExecutionRequest{
+		Namespace:  workspaceID,
+		WorkflowID: executionID,
+		RunID:      body.RunID,
+		Reason:     body.Reason,
+		Identity:   identity,
+	}
+
+	if err := h.service.ForceTerminateExecution(ctx, req); err != nil {
+		h.logger.Error("force terminate failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "terminated", "run_id": req.RunID})
+}
+
+// DELETE /api/v1/admin/executions/{workspace_id}/{execution_id}?run_id=...
+// Permanently deletes a closed execution. Omitting run_id deletes the current run.
+func (h *HTTPHandler) DeleteExecution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+
+	identity := ""
+	if claims, ok := interceptor.ClaimsFromContext(ctx); ok {
+		identity = claims.Subject
+	}
+
+	req := &frontend.DeleteExecutionRequest{
+		Namespace:  workspaceID,
+		WorkflowID: executionID,
+		RunID:      r.URL.Query().Get("run_id"),
+		Identity:   identity,
+	}
+
+	runID, err := h.service.DeleteWorkflowExecution(ctx, req)
+	switch {
+	case errors.Is(err, frontend.ErrExecutionNotFound):
+		h.writeError(w, http.StatusNotFound, "execution not found")
+		return
+	case errors.Is(err, frontend.ErrExecutionRunning):
+		h.writeError(w, http.StatusConflict, "execution is still running; terminate it before deleting")
+		return
+	case err != nil:
+		h.logger.Error("delete execution failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "deleted", "run_id": runID})
+}
+
+// GET /api/v1/admin/executions/{workspace_id}/{execution_id}/integrity?run_id=...
+// Checks the execution's history against its event hash chain. Omitting
+// run_id checks the current run.
+func (h *HTTPHandler) VerifyHistoryIntegrity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+
+	result, err := h.service.VerifyHistoryIntegrity(ctx, frontend.ExecutionKey{
+		NamespaceID: workspaceID,
+		WorkflowID:  executionID,
+		RunID:       r.URL.Query().Get("run_id"),
+	})
+	switch {
+	case errors.Is(err, frontend.ErrExecutionNotFound):
+		h.writeError(w, http.StatusNotFound, "execution not found")
+		return
+	case errors.Is(err, frontend.ErrIntegrityUnsupported):
+		h.writeError(w, http.StatusNotImplemented, err.Error())
+		return
+	case err != nil:
+		h.logger.Error("history integrity check failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if result.Hashed && !result.Intact {
+		h.logger.Warn("history integrity check found a broken hash chain",
+			slog.String("workspace_id", workspaceID),
+			slog.String("execution_id", executionID),
+			slog.String("run_id", result.RunID),
+			slog.Int64("broken_event_id", result.BrokenEventID),
+			slog.String("reason", result.Reason),
+		)
+	}
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// CompleteAsyncActivityBody is the request body for completing an async activity.
+type CompleteAsyncActivityBody struct {
+	Result   json.RawMessage `json:"result"`
+	Identity string          `json:"identity,omitempty"`
+}
+
+// FailAsyncActivityBody is the request body for failing an async activity.
+type FailAsyncActivityBody struct {
+	Reason   string `json:"reason"`
+	Details  string `json:"details,omitempty"`
+	Identity string `json:"identity,omitempty"`
+}
+
+// POST /api/v1/async-activities/{token}/complete.
+func (h *HTTPHandler) CompleteAsyncActivity(w http.ResponseWriter, r *http.Request) {
+	var body CompleteAsyncActivityBody
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if len(body.Result) == 0 {
+		body.Result = json.RawMessage("{}")
+	}
+
+	err := h.service.CompleteAsyncActivity(r.Context(), &frontend.CompleteAsyncActivityRequest{
+		TaskToken: r.PathValue("token"),
+		Result:    body.Result,
+		Identity:  body.Identity,
+	})
+	if err != nil {
+		h.writeAsyncActivityError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "completed"})
+}
+
+// POST /api/v1/async-activities/{token}/fail.
+func (h *HTTPHandler) FailAsyncActivity(w http.ResponseWriter, r *http.Request) {
+	var body FailAsyncActivityBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if body.Reason == "" {
+		h.writeError(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+
+	err := h.service.FailAsyncActivity(r.Context(), &frontend.FailAsyncActivityRequest{
+		TaskToken: r.PathValue("token"),
+		Reason:    body.Reason,
+		Details:   body.Details,
+		Identity:  body.Identity,
+	})
+	if err != nil {
+		h.writeAsyncActivityError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "failed"})
+}
+
+func (h *HTTPHandler) writeAsyncActivityError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, frontend.ErrInvalidTaskToken):
+		h.writeError(w, http.StatusNotFound, "unknown task token")
+	case errors.Is(err, frontend.ErrActivityNotPending):
+		h.writeError(w, http.StatusConflict, "activity is no longer pending")
+	default:
+		h.logger.Error("async activity callback failed", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusInternalServerError, "failed to record activity result")
+	}
+}
+
+// ConcurrencyLimitResponse is returned with 429 when a namespace is at its
+// MaxConcurrentExecutions.
+type ConcurrencyLimitResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Limit   int    `json:"limit"`
+	Running int64  `json:"running"`
+}
+
+// writeConcurrencyLimitError writes a 429 and returns true if err is a
+// namespace concurrency limit rejection.
+func (h *HTTPHandler) writeConcurrencyLimitError(w http.ResponseWriter, err error) bool {
+	var limitErr *frontend.ConcurrencyLimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	h.writeJSON(w, http.StatusTooManyRequests, ConcurrencyLimitResponse{
+		Error:   "concurrency_limit_exceeded",
+		Message: limitErr.Error(),
+		Limit:   limitErr.Limit,
+		Running: limitErr.Running,
+	})
+	return true
+}
+
 // RetryExecutionRequest contains optional retry configuration.
 type RetryExecutionRequest struct {
 	MaxAttempts int    `json:"max_attempts,omitempty"`
@@ -375,6 +1223,9 @@ func (h *HTTPHandler) RetryExecution(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp, err := h.service.StartWorkflowExecution(ctx, startReq)
+	if h.writeConcurrencyLimitError(w, err) {
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to start retry execution",
 			slog.String("workspace_id", workspaceID),
@@ -427,6 +1278,12 @@ func (h *HTTPHandler) SendSignal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.SignalWorkflowExecution(ctx, req); err != nil {
+		// History rejects signals over the execution's rate limit or
+		// signal buffer with RESOURCE_EXHAUSTED.
+		if status.Code(err) == codes.ResourceExhausted {
+			h.writeError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
 		h.writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -434,16 +1291,6 @@ func (h *HTTPHandler) SendSignal(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, map[string]string{"status": "signal_sent"})
 }
 
-// Health check endpoint.
-func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
-	h.writeJSON(w, http.StatusOK, map[string]string{"status": "healthy"})
-}
-
-// Ready check endpoint.
-func (h *HTTPHandler) Ready(w http.ResponseWriter, r *http.Request) {
-	h.writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
-}
-
 // Helper functions
 
 func (h *HTTPHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
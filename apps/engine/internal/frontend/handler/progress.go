@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+const (
+	// callbackSignatureTolerance bounds the age of a signed worker post, so
+	// a captured post cannot be replayed later.
+	callbackSignatureTolerance = 5 * time.Minute
+
+	// progressHeartbeatInterval keeps idle progress streams open through
+	// proxies that close silent connections.
+	progressHeartbeatInterval = 15 * time.Second
+)
+
+// WithCallbackSecret sets the CALLBACK_SECRET the worker signs its progress
+// and callback posts with. Those posts are rejected until it is set.
+func (h *HTTPHandler) WithCallbackSecret(secret string) *HTTPHandler {
+	h.callbackSecret = secret
+	return h
+}
+
+// WorkerProgressBody is a progress or completion post from the worker.
+type WorkerProgressBody struct {
+	JobID         string `json:"job_id"`
+	WorkspaceID   int    `json:"workspace_id"`
+	ExecutionID   int    `json:"execution_id"`
+	Status        string `json:"status"`
+	Progress      int    `json:"progress"`
+	CurrentNode   string `json:"current_node"`
+	PartialOutput string `json:"partial_output"`
+	Error         *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Nodes []struct {
+		NodeID      string `json:"node_id"`
+		NodeType    string `json:"node_type"`
+		NodeName    string `json:"node_name"`
+		Status      string `json:"status"`
+		StartedAt   string `json:"started_at"`
+		CompletedAt string `json:"completed_at"`
+		Error       *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"nodes"`
+}
+
+func (b *WorkerProgressBody) toProgress() *frontend.ExecutionProgress {
+	p := &frontend.ExecutionProgress{
+		Status:        b.Status,
+		Progress:      b.Progress,
+		CurrentNode:   b.CurrentNode,
+		PartialOutput: b.PartialOutput,
+	}
+	if b.WorkspaceID != 0 {
+		p.WorkspaceID = strconv.Itoa(b.WorkspaceID)
+	}
+	if b.ExecutionID != 0 {
+		p.ExecutionID = strconv.Itoa(b.ExecutionID)
+	}
+	if b.Error != nil {
+		p.Error = b.Error.Message
+	}
+	for _, n := range b.Nodes {
+		node := frontend.NodeProgress{
+			NodeID:      n.NodeID,
+			NodeType:    n.NodeType,
+			NodeName:    n.NodeName,
+			Status:      n.Status,
+			StartedAt:   n.StartedAt,
+			CompletedAt: n.CompletedAt,
+		}
+		if n.Error != nil {
+			node.Error = n.Error.Message
+		}
+		p.Nodes = append(p.Nodes, node)
+	}
+	return p
+}
+
+// POST /api/v1/executions/progress and POST /api/v1/executions/callback.
+// The worker posts progress while a job runs and a callback once it
+// completes or fails; both are signed with X-LinkFlow-Signature.
+func (h *HTTPHandler) ReportProgress(w http.ResponseWriter, r *http.Request) {
+	if h.callbackSecret == "" {
+		h.writeError(w, http.StatusForbidden, "Progress callbacks are not enabled")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.writeError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+			return
+		}
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := verifyCallbackSignature(r.Header, body, h.callbackSecret, time.Now()); err != nil {
+		h.logger.Warn("rejected worker progress post", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusUnauthorized, "Invalid signature")
+		return
+	}
+
+	var report WorkerProgressBody
+	if err := json.Unmarshal(body, &report); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	progress, err := h.service.RecordProgress(r.Context(), report.toProgress())
+	if err != nil {
+		h.writeProgressError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, progress)
+}
+
+// GET /api/v1/workspaces/{workspace_id}/executions/{execution_id}/progress/stream.
+// Streams the execution's progress as server-sent "progress" events, starting
+// with the latest one, until the execution completes or fails.
+func (h *HTTPHandler) StreamProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceID := r.PathValue("workspace_id")
+	executionID := r.PathValue("execution_id")
+
+	// Subscribe before reading the latest progress so nothing saved in
+	// between is lost.
+	updates, err := h.service.SubscribeProgress(ctx, workspaceID, executionID)
+	if err != nil {
+		h.writeProgressError(w, err)
+		return
+	}
+	latest, err := h.service.GetProgress(ctx, workspaceID, executionID)
+	if err != nil && !errors.Is(err, frontend.ErrProgressNotFound) {
+		h.writeProgressError(w, err)
+		return
+	}
+
+	// Streams outlive the server's write timeout.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(p *frontend.ExecutionProgress) bool {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if latest != nil {
+		if !send(latest) || latest.IsTerminal() {
+			return
+		}
+	} else if _, err := io.WriteString(w, ": waiting for progress\n\n"); err != nil || rc.Flush() != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(progressHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil || rc.Flush() != nil {
+				return
+			}
+		case p, ok := <-updates:
+			if !ok {
+				return
+			}
+			if !send(p) || p.IsTerminal() {
+				return
+			}
+		}
+	}
+}
+
+func (h *HTTPHandler) writeProgressError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, frontend.ErrInvalidProgress):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, frontend.ErrProgressNotFound):
+		h.writeError(w, http.StatusNotFound, "progress not found")
+	case errors.Is(err, frontend.ErrProgressDisabled):
+		h.writeError(w, http.StatusNotImplemented, err.Error())
+	default:
+		h.logger.Error("progress request failed", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// verifyCallbackSignature checks the worker's X-LinkFlow-Signature: the hex
+// HMAC-SHA256 of "<X-LinkFlow-Timestamp>.<body>" under secret.
+func verifyCallbackSignature(header http.Header, body []byte, secret string, now time.Time) error {
+	ts := header.Get("X-LinkFlow-Timestamp")
+	signed, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", ts)
+	}
+	if age := now.Sub(signed); age > callbackSignatureTolerance || age < -callbackSignatureTolerance {
+		return fmt.Errorf("timestamp %s is outside the allowed window", ts)
+	}
+
+	signature, err := hex.DecodeString(header.Get("X-LinkFlow-Signature"))
+	if err != nil || len(signature) == 0 {
+		return errors.New("missing or malformed signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+// SaveSearchQueryBody is the request body for saving a named search query.
+type SaveSearchQueryBody struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// SearchQueryResponse is a saved search query as returned by the API.
+type SearchQueryResponse struct {
+	Name      string    `json:"name"`
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toSearchQueryResponse(q *frontend.SavedSearchQuery) SearchQueryResponse {
+	return SearchQueryResponse{
+		Name:      q.Name,
+		Query:     q.Query,
+		CreatedAt: q.CreatedAt,
+		UpdatedAt: q.UpdatedAt,
+	}
+}
+
+// POST /api/v1/workspaces/{workspace_id}/search-queries.
+func (h *HTTPHandler) SaveSearchQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceID := r.PathValue("workspace_id")
+
+	var body SaveSearchQueryBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	saved, err := h.service.SaveSearchQuery(ctx, &frontend.SaveSearchQueryRequest{
+		Namespace: workspaceID,
+		Name:      body.Name,
+		Query:     body.Query,
+	})
+	if err != nil {
+		h.writeSearchQueryError(w, workspaceID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, toSearchQueryResponse(saved))
+}
+
+// GET /api/v1/workspaces/{workspace_id}/search-queries.
+func (h *HTTPHandler) ListSearchQueries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceID := r.PathValue("workspace_id")
+
+	queries, err := h.service.ListSearchQueries(ctx, workspaceID)
+	if err != nil {
+		h.writeSearchQueryError(w, workspaceID, err)
+		return
+	}
+
+	resp := make([]SearchQueryResponse, 0, len(queries))
+	for _, q := range queries {
+		resp = append(resp, toSearchQueryResponse(q))
+	}
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"search_queries": resp})
+}
+
+// GET /api/v1/workspaces/{workspace_id}/search-queries/{name}/executions.
+// Pages are requested with page_size and the previous next_page_token.
+func (h *HTTPHandler) RunSearchQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceID := r.PathValue("workspace_id")
+
+	req := &frontend.RunSearchQueryRequest{
+		Namespace: workspaceID,
+		Name:      r.PathValue("name"),
+		PageSize:  100,
+	}
+	if raw := r.URL.Query().Get("page_size"); raw != "" {
+		pageSize, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || pageSize <= 0 {
+			h.writeError(w, http.StatusBadRequest, "page_size must be a positive integer")
+			return
+		}
+		req.PageSize = int32(pageSize)
+	}
+	if raw := r.URL.Query().Get("next_page_token"); raw != "" {
+		token, err := base64.URLEncoding.DecodeString(raw)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid next_page_token")
+			return
+		}
+		req.NextPageToken = token
+	}
+
+	resp, err := h.service.RunSearchQuery(ctx, req)
+	if err != nil {
+		h.writeSearchQueryError(w, workspaceID, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"executions":      resp.Executions,
+		"next_page_token": base64.URLEncoding.EncodeToString(resp.NextPageToken),
+		"has_more":        len(resp.NextPageToken) > 0,
+	})
+}
+
+func (h *HTTPHandler) writeSearchQueryError(w http.ResponseWriter, workspaceID string, err error) {
+	switch {
+	case errors.Is(err, frontend.ErrInvalidSearchQuery):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, frontend.ErrSearchQueryNotFound):
+		h.writeError(w, http.StatusNotFound, "search query not found")
+	case errors.Is(err, frontend.ErrSearchQueriesDisabled):
+		h.writeError(w, http.StatusNotImplemented, err.Error())
+	default:
+		h.logger.Error("search query request failed",
+			slog.String("workspace_id", workspaceID),
+			slog.String("error", err.Error()),
+		)
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
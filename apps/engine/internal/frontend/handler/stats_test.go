@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+type statsHistoryClient struct {
+	frontend.StubHistoryClient
+	err error
+	req *frontend.GetExecutionStatsRequest
+}
+
+func (c *statsHistoryClient) GetExecutionStats(_ context.Context, req *frontend.GetExecutionStatsRequest) (*frontend.ExecutionStats, error) {
+	c.req = req
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &frontend.ExecutionStats{WorkflowID: req.WorkflowID, RunID: "run-1"}, nil
+}
+
+func TestGetExecutionStatsStatusCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "found", want: http.StatusOK},
+		{name: "not found", err: frontend.ErrExecutionNotFound, want: http.StatusNotFound},
+		{name: "history unavailable", err: errors.New("connection refused"), want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			history := &statsHistoryClient{StubHistoryClient: frontend.StubHistoryClient{Logger: logger}, err: tt.err}
+			svc := frontend.NewService(history, &frontend.StubMatchingClient{Logger: logger}, logger, frontend.DefaultServiceConfig())
+			mux := http.NewServeMux()
+			NewHTTPHandler(svc, logger).RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/workspaces/ws-1/executions/order/stats?run_id=run-1", nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+			if history.req.RunID != "run-1" {
+				t.Fatalf("run ID = %q, want the run_id query parameter", history.req.RunID)
+			}
+		})
+	}
+}
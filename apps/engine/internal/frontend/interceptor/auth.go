@@ -245,6 +245,11 @@ func base64URLDecode(s string) ([]byte, error) {
 
 type claimsContextKey struct{}
 
+// ContextWithClaims returns a copy of ctx carrying the given claims.
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
 func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
 	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
 	return claims, ok
@@ -0,0 +1,87 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// DeadlineConfig bounds the deadline requests run under.
+type DeadlineConfig struct {
+	// DefaultTimeout is applied to requests that arrive without a deadline
+	// (0 leaves them unbounded).
+	DefaultTimeout time.Duration
+
+	// MaxTimeout caps client-set deadlines (0 for no cap).
+	MaxTimeout time.Duration
+}
+
+// DeadlineInterceptor runs each request under the client's deadline, so
+// downstream history and matching calls made with the request context carry
+// the time the client has left and stop when the client gives up. Requests
+// whose deadline has already passed are rejected before the handler runs.
+type DeadlineInterceptor struct {
+	cfg DeadlineConfig
+}
+
+func NewDeadlineInterceptor(cfg DeadlineConfig) *DeadlineInterceptor {
+	return &DeadlineInterceptor{
+		cfg: cfg,
+	}
+}
+
+func (d *DeadlineInterceptor) UnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx, cancel := d.withDeadline(ctx)
+	defer cancel()
+
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	return handler(ctx, req)
+}
+
+func (d *DeadlineInterceptor) StreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, cancel := d.withDeadline(ss.Context())
+	defer cancel()
+
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+
+	return handler(srv, &deadlineServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// withDeadline applies the default timeout to requests without a deadline
+// and caps deadlines beyond the maximum timeout.
+func (d *DeadlineInterceptor) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	switch {
+	case !ok && d.cfg.DefaultTimeout > 0:
+		return context.WithTimeout(ctx, d.cfg.DefaultTimeout)
+	case ok && d.cfg.MaxTimeout > 0 && time.Until(deadline) > d.cfg.MaxTimeout:
+		return context.WithTimeout(ctx, d.cfg.MaxTimeout)
+	}
+	return ctx, func() {}
+}
+
+type deadlineServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *deadlineServerStream) Context() context.Context {
+	return s.ctx
+}
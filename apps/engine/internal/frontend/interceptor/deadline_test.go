@@ -0,0 +1,45 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeadlineInterceptor_AppliesClientDeadline(t *testing.T) {
+	d := NewDeadlineInterceptor(DeadlineConfig{DefaultTimeout: time.Second, MaxTimeout: time.Minute})
+	info := &grpc.UnaryServerInfo{FullMethod: "/linkflow.api.v1.WorkflowService/StartWorkflowExecution"}
+
+	var handled []time.Duration
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("handler ran without a deadline")
+		}
+		handled = append(handled, time.Until(deadline))
+		return nil, nil
+	}
+
+	// A client whose deadline already passed never reaches the handler.
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := d.UnaryInterceptor(expired, nil, info, handler); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expired deadline error = %v, want DeadlineExceeded", err)
+	}
+
+	// Deadlines beyond the maximum are capped; missing ones get the default.
+	long, cancelLong := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelLong()
+	for _, ctx := range []context.Context{long, context.Background()} {
+		if _, err := d.UnaryInterceptor(ctx, nil, info, handler); err != nil {
+			t.Fatalf("live client error = %v", err)
+		}
+	}
+	if len(handled) != 2 || handled[0] > time.Minute || handled[1] > time.Second {
+		t.Fatalf("handler deadlines = %v, want at most 1m and 1s", handled)
+	}
+}
@@ -0,0 +1,142 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Execution progress statuses. A completed or failed execution accepts no
+// further progress reports.
+const (
+	ProgressStatusRunning   = "running"
+	ProgressStatusCompleted = "completed"
+	ProgressStatusFailed    = "failed"
+)
+
+// ProgressStore keeps the latest progress of each execution and fans new
+// progress out to subscribers.
+type ProgressStore interface {
+	// SaveProgress stores p as the execution's latest progress and
+	// publishes it to the execution's subscribers.
+	SaveProgress(ctx context.Context, p *ExecutionProgress) error
+	// GetProgress returns ErrProgressNotFound if nothing was reported.
+	GetProgress(ctx context.Context, workspaceID, executionID string) (*ExecutionProgress, error)
+	// SubscribeProgress delivers progress saved after it returns until ctx
+	// is done, then closes the channel.
+	SubscribeProgress(ctx context.Context, workspaceID, executionID string) (<-chan *ExecutionProgress, error)
+}
+
+// WithProgressStore enables relaying worker progress to UI clients.
+func (s *Service) WithProgressStore(store ProgressStore) *Service {
+	s.progress = store
+	return s
+}
+
+// RecordProgress merges a worker report into the execution's latest progress
+// and publishes the result. Reports arriving after the execution completed or
+// failed are ignored.
+func (s *Service) RecordProgress(ctx context.Context, report *ExecutionProgress) (*ExecutionProgress, error) {
+	if s.progress == nil {
+		return nil, ErrProgressDisabled
+	}
+	if report.WorkspaceID == "" || report.ExecutionID == "" {
+		return nil, fmt.Errorf("%w: workspace_id and execution_id are required", ErrInvalidProgress)
+	}
+	switch report.Status {
+	case "", ProgressStatusRunning, ProgressStatusCompleted, ProgressStatusFailed:
+	default:
+		return nil, fmt.Errorf("%w: unsupported status %q", ErrInvalidProgress, report.Status)
+	}
+
+	latest, err := s.progress.GetProgress(ctx, report.WorkspaceID, report.ExecutionID)
+	if err != nil && !errors.Is(err, ErrProgressNotFound) {
+		return nil, err
+	}
+	merged, changed := mergeProgress(latest, report)
+	if !changed {
+		return merged, nil
+	}
+	merged.UpdatedAt = time.Now().UTC()
+	if err := s.progress.SaveProgress(ctx, merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// GetProgress returns the latest progress of an execution.
+func (s *Service) GetProgress(ctx context.Context, workspaceID, executionID string) (*ExecutionProgress, error) {
+	if s.progress == nil {
+		return nil, ErrProgressDisabled
+	}
+	return s.progress.GetProgress(ctx, workspaceID, executionID)
+}
+
+// SubscribeProgress streams an execution's progress until ctx is done.
+func (s *Service) SubscribeProgress(ctx context.Context, workspaceID, executionID string) (<-chan *ExecutionProgress, error) {
+	if s.progress == nil {
+		return nil, ErrProgressDisabled
+	}
+	return s.progress.SubscribeProgress(ctx, workspaceID, executionID)
+}
+
+// IsTerminal reports whether the execution has completed or failed.
+func (p *ExecutionProgress) IsTerminal() bool {
+	return p.Status == ProgressStatusCompleted || p.Status == ProgressStatusFailed
+}
+
+// mergeProgress applies report on top of latest, reporting false when the
+// report changes nothing because the execution already finished.
+func mergeProgress(latest, report *ExecutionProgress) (*ExecutionProgress, bool) {
+	if latest != nil && latest.IsTerminal() {
+		return latest, false
+	}
+
+	merged := &ExecutionProgress{
+		WorkspaceID: report.WorkspaceID,
+		ExecutionID: report.ExecutionID,
+		Status:      ProgressStatusRunning,
+	}
+	if latest != nil {
+		*merged = *latest
+		merged.Nodes = append([]NodeProgress(nil), latest.Nodes...)
+	}
+	if report.Status != "" {
+		merged.Status = report.Status
+	}
+	merged.Progress = report.Progress
+	if merged.Status == ProgressStatusCompleted {
+		merged.Progress = 100
+	}
+	if report.CurrentNode != "" {
+		merged.CurrentNode = report.CurrentNode
+	}
+	// Partial output belongs to the report that carried it; a later report
+	// for the node supersedes it.
+	merged.PartialOutput = report.PartialOutput
+	if report.Error != "" {
+		merged.Error = report.Error
+	}
+
+	index := make(map[string]int, len(merged.Nodes))
+	for i, node := range merged.Nodes {
+		index[node.NodeID] = i
+	}
+	for _, node := range report.Nodes {
+		if i, ok := index[node.NodeID]; ok {
+			merged.Nodes[i] = node
+			continue
+		}
+		index[node.NodeID] = len(merged.Nodes)
+		merged.Nodes = append(merged.Nodes, node)
+	}
+	// A progress report for a node the execution has not listed yet marks
+	// it running until the completion callback reports its final status.
+	if report.CurrentNode != "" && len(report.Nodes) == 0 {
+		if _, ok := index[report.CurrentNode]; !ok {
+			merged.Nodes = append(merged.Nodes, NodeProgress{NodeID: report.CurrentNode, Status: ProgressStatusRunning})
+		}
+	}
+	return merged, true
+}
@@ -0,0 +1,119 @@
+// Package progress stores worker-reported execution progress for the frontend.
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+const (
+	// DefaultTTL bounds how long progress outlives the execution's last report.
+	DefaultTTL = 24 * time.Hour
+
+	progressKeyPrefix     = "execution_progress:"
+	progressChannelPrefix = "execution_progress_updates:"
+)
+
+// RedisStore implements frontend.ProgressStore with one key per execution
+// holding its latest progress and a pub/sub channel carrying each update.
+type RedisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, ttl: DefaultTTL}
+}
+
+// WithTTL sets how long progress is kept after its last update.
+func (s *RedisStore) WithTTL(ttl time.Duration) *RedisStore {
+	if ttl > 0 {
+		s.ttl = ttl
+	}
+	return s
+}
+
+var _ frontend.ProgressStore = (*RedisStore)(nil)
+
+func (s *RedisStore) SaveProgress(ctx context.Context, p *frontend.ExecutionProgress) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode progress: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, progressKey(p.WorkspaceID, p.ExecutionID), data, s.ttl)
+	pipe.Publish(ctx, progressChannel(p.WorkspaceID, p.ExecutionID), data)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save progress: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) GetProgress(ctx context.Context, workspaceID, executionID string) (*frontend.ExecutionProgress, error) {
+	data, err := s.client.Get(ctx, progressKey(workspaceID, executionID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, frontend.ErrProgressNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	var p frontend.ExecutionProgress
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode progress: %w", err)
+	}
+	return &p, nil
+}
+
+func (s *RedisStore) SubscribeProgress(ctx context.Context, workspaceID, executionID string) (<-chan *frontend.ExecutionProgress, error) {
+	pubsub := s.client.Subscribe(ctx, progressChannel(workspaceID, executionID))
+	// Wait for the subscription so no update saved after we return is missed.
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to progress: %w", err)
+	}
+
+	updates := make(chan *frontend.ExecutionProgress, 16)
+	go func() {
+		defer close(updates)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var p frontend.ExecutionProgress
+				if err := json.Unmarshal([]byte(msg.Payload), &p); err != nil {
+					continue
+				}
+				select {
+				case updates <- &p:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return updates, nil
+}
+
+func progressKey(workspaceID, executionID string) string {
+	return progressKeyPrefix + workspaceID + ":" + executionID
+}
+
+func progressChannel(workspaceID, executionID string) string {
+	return progressChannelPrefix + workspaceID + ":" + executionID
+}
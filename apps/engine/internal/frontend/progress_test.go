@@ -0,0 +1,77 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+type memoryProgressStore struct {
+	latest map[string]*ExecutionProgress
+	saved  int
+}
+
+func (m *memoryProgressStore) SaveProgress(_ context.Context, p *ExecutionProgress) error {
+	m.latest[p.WorkspaceID+"/"+p.ExecutionID] = p
+	m.saved++
+	return nil
+}
+
+func (m *memoryProgressStore) GetProgress(_ context.Context, workspaceID, executionID string) (*ExecutionProgress, error) {
+	p, ok := m.latest[workspaceID+"/"+executionID]
+	if !ok {
+		return nil, ErrProgressNotFound
+	}
+	return p, nil
+}
+
+func (m *memoryProgressStore) SubscribeProgress(ctx context.Context, _, _ string) (<-chan *ExecutionProgress, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestRecordProgressMergesWorkerReports(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := &memoryProgressStore{latest: make(map[string]*ExecutionProgress)}
+	svc := NewService(&StubHistoryClient{Logger: logger}, nil, logger, DefaultServiceConfig()).WithProgressStore(store)
+
+	record := func(report *ExecutionProgress) *ExecutionProgress {
+		t.Helper()
+		report.WorkspaceID, report.ExecutionID = "7", "42"
+		p, err := svc.RecordProgress(ctx, report)
+		if err != nil {
+			t.Fatalf("record %+v: %v", report, err)
+		}
+		return p
+	}
+
+	record(&ExecutionProgress{Progress: 50, CurrentNode: "fetch"})
+	p := record(&ExecutionProgress{Progress: 50, CurrentNode: "notify", PartialOutput: "Hel"})
+	if p.Status != ProgressStatusRunning || len(p.Nodes) != 2 || p.Nodes[1].NodeID != "notify" || p.PartialOutput != "Hel" {
+		t.Fatalf("running progress = %+v", p)
+	}
+
+	p = record(&ExecutionProgress{
+		Status: ProgressStatusFailed,
+		Error:  "node 'notify' failed",
+		Nodes: []NodeProgress{
+			{NodeID: "fetch", Status: "completed"},
+			{NodeID: "notify", Status: "failed", Error: "timeout"},
+		},
+	})
+	if !p.IsTerminal() || p.CurrentNode != "notify" || p.PartialOutput != "" || len(p.Nodes) != 2 || p.Nodes[1].Error != "timeout" {
+		t.Fatalf("failed progress = %+v", p)
+	}
+
+	// A progress post delivered after the completion callback is dropped.
+	saved := store.saved
+	if p := record(&ExecutionProgress{Progress: 80, CurrentNode: "notify"}); p.Status != ProgressStatusFailed || store.saved != saved {
+		t.Fatalf("late report changed progress to %+v", p)
+	}
+
+	if _, err := svc.RecordProgress(ctx, &ExecutionProgress{ExecutionID: "42"}); !errors.Is(err, ErrInvalidProgress) {
+		t.Fatalf("report without workspace error = %v, want ErrInvalidProgress", err)
+	}
+}
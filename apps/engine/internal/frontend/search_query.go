@@ -0,0 +1,85 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/linkflow/engine/internal/history/visibility"
+)
+
+// maxSearchQueryNameLength matches the saved_search_queries.name column.
+const maxSearchQueryNameLength = 255
+
+// SearchQueryStore persists named visibility queries per namespace.
+type SearchQueryStore interface {
+	// SaveSearchQuery creates or replaces the query with the same name.
+	SaveSearchQuery(ctx context.Context, q *SavedSearchQuery) (*SavedSearchQuery, error)
+	// GetSearchQuery returns ErrSearchQueryNotFound if no query has the name.
+	GetSearchQuery(ctx context.Context, namespace, name string) (*SavedSearchQuery, error)
+	ListSearchQueries(ctx context.Context, namespace string) ([]*SavedSearchQuery, error)
+}
+
+// WithSearchQueries enables saved search queries backed by store.
+func (s *Service) WithSearchQueries(store SearchQueryStore) *Service {
+	s.searchQueries = store
+	return s
+}
+
+// SaveSearchQuery validates the query the way the visibility store applies it
+// and stores it under its name, so malformed or unsupported queries fail here
+// rather than when they are run.
+func (s *Service) SaveSearchQuery(ctx context.Context, req *SaveSearchQueryRequest) (*SavedSearchQuery, error) {
+	if s.searchQueries == nil {
+		return nil, ErrSearchQueriesDisabled
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidSearchQuery)
+	}
+	if len(name) > maxSearchQueryNameLength {
+		return nil, fmt.Errorf("%w: name exceeds %d characters", ErrInvalidSearchQuery, maxSearchQueryNameLength)
+	}
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidSearchQuery)
+	}
+	if _, err := visibility.CompileQuery(query); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSearchQuery, err)
+	}
+
+	return s.searchQueries.SaveSearchQuery(ctx, &SavedSearchQuery{
+		Namespace: req.Namespace,
+		Name:      name,
+		Query:     query,
+	})
+}
+
+// ListSearchQueries returns the saved queries of a namespace ordered by name.
+func (s *Service) ListSearchQueries(ctx context.Context, namespace string) ([]*SavedSearchQuery, error) {
+	if s.searchQueries == nil {
+		return nil, ErrSearchQueriesDisabled
+	}
+	return s.searchQueries.ListSearchQueries(ctx, namespace)
+}
+
+// RunSearchQuery resolves a saved query and lists the matching executions.
+// NextPageToken is passed through to ListExecutions, which pages by keyset.
+func (s *Service) RunSearchQuery(ctx context.Context, req *RunSearchQueryRequest) (*ListExecutionsResponse, error) {
+	if s.searchQueries == nil {
+		return nil, ErrSearchQueriesDisabled
+	}
+
+	saved, err := s.searchQueries.GetSearchQuery(ctx, req.Namespace, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ListExecutions(ctx, &ListExecutionsRequest{
+		Namespace:     req.Namespace,
+		PageSize:      req.PageSize,
+		NextPageToken: req.NextPageToken,
+		Query:         saved.Query,
+	})
+}
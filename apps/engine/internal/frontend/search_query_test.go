@@ -0,0 +1,125 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+type memorySearchQueryStore struct {
+	queries map[string]*SavedSearchQuery
+}
+
+func (m *memorySearchQueryStore) SaveSearchQuery(_ context.Context, q *SavedSearchQuery) (*SavedSearchQuery, error) {
+	m.queries[q.Namespace+"/"+q.Name] = q
+	return q, nil
+}
+
+func (m *memorySearchQueryStore) GetSearchQuery(_ context.Context, namespace, name string) (*SavedSearchQuery, error) {
+	q, ok := m.queries[namespace+"/"+name]
+	if !ok {
+		return nil, ErrSearchQueryNotFound
+	}
+	return q, nil
+}
+
+func (m *memorySearchQueryStore) ListSearchQueries(_ context.Context, namespace string) ([]*SavedSearchQuery, error) {
+	var out []*SavedSearchQuery
+	for _, q := range m.queries {
+		if q.Namespace == namespace {
+			out = append(out, q)
+		}
+	}
+	return out, nil
+}
+
+func newSearchQueryTestService() *Service {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := NewService(&StubHistoryClient{Logger: logger}, nil, logger, DefaultServiceConfig())
+	return svc.WithSearchQueries(&memorySearchQueryStore{queries: map[string]*SavedSearchQuery{}})
+}
+
+func TestSaveSearchQueryValidatesAtSaveTime(t *testing.T) {
+	svc := newSearchQueryTestService()
+	ctx := context.Background()
+
+	for _, req := range []*SaveSearchQueryRequest{
+		{Namespace: "ns", Name: "", Query: "WorkflowType = 'order'"},
+		{Namespace: "ns", Name: "empty", Query: "  "},
+		{Namespace: "ns", Name: "garbage", Query: "WorkflowType 'order'"},
+		{Namespace: "ns", Name: "half", Query: "ExecutionStatus = 'Running' AND bogus"},
+		{Namespace: "ns", Name: "unknown-field", Query: "Owner = 'alice'"},
+		{Namespace: "ns", Name: "ordered", Query: "WorkflowType = 'order' ORDER BY StartTime DESC"},
+	} {
+		if _, err := svc.SaveSearchQuery(ctx, req); !errors.Is(err, ErrInvalidSearchQuery) {
+			t.Errorf("SaveSearchQuery(%q, %q) error = %v, want ErrInvalidSearchQuery", req.Name, req.Query, err)
+		}
+	}
+
+	if _, err := svc.SaveSearchQuery(ctx, &SaveSearchQueryRequest{
+		Namespace: "ns",
+		Name:      "running-orders",
+		Query:     "ExecutionStatus = 'Running' AND WorkflowType = 'order'",
+	}); err != nil {
+		t.Fatalf("SaveSearchQuery valid query error = %v", err)
+	}
+
+	queries, err := svc.ListSearchQueries(ctx, "ns")
+	if err != nil || len(queries) != 1 {
+		t.Fatalf("ListSearchQueries = %v, %v; want one query", queries, err)
+	}
+}
+
+func TestRunSearchQuery(t *testing.T) {
+	svc := newSearchQueryTestService()
+	ctx := context.Background()
+
+	if _, err := svc.RunSearchQuery(ctx, &RunSearchQueryRequest{Namespace: "ns", Name: "missing"}); !errors.Is(err, ErrSearchQueryNotFound) {
+		t.Fatalf("RunSearchQuery missing error = %v, want ErrSearchQueryNotFound", err)
+	}
+
+	if _, err := svc.SaveSearchQuery(ctx, &SaveSearchQueryRequest{Namespace: "ns", Name: "failed", Query: "ExecutionStatus = 'Failed'"}); err != nil {
+		t.Fatalf("SaveSearchQuery error = %v", err)
+	}
+	if _, err := svc.RunSearchQuery(ctx, &RunSearchQueryRequest{Namespace: "ns", Name: "failed", PageSize: 10}); err != nil {
+		t.Fatalf("RunSearchQuery error = %v", err)
+	}
+
+	disabled := NewService(nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), DefaultServiceConfig())
+	if _, err := disabled.ListSearchQueries(ctx, "ns"); !errors.Is(err, ErrSearchQueriesDisabled) {
+		t.Fatalf("ListSearchQueries without store error = %v, want ErrSearchQueriesDisabled", err)
+	}
+}
+
+type listRecordingHistoryClient struct {
+	StubHistoryClient
+	last *ListExecutionsRequest
+}
+
+func (c *listRecordingHistoryClient) ListExecutions(ctx context.Context, req *ListExecutionsRequest) (*ListExecutionsResponse, error) {
+	c.last = req
+	return c.StubHistoryClient.ListExecutions(ctx, req)
+}
+
+func TestListExecutionsValidatesFilters(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := &listRecordingHistoryClient{StubHistoryClient: StubHistoryClient{Logger: logger}}
+	svc := NewService(client, nil, logger, DefaultServiceConfig())
+	ctx := context.Background()
+
+	if _, err := svc.ListExecutions(ctx, &ListExecutionsRequest{Namespace: "ns", Status: "sleeping"}); !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("ListExecutions bad status error = %v, want ErrInvalidStatus", err)
+	}
+	if _, err := svc.ListExecutions(ctx, &ListExecutionsRequest{Namespace: "ns", Query: "WorkflowType 'order'"}); !errors.Is(err, ErrInvalidSearchQuery) {
+		t.Fatalf("ListExecutions bad query error = %v, want ErrInvalidSearchQuery", err)
+	}
+
+	if _, err := svc.ListExecutions(ctx, &ListExecutionsRequest{Namespace: "ns", Status: "failed", Query: "WorkflowType = 'order'", PageSize: 50000}); err != nil {
+		t.Fatalf("ListExecutions error = %v", err)
+	}
+	if client.last.PageSize != MaxListExecutionsPageSize || client.last.Query != "WorkflowType = 'order'" {
+		t.Fatalf("forwarded request = %+v, want the query with a capped page size", client.last)
+	}
+}
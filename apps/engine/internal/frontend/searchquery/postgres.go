@@ -0,0 +1,82 @@
+// Package searchquery stores named visibility queries for the frontend.
+package searchquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/linkflow/engine/internal/frontend"
+)
+
+// PostgresStore implements frontend.SearchQueryStore on the
+// saved_search_queries table (see scripts/migrations).
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+var _ frontend.SearchQueryStore = (*PostgresStore)(nil)
+
+func (s *PostgresStore) SaveSearchQuery(ctx context.Context, q *frontend.SavedSearchQuery) (*frontend.SavedSearchQuery, error) {
+	saved := &frontend.SavedSearchQuery{Namespace: q.Namespace, Name: q.Name}
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO saved_search_queries (namespace_id, name, query, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (namespace_id, name) DO UPDATE SET
+			query = EXCLUDED.query, updated_at = NOW()
+		RETURNING query, created_at, updated_at
+	`, q.Namespace, q.Name, q.Query).Scan(&saved.Query, &saved.CreatedAt, &saved.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save search query: %w", err)
+	}
+	return saved, nil
+}
+
+func (s *PostgresStore) GetSearchQuery(ctx context.Context, namespace, name string) (*frontend.SavedSearchQuery, error) {
+	saved := &frontend.SavedSearchQuery{Namespace: namespace, Name: name}
+	err := s.pool.QueryRow(ctx, `
+		SELECT query, created_at, updated_at
+		FROM saved_search_queries
+		WHERE namespace_id = $1 AND name = $2
+	`, namespace, name).Scan(&saved.Query, &saved.CreatedAt, &saved.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, frontend.ErrSearchQueryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search query: %w", err)
+	}
+	return saved, nil
+}
+
+func (s *PostgresStore) ListSearchQueries(ctx context.Context, namespace string) ([]*frontend.SavedSearchQuery, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT name, query, created_at, updated_at
+		FROM saved_search_queries
+		WHERE namespace_id = $1
+		ORDER BY name
+	`, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list search queries: %w", err)
+	}
+	defer rows.Close()
+
+	queries := []*frontend.SavedSearchQuery{}
+	for rows.Next() {
+		q := &frontend.SavedSearchQuery{Namespace: namespace}
+		if err := rows.Scan(&q.Name, &q.Query, &q.CreatedAt, &q.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan search query: %w", err)
+		}
+		queries = append(queries, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list search queries: %w", err)
+	}
+	return queries, nil
+}
@@ -5,16 +5,33 @@ import (
 	"crypto/rand"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/linkflow/engine/internal/approval"
 	"github.com/linkflow/engine/internal/frontend/namespace"
 	"github.com/linkflow/engine/internal/frontend/ratelimit"
+	"github.com/linkflow/engine/internal/history/visibility"
 )
 
 type HistoryClient interface {
 	RecordEvent(ctx context.Context, req *RecordEventRequest) error
 	GetHistory(ctx context.Context, req *GetHistoryRequest) (*GetHistoryResponse, error)
 	GetMutableState(ctx context.Context, key ExecutionKey) (*MutableState, error)
+	ForceTerminateExecution(ctx context.Context, req *ForceTerminateExecutionRequest) error
+	DeleteExecution(ctx context.Context, req *DeleteExecutionRequest) (string, error)
+	VerifyHistoryIntegrity(ctx context.Context, key ExecutionKey) (*HistoryIntegrity, error)
+	CompleteAsyncActivity(ctx context.Context, req *CompleteAsyncActivityRequest) error
+	FailAsyncActivity(ctx context.Context, req *FailAsyncActivityRequest) error
+	GetExecutionStats(ctx context.Context, req *GetExecutionStatsRequest) (*ExecutionStats, error)
+	ListResetPoints(ctx context.Context, req *ListResetPointsRequest) ([]ResetPoint, error)
+	ListExecutions(ctx context.Context, req *ListExecutionsRequest) (*ListExecutionsResponse, error)
+	ListExecutionsByCorrelationID(ctx context.Context, req *ListExecutionsByCorrelationIDRequest) (*ListExecutionsResponse, error)
+	DescribeExecution(ctx context.Context, req *DescribeExecutionRequest) (*DescribeExecutionResponse, error)
+	GetExecutionTree(ctx context.Context, req *GetExecutionTreeRequest) (*ExecutionTree, error)
+	GetExecutionTimeline(ctx context.Context, req *GetExecutionTimelineRequest) ([]*TimelineNode, error)
 }
 
 type MatchingClient interface {
@@ -22,12 +39,25 @@ type MatchingClient interface {
 	PollTask(ctx context.Context, req *PollTaskRequest) (*Task, error)
 }
 
+// TimerClient lists the timers the timer service holds for an execution.
+type TimerClient interface {
+	ListPendingTimers(ctx context.Context, key ExecutionKey) ([]*PendingTimer, error)
+}
+
 type Service struct {
 	historyClient  HistoryClient
 	matchingClient MatchingClient
+	timerClient    TimerClient
 	namespaceCache *namespace.Cache
 	rateLimiter    *ratelimit.Limiter
 	logger         *slog.Logger
+
+	namespaceConfigs NamespaceConfigProvider
+	executionCounter ExecutionSlots
+
+	searchQueries SearchQueryStore
+	progress      ProgressStore
+	approvals     approval.Store
 }
 
 type ServiceConfig struct {
@@ -55,6 +85,13 @@ func NewService(
 	}
 }
 
+// WithTimerClient sets the client used to include timer service timers when
+// describing an execution.
+func (s *Service) WithTimerClient(client TimerClient) *Service {
+	s.timerClient = client
+	return s
+}
+
 func (s *Service) HistoryClient() HistoryClient {
 	return s.historyClient
 }
@@ -81,18 +118,32 @@ func (s *Service) StartWorkflowExecution(ctx context.Context, req *StartWorkflow
 		runID = generateRunID()
 	}
 
+	// A client that has already given up gets no execution started for it.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	acquired, err := s.acquireExecutionSlot(ctx, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	eventReq := &RecordEventRequest{
 		NamespaceID: req.Namespace,
 		WorkflowID:  req.WorkflowID,
 		RunID:       runID,
 		EventType:   "WorkflowExecutionStarted",
 		Attributes: &ExecutionStartedAttributes{
-			WorkflowType: req.WorkflowType,
-			TaskQueue:    req.TaskQueue,
-			Input:        req.Input,
+			WorkflowType:  req.WorkflowType,
+			TaskQueue:     req.TaskQueue,
+			Input:         req.Input,
+			CorrelationID: req.CorrelationID,
 		},
 	}
 	if err := s.historyClient.RecordEvent(ctx, eventReq); err != nil {
+		if acquired {
+			s.releaseExecutionSlot(ctx, req.Namespace)
+		}
 		return nil, err
 	}
 
@@ -120,7 +171,10 @@ func (s *Service) SignalWorkflowExecution(ctx context.Context, req *SignalWorkfl
 		WorkflowID:  req.WorkflowID,
 		RunID:       req.RunID,
 		EventType:   "WorkflowExecutionSignaled",
-		Attributes:  req.Input,
+		Attributes: &SignalReceivedAttributes{
+			SignalName: req.SignalName,
+			Input:      req.Input,
+		},
 	}
 	return s.historyClient.RecordEvent(ctx, eventReq)
 }
@@ -136,6 +190,104 @@ func (s *Service) TerminateWorkflowExecution(ctx context.Context, req *Terminate
 	return s.historyClient.RecordEvent(ctx, eventReq)
 }
 
+// ForceTerminateExecution is a break-glass operation that terminates an
+// execution in history without going through normal close logic.
+func (s *Service) ForceTerminateExecution(ctx context.Context, req *ForceTerminateExecutionRequest) error {
+	if req.RunID == "" {
+		state, err := s.historyClient.GetMutableState(ctx, ExecutionKey{
+			NamespaceID: req.Namespace,
+			WorkflowID:  req.WorkflowID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve run ID: %w", err)
+		}
+		if state.ExecutionInfo != nil {
+			req.RunID = state.ExecutionInfo.RunID
+		}
+	}
+
+	s.logger.Warn("FORCE TERMINATE requested via admin API",
+		slog.String("namespace", req.Namespace),
+		slog.String("workflow_id", req.WorkflowID),
+		slog.String("run_id", req.RunID),
+		slog.String("reason", req.Reason),
+		slog.String("identity", req.Identity),
+	)
+
+	return s.historyClient.ForceTerminateExecution(ctx, req)
+}
+
+// DeleteWorkflowExecution permanently deletes a closed execution from history
+// and visibility. It returns the deleted run ID.
+func (s *Service) DeleteWorkflowExecution(ctx context.Context, req *DeleteExecutionRequest) (string, error) {
+	s.logger.Warn("DELETE EXECUTION requested via admin API",
+		slog.String("namespace", req.Namespace),
+		slog.String("workflow_id", req.WorkflowID),
+		slog.String("run_id", req.RunID),
+		slog.String("identity", req.Identity),
+	)
+
+	return s.historyClient.DeleteExecution(ctx, req)
+}
+
+// VerifyHistoryIntegrity checks an execution's history against its event
+// hash chain. An empty RunID checks the current run.
+func (s *Service) VerifyHistoryIntegrity(ctx context.Context, key ExecutionKey) (*HistoryIntegrity, error) {
+	if key.RunID == "" {
+		state, err := s.historyClient.GetMutableState(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve run ID: %w", err)
+		}
+		if state.ExecutionInfo != nil {
+			key.RunID = state.ExecutionInfo.RunID
+		}
+	}
+	return s.historyClient.VerifyHistoryIntegrity(ctx, key)
+}
+
+// CompleteAsyncActivity feeds an external result back to a pending async activity.
+func (s *Service) CompleteAsyncActivity(ctx context.Context, req *CompleteAsyncActivityRequest) error {
+	if req.TaskToken == "" {
+		return ErrInvalidTaskToken
+	}
+	return s.historyClient.CompleteAsyncActivity(ctx, req)
+}
+
+// FailAsyncActivity reports an external failure for a pending async activity.
+func (s *Service) FailAsyncActivity(ctx context.Context, req *FailAsyncActivityRequest) error {
+	if req.TaskToken == "" {
+		return ErrInvalidTaskToken
+	}
+	return s.historyClient.FailAsyncActivity(ctx, req)
+}
+
+// GetExecutionStats returns summary statistics for an execution.
+func (s *Service) GetExecutionStats(ctx context.Context, req *GetExecutionStatsRequest) (*ExecutionStats, error) {
+	return s.historyClient.GetExecutionStats(ctx, req)
+}
+
+// ListResetPoints returns the workflow task boundaries an execution can be
+// reset to.
+func (s *Service) ListResetPoints(ctx context.Context, req *ListResetPointsRequest) ([]ResetPoint, error) {
+	return s.historyClient.ListResetPoints(ctx, req)
+}
+
+// GetExecutionTree returns an execution and the child workflows below it.
+func (s *Service) GetExecutionTree(ctx context.Context, req *GetExecutionTreeRequest) (*ExecutionTree, error) {
+	return s.historyClient.GetExecutionTree(ctx, req)
+}
+
+// GetExecutionTimeline returns the per-node timeline of an execution,
+// reconstructed from its history. It works for running and closed executions;
+// nodes still running have no completion time.
+func (s *Service) GetExecutionTimeline(ctx context.Context, namespace, workflowID, runID string) ([]*TimelineNode, error) {
+	return s.historyClient.GetExecutionTimeline(ctx, &GetExecutionTimelineRequest{
+		Namespace:  namespace,
+		WorkflowID: workflowID,
+		RunID:      runID,
+	})
+}
+
 func (s *Service) QueryWorkflow(ctx context.Context, req *QueryWorkflowRequest) (*QueryWorkflowResponse, error) {
 	key := ExecutionKey{
 		NamespaceID: req.Namespace,
@@ -172,50 +324,83 @@ func (s *Service) GetExecution(ctx context.Context, req *GetExecutionRequest) (*
 	}, nil
 }
 
+// ListExecutions lists the executions of a namespace, open ones unless
+// req.Status asks for closed or all executions. req.Query narrows the listing
+// and is rejected up front if the visibility store cannot apply it. The page
+// size is capped at MaxListExecutionsPageSize.
 func (s *Service) ListExecutions(ctx context.Context, req *ListExecutionsRequest) (*ListExecutionsResponse, error) {
-	return &ListExecutionsResponse{
-		Executions:    []*WorkflowExecution{},
-		NextPageToken: nil,
-	}, nil
+	if req.Status != "" && !slices.Contains(ListStatusFilters, req.Status) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStatus, req.Status)
+	}
+	if query := strings.TrimSpace(req.Query); query != "" {
+		if _, err := visibility.CompileQuery(query); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSearchQuery, err)
+		}
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = DefaultListExecutionsPageSize
+	} else if req.PageSize > MaxListExecutionsPageSize {
+		req.PageSize = MaxListExecutionsPageSize
+	}
+	return s.historyClient.ListExecutions(ctx, req)
 }
 
-func (s *Service) DescribeExecution(ctx context.Context, req *DescribeExecutionRequest) (*DescribeExecutionResponse, error) {
-	key := ExecutionKey{
-		NamespaceID: req.Namespace,
-		WorkflowID:  req.WorkflowID,
-		RunID:       req.RunID,
+// ListExecutionsByCorrelationID lists the runs started with a correlation
+// ID, most recently started first, whatever their status. The page size is
+// capped at MaxListExecutionsPageSize.
+func (s *Service) ListExecutionsByCorrelationID(ctx context.Context, req *ListExecutionsByCorrelationIDRequest) (*ListExecutionsResponse, error) {
+	if strings.TrimSpace(req.CorrelationID) == "" {
+		return nil, ErrCorrelationIDRequired
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = DefaultListExecutionsPageSize
+	} else if req.PageSize > MaxListExecutionsPageSize {
+		req.PageSize = MaxListExecutionsPageSize
 	}
+	return s.historyClient.ListExecutionsByCorrelationID(ctx, req)
+}
 
-	state, err := s.historyClient.GetMutableState(ctx, key)
+// DescribeExecution returns an execution with the activities and timers it is
+// waiting on. Timers from the timer service are added to those in the
+// execution's state when a timer client is configured.
+func (s *Service) DescribeExecution(ctx context.Context, req *DescribeExecutionRequest) (*DescribeExecutionResponse, error) {
+	resp, err := s.historyClient.DescribeExecution(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	pendingActivities := make([]*PendingActivity, 0, len(state.ActivityInfos))
-	for _, info := range state.ActivityInfos {
-		pendingActivities = append(pendingActivities, &PendingActivity{
-			ActivityID:    info.ActivityID,
-			ActivityType:  info.ActivityType,
-			ScheduledTime: info.ScheduledTime,
-			Attempt:       info.Attempt,
-		})
+	if s.timerClient == nil || resp.Execution == nil || resp.Execution.Status != ExecutionStatusRunning {
+		return resp, nil
 	}
 
-	pendingChildren := make([]*PendingChildExecution, 0, len(state.ChildExecutions))
-	for _, child := range state.ChildExecutions {
-		pendingChildren = append(pendingChildren, &PendingChildExecution{
-			WorkflowID:   child.WorkflowID,
-			RunID:        child.RunID,
-			WorkflowType: child.WorkflowType,
-			InitiatedID:  child.InitiatedID,
-		})
+	key := ExecutionKey{
+		NamespaceID: req.Namespace,
+		WorkflowID:  req.WorkflowID,
+		RunID:       resp.Execution.RunID,
+	}
+	timers, err := s.timerClient.ListPendingTimers(ctx, key)
+	if err != nil {
+		// The execution is still worth describing without them.
+		s.logger.Warn("failed to list pending timers",
+			slog.String("workflow_id", req.WorkflowID),
+			slog.String("error", err.Error()),
+		)
+		return resp, nil
 	}
 
-	return &DescribeExecutionResponse{
-		Execution:         state.ExecutionInfo,
-		PendingActivities: pendingActivities,
-		PendingChildExecs: pendingChildren,
-	}, nil
+	seen := make(map[string]bool, len(resp.PendingTimers))
+	for _, timer := range resp.PendingTimers {
+		seen[timer.TimerID] = true
+	}
+	for _, timer := range timers {
+		if !seen[timer.TimerID] {
+			resp.PendingTimers = append(resp.PendingTimers, timer)
+		}
+	}
+	sort.Slice(resp.PendingTimers, func(i, j int) bool {
+		return resp.PendingTimers[i].FireTime.Before(resp.PendingTimers[j].FireTime)
+	})
+	return resp, nil
 }
 
 func generateRunID() string {
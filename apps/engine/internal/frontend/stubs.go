@@ -28,6 +28,88 @@ func (c *StubHistoryClient) GetMutableState(ctx context.Context, key ExecutionKe
 	}, nil
 }
 
+func (c *StubHistoryClient) ForceTerminateExecution(ctx context.Context, req *ForceTerminateExecutionRequest) error {
+	c.Logger.Warn("STUB: ForceTerminateExecution", "namespace", req.Namespace, "workflow_id", req.WorkflowID, "run_id", req.RunID)
+	return nil
+}
+
+func (c *StubHistoryClient) DeleteExecution(ctx context.Context, req *DeleteExecutionRequest) (string, error) {
+	c.Logger.Warn("STUB: DeleteExecution", "namespace", req.Namespace, "workflow_id", req.WorkflowID, "run_id", req.RunID)
+	return req.RunID, nil
+}
+
+func (c *StubHistoryClient) VerifyHistoryIntegrity(ctx context.Context, key ExecutionKey) (*HistoryIntegrity, error) {
+	c.Logger.Info("STUB: VerifyHistoryIntegrity", "namespace", key.NamespaceID, "workflow_id", key.WorkflowID, "run_id", key.RunID)
+	return &HistoryIntegrity{NamespaceID: key.NamespaceID, WorkflowID: key.WorkflowID, RunID: key.RunID}, nil
+}
+
+func (c *StubHistoryClient) CompleteAsyncActivity(ctx context.Context, req *CompleteAsyncActivityRequest) error {
+	c.Logger.Info("STUB: CompleteAsyncActivity")
+	return nil
+}
+
+func (c *StubHistoryClient) FailAsyncActivity(ctx context.Context, req *FailAsyncActivityRequest) error {
+	c.Logger.Info("STUB: FailAsyncActivity", "reason", req.Reason)
+	return nil
+}
+
+func (c *StubHistoryClient) GetExecutionStats(ctx context.Context, req *GetExecutionStatsRequest) (*ExecutionStats, error) {
+	c.Logger.Info("STUB: GetExecutionStats")
+	return &ExecutionStats{
+		WorkflowID: req.WorkflowID,
+		RunID:      req.RunID,
+		Status:     ExecutionStatusRunning,
+	}, nil
+}
+
+func (c *StubHistoryClient) ListResetPoints(ctx context.Context, req *ListResetPointsRequest) ([]ResetPoint, error) {
+	c.Logger.Info("STUB: ListResetPoints")
+	return []ResetPoint{}, nil
+}
+
+func (c *StubHistoryClient) GetExecutionTree(ctx context.Context, req *GetExecutionTreeRequest) (*ExecutionTree, error) {
+	c.Logger.Info("STUB: GetExecutionTree", "workflow_id", req.WorkflowID)
+	return &ExecutionTree{
+		Root: &ExecutionTreeNode{
+			Execution: &WorkflowExecution{
+				WorkflowID: req.WorkflowID,
+				RunID:      req.RunID,
+				Status:     ExecutionStatusRunning,
+			},
+		},
+		NodeCount: 1,
+	}, nil
+}
+
+func (c *StubHistoryClient) GetExecutionTimeline(ctx context.Context, req *GetExecutionTimelineRequest) ([]*TimelineNode, error) {
+	c.Logger.Info("STUB: GetExecutionTimeline", "workflow_id", req.WorkflowID)
+	return []*TimelineNode{}, nil
+}
+
+func (c *StubHistoryClient) ListExecutions(ctx context.Context, req *ListExecutionsRequest) (*ListExecutionsResponse, error) {
+	c.Logger.Info("STUB: ListExecutions", "namespace", req.Namespace, "status", req.Status)
+	return &ListExecutionsResponse{Executions: []*WorkflowExecution{}}, nil
+}
+
+func (c *StubHistoryClient) ListExecutionsByCorrelationID(ctx context.Context, req *ListExecutionsByCorrelationIDRequest) (*ListExecutionsResponse, error) {
+	c.Logger.Info("STUB: ListExecutionsByCorrelationID", "namespace", req.Namespace, "correlation_id", req.CorrelationID)
+	return &ListExecutionsResponse{Executions: []*WorkflowExecution{}}, nil
+}
+
+func (c *StubHistoryClient) DescribeExecution(ctx context.Context, req *DescribeExecutionRequest) (*DescribeExecutionResponse, error) {
+	c.Logger.Info("STUB: DescribeExecution", "workflow_id", req.WorkflowID)
+	return &DescribeExecutionResponse{
+		Execution: &WorkflowExecution{
+			WorkflowID: req.WorkflowID,
+			RunID:      req.RunID,
+			Status:     ExecutionStatusRunning,
+		},
+		PendingActivities: []*PendingActivity{},
+		PendingTimers:     []*PendingTimer{},
+		PendingChildExecs: []*PendingChildExecution{},
+	}, nil
+}
+
 type StubMatchingClient struct {
 	Logger *slog.Logger
 }
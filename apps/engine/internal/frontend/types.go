@@ -1,9 +1,32 @@
 package frontend
 
 import (
+	"errors"
 	"time"
 )
 
+var (
+	ErrInvalidTaskToken      = errors.New("invalid task token")
+	ErrActivityNotPending    = errors.New("activity is not pending")
+	ErrExecutionNotFound     = errors.New("execution not found")
+	ErrExecutionRunning      = errors.New("execution is still running")
+	ErrInvalidStatus         = errors.New("invalid execution status filter")
+	ErrCorrelationIDRequired = errors.New("correlation ID is required")
+	ErrIntegrityUnsupported  = errors.New("history event store does not record hash chains")
+
+	ErrSearchQueryNotFound   = errors.New("search query not found")
+	ErrInvalidSearchQuery    = errors.New("invalid search query")
+	ErrSearchQueriesDisabled = errors.New("saved search queries are not configured")
+
+	ErrProgressNotFound = errors.New("execution progress not found")
+	ErrInvalidProgress  = errors.New("invalid execution progress")
+	ErrProgressDisabled = errors.New("execution progress is not configured")
+
+	ErrInvalidApprovalDecision = errors.New("invalid approval decision")
+	ErrApproverNotAllowed      = errors.New("approver is not allowed to decide this approval")
+	ErrApprovalsDisabled       = errors.New("approvals are not configured")
+)
+
 type ExecutionKey struct {
 	NamespaceID string
 	WorkflowID  string
@@ -23,6 +46,10 @@ type StartWorkflowExecutionRequest struct {
 	RetryPolicy              *RetryPolicy
 	Memo                     map[string][]byte
 	SearchAttributes         map[string][]byte
+	// CorrelationID is the caller's own identifier for the run, such as an
+	// order ID. It is indexed apart from search attributes and may be shared
+	// by several runs.
+	CorrelationID string
 }
 
 type StartWorkflowExecutionResponse struct {
@@ -46,6 +73,148 @@ type TerminateWorkflowExecutionRequest struct {
 	Details    []byte
 }
 
+// ForceTerminateExecutionRequest is an admin request to terminate an execution
+// that normal termination cannot clear.
+type ForceTerminateExecutionRequest struct {
+	Namespace  string
+	WorkflowID string
+	RunID      string
+	Reason     string
+	Identity   string
+}
+
+// DeleteExecutionRequest is an admin request to permanently delete a closed
+// execution, e.g. for tenant offboarding or erasure requests.
+type DeleteExecutionRequest struct {
+	Namespace  string
+	WorkflowID string
+	RunID      string
+	Identity   string
+}
+
+// HistoryIntegrity is the outcome of checking an execution's event hash
+// chain. Hashed is false for histories of namespaces that do not chain
+// hashes; otherwise BrokenEventID is the first event whose link does not
+// hold when the history is not Intact.
+type HistoryIntegrity struct {
+	NamespaceID       string `json:"namespace_id"`
+	WorkflowID        string `json:"workflow_id"`
+	RunID             string `json:"run_id"`
+	Events            int64  `json:"events"`
+	HashedEvents      int64  `json:"hashed_events"`
+	ChainStartEventID int64  `json:"chain_start_event_id,omitempty"`
+	Hashed            bool   `json:"hashed"`
+	Intact            bool   `json:"intact"`
+	BrokenEventID     int64  `json:"broken_event_id,omitempty"`
+	Reason            string `json:"reason,omitempty"`
+}
+
+// CompleteAsyncActivityRequest completes an activity that is waiting on an
+// external callback.
+type CompleteAsyncActivityRequest struct {
+	TaskToken string
+	Result    []byte
+	Identity  string
+}
+
+// FailAsyncActivityRequest fails an activity that is waiting on an external
+// callback.
+type FailAsyncActivityRequest struct {
+	TaskToken string
+	Reason    string
+	Details   string
+	Identity  string
+}
+
+// GetExecutionStatsRequest requests summary statistics for an execution.
+type GetExecutionStatsRequest struct {
+	Namespace  string
+	WorkflowID string
+	RunID      string
+}
+
+// ExecutionStats summarizes an execution's timing, history size and nodes.
+type ExecutionStats struct {
+	WorkflowID string
+	RunID      string
+	Status     ExecutionStatus
+	StartTime  time.Time
+	CloseTime  *time.Time
+	Duration   time.Duration
+	EventCount int64
+	NodeCounts NodeStatusCounts
+}
+
+// NodeStatusCounts counts scheduled node attempts by their latest status.
+type NodeStatusCounts struct {
+	Scheduled int64
+	Running   int64
+	Completed int64
+	Failed    int64
+	TimedOut  int64
+}
+
+// ListResetPointsRequest requests the points an execution can be reset to.
+type ListResetPointsRequest struct {
+	Namespace  string
+	WorkflowID string
+	RunID      string
+}
+
+// ResetPoint is a completed workflow task an execution can be reset to.
+type ResetPoint struct {
+	EventID   int64
+	Label     string
+	Timestamp time.Time
+}
+
+// GetExecutionTreeRequest requests an execution and its descendant child
+// workflows.
+type GetExecutionTreeRequest struct {
+	Namespace  string
+	WorkflowID string
+	RunID      string
+}
+
+// ExecutionTreeNode is an execution and the child workflows it started.
+type ExecutionTreeNode struct {
+	Execution *WorkflowExecution
+	Children  []*ExecutionTreeNode
+}
+
+// ExecutionTree is the hierarchy of child workflows below a root execution.
+// Truncated is set when descendants beyond the depth or size limit were
+// left out.
+type ExecutionTree struct {
+	Root      *ExecutionTreeNode
+	NodeCount int
+	Truncated bool
+}
+
+// GetExecutionTimelineRequest requests the per-node timeline of an execution.
+type GetExecutionTimelineRequest struct {
+	Namespace  string
+	WorkflowID string
+	RunID      string
+}
+
+// TimelineNode is one workflow node in an execution timeline. Attempts counts
+// how often the node was scheduled; the other fields describe the latest
+// attempt.
+type TimelineNode struct {
+	NodeID      string
+	NodeType    string
+	Name        string
+	Status      string
+	Sequence    int
+	Attempts    int
+	ScheduledAt time.Time
+	StartedAt   time.Time
+	CompletedAt *time.Time
+	Output      []byte
+	Error       string
+}
+
 type QueryWorkflowRequest struct {
 	Namespace  string
 	WorkflowID string
@@ -73,6 +242,22 @@ type ListExecutionsRequest struct {
 	PageSize      int32
 	NextPageToken []byte
 	Query         string
+	// Status is "open" (default), "closed", "all" or a single status such as
+	// "completed".
+	Status string
+}
+
+// DefaultListExecutionsPageSize and MaxListExecutionsPageSize bound the page
+// size of ListExecutions.
+const (
+	DefaultListExecutionsPageSize = 100
+	MaxListExecutionsPageSize     = 1000
+)
+
+// ListStatusFilters are the accepted ListExecutionsRequest.Status values.
+var ListStatusFilters = []string{
+	"open", "closed", "all",
+	"running", "completed", "failed", "canceled", "terminated", "timed_out", "continued_as_new",
 }
 
 type ListExecutionsResponse struct {
@@ -80,6 +265,15 @@ type ListExecutionsResponse struct {
 	NextPageToken []byte
 }
 
+// ListExecutionsByCorrelationIDRequest lists the runs started with a
+// correlation ID, in any status.
+type ListExecutionsByCorrelationIDRequest struct {
+	Namespace     string
+	CorrelationID string
+	PageSize      int32
+	NextPageToken []byte
+}
+
 type DescribeExecutionRequest struct {
 	Namespace  string
 	WorkflowID string
@@ -89,7 +283,9 @@ type DescribeExecutionRequest struct {
 type DescribeExecutionResponse struct {
 	Execution         *WorkflowExecution
 	PendingActivities []*PendingActivity
+	PendingTimers     []*PendingTimer
 	PendingChildExecs []*PendingChildExecution
+	VersionMarkers    []*VersionMarker
 }
 
 type WorkflowExecution struct {
@@ -127,9 +323,24 @@ type PendingActivity struct {
 	Attempt           int32
 	MaximumAttempts   int32
 	LastFailure       *Failure
+	LastFailureTime   *time.Time
 	LastHeartbeatTime *time.Time
 }
 
+// VersionMarker is the version an execution recorded for a workflow change.
+type VersionMarker struct {
+	ChangeID string
+	Version  int32
+	EventID  int64
+}
+
+// PendingTimer is a timer an execution is waiting on.
+type PendingTimer struct {
+	TimerID        string
+	StartedEventID int64
+	FireTime       time.Time
+}
+
 type PendingActivityState int32
 
 const (
@@ -168,9 +379,16 @@ type RecordEventRequest struct {
 }
 
 type ExecutionStartedAttributes struct {
-	WorkflowType string
-	TaskQueue    string
-	Input        []byte
+	WorkflowType  string
+	TaskQueue     string
+	Input         []byte
+	CorrelationID string
+}
+
+type SignalReceivedAttributes struct {
+	SignalName string
+	Input      []byte
+	Identity   string
 }
 
 type GetHistoryRequest struct {
@@ -268,3 +486,51 @@ type Task struct {
 	TaskType  TaskType
 	TaskInfo  []byte
 }
+
+// SavedSearchQuery is a named visibility query stored for a namespace.
+type SavedSearchQuery struct {
+	Namespace string
+	Name      string
+	Query     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SaveSearchQueryRequest struct {
+	Namespace string
+	Name      string
+	Query     string
+}
+
+type RunSearchQueryRequest struct {
+	Namespace     string
+	Name          string
+	PageSize      int32
+	NextPageToken []byte
+}
+
+// ExecutionProgress is the latest progress a worker reported for a legacy
+// execution, keyed by the workspace and execution IDs of the job payload. It
+// is stored and streamed as JSON.
+type ExecutionProgress struct {
+	WorkspaceID   string         `json:"workspace_id"`
+	ExecutionID   string         `json:"execution_id"`
+	Status        string         `json:"status"` // running, completed or failed
+	Progress      int            `json:"progress"`
+	CurrentNode   string         `json:"current_node,omitempty"`
+	PartialOutput string         `json:"partial_output,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	Nodes         []NodeProgress `json:"nodes,omitempty"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// NodeProgress is the status of one node of an execution.
+type NodeProgress struct {
+	NodeID      string `json:"node_id"`
+	NodeType    string `json:"node_type,omitempty"`
+	NodeName    string `json:"node_name,omitempty"`
+	Status      string `json:"status"`
+	StartedAt   string `json:"started_at,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
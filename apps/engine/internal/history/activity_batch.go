@@ -0,0 +1,111 @@
+package history
+
+import (
+	"context"
+	"log/slog"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxActivityCompletionBatch is the most completions history accepts in one
+// RespondActivityTaskCompletedBatch call.
+const MaxActivityCompletionBatch = 1000
+
+// ActivityCompletionResult is the outcome of one completion of a batch.
+type ActivityCompletionResult struct {
+	Response *historyv1.RespondActivityTaskCompletedResponse
+	Err      error
+}
+
+// RespondActivityTaskCompletedBatch records activity completions, returning
+// one result per request in request order. Completions of the same execution
+// are appended in request order by a single processEvents call. When that
+// call is rejected, e.g. because one of the nodes is no longer pending or a
+// request was already applied, the execution's completions are recorded one
+// by one instead, so each gets the result it would have had on its own.
+func (s *Service) RespondActivityTaskCompletedBatch(ctx context.Context, reqs []*historyv1.RespondActivityTaskCompletedRequest) []ActivityCompletionResult {
+	results := make([]ActivityCompletionResult, len(reqs))
+
+	groups := make(map[types.ExecutionKey][]int)
+	var order []types.ExecutionKey
+	for i, req := range reqs {
+		// Async completions resolve their execution from the task token.
+		if len(req.GetTaskToken()) > 0 {
+			results[i].Response, results[i].Err = s.RespondActivityTaskCompleted(ctx, req)
+			continue
+		}
+		key := activityCompletionKey(req)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, key := range order {
+		s.completeActivityGroup(ctx, key, reqs, groups[key], results)
+	}
+	return results
+}
+
+// completeActivityGroup records the completions reqs[i], for i in indexes,
+// of one execution.
+func (s *Service) completeActivityGroup(ctx context.Context, key types.ExecutionKey, reqs []*historyv1.RespondActivityTaskCompletedRequest, indexes []int, results []ActivityCompletionResult) {
+	if len(indexes) > 1 {
+		requestIDs := make([]string, len(indexes))
+		events := make([]*types.HistoryEvent, len(indexes))
+		for j, i := range indexes {
+			requestIDs[j] = reqs[i].GetRequestId()
+			events[j] = nodeCompletedEvent(reqs[i])
+		}
+
+		_, err := s.processEventBatch(ctx, key, requestIDs, events)
+		if err == nil {
+			for j, i := range indexes {
+				results[i].Response = &historyv1.RespondActivityTaskCompletedResponse{EventId: events[j].EventID}
+			}
+			return
+		}
+		s.logger.Debug("activity completion batch rejected, completing one by one",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("run_id", key.RunID),
+			slog.Int("completions", len(indexes)),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	for _, i := range indexes {
+		results[i].Response, results[i].Err = s.RespondActivityTaskCompleted(ctx, reqs[i])
+	}
+}
+
+// RespondActivityTaskCompletedBatch serves one batch of activity
+// completions.
+func (s *GRPCServer) RespondActivityTaskCompletedBatch(ctx context.Context, req *historyv1.RespondActivityTaskCompletedBatchRequest) (*historyv1.RespondActivityTaskCompletedBatchResponse, error) {
+	if len(req.GetRequests()) > MaxActivityCompletionBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "batch exceeds %d completions", MaxActivityCompletionBatch)
+	}
+
+	results := s.service.RespondActivityTaskCompletedBatch(ctx, req.GetRequests())
+	resp := &historyv1.RespondActivityTaskCompletedBatchResponse{
+		Results: make([]*historyv1.ActivityCompletionResult, len(results)),
+	}
+	for i, result := range results {
+		reply := &historyv1.ActivityCompletionResult{Response: result.Response}
+		if result.Err != nil {
+			st := status.Convert(s.toGRPCError(result.Err))
+			reply.ErrorCode = int32(st.Code())
+			reply.ErrorMessage = st.Message()
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reply.ErrorReason = info.GetReason()
+				}
+			}
+		}
+		resp.Results[i] = reply
+	}
+	return resp, nil
+}
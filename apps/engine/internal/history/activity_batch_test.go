@@ -0,0 +1,131 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"github.com/linkflow/engine/internal/worker/adapter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestRespondActivityTaskCompletedBatch(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+			EventType: types.EventTypeNodeScheduled,
+			Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+				NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{
+					NodeId:    fmt.Sprintf("node-%d", i),
+					NodeType:  "http",
+					TaskQueue: &apiv1.TaskQueue{Name: "default"},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("schedule node %d: %v", i, err)
+		}
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	historyv1.RegisterHistoryServiceServer(server, NewGRPCServer(svc))
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := adapter.NewHistoryClient(conn)
+
+	completion := func(scheduledEventID int64, requestID string) *historyv1.RespondActivityTaskCompletedRequest {
+		return &historyv1.RespondActivityTaskCompletedRequest{
+			Namespace:         key.NamespaceID,
+			WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+			ScheduledEventId:  scheduledEventID,
+			RequestId:         requestID,
+		}
+	}
+
+	// Both completions are appended in order by one state update.
+	before, err := stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	results, err := client.RespondActivityTaskCompletedBatch(ctx, []*historyv1.RespondActivityTaskCompletedRequest{
+		completion(2, "activity/2"),
+		completion(1, "activity/1"),
+	})
+	if err != nil {
+		t.Fatalf("first batch: %v", err)
+	}
+	for i, want := range []int64{4, 5} {
+		if results[i].Err != nil || results[i].Response.GetEventId() != want {
+			t.Fatalf("result %d: expected event %d, got %+v", i, want, results[i])
+		}
+	}
+	after, err := stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if after.DBVersion != before.DBVersion+1 {
+		t.Fatalf("expected one state update, DB version went from %d to %d", before.DBVersion, after.DBVersion)
+	}
+
+	// A stale completion and a retried one do not fail the rest of the batch.
+	results, err = client.RespondActivityTaskCompletedBatch(ctx, []*historyv1.RespondActivityTaskCompletedRequest{
+		completion(1, "stale/1"),
+		completion(3, "activity/3"),
+		completion(2, "activity/2"),
+	})
+	if err != nil {
+		t.Fatalf("second batch: %v", err)
+	}
+	if !adapter.IsNodeNotPending(results[0].Err) {
+		t.Fatalf("expected stale completion to be rejected, got %+v", results[0])
+	}
+	if results[1].Err != nil || results[1].Response.GetEventId() != 6 {
+		t.Fatalf("expected node 3 completed at event 6, got %+v", results[1])
+	}
+	if results[2].Err != nil || !results[2].Response.GetDuplicate() || results[2].Response.GetEventId() != 4 {
+		t.Fatalf("expected duplicate of event 4, got %+v", results[2])
+	}
+
+	completed, err := eventStore.GetEventCountByType(ctx, key, []types.EventType{types.EventTypeNodeCompleted})
+	if err != nil {
+		t.Fatalf("event count: %v", err)
+	}
+	if completed != 3 {
+		t.Fatalf("expected 3 completion events, got %d", completed)
+	}
+}
@@ -0,0 +1,56 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecordActivityTaskStarted records that a worker began an attempt of the
+// pending node scheduled by scheduledEventID. Attempts start at 1; recording
+// the same attempt again, e.g. after a lost acknowledgement, returns the
+// event of the first call. A node that is no longer pending is rejected with
+// engine.ErrNodeNotPending.
+func (s *Service) RecordActivityTaskStarted(ctx context.Context, key types.ExecutionKey, scheduledEventID int64, attempt int32, identity string) (int64, bool, error) {
+	if attempt < 1 {
+		attempt = 1
+	}
+	requestID := fmt.Sprintf("activity/%d/started/%d", scheduledEventID, attempt)
+	event := &types.HistoryEvent{
+		EventType: types.EventTypeNodeStarted,
+		Timestamp: time.Now(),
+		Attributes: &historyv1.HistoryEvent_NodeStartedAttributes{
+			NodeStartedAttributes: &historyv1.NodeStartedEventAttributes{
+				ScheduledEventId: scheduledEventID,
+				Identity:         identity,
+				RequestId:        requestID,
+				Attempt:          attempt,
+			},
+		},
+	}
+
+	return s.processEventsOnce(ctx, key, requestID, []*types.HistoryEvent{event})
+}
+
+// RecordActivityTaskStarted records the start of the request's attempt.
+func (s *GRPCServer) RecordActivityTaskStarted(ctx context.Context, req *historyv1.RecordActivityTaskStartedRequest) (*historyv1.RecordActivityTaskStartedResponse, error) {
+	if req.GetScheduledEventId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "scheduled event ID is required")
+	}
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	eventID, duplicate, err := s.service.RecordActivityTaskStarted(ctx, key, req.GetScheduledEventId(), req.GetAttempt(), req.GetIdentity())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return &historyv1.RecordActivityTaskStartedResponse{EventId: eventID, Duplicate: duplicate}, nil
+}
@@ -0,0 +1,68 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestRecordActivityTaskStartedTracksAttempts(t *testing.T) {
+	ctx := context.Background()
+	svc, eventStore, stateStore := newChildActivityTestService(t)
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	seedRunningExecution(t, stateStore, key, "")
+
+	err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType: types.EventTypeNodeScheduled,
+		Timestamp: time.Now(),
+		Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+			NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{
+				NodeId:    "fetch",
+				NodeType:  "http",
+				TaskQueue: &apiv1.TaskQueue{Name: "default"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("schedule node: %v", err)
+	}
+
+	first, duplicate, err := svc.RecordActivityTaskStarted(ctx, key, 1, 1, "worker-a")
+	if err != nil || duplicate {
+		t.Fatalf("start attempt 1: %d, %v, %v", first, duplicate, err)
+	}
+	// A redelivered acknowledgement for the same attempt records nothing new.
+	again, duplicate, err := svc.RecordActivityTaskStarted(ctx, key, 1, 1, "worker-a")
+	if err != nil || !duplicate || again != first {
+		t.Fatalf("repeat attempt 1: %d, %v, %v; want event %d as a duplicate", again, duplicate, err, first)
+	}
+	if _, _, err := svc.RecordActivityTaskStarted(ctx, key, 1, 2, "worker-b"); err != nil {
+		t.Fatalf("start attempt 2: %v", err)
+	}
+
+	state, err := stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	pending := state.PendingNodes[1]
+	if pending == nil || pending.Attempt != 2 || pending.StartedTime.IsZero() || pending.StartedEventID == 0 {
+		t.Fatalf("pending node = %+v, want attempt 2 started", pending)
+	}
+	started, err := eventStore.GetEventsByType(ctx, key, []types.EventType{types.EventTypeNodeStarted}, 1, 10)
+	if err != nil {
+		t.Fatalf("get events: %v", err)
+	}
+	if len(started) != 2 {
+		t.Fatalf("expected two NodeStarted events, got %d", len(started))
+	}
+
+	if _, _, err := svc.RecordActivityTaskStarted(ctx, key, 99, 1, "worker-a"); !errors.Is(err, engine.ErrNodeNotPending) {
+		t.Fatalf("expected ErrNodeNotPending for an unknown node, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/history/ndc"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// MutableStateView is the JSON form of a MutableStateDescription served by
+// the admin handler.
+type MutableStateView struct {
+	NamespaceID     string              `json:"namespace_id"`
+	WorkflowID      string              `json:"workflow_id"`
+	RunID           string              `json:"run_id"`
+	WorkflowType    string              `json:"workflow_type"`
+	TaskQueue       string              `json:"task_queue"`
+	Status          string              `json:"status"`
+	StartTime       time.Time           `json:"start_time"`
+	CloseTime       *time.Time          `json:"close_time,omitempty"`
+	NextEventID     int64               `json:"next_event_id"`
+	DBVersion       int64               `json:"db_version"`
+	BranchToken     []byte              `json:"branch_token"`
+	VersionHistory  *ndc.VersionHistory `json:"version_history"`
+	PendingTasks    PendingTasksView    `json:"pending_tasks"`
+	CompletedNodes  int                 `json:"completed_nodes"`
+	BufferedEvents  int                 `json:"buffered_events"`
+	BufferedSignals int                 `json:"buffered_signals"`
+}
+
+// PendingTasksView is the JSON form of PendingTasks.
+type PendingTasksView struct {
+	Activities []PendingTaskView `json:"activities"`
+	Nodes      []PendingTaskView `json:"nodes"`
+	Timers     []PendingTaskView `json:"timers"`
+	Children   []PendingTaskView `json:"children"`
+}
+
+// PendingTaskView is one pending activity, node, timer or child workflow,
+// identified by the event that created it.
+type PendingTaskView struct {
+	EventID int64     `json:"event_id"`
+	ID      string    `json:"id"`
+	Type    string    `json:"type,omitempty"`
+	Time    time.Time `json:"time"`
+	Attempt int32     `json:"attempt,omitempty"`
+}
+
+// NewMutableStateView converts a description to its JSON form.
+func NewMutableStateView(desc *MutableStateDescription) *MutableStateView {
+	view := &MutableStateView{
+		NamespaceID:     desc.Info.NamespaceID,
+		WorkflowID:      desc.Info.WorkflowID,
+		RunID:           desc.Info.RunID,
+		WorkflowType:    desc.Info.WorkflowTypeName,
+		TaskQueue:       desc.Info.TaskQueue,
+		Status:          internalExecutionStatusToProto(desc.Info.Status).String(),
+		StartTime:       desc.Info.StartTime,
+		NextEventID:     desc.NextEventID,
+		DBVersion:       desc.DBVersion,
+		BranchToken:     desc.VersionHistory.BranchToken,
+		VersionHistory:  desc.VersionHistory,
+		CompletedNodes:  desc.CompletedNodes,
+		BufferedEvents:  desc.BufferedEvents,
+		BufferedSignals: desc.BufferedSignals,
+		PendingTasks: PendingTasksView{
+			Activities: make([]PendingTaskView, 0, len(desc.PendingTasks.Activities)),
+			Nodes:      make([]PendingTaskView, 0, len(desc.PendingTasks.Nodes)),
+			Timers:     make([]PendingTaskView, 0, len(desc.PendingTasks.Timers)),
+			Children:   make([]PendingTaskView, 0, len(desc.PendingTasks.Children)),
+		},
+	}
+	if !desc.Info.CloseTime.IsZero() {
+		closeTime := desc.Info.CloseTime
+		view.CloseTime = &closeTime
+	}
+
+	pending := &view.PendingTasks
+	for _, a := range desc.PendingTasks.Activities {
+		pending.Activities = append(pending.Activities, PendingTaskView{EventID: a.ScheduledEventID, ID: a.ActivityID, Type: a.ActivityType, Time: a.ScheduledTime, Attempt: a.Attempt})
+	}
+	for _, n := range desc.PendingTasks.Nodes {
+		pending.Nodes = append(pending.Nodes, PendingTaskView{EventID: n.ScheduledEventID, ID: n.NodeID, Type: n.NodeType, Time: n.ScheduledTime})
+	}
+	for _, t := range desc.PendingTasks.Timers {
+		pending.Timers = append(pending.Timers, PendingTaskView{EventID: t.StartedEventID, ID: t.TimerID, Time: t.FireTime})
+	}
+	for _, c := range desc.PendingTasks.Children {
+		pending.Children = append(pending.Children, PendingTaskView{EventID: c.StartedEventID, ID: c.WorkflowID + "/" + c.RunID, Type: c.WorkflowType, Time: c.StartedTime})
+	}
+	return view
+}
+
+// AdminHandler serves admin endpoints for debugging executions and tuning
+// the service:
+//
+//	GET /admin/v1/namespaces/{namespace}/executions/{workflow_id}/{run_id}/mutable-state
+//	GET|PUT /admin/v1/signal-rate-limits
+func (s *Service) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/v1/namespaces/{namespace}/executions/{workflow_id}/{run_id}/mutable-state", func(w http.ResponseWriter, r *http.Request) {
+		key := types.ExecutionKey{
+			NamespaceID: r.PathValue("namespace"),
+			WorkflowID:  r.PathValue("workflow_id"),
+			RunID:       r.PathValue("run_id"),
+		}
+		desc, err := s.DescribeMutableState(r.Context(), key)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, types.ErrExecutionNotFound) {
+				status = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(NewMutableStateView(desc))
+	})
+	mux.HandleFunc("GET /admin/v1/signal-rate-limits", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.signalLimits.currentConfig())
+	})
+	mux.HandleFunc("PUT /admin/v1/signal-rate-limits", func(w http.ResponseWriter, r *http.Request) {
+		var cfg controlplane.SignalRateLimitConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.SetSignalRateLimits(cfg)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
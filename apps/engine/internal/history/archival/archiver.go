@@ -74,6 +74,7 @@ type ArchiveRequest struct {
 	ExecutionID string
 	WorkflowID  string
 	Events      []*types.HistoryEvent
+	Status      types.ExecutionStatus // the status the execution closed with
 	ClosedAt    time.Time
 }
 
@@ -89,6 +90,7 @@ func (a *Archiver) Archive(ctx context.Context, req *ArchiveRequest) error {
 		WorkflowID:  req.WorkflowID,
 		NamespaceID: req.NamespaceID,
 		Events:      req.Events,
+		Status:      req.Status,
 		ArchivedAt:  time.Now(),
 		ClosedAt:    req.ClosedAt,
 		Version:     1,
@@ -124,6 +126,7 @@ type Archive struct {
 	WorkflowID  string                `json:"workflow_id"`
 	NamespaceID string                `json:"namespace_id"`
 	Events      []*types.HistoryEvent `json:"events"`
+	Status      types.ExecutionStatus `json:"status"`
 	ArchivedAt  time.Time             `json:"archived_at"`
 	ClosedAt    time.Time             `json:"closed_at"`
 	Version     int                   `json:"version"`
@@ -214,7 +217,7 @@ func (a *Archiver) CleanupExpired(ctx context.Context, namespaceID string) (int,
 			continue
 		}
 
-		if archive.ArchivedAt.Before(cutoff) {
+		if archive.retainedSince().Before(cutoff) {
 			if err := a.storage.Delete(ctx, key); err != nil {
 				a.logger.Warn("failed to delete expired archive",
 					slog.String("key", key),
@@ -234,6 +237,15 @@ func (a *Archiver) CleanupExpired(ctx context.Context, namespaceID string) (int,
 	return deleted, nil
 }
 
+// retainedSince is when the archive's retention period starts: when the
+// execution closed, or when it was archived for archives without a close time.
+func (a *Archive) retainedSince() time.Time {
+	if a.ClosedAt.IsZero() {
+		return a.ArchivedAt
+	}
+	return a.ClosedAt
+}
+
 func (a *Archiver) generateKey(namespaceID, executionID string, archivedAt time.Time) string {
 	return fmt.Sprintf("%s/%s/%s.json",
 		namespaceID,
@@ -0,0 +1,113 @@
+package history
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/archival"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// countingBlobStorage counts the archives written to it.
+type countingBlobStorage struct {
+	*archival.InMemoryStorage
+	mu   sync.Mutex
+	puts int
+}
+
+func (s *countingBlobStorage) Put(ctx context.Context, key string, data io.Reader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.puts++
+	return s.InMemoryStorage.Put(ctx, key, data)
+}
+
+func newArchivalTestService(t *testing.T) (*Service, *countingBlobStorage, *archival.Archiver) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	storage := &countingBlobStorage{InMemoryStorage: archival.NewInMemoryStorage()}
+	archiver := archival.NewArchiver(storage, nil, logger)
+	svc := newTestService(t, Config{
+		Archiver: archiver,
+		Logger:   logger,
+	})
+	return svc, storage, archiver
+}
+
+func startArchivalTestExecution(t *testing.T, svc *Service, key types.ExecutionKey) {
+	t.Helper()
+	err := svc.RecordEvent(context.Background(), key, &types.HistoryEvent{
+		EventType:  types.EventTypeExecutionStarted,
+		Timestamp:  time.Now(),
+		Attributes: &types.ExecutionStartedAttributes{WorkflowType: "order", TaskQueue: "default"},
+	})
+	if err != nil {
+		t.Fatalf("start execution: %v", err)
+	}
+}
+
+func TestExecutionCloseArchivesOnce(t *testing.T) {
+	for _, tc := range []struct {
+		eventType types.EventType
+		status    types.ExecutionStatus
+	}{
+		{types.EventTypeExecutionCompleted, types.ExecutionStatusCompleted},
+		{types.EventTypeExecutionFailed, types.ExecutionStatusFailed},
+		{types.EventTypeExecutionTerminated, types.ExecutionStatusTerminated},
+		{types.EventTypeExecutionTimedOut, types.ExecutionStatusTimedOut},
+		{types.EventTypeExecutionCanceled, types.ExecutionStatusCanceled},
+	} {
+		t.Run(tc.eventType.String(), func(t *testing.T) {
+			ctx := context.Background()
+			svc, storage, archiver := newArchivalTestService(t)
+			key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+			startArchivalTestExecution(t, svc, key)
+
+			closedAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+			if err := svc.RecordEvent(ctx, key, &types.HistoryEvent{EventType: tc.eventType, Timestamp: closedAt}); err != nil {
+				t.Fatalf("close execution: %v", err)
+			}
+			if storage.puts != 1 {
+				t.Fatalf("archived %d times, want once", storage.puts)
+			}
+			archive, err := archiver.Retrieve(ctx, key.NamespaceID, key.RunID)
+			if err != nil {
+				t.Fatalf("retrieve archive: %v", err)
+			}
+			if archive.Status != tc.status || !archive.ClosedAt.Equal(closedAt) || archive.WorkflowID != key.WorkflowID || len(archive.Events) != 2 {
+				t.Fatalf("archive = status %d, closed %v, workflow %s, %d events", archive.Status, archive.ClosedAt, archive.WorkflowID, len(archive.Events))
+			}
+
+			// A force terminate appends a second close event to the closed
+			// execution, which must not archive it again.
+			if err := svc.ForceTerminate(ctx, key, "cleanup", ""); err != nil {
+				t.Fatalf("force terminate: %v", err)
+			}
+			if storage.puts != 1 {
+				t.Fatalf("archived %d times after a second close event, want once", storage.puts)
+			}
+		})
+	}
+}
+
+func TestForceTerminateArchivesRunningExecution(t *testing.T) {
+	ctx := context.Background()
+	svc, storage, archiver := newArchivalTestService(t)
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	startArchivalTestExecution(t, svc, key)
+
+	if err := svc.ForceTerminate(ctx, key, "stuck", ""); err != nil {
+		t.Fatalf("force terminate: %v", err)
+	}
+	archive, err := archiver.Retrieve(ctx, key.NamespaceID, key.RunID)
+	if err != nil || storage.puts != 1 {
+		t.Fatalf("archives = %d, %v; want one", storage.puts, err)
+	}
+	if archive.Status != types.ExecutionStatusTerminated || archive.ClosedAt.IsZero() {
+		t.Fatalf("archive = status %d, closed %v", archive.Status, archive.ClosedAt)
+	}
+}
@@ -0,0 +1,294 @@
+package history
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+var (
+	ErrInvalidTaskToken   = errors.New("invalid async activity task token")
+	ErrActivityNotPending = errors.New("async activity is not pending")
+)
+
+// DefaultAsyncActivityTimeout bounds how long an async activity may wait for
+// its completion when the worker does not specify a ScheduleToCloseTimeout.
+const DefaultAsyncActivityTimeout = 24 * time.Hour
+
+// asyncActivityToken identifies a pending async activity. The nonce is
+// recorded in history so tokens cannot be forged from public identifiers.
+type asyncActivityToken struct {
+	Namespace        string `json:"ns"`
+	WorkflowID       string `json:"wid"`
+	RunID            string `json:"rid"`
+	ScheduledEventID int64  `json:"sid"`
+	Nonce            string `json:"n"`
+}
+
+func (t *asyncActivityToken) encode() ([]byte, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.RawURLEncoding.EncodeToString(data)), nil
+}
+
+func decodeAsyncActivityToken(token []byte) (*asyncActivityToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(string(token))
+	if err != nil {
+		return nil, ErrInvalidTaskToken
+	}
+	var t asyncActivityToken
+	if err := json.Unmarshal(data, &t); err != nil || t.WorkflowID == "" || t.Nonce == "" {
+		return nil, ErrInvalidTaskToken
+	}
+	return &t, nil
+}
+
+func (t *asyncActivityToken) key() types.ExecutionKey {
+	return types.ExecutionKey{
+		NamespaceID: t.Namespace,
+		WorkflowID:  t.WorkflowID,
+		RunID:       t.RunID,
+	}
+}
+
+// RecordActivityTaskPending marks a scheduled node as started and waiting for an
+// out-of-band completion, and returns the token that completes it. Repeated
+// calls for the same node return the existing token.
+func (s *Service) RecordActivityTaskPending(ctx context.Context, req *historyv1.RecordActivityTaskPendingRequest) (*historyv1.RecordActivityTaskPendingResponse, error) {
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	token := &asyncActivityToken{
+		Namespace:        key.NamespaceID,
+		WorkflowID:       key.WorkflowID,
+		RunID:            key.RunID,
+		ScheduledEventID: req.GetScheduledEventId(),
+	}
+
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if ai, ok := state.PendingActivities[token.ScheduledEventID]; ok && ai.AsyncNonce != "" {
+		token.Nonce = ai.AsyncNonce
+		encoded, err := token.encode()
+		if err != nil {
+			return nil, err
+		}
+		return &historyv1.RecordActivityTaskPendingResponse{TaskToken: encoded}, nil
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate task token: %w", err)
+	}
+	token.Nonce = hex.EncodeToString(nonce)
+
+	timeout := DefaultAsyncActivityTimeout
+	if d := req.GetScheduleToCloseTimeout(); d != nil && d.AsDuration() > 0 {
+		timeout = d.AsDuration()
+	}
+
+	event := &types.HistoryEvent{
+		EventType: types.EventTypeNodeStarted,
+		Timestamp: time.Now(),
+		Attributes: &types.NodeStartedAttributes{
+			NodeID:           req.GetNodeId(),
+			ScheduledEventID: token.ScheduledEventID,
+			Identity:         req.GetIdentity(),
+			AsyncNonce:       token.Nonce,
+			ScheduleToClose:  timeout,
+			WaitSignal:       req.GetWaitSignalName(),
+		},
+	}
+	if payloads := req.GetTimeoutResult().GetPayloads(); len(payloads) > 0 {
+		event.Attributes.(*types.NodeStartedAttributes).TimeoutResult = payloads[0].GetData()
+	}
+	events := []*types.HistoryEvent{event}
+
+	// A signal that arrived before the node started waiting for it completes
+	// the node right away.
+	delivered, err := bufferedSignalEvent(state, event.Attributes.(*types.NodeStartedAttributes))
+	if err != nil {
+		return nil, err
+	}
+	if delivered != nil {
+		events = append(events, delivered)
+	}
+	if err := s.processEvents(ctx, key, events); err != nil {
+		return nil, err
+	}
+
+	encoded, err := token.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("async activity pending",
+		slog.String("workflow_id", key.WorkflowID),
+		slog.String("node_id", req.GetNodeId()),
+		slog.Int64("scheduled_event_id", token.ScheduledEventID),
+		slog.Duration("schedule_to_close_timeout", timeout),
+		slog.String("wait_signal", req.GetWaitSignalName()),
+	)
+
+	return &historyv1.RecordActivityTaskPendingResponse{TaskToken: encoded}, nil
+}
+
+// resolveAsyncActivity validates a task token against the execution's pending
+// async activities.
+func (s *Service) resolveAsyncActivity(ctx context.Context, rawToken []byte) (types.ExecutionKey, *types.ActivityInfo, error) {
+	token, err := decodeAsyncActivityToken(rawToken)
+	if err != nil {
+		return types.ExecutionKey{}, nil, err
+	}
+
+	key := token.key()
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return key, nil, err
+	}
+
+	ai, ok := state.PendingActivities[token.ScheduledEventID]
+	if !ok || ai.AsyncNonce == "" {
+		return key, nil, ErrActivityNotPending
+	}
+	if subtle.ConstantTimeCompare([]byte(ai.AsyncNonce), []byte(token.Nonce)) != 1 {
+		return key, nil, ErrInvalidTaskToken
+	}
+
+	return key, ai, nil
+}
+
+// priorAsyncResponse reports the event appended by an already applied
+// request for an async activity that is no longer pending.
+func (s *Service) priorAsyncResponse(ctx context.Context, key types.ExecutionKey, requestID string) (int64, bool) {
+	if requestID == "" {
+		return 0, false
+	}
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return 0, false
+	}
+	return state.GetAppliedRequest(requestID)
+}
+
+func (s *Service) completeAsyncActivity(ctx context.Context, req *historyv1.RespondActivityTaskCompletedRequest) (int64, bool, error) {
+	key, ai, err := s.resolveAsyncActivity(ctx, req.GetTaskToken())
+	if errors.Is(err, ErrActivityNotPending) {
+		if eventID, ok := s.priorAsyncResponse(ctx, key, req.GetRequestId()); ok {
+			return eventID, true, nil
+		}
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	attrs := &types.NodeCompletedAttributes{
+		NodeID:           ai.ActivityID,
+		ScheduledEventID: ai.ScheduledEventID,
+		StartedEventID:   ai.StartedEventID,
+	}
+	if payloads := req.GetResult().GetPayloads(); len(payloads) > 0 {
+		attrs.Result = payloads[0].GetData()
+	}
+
+	return s.processEventsOnce(ctx, key, req.GetRequestId(), []*types.HistoryEvent{{
+		EventType:  types.EventTypeNodeCompleted,
+		Timestamp:  time.Now(),
+		Attributes: attrs,
+	}})
+}
+
+func (s *Service) failAsyncActivity(ctx context.Context, req *historyv1.RespondActivityTaskFailedRequest) (int64, bool, error) {
+	key, ai, err := s.resolveAsyncActivity(ctx, req.GetTaskToken())
+	if errors.Is(err, ErrActivityNotPending) {
+		if eventID, ok := s.priorAsyncResponse(ctx, key, req.GetRequestId()); ok {
+			return eventID, true, nil
+		}
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	reason := req.GetFailure().GetMessage()
+	if reason == "" {
+		reason = "async activity failed"
+	}
+
+	return s.processEventsOnce(ctx, key, req.GetRequestId(), []*types.HistoryEvent{{
+		EventType: types.EventTypeNodeFailed,
+		Timestamp: time.Now(),
+		Attributes: &types.NodeFailedAttributes{
+			NodeID:           ai.ActivityID,
+			ScheduledEventID: ai.ScheduledEventID,
+			StartedEventID:   ai.StartedEventID,
+			Reason:           reason,
+			Details:          []byte(req.GetFailure().GetStackTrace()),
+		},
+	}})
+}
+
+// checkAsyncActivityTimeouts fails async activities whose ScheduleToClose
+// timeout elapsed without a completion, or completes them with their
+// timeout result when they have one. A child workflow started for a timed
+// out activity gets its ParentClosePolicy applied.
+func (s *Service) checkAsyncActivityTimeouts(ctx context.Context, key types.ExecutionKey, state *engine.MutableState) {
+	now := time.Now()
+	for _, ai := range state.PendingActivities {
+		if ai.AsyncNonce == "" || ai.ScheduleTimeout <= 0 || now.Sub(ai.StartedTime) <= ai.ScheduleTimeout {
+			continue
+		}
+
+		s.logger.Info("async activity timed out",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("node_id", ai.ActivityID),
+			slog.Int64("scheduled_event_id", ai.ScheduledEventID),
+		)
+
+		event := &types.HistoryEvent{
+			EventType: types.EventTypeNodeFailed,
+			Timestamp: now,
+			Attributes: &types.NodeFailedAttributes{
+				NodeID:           ai.ActivityID,
+				ScheduledEventID: ai.ScheduledEventID,
+				StartedEventID:   ai.StartedEventID,
+				Reason:           fmt.Sprintf("async activity timed out after %s", ai.ScheduleTimeout),
+			},
+		}
+		if len(ai.TimeoutResult) > 0 {
+			event = &types.HistoryEvent{
+				EventType: types.EventTypeNodeCompleted,
+				Timestamp: now,
+				Attributes: &types.NodeCompletedAttributes{
+					NodeID:           ai.ActivityID,
+					ScheduledEventID: ai.ScheduledEventID,
+					StartedEventID:   ai.StartedEventID,
+					Result:           ai.TimeoutResult,
+				},
+			}
+		}
+		if err := s.processEvents(ctx, key, []*types.HistoryEvent{event}); err != nil {
+			s.logger.Warn("failed to time out async activity", "error", err, "workflow_id", key.WorkflowID)
+			continue
+		}
+		// A child workflow started for the activity is no longer awaited.
+		s.cancelActivityChild(ctx, key, state, ai)
+	}
+}
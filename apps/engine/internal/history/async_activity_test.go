@@ -0,0 +1,212 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestAsyncActivityCallbacks(t *testing.T) {
+	tests := []struct {
+		name string
+		// respond completes or fails the pending activity with the token.
+		respond    func(ctx context.Context, svc *Service, token []byte) error
+		wantErr    error
+		wantOutput string
+		wantReason string
+	}{
+		{
+			name: "completed",
+			respond: func(ctx context.Context, svc *Service, token []byte) error {
+				_, err := svc.RespondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{
+					TaskToken: token,
+					Result:    &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: []byte(`{"paid":true}`)}}},
+				})
+				return err
+			},
+			wantOutput: `{"paid":true}`,
+		},
+		{
+			name: "failed",
+			respond: func(ctx context.Context, svc *Service, token []byte) error {
+				_, err := svc.RespondActivityTaskFailed(ctx, &historyv1.RespondActivityTaskFailedRequest{
+					TaskToken: token,
+					Failure:   &commonv1.Failure{Message: "card declined"},
+				})
+				return err
+			},
+			wantReason: "card declined",
+		},
+		{
+			name: "failed without message",
+			respond: func(ctx context.Context, svc *Service, token []byte) error {
+				_, err := svc.RespondActivityTaskFailed(ctx, &historyv1.RespondActivityTaskFailedRequest{TaskToken: token})
+				return err
+			},
+			wantReason: "async activity failed",
+		},
+		{
+			name: "forged nonce",
+			respond: func(ctx context.Context, svc *Service, token []byte) error {
+				decoded, err := decodeAsyncActivityToken(token)
+				if err != nil {
+					return err
+				}
+				decoded.Nonce = "00000000000000000000000000000000"
+				forged, err := decoded.encode()
+				if err != nil {
+					return err
+				}
+				_, err = svc.RespondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{TaskToken: forged})
+				return err
+			},
+			wantErr: ErrInvalidTaskToken,
+		},
+		{
+			name: "malformed token",
+			respond: func(ctx context.Context, svc *Service, token []byte) error {
+				_, err := svc.RespondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{TaskToken: []byte("not a token")})
+				return err
+			},
+			wantErr: ErrInvalidTaskToken,
+		},
+		{
+			name: "other activity",
+			respond: func(ctx context.Context, svc *Service, token []byte) error {
+				decoded, err := decodeAsyncActivityToken(token)
+				if err != nil {
+					return err
+				}
+				decoded.ScheduledEventID++
+				other, err := decoded.encode()
+				if err != nil {
+					return err
+				}
+				_, err = svc.RespondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{TaskToken: other})
+				return err
+			},
+			wantErr: ErrActivityNotPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			stateStore := store.NewMemoryMutableStateStore()
+			svc := newTestService(t, Config{
+				StateStore: stateStore,
+			})
+
+			key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+			state := engine.NewMutableState(&types.ExecutionInfo{
+				NamespaceID: key.NamespaceID,
+				WorkflowID:  key.WorkflowID,
+				RunID:       key.RunID,
+				Status:      types.ExecutionStatusRunning,
+			})
+			if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+				t.Fatalf("seed state: %v", err)
+			}
+
+			pendingReq := &historyv1.RecordActivityTaskPendingRequest{
+				Namespace:              key.NamespaceID,
+				WorkflowExecution:      &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+				ScheduledEventId:       3,
+				NodeId:                 "charge",
+				ScheduleToCloseTimeout: durationpb.New(time.Hour),
+			}
+			pending, err := svc.RecordActivityTaskPending(ctx, pendingReq)
+			if err != nil {
+				t.Fatalf("record pending: %v", err)
+			}
+			again, err := svc.RecordActivityTaskPending(ctx, pendingReq)
+			if err != nil {
+				t.Fatalf("record pending again: %v", err)
+			}
+			if string(again.GetTaskToken()) != string(pending.GetTaskToken()) {
+				t.Fatal("repeated pending call issued a new token")
+			}
+
+			err = tt.respond(ctx, svc, pending.GetTaskToken())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("respond error = %v, want %v", err, tt.wantErr)
+				}
+				state, err := stateStore.GetMutableState(ctx, key)
+				if err != nil {
+					t.Fatalf("get state: %v", err)
+				}
+				if _, ok := state.PendingActivities[3]; !ok {
+					t.Fatal("rejected callback resolved the activity")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("respond: %v", err)
+			}
+
+			state, err = stateStore.GetMutableState(ctx, key)
+			if err != nil {
+				t.Fatalf("get state: %v", err)
+			}
+			if _, ok := state.PendingActivities[3]; ok {
+				t.Fatal("activity still pending after its callback")
+			}
+			result := state.CompletedNodes["charge"]
+			if result == nil || string(result.Output) != tt.wantOutput || result.FailureReason != tt.wantReason {
+				t.Fatalf("charge result = %+v", result)
+			}
+
+			// The token is spent once the activity resolved.
+			_, err = svc.RespondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{TaskToken: pending.GetTaskToken()})
+			if !errors.Is(err, ErrActivityNotPending) {
+				t.Fatalf("second callback error = %v, want ErrActivityNotPending", err)
+			}
+		})
+	}
+}
+
+func TestAsyncActivityCallbackRetryIsDeduplicated(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, Config{})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType:  types.EventTypeExecutionStarted,
+		Timestamp:  time.Now(),
+		Attributes: &types.ExecutionStartedAttributes{WorkflowType: "order", TaskQueue: "default"},
+	})
+	if err != nil {
+		t.Fatalf("start execution: %v", err)
+	}
+	pending, err := svc.RecordActivityTaskPending(ctx, &historyv1.RecordActivityTaskPendingRequest{
+		Namespace:         key.NamespaceID,
+		WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+		ScheduledEventId:  2,
+		NodeId:            "charge",
+	})
+	if err != nil {
+		t.Fatalf("record pending: %v", err)
+	}
+
+	req := &historyv1.RespondActivityTaskCompletedRequest{TaskToken: pending.GetTaskToken(), RequestId: "callback-1"}
+	first, err := svc.RespondActivityTaskCompleted(ctx, req)
+	if err != nil || first.GetDuplicate() {
+		t.Fatalf("first callback = %+v, %v", first, err)
+	}
+	retry, err := svc.RespondActivityTaskCompleted(ctx, req)
+	if err != nil {
+		t.Fatalf("retried callback: %v", err)
+	}
+	if !retry.GetDuplicate() || retry.GetEventId() != first.GetEventId() {
+		t.Fatalf("retried callback = %+v, want duplicate of event %d", retry, first.GetEventId())
+	}
+}
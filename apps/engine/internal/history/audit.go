@@ -0,0 +1,148 @@
+package history
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/audit"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// DefaultAuditBufferSize is the default number of audit records held in
+// memory while the sink catches up. Records beyond it are dropped.
+const DefaultAuditBufferSize = 10000
+
+const (
+	auditBatchSize     = 100
+	auditFlushInterval = time.Second
+	auditWriteTimeout  = 10 * time.Second
+)
+
+// AuditStats counts what happened to audit records since the service started.
+type AuditStats struct {
+	Written int64 // records the sink accepted
+	Failed  int64 // records in batches the sink rejected
+	Dropped int64 // records discarded because the buffer was full
+}
+
+// isAuditedEvent reports whether an event is a lifecycle transition that is
+// sent to the audit sink.
+func isAuditedEvent(eventType types.EventType) bool {
+	switch eventType {
+	case types.EventTypeExecutionStarted,
+		types.EventTypeExecutionCompleted,
+		types.EventTypeExecutionFailed,
+		types.EventTypeExecutionTerminated,
+		types.EventTypeExecutionTimedOut,
+		types.EventTypeExecutionCanceled,
+		types.EventTypeNodeScheduled,
+		types.EventTypeNodeCompleted,
+		types.EventTypeNodeFailed,
+		types.EventTypeNodeTimedOut,
+		types.EventTypeActivityScheduled,
+		types.EventTypeActivityCompleted,
+		types.EventTypeActivityFailed,
+		types.EventTypeActivityTimedOut,
+		types.EventTypeSignalReceived:
+		return true
+	}
+	return false
+}
+
+// emitAudit queues audit records for the processed events. It never blocks:
+// when the buffer is full the record is dropped and counted.
+func (s *Service) emitAudit(key types.ExecutionKey, events []*types.HistoryEvent) {
+	for _, event := range events {
+		if !isAuditedEvent(event.EventType) {
+			continue
+		}
+		record := audit.Record{
+			NamespaceID: key.NamespaceID,
+			WorkflowID:  key.WorkflowID,
+			RunID:       key.RunID,
+			EventID:     event.EventID,
+			EventType:   event.EventType.String(),
+			Timestamp:   event.Timestamp,
+		}
+		select {
+		case s.auditRecords <- record:
+		default:
+			if s.auditStats.dropped.Add(1) == 1 {
+				s.logger.Warn("audit buffer full, dropping records", "workflow_id", key.WorkflowID)
+			}
+		}
+	}
+}
+
+// AuditStats returns the audit record counters.
+func (s *Service) AuditStats() AuditStats {
+	return AuditStats{
+		Written: s.auditStats.written.Load(),
+		Failed:  s.auditStats.failed.Load(),
+		Dropped: s.auditStats.dropped.Load(),
+	}
+}
+
+type auditCounters struct {
+	written atomic.Int64
+	failed  atomic.Int64
+	dropped atomic.Int64
+}
+
+// startAuditFlusher writes queued audit records to the sink in batches until
+// the service stops, then flushes what is left in the buffer.
+func (s *Service) startAuditFlusher() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(auditFlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]audit.Record, 0, auditBatchSize)
+		for {
+			select {
+			case record := <-s.auditRecords:
+				batch = append(batch, record)
+				if len(batch) >= auditBatchSize {
+					batch = s.flushAudit(batch)
+				}
+			case <-ticker.C:
+				batch = s.flushAudit(batch)
+			case <-s.stopCh:
+				for {
+					select {
+					case record := <-s.auditRecords:
+						batch = append(batch, record)
+						if len(batch) >= auditBatchSize {
+							batch = s.flushAudit(batch)
+						}
+					default:
+						s.flushAudit(batch)
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// flushAudit writes batch to the sink and returns it emptied. Sink errors are
+// logged and counted; the records are not retried.
+func (s *Service) flushAudit(batch []audit.Record) []audit.Record {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+	defer cancel()
+
+	if err := s.auditSink.Write(ctx, batch); err != nil {
+		s.auditStats.failed.Add(int64(len(batch)))
+		s.logger.Warn("failed to write audit records", "error", err, "records", len(batch))
+	} else {
+		s.auditStats.written.Add(int64(len(batch)))
+	}
+	return batch[:0]
+}
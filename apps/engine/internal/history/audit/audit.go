@@ -0,0 +1,30 @@
+// Package audit records workflow lifecycle transitions for compliance. Sinks
+// receive records in batches and are expected to store them append-only.
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// Record is one lifecycle transition of a workflow execution.
+type Record struct {
+	NamespaceID string
+	WorkflowID  string
+	RunID       string
+	EventID     int64
+	EventType   string
+	Timestamp   time.Time
+}
+
+// Sink persists audit records. Write receives records in the order their
+// events were processed; an error fails the whole batch. Implementations
+// must not retain the slice after Write returns.
+type Sink interface {
+	Write(ctx context.Context, records []Record) error
+}
+
+// NoopSink discards all records.
+type NoopSink struct{}
+
+func (NoopSink) Write(context.Context, []Record) error { return nil }
@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresSink appends records to the workflow_audit_log table, which rejects
+// updates and deletes (see scripts/migrations/005_workflow_audit_log.up.sql).
+type PostgresSink struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSink(pool *pgxpool.Pool) *PostgresSink {
+	return &PostgresSink{pool: pool}
+}
+
+func (s *PostgresSink) Write(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, len(records))
+	for i, r := range records {
+		rows[i] = []any{r.NamespaceID, r.WorkflowID, r.RunID, r.EventID, r.EventType, r.Timestamp}
+	}
+
+	_, err := s.pool.CopyFrom(ctx,
+		pgx.Identifier{"workflow_audit_log"},
+		[]string{"namespace_id", "workflow_id", "run_id", "event_id", "event_type", "event_time"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write audit records: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/audit"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+type recordingAuditSink struct {
+	mu      sync.Mutex
+	records []audit.Record
+	err     error
+}
+
+func (s *recordingAuditSink) Write(_ context.Context, records []audit.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func runAuditedExecution(t *testing.T, sink audit.Sink) *Service {
+	t.Helper()
+	ctx := context.Background()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		StateStore: stateStore,
+		AuditSink:  sink,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	for _, eventType := range []types.EventType{
+		types.EventTypeSignalReceived,
+		types.EventTypeMarkerRecorded,
+		types.EventTypeExecutionTerminated,
+	} {
+		event := &types.HistoryEvent{EventType: eventType, Timestamp: time.Now()}
+		if err := svc.RecordEvent(ctx, key, event); err != nil {
+			t.Fatalf("record %s: %v", eventType, err)
+		}
+	}
+
+	// Stop flushes the buffered records.
+	if err := svc.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	return svc
+}
+
+func TestAuditSinkReceivesLifecycleTransitions(t *testing.T) {
+	sink := &recordingAuditSink{}
+	svc := runAuditedExecution(t, sink)
+
+	if len(sink.records) != 2 {
+		t.Fatalf("expected 2 audit records, got %+v", sink.records)
+	}
+	if sink.records[0].EventType != "SignalReceived" || sink.records[1].EventType != "ExecutionTerminated" {
+		t.Fatalf("unexpected audit records %+v", sink.records)
+	}
+	for _, r := range sink.records {
+		if r.NamespaceID != "default" || r.WorkflowID != "wf-1" || r.RunID != "run-1" || r.Timestamp.IsZero() {
+			t.Fatalf("incomplete audit record %+v", r)
+		}
+	}
+	if stats := svc.AuditStats(); stats.Written != 2 || stats.Failed != 0 {
+		t.Fatalf("unexpected audit stats %+v", stats)
+	}
+}
+
+func TestAuditSinkErrorsAreCounted(t *testing.T) {
+	svc := runAuditedExecution(t, &recordingAuditSink{err: errors.New("sink down")})
+
+	if stats := svc.AuditStats(); stats.Failed != 2 || stats.Written != 0 {
+		t.Fatalf("unexpected audit stats %+v", stats)
+	}
+}
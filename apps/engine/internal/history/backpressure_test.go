@@ -0,0 +1,89 @@
+package history
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// backpressureMatchingClient refuses the first rejections AddTask calls with
+// RESOURCE_EXHAUSTED and records the tasks it accepts.
+type backpressureMatchingClient struct {
+	matchingv1.MatchingServiceClient
+
+	mu         sync.Mutex
+	rejections int
+	attempts   int
+	accepted   []*matchingv1.AddTaskRequest
+}
+
+func (c *backpressureMatchingClient) AddTask(_ context.Context, req *matchingv1.AddTaskRequest, _ ...grpc.CallOption) (*matchingv1.AddTaskResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	if c.attempts <= c.rejections {
+		return nil, status.Error(codes.ResourceExhausted, "task queue over hard limit")
+	}
+	c.accepted = append(c.accepted, req)
+	return &matchingv1.AddTaskResponse{}, nil
+}
+
+func (c *backpressureMatchingClient) snapshot() (int, []*matchingv1.AddTaskRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.attempts, append([]*matchingv1.AddTaskRequest(nil), c.accepted...)
+}
+
+func TestDispatchRetriesTaskRefusedByBackpressure(t *testing.T) {
+	matching := &backpressureMatchingClient{rejections: 3}
+	svc := newTestService(t, Config{MatchingClient: matching})
+	svc.backpressureRetry = time.Millisecond
+	ctx := context.Background()
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	if err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType:  types.EventTypeExecutionStarted,
+		Timestamp:  time.Now(),
+		Attributes: &types.ExecutionStartedAttributes{WorkflowType: "order", TaskQueue: "default"},
+	}); err != nil {
+		t.Fatalf("start execution: %v", err)
+	}
+	if err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType: types.EventTypeWorkflowTaskScheduled,
+		Timestamp: time.Now(),
+		Attributes: &historyv1.HistoryEvent_WorkflowTaskScheduledAttributes{
+			WorkflowTaskScheduledAttributes: &historyv1.WorkflowTaskScheduledEventAttributes{
+				TaskQueue: &apiv1.TaskQueue{Name: "default"},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("schedule workflow task: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		attempts, accepted := matching.snapshot()
+		if len(accepted) == 1 {
+			if attempts != 4 {
+				t.Fatalf("AddTask attempts = %d, want 3 rejections and 1 success", attempts)
+			}
+			if got := accepted[0].GetTaskQueue().GetName(); got != "default" {
+				t.Fatalf("accepted task queue = %q, want default", got)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("task never accepted after %d attempts", attempts)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
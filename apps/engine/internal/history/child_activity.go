@@ -0,0 +1,188 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrChildWorkflowNotStarted is returned when history could not start the
+// child workflow of an activity. The activity has been failed with the
+// reason.
+var ErrChildWorkflowNotStarted = errors.New("child workflow not started")
+
+// StartChildWorkflowForActivity starts a child workflow on behalf of the
+// pending async activity identified by taskToken, e.g. a sub_workflow node.
+// The child is recorded on the parent like one started by a decider command,
+// so the parent's close policy and execution tree cover it, and when it closes
+// the activity completes with its result or fails with its failure. Repeated
+// calls for the same activity return the child already started.
+func (s *Service) StartChildWorkflowForActivity(ctx context.Context, taskToken []byte, attr *historyv1.StartChildWorkflowExecutionCommandAttributes) (*types.ChildExecutionInfo, error) {
+	key, ai, err := s.resolveAsyncActivity(ctx, taskToken)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if child := pendingChildForNode(state, ai.ActivityID); child != nil {
+		return child, nil
+	}
+
+	attr = proto.Clone(attr).(*historyv1.StartChildWorkflowExecutionCommandAttributes)
+	attr.NodeId = ai.ActivityID
+	child, event := s.prepareChildWorkflow(key, state, attr)
+	if child == nil {
+		closed := event.Attributes.(*types.ChildWorkflowCompletedAttributes)
+		s.notifyParentOfChildClose(ctx, key, closed)
+		return nil, fmt.Errorf("%w: %s", ErrChildWorkflowNotStarted, closed.FailureReason)
+	}
+
+	// The request ID makes concurrent starts for one pending activity record
+	// a single child.
+	requestID := fmt.Sprintf("start-child/%d/%s", ai.ScheduledEventID, ai.AsyncNonce)
+	eventID, duplicate, err := s.processEventsOnce(ctx, key, requestID, []*types.HistoryEvent{event})
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		state, err := s.stateStore.GetMutableState(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if started := pendingChildForNode(state, ai.ActivityID); started != nil {
+			return started, nil
+		}
+		return nil, ErrActivityNotPending
+	}
+
+	if err := s.startChildWorkflow(ctx, child); err != nil {
+		s.logger.Error("failed to start child workflow",
+			slog.String("parent_workflow_id", key.WorkflowID),
+			slog.String("child_workflow_id", child.key.WorkflowID),
+			slog.String("error", err.Error()),
+		)
+		s.notifyParentOfChildClose(ctx, key, &types.ChildWorkflowCompletedAttributes{
+			NodeID:        child.nodeID,
+			WorkflowID:    child.key.WorkflowID,
+			RunID:         child.key.RunID,
+			Status:        types.ExecutionStatusFailed,
+			FailureReason: fmt.Sprintf("failed to start child workflow: %v", err),
+		})
+		return nil, fmt.Errorf("%w: %v", ErrChildWorkflowNotStarted, err)
+	}
+
+	return &types.ChildExecutionInfo{
+		NodeID:            child.nodeID,
+		WorkflowID:        child.key.WorkflowID,
+		RunID:             child.key.RunID,
+		WorkflowType:      child.workflowType,
+		StartedEventID:    eventID,
+		StartedTime:       event.Timestamp,
+		ParentClosePolicy: child.closePolicy,
+	}, nil
+}
+
+func pendingChildForNode(state *engine.MutableState, nodeID string) *types.ChildExecutionInfo {
+	for _, child := range state.PendingChildren {
+		if child.NodeID == nodeID {
+			return child
+		}
+	}
+	return nil
+}
+
+// childActivityEvent returns the event that resolves the async activity
+// waiting on a child that closed with attrs, or nil when no activity of the
+// parent waits on it.
+func childActivityEvent(state *engine.MutableState, attrs *types.ChildWorkflowCompletedAttributes) *types.HistoryEvent {
+	if attrs.NodeID == "" {
+		return nil
+	}
+	for _, ai := range state.PendingActivities {
+		if ai.AsyncNonce == "" || ai.ActivityID != attrs.NodeID {
+			continue
+		}
+		if attrs.Status == types.ExecutionStatusCompleted {
+			return &types.HistoryEvent{
+				EventType: types.EventTypeNodeCompleted,
+				Timestamp: time.Now(),
+				Attributes: &types.NodeCompletedAttributes{
+					NodeID:           ai.ActivityID,
+					ScheduledEventID: ai.ScheduledEventID,
+					StartedEventID:   ai.StartedEventID,
+					Result:           attrs.Result,
+				},
+			}
+		}
+		reason := attrs.FailureReason
+		if reason == "" {
+			reason = "child workflow failed"
+		}
+		return &types.HistoryEvent{
+			EventType: types.EventTypeNodeFailed,
+			Timestamp: time.Now(),
+			Attributes: &types.NodeFailedAttributes{
+				NodeID:           ai.ActivityID,
+				ScheduledEventID: ai.ScheduledEventID,
+				StartedEventID:   ai.StartedEventID,
+				Reason:           reason,
+			},
+		}
+	}
+	return nil
+}
+
+// cancelActivityChild applies the close policy of the child started for the
+// async activity ai, which timed out before the child closed.
+func (s *Service) cancelActivityChild(ctx context.Context, key types.ExecutionKey, state *engine.MutableState, ai *types.ActivityInfo) {
+	child := pendingChildForNode(state, ai.ActivityID)
+	if child == nil {
+		return
+	}
+	if err := s.applyParentClosePolicy(ctx, key, child, "parent node timed out"); err != nil {
+		s.logger.Warn("failed to cancel child workflow of timed out node",
+			slog.String("parent_workflow_id", key.WorkflowID),
+			slog.String("node_id", ai.ActivityID),
+			slog.String("child_workflow_id", child.WorkflowID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// StartChildWorkflowForActivity starts the child workflow of the activity
+// identified by the request's task token.
+func (s *GRPCServer) StartChildWorkflowForActivity(ctx context.Context, req *historyv1.StartChildWorkflowForActivityRequest) (*historyv1.StartChildWorkflowForActivityResponse, error) {
+	if len(req.GetTaskToken()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "task token is required")
+	}
+
+	child, err := s.service.StartChildWorkflowForActivity(ctx, req.GetTaskToken(), req.GetAttributes())
+	if errors.Is(err, ErrChildWorkflowNotStarted) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &historyv1.StartChildWorkflowForActivityResponse{
+		NodeId: child.NodeID,
+		ChildExecution: &commonv1.WorkflowExecution{
+			WorkflowId: child.WorkflowID,
+			RunId:      child.RunID,
+		},
+		WorkflowType: &apiv1.WorkflowType{Name: child.WorkflowType},
+	}, nil
+}
@@ -0,0 +1,145 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// startSubWorkflowNode schedules node "call" on parent, leaves it pending and
+// starts its child workflow.
+func startSubWorkflowNode(t *testing.T, svc *Service, parent types.ExecutionKey, timeout time.Duration) *types.ChildExecutionInfo {
+	t.Helper()
+	ctx := context.Background()
+
+	err := svc.RecordEvent(ctx, parent, &types.HistoryEvent{
+		EventType: types.EventTypeNodeScheduled,
+		Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+			NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{
+				NodeId:    "call",
+				NodeType:  "sub_workflow",
+				TaskQueue: &apiv1.TaskQueue{Name: "default"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("schedule node: %v", err)
+	}
+	pending, err := svc.RecordActivityTaskPending(ctx, &historyv1.RecordActivityTaskPendingRequest{
+		Namespace:              parent.NamespaceID,
+		WorkflowExecution:      &commonv1.WorkflowExecution{WorkflowId: parent.WorkflowID, RunId: parent.RunID},
+		ScheduledEventId:       1,
+		NodeId:                 "call",
+		ScheduleToCloseTimeout: durationpb.New(timeout),
+	})
+	if err != nil {
+		t.Fatalf("record pending: %v", err)
+	}
+
+	attrs := &historyv1.StartChildWorkflowExecutionCommandAttributes{
+		WorkflowType: &apiv1.WorkflowType{Name: "sub_workflow"},
+		Input:        &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: []byte(`{}`)}}},
+	}
+	child, err := svc.StartChildWorkflowForActivity(ctx, pending.GetTaskToken(), attrs)
+	if err != nil {
+		t.Fatalf("start child: %v", err)
+	}
+	again, err := svc.StartChildWorkflowForActivity(ctx, pending.GetTaskToken(), attrs)
+	if err != nil {
+		t.Fatalf("start child again: %v", err)
+	}
+	if again.RunID != child.RunID {
+		t.Fatalf("second start created run %s, want existing run %s", again.RunID, child.RunID)
+	}
+	return child
+}
+
+func newChildActivityTestService(t *testing.T) (*Service, *store.MemoryEventStore, *store.MemoryMutableStateStore) {
+	t.Helper()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+	return svc, eventStore, stateStore
+}
+
+func TestChildWorkflowForActivityCompletesNode(t *testing.T) {
+	ctx := context.Background()
+	svc, eventStore, stateStore := newChildActivityTestService(t)
+	parent := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	seedRunningExecution(t, stateStore, parent, "")
+
+	child := startSubWorkflowNode(t, svc, parent, time.Hour)
+	if child.WorkflowID != "order-call" || child.NodeID != "call" {
+		t.Fatalf("unexpected child %+v", child)
+	}
+	childKey := types.ExecutionKey{NamespaceID: parent.NamespaceID, WorkflowID: child.WorkflowID, RunID: child.RunID}
+
+	err := svc.processEvents(ctx, childKey, []*types.HistoryEvent{{
+		EventType:  types.EventTypeExecutionCompleted,
+		Timestamp:  time.Now(),
+		Attributes: &types.ExecutionCompletedAttributes{Result: []byte(`{"total":42}`)},
+	}})
+	if err != nil {
+		t.Fatalf("complete child: %v", err)
+	}
+
+	state, err := stateStore.GetMutableState(ctx, parent)
+	if err != nil {
+		t.Fatalf("get parent state: %v", err)
+	}
+	if len(state.PendingActivities) != 0 || len(state.PendingChildren) != 0 {
+		t.Fatalf("expected node and child resolved, pending activities %d children %d", len(state.PendingActivities), len(state.PendingChildren))
+	}
+	completed, err := eventStore.GetEventsByType(ctx, parent, []types.EventType{types.EventTypeNodeCompleted}, 1, 10)
+	if err != nil {
+		t.Fatalf("get events: %v", err)
+	}
+	if len(completed) != 1 {
+		t.Fatalf("expected one NodeCompleted event, got %d", len(completed))
+	}
+	if result := string(completed[0].Attributes.(*types.NodeCompletedAttributes).Result); result != `{"total":42}` {
+		t.Fatalf("node result = %s, want the child's result", result)
+	}
+}
+
+func TestTimedOutActivityCancelsChildWorkflow(t *testing.T) {
+	ctx := context.Background()
+	svc, eventStore, stateStore := newChildActivityTestService(t)
+	parent := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	seedRunningExecution(t, stateStore, parent, "")
+
+	child := startSubWorkflowNode(t, svc, parent, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	state, err := stateStore.GetMutableState(ctx, parent)
+	if err != nil {
+		t.Fatalf("get parent state: %v", err)
+	}
+	svc.checkAsyncActivityTimeouts(ctx, parent, state)
+
+	childState, err := stateStore.GetMutableState(ctx, types.ExecutionKey{NamespaceID: parent.NamespaceID, WorkflowID: child.WorkflowID, RunID: child.RunID})
+	if err != nil {
+		t.Fatalf("get child state: %v", err)
+	}
+	if childState.ExecutionInfo.Status != types.ExecutionStatusTerminated {
+		t.Fatalf("child status = %v, want terminated", childState.ExecutionInfo.Status)
+	}
+
+	failed, err := eventStore.GetEventsByType(ctx, parent, []types.EventType{types.EventTypeNodeFailed, types.EventTypeNodeCompleted}, 1, 10)
+	if err != nil {
+		t.Fatalf("get events: %v", err)
+	}
+	if len(failed) != 1 || failed[0].EventType != types.EventTypeNodeFailed {
+		t.Fatalf("expected the node to fail once on timeout, got %d events", len(failed))
+	}
+}
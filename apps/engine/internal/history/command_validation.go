@@ -0,0 +1,160 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+)
+
+// ErrInvalidCommands is matched by the *CommandValidationError returned when
+// a workflow task completes with commands that cannot be applied together.
+var ErrInvalidCommands = errors.New("invalid workflow task commands")
+
+// CommandViolation describes one rejected command of a decision.
+type CommandViolation struct {
+	CommandIndex int
+	CommandType  historyv1.CommandType
+	Reason       string
+}
+
+// CommandValidationError lists every violation found in a decision. None of
+// its commands are applied; the workflow task is failed instead.
+type CommandValidationError struct {
+	Violations []CommandViolation
+}
+
+func (e *CommandValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("command %d (%s): %s", v.CommandIndex, v.CommandType, v.Reason)
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidCommands, strings.Join(parts, "; "))
+}
+
+func (e *CommandValidationError) Unwrap() error { return ErrInvalidCommands }
+
+// validateCommands checks a decision before any of it is translated into
+// events. knownNodes holds the node IDs of the workflow definition; when it
+// is nil, node references are not checked against the definition.
+func validateCommands(commands []*historyv1.Command, knownNodes map[string]bool) error {
+	var violations []CommandViolation
+	reject := func(index int, cmd *historyv1.Command, format string, args ...interface{}) {
+		violations = append(violations, CommandViolation{
+			CommandIndex: index,
+			CommandType:  cmd.GetCommandType(),
+			Reason:       fmt.Sprintf(format, args...),
+		})
+	}
+
+	closeIndex := -1
+	for i, cmd := range commands {
+		if isCloseCommand(cmd) {
+			if closeIndex >= 0 {
+				reject(i, cmd, "conflicts with command %d, which already closes the workflow", closeIndex)
+				continue
+			}
+			closeIndex = i
+		}
+	}
+
+	nodeCommands := make(map[string]int)
+	for i, cmd := range commands {
+		if cmd == nil {
+			violations = append(violations, CommandViolation{CommandIndex: i, Reason: "command is nil"})
+			continue
+		}
+
+		var nodeID string
+		switch cmd.GetCommandType() {
+		case historyv1.CommandType_COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK:
+			attr := cmd.GetScheduleActivityTaskAttributes()
+			if attr == nil {
+				reject(i, cmd, "missing schedule activity task attributes")
+				continue
+			}
+			nodeID = attr.GetNodeId()
+		case historyv1.CommandType_COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:
+			attr := cmd.GetStartChildWorkflowExecutionAttributes()
+			if attr == nil {
+				reject(i, cmd, "missing start child workflow attributes")
+				continue
+			}
+			nodeID = attr.GetNodeId()
+		case historyv1.CommandType_COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION:
+			if cmd.GetCompleteWorkflowExecutionAttributes() == nil {
+				reject(i, cmd, "missing complete workflow attributes")
+			}
+			continue
+		case historyv1.CommandType_COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION:
+			if cmd.GetFailWorkflowExecutionAttributes() == nil {
+				reject(i, cmd, "missing fail workflow attributes")
+			}
+			continue
+		case historyv1.CommandType_COMMAND_TYPE_UNSPECIFIED:
+			reject(i, cmd, "command type is unspecified")
+			continue
+		default:
+			if closeIndex >= 0 && i > closeIndex {
+				reject(i, cmd, "follows command %d, which closes the workflow", closeIndex)
+			}
+			continue
+		}
+
+		// Scheduling work in the decision that closes the workflow would
+		// leave nodes running in a closed execution, wherever it appears.
+		if closeIndex >= 0 {
+			reject(i, cmd, "schedules node %q in the same decision as workflow close command %d", nodeID, closeIndex)
+		}
+		switch {
+		case nodeID == "":
+			reject(i, cmd, "node ID is required")
+		case knownNodes != nil && !knownNodes[nodeID]:
+			reject(i, cmd, "node %q is not in the workflow definition", nodeID)
+		}
+		if nodeID == "" {
+			continue
+		}
+		if first, ok := nodeCommands[nodeID]; ok {
+			reject(i, cmd, "node %q is already scheduled by command %d", nodeID, first)
+			continue
+		}
+		nodeCommands[nodeID] = i
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &CommandValidationError{Violations: violations}
+}
+
+func isCloseCommand(cmd *historyv1.Command) bool {
+	switch cmd.GetCommandType() {
+	case historyv1.CommandType_COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION,
+		historyv1.CommandType_COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION:
+		return true
+	}
+	return false
+}
+
+// workflowNodeIDs returns the node IDs of the workflow definition carried in
+// an execution's input, or nil when the input has no definition.
+func workflowNodeIDs(input []byte) map[string]bool {
+	var payload struct {
+		Workflow struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+		} `json:"workflow"`
+	}
+	if len(input) == 0 || json.Unmarshal(input, &payload) != nil || len(payload.Workflow.Nodes) == 0 {
+		return nil
+	}
+	nodes := make(map[string]bool, len(payload.Workflow.Nodes))
+	for _, node := range payload.Workflow.Nodes {
+		nodes[node.ID] = true
+	}
+	return nodes
+}
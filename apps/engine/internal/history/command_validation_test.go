@@ -0,0 +1,171 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func scheduleCommand(nodeID string) *historyv1.Command {
+	return &historyv1.Command{
+		CommandType: historyv1.CommandType_COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK,
+		Attributes: &historyv1.Command_ScheduleActivityTaskAttributes{
+			ScheduleActivityTaskAttributes: &historyv1.ScheduleActivityTaskCommandAttributes{NodeId: nodeID, NodeType: "http"},
+		},
+	}
+}
+
+func startChildCommand(nodeID string) *historyv1.Command {
+	return &historyv1.Command{
+		CommandType: historyv1.CommandType_COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION,
+		Attributes: &historyv1.Command_StartChildWorkflowExecutionAttributes{
+			StartChildWorkflowExecutionAttributes: &historyv1.StartChildWorkflowExecutionCommandAttributes{NodeId: nodeID},
+		},
+	}
+}
+
+func completeCommand() *historyv1.Command {
+	return &historyv1.Command{
+		CommandType: historyv1.CommandType_COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION,
+		Attributes: &historyv1.Command_CompleteWorkflowExecutionAttributes{
+			CompleteWorkflowExecutionAttributes: &historyv1.CompleteWorkflowExecutionCommandAttributes{},
+		},
+	}
+}
+
+func failCommand() *historyv1.Command {
+	return &historyv1.Command{
+		CommandType: historyv1.CommandType_COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION,
+		Attributes: &historyv1.Command_FailWorkflowExecutionAttributes{
+			FailWorkflowExecutionAttributes: &historyv1.FailWorkflowExecutionCommandAttributes{
+				Failure: &commonv1.Failure{Message: "node failed"},
+			},
+		},
+	}
+}
+
+func markerCommand() *historyv1.Command {
+	return &historyv1.Command{
+		CommandType: historyv1.CommandType_COMMAND_TYPE_RECORD_VERSION_MARKER,
+		Attributes: &historyv1.Command_RecordVersionMarkerAttributes{
+			RecordVersionMarkerAttributes: &historyv1.RecordVersionMarkerCommandAttributes{ChangeId: "c", Version: 1},
+		},
+	}
+}
+
+func TestValidateCommands(t *testing.T) {
+	known := map[string]bool{"fetch": true, "notify": true, "child": true}
+
+	valid := [][]*historyv1.Command{
+		nil,
+		{markerCommand(), scheduleCommand("fetch"), startChildCommand("child")},
+		{markerCommand(), completeCommand()},
+		{failCommand()},
+	}
+	for i, commands := range valid {
+		if err := validateCommands(commands, known); err != nil {
+			t.Fatalf("valid decision %d rejected: %v", i, err)
+		}
+	}
+	if err := validateCommands([]*historyv1.Command{scheduleCommand("anything")}, nil); err != nil {
+		t.Fatalf("decision without a definition rejected: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		commands []*historyv1.Command
+		index    int
+		reason   string
+	}{
+		{"complete and fail", []*historyv1.Command{completeCommand(), failCommand()}, 1, "already closes the workflow"},
+		{"schedule after complete", []*historyv1.Command{completeCommand(), scheduleCommand("fetch")}, 1, "same decision as workflow close command 0"},
+		{"schedule before complete", []*historyv1.Command{scheduleCommand("fetch"), completeCommand()}, 0, "same decision as workflow close command 1"},
+		{"child after fail", []*historyv1.Command{failCommand(), startChildCommand("child")}, 1, "same decision as workflow close command 0"},
+		{"marker after complete", []*historyv1.Command{completeCommand(), markerCommand()}, 1, "follows command 0"},
+		{"duplicate node", []*historyv1.Command{scheduleCommand("fetch"), scheduleCommand("fetch")}, 1, `node "fetch" is already scheduled by command 0`},
+		{"duplicate node across kinds", []*historyv1.Command{scheduleCommand("child"), startChildCommand("child")}, 1, "already scheduled"},
+		{"unknown node", []*historyv1.Command{scheduleCommand("ghost")}, 0, `node "ghost" is not in the workflow definition`},
+		{"unknown child node", []*historyv1.Command{startChildCommand("ghost")}, 0, "not in the workflow definition"},
+		{"missing node ID", []*historyv1.Command{scheduleCommand("")}, 0, "node ID is required"},
+		{"missing attributes", []*historyv1.Command{{CommandType: historyv1.CommandType_COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK}}, 0, "missing schedule activity task attributes"},
+		{"unspecified type", []*historyv1.Command{{}}, 0, "unspecified"},
+		{"nil command", []*historyv1.Command{nil}, 0, "nil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCommands(tt.commands, known)
+			var validationErr *CommandValidationError
+			if !errors.As(err, &validationErr) || !errors.Is(err, ErrInvalidCommands) {
+				t.Fatalf("err = %v, want a command validation error", err)
+			}
+			for _, v := range validationErr.Violations {
+				if v.CommandIndex == tt.index && strings.Contains(v.Reason, tt.reason) {
+					return
+				}
+			}
+			t.Fatalf("violations = %+v, want command %d: %q", validationErr.Violations, tt.index, tt.reason)
+		})
+	}
+}
+
+func TestRespondWorkflowTaskCompletedRejectsInvalidCommands(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+		Input:       []byte(`{"workflow":{"nodes":[{"id":"fetch"}]}}`),
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	_, respondErr := svc.RespondWorkflowTaskCompleted(ctx, &historyv1.RespondWorkflowTaskCompletedRequest{
+		Namespace:         key.NamespaceID,
+		WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+		TaskToken:         3,
+		Commands:          []*historyv1.Command{scheduleCommand("fetch"), scheduleCommand("ghost")},
+	})
+	if !errors.Is(respondErr, ErrInvalidCommands) {
+		t.Fatalf("err = %v, want ErrInvalidCommands", respondErr)
+	}
+
+	scheduled, err := eventStore.GetEventCountByType(ctx, key, []types.EventType{types.EventTypeNodeScheduled})
+	if err != nil {
+		t.Fatalf("event count: %v", err)
+	}
+	failed, err := eventStore.GetEventCountByType(ctx, key, []types.EventType{types.EventTypeWorkflowTaskFailed})
+	if err != nil {
+		t.Fatalf("event count: %v", err)
+	}
+	if scheduled != 0 || failed != 1 {
+		t.Fatalf("recorded %d scheduled and %d workflow task failed events, want 0 and 1", scheduled, failed)
+	}
+
+	st, _ := status.FromError((&GRPCServer{}).toGRPCError(respondErr))
+	if st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+		t.Fatalf("grpc status = %v, want InvalidArgument with error info", st)
+	}
+	if info, ok := st.Details()[0].(*errdetails.ErrorInfo); !ok || info.Reason != ErrorReasonInvalidCommands || !strings.Contains(info.Metadata["command_1"], "ghost") {
+		t.Fatalf("error details = %+v", st.Details())
+	}
+}
@@ -0,0 +1,136 @@
+package history
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// DefaultEventCompactionInterval is how often event compaction runs when it
+// is enabled.
+const DefaultEventCompactionInterval = time.Hour
+
+// EventCompactionConfig enables event compaction: raw events of running
+// executions that are older than their namespace's retention and already
+// covered by the latest snapshot are deleted. State stays rebuildable from
+// the snapshot and the events after it.
+type EventCompactionConfig struct {
+	// Retention opts namespaces in, mapping each namespace ID to the age an
+	// event must reach before it may be compacted. Namespaces not listed are
+	// never compacted.
+	Retention map[string]time.Duration
+
+	// Interval is how often compaction runs (default
+	// DefaultEventCompactionInterval).
+	Interval time.Duration
+}
+
+// EventTrimmer is implemented by event stores that can delete a range of an
+// execution's events. Compaction needs it.
+type EventTrimmer interface {
+	TrimEvents(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64, olderThan time.Time) (int64, error)
+}
+
+func (s *Service) startEventCompactor() {
+	if len(s.compaction.Retention) == 0 {
+		return
+	}
+	if s.snapshotStore == nil {
+		s.logger.Warn("event compaction disabled: no snapshot store configured")
+		return
+	}
+	if _, ok := s.eventStore.(EventTrimmer); !ok {
+		s.logger.Warn("event compaction disabled: event store cannot trim events")
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.compaction.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), s.compaction.Interval)
+				s.compactEvents(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// compactEvents compacts the running executions of every opted-in namespace.
+func (s *Service) compactEvents(ctx context.Context) {
+	keys, err := s.stateStore.ListRunningExecutions(ctx)
+	if err != nil {
+		s.logger.Warn("failed to list running executions for event compaction", "error", err)
+		return
+	}
+
+	var total int64
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+		retention, ok := s.compaction.Retention[key.NamespaceID]
+		if !ok {
+			continue
+		}
+
+		compacted, err := s.compactExecutionEvents(ctx, key, time.Now().Add(-retention))
+		if err != nil {
+			s.logger.Warn("failed to compact execution events", "error", err, "workflow_id", key.WorkflowID, "run_id", key.RunID)
+			continue
+		}
+		total += compacted
+	}
+
+	if total > 0 {
+		s.logger.Info("compacted history events", slog.Int64("events", total))
+	}
+}
+
+// compactExecutionEvents deletes the events of key recorded before olderThan
+// that the latest snapshot already covers. It never trims past that snapshot,
+// so replaying it plus the remaining events always rebuilds the state, and it
+// keeps the first event, which carries the execution's input.
+func (s *Service) compactExecutionEvents(ctx context.Context, key types.ExecutionKey, olderThan time.Time) (int64, error) {
+	snapshot, err := s.snapshotStore.GetLatestSnapshot(ctx, key)
+	if err != nil || snapshot == nil || snapshot.State == nil {
+		// Without a snapshot every event is still needed to rebuild state.
+		return 0, nil
+	}
+
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	// A snapshot from ahead of the current state, e.g. one left behind by a
+	// reset, cannot be replayed forward from.
+	if snapshot.LastEventID >= state.NextEventID || snapshot.State.NextEventID != snapshot.LastEventID+1 {
+		s.logger.Warn("skipping event compaction: snapshot does not match execution state",
+			"workflow_id", key.WorkflowID, "run_id", key.RunID,
+			"snapshot_event_id", snapshot.LastEventID, "next_event_id", state.NextEventID)
+		return 0, nil
+	}
+	if snapshot.LastEventID < 2 {
+		return 0, nil
+	}
+
+	compacted, err := s.eventStore.(EventTrimmer).TrimEvents(ctx, key, 2, snapshot.LastEventID, olderThan)
+	if err != nil {
+		return 0, err
+	}
+	if compacted > 0 {
+		s.metrics.RecordEventsCompacted(int(compacted))
+		s.statsCache.remove(key)
+	}
+	return compacted, nil
+}
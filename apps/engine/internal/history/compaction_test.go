@@ -0,0 +1,110 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+type fakeSnapshotStore struct {
+	snapshots map[types.ExecutionKey]*engine.Snapshot
+}
+
+func (f *fakeSnapshotStore) SaveSnapshot(ctx context.Context, snapshot *engine.Snapshot) error {
+	f.snapshots[snapshot.ExecutionKey] = snapshot
+	return nil
+}
+
+func (f *fakeSnapshotStore) GetLatestSnapshot(ctx context.Context, key types.ExecutionKey) (*engine.Snapshot, error) {
+	return f.snapshots[key], nil
+}
+
+func (f *fakeSnapshotStore) DeleteSnapshots(ctx context.Context, key types.ExecutionKey) error {
+	delete(f.snapshots, key)
+	return nil
+}
+
+func TestCompactExecutionEventsKeepsSnapshotReplayable(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	snapshots := &fakeSnapshotStore{snapshots: make(map[types.ExecutionKey]*engine.Snapshot)}
+	svc := newTestService(t, Config{
+		EventStore:    eventStore,
+		StateStore:    stateStore,
+		SnapshotStore: snapshots,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	newState := func() *engine.MutableState {
+		return engine.NewMutableState(&types.ExecutionInfo{
+			NamespaceID: key.NamespaceID,
+			WorkflowID:  key.WorkflowID,
+			RunID:       key.RunID,
+			Status:      types.ExecutionStatusRunning,
+		})
+	}
+
+	now := time.Now()
+	var events []*types.HistoryEvent
+	for id := int64(1); id <= 6; id++ {
+		timestamp := now
+		if id <= 4 {
+			timestamp = now.Add(-48 * time.Hour)
+		}
+		events = append(events, &types.HistoryEvent{EventID: id, EventType: types.EventTypeNodeScheduled, Timestamp: timestamp,
+			Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+				NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{NodeId: "node", NodeType: "http"},
+			}})
+	}
+	if err := eventStore.AppendEvents(ctx, key, events, 0); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	state := newState()
+	state.NextEventID = 7
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	olderThan := now.Add(-24 * time.Hour)
+	if compacted, err := svc.compactExecutionEvents(ctx, key, olderThan); err != nil || compacted != 0 {
+		t.Fatalf("compact without snapshot = %d, %v; want 0", compacted, err)
+	}
+
+	snapshotState := newState()
+	for _, event := range events[:5] {
+		if err := snapshotState.ApplyEvent(event); err != nil {
+			t.Fatalf("apply event %d: %v", event.EventID, err)
+		}
+	}
+	snapshots.SaveSnapshot(ctx, &engine.Snapshot{ExecutionKey: key, State: snapshotState, LastEventID: 5})
+
+	// Events 2 to 4 are old and covered by the snapshot; event 1 is always
+	// kept and event 5 is too recent.
+	compacted, err := svc.compactExecutionEvents(ctx, key, olderThan)
+	if err != nil || compacted != 3 {
+		t.Fatalf("compact = %d, %v; want 3", compacted, err)
+	}
+	remaining, _ := eventStore.GetEvents(ctx, key, 1, 6)
+	var ids []int64
+	for _, event := range remaining {
+		ids = append(ids, event.EventID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 5 || ids[2] != 6 {
+		t.Fatalf("remaining events = %v, want [1 5 6]", ids)
+	}
+
+	result, err := svc.DescribeStateAtEvent(ctx, key, 6)
+	if err != nil {
+		t.Fatalf("DescribeStateAtEvent(6) after compaction: %v", err)
+	}
+	if result.SnapshotEventID != 5 || len(result.State.PendingNodes) != 6 {
+		t.Fatalf("snapshot event = %d, pending nodes = %d; want 5 and 6", result.SnapshotEventID, len(result.State.PendingNodes))
+	}
+}
@@ -0,0 +1,113 @@
+package history
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// DefaultConcurrencyReconcileInterval is how often namespace running-execution
+// counters are reset from visibility.
+const DefaultConcurrencyReconcileInterval = time.Minute
+
+func isExecutionCloseEvent(eventType types.EventType) bool {
+	switch eventType {
+	case types.EventTypeExecutionCompleted,
+		types.EventTypeExecutionFailed,
+		types.EventTypeExecutionTerminated,
+		types.EventTypeExecutionTimedOut,
+		types.EventTypeExecutionCanceled:
+		return true
+	}
+	return false
+}
+
+// executionCloseStatus returns the status an execution closes with on a close
+// event of eventType, or ExecutionStatusUnspecified for other events.
+func executionCloseStatus(eventType types.EventType) types.ExecutionStatus {
+	switch eventType {
+	case types.EventTypeExecutionCompleted:
+		return types.ExecutionStatusCompleted
+	case types.EventTypeExecutionFailed:
+		return types.ExecutionStatusFailed
+	case types.EventTypeExecutionTerminated:
+		return types.ExecutionStatusTerminated
+	case types.EventTypeExecutionTimedOut:
+		return types.ExecutionStatusTimedOut
+	case types.EventTypeExecutionCanceled:
+		return types.ExecutionStatusCanceled
+	}
+	return types.ExecutionStatusUnspecified
+}
+
+func (s *Service) releaseExecutionSlot(ctx context.Context, key types.ExecutionKey) {
+	if err := s.executionCounter.Release(ctx, key.NamespaceID); err != nil {
+		s.logger.Warn("failed to release execution slot",
+			"error", err, "namespace", key.NamespaceID, "workflow_id", key.WorkflowID)
+	}
+}
+
+func (s *Service) startConcurrencyReconciler() {
+	if s.executionCounter == nil || s.visibilityStore == nil {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.reconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				s.reconcileExecutionCounters(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// reconcileExecutionCounters resets each namespace's running-execution counter
+// to the visibility count, correcting drift from lost releases or crashes
+// between a start and its history event.
+func (s *Service) reconcileExecutionCounters(ctx context.Context) {
+	namespaces, err := s.executionCounter.Namespaces(ctx)
+	if err != nil {
+		s.logger.Warn("failed to list execution counters", "error", err)
+		return
+	}
+
+	for _, ns := range namespaces {
+		running, err := s.visibilityStore.CountOpenTopLevelExecutions(ctx, ns)
+		if err != nil {
+			s.logger.Warn("failed to count running executions", "error", err, "namespace", ns)
+			continue
+		}
+
+		previous, err := s.executionCounter.Get(ctx, ns)
+		if err != nil {
+			s.logger.Warn("failed to read execution counter", "error", err, "namespace", ns)
+			continue
+		}
+		if previous == running {
+			continue
+		}
+
+		if err := s.executionCounter.Set(ctx, ns, running); err != nil {
+			s.logger.Warn("failed to reconcile execution counter", "error", err, "namespace", ns)
+			continue
+		}
+		s.logger.Info("reconciled execution counter",
+			slog.String("namespace", ns),
+			slog.Int64("previous", previous),
+			slog.Int64("running", running),
+		)
+	}
+}
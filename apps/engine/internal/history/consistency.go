@@ -0,0 +1,302 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// ErrHistoryBehindState is returned when an execution's mutable state covers
+// events its history no longer holds, so it cannot be rebuilt from history.
+var ErrHistoryBehindState = errors.New("history is missing events the mutable state covers")
+
+const (
+	// verifyAttempts bounds how often VerifyExecution re-checks drift that
+	// may be an append still in flight.
+	verifyAttempts = 3
+	// verifyRecheckDelay is the base wait before such a re-check; it grows
+	// linearly with the attempt.
+	verifyRecheckDelay = 50 * time.Millisecond
+)
+
+// ExecutionConsistency is the outcome of comparing an execution's persisted
+// mutable state with the state its history replays to.
+type ExecutionConsistency struct {
+	NamespaceID string `json:"namespace_id"`
+	WorkflowID  string `json:"workflow_id"`
+	RunID       string `json:"run_id"`
+	// StoredNextEventID is the next event ID of the persisted state, and
+	// ReplayedNextEventID the one after the last event in history.
+	StoredNextEventID   int64 `json:"stored_next_event_id"`
+	ReplayedNextEventID int64 `json:"replayed_next_event_id"`
+	// SnapshotEventID is the last event of the snapshot the replay started
+	// from, or 0 when it replayed from the first event.
+	SnapshotEventID int64        `json:"snapshot_event_id,omitempty"`
+	Consistent      bool         `json:"consistent"`
+	Drift           []StateDrift `json:"drift,omitempty"`
+	// Repaired reports whether RepairExecution rewrote the state; Drift is
+	// then what it fixed.
+	Repaired bool `json:"repaired,omitempty"`
+}
+
+// StateDrift is one field on which the persisted state and the replayed
+// state disagree.
+type StateDrift struct {
+	Field    string `json:"field"`
+	Stored   string `json:"stored"`
+	Replayed string `json:"replayed"`
+}
+
+// VerifyExecution replays an execution's history into a fresh mutable state,
+// starting from the latest snapshot when one exists, and compares its next
+// event ID, status and pending nodes, activities, timers and children with
+// the persisted state. It is read-only and safe to run against running
+// executions: events are appended before the state is updated, so drift is
+// re-checked a few times and only reported once the state stops moving or
+// the history stays ahead of it.
+func (s *Service) VerifyExecution(ctx context.Context, key types.ExecutionKey) (*ExecutionConsistency, error) {
+	result, _, _, err := s.verifyExecution(ctx, key)
+	return result, err
+}
+
+// RepairExecution verifies an execution and, when its state has drifted,
+// replaces the persisted state with the one rebuilt from history. Request
+// IDs already applied are kept so retried calls stay deduplicated. Tasks for
+// pending work are not dispatched again; their timeouts recover them. A state
+// that covers events missing from history is not repaired and fails with
+// ErrHistoryBehindState; a concurrent update fails with
+// types.ErrOptimisticLock.
+func (s *Service) RepairExecution(ctx context.Context, key types.ExecutionKey) (*ExecutionConsistency, error) {
+	if _, err := s.shardController.GetShardForExecution(key); err != nil {
+		return nil, err
+	}
+
+	result, stored, replayed, err := s.verifyExecution(ctx, key)
+	if err != nil || result.Consistent {
+		return result, err
+	}
+	if result.ReplayedNextEventID < result.StoredNextEventID {
+		return result, fmt.Errorf("%w: state is at event %d, history at %d",
+			ErrHistoryBehindState, result.StoredNextEventID-1, result.ReplayedNextEventID-1)
+	}
+
+	replayed.AppliedRequests = stored.AppliedRequests
+	replayed.BufferedEvents = stored.BufferedEvents
+	replayed.DBVersion = stored.DBVersion + 1
+	if err := s.stateStore.UpdateMutableState(ctx, key, replayed, stored.DBVersion); err != nil {
+		return result, fmt.Errorf("failed to persist repaired state: %w", err)
+	}
+	s.statsCache.remove(key)
+
+	s.logger.Warn("repaired execution state from history",
+		"namespace", key.NamespaceID, "workflow_id", key.WorkflowID, "run_id", key.RunID,
+		"stored_next_event_id", result.StoredNextEventID, "replayed_next_event_id", result.ReplayedNextEventID,
+		"drifted_fields", len(result.Drift))
+	result.Repaired = true
+	return result, nil
+}
+
+// verifyExecution is VerifyExecution that also returns the persisted and the
+// replayed state of the last comparison.
+func (s *Service) verifyExecution(ctx context.Context, key types.ExecutionKey) (*ExecutionConsistency, *engine.MutableState, *engine.MutableState, error) {
+	for attempt := 1; ; attempt++ {
+		stored, err := s.stateStore.GetMutableState(ctx, key)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		replayed, snapshotEventID, err := s.replayExecution(ctx, key, stored.NextEventID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		result := &ExecutionConsistency{
+			NamespaceID:         key.NamespaceID,
+			WorkflowID:          key.WorkflowID,
+			RunID:               key.RunID,
+			StoredNextEventID:   stored.NextEventID,
+			ReplayedNextEventID: replayed.NextEventID,
+			SnapshotEventID:     snapshotEventID,
+			Drift:               compareMutableState(stored, replayed),
+		}
+		result.Consistent = len(result.Drift) == 0
+		if result.Consistent || attempt == verifyAttempts {
+			return result, stored, replayed, nil
+		}
+
+		// History ahead of the state may be an append whose state update has
+		// not landed yet. Give the writer time and look again; drift that
+		// outlives the re-checks is real.
+		select {
+		case <-ctx.Done():
+			return nil, nil, nil, ctx.Err()
+		case <-time.After(time.Duration(attempt) * verifyRecheckDelay):
+		}
+	}
+}
+
+// replayExecution rebuilds an execution's state from its latest valid
+// snapshot and every event after it, returning the snapshot's last event or
+// 0 when it replayed from the first event. Snapshots are taken of persisted
+// state, so one at or past nextEventID, e.g. left behind by a reset, is not
+// used.
+func (s *Service) replayExecution(ctx context.Context, key types.ExecutionKey, nextEventID int64) (*engine.MutableState, int64, error) {
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+	})
+	var snapshotEventID int64
+	if s.snapshotStore != nil {
+		snapshot, err := s.snapshotStore.GetLatestSnapshot(ctx, key)
+		if err != nil {
+			s.logger.Debug("no snapshot for state replay", "error", err, "workflow_id", key.WorkflowID)
+		} else if snapshot != nil && snapshot.State != nil &&
+			snapshot.LastEventID < nextEventID && snapshot.State.NextEventID == snapshot.LastEventID+1 {
+			state = snapshot.State.Clone()
+			snapshotEventID = snapshot.LastEventID
+		}
+	}
+
+	firstEventID := snapshotEventID + 1
+	events, err := s.eventStore.GetEvents(ctx, key, firstEventID, math.MaxInt64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch events: %w", err)
+	}
+	if snapshotEventID == 0 && len(events) == 0 {
+		return nil, 0, fmt.Errorf("%w: no history", types.ErrExecutionNotFound)
+	}
+	for i, event := range events {
+		if event.EventID != firstEventID+int64(i) {
+			return nil, 0, fmt.Errorf("history is missing events between %d and %d", firstEventID+int64(i), event.EventID-1)
+		}
+		if err := state.ApplyEvent(event); err != nil {
+			return nil, 0, fmt.Errorf("failed to replay event %d: %w", event.EventID, err)
+		}
+	}
+	return state, snapshotEventID, nil
+}
+
+// compareMutableState lists the event-derived fields on which stored and
+// replayed differ. Pending nodes are skipped for state persisted before they
+// were tracked.
+func compareMutableState(stored, replayed *engine.MutableState) []StateDrift {
+	var drift []StateDrift
+	add := func(field, storedValue, replayedValue string) {
+		if storedValue != replayedValue {
+			drift = append(drift, StateDrift{Field: field, Stored: storedValue, Replayed: replayedValue})
+		}
+	}
+
+	add("next_event_id", fmt.Sprint(stored.NextEventID), fmt.Sprint(replayed.NextEventID))
+	add("status", executionStatusName(stored.ExecutionInfo), executionStatusName(replayed.ExecutionInfo))
+	if stored.PendingNodes != nil {
+		add("pending_nodes", eventIDSet(stored.PendingNodes), eventIDSet(replayed.PendingNodes))
+	}
+	add("pending_activities", eventIDSet(stored.PendingActivities), eventIDSet(replayed.PendingActivities))
+	add("pending_timers", stringKeySet(stored.PendingTimers), stringKeySet(replayed.PendingTimers))
+	add("pending_children", stringKeySet(stored.PendingChildren), stringKeySet(replayed.PendingChildren))
+	return drift
+}
+
+func executionStatusName(info *types.ExecutionInfo) string {
+	if info == nil {
+		return commonv1.ExecutionStatus_EXECUTION_STATUS_UNSPECIFIED.String()
+	}
+	return internalExecutionStatusToProto(info.Status).String()
+}
+
+// eventIDSet formats the keys of a map keyed by scheduled event ID in order.
+func eventIDSet[V any](m map[int64]V) string {
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return fmt.Sprint(ids)
+}
+
+func stringKeySet[V any](m map[string]V) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return "[" + strings.Join(keys, " ") + "]"
+}
+
+// VerifyExecution compares the request's execution state with its history.
+func (s *GRPCServer) VerifyExecution(ctx context.Context, req *historyv1.VerifyExecutionRequest) (*historyv1.VerifyExecutionResponse, error) {
+	key, err := consistencyRequestKey(req.GetNamespace(), req.GetWorkflowExecution())
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.service.VerifyExecution(ctx, key)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return &historyv1.VerifyExecutionResponse{Consistency: consistencyToProto(result)}, nil
+}
+
+// RepairExecution rebuilds the request's execution state from its history
+// when the two have drifted.
+func (s *GRPCServer) RepairExecution(ctx context.Context, req *historyv1.RepairExecutionRequest) (*historyv1.RepairExecutionResponse, error) {
+	key, err := consistencyRequestKey(req.GetNamespace(), req.GetWorkflowExecution())
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.service.RepairExecution(ctx, key)
+	if errors.Is(err, ErrHistoryBehindState) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return &historyv1.RepairExecutionResponse{Consistency: consistencyToProto(result)}, nil
+}
+
+func consistencyRequestKey(namespace string, execution *commonv1.WorkflowExecution) (types.ExecutionKey, error) {
+	key := types.ExecutionKey{
+		NamespaceID: namespace,
+		WorkflowID:  execution.GetWorkflowId(),
+		RunID:       execution.GetRunId(),
+	}
+	if key.WorkflowID == "" || key.RunID == "" {
+		return key, status.Error(codes.InvalidArgument, "workflow_id and run_id are required")
+	}
+	return key, nil
+}
+
+func consistencyToProto(result *ExecutionConsistency) *historyv1.ExecutionConsistency {
+	out := &historyv1.ExecutionConsistency{
+		Namespace: result.NamespaceID,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: result.WorkflowID,
+			RunId:      result.RunID,
+		},
+		StoredNextEventId:   result.StoredNextEventID,
+		ReplayedNextEventId: result.ReplayedNextEventID,
+		SnapshotEventId:     result.SnapshotEventID,
+		Consistent:          result.Consistent,
+		Repaired:            result.Repaired,
+	}
+	for _, drift := range result.Drift {
+		out.Drift = append(out.Drift, &historyv1.StateDrift{
+			Field:    drift.Field,
+			Stored:   drift.Stored,
+			Replayed: drift.Replayed,
+		})
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestVerifyAndRepairExecution(t *testing.T) {
+	ctx := context.Background()
+	svc, eventStore, stateStore := newChildActivityTestService(t)
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+
+	scheduled := func(nodeID string) *types.HistoryEvent {
+		return &types.HistoryEvent{
+			EventType: types.EventTypeNodeScheduled,
+			Timestamp: time.Now(),
+			Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+				NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{
+					NodeId:    nodeID,
+					NodeType:  "http",
+					TaskQueue: &apiv1.TaskQueue{Name: "default"},
+				},
+			},
+		}
+	}
+	err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType:  types.EventTypeExecutionStarted,
+		Timestamp:  time.Now(),
+		Attributes: &types.ExecutionStartedAttributes{WorkflowType: "order", TaskQueue: "default"},
+	})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := svc.RecordEvent(ctx, key, scheduled("fetch")); err != nil {
+		t.Fatalf("schedule fetch: %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	historyv1.RegisterHistoryServiceServer(server, NewGRPCServer(svc))
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := historyv1.NewHistoryServiceClient(conn)
+	execution := &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID}
+
+	verified, err := client.VerifyExecution(ctx, &historyv1.VerifyExecutionRequest{Namespace: key.NamespaceID, WorkflowExecution: execution})
+	if err != nil || !verified.GetConsistency().GetConsistent() || verified.GetConsistency().GetStoredNextEventId() != 3 {
+		t.Fatalf("verify healthy execution = %+v, %v", verified, err)
+	}
+
+	// A crash between appending an event and updating the state leaves the
+	// history ahead.
+	lost := scheduled("notify")
+	lost.EventID = 3
+	if err := eventStore.AppendEvents(ctx, key, []*types.HistoryEvent{lost}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	verified, err = client.VerifyExecution(ctx, &historyv1.VerifyExecutionRequest{Namespace: key.NamespaceID, WorkflowExecution: execution})
+	if err != nil || verified.GetConsistency().GetConsistent() || verified.GetConsistency().GetReplayedNextEventId() != 4 {
+		t.Fatalf("verify drifted execution = %+v, %v", verified, err)
+	}
+	fields := map[string]bool{}
+	for _, drift := range verified.GetConsistency().GetDrift() {
+		fields[drift.GetField()] = true
+	}
+	if len(fields) != 2 || !fields["next_event_id"] || !fields["pending_nodes"] {
+		t.Fatalf("drift = %+v, want next_event_id and pending_nodes", verified.GetConsistency().GetDrift())
+	}
+
+	repaired, err := client.RepairExecution(ctx, &historyv1.RepairExecutionRequest{Namespace: key.NamespaceID, WorkflowExecution: execution})
+	if err != nil || !repaired.GetConsistency().GetRepaired() {
+		t.Fatalf("repair = %+v, %v", repaired, err)
+	}
+	state, err := stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if state.NextEventID != 4 || len(state.PendingNodes) != 2 {
+		t.Fatalf("repaired state at %d with pending nodes %v", state.NextEventID, state.PendingNodes)
+	}
+	if result, err := svc.VerifyExecution(ctx, key); err != nil || !result.Consistent {
+		t.Fatalf("verify after repair = %+v, %v", result, err)
+	}
+	// Repairing a consistent execution changes nothing.
+	if result, err := svc.RepairExecution(ctx, key); err != nil || result.Repaired {
+		t.Fatalf("second repair = %+v, %v", result, err)
+	}
+
+	// State that covers events history lacks cannot be rebuilt from it.
+	state.NextEventID = 9
+	if err := stateStore.UpdateMutableState(ctx, key, state, state.DBVersion); err != nil {
+		t.Fatalf("update state: %v", err)
+	}
+	if _, err := svc.RepairExecution(ctx, key); !errors.Is(err, ErrHistoryBehindState) {
+		t.Fatalf("repair with missing history = %v, want ErrHistoryBehindState", err)
+	}
+	if _, err := client.RepairExecution(ctx, &historyv1.RepairExecutionRequest{Namespace: key.NamespaceID, WorkflowExecution: execution}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("repair RPC with missing history = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestVerifyExecutionToleratesInFlightAppend(t *testing.T) {
+	ctx := context.Background()
+	svc, eventStore, stateStore := newChildActivityTestService(t)
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType:  types.EventTypeExecutionStarted,
+		Timestamp:  time.Now(),
+		Attributes: &types.ExecutionStartedAttributes{WorkflowType: "order", TaskQueue: "default"},
+	})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	// The event lands now and the state update shortly after, as a write in
+	// progress would.
+	signal := &types.HistoryEvent{EventID: 2, EventType: types.EventTypeSignalReceived, Timestamp: time.Now()}
+	if err := eventStore.AppendEvents(ctx, key, []*types.HistoryEvent{signal}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	go func() {
+		time.Sleep(verifyRecheckDelay / 2)
+		state, _ := stateStore.GetMutableState(ctx, key)
+		_ = state.ApplyEvent(signal)
+		state.DBVersion++
+		_ = stateStore.UpdateMutableState(ctx, key, state, state.DBVersion-1)
+	}()
+
+	result, err := svc.VerifyExecution(ctx, key)
+	if err != nil || !result.Consistent || result.StoredNextEventID != 3 {
+		t.Fatalf("verify during append = %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,45 @@
+package history
+
+import (
+	"context"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CorrelationIDHeaderField is the ExecutionStarted header field carrying the
+// caller's correlation ID, such as an order ID. History records it on the
+// execution and in its own visibility column.
+const CorrelationIDHeaderField = "correlation_id"
+
+// ListExecutionsByCorrelationID lists the runs of a namespace started with
+// correlationID, in any status and most recently started first.
+func (s *Service) ListExecutionsByCorrelationID(ctx context.Context, namespaceID, correlationID string, pageSize int, nextPageToken []byte) (*historyv1.ListExecutionsByCorrelationIDResponse, error) {
+	if s.visibilityStore == nil {
+		return nil, ErrVisibilityNotConfigured
+	}
+
+	resp, err := s.visibilityStore.ListExecutionsByCorrelationID(ctx, namespaceID, correlationID, pageSize, nextPageToken)
+	if err != nil {
+		return nil, err
+	}
+	return &historyv1.ListExecutionsByCorrelationIDResponse{
+		Executions:    executionInfosToProto(resp.Executions),
+		NextPageToken: resp.NextPageToken,
+	}, nil
+}
+
+// ListExecutionsByCorrelationID lists the runs started with the request's
+// correlation ID.
+func (s *GRPCServer) ListExecutionsByCorrelationID(ctx context.Context, req *historyv1.ListExecutionsByCorrelationIDRequest) (*historyv1.ListExecutionsByCorrelationIDResponse, error) {
+	if req.GetCorrelationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "correlation ID is required")
+	}
+
+	resp, err := s.service.ListExecutionsByCorrelationID(ctx, req.GetNamespace(), req.GetCorrelationId(), int(req.GetPageSize()), req.GetNextPageToken())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return resp, nil
+}
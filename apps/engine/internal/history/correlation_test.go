@@ -0,0 +1,116 @@
+package history
+
+import (
+	"context"
+	"net"
+	"sort"
+	"testing"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/frontend"
+	"github.com/linkflow/engine/internal/frontend/adapter"
+	"github.com/linkflow/engine/internal/history/visibility"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// correlationVisibilityStore keeps started runs and lists them by
+// correlation ID, newest first, in a single page.
+type correlationVisibilityStore struct {
+	visibility.Store
+	started []*visibility.WorkflowExecutionInfo
+}
+
+func (m *correlationVisibilityStore) RecordWorkflowExecutionStarted(_ context.Context, req *visibility.RecordWorkflowExecutionStartedRequest) error {
+	m.started = append(m.started, &visibility.WorkflowExecutionInfo{
+		Execution:     req.Execution,
+		Type:          req.WorkflowType,
+		StartTime:     req.StartTime,
+		Status:        req.Status,
+		CorrelationID: req.CorrelationID,
+	})
+	return nil
+}
+
+func (m *correlationVisibilityStore) ListExecutionsByCorrelationID(_ context.Context, _, correlationID string, _ int, _ []byte) (*visibility.ListResponse, error) {
+	resp := &visibility.ListResponse{}
+	for _, info := range m.started {
+		if info.CorrelationID == correlationID {
+			resp.Executions = append(resp.Executions, info)
+		}
+	}
+	sort.SliceStable(resp.Executions, func(i, j int) bool {
+		return resp.Executions[i].StartTime.After(resp.Executions[j].StartTime)
+	})
+	return resp, nil
+}
+
+func TestListExecutionsByCorrelationID(t *testing.T) {
+	ctx := context.Background()
+	vis := &correlationVisibilityStore{}
+	svc := newTestService(t, Config{
+		VisibilityStore: vis,
+	})
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	grpcServer := NewGRPCServer(svc)
+	historyv1.RegisterHistoryServiceServer(server, grpcServer)
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := adapter.NewHistoryClient(conn)
+
+	// Two runs share the order's correlation ID; a third has its own.
+	for _, run := range []struct{ workflowID, runID, correlationID string }{
+		{"checkout", "run-1", "order-42"},
+		{"checkout", "run-2", "order-42"},
+		{"checkout", "run-3", "order-7"},
+	} {
+		err := client.RecordEvent(ctx, &frontend.RecordEventRequest{
+			NamespaceID: "default",
+			WorkflowID:  run.workflowID,
+			RunID:       run.runID,
+			EventType:   "WorkflowExecutionStarted",
+			Attributes: &frontend.ExecutionStartedAttributes{
+				WorkflowType:  "checkout",
+				TaskQueue:     "default",
+				CorrelationID: run.correlationID,
+			},
+		})
+		if err != nil {
+			t.Fatalf("start %s: %v", run.runID, err)
+		}
+	}
+
+	resp, err := client.ListExecutionsByCorrelationID(ctx, &frontend.ListExecutionsByCorrelationIDRequest{
+		Namespace:     "default",
+		CorrelationID: "order-42",
+	})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var runIDs []string
+	for _, execution := range resp.Executions {
+		runIDs = append(runIDs, execution.RunID)
+	}
+	sort.Strings(runIDs)
+	if len(runIDs) != 2 || runIDs[0] != "run-1" || runIDs[1] != "run-2" {
+		t.Fatalf("listed runs %v, want run-1 and run-2", runIDs)
+	}
+
+	_, err = grpcServer.ListExecutionsByCorrelationID(ctx, &historyv1.ListExecutionsByCorrelationIDRequest{Namespace: "default"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without a correlation ID, got %v", err)
+	}
+}
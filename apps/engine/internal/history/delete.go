@@ -0,0 +1,83 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// ErrExecutionRunning is returned when deleting an execution that has not closed.
+var ErrExecutionRunning = errors.New("execution is still running")
+
+// DeleteWorkflowExecution permanently removes a closed execution from
+// visibility, the snapshot store, the event store and the mutable-state store.
+// Deletion is best-effort: every other store is attempted even if one fails
+// and the failures are returned together. Mutable state is only deleted once
+// the rest succeeded, so a retry after a partial failure still finds the
+// execution and its closed status. It returns the deleted run ID, resolved
+// from the current run when key.RunID is empty.
+func (s *Service) DeleteWorkflowExecution(ctx context.Context, key types.ExecutionKey) (string, error) {
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	switch {
+	case err == nil:
+		if info := state.ExecutionInfo; info != nil {
+			if info.Status == types.ExecutionStatusRunning {
+				return "", ErrExecutionRunning
+			}
+			if key.RunID == "" {
+				key.RunID = info.RunID
+			}
+		}
+	case errors.Is(err, types.ErrExecutionNotFound) && key.RunID != "":
+		// Mutable state is gone but other stores may still hold rows from an
+		// earlier partial delete.
+	default:
+		return "", err
+	}
+	if key.RunID == "" {
+		return "", types.ErrExecutionNotFound
+	}
+
+	var errs []error
+	if s.visibilityStore != nil {
+		if err := s.visibilityStore.DeleteWorkflowExecution(ctx, key.NamespaceID, key.RunID); err != nil {
+			errs = append(errs, fmt.Errorf("visibility: %w", err))
+		}
+	}
+	if s.snapshotStore != nil {
+		if err := s.snapshotStore.DeleteSnapshots(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("snapshot store: %w", err))
+		}
+	}
+	if err := s.eventStore.DeleteEvents(ctx, key); err != nil {
+		errs = append(errs, fmt.Errorf("event store: %w", err))
+	}
+	if len(errs) == 0 {
+		if err := s.stateStore.DeleteMutableState(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("mutable state store: %w", err))
+		}
+	}
+
+	s.statsCache.remove(key)
+
+	if len(errs) > 0 {
+		err := fmt.Errorf("failed to delete execution: %w", errors.Join(errs...))
+		s.logger.Warn("workflow execution partially deleted",
+			slog.String("namespace", key.NamespaceID),
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("run_id", key.RunID),
+			slog.String("error", err.Error()),
+		)
+		return key.RunID, err
+	}
+
+	s.logger.Info("workflow execution deleted",
+		slog.String("namespace", key.NamespaceID),
+		slog.String("workflow_id", key.WorkflowID),
+		slog.String("run_id", key.RunID),
+	)
+	return key.RunID, nil
+}
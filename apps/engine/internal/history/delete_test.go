@@ -0,0 +1,126 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"github.com/linkflow/engine/internal/history/visibility"
+)
+
+// deletingVisibilityStore accepts visibility records and remembers the runs
+// deleted from it.
+type deletingVisibilityStore struct {
+	visibility.Store
+	deleted []string
+}
+
+func (s *deletingVisibilityStore) RecordWorkflowExecutionStarted(context.Context, *visibility.RecordWorkflowExecutionStartedRequest) error {
+	return nil
+}
+
+func (s *deletingVisibilityStore) RecordWorkflowExecutionClosed(context.Context, *visibility.RecordWorkflowExecutionClosedRequest) error {
+	return nil
+}
+
+func (s *deletingVisibilityStore) DeleteWorkflowExecution(_ context.Context, _, runID string) error {
+	s.deleted = append(s.deleted, runID)
+	return nil
+}
+
+// flakyDeleteEventStore fails the first DeleteEvents call.
+type flakyDeleteEventStore struct {
+	*store.MemoryEventStore
+	failed bool
+}
+
+func (s *flakyDeleteEventStore) DeleteEvents(ctx context.Context, key types.ExecutionKey) error {
+	if !s.failed {
+		s.failed = true
+		return errors.New("connection reset")
+	}
+	return s.MemoryEventStore.DeleteEvents(ctx, key)
+}
+
+func TestDeleteWorkflowExecution(t *testing.T) {
+	tests := []struct {
+		name       string
+		close      bool
+		flakyStore bool
+		key        types.ExecutionKey
+		wantErr    error
+		wantGone   bool
+	}{
+		{name: "closed", close: true, key: types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}, wantGone: true},
+		{name: "running", key: types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}, wantErr: ErrExecutionRunning},
+		{name: "unknown run", close: true, key: types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-2"}, wantGone: false},
+		{name: "partial failure keeps state", close: true, flakyStore: true, key: types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			var eventStore store.EventStore = store.NewMemoryEventStore()
+			if tt.flakyStore {
+				eventStore = &flakyDeleteEventStore{MemoryEventStore: store.NewMemoryEventStore()}
+			}
+			stateStore := store.NewMemoryMutableStateStore()
+			visibilityStore := &deletingVisibilityStore{}
+			svc := newTestService(t, Config{
+				EventStore:      eventStore,
+				StateStore:      stateStore,
+				VisibilityStore: visibilityStore,
+			})
+
+			key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+			startArchivalTestExecution(t, svc, key)
+			if tt.close {
+				if err := svc.RecordEvent(ctx, key, &types.HistoryEvent{EventType: types.EventTypeExecutionCompleted, Timestamp: time.Now()}); err != nil {
+					t.Fatalf("close execution: %v", err)
+				}
+			}
+
+			runID, err := svc.DeleteWorkflowExecution(ctx, tt.key)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("delete error = %v, want %v", err, tt.wantErr)
+				}
+				if _, err := stateStore.GetMutableState(ctx, key); err != nil {
+					t.Fatalf("rejected delete removed state: %v", err)
+				}
+				return
+			}
+
+			if tt.flakyStore {
+				if err == nil {
+					t.Fatal("delete succeeded despite the event store failing")
+				}
+				if _, err := stateStore.GetMutableState(ctx, key); err != nil {
+					t.Fatalf("partial delete removed mutable state: %v", err)
+				}
+				// A retry finishes the delete.
+				if runID, err = svc.DeleteWorkflowExecution(ctx, tt.key); err != nil {
+					t.Fatalf("retried delete: %v", err)
+				}
+				tt.wantGone = true
+			} else if err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+
+			if runID != tt.key.RunID || visibilityStore.deleted[len(visibilityStore.deleted)-1] != tt.key.RunID {
+				t.Fatalf("deleted run %q, visibility deletes %v", runID, visibilityStore.deleted)
+			}
+			_, stateErr := stateStore.GetMutableState(ctx, key)
+			count, err := eventStore.GetEventCount(ctx, key)
+			if err != nil {
+				t.Fatalf("event count: %v", err)
+			}
+			if gone := errors.Is(stateErr, types.ErrExecutionNotFound) && count == 0; gone != tt.wantGone {
+				t.Fatalf("execution gone = %v (state err %v, %d events), want %v", gone, stateErr, count, tt.wantGone)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// PendingActivity is an activity or node that was scheduled and has not
+// completed, failed or timed out yet.
+type PendingActivity struct {
+	ScheduledEventID int64
+	ActivityID       string
+	ActivityType     string
+	Started          bool
+	ScheduledTime    time.Time
+	LastStartedTime  time.Time
+	LastHeartbeat    time.Time
+	// Attempt counts the times the activity has been scheduled, starting at
+	// 1, or is the attempt of its last NodeStarted event when that is higher.
+	Attempt         int32
+	LastFailure     string
+	LastFailureTime time.Time
+}
+
+// ExecutionDescription is an execution together with the work it is waiting
+// on.
+type ExecutionDescription struct {
+	Info              types.ExecutionInfo
+	PendingActivities []PendingActivity
+	PendingTimers     []types.TimerInfo
+	// VersionMarkers are reported for closed executions too, in event order.
+	VersionMarkers []types.VersionMarker
+}
+
+// nodeAttempt tracks a scheduled node while scanning its events.
+type nodeAttempt struct {
+	pending PendingActivity
+	closed  bool
+}
+
+// nodeFailure is the most recent failure recorded for a node ID.
+type nodeFailure struct {
+	reason string
+	time   time.Time
+}
+
+// DescribeWorkflowExecution returns an execution with its pending activities,
+// timers and recorded version markers. Pending work is read from the mutable state and, for nodes,
+// from the node events; closed executions report none.
+func (s *Service) DescribeWorkflowExecution(ctx context.Context, key types.ExecutionKey) (*ExecutionDescription, error) {
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &ExecutionDescription{
+		PendingActivities: []PendingActivity{},
+		PendingTimers:     []types.TimerInfo{},
+		VersionMarkers:    make([]types.VersionMarker, 0, len(state.VersionMarkers)),
+	}
+	if state.ExecutionInfo != nil {
+		desc.Info = *state.ExecutionInfo
+	}
+	for _, marker := range state.VersionMarkers {
+		desc.VersionMarkers = append(desc.VersionMarkers, *marker)
+	}
+	sort.Slice(desc.VersionMarkers, func(i, j int) bool {
+		return desc.VersionMarkers[i].EventID < desc.VersionMarkers[j].EventID
+	})
+	if desc.Info.Status != types.ExecutionStatusRunning {
+		return desc, nil
+	}
+
+	attempts, err := s.scanPendingNodes(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	for scheduledID, info := range state.PendingActivities {
+		attempt, ok := attempts[scheduledID]
+		if !ok {
+			attempt = &nodeAttempt{pending: PendingActivity{
+				ScheduledEventID: scheduledID,
+				ActivityID:       info.ActivityID,
+				ActivityType:     info.ActivityType,
+				ScheduledTime:    info.ScheduledTime,
+				Attempt:          info.Attempt,
+			}}
+			attempts[scheduledID] = attempt
+		}
+		if attempt.closed {
+			continue
+		}
+		pending := &attempt.pending
+		if !info.StartedTime.IsZero() {
+			pending.Started = true
+			pending.LastStartedTime = info.StartedTime
+		}
+		if info.AsyncNonce != "" {
+			pending.Started = true
+		}
+		pending.LastHeartbeat = info.LastHeartbeat
+		if pending.Attempt < 1 {
+			pending.Attempt = 1
+		}
+	}
+
+	for _, attempt := range attempts {
+		if !attempt.closed {
+			desc.PendingActivities = append(desc.PendingActivities, attempt.pending)
+		}
+	}
+	sort.Slice(desc.PendingActivities, func(i, j int) bool {
+		return desc.PendingActivities[i].ScheduledEventID < desc.PendingActivities[j].ScheduledEventID
+	})
+
+	for _, timer := range state.PendingTimers {
+		desc.PendingTimers = append(desc.PendingTimers, *timer)
+	}
+	sort.Slice(desc.PendingTimers, func(i, j int) bool {
+		return desc.PendingTimers[i].FireTime.Before(desc.PendingTimers[j].FireTime)
+	})
+
+	return desc, nil
+}
+
+// scanPendingNodes reads the node events of an execution and returns every
+// scheduled node attempt keyed by its NodeScheduled event ID. Attempts that
+// have completed, failed or timed out are marked closed. Each attempt carries
+// the number of times its node was scheduled and the node's last failure
+// before it.
+func (s *Service) scanPendingNodes(ctx context.Context, key types.ExecutionKey) (map[int64]*nodeAttempt, error) {
+	attempts := make(map[int64]*nodeAttempt)
+	scheduleCounts := make(map[string]int32)
+	failures := make(map[string]nodeFailure)
+
+	firstEventID := int64(1)
+	for {
+		events, err := s.eventStore.GetEventsByType(ctx, key, nodeEventTypes, firstEventID, statsScanPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get node events: %w", err)
+		}
+
+		for _, event := range events {
+			if event.EventType == types.EventTypeNodeScheduled {
+				nodeID, nodeType := nodeScheduledInfo(event)
+				scheduleCounts[nodeID]++
+				pending := PendingActivity{
+					ScheduledEventID: event.EventID,
+					ActivityID:       nodeID,
+					ActivityType:     nodeType,
+					ScheduledTime:    event.Timestamp,
+					Attempt:          scheduleCounts[nodeID],
+				}
+				if failure, ok := failures[nodeID]; ok {
+					pending.LastFailure = failure.reason
+					pending.LastFailureTime = failure.time
+				}
+				attempts[event.EventID] = &nodeAttempt{pending: pending}
+				continue
+			}
+
+			attempt, ok := attempts[nodeScheduledEventID(event)]
+			if !ok {
+				continue
+			}
+			switch event.EventType {
+			case types.EventTypeNodeStarted:
+				attempt.pending.Started = true
+				attempt.pending.LastStartedTime = event.Timestamp
+				if started, ok := event.Attributes.(*historyv1.HistoryEvent_NodeStartedAttributes); ok {
+					if n := started.NodeStartedAttributes.GetAttempt(); n > attempt.pending.Attempt {
+						attempt.pending.Attempt = n
+					}
+				}
+			case types.EventTypeNodeCompleted:
+				attempt.closed = true
+			case types.EventTypeNodeFailed, types.EventTypeNodeTimedOut:
+				attempt.closed = true
+				failures[attempt.pending.ActivityID] = nodeFailure{
+					reason: nodeFailureReason(event),
+					time:   event.Timestamp,
+				}
+			}
+		}
+
+		if len(events) < statsScanPageSize {
+			break
+		}
+		firstEventID = events[len(events)-1].EventID + 1
+	}
+
+	return attempts, nil
+}
+
+// nodeScheduledInfo returns the node ID and type of a NodeScheduled event.
+func nodeScheduledInfo(event *types.HistoryEvent) (string, string) {
+	switch attrs := event.Attributes.(type) {
+	case *types.NodeScheduledAttributes:
+		return attrs.NodeID, attrs.NodeType
+	case *historyv1.HistoryEvent_NodeScheduledAttributes:
+		return attrs.NodeScheduledAttributes.GetNodeId(), attrs.NodeScheduledAttributes.GetNodeType()
+	}
+	return "", ""
+}
+
+// nodeFailureReason returns the failure message of a NodeFailed or
+// NodeTimedOut event.
+func nodeFailureReason(event *types.HistoryEvent) string {
+	switch attrs := event.Attributes.(type) {
+	case *types.NodeFailedAttributes:
+		return attrs.Reason
+	case *historyv1.HistoryEvent_NodeFailedAttributes:
+		return attrs.NodeFailedAttributes.GetFailure().GetMessage()
+	case *historyv1.HistoryEvent_NodeTimedOutAttributes:
+		if message := attrs.NodeTimedOutAttributes.GetFailure().GetMessage(); message != "" {
+			return message
+		}
+	}
+	if event.EventType == types.EventTypeNodeTimedOut {
+		return "timed out"
+	}
+	return ""
+}
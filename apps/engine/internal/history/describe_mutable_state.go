@@ -0,0 +1,94 @@
+package history
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/linkflow/engine/internal/history/ndc"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// MutableStateDescription is an execution's mutable state as stored on this
+// cluster, with the version history of its events, for comparing clusters'
+// views of the same execution.
+type MutableStateDescription struct {
+	Info           types.ExecutionInfo
+	NextEventID    int64
+	DBVersion      int64
+	VersionHistory *ndc.VersionHistory
+	PendingTasks   PendingTasks
+	// CompletedNodes, BufferedEvents and BufferedSignals are counts.
+	CompletedNodes  int
+	BufferedEvents  int
+	BufferedSignals int
+}
+
+// PendingTasks is the work an execution's mutable state is waiting on, each
+// list ordered by the event that created it.
+type PendingTasks struct {
+	Activities []types.ActivityInfo
+	Nodes      []types.PendingNodeInfo
+	Timers     []types.TimerInfo
+	Children   []types.ChildExecutionInfo
+}
+
+// DescribeMutableState returns the stored mutable state of an execution and
+// the version history of its recorded events. It is read-only. Events
+// removed by compaction are not part of the version history.
+func (s *Service) DescribeMutableState(ctx context.Context, key types.ExecutionKey) (*MutableStateDescription, error) {
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventStore.GetEvents(ctx, key, 1, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &MutableStateDescription{
+		NextEventID:     state.NextEventID,
+		DBVersion:       state.DBVersion,
+		VersionHistory:  ndc.BuildVersionHistory(key.RunID, events),
+		CompletedNodes:  len(state.CompletedNodes),
+		BufferedEvents:  len(state.BufferedEvents),
+		BufferedSignals: len(state.SignalBuffer),
+		PendingTasks: PendingTasks{
+			Activities: make([]types.ActivityInfo, 0, len(state.PendingActivities)),
+			Nodes:      make([]types.PendingNodeInfo, 0, len(state.PendingNodes)),
+			Timers:     make([]types.TimerInfo, 0, len(state.PendingTimers)),
+			Children:   make([]types.ChildExecutionInfo, 0, len(state.PendingChildren)),
+		},
+	}
+	if state.ExecutionInfo != nil {
+		desc.Info = *state.ExecutionInfo
+	}
+
+	pending := &desc.PendingTasks
+	for _, info := range state.PendingActivities {
+		pending.Activities = append(pending.Activities, *info)
+	}
+	sort.Slice(pending.Activities, func(i, j int) bool {
+		return pending.Activities[i].ScheduledEventID < pending.Activities[j].ScheduledEventID
+	})
+	for _, info := range state.PendingNodes {
+		pending.Nodes = append(pending.Nodes, *info)
+	}
+	sort.Slice(pending.Nodes, func(i, j int) bool {
+		return pending.Nodes[i].ScheduledEventID < pending.Nodes[j].ScheduledEventID
+	})
+	for _, info := range state.PendingTimers {
+		pending.Timers = append(pending.Timers, *info)
+	}
+	sort.Slice(pending.Timers, func(i, j int) bool {
+		return pending.Timers[i].StartedEventID < pending.Timers[j].StartedEventID
+	})
+	for _, info := range state.PendingChildren {
+		pending.Children = append(pending.Children, *info)
+	}
+	sort.Slice(pending.Children, func(i, j int) bool {
+		return pending.Children[i].StartedEventID < pending.Children[j].StartedEventID
+	})
+
+	return desc, nil
+}
@@ -0,0 +1,90 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/ndc"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestDescribeMutableStateReportsVersionHistory(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	state.NextEventID = 6
+	state.PendingTimers["b"] = &types.TimerInfo{TimerID: "b", StartedEventID: 5}
+	state.PendingTimers["a"] = &types.TimerInfo{TimerID: "a", StartedEventID: 4}
+	state.PendingNodes[3] = &types.PendingNodeInfo{ScheduledEventID: 3, NodeID: "fetch", NodeType: "http"}
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	// Written at version 1, failed over to version 2 after event 2.
+	var events []*types.HistoryEvent
+	for id, version := range []int64{1, 1, 2, 2, 2} {
+		events = append(events, &types.HistoryEvent{EventID: int64(id + 1), EventType: types.EventTypeNodeScheduled, Timestamp: time.Now(), Version: version})
+	}
+	if err := eventStore.AppendEvents(ctx, key, events, 0); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	desc, err := svc.DescribeMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("DescribeMutableState: %v", err)
+	}
+	want := []ndc.VersionHistoryItem{{EventID: 2, Version: 1}, {EventID: 5, Version: 2}}
+	if got := desc.VersionHistory.Items; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("version history = %+v, want %+v", got, want)
+	}
+	if timers := desc.PendingTasks.Timers; len(timers) != 2 || timers[0].TimerID != "a" || len(desc.PendingTasks.Nodes) != 1 {
+		t.Fatalf("pending tasks = %+v", desc.PendingTasks)
+	}
+
+	// A cluster that diverged at version 3 after event 3 shares events up to 3
+	// and is on another branch.
+	remote := ndc.BuildVersionHistory(key.RunID, []*types.HistoryEvent{
+		{EventID: 1, Version: 1}, {EventID: 2, Version: 1}, {EventID: 3, Version: 2}, {EventID: 4, Version: 3},
+	})
+	if lca, ok := ndc.FindLCAItem(desc.VersionHistory, remote); !ok || lca != (ndc.VersionHistoryItem{EventID: 3, Version: 2}) {
+		t.Fatalf("LCA = %+v, %v, want event 3 at version 2", lca, ok)
+	}
+	if string(remote.BranchToken) == string(desc.VersionHistory.BranchToken) {
+		t.Fatal("diverged histories should have different branch tokens")
+	}
+	behind := ndc.BuildVersionHistory(key.RunID, events[:4])
+	if string(behind.BranchToken) != string(desc.VersionHistory.BranchToken) {
+		t.Fatal("a history that is behind on the same branch should share its branch token")
+	}
+
+	rec := httptest.NewRecorder()
+	svc.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/v1/namespaces/default/executions/wf-1/run-1/mutable-state", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var view MutableStateView
+	if err := json.Unmarshal(rec.Body.Bytes(), &view); err != nil {
+		t.Fatalf("decode view: %v", err)
+	}
+	if view.NextEventID != 6 || view.Status != "EXECUTION_STATUS_RUNNING" || len(view.PendingTasks.Timers) != 2 || len(view.VersionHistory.Items) != 2 {
+		t.Fatalf("view = %+v", view)
+	}
+}
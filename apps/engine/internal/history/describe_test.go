@@ -0,0 +1,179 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestDescribeWorkflowExecutionReportsPendingWork(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	fireTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	state.PendingTimers["wait-1"] = &types.TimerInfo{TimerID: "wait-1", StartedEventID: 8, FireTime: fireTime}
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	scheduled := func(id int64, nodeID string) *types.HistoryEvent {
+		return &types.HistoryEvent{EventID: id, EventType: types.EventTypeNodeScheduled,
+			Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+				NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{NodeId: nodeID, NodeType: "http"},
+			}}
+	}
+	started := func(id, scheduledID int64) *types.HistoryEvent {
+		return &types.HistoryEvent{EventID: id, EventType: types.EventTypeNodeStarted,
+			Attributes: &historyv1.HistoryEvent_NodeStartedAttributes{
+				NodeStartedAttributes: &historyv1.NodeStartedEventAttributes{ScheduledEventId: scheduledID},
+			}}
+	}
+	events := []*types.HistoryEvent{
+		scheduled(1, "fetch"),
+		started(2, 1),
+		{EventID: 3, EventType: types.EventTypeNodeFailed, Attributes: &historyv1.HistoryEvent_NodeFailedAttributes{
+			NodeFailedAttributes: &historyv1.NodeFailedEventAttributes{
+				ScheduledEventId: 1,
+				Failure:          &commonv1.Failure{Message: "503 Service Unavailable"},
+			},
+		}},
+		scheduled(4, "fetch"),
+		started(5, 4),
+		scheduled(6, "notify"),
+		{EventID: 7, EventType: types.EventTypeNodeCompleted, Attributes: &historyv1.HistoryEvent_NodeCompletedAttributes{
+			NodeCompletedAttributes: &historyv1.NodeCompletedEventAttributes{ScheduledEventId: 6},
+		}},
+	}
+	if err := eventStore.AppendEvents(ctx, key, events, 0); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	desc, err := svc.DescribeWorkflowExecution(ctx, key)
+	if err != nil {
+		t.Fatalf("DescribeWorkflowExecution: %v", err)
+	}
+
+	if len(desc.PendingActivities) != 1 {
+		t.Fatalf("pending activities = %+v, want only the retried fetch", desc.PendingActivities)
+	}
+	activity := desc.PendingActivities[0]
+	if activity.ScheduledEventID != 4 || activity.ActivityID != "fetch" || !activity.Started {
+		t.Fatalf("unexpected pending activity %+v", activity)
+	}
+	if activity.Attempt != 2 || activity.LastFailure != "503 Service Unavailable" {
+		t.Fatalf("attempt = %d, last failure = %q", activity.Attempt, activity.LastFailure)
+	}
+
+	if len(desc.PendingTimers) != 1 || desc.PendingTimers[0].TimerID != "wait-1" || !desc.PendingTimers[0].FireTime.Equal(fireTime) {
+		t.Fatalf("unexpected pending timers %+v", desc.PendingTimers)
+	}
+
+	state.ExecutionInfo.Status = types.ExecutionStatusCompleted
+	if err := stateStore.UpdateMutableState(ctx, key, state, 1); err != nil {
+		t.Fatalf("close state: %v", err)
+	}
+	desc, err = svc.DescribeWorkflowExecution(ctx, key)
+	if err != nil {
+		t.Fatalf("DescribeWorkflowExecution closed: %v", err)
+	}
+	if len(desc.PendingActivities) != 0 || len(desc.PendingTimers) != 0 {
+		t.Fatalf("closed execution reported pending work: %+v", desc)
+	}
+}
+
+func TestDescribeStateAtEventReplaysToEvent(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	state.NextEventID = 5
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	scheduled := func(id int64, nodeID string) *types.HistoryEvent {
+		return &types.HistoryEvent{EventID: id, EventType: types.EventTypeNodeScheduled,
+			Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+				NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{NodeId: nodeID, NodeType: "http"},
+			}}
+	}
+	events := []*types.HistoryEvent{
+		scheduled(1, "fetch"),
+		{EventID: 2, EventType: types.EventTypeNodeStarted, Attributes: &historyv1.HistoryEvent_NodeStartedAttributes{
+			NodeStartedAttributes: &historyv1.NodeStartedEventAttributes{ScheduledEventId: 1},
+		}},
+		{EventID: 3, EventType: types.EventTypeNodeCompleted, Attributes: &historyv1.HistoryEvent_NodeCompletedAttributes{
+			NodeCompletedAttributes: &historyv1.NodeCompletedEventAttributes{ScheduledEventId: 1},
+		}},
+		scheduled(4, "notify"),
+	}
+	if err := eventStore.AppendEvents(ctx, key, events, 0); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	result, err := svc.DescribeStateAtEvent(ctx, key, 2)
+	if err != nil {
+		t.Fatalf("DescribeStateAtEvent(2): %v", err)
+	}
+	if result.Event.EventID != 2 || result.State.NextEventID != 3 {
+		t.Fatalf("event = %d, next event = %d; want 2 and 3", result.Event.EventID, result.State.NextEventID)
+	}
+	if node := result.State.PendingNodes[1]; node == nil || node.NodeID != "fetch" {
+		t.Fatalf("pending nodes at event 2 = %+v, want fetch", result.State.PendingNodes)
+	}
+	if _, err := result.JSON(); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+
+	result, err = svc.DescribeStateAtEvent(ctx, key, 4)
+	if err != nil {
+		t.Fatalf("DescribeStateAtEvent(4): %v", err)
+	}
+	if len(result.State.PendingNodes) != 1 || result.State.PendingNodes[4] == nil {
+		t.Fatalf("pending nodes at event 4 = %+v, want only notify", result.State.PendingNodes)
+	}
+
+	for _, eventID := range []int64{0, 5} {
+		if _, err := svc.DescribeStateAtEvent(ctx, key, eventID); !errors.Is(err, ErrEventIDOutOfRange) {
+			t.Fatalf("DescribeStateAtEvent(%d) error = %v, want ErrEventIDOutOfRange", eventID, err)
+		}
+	}
+
+	current, err := stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if current.NextEventID != 5 || len(current.PendingNodes) != 0 {
+		t.Fatalf("stored state changed: next event %d, pending nodes %+v", current.NextEventID, current.PendingNodes)
+	}
+}
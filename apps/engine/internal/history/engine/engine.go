@@ -2,6 +2,7 @@ package engine
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -16,6 +17,7 @@ var (
 	ErrActivityNotFound   = errors.New("activity not found")
 	ErrWorkflowNotRunning = errors.New("workflow not running")
 	ErrInvalidEventType   = errors.New("invalid event type")
+	ErrNodeNotPending     = errors.New("node not pending")
 )
 
 type Engine struct {
@@ -50,7 +52,8 @@ func (e *Engine) ValidateEvent(state *MutableState, event *types.HistoryEvent) e
 	switch event.EventType {
 	case types.EventTypeExecutionStarted:
 		return e.validateExecutionStarted(state, event)
-	case types.EventTypeExecutionCompleted, types.EventTypeExecutionFailed, types.EventTypeExecutionTerminated:
+	case types.EventTypeExecutionCompleted, types.EventTypeExecutionFailed, types.EventTypeExecutionTerminated,
+		types.EventTypeExecutionTimedOut, types.EventTypeExecutionCanceled:
 		return e.validateExecutionClose(state)
 	case types.EventTypeTimerStarted:
 		return e.validateTimerStarted(state, event)
@@ -62,6 +65,10 @@ func (e *Engine) ValidateEvent(state *MutableState, event *types.HistoryEvent) e
 		return e.validateActivityStarted(state, event)
 	case types.EventTypeActivityCompleted, types.EventTypeActivityFailed, types.EventTypeActivityTimedOut:
 		return e.validateActivityClose(state, event)
+	case types.EventTypeNodeStarted:
+		return e.validateNodeStarted(state, event)
+	case types.EventTypeNodeCompleted, types.EventTypeNodeFailed, types.EventTypeNodeTimedOut:
+		return e.validateNodeResult(state, event)
 	}
 
 	return nil
@@ -154,6 +161,56 @@ func (e *Engine) validateActivityClose(state *MutableState, event *types.History
 	return nil
 }
 
+// validateNodeResult rejects a node result whose scheduled event is not a
+// pending node, such as a stale completion redelivered after the node closed
+// or a misrouted one naming another event. State persisted before nodes were
+// tracked has no PendingNodes and is not checked.
+func (e *Engine) validateNodeResult(state *MutableState, event *types.HistoryEvent) error {
+	scheduledEventID, ok := nodeResultScheduledEventID(event)
+	if !ok {
+		return ErrInvalidEventType
+	}
+	if state.PendingNodes == nil {
+		return nil
+	}
+
+	node, ok := state.PendingNodes[scheduledEventID]
+	if !ok {
+		// Async completions are matched against the pending activity their
+		// task token was issued for.
+		if _, async := state.PendingActivities[scheduledEventID]; async {
+			return nil
+		}
+		return fmt.Errorf("%w: scheduled event %d is not a pending node", ErrNodeNotPending, scheduledEventID)
+	}
+
+	var nodeID string
+	switch attrs := event.Attributes.(type) {
+	case *types.NodeCompletedAttributes:
+		nodeID = attrs.NodeID
+	case *types.NodeFailedAttributes:
+		nodeID = attrs.NodeID
+	}
+	if nodeID != "" && nodeID != node.NodeID {
+		return fmt.Errorf("%w: scheduled event %d is node %q, not %q", ErrNodeNotPending, scheduledEventID, node.NodeID, nodeID)
+	}
+	return nil
+}
+
+// validateNodeStarted rejects a worker's start of a node that is no longer
+// pending, such as a task redelivered after the node closed. Async starts
+// are checked when their token is issued.
+func (e *Engine) validateNodeStarted(state *MutableState, event *types.HistoryEvent) error {
+	scheduledEventID, ok := workerNodeStartedScheduledEventID(event)
+	if !ok || state.PendingNodes == nil {
+		return nil
+	}
+	if _, ok := state.PendingNodes[scheduledEventID]; !ok {
+		return fmt.Errorf("%w: scheduled event %d is not a pending node", ErrNodeNotPending, scheduledEventID)
+	}
+	return nil
+}
+
 func (e *Engine) ScheduleNode(state *MutableState, nodeID, nodeType string, input []byte, taskQueue string) (*types.HistoryEvent, error) {
 	if !state.IsWorkflowExecutionRunning() {
 		return nil, ErrWorkflowNotRunning
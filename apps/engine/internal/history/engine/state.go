@@ -1,8 +1,12 @@
 package engine
 
 import (
+	"encoding/json"
+	"sort"
+	"strconv"
 	"time"
 
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
 	"github.com/linkflow/engine/internal/history/types"
 )
 
@@ -10,9 +14,16 @@ type MutableState struct {
 	ExecutionInfo     *types.ExecutionInfo
 	NextEventID       int64
 	PendingActivities map[int64]*types.ActivityInfo
+	PendingNodes      map[int64]*types.PendingNodeInfo // nil for state persisted before nodes were tracked
 	PendingTimers     map[string]*types.TimerInfo
+	FiredTimers       map[string]int64 // timer ID -> its TimerFired event; nil for state persisted before fired timers were tracked
 	CompletedNodes    map[string]*types.NodeResult
+	PendingChildren   map[string]*types.ChildExecutionInfo
 	BufferedEvents    []*types.HistoryEvent
+	AppliedRequests   map[string]int64                // client request ID -> first event it appended
+	VersionMarkers    map[string]*types.VersionMarker // change ID -> first recorded version
+	SignalBuffer      []*types.BufferedSignal         // undelivered signals in arrival order
+	Variables         map[string][]byte               // workflow variable -> JSON value set by set_variable nodes
 	DBVersion         int64
 }
 
@@ -21,9 +32,16 @@ func NewMutableState(info *types.ExecutionInfo) *MutableState {
 		ExecutionInfo:     info,
 		NextEventID:       1,
 		PendingActivities: make(map[int64]*types.ActivityInfo),
+		PendingNodes:      make(map[int64]*types.PendingNodeInfo),
 		PendingTimers:     make(map[string]*types.TimerInfo),
+		FiredTimers:       make(map[string]int64),
 		CompletedNodes:    make(map[string]*types.NodeResult),
+		PendingChildren:   make(map[string]*types.ChildExecutionInfo),
 		BufferedEvents:    make([]*types.HistoryEvent, 0),
+		AppliedRequests:   make(map[string]int64),
+		VersionMarkers:    make(map[string]*types.VersionMarker),
+		SignalBuffer:      make([]*types.BufferedSignal, 0),
+		Variables:         make(map[string][]byte),
 		DBVersion:         0,
 	}
 }
@@ -34,21 +52,55 @@ func (ms *MutableState) Clone() *MutableState {
 		NextEventID:       ms.NextEventID,
 		PendingActivities: make(map[int64]*types.ActivityInfo, len(ms.PendingActivities)),
 		PendingTimers:     make(map[string]*types.TimerInfo, len(ms.PendingTimers)),
+		FiredTimers:       make(map[string]int64, len(ms.FiredTimers)),
 		CompletedNodes:    make(map[string]*types.NodeResult, len(ms.CompletedNodes)),
+		PendingChildren:   make(map[string]*types.ChildExecutionInfo, len(ms.PendingChildren)),
 		BufferedEvents:    make([]*types.HistoryEvent, len(ms.BufferedEvents)),
+		AppliedRequests:   make(map[string]int64, len(ms.AppliedRequests)),
+		VersionMarkers:    make(map[string]*types.VersionMarker, len(ms.VersionMarkers)),
+		SignalBuffer:      make([]*types.BufferedSignal, len(ms.SignalBuffer)),
+		Variables:         make(map[string][]byte, len(ms.Variables)),
 		DBVersion:         ms.DBVersion,
 	}
 
 	for k, v := range ms.PendingActivities {
 		clone.PendingActivities[k] = ms.cloneActivityInfo(v)
 	}
+	if ms.PendingNodes != nil {
+		clone.PendingNodes = make(map[int64]*types.PendingNodeInfo, len(ms.PendingNodes))
+		for k, v := range ms.PendingNodes {
+			node := *v
+			clone.PendingNodes[k] = &node
+		}
+	}
 	for k, v := range ms.PendingTimers {
 		clone.PendingTimers[k] = ms.cloneTimerInfo(v)
 	}
+	for k, v := range ms.FiredTimers {
+		clone.FiredTimers[k] = v
+	}
 	for k, v := range ms.CompletedNodes {
 		clone.CompletedNodes[k] = ms.cloneNodeResult(v)
 	}
+	for k, v := range ms.PendingChildren {
+		child := *v
+		clone.PendingChildren[k] = &child
+	}
 	copy(clone.BufferedEvents, ms.BufferedEvents)
+	for k, v := range ms.AppliedRequests {
+		clone.AppliedRequests[k] = v
+	}
+	for k, v := range ms.VersionMarkers {
+		marker := *v
+		clone.VersionMarkers[k] = &marker
+	}
+	for i, v := range ms.SignalBuffer {
+		signal := *v
+		clone.SignalBuffer[i] = &signal
+	}
+	for k, v := range ms.Variables {
+		clone.Variables[k] = append([]byte(nil), v...)
+	}
 
 	return clone
 }
@@ -115,12 +167,20 @@ func (ms *MutableState) ApplyEvent(event *types.HistoryEvent) error {
 		return ms.applyExecutionFailed(event)
 	case types.EventTypeExecutionTerminated:
 		return ms.applyExecutionTerminated(event)
+	case types.EventTypeExecutionTimedOut:
+		return ms.applyExecutionClosed(event, types.ExecutionStatusTimedOut)
+	case types.EventTypeExecutionCanceled:
+		return ms.applyExecutionClosed(event, types.ExecutionStatusCanceled)
 	case types.EventTypeNodeScheduled:
 		return ms.applyNodeScheduled(event)
 	case types.EventTypeNodeCompleted:
 		return ms.applyNodeCompleted(event)
+	case types.EventTypeNodeStarted:
+		return ms.applyNodeStarted(event)
 	case types.EventTypeNodeFailed:
 		return ms.applyNodeFailed(event)
+	case types.EventTypeNodeTimedOut:
+		return ms.applyNodeTimedOut(event)
 	case types.EventTypeTimerStarted:
 		return ms.applyTimerStarted(event)
 	case types.EventTypeTimerFired:
@@ -135,6 +195,14 @@ func (ms *MutableState) ApplyEvent(event *types.HistoryEvent) error {
 		return ms.applyActivityCompleted(event)
 	case types.EventTypeActivityFailed:
 		return ms.applyActivityFailed(event)
+	case types.EventTypeChildWorkflowStarted:
+		return ms.applyChildWorkflowStarted(event)
+	case types.EventTypeChildWorkflowCompleted:
+		return ms.applyChildWorkflowCompleted(event)
+	case types.EventTypeMarkerRecorded:
+		return ms.applyMarkerRecorded(event)
+	case types.EventTypeSignalReceived:
+		return ms.applySignalReceived(event)
 	}
 
 	ms.NextEventID = event.EventID + 1
@@ -154,6 +222,13 @@ func (ms *MutableState) applyExecutionStarted(event *types.HistoryEvent) error {
 	ms.ExecutionInfo.TaskTimeout = attrs.TaskTimeout
 	ms.ExecutionInfo.Status = types.ExecutionStatusRunning
 	ms.ExecutionInfo.StartTime = event.Timestamp
+	if attrs.ParentExecution != nil {
+		ms.ExecutionInfo.ParentWorkflowID = attrs.ParentExecution.WorkflowID
+		ms.ExecutionInfo.ParentRunID = attrs.ParentExecution.RunID
+		ms.ExecutionInfo.ParentNodeID = attrs.ParentNodeID
+	}
+	ms.ExecutionInfo.ChildDepth = attrs.ChildDepth
+	ms.ExecutionInfo.CorrelationID = attrs.CorrelationID
 	ms.NextEventID = event.EventID + 1
 	return nil
 }
@@ -179,40 +254,175 @@ func (ms *MutableState) applyExecutionTerminated(event *types.HistoryEvent) erro
 	return nil
 }
 
+func (ms *MutableState) applyExecutionClosed(event *types.HistoryEvent, status types.ExecutionStatus) error {
+	ms.ExecutionInfo.Status = status
+	ms.ExecutionInfo.CloseTime = event.Timestamp
+	ms.NextEventID = event.EventID + 1
+	return nil
+}
+
 func (ms *MutableState) applyNodeScheduled(event *types.HistoryEvent) error {
 	ms.NextEventID = event.EventID + 1
+	if ms.PendingNodes == nil {
+		return nil
+	}
+	node := &types.PendingNodeInfo{ScheduledEventID: event.EventID, ScheduledTime: event.Timestamp}
+	switch attrs := event.Attributes.(type) {
+	case *types.NodeScheduledAttributes:
+		node.NodeID = attrs.NodeID
+		node.NodeType = attrs.NodeType
+	case *historyv1.HistoryEvent_NodeScheduledAttributes:
+		node.NodeID = attrs.NodeScheduledAttributes.GetNodeId()
+		node.NodeType = attrs.NodeScheduledAttributes.GetNodeType()
+	}
+	ms.PendingNodes[event.EventID] = node
+	return nil
+}
+
+func (ms *MutableState) applyNodeStarted(event *types.HistoryEvent) error {
+	ms.NextEventID = event.EventID + 1
+	if started, ok := event.Attributes.(*historyv1.HistoryEvent_NodeStartedAttributes); ok {
+		ms.startNode(event, started.NodeStartedAttributes.GetScheduledEventId(), started.NodeStartedAttributes.GetAttempt())
+		return nil
+	}
+	attrs, ok := event.Attributes.(*types.NodeStartedAttributes)
+	if !ok || attrs.AsyncNonce == "" {
+		return nil
+	}
+	if node := ms.PendingNodes[attrs.ScheduledEventID]; node != nil && node.StartedEventID == 0 {
+		ms.startNode(event, attrs.ScheduledEventID, 0)
+	}
+	ms.PendingActivities[attrs.ScheduledEventID] = &types.ActivityInfo{
+		ScheduledEventID: attrs.ScheduledEventID,
+		StartedEventID:   event.EventID,
+		ActivityID:       attrs.NodeID,
+		StartedTime:      event.Timestamp,
+		ScheduleTimeout:  attrs.ScheduleToClose,
+		AsyncNonce:       attrs.AsyncNonce,
+		WaitSignal:       attrs.WaitSignal,
+		TimeoutResult:    attrs.TimeoutResult,
+	}
+	return nil
+}
+
+// startNode records a worker beginning an attempt of a pending node. An
+// attempt of 0 counts as the one after the last recorded attempt.
+func (ms *MutableState) startNode(event *types.HistoryEvent, scheduledEventID int64, attempt int32) {
+	node := ms.PendingNodes[scheduledEventID]
+	if node == nil {
+		return
+	}
+	if attempt <= 0 {
+		attempt = node.Attempt + 1
+	}
+	node.StartedEventID = event.EventID
+	node.StartedTime = event.Timestamp
+	node.Attempt = attempt
+}
+
+// pendingNodeFor returns the pending node a NodeCompleted, NodeFailed or
+// NodeTimedOut event closes, if it is tracked.
+func (ms *MutableState) pendingNodeFor(event *types.HistoryEvent) *types.PendingNodeInfo {
+	if scheduledEventID, ok := nodeResultScheduledEventID(event); ok {
+		return ms.PendingNodes[scheduledEventID]
+	}
 	return nil
 }
 
 func (ms *MutableState) applyNodeCompleted(event *types.HistoryEvent) error {
+	var nodeType string
+	node := ms.pendingNodeFor(event)
+	if node != nil {
+		nodeType = node.NodeType
+	}
+	ms.closeNode(event)
 	attrs, ok := event.Attributes.(*types.NodeCompletedAttributes)
 	if !ok {
 		return nil
 	}
-	ms.CompletedNodes[attrs.NodeID] = &types.NodeResult{
+	if nodeType == types.NodeTypeSetVariable {
+		ms.setVariable(attrs.Result)
+	}
+	delete(ms.PendingActivities, attrs.ScheduledEventID)
+	result := &types.NodeResult{
 		NodeID:        attrs.NodeID,
 		CompletedTime: event.Timestamp,
 		Output:        attrs.Result,
 	}
+	if node != nil {
+		result.StartedTime, result.Attempts = node.StartedTime, node.Attempt
+	}
+	ms.CompletedNodes[attrs.NodeID] = result
+	if attrs.SignalEventID != 0 {
+		ms.removeBufferedSignal(attrs.SignalEventID)
+	}
 	ms.NextEventID = event.EventID + 1
 	return nil
 }
 
 func (ms *MutableState) applyNodeFailed(event *types.HistoryEvent) error {
+	node := ms.pendingNodeFor(event)
+	ms.closeNode(event)
 	attrs, ok := event.Attributes.(*types.NodeFailedAttributes)
 	if !ok {
 		return nil
 	}
-	ms.CompletedNodes[attrs.NodeID] = &types.NodeResult{
+	delete(ms.PendingActivities, attrs.ScheduledEventID)
+	result := &types.NodeResult{
 		NodeID:         attrs.NodeID,
 		CompletedTime:  event.Timestamp,
 		FailureReason:  attrs.Reason,
 		FailureDetails: attrs.Details,
 	}
+	if node != nil {
+		result.StartedTime, result.Attempts = node.StartedTime, node.Attempt
+	}
+	ms.CompletedNodes[attrs.NodeID] = result
 	ms.NextEventID = event.EventID + 1
 	return nil
 }
 
+func (ms *MutableState) applyNodeTimedOut(event *types.HistoryEvent) error {
+	ms.closeNode(event)
+	return nil
+}
+
+// closeNode removes the node a result event refers to from PendingNodes and
+// advances NextEventID, for both internal and API event attributes.
+func (ms *MutableState) closeNode(event *types.HistoryEvent) {
+	ms.NextEventID = event.EventID + 1
+	if scheduledEventID, ok := nodeResultScheduledEventID(event); ok {
+		delete(ms.PendingNodes, scheduledEventID)
+	}
+}
+
+// workerNodeStartedScheduledEventID returns the scheduled event of a
+// NodeStarted event recorded by a worker beginning an attempt.
+func workerNodeStartedScheduledEventID(event *types.HistoryEvent) (int64, bool) {
+	if started, ok := event.Attributes.(*historyv1.HistoryEvent_NodeStartedAttributes); ok {
+		return started.NodeStartedAttributes.GetScheduledEventId(), true
+	}
+	return 0, false
+}
+
+// nodeResultScheduledEventID returns the scheduled event a NodeCompleted,
+// NodeFailed or NodeTimedOut event refers to.
+func nodeResultScheduledEventID(event *types.HistoryEvent) (int64, bool) {
+	switch attrs := event.Attributes.(type) {
+	case *types.NodeCompletedAttributes:
+		return attrs.ScheduledEventID, true
+	case *types.NodeFailedAttributes:
+		return attrs.ScheduledEventID, true
+	case *historyv1.HistoryEvent_NodeCompletedAttributes:
+		return attrs.NodeCompletedAttributes.GetScheduledEventId(), true
+	case *historyv1.HistoryEvent_NodeFailedAttributes:
+		return attrs.NodeFailedAttributes.GetScheduledEventId(), true
+	case *historyv1.HistoryEvent_NodeTimedOutAttributes:
+		return attrs.NodeTimedOutAttributes.GetScheduledEventId(), true
+	}
+	return 0, false
+}
+
 func (ms *MutableState) applyTimerStarted(event *types.HistoryEvent) error {
 	attrs, ok := event.Attributes.(*types.TimerStartedAttributes)
 	if !ok {
@@ -234,6 +444,10 @@ func (ms *MutableState) applyTimerFired(event *types.HistoryEvent) error {
 		return nil
 	}
 	delete(ms.PendingTimers, attrs.TimerID)
+	if ms.FiredTimers == nil {
+		ms.FiredTimers = make(map[string]int64)
+	}
+	ms.FiredTimers[attrs.TimerID] = event.EventID
 	ms.NextEventID = event.EventID + 1
 	return nil
 }
@@ -302,6 +516,72 @@ func (ms *MutableState) applyActivityFailed(event *types.HistoryEvent) error {
 	return nil
 }
 
+func (ms *MutableState) applyChildWorkflowStarted(event *types.HistoryEvent) error {
+	ms.NextEventID = event.EventID + 1
+	attrs, ok := event.Attributes.(*types.ChildWorkflowStartedAttributes)
+	if !ok {
+		return nil
+	}
+	if ms.PendingChildren == nil {
+		ms.PendingChildren = make(map[string]*types.ChildExecutionInfo)
+	}
+	ms.PendingChildren[attrs.WorkflowID] = &types.ChildExecutionInfo{
+		NodeID:            attrs.NodeID,
+		WorkflowID:        attrs.WorkflowID,
+		RunID:             attrs.RunID,
+		WorkflowType:      attrs.WorkflowType,
+		StartedEventID:    event.EventID,
+		StartedTime:       event.Timestamp,
+		ParentClosePolicy: attrs.ParentClosePolicy,
+	}
+	return nil
+}
+
+func (ms *MutableState) applyChildWorkflowCompleted(event *types.HistoryEvent) error {
+	ms.NextEventID = event.EventID + 1
+	attrs, ok := event.Attributes.(*types.ChildWorkflowCompletedAttributes)
+	if !ok {
+		return nil
+	}
+	delete(ms.PendingChildren, attrs.WorkflowID)
+	if attrs.NodeID != "" {
+		ms.AddCompletedNode(attrs.NodeID, &types.NodeResult{
+			NodeID:        attrs.NodeID,
+			CompletedTime: event.Timestamp,
+			Output:        attrs.Result,
+			FailureReason: attrs.FailureReason,
+		})
+	}
+	return nil
+}
+
+// applyMarkerRecorded records version markers. Only the first marker for a
+// change counts, so a decider replaying history always sees the version it
+// chose originally.
+func (ms *MutableState) applyMarkerRecorded(event *types.HistoryEvent) error {
+	ms.NextEventID = event.EventID + 1
+	attrs, ok := event.Attributes.(*types.MarkerRecordedAttributes)
+	if !ok || attrs.MarkerName != types.VersionMarkerName {
+		return nil
+	}
+	changeID := string(attrs.Details[types.VersionMarkerChangeIDKey])
+	version, err := strconv.ParseInt(string(attrs.Details[types.VersionMarkerVersionKey]), 10, 32)
+	if changeID == "" || err != nil {
+		return nil
+	}
+	if ms.VersionMarkers == nil {
+		ms.VersionMarkers = make(map[string]*types.VersionMarker)
+	}
+	if _, exists := ms.VersionMarkers[changeID]; !exists {
+		ms.VersionMarkers[changeID] = &types.VersionMarker{
+			ChangeID: changeID,
+			Version:  int32(version),
+			EventID:  event.EventID,
+		}
+	}
+	return nil
+}
+
 func (ms *MutableState) AddPendingActivity(scheduledEventID int64, info *types.ActivityInfo) {
 	ms.PendingActivities[scheduledEventID] = info
 }
@@ -337,6 +617,46 @@ func (ms *MutableState) GetCompletedNode(nodeID string) (*types.NodeResult, bool
 	return result, ok
 }
 
+// GetFiredTimer returns the ID of the TimerFired event recorded for timerID.
+func (ms *MutableState) GetFiredTimer(timerID string) (int64, bool) {
+	eventID, ok := ms.FiredTimers[timerID]
+	return eventID, ok
+}
+
+func (ms *MutableState) AddAppliedRequest(requestID string, eventID int64) {
+	if ms.AppliedRequests == nil {
+		ms.AppliedRequests = make(map[string]int64)
+	}
+	ms.AppliedRequests[requestID] = eventID
+}
+
+// TrimAppliedRequests forgets the requests that appended the oldest events
+// until at most max remain.
+func (ms *MutableState) TrimAppliedRequests(max int) {
+	if max <= 0 || len(ms.AppliedRequests) <= max {
+		return
+	}
+	requestIDs := make([]string, 0, len(ms.AppliedRequests))
+	for requestID := range ms.AppliedRequests {
+		requestIDs = append(requestIDs, requestID)
+	}
+	sort.Slice(requestIDs, func(i, j int) bool {
+		a, b := ms.AppliedRequests[requestIDs[i]], ms.AppliedRequests[requestIDs[j]]
+		if a != b {
+			return a < b
+		}
+		return requestIDs[i] < requestIDs[j]
+	})
+	for _, requestID := range requestIDs[:len(requestIDs)-max] {
+		delete(ms.AppliedRequests, requestID)
+	}
+}
+
+func (ms *MutableState) GetAppliedRequest(requestID string) (int64, bool) {
+	eventID, ok := ms.AppliedRequests[requestID]
+	return eventID, ok
+}
+
 func (ms *MutableState) AddBufferedEvent(event *types.HistoryEvent) {
 	ms.BufferedEvents = append(ms.BufferedEvents, event)
 }
@@ -372,3 +692,63 @@ func (ms *MutableState) GetCloseTime() time.Time {
 	}
 	return ms.ExecutionInfo.CloseTime
 }
+
+// applySignalReceived buffers a signal unless a wait_signal node is already
+// waiting for it, in which case the node completes in the same batch. Signals
+// stay buffered in arrival order until a NodeCompleted event delivers them.
+func (ms *MutableState) applySignalReceived(event *types.HistoryEvent) error {
+	ms.NextEventID = event.EventID + 1
+	attrs, ok := event.Attributes.(*types.SignalReceivedAttributes)
+	if !ok {
+		return nil
+	}
+	for _, ai := range ms.PendingActivities {
+		if ai.AsyncNonce != "" && ai.WaitSignal == attrs.SignalName {
+			return nil
+		}
+	}
+	ms.SignalBuffer = append(ms.SignalBuffer, &types.BufferedSignal{
+		EventID:      event.EventID,
+		SignalName:   attrs.SignalName,
+		Input:        attrs.Input,
+		Identity:     attrs.Identity,
+		ReceivedTime: event.Timestamp,
+	})
+	return nil
+}
+
+// NextBufferedSignal returns the earliest buffered signal named name, or nil.
+func (ms *MutableState) NextBufferedSignal(name string) *types.BufferedSignal {
+	for _, signal := range ms.SignalBuffer {
+		if signal.SignalName == name {
+			return signal
+		}
+	}
+	return nil
+}
+
+// setVariable merges the variable a set_variable node completed with into
+// the workflow variables. Results that do not name a variable are ignored.
+func (ms *MutableState) setVariable(result []byte) {
+	var variable types.SetVariableResult
+	if err := json.Unmarshal(result, &variable); err != nil || variable.Name == "" {
+		return
+	}
+	if ms.Variables == nil {
+		ms.Variables = make(map[string][]byte)
+	}
+	value := []byte(variable.Value)
+	if len(value) == 0 {
+		value = []byte("null")
+	}
+	ms.Variables[variable.Name] = value
+}
+
+func (ms *MutableState) removeBufferedSignal(eventID int64) {
+	for i, signal := range ms.SignalBuffer {
+		if signal.EventID == eventID {
+			ms.SignalBuffer = append(ms.SignalBuffer[:i], ms.SignalBuffer[i+1:]...)
+			return
+		}
+	}
+}
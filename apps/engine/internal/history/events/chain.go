@@ -0,0 +1,32 @@
+package events
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// CanonicalEvent returns the encoding of event that hash chains cover: its
+// JSON serialization with the timestamp in UTC at the microsecond precision
+// it is stored with. It does not depend on the payload encoding, so an event
+// hashes the same when it is read back in any encoding.
+func CanonicalEvent(event *types.HistoryEvent) ([]byte, error) {
+	canonical := *event
+	canonical.Timestamp = event.Timestamp.UTC().Truncate(time.Microsecond)
+	return NewJSONSerializer().Serialize(&canonical)
+}
+
+// ChainHash returns the hash linking event to the event before it, whose
+// hash is prevHash: SHA-256 over prevHash followed by the canonical event.
+// The first event of a chain has no prevHash.
+func ChainHash(prevHash []byte, event *types.HistoryEvent) ([]byte, error) {
+	data, err := CanonicalEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write(prevHash)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
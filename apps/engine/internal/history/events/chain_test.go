@@ -0,0 +1,54 @@
+package events
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestChainHashSurvivesStorage(t *testing.T) {
+	s := NewJSONSerializer()
+	event := representativeEvent()
+	prev := bytes.Repeat([]byte{0xab}, 32)
+
+	want, err := ChainHash(prev, event)
+	if err != nil {
+		t.Fatalf("ChainHash: %v", err)
+	}
+
+	for _, encoding := range payloadEncodings {
+		t.Run(encoding.String(), func(t *testing.T) {
+			stored, err := s.SerializePayload(event, encoding)
+			if err != nil {
+				t.Fatalf("serialize: %v", err)
+			}
+			got, err := s.DeserializePayload(stored)
+			if err != nil {
+				t.Fatalf("deserialize: %v", err)
+			}
+			// The database keeps timestamps to the microsecond.
+			got.Timestamp = event.Timestamp.Truncate(time.Microsecond).Local()
+
+			hash, err := ChainHash(prev, got)
+			if err != nil {
+				t.Fatalf("ChainHash: %v", err)
+			}
+			if !bytes.Equal(hash, want) {
+				t.Fatalf("hash of stored event = %x, want %x", hash, want)
+			}
+		})
+	}
+}
+
+func TestChainHashCoversPrevHashAndEvent(t *testing.T) {
+	event := representativeEvent()
+	base, _ := ChainHash(nil, event)
+
+	if other, _ := ChainHash([]byte{1}, event); bytes.Equal(other, base) {
+		t.Error("hash does not depend on the previous hash")
+	}
+	event.Version++
+	if other, _ := ChainHash(nil, event); bytes.Equal(other, base) {
+		t.Error("hash does not depend on the event")
+	}
+}
@@ -0,0 +1,260 @@
+package events
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// PayloadEncoding identifies the wire format of a persisted event payload.
+type PayloadEncoding uint8
+
+const (
+	PayloadEncodingJSON PayloadEncoding = iota
+	PayloadEncodingProtobuf
+	PayloadEncodingMsgpack
+)
+
+// payloadEnvelopeMagic starts every non-JSON payload envelope. JSON payloads
+// are stored unframed so rows written before encodings were configurable keep
+// decoding; no JSON document starts with a NUL byte.
+const payloadEnvelopeMagic byte = 0x00
+
+func (e PayloadEncoding) String() string {
+	switch e {
+	case PayloadEncodingJSON:
+		return "json"
+	case PayloadEncodingProtobuf:
+		return "protobuf"
+	case PayloadEncodingMsgpack:
+		return "msgpack"
+	default:
+		return fmt.Sprintf("PayloadEncoding(%d)", uint8(e))
+	}
+}
+
+// ParsePayloadEncoding parses "json", "protobuf" (or "proto") and "msgpack".
+// An empty string selects JSON.
+func ParsePayloadEncoding(s string) (PayloadEncoding, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "json":
+		return PayloadEncodingJSON, nil
+	case "protobuf", "proto":
+		return PayloadEncodingProtobuf, nil
+	case "msgpack":
+		return PayloadEncodingMsgpack, nil
+	default:
+		return 0, fmt.Errorf("unknown payload encoding %q", s)
+	}
+}
+
+// PayloadEnvelope is a persisted event payload and the encoding of its data.
+type PayloadEnvelope struct {
+	PayloadEncoding PayloadEncoding
+	Data            []byte
+}
+
+// Marshal returns the stored form of the envelope: JSON data as is, any
+// other encoding prefixed with the envelope magic byte and the encoding.
+func (p PayloadEnvelope) Marshal() []byte {
+	if p.PayloadEncoding == PayloadEncodingJSON {
+		return p.Data
+	}
+	out := make([]byte, 0, len(p.Data)+2)
+	out = append(out, payloadEnvelopeMagic, byte(p.PayloadEncoding))
+	return append(out, p.Data...)
+}
+
+// UnmarshalPayloadEnvelope splits stored bytes into encoding and data.
+func UnmarshalPayloadEnvelope(stored []byte) (PayloadEnvelope, error) {
+	if len(stored) == 0 {
+		return PayloadEnvelope{}, errors.New("cannot deserialize empty data")
+	}
+	if stored[0] != payloadEnvelopeMagic {
+		return PayloadEnvelope{PayloadEncoding: PayloadEncodingJSON, Data: stored}, nil
+	}
+	if len(stored) < 2 {
+		return PayloadEnvelope{}, errors.New("payload envelope too short")
+	}
+	return PayloadEnvelope{PayloadEncoding: PayloadEncoding(stored[1]), Data: stored[2:]}, nil
+}
+
+// SerializePayload encodes event with the given encoding and returns the
+// stored form of its payload envelope.
+func (s *Serializer) SerializePayload(event *types.HistoryEvent, encoding PayloadEncoding) ([]byte, error) {
+	if event == nil {
+		return nil, errors.New("cannot serialize nil event")
+	}
+
+	var data []byte
+	var err error
+	switch encoding {
+	case PayloadEncodingJSON:
+		data, err = s.serializeJSON(event)
+	case PayloadEncodingProtobuf:
+		data, err = s.serializeProtobuf(event)
+	case PayloadEncodingMsgpack:
+		data, err = s.serializeMsgpack(event)
+	default:
+		return nil, fmt.Errorf("unsupported payload encoding: %s", encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return PayloadEnvelope{PayloadEncoding: encoding, Data: data}.Marshal(), nil
+}
+
+// DeserializePayload decodes a stored payload envelope of any encoding.
+func (s *Serializer) DeserializePayload(stored []byte) (*types.HistoryEvent, error) {
+	envelope, err := UnmarshalPayloadEnvelope(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	switch envelope.PayloadEncoding {
+	case PayloadEncodingJSON:
+		return s.deserializeJSON(envelope.Data)
+	case PayloadEncodingProtobuf:
+		return s.deserializeProtobuf(envelope.Data)
+	case PayloadEncodingMsgpack:
+		return s.deserializeMsgpack(envelope.Data)
+	default:
+		return nil, fmt.Errorf("unsupported payload encoding: %s", envelope.PayloadEncoding)
+	}
+}
+
+// serializeProtobuf stores the same attribute map as the JSON encoding, so
+// both decode through deserializeAttributes.
+func (s *Serializer) serializeProtobuf(event *types.HistoryEvent) ([]byte, error) {
+	msg := &historyv1.StoredHistoryEvent{
+		SerializerVersion: currentSerializerVersion,
+		EventId:           event.EventID,
+		EventType:         int32(event.EventType),
+		Timestamp:         event.Timestamp.UnixNano(),
+		EventVersion:      event.Version,
+		TaskId:            event.TaskID,
+	}
+
+	if event.Attributes != nil {
+		attrMap, err := attributesToMap(event.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		st, err := structpb.NewStruct(attrMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert attributes to struct: %w", err)
+		}
+		msg.Attributes = st
+	}
+
+	return proto.Marshal(msg)
+}
+
+func (s *Serializer) deserializeProtobuf(data []byte) (*types.HistoryEvent, error) {
+	var msg historyv1.StoredHistoryEvent
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	event := &types.HistoryEvent{
+		EventID:   msg.EventId,
+		EventType: types.EventType(msg.EventType),
+		Version:   msg.EventVersion,
+		TaskID:    msg.TaskId,
+		Timestamp: time.Unix(0, msg.Timestamp).UTC(),
+	}
+
+	if msg.Attributes != nil {
+		attrs, err := s.deserializeAttributes(event.EventType, msg.Attributes.AsMap())
+		if err != nil {
+			return nil, err
+		}
+		event.Attributes = attrs
+	}
+
+	return event, nil
+}
+
+type msgpackEvent struct {
+	Version    int                `msgpack:"v"`
+	EventID    int64              `msgpack:"event_id"`
+	EventType  int32              `msgpack:"event_type"`
+	Timestamp  int64              `msgpack:"timestamp"`
+	EvtVersion int64              `msgpack:"evt_version"`
+	TaskID     int64              `msgpack:"task_id"`
+	Attributes msgpack.RawMessage `msgpack:"attributes,omitempty"`
+}
+
+// serializeMsgpack encodes the typed attributes directly, keeping byte fields
+// binary instead of base64 as in the JSON encoding.
+func (s *Serializer) serializeMsgpack(event *types.HistoryEvent) ([]byte, error) {
+	me := msgpackEvent{
+		Version:    currentSerializerVersion,
+		EventID:    event.EventID,
+		EventType:  int32(event.EventType),
+		Timestamp:  event.Timestamp.UnixNano(),
+		EvtVersion: event.Version,
+		TaskID:     event.TaskID,
+	}
+
+	if event.Attributes != nil {
+		attrBytes, err := marshalMsgpack(event.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal attributes: %w", err)
+		}
+		me.Attributes = attrBytes
+	}
+
+	return marshalMsgpack(&me)
+}
+
+func (s *Serializer) deserializeMsgpack(data []byte) (*types.HistoryEvent, error) {
+	var me msgpackEvent
+	if err := msgpack.Unmarshal(data, &me); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	event := &types.HistoryEvent{
+		EventID:   me.EventID,
+		EventType: types.EventType(me.EventType),
+		Version:   me.EvtVersion,
+		TaskID:    me.TaskID,
+		Timestamp: time.Unix(0, me.Timestamp).UTC(),
+	}
+
+	if len(me.Attributes) > 0 {
+		attrs, ok := newEventAttributes(event.EventType)
+		if !ok {
+			attrs = &map[string]interface{}{}
+		}
+		if err := msgpack.Unmarshal(me.Attributes, attrs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal attributes for event type %s: %w", event.EventType, err)
+		}
+		if m, isMap := attrs.(*map[string]interface{}); isMap {
+			attrs = *m
+		}
+		event.Attributes = attrs
+	}
+
+	return event, nil
+}
+
+func marshalMsgpack(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
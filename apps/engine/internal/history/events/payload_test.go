@@ -0,0 +1,136 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+var payloadEncodings = []PayloadEncoding{
+	PayloadEncodingJSON,
+	PayloadEncodingProtobuf,
+	PayloadEncodingMsgpack,
+}
+
+// representativeEvent is a node completion whose result carries a
+// numeric-heavy JSON document, as produced by data transformation nodes.
+func representativeEvent() *types.HistoryEvent {
+	rows := make([]map[string]interface{}, 50)
+	for i := range rows {
+		rows[i] = map[string]interface{}{
+			"id":       i,
+			"price":    float64(i) * 1.25,
+			"quantity": i % 7,
+			"scores":   []float64{0.12, 3.5, 42, 1e-3, float64(i)},
+		}
+	}
+	result, _ := json.Marshal(map[string]interface{}{"rows": rows})
+
+	return &types.HistoryEvent{
+		EventID:   42,
+		EventType: types.EventTypeNodeCompleted,
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC),
+		Version:   3,
+		TaskID:    1001,
+		Attributes: &types.NodeCompletedAttributes{
+			NodeID:           "transform-1",
+			ScheduledEventID: 40,
+			StartedEventID:   41,
+			Result:           result,
+			Logs:             []byte(`[{"level":"info","message":"transformed 50 rows"}]`),
+		},
+	}
+}
+
+func TestPayloadEncodingsRoundTrip(t *testing.T) {
+	s := NewJSONSerializer()
+	want := representativeEvent()
+
+	for _, encoding := range payloadEncodings {
+		t.Run(encoding.String(), func(t *testing.T) {
+			stored, err := s.SerializePayload(want, encoding)
+			if err != nil {
+				t.Fatalf("serialize: %v", err)
+			}
+
+			got, err := s.DeserializePayload(stored)
+			if err != nil {
+				t.Fatalf("deserialize: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("round trip mismatch:\n got %#v\nwant %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestDeserializePayloadReadsLegacyJSON(t *testing.T) {
+	s := NewJSONSerializer()
+	event := representativeEvent()
+
+	legacy, err := s.Serialize(event)
+	if err != nil {
+		t.Fatalf("serialize: %v", err)
+	}
+	stored, err := s.SerializePayload(event, PayloadEncodingJSON)
+	if err != nil {
+		t.Fatalf("serialize payload: %v", err)
+	}
+	if string(stored) != string(legacy) {
+		t.Fatal("JSON payloads must stay unframed for compatibility")
+	}
+
+	got, err := s.DeserializePayload(legacy)
+	if err != nil {
+		t.Fatalf("deserialize: %v", err)
+	}
+	if !reflect.DeepEqual(got, event) {
+		t.Fatalf("legacy decode mismatch: %#v", got)
+	}
+}
+
+func TestDeserializePayloadRejectsUnknownEncoding(t *testing.T) {
+	s := NewJSONSerializer()
+	if _, err := s.DeserializePayload([]byte{payloadEnvelopeMagic, 99, 1, 2}); err == nil {
+		t.Fatal("expected error for unknown encoding")
+	}
+	if _, err := ParsePayloadEncoding("xml"); err == nil {
+		t.Fatal("expected error for unknown encoding name")
+	}
+}
+
+func BenchmarkPayloadEncodings(b *testing.B) {
+	s := NewJSONSerializer()
+	event := representativeEvent()
+
+	for _, encoding := range payloadEncodings {
+		stored, err := s.SerializePayload(event, encoding)
+		if err != nil {
+			b.Fatalf("serialize %s: %v", encoding, err)
+		}
+
+		b.Run(fmt.Sprintf("encode/%s", encoding), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ReportMetric(float64(len(stored)), "stored_bytes")
+			for i := 0; i < b.N; i++ {
+				if _, err := s.SerializePayload(event, encoding); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("decode/%s", encoding), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ReportMetric(float64(len(stored)), "stored_bytes")
+			for i := 0; i < b.N; i++ {
+				if _, err := s.DeserializePayload(stored); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
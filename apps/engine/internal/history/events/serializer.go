@@ -29,6 +29,8 @@ func init() {
 	gob.Register(&types.ActivityFailedAttributes{})
 	gob.Register(&types.SignalReceivedAttributes{})
 	gob.Register(&types.MarkerRecordedAttributes{})
+	gob.Register(&types.ChildWorkflowStartedAttributes{})
+	gob.Register(&types.ChildWorkflowCompletedAttributes{})
 	gob.Register(&types.ExecutionKey{})
 	gob.Register(&types.RetryPolicy{})
 }
@@ -96,13 +98,9 @@ func (s *Serializer) serializeJSON(event *types.HistoryEvent) ([]byte, error) {
 	}
 
 	if event.Attributes != nil {
-		attrBytes, err := json.Marshal(event.Attributes)
+		attrMap, err := attributesToMap(event.Attributes)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal attributes: %w", err)
-		}
-		var attrMap map[string]interface{}
-		if err := json.Unmarshal(attrBytes, &attrMap); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal attributes to map: %w", err)
+			return nil, err
 		}
 		se.Attributes = attrMap
 	}
@@ -110,6 +108,18 @@ func (s *Serializer) serializeJSON(event *types.HistoryEvent) ([]byte, error) {
 	return json.Marshal(se)
 }
 
+func attributesToMap(attributes any) (map[string]interface{}, error) {
+	attrBytes, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal attributes: %w", err)
+	}
+	var attrMap map[string]interface{}
+	if err := json.Unmarshal(attrBytes, &attrMap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attributes to map: %w", err)
+	}
+	return attrMap, nil
+}
+
 func (s *Serializer) serializeGob(event *types.HistoryEvent) ([]byte, error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(currentSerializerVersion))
@@ -161,56 +171,67 @@ func (s *Serializer) deserializeJSON(data []byte) (*types.HistoryEvent, error) {
 }
 
 func (s *Serializer) deserializeAttributes(eventType types.EventType, attrMap map[string]interface{}) (any, error) {
+	attrs, ok := newEventAttributes(eventType)
+	if !ok {
+		return attrMap, nil
+	}
+
 	attrBytes, err := json.Marshal(attrMap)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal attribute map: %w", err)
 	}
 
-	var attrs any
+	if err := json.Unmarshal(attrBytes, attrs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attributes for event type %s: %w", eventType, err)
+	}
+
+	return attrs, nil
+}
+
+// newEventAttributes returns an empty attributes struct for eventType, or false
+// when the event type has no typed attributes.
+func newEventAttributes(eventType types.EventType) (any, bool) {
 	switch eventType {
 	case types.EventTypeExecutionStarted:
-		attrs = &types.ExecutionStartedAttributes{}
+		return &types.ExecutionStartedAttributes{}, true
 	case types.EventTypeExecutionCompleted:
-		attrs = &types.ExecutionCompletedAttributes{}
+		return &types.ExecutionCompletedAttributes{}, true
 	case types.EventTypeExecutionFailed:
-		attrs = &types.ExecutionFailedAttributes{}
+		return &types.ExecutionFailedAttributes{}, true
 	case types.EventTypeExecutionTerminated:
-		attrs = &types.ExecutionTerminatedAttributes{}
+		return &types.ExecutionTerminatedAttributes{}, true
 	case types.EventTypeNodeScheduled:
-		attrs = &types.NodeScheduledAttributes{}
+		return &types.NodeScheduledAttributes{}, true
 	case types.EventTypeNodeStarted:
-		attrs = &types.NodeStartedAttributes{}
+		return &types.NodeStartedAttributes{}, true
 	case types.EventTypeNodeCompleted:
-		attrs = &types.NodeCompletedAttributes{}
+		return &types.NodeCompletedAttributes{}, true
 	case types.EventTypeNodeFailed:
-		attrs = &types.NodeFailedAttributes{}
+		return &types.NodeFailedAttributes{}, true
 	case types.EventTypeTimerStarted:
-		attrs = &types.TimerStartedAttributes{}
+		return &types.TimerStartedAttributes{}, true
 	case types.EventTypeTimerFired:
-		attrs = &types.TimerFiredAttributes{}
+		return &types.TimerFiredAttributes{}, true
 	case types.EventTypeTimerCanceled:
-		attrs = &types.TimerCanceledAttributes{}
+		return &types.TimerCanceledAttributes{}, true
 	case types.EventTypeActivityScheduled:
-		attrs = &types.ActivityScheduledAttributes{}
+		return &types.ActivityScheduledAttributes{}, true
 	case types.EventTypeActivityStarted:
-		attrs = &types.ActivityStartedAttributes{}
+		return &types.ActivityStartedAttributes{}, true
 	case types.EventTypeActivityCompleted:
-		attrs = &types.ActivityCompletedAttributes{}
+		return &types.ActivityCompletedAttributes{}, true
 	case types.EventTypeActivityFailed:
-		attrs = &types.ActivityFailedAttributes{}
+		return &types.ActivityFailedAttributes{}, true
 	case types.EventTypeSignalReceived:
-		attrs = &types.SignalReceivedAttributes{}
+		return &types.SignalReceivedAttributes{}, true
 	case types.EventTypeMarkerRecorded:
-		attrs = &types.MarkerRecordedAttributes{}
-	default:
-		return attrMap, nil
+		return &types.MarkerRecordedAttributes{}, true
+	case types.EventTypeChildWorkflowStarted:
+		return &types.ChildWorkflowStartedAttributes{}, true
+	case types.EventTypeChildWorkflowCompleted:
+		return &types.ChildWorkflowCompletedAttributes{}, true
 	}
-
-	if err := json.Unmarshal(attrBytes, attrs); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal attributes for event type %s: %w", eventType, err)
-	}
-
-	return attrs, nil
+	return nil, false
 }
 
 func (s *Serializer) deserializeGob(data []byte) (*types.HistoryEvent, error) {
@@ -0,0 +1,61 @@
+package history
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestForceTerminateRecordsOperatorIdentity(t *testing.T) {
+	tests := []struct {
+		name            string
+		claims          *interceptor.Claims
+		requestIdentity string
+		want            string
+	}{
+		{name: "authenticated caller", claims: &interceptor.Claims{Subject: "alice"}, requestIdentity: "mallory", want: "alice"},
+		{name: "authenticated by user ID", claims: &interceptor.Claims{UserID: "user-7"}, want: "user-7"},
+		{name: "request identity", requestIdentity: "bob", want: "bob"},
+		{name: "unknown operator", want: defaultForceTerminateIdentity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, _, _ := newArchivalTestService(t)
+			key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+			startArchivalTestExecution(t, svc, key)
+
+			callCtx := ctx
+			if tt.claims != nil {
+				callCtx = interceptor.ContextWithClaims(ctx, tt.claims)
+			}
+			_, err := NewGRPCServer(svc).ForceTerminateExecution(callCtx, &historyv1.ForceTerminateExecutionRequest{
+				Namespace:         key.NamespaceID,
+				WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+				Reason:            "stuck",
+				Identity:          tt.requestIdentity,
+			})
+			if err != nil {
+				t.Fatalf("ForceTerminateExecution: %v", err)
+			}
+
+			events, err := svc.eventStore.GetEvents(ctx, key, 1, math.MaxInt64)
+			if err != nil {
+				t.Fatalf("GetEvents: %v", err)
+			}
+			last := events[len(events)-1]
+			attrs, ok := last.Attributes.(*types.ExecutionTerminatedAttributes)
+			if last.EventType != types.EventTypeExecutionTerminated || !ok {
+				t.Fatalf("last event = %v %T, want ExecutionTerminated", last.EventType, last.Attributes)
+			}
+			if attrs.Identity != tt.want || attrs.Reason != "stuck" {
+				t.Fatalf("terminated by %q for %q, want %q for stuck", attrs.Identity, attrs.Reason, tt.want)
+			}
+		})
+	}
+}
@@ -3,17 +3,33 @@ package history
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
 	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
 	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/frontend/interceptor"
+	"github.com/linkflow/engine/internal/history/engine"
 	"github.com/linkflow/engine/internal/history/types"
+	"github.com/linkflow/engine/internal/history/visibility"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// ErrorReasonNodeNotPending is the ErrorInfo reason of a node result rejected
+// because its scheduled event is not a pending node.
+const ErrorReasonNodeNotPending = "NODE_NOT_PENDING"
+
+// ErrorReasonInvalidCommands is the ErrorInfo reason of a workflow task
+// completion rejected because its commands failed validation.
+const ErrorReasonInvalidCommands = "INVALID_COMMANDS"
+
 type GRPCServer struct {
 	historyv1.UnimplementedHistoryServiceServer
 	service *Service
@@ -48,7 +64,16 @@ func (s *GRPCServer) GetHistory(ctx context.Context, req *historyv1.GetHistoryRe
 		RunID:       req.GetWorkflowExecution().GetRunId(),
 	}
 
-	events, err := s.service.GetHistory(ctx, key, req.GetFirstEventId(), req.GetNextEventId())
+	// Unset bounds read the whole history.
+	firstEventID, lastEventID := req.GetFirstEventId(), req.GetNextEventId()
+	if firstEventID <= 0 {
+		firstEventID = 1
+	}
+	if lastEventID <= 0 {
+		lastEventID = math.MaxInt64
+	}
+
+	events, err := s.service.GetHistory(ctx, key, firstEventID, lastEventID)
 	if err != nil {
 		return nil, s.toGRPCError(err)
 	}
@@ -87,6 +112,43 @@ func (s *GRPCServer) GetMutableState(ctx context.Context, req *historyv1.GetMuta
 	}, nil
 }
 
+func (s *GRPCServer) GetWorkflowExecutionStats(ctx context.Context, req *historyv1.GetWorkflowExecutionStatsRequest) (*historyv1.GetWorkflowExecutionStatsResponse, error) {
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	stats, err := s.service.DescribeExecutionStats(ctx, key)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	resp := &historyv1.GetWorkflowExecutionStatsResponse{
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: stats.Key.WorkflowID,
+			RunId:      stats.Key.RunID,
+		},
+		Status:     internalExecutionStatusToProto(stats.Status),
+		Duration:   durationpb.New(stats.Duration),
+		EventCount: stats.EventCount,
+		NodeCounts: &historyv1.NodeStatusCounts{
+			Scheduled: stats.NodeCounts.Scheduled,
+			Running:   stats.NodeCounts.Running,
+			Completed: stats.NodeCounts.Completed,
+			Failed:    stats.NodeCounts.Failed,
+			TimedOut:  stats.NodeCounts.TimedOut,
+		},
+	}
+	if !stats.StartTime.IsZero() {
+		resp.StartTime = timestamppb.New(stats.StartTime)
+	}
+	if !stats.CloseTime.IsZero() {
+		resp.CloseTime = timestamppb.New(stats.CloseTime)
+	}
+	return resp, nil
+}
+
 func (s *GRPCServer) ResetExecution(ctx context.Context, req *historyv1.ResetExecutionRequest) (*historyv1.ResetExecutionResponse, error) {
 	key := types.ExecutionKey{
 		NamespaceID: req.GetNamespace(),
@@ -104,6 +166,276 @@ func (s *GRPCServer) ResetExecution(ctx context.Context, req *historyv1.ResetExe
 	}, nil
 }
 
+func (s *GRPCServer) ListWorkflowExecutions(ctx context.Context, req *historyv1.ListWorkflowExecutionsRequest) (*historyv1.ListWorkflowExecutionsResponse, error) {
+	resp, err := s.service.ListWorkflowExecutions(ctx, req)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) ListResetPoints(ctx context.Context, req *historyv1.ListResetPointsRequest) (*historyv1.ListResetPointsResponse, error) {
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	points, err := s.service.ListResetPoints(ctx, key)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	resp := &historyv1.ListResetPointsResponse{
+		ResetPoints: make([]*historyv1.ResetPoint, 0, len(points)),
+	}
+	for _, point := range points {
+		resp.ResetPoints = append(resp.ResetPoints, &historyv1.ResetPoint{
+			EventId:   point.EventID,
+			Label:     point.Label,
+			EventTime: timestamppb.New(point.Timestamp),
+		})
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) GetExecutionTree(ctx context.Context, req *historyv1.GetExecutionTreeRequest) (*historyv1.GetExecutionTreeResponse, error) {
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	tree, err := s.service.GetExecutionTree(ctx, key)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return &historyv1.GetExecutionTreeResponse{
+		Root:      executionTreeNodeToProto(tree.Root),
+		NodeCount: int32(tree.NodeCount),
+		Truncated: tree.Truncated,
+	}, nil
+}
+
+func executionTreeNodeToProto(node *ExecutionTreeNode) *historyv1.ExecutionTreeNode {
+	info := &historyv1.WorkflowExecutionInfo{
+		Execution: &commonv1.WorkflowExecution{
+			WorkflowId: node.Key.WorkflowID,
+			RunId:      node.Key.RunID,
+		},
+		Type:   &apiv1.WorkflowType{Name: node.WorkflowType},
+		Status: node.Status,
+	}
+	if !node.StartTime.IsZero() {
+		info.StartTime = timestamppb.New(node.StartTime)
+	}
+	if !node.CloseTime.IsZero() {
+		info.CloseTime = timestamppb.New(node.CloseTime)
+	}
+
+	out := &historyv1.ExecutionTreeNode{
+		ExecutionInfo: info,
+		Children:      make([]*historyv1.ExecutionTreeNode, 0, len(node.Children)),
+	}
+	for _, child := range node.Children {
+		out.Children = append(out.Children, executionTreeNodeToProto(child))
+	}
+	return out
+}
+
+func (s *GRPCServer) DescribeWorkflowExecution(ctx context.Context, req *historyv1.DescribeWorkflowExecutionRequest) (*historyv1.DescribeWorkflowExecutionResponse, error) {
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	desc, err := s.service.DescribeWorkflowExecution(ctx, key)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	info := &historyv1.WorkflowExecutionInfo{
+		Execution: &commonv1.WorkflowExecution{
+			WorkflowId: key.WorkflowID,
+			RunId:      desc.Info.RunID,
+		},
+		Type:              &apiv1.WorkflowType{Name: desc.Info.WorkflowTypeName},
+		Status:            internalExecutionStatusToProto(desc.Info.Status),
+		ParentExecutionId: desc.Info.ParentWorkflowID,
+	}
+	if info.Execution.RunId == "" {
+		info.Execution.RunId = key.RunID
+	}
+	if !desc.Info.StartTime.IsZero() {
+		info.StartTime = timestamppb.New(desc.Info.StartTime)
+	}
+	if !desc.Info.CloseTime.IsZero() {
+		info.CloseTime = timestamppb.New(desc.Info.CloseTime)
+	}
+
+	resp := &historyv1.DescribeWorkflowExecutionResponse{
+		ExecutionInfo:     info,
+		TaskQueue:         desc.Info.TaskQueue,
+		PendingActivities: make([]*historyv1.PendingActivityInfo, 0, len(desc.PendingActivities)),
+		PendingTimers:     make([]*historyv1.PendingTimerInfo, 0, len(desc.PendingTimers)),
+		VersionMarkers:    make([]*historyv1.VersionMarkerInfo, 0, len(desc.VersionMarkers)),
+	}
+	for _, activity := range desc.PendingActivities {
+		pending := &historyv1.PendingActivityInfo{
+			ScheduledEventId: activity.ScheduledEventID,
+			ActivityId:       activity.ActivityID,
+			ActivityType:     activity.ActivityType,
+			State:            historyv1.PendingActivityState_PENDING_ACTIVITY_STATE_SCHEDULED,
+			Attempt:          activity.Attempt,
+		}
+		if activity.Started {
+			pending.State = historyv1.PendingActivityState_PENDING_ACTIVITY_STATE_STARTED
+		}
+		if !activity.ScheduledTime.IsZero() {
+			pending.ScheduledTime = timestamppb.New(activity.ScheduledTime)
+		}
+		if !activity.LastStartedTime.IsZero() {
+			pending.LastStartedTime = timestamppb.New(activity.LastStartedTime)
+		}
+		if !activity.LastHeartbeat.IsZero() {
+			pending.LastHeartbeatTime = timestamppb.New(activity.LastHeartbeat)
+		}
+		if activity.LastFailure != "" || !activity.LastFailureTime.IsZero() {
+			pending.LastFailure = &commonv1.Failure{Message: activity.LastFailure}
+			pending.LastFailureTime = timestamppb.New(activity.LastFailureTime)
+		}
+		resp.PendingActivities = append(resp.PendingActivities, pending)
+	}
+	for _, timer := range desc.PendingTimers {
+		resp.PendingTimers = append(resp.PendingTimers, &historyv1.PendingTimerInfo{
+			TimerId:        timer.TimerID,
+			StartedEventId: timer.StartedEventID,
+			FireTime:       timestamppb.New(timer.FireTime),
+		})
+	}
+	for _, marker := range desc.VersionMarkers {
+		resp.VersionMarkers = append(resp.VersionMarkers, &historyv1.VersionMarkerInfo{
+			ChangeId: marker.ChangeID,
+			Version:  marker.Version,
+			EventId:  marker.EventID,
+		})
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) DescribeStateAtEvent(ctx context.Context, req *historyv1.DescribeStateAtEventRequest) (*historyv1.DescribeStateAtEventResponse, error) {
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	result, err := s.service.DescribeStateAtEvent(ctx, key, req.GetEventId())
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	stateJSON, err := result.JSON()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode state: %v", err)
+	}
+
+	return &historyv1.DescribeStateAtEventResponse{
+		EventId:         result.EventID,
+		Event:           internalEventToProto(result.Event),
+		StateJson:       stateJSON,
+		SnapshotEventId: result.SnapshotEventID,
+	}, nil
+}
+
+func (s *GRPCServer) DescribeShards(ctx context.Context, req *historyv1.DescribeShardsRequest) (*historyv1.DescribeShardsResponse, error) {
+	shards, err := s.service.DescribeShards(ctx)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	resp := &historyv1.DescribeShardsResponse{
+		NumShards: int32(len(shards)),
+		Shards:    make([]*historyv1.ShardInfo, 0, len(shards)),
+	}
+	for _, shard := range shards {
+		resp.Shards = append(resp.Shards, &historyv1.ShardInfo{
+			ShardId:           shard.ShardID,
+			Owned:             shard.Owned,
+			RunningExecutions: shard.RunningExecutions,
+		})
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) RebalanceShards(ctx context.Context, req *historyv1.RebalanceShardsRequest) (*historyv1.RebalanceShardsResponse, error) {
+	return &historyv1.RebalanceShardsResponse{
+		AcquiredShardIds: s.service.RebalanceShards(),
+	}, nil
+}
+
+func (s *GRPCServer) ForceTerminateExecution(ctx context.Context, req *historyv1.ForceTerminateExecutionRequest) (*historyv1.ForceTerminateExecutionResponse, error) {
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	// The authenticated operator takes precedence over the identity the
+	// request claims.
+	identity := req.GetIdentity()
+	if claims, ok := interceptor.ClaimsFromContext(ctx); ok {
+		if caller := controlplane.ClaimsIdentity(claims); caller != "" {
+			identity = caller
+		}
+	}
+
+	if err := s.service.ForceTerminate(ctx, key, req.GetReason(), identity); err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &historyv1.ForceTerminateExecutionResponse{}, nil
+}
+
+func (s *GRPCServer) DeleteWorkflowExecution(ctx context.Context, req *historyv1.DeleteWorkflowExecutionRequest) (*historyv1.DeleteWorkflowExecutionResponse, error) {
+	key := types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+
+	runID, err := s.service.DeleteWorkflowExecution(ctx, key)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+
+	return &historyv1.DeleteWorkflowExecutionResponse{RunId: runID}, nil
+}
+
+func (s *GRPCServer) RespondActivityTaskCompleted(ctx context.Context, req *historyv1.RespondActivityTaskCompletedRequest) (*historyv1.RespondActivityTaskCompletedResponse, error) {
+	resp, err := s.service.RespondActivityTaskCompleted(ctx, req)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) RespondActivityTaskFailed(ctx context.Context, req *historyv1.RespondActivityTaskFailedRequest) (*historyv1.RespondActivityTaskFailedResponse, error) {
+	resp, err := s.service.RespondActivityTaskFailed(ctx, req)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) RecordActivityTaskPending(ctx context.Context, req *historyv1.RecordActivityTaskPendingRequest) (*historyv1.RecordActivityTaskPendingResponse, error) {
+	resp, err := s.service.RecordActivityTaskPending(ctx, req)
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return resp, nil
+}
+
 func (s *GRPCServer) toGRPCError(err error) error {
 	if err == nil {
 		return nil
@@ -111,12 +443,52 @@ func (s *GRPCServer) toGRPCError(err error) error {
 	if errors.Is(err, types.ErrExecutionNotFound) || errors.Is(err, ErrEventNotFound) {
 		return status.Error(codes.NotFound, err.Error())
 	}
-	if errors.Is(err, ErrServiceNotRunning) {
+	if errors.Is(err, ErrServiceNotRunning) || errors.Is(err, ErrVisibilityNotConfigured) {
 		return status.Error(codes.Unavailable, err.Error())
 	}
+	if errors.Is(err, ErrSignalBufferFull) || errors.Is(err, ErrSignalRateLimited) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
 	if errors.Is(err, types.ErrOptimisticLock) {
 		return status.Error(codes.Aborted, err.Error())
 	}
+	if errors.Is(err, ErrInvalidTaskToken) || errors.Is(err, ErrInvalidResetPoint) || errors.Is(err, ErrEventIDOutOfRange) || errors.Is(err, errInvalidListPageToken) || errors.Is(err, visibility.ErrInvalidQuery) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, engine.ErrNodeNotPending) {
+		st, detailErr := status.New(codes.FailedPrecondition, err.Error()).WithDetails(&errdetails.ErrorInfo{
+			Reason: ErrorReasonNodeNotPending,
+			Domain: "history.linkflow",
+		})
+		if detailErr != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return st.Err()
+	}
+	var commandErr *CommandValidationError
+	if errors.As(err, &commandErr) {
+		metadata := make(map[string]string, len(commandErr.Violations))
+		for _, v := range commandErr.Violations {
+			key := fmt.Sprintf("command_%d", v.CommandIndex)
+			if prev, ok := metadata[key]; ok {
+				metadata[key] = prev + "; " + v.Reason
+			} else {
+				metadata[key] = v.Reason
+			}
+		}
+		st, detailErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(&errdetails.ErrorInfo{
+			Reason:   ErrorReasonInvalidCommands,
+			Domain:   "history.linkflow",
+			Metadata: metadata,
+		})
+		if detailErr != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return st.Err()
+	}
+	if errors.Is(err, ErrActivityNotPending) || errors.Is(err, ErrExecutionRunning) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
 	// Add other mappings as needed
 	return err
 }
@@ -143,8 +515,9 @@ func protoEventToInternal(pe *historyv1.HistoryEvent) *types.HistoryEvent {
 	case types.EventTypeExecutionStarted:
 		if attr := pe.GetExecutionStartedAttributes(); attr != nil {
 			internalAttr := &types.ExecutionStartedAttributes{
-				WorkflowType: attr.GetWorkflowType().GetName(),
-				TaskQueue:    attr.GetTaskQueue().GetName(),
+				WorkflowType:  attr.GetWorkflowType().GetName(),
+				TaskQueue:     attr.GetTaskQueue().GetName(),
+				CorrelationID: string(attr.GetHeader().GetFields()[CorrelationIDHeaderField].GetData()),
 			}
 			if input := attr.GetInput(); input != nil && len(input.GetPayloads()) > 0 {
 				internalAttr.Input = input.GetPayloads()[0].GetData()
@@ -197,6 +570,17 @@ func protoEventToInternal(pe *historyv1.HistoryEvent) *types.HistoryEvent {
 			}
 			event.Attributes = internalAttr
 		}
+	case types.EventTypeSignalReceived:
+		if attr := pe.GetSignalReceivedAttributes(); attr != nil {
+			internalAttr := &types.SignalReceivedAttributes{
+				SignalName: attr.GetSignalName(),
+				Identity:   attr.GetIdentity(),
+			}
+			if input := attr.GetInput(); input != nil && len(input.GetPayloads()) > 0 {
+				internalAttr.Input = input.GetPayloads()[0].GetData()
+			}
+			event.Attributes = internalAttr
+		}
 		// TODO: Add Timer and Activity mappings if needed for future tasks
 		// For now, Node events are critical for workflow progress.
 	}
@@ -214,6 +598,10 @@ func protoEventTypeToInternal(et commonv1.EventType) types.EventType {
 		return types.EventTypeExecutionFailed
 	case commonv1.EventType_EVENT_TYPE_EXECUTION_TERMINATED:
 		return types.EventTypeExecutionTerminated
+	case commonv1.EventType_EVENT_TYPE_EXECUTION_TIMED_OUT:
+		return types.EventTypeExecutionTimedOut
+	case commonv1.EventType_EVENT_TYPE_EXECUTION_CANCELLED:
+		return types.EventTypeExecutionCanceled
 	case commonv1.EventType_EVENT_TYPE_NODE_SCHEDULED:
 		return types.EventTypeNodeScheduled
 	case commonv1.EventType_EVENT_TYPE_NODE_STARTED:
@@ -230,6 +618,14 @@ func protoEventTypeToInternal(et commonv1.EventType) types.EventType {
 		return types.EventTypeTimerFired
 	case commonv1.EventType_EVENT_TYPE_TIMER_CANCELLED:
 		return types.EventTypeTimerCanceled
+	case commonv1.EventType_EVENT_TYPE_CHILD_WORKFLOW_STARTED:
+		return types.EventTypeChildWorkflowStarted
+	case commonv1.EventType_EVENT_TYPE_CHILD_WORKFLOW_COMPLETED:
+		return types.EventTypeChildWorkflowCompleted
+	case commonv1.EventType_EVENT_TYPE_SIGNAL_RECEIVED:
+		return types.EventTypeSignalReceived
+	case commonv1.EventType_EVENT_TYPE_MARKER_RECORDED:
+		return types.EventTypeMarkerRecorded
 	default:
 		return types.EventTypeUnspecified
 	}
@@ -245,6 +641,10 @@ func internalEventTypeToProto(et types.EventType) commonv1.EventType {
 		return commonv1.EventType_EVENT_TYPE_EXECUTION_FAILED
 	case types.EventTypeExecutionTerminated:
 		return commonv1.EventType_EVENT_TYPE_EXECUTION_TERMINATED
+	case types.EventTypeExecutionTimedOut:
+		return commonv1.EventType_EVENT_TYPE_EXECUTION_TIMED_OUT
+	case types.EventTypeExecutionCanceled:
+		return commonv1.EventType_EVENT_TYPE_EXECUTION_CANCELLED
 	case types.EventTypeNodeScheduled:
 		return commonv1.EventType_EVENT_TYPE_NODE_SCHEDULED
 	case types.EventTypeNodeStarted:
@@ -261,11 +661,38 @@ func internalEventTypeToProto(et types.EventType) commonv1.EventType {
 		return commonv1.EventType_EVENT_TYPE_TIMER_FIRED
 	case types.EventTypeTimerCanceled:
 		return commonv1.EventType_EVENT_TYPE_TIMER_CANCELLED
+	case types.EventTypeChildWorkflowStarted:
+		return commonv1.EventType_EVENT_TYPE_CHILD_WORKFLOW_STARTED
+	case types.EventTypeChildWorkflowCompleted:
+		return commonv1.EventType_EVENT_TYPE_CHILD_WORKFLOW_COMPLETED
+	case types.EventTypeSignalReceived:
+		return commonv1.EventType_EVENT_TYPE_SIGNAL_RECEIVED
+	case types.EventTypeMarkerRecorded:
+		return commonv1.EventType_EVENT_TYPE_MARKER_RECORDED
 	default:
 		return commonv1.EventType_EVENT_TYPE_UNSPECIFIED
 	}
 }
 
+func internalExecutionStatusToProto(st types.ExecutionStatus) commonv1.ExecutionStatus {
+	switch st {
+	case types.ExecutionStatusRunning:
+		return commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING
+	case types.ExecutionStatusCompleted:
+		return commonv1.ExecutionStatus_EXECUTION_STATUS_COMPLETED
+	case types.ExecutionStatusFailed:
+		return commonv1.ExecutionStatus_EXECUTION_STATUS_FAILED
+	case types.ExecutionStatusTerminated:
+		return commonv1.ExecutionStatus_EXECUTION_STATUS_TERMINATED
+	case types.ExecutionStatusTimedOut:
+		return commonv1.ExecutionStatus_EXECUTION_STATUS_TIMED_OUT
+	case types.ExecutionStatusCanceled:
+		return commonv1.ExecutionStatus_EXECUTION_STATUS_CANCELLED
+	default:
+		return commonv1.ExecutionStatus_EXECUTION_STATUS_UNSPECIFIED
+	}
+}
+
 func internalEventToProto(e *types.HistoryEvent) *historyv1.HistoryEvent {
 	if e == nil {
 		return nil
@@ -282,12 +709,18 @@ func internalEventToProto(e *types.HistoryEvent) *historyv1.HistoryEvent {
 	switch e.EventType {
 	case types.EventTypeExecutionStarted:
 		if attr, ok := e.Attributes.(*types.ExecutionStartedAttributes); ok {
+			started := &historyv1.ExecutionStartedEventAttributes{
+				WorkflowType: &apiv1.WorkflowType{Name: attr.WorkflowType},
+				TaskQueue:    &apiv1.TaskQueue{Name: attr.TaskQueue},
+				Input:        &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: attr.Input}}},
+			}
+			if attr.CorrelationID != "" {
+				started.Header = &commonv1.Header{Fields: map[string]*commonv1.Payload{
+					CorrelationIDHeaderField: {Data: []byte(attr.CorrelationID)},
+				}}
+			}
 			event.Attributes = &historyv1.HistoryEvent_ExecutionStartedAttributes{ // This one was correct
-				ExecutionStartedAttributes: &historyv1.ExecutionStartedEventAttributes{
-					WorkflowType: &apiv1.WorkflowType{Name: attr.WorkflowType},
-					TaskQueue:    &apiv1.TaskQueue{Name: attr.TaskQueue},
-					Input:        &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: attr.Input}}},
-				},
+				ExecutionStartedAttributes: started,
 			}
 		}
 	case types.EventTypeNodeScheduled:
@@ -336,6 +769,55 @@ func internalEventToProto(e *types.HistoryEvent) *historyv1.HistoryEvent {
 				event.GetNodeFailedAttributes().Logs = &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: attr.Logs}}}
 			}
 		}
+	case types.EventTypeChildWorkflowStarted:
+		if attr, ok := e.Attributes.(*types.ChildWorkflowStartedAttributes); ok {
+			event.Attributes = &historyv1.HistoryEvent_ChildWorkflowStartedAttributes{
+				ChildWorkflowStartedAttributes: &historyv1.ChildWorkflowStartedEventAttributes{
+					NodeId:            attr.NodeID,
+					WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: attr.WorkflowID, RunId: attr.RunID},
+					WorkflowType:      &apiv1.WorkflowType{Name: attr.WorkflowType},
+				},
+			}
+		}
+	case types.EventTypeChildWorkflowCompleted:
+		if attr, ok := e.Attributes.(*types.ChildWorkflowCompletedAttributes); ok {
+			protoAttr := &historyv1.ChildWorkflowCompletedEventAttributes{
+				NodeId:            attr.NodeID,
+				WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: attr.WorkflowID, RunId: attr.RunID},
+				Status:            internalExecutionStatusToProto(attr.Status),
+			}
+			if len(attr.Result) > 0 {
+				protoAttr.Result = &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: attr.Result}}}
+			}
+			if attr.FailureReason != "" {
+				protoAttr.Failure = &commonv1.Failure{Message: attr.FailureReason}
+			}
+			event.Attributes = &historyv1.HistoryEvent_ChildWorkflowCompletedAttributes{
+				ChildWorkflowCompletedAttributes: protoAttr,
+			}
+		}
+	case types.EventTypeSignalReceived:
+		if attr, ok := e.Attributes.(*types.SignalReceivedAttributes); ok {
+			protoAttr := &historyv1.SignalReceivedEventAttributes{
+				SignalName: attr.SignalName,
+				Identity:   attr.Identity,
+			}
+			if len(attr.Input) > 0 {
+				protoAttr.Input = &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: attr.Input}}}
+			}
+			event.Attributes = &historyv1.HistoryEvent_SignalReceivedAttributes{
+				SignalReceivedAttributes: protoAttr,
+			}
+		}
+	case types.EventTypeMarkerRecorded:
+		if attr, ok := e.Attributes.(*types.MarkerRecordedAttributes); ok {
+			event.Attributes = &historyv1.HistoryEvent_MarkerRecordedAttributes{
+				MarkerRecordedAttributes: &historyv1.MarkerRecordedEventAttributes{
+					MarkerName: attr.MarkerName,
+					Details:    attr.Details,
+				},
+			}
+		}
 	}
 
 	return event
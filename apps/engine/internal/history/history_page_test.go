@@ -0,0 +1,115 @@
+package history
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestGetHistoryPageFiltersByEventType(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	var events []*types.HistoryEvent
+	for i, eventType := range []types.EventType{
+		types.EventTypeExecutionStarted,
+		types.EventTypeNodeScheduled,
+		types.EventTypeNodeStarted,
+		types.EventTypeNodeCompleted,
+		types.EventTypeNodeScheduled,
+		types.EventTypeNodeStarted,
+		types.EventTypeNodeFailed,
+		types.EventTypeExecutionFailed,
+	} {
+		events = append(events, &types.HistoryEvent{EventID: int64(i + 1), EventType: eventType})
+	}
+	if err := eventStore.AppendEvents(ctx, key, events, 0); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		eventTypes []types.EventType
+		pageSize   int32
+		wantPages  [][]int64
+		wantTotal  int64
+	}{
+		{
+			name:      "no filter",
+			pageSize:  5,
+			wantPages: [][]int64{{1, 2, 3, 4, 5}, {6, 7, 8}},
+			wantTotal: 8,
+		},
+		{
+			name:       "single type",
+			eventTypes: []types.EventType{types.EventTypeNodeScheduled},
+			pageSize:   1,
+			wantPages:  [][]int64{{2}, {5}},
+			wantTotal:  2,
+		},
+		{
+			name:       "several types",
+			eventTypes: []types.EventType{types.EventTypeNodeCompleted, types.EventTypeNodeFailed, types.EventTypeExecutionFailed},
+			pageSize:   2,
+			wantPages:  [][]int64{{4, 7}, {8}},
+			wantTotal:  3,
+		},
+		{
+			name:       "page ends on last match",
+			eventTypes: []types.EventType{types.EventTypeNodeStarted},
+			pageSize:   2,
+			wantPages:  [][]int64{{3, 6}},
+			wantTotal:  2,
+		},
+		{
+			name:       "no matches",
+			eventTypes: []types.EventType{types.EventTypeTimerFired},
+			pageSize:   10,
+			wantPages:  [][]int64{nil},
+			wantTotal:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages [][]int64
+			token := ""
+			for {
+				resp, err := svc.GetHistoryPage(ctx, &GetHistoryPageRequest{
+					Key:        key,
+					PageSize:   tt.pageSize,
+					PageToken:  token,
+					EventTypes: tt.eventTypes,
+				})
+				if err != nil {
+					t.Fatalf("GetHistoryPage: %v", err)
+				}
+				if resp.TotalEvents != tt.wantTotal {
+					t.Fatalf("total events = %d, want %d", resp.TotalEvents, tt.wantTotal)
+				}
+				var ids []int64
+				for _, event := range resp.Events {
+					ids = append(ids, event.EventID)
+				}
+				pages = append(pages, ids)
+				if resp.NextPageToken == "" {
+					break
+				}
+				if len(pages) > len(tt.wantPages) {
+					t.Fatalf("more pages than expected: %v", pages)
+				}
+				token = resp.NextPageToken
+			}
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Fatalf("pages = %v, want %v", pages, tt.wantPages)
+			}
+		})
+	}
+}
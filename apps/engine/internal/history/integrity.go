@@ -0,0 +1,163 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/events"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrHashChainUnsupported is returned by VerifyHistoryIntegrity when the
+// event store does not record hash chains.
+var ErrHashChainUnsupported = errors.New("event store does not record event hash chains")
+
+// HashChainSetter is implemented by event stores that can chain the hashes
+// of the events of selected namespaces as they are appended.
+type HashChainSetter interface {
+	SetHashChainNamespaces(namespaces []string)
+}
+
+// EventHashReader is implemented by event stores that record hash chains.
+type EventHashReader interface {
+	GetEventHashes(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64) ([]types.EventHash, error)
+}
+
+// HistoryIntegrity is the outcome of checking an execution's event hash
+// chain.
+type HistoryIntegrity struct {
+	NamespaceID string `json:"namespace_id"`
+	WorkflowID  string `json:"workflow_id"`
+	RunID       string `json:"run_id"`
+	Events      int64  `json:"events"`
+	// HashedEvents is how many events were verified against their hash,
+	// starting at ChainStartEventID. Hashed is false for histories written
+	// before their namespace opted in; there is nothing to verify.
+	HashedEvents      int64 `json:"hashed_events"`
+	ChainStartEventID int64 `json:"chain_start_event_id,omitempty"`
+	Hashed            bool  `json:"hashed"`
+	Intact            bool  `json:"intact"`
+	// BrokenEventID is the first event whose link does not hold, and
+	// Reason why.
+	BrokenEventID int64  `json:"broken_event_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// VerifyHistoryIntegrity recomputes an execution's event hash chain from its
+// stored events and reports the first broken link. The chain starts at the
+// first hashed event: the first event appended after the namespace opted in,
+// whose predecessors, if any, are unhashed, or the oldest event left by
+// compaction, whose prev_hash is taken as given. Every event after it must
+// be present, link to the hash of the one before, and hash to its stored
+// hash.
+func (s *Service) VerifyHistoryIntegrity(ctx context.Context, key types.ExecutionKey) (*HistoryIntegrity, error) {
+	reader, ok := s.eventStore.(EventHashReader)
+	if !ok {
+		return nil, ErrHashChainUnsupported
+	}
+
+	evts, err := s.eventStore.GetEvents(ctx, key, 1, math.MaxInt64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
+	}
+	if len(evts) == 0 {
+		return nil, fmt.Errorf("%w: no history", types.ErrExecutionNotFound)
+	}
+	hashes, err := reader.GetEventHashes(ctx, key, 1, math.MaxInt64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch event hashes: %w", err)
+	}
+	links := make(map[int64]types.EventHash, len(hashes))
+	for _, h := range hashes {
+		links[h.EventID] = h
+	}
+
+	result := &HistoryIntegrity{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Events:      int64(len(evts)),
+	}
+	broken := func(eventID int64, format string, args ...any) (*HistoryIntegrity, error) {
+		result.BrokenEventID = eventID
+		result.Reason = fmt.Sprintf(format, args...)
+		return result, nil
+	}
+
+	var prevHash []byte
+	for i, event := range evts {
+		link := links[event.EventID]
+		if !result.Hashed {
+			if len(link.Hash) == 0 {
+				continue
+			}
+			result.Hashed = true
+			result.ChainStartEventID = event.EventID
+			if i == 0 && event.EventID > 1 {
+				prevHash = link.PrevHash
+			}
+		} else if prev := evts[i-1].EventID; event.EventID != prev+1 {
+			return broken(prev+1, "events %d to %d are missing", prev+1, event.EventID-1)
+		}
+
+		if len(link.Hash) == 0 {
+			return broken(event.EventID, "event has no hash")
+		}
+		if !bytes.Equal(link.PrevHash, prevHash) {
+			return broken(event.EventID, "prev_hash does not match the hash of event %d", event.EventID-1)
+		}
+		hash, err := events.ChainHash(prevHash, event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash event %d: %w", event.EventID, err)
+		}
+		if !bytes.Equal(link.Hash, hash) {
+			return broken(event.EventID, "hash does not match the event")
+		}
+		prevHash = link.Hash
+		result.HashedEvents++
+	}
+
+	if !result.Hashed {
+		result.Reason = "history has no hash chain"
+		return result, nil
+	}
+	result.Intact = true
+	return result, nil
+}
+
+// VerifyHistoryIntegrity checks the request's execution history against its
+// hash chain.
+func (s *GRPCServer) VerifyHistoryIntegrity(ctx context.Context, req *historyv1.VerifyHistoryIntegrityRequest) (*historyv1.VerifyHistoryIntegrityResponse, error) {
+	key, err := consistencyRequestKey(req.GetNamespace(), req.GetWorkflowExecution())
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.service.VerifyHistoryIntegrity(ctx, key)
+	if errors.Is(err, ErrHashChainUnsupported) {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		return nil, s.toGRPCError(err)
+	}
+	return &historyv1.VerifyHistoryIntegrityResponse{
+		Namespace: result.NamespaceID,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: result.WorkflowID,
+			RunId:      result.RunID,
+		},
+		Events:            result.Events,
+		HashedEvents:      result.HashedEvents,
+		ChainStartEventId: result.ChainStartEventID,
+		Hashed:            result.Hashed,
+		Intact:            result.Intact,
+		BrokenEventId:     result.BrokenEventID,
+		Reason:            result.Reason,
+	}, nil
+}
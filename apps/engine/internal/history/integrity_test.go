@@ -0,0 +1,94 @@
+package history
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestVerifyHistoryIntegrity(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	svc := newTestService(t, Config{
+		EventStore:          eventStore,
+		HashChainNamespaces: []string{"audited"},
+	})
+
+	record := func(key types.ExecutionKey) {
+		t.Helper()
+		events := []*types.HistoryEvent{
+			{EventType: types.EventTypeExecutionStarted, Attributes: &types.ExecutionStartedAttributes{WorkflowType: "order", TaskQueue: "default"}},
+			{EventType: types.EventTypeSignalReceived, Attributes: &types.SignalReceivedAttributes{SignalName: "approve", Input: []byte(`{"ok":true}`)}},
+			{EventType: types.EventTypeExecutionCompleted, Attributes: &types.ExecutionCompletedAttributes{Result: []byte(`{"total":42}`)}},
+		}
+		for _, event := range events {
+			event.Timestamp = time.Now()
+			if err := svc.RecordEvent(ctx, key, event); err != nil {
+				t.Fatalf("record %s: %v", event.EventType, err)
+			}
+		}
+	}
+	audited := types.ExecutionKey{NamespaceID: "audited", WorkflowID: "order", RunID: "run-1"}
+	legacy := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	record(audited)
+	record(legacy)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	historyv1.RegisterHistoryServiceServer(server, NewGRPCServer(svc))
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	verified, err := historyv1.NewHistoryServiceClient(conn).VerifyHistoryIntegrity(ctx, &historyv1.VerifyHistoryIntegrityRequest{
+		Namespace:         audited.NamespaceID,
+		WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: audited.WorkflowID, RunId: audited.RunID},
+	})
+	if err != nil || !verified.GetHashed() || !verified.GetIntact() || verified.GetHashedEvents() != 3 || verified.GetChainStartEventId() != 1 {
+		t.Fatalf("verify hashed history = %+v, %v", verified, err)
+	}
+
+	// Histories of namespaces that did not opt in stay readable and report
+	// that there is no chain to check.
+	result, err := svc.VerifyHistoryIntegrity(ctx, legacy)
+	if err != nil || result.Hashed || result.Intact || result.Events != 3 {
+		t.Fatalf("verify legacy history = %+v, %v", result, err)
+	}
+
+	// Rewriting an event's payload breaks its link and no other.
+	events, err := eventStore.GetEvents(ctx, audited, 2, 2)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("get event 2 = %v, %v", events, err)
+	}
+	events[0].Attributes.(*types.SignalReceivedAttributes).Input = []byte(`{"ok":false}`)
+	result, err = svc.VerifyHistoryIntegrity(ctx, audited)
+	if err != nil || result.Intact || result.BrokenEventID != 2 || result.Reason != "hash does not match the event" {
+		t.Fatalf("verify tampered history = %+v, %v", result, err)
+	}
+
+	// Deleting the event is caught too.
+	if _, err := eventStore.TrimEvents(ctx, audited, 2, 2, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("trim: %v", err)
+	}
+	result, err = svc.VerifyHistoryIntegrity(ctx, audited)
+	if err != nil || result.Intact || result.BrokenEventID != 2 {
+		t.Fatalf("verify history with a missing event = %+v, %v", result, err)
+	}
+}
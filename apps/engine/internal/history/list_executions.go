@@ -0,0 +1,134 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/visibility"
+)
+
+// defaultListPageSize matches the visibility store default.
+const defaultListPageSize = 100
+
+var errInvalidListPageToken = errors.New("invalid next page token")
+
+// listModeFor resolves which executions a list request targets. An exact
+// status picks the open or closed listing it belongs to.
+func listModeFor(req *historyv1.ListWorkflowExecutionsRequest) historyv1.ExecutionStatusFilter {
+	switch req.Status {
+	case commonv1.ExecutionStatus_EXECUTION_STATUS_UNSPECIFIED:
+	case commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING:
+		return historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_OPEN
+	default:
+		return historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_CLOSED
+	}
+	if req.StatusFilter == historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_UNSPECIFIED {
+		return historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_OPEN
+	}
+	return req.StatusFilter
+}
+
+// allExecutionsPageToken tracks the position in the open and closed listings
+// separately; each cursor is a visibility store token.
+type allExecutionsPageToken struct {
+	Open       []byte `json:"open,omitempty"`
+	Closed     []byte `json:"closed,omitempty"`
+	OpenDone   bool   `json:"open_done,omitempty"`
+	ClosedDone bool   `json:"closed_done,omitempty"`
+}
+
+type listFunc func(ctx context.Context, req *visibility.ListRequest) (*visibility.ListResponse, error)
+
+// listAllWorkflowExecutions merges the open and closed listings, most recent
+// activity first: open executions by start time and closed ones by close time,
+// which is the order each listing is already sorted in.
+func (s *Service) listAllWorkflowExecutions(ctx context.Context, req *visibility.ListRequest) (*visibility.ListResponse, error) {
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultListPageSize
+	}
+
+	var token allExecutionsPageToken
+	if len(req.NextPageToken) > 0 {
+		if err := json.Unmarshal(req.NextPageToken, &token); err != nil {
+			return nil, errInvalidListPageToken
+		}
+	}
+
+	fetch := func(list listFunc, cursor []byte, done bool) (*visibility.ListResponse, error) {
+		if done {
+			return &visibility.ListResponse{}, nil
+		}
+		return list(ctx, &visibility.ListRequest{
+			NamespaceID:   req.NamespaceID,
+			PageSize:      pageSize,
+			NextPageToken: cursor,
+			Query:         req.Query,
+		})
+	}
+
+	open, err := fetch(s.visibilityStore.ListOpenWorkflowExecutions, token.Open, token.OpenDone)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := fetch(s.visibilityStore.ListClosedWorkflowExecutions, token.Closed, token.ClosedDone)
+	if err != nil {
+		return nil, err
+	}
+
+	executions := make([]*visibility.WorkflowExecutionInfo, 0, pageSize)
+	i, j := 0, 0
+	for len(executions) < pageSize && (i < len(open.Executions) || j < len(closed.Executions)) {
+		takeOpen := j >= len(closed.Executions) ||
+			(i < len(open.Executions) && !open.Executions[i].StartTime.Before(closed.Executions[j].CloseTime))
+		if takeOpen {
+			executions = append(executions, open.Executions[i])
+			i++
+		} else {
+			executions = append(executions, closed.Executions[j])
+			j++
+		}
+	}
+
+	advance := func(list listFunc, cursor []byte, done bool, page *visibility.ListResponse, consumed int) ([]byte, bool, error) {
+		switch {
+		case done:
+			return nil, true, nil
+		case consumed == len(page.Executions):
+			return page.NextPageToken, page.NextPageToken == nil, nil
+		case consumed == 0:
+			return cursor, false, nil
+		}
+		// Part of the page was used: ask the store for the cursor just
+		// after the consumed executions.
+		resp, err := list(ctx, &visibility.ListRequest{
+			NamespaceID:   req.NamespaceID,
+			PageSize:      consumed,
+			NextPageToken: cursor,
+			Query:         req.Query,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		return resp.NextPageToken, resp.NextPageToken == nil, nil
+	}
+
+	var next allExecutionsPageToken
+	if next.Open, next.OpenDone, err = advance(s.visibilityStore.ListOpenWorkflowExecutions, token.Open, token.OpenDone, open, i); err != nil {
+		return nil, err
+	}
+	if next.Closed, next.ClosedDone, err = advance(s.visibilityStore.ListClosedWorkflowExecutions, token.Closed, token.ClosedDone, closed, j); err != nil {
+		return nil, err
+	}
+
+	resp := &visibility.ListResponse{Executions: executions}
+	if !next.OpenDone || !next.ClosedDone {
+		if resp.NextPageToken, err = json.Marshal(next); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,198 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/visibility"
+)
+
+// memoryVisibilityStore lists executions like the Postgres store: open ones by
+// start time and closed ones by close time, newest first, paged by offset and
+// narrowed by the request's query.
+type memoryVisibilityStore struct {
+	visibility.Store
+	executions []*visibility.WorkflowExecutionInfo
+}
+
+func (m *memoryVisibilityStore) ListOpenWorkflowExecutions(_ context.Context, req *visibility.ListRequest) (*visibility.ListResponse, error) {
+	return m.list(req, true)
+}
+
+func (m *memoryVisibilityStore) ListClosedWorkflowExecutions(_ context.Context, req *visibility.ListRequest) (*visibility.ListResponse, error) {
+	return m.list(req, false)
+}
+
+func (m *memoryVisibilityStore) list(req *visibility.ListRequest, open bool) (*visibility.ListResponse, error) {
+	query, err := visibility.CompileQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	var matched []*visibility.WorkflowExecutionInfo
+	for _, info := range m.executions {
+		running := info.Status == commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING
+		if running != open || !query.Matches(info) {
+			continue
+		}
+		if req.Status != commonv1.ExecutionStatus_EXECUTION_STATUS_UNSPECIFIED && info.Status != req.Status {
+			continue
+		}
+		matched = append(matched, info)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if open {
+			return matched[i].StartTime.After(matched[j].StartTime)
+		}
+		return matched[i].CloseTime.After(matched[j].CloseTime)
+	})
+
+	offset := 0
+	if len(req.NextPageToken) > 0 {
+		offset, _ = strconv.Atoi(string(req.NextPageToken))
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultListPageSize
+	}
+	end := offset + pageSize
+	resp := &visibility.ListResponse{}
+	if end < len(matched) {
+		resp.NextPageToken = []byte(strconv.Itoa(end))
+	} else {
+		end = len(matched)
+	}
+	resp.Executions = matched[offset:end]
+	return resp, nil
+}
+
+func newListTestService(t *testing.T) *Service {
+	t.Helper()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	execution := func(id, workflowType string, status commonv1.ExecutionStatus, start, close int) *visibility.WorkflowExecutionInfo {
+		info := &visibility.WorkflowExecutionInfo{
+			Execution: &commonv1.WorkflowExecution{WorkflowId: id, RunId: "run-" + id},
+			Type:      &apiv1.WorkflowType{Name: workflowType},
+			Status:    status,
+			StartTime: at(start),
+		}
+		if close > 0 {
+			info.CloseTime = at(close)
+		}
+		return info
+	}
+
+	return newTestService(t, Config{
+		VisibilityStore: &memoryVisibilityStore{executions: []*visibility.WorkflowExecutionInfo{
+			execution("open-1", "order", commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING, 10, 0),
+			execution("open-2", "refund", commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING, 40, 0),
+			execution("done-1", "order", commonv1.ExecutionStatus_EXECUTION_STATUS_COMPLETED, 1, 20),
+			execution("done-2", "refund", commonv1.ExecutionStatus_EXECUTION_STATUS_FAILED, 2, 30),
+			execution("done-3", "order", commonv1.ExecutionStatus_EXECUTION_STATUS_COMPLETED, 3, 50),
+		}},
+	})
+}
+
+func listedIDs(resp *historyv1.ListWorkflowExecutionsResponse) []string {
+	ids := make([]string, 0, len(resp.Executions))
+	for _, e := range resp.Executions {
+		ids = append(ids, e.Execution.GetWorkflowId())
+	}
+	return ids
+}
+
+func TestListWorkflowExecutionsByStatus(t *testing.T) {
+	svc := newListTestService(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		req  *historyv1.ListWorkflowExecutionsRequest
+		want []string
+	}{
+		{"open by default", &historyv1.ListWorkflowExecutionsRequest{}, []string{"open-2", "open-1"}},
+		{"closed", &historyv1.ListWorkflowExecutionsRequest{
+			StatusFilter: historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_CLOSED,
+		}, []string{"done-3", "done-2", "done-1"}},
+		{"completed", &historyv1.ListWorkflowExecutionsRequest{
+			Status: commonv1.ExecutionStatus_EXECUTION_STATUS_COMPLETED,
+		}, []string{"done-3", "done-1"}},
+		{"all narrowed by query", &historyv1.ListWorkflowExecutionsRequest{
+			StatusFilter: historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_ALL,
+			Query:        "WorkflowType = 'refund'",
+		}, []string{"open-2", "done-2"}},
+		{"closed narrowed by query", &historyv1.ListWorkflowExecutionsRequest{
+			StatusFilter: historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_CLOSED,
+			Query:        "WorkflowId LIKE 'done-%' AND StartTime >= '2026-01-01T00:02:00Z'",
+		}, []string{"done-3", "done-2"}},
+		{"status query", &historyv1.ListWorkflowExecutionsRequest{
+			StatusFilter: historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_CLOSED,
+			Query:        "ExecutionStatus = 'Failed'",
+		}, []string{"done-2"}},
+	}
+	for _, c := range cases {
+		c.req.Namespace = "default"
+		resp, err := svc.ListWorkflowExecutions(ctx, c.req)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got := listedIDs(resp); fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestListWorkflowExecutionsRejectsUnsupportedQuery(t *testing.T) {
+	svc := newListTestService(t)
+
+	for _, query := range []string{
+		"Owner = 'alice'",
+		"StartTime LIKE '2026%'",
+		"ExecutionStatus = 'Sleeping'",
+		"WorkflowType = 'order' ORDER BY StartTime",
+	} {
+		_, err := svc.ListWorkflowExecutions(context.Background(), &historyv1.ListWorkflowExecutionsRequest{Namespace: "default", Query: query})
+		if !errors.Is(err, visibility.ErrInvalidQuery) {
+			t.Errorf("query %q: error = %v, want ErrInvalidQuery", query, err)
+		}
+	}
+}
+
+func TestListWorkflowExecutionsAllPaginates(t *testing.T) {
+	svc := newListTestService(t)
+	ctx := context.Background()
+
+	req := &historyv1.ListWorkflowExecutionsRequest{
+		Namespace:    "default",
+		PageSize:     2,
+		StatusFilter: historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_ALL,
+	}
+	var got []string
+	for page := 0; ; page++ {
+		if page > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		resp, err := svc.ListWorkflowExecutions(ctx, req)
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		got = append(got, listedIDs(resp)...)
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		req.NextPageToken = resp.NextPageToken
+	}
+
+	want := []string{"done-3", "open-2", "done-2", "done-1", "open-1"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package history
+
+import (
+	"time"
+
+	"github.com/linkflow/engine/internal/history/types"
+	"github.com/linkflow/engine/internal/observability/metrics"
+)
+
+// PrometheusMetrics implements Metrics on top of the shared metrics registry,
+// which is exported in the Prometheus text format.
+type PrometheusMetrics struct {
+	m *metrics.ServiceMetrics
+}
+
+// NewPrometheusMetrics creates a Metrics backed by registry (default
+// metrics.DefaultRegistry).
+func NewPrometheusMetrics(registry *metrics.Registry) *PrometheusMetrics {
+	return &PrometheusMetrics{m: metrics.NewServiceMetrics(registry, "history")}
+}
+
+func (p *PrometheusMetrics) RecordEventRecorded(eventType types.EventType) {
+	p.m.HistoryEventRecorded(eventType.String())
+}
+
+func (p *PrometheusMetrics) RecordEventRetrieved(count int) {
+	p.m.HistoryEventsRetrieved(count)
+}
+
+func (p *PrometheusMetrics) RecordServiceLatency(operation string, duration time.Duration) {
+	p.m.RequestCompleted(operation, "", "success", duration)
+}
+
+func (p *PrometheusMetrics) RecordStateConflictRetry() {
+	p.m.HistoryStateConflictRetried()
+}
+
+func (p *PrometheusMetrics) RecordEventsCompacted(count int) {
+	p.m.HistoryEventsCompacted(count)
+}
+
+func (p *PrometheusMetrics) RecordStateConflict(namespace string) {
+	p.m.HistoryStateConflict(namespace)
+}
+
+func (p *PrometheusMetrics) RecordStateConflictRetryDepth(namespace string, depth int) {
+	p.m.HistoryStateConflictRetryDepth(namespace, depth)
+}
+
+func (p *PrometheusMetrics) RecordSignalThrottled(namespace string) {
+	p.m.HistorySignalThrottled(namespace)
+}
@@ -0,0 +1,101 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"github.com/linkflow/engine/internal/observability/metrics"
+)
+
+// conflictingStateStore fails the first conflicts updates with a version
+// conflict.
+type conflictingStateStore struct {
+	*store.MemoryMutableStateStore
+	conflicts int
+	updates   int
+}
+
+func (s *conflictingStateStore) UpdateMutableState(ctx context.Context, key types.ExecutionKey, state *engine.MutableState, expectedVersion int64) error {
+	s.updates++
+	if s.conflicts > 0 {
+		s.conflicts--
+		return types.ErrOptimisticLock
+	}
+	return s.MemoryMutableStateStore.UpdateMutableState(ctx, key, state, expectedVersion)
+}
+
+func TestProcessEventsRecordsStateConflicts(t *testing.T) {
+	ctx := context.Background()
+	registry := metrics.NewRegistry()
+	stateStore := &conflictingStateStore{MemoryMutableStateStore: store.NewMemoryMutableStateStore()}
+	svc := newTestService(t, Config{
+		StateStore: stateStore,
+		Metrics:    NewPrometheusMetrics(registry),
+	})
+
+	key := types.ExecutionKey{NamespaceID: "hot", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	stateStore.conflicts = 2
+	event := &types.HistoryEvent{
+		EventType:  types.EventTypeMarkerRecorded,
+		Attributes: &types.MarkerRecordedAttributes{MarkerName: "m"},
+	}
+	if err := svc.processEvents(ctx, key, []*types.HistoryEvent{event}); err != nil {
+		t.Fatalf("process events: %v", err)
+	}
+
+	labels := metrics.Labels{"service": "history", "namespace": "hot"}
+	if got := registry.Counter("linkflow_history_state_conflicts_total", labels).Value(); got != 2 {
+		t.Fatalf("expected 2 conflicts, got %d", got)
+	}
+	if got := registry.Gauge("linkflow_history_state_conflict_retry_depth", labels).Value(); got != 0 {
+		t.Fatalf("expected retry depth to reset to 0, got %v", got)
+	}
+}
+
+func TestProcessEventsGivesUpAfterMaxStateConflictRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxRetries   int
+		wantAttempts int
+	}{
+		{name: "default", maxRetries: 0, wantAttempts: DefaultMaxStateConflictRetries + 1},
+		{name: "configured", maxRetries: 1, wantAttempts: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			stateStore := &conflictingStateStore{MemoryMutableStateStore: store.NewMemoryMutableStateStore()}
+			svc := newTestService(t, Config{
+				StateStore:              stateStore,
+				MaxStateConflictRetries: tt.maxRetries,
+			})
+
+			key := types.ExecutionKey{NamespaceID: "hot", WorkflowID: "wf-1", RunID: "run-1"}
+			// More conflicts than the service retries.
+			stateStore.conflicts = tt.wantAttempts + 1
+			event := &types.HistoryEvent{
+				EventType:  types.EventTypeMarkerRecorded,
+				Attributes: &types.MarkerRecordedAttributes{MarkerName: "m"},
+			}
+			if err := svc.processEvents(ctx, key, []*types.HistoryEvent{event}); !errors.Is(err, types.ErrOptimisticLock) {
+				t.Fatalf("process events error = %v, want ErrOptimisticLock", err)
+			}
+			if stateStore.updates != tt.wantAttempts {
+				t.Fatalf("updates = %d, want %d", stateStore.updates, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package ndc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// VersionHistoryItem is the last event written at a failover version.
+type VersionHistoryItem struct {
+	EventID int64 `json:"event_id"`
+	Version int64 `json:"version"`
+}
+
+// VersionHistory is a branch of an execution's history summarized as the
+// runs of events written at each failover version, oldest first. Two
+// clusters agree on a branch up to the lowest common ancestor of their
+// version histories.
+type VersionHistory struct {
+	BranchToken []byte               `json:"branch_token"`
+	Items       []VersionHistoryItem `json:"items"`
+}
+
+// BranchToken identifies a history branch. Events share a branch while they
+// were written at the same sequence of versions, so the branch ID covers
+// every version change but not how far the current version has got.
+type BranchToken struct {
+	TreeID   string `json:"tree_id"`
+	BranchID string `json:"branch_id"`
+}
+
+// BuildVersionHistory summarizes events, ordered by event ID, as the
+// version history of the run identified by treeID.
+func BuildVersionHistory(treeID string, events []*types.HistoryEvent) *VersionHistory {
+	history := &VersionHistory{Items: []VersionHistoryItem{}}
+	for _, event := range events {
+		last := len(history.Items) - 1
+		if last >= 0 && history.Items[last].Version == event.Version {
+			history.Items[last].EventID = event.EventID
+			continue
+		}
+		history.Items = append(history.Items, VersionHistoryItem{EventID: event.EventID, Version: event.Version})
+	}
+
+	token, _ := json.Marshal(BranchToken{TreeID: treeID, BranchID: branchID(history.Items)})
+	history.BranchToken = token
+	return history
+}
+
+// branchID hashes every item's version and, except for the current one,
+// where it ended.
+func branchID(items []VersionHistoryItem) string {
+	h := sha256.New()
+	var buf [8]byte
+	for i, item := range items {
+		binary.BigEndian.PutUint64(buf[:], uint64(item.Version))
+		h.Write(buf[:])
+		if i < len(items)-1 {
+			binary.BigEndian.PutUint64(buf[:], uint64(item.EventID))
+			h.Write(buf[:])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// LastItem returns the item of the current version.
+func (h *VersionHistory) LastItem() (VersionHistoryItem, bool) {
+	if h == nil || len(h.Items) == 0 {
+		return VersionHistoryItem{}, false
+	}
+	return h.Items[len(h.Items)-1], true
+}
+
+// FindLCAItem returns the last event two version histories agree on: the
+// end of their longest common prefix of versions, capped at the shorter run
+// of the first version they disagree on. It reports false when they share
+// no events.
+func FindLCAItem(a, b *VersionHistory) (VersionHistoryItem, bool) {
+	if a == nil || b == nil {
+		return VersionHistoryItem{}, false
+	}
+	var lca VersionHistoryItem
+	found := false
+	for i := 0; i < len(a.Items) && i < len(b.Items); i++ {
+		x, y := a.Items[i], b.Items[i]
+		if x.Version != y.Version {
+			break
+		}
+		lca = VersionHistoryItem{EventID: min(x.EventID, y.EventID), Version: x.Version}
+		found = true
+		if x.EventID != y.EventID {
+			break
+		}
+	}
+	return lca, found
+}
@@ -0,0 +1,116 @@
+package history
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// DefaultMaxParentCloseFanOut is the default limit on children one parent
+// close applies its ParentClosePolicy to.
+const DefaultMaxParentCloseFanOut = 1000
+
+// parentClosePolicyFromProto returns the policy a StartChildWorkflowExecution
+// command asks for. PARENT_CLOSE_POLICY_UNSPECIFIED is
+// ParentClosePolicyTerminate; an unknown value is too, and reports false.
+func parentClosePolicyFromProto(p historyv1.ParentClosePolicy) (types.ParentClosePolicy, bool) {
+	switch p {
+	case historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_UNSPECIFIED,
+		historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_TERMINATE:
+		return types.ParentClosePolicyTerminate, true
+	case historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_ABANDON:
+		return types.ParentClosePolicyAbandon, true
+	case historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_REQUEST_CANCEL:
+		return types.ParentClosePolicyRequestCancel, true
+	}
+	return types.ParentClosePolicyTerminate, false
+}
+
+// applyParentClosePolicies applies each pending child's ParentClosePolicy
+// after the parent identified by key closed. Terminating a child closes it in
+// turn, so grandchildren are handled by the child's own close; the recursion
+// is bounded by the child depth limit.
+func (s *Service) applyParentClosePolicies(ctx context.Context, key types.ExecutionKey, state *engine.MutableState) {
+	if len(state.PendingChildren) == 0 {
+		return
+	}
+	if state.ExecutionInfo != nil && state.ExecutionInfo.ChildDepth > s.maxChildDepth {
+		s.logger.Warn("parent close policy skipped: max nesting depth exceeded",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("run_id", key.RunID),
+			slog.Int("depth", int(state.ExecutionInfo.ChildDepth)),
+		)
+		return
+	}
+
+	children := make([]*types.ChildExecutionInfo, 0, len(state.PendingChildren))
+	for _, child := range state.PendingChildren {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].StartedEventID < children[j].StartedEventID
+	})
+	if len(children) > s.maxCloseFanOut {
+		s.logger.Warn("parent close policy fan-out limit reached, abandoning remaining children",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("run_id", key.RunID),
+			slog.Int("children", len(children)),
+			slog.Int("max_fan_out", s.maxCloseFanOut),
+		)
+		children = children[:s.maxCloseFanOut]
+	}
+
+	for _, child := range children {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.applyParentClosePolicy(ctx, key, child, "parent workflow closed"); err != nil {
+			s.logger.Warn("failed to apply parent close policy",
+				slog.String("parent_workflow_id", key.WorkflowID),
+				slog.String("child_workflow_id", child.WorkflowID),
+				slog.String("child_run_id", child.RunID),
+				slog.String("policy", string(child.ParentClosePolicy)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// applyParentClosePolicy terminates or requests cancellation of the child of
+// the execution identified by key, as its ParentClosePolicy says, giving
+// reason as the termination reason.
+func (s *Service) applyParentClosePolicy(ctx context.Context, key types.ExecutionKey, child *types.ChildExecutionInfo, reason string) error {
+	childKey := types.ExecutionKey{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  child.WorkflowID,
+		RunID:       child.RunID,
+	}
+
+	switch child.ParentClosePolicy {
+	case types.ParentClosePolicyAbandon:
+		return nil
+	case types.ParentClosePolicyRequestCancel:
+		return s.RecordEvent(ctx, childKey, &types.HistoryEvent{
+			EventType: types.EventTypeSignalReceived,
+			Timestamp: time.Now(),
+			Attributes: &types.SignalReceivedAttributes{
+				SignalName: types.CancelRequestedSignalName,
+				Identity:   "parent-close-policy",
+			},
+		})
+	default:
+		return s.processEvents(ctx, childKey, []*types.HistoryEvent{{
+			EventType: types.EventTypeExecutionTerminated,
+			Timestamp: time.Now(),
+			Attributes: &types.ExecutionTerminatedAttributes{
+				Reason:   reason,
+				Identity: "parent-close-policy",
+			},
+		}})
+	}
+}
@@ -0,0 +1,182 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// seedRunningExecution stores a running execution, linked to parentWorkflowID
+// when it is a child.
+func seedRunningExecution(t *testing.T, stateStore *store.MemoryMutableStateStore, key types.ExecutionKey, parentWorkflowID string) {
+	t.Helper()
+	info := &types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	}
+	if parentWorkflowID != "" {
+		info.ParentWorkflowID = parentWorkflowID
+		info.ParentRunID = "run-1"
+		info.ParentNodeID = key.WorkflowID
+		info.ChildDepth = 1
+	}
+	if err := stateStore.UpdateMutableState(context.Background(), key, engine.NewMutableState(info), 0); err != nil {
+		t.Fatalf("seed %s: %v", key.WorkflowID, err)
+	}
+}
+
+func TestParentClosePolicyAppliesToChildren(t *testing.T) {
+	tests := []struct {
+		policy     types.ParentClosePolicy
+		wantStatus types.ExecutionStatus
+		wantSignal bool
+	}{
+		{policy: "", wantStatus: types.ExecutionStatusTerminated},
+		{policy: types.ParentClosePolicyTerminate, wantStatus: types.ExecutionStatusTerminated},
+		{policy: types.ParentClosePolicyAbandon, wantStatus: types.ExecutionStatusRunning},
+		{policy: types.ParentClosePolicyRequestCancel, wantStatus: types.ExecutionStatusRunning, wantSignal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			ctx := context.Background()
+			eventStore := store.NewMemoryEventStore()
+			stateStore := store.NewMemoryMutableStateStore()
+			svc := newTestService(t, Config{
+				EventStore: eventStore,
+				StateStore: stateStore,
+			})
+
+			parent := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+			seedRunningExecution(t, stateStore, parent, "")
+			children := []types.ExecutionKey{
+				{NamespaceID: "default", WorkflowID: "charge", RunID: "charge-run"},
+				{NamespaceID: "default", WorkflowID: "ship", RunID: "ship-run"},
+			}
+			for _, child := range children {
+				seedRunningExecution(t, stateStore, child, parent.WorkflowID)
+				err := svc.processEvents(ctx, parent, []*types.HistoryEvent{{
+					EventType: types.EventTypeChildWorkflowStarted,
+					Timestamp: time.Now(),
+					Attributes: &types.ChildWorkflowStartedAttributes{
+						NodeID:            child.WorkflowID,
+						WorkflowID:        child.WorkflowID,
+						RunID:             child.RunID,
+						WorkflowType:      child.WorkflowID,
+						ParentClosePolicy: tt.policy,
+					},
+				}})
+				if err != nil {
+					t.Fatalf("record child %s: %v", child.WorkflowID, err)
+				}
+			}
+
+			err := svc.processEvents(ctx, parent, []*types.HistoryEvent{{
+				EventType:  types.EventTypeExecutionTerminated,
+				Timestamp:  time.Now(),
+				Attributes: &types.ExecutionTerminatedAttributes{Reason: "operator", Identity: "test"},
+			}})
+			if err != nil {
+				t.Fatalf("terminate parent: %v", err)
+			}
+
+			for _, child := range children {
+				state, err := stateStore.GetMutableState(ctx, child)
+				if err != nil {
+					t.Fatalf("child %s state: %v", child.WorkflowID, err)
+				}
+				if state.ExecutionInfo.Status != tt.wantStatus {
+					t.Fatalf("child %s status = %v, want %v", child.WorkflowID, state.ExecutionInfo.Status, tt.wantStatus)
+				}
+				signals, err := eventStore.GetEventCountByType(ctx, child, []types.EventType{types.EventTypeSignalReceived})
+				if err != nil {
+					t.Fatalf("event count: %v", err)
+				}
+				if (signals == 1) != tt.wantSignal {
+					t.Fatalf("child %s received %d signals, want signal %v", child.WorkflowID, signals, tt.wantSignal)
+				}
+			}
+
+			// Terminated children do not append events to the closed parent.
+			parentState, err := stateStore.GetMutableState(ctx, parent)
+			if err != nil {
+				t.Fatalf("parent state: %v", err)
+			}
+			if parentState.NextEventID != 4 {
+				t.Fatalf("parent next event ID = %d, want 4", parentState.NextEventID)
+			}
+		})
+	}
+}
+
+func TestParentClosePolicyFromProto(t *testing.T) {
+	tests := []struct {
+		policy historyv1.ParentClosePolicy
+		want   types.ParentClosePolicy
+		wantOK bool
+	}{
+		{historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_UNSPECIFIED, types.ParentClosePolicyTerminate, true},
+		{historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_TERMINATE, types.ParentClosePolicyTerminate, true},
+		{historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_ABANDON, types.ParentClosePolicyAbandon, true},
+		{historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_REQUEST_CANCEL, types.ParentClosePolicyRequestCancel, true},
+		{historyv1.ParentClosePolicy(42), types.ParentClosePolicyTerminate, false},
+	}
+	for _, tt := range tests {
+		got, ok := parentClosePolicyFromProto(tt.policy)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parentClosePolicyFromProto(%v) = %q, %v; want %q, %v", tt.policy, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParentClosePolicyFanOutLimit(t *testing.T) {
+	ctx := context.Background()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		StateStore:           stateStore,
+		MaxParentCloseFanOut: 1,
+	})
+
+	parent := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	seedRunningExecution(t, stateStore, parent, "")
+	var events []*types.HistoryEvent
+	children := []types.ExecutionKey{
+		{NamespaceID: "default", WorkflowID: "charge", RunID: "charge-run"},
+		{NamespaceID: "default", WorkflowID: "ship", RunID: "ship-run"},
+	}
+	for _, child := range children {
+		seedRunningExecution(t, stateStore, child, parent.WorkflowID)
+		events = append(events, &types.HistoryEvent{
+			EventType:  types.EventTypeChildWorkflowStarted,
+			Timestamp:  time.Now(),
+			Attributes: &types.ChildWorkflowStartedAttributes{WorkflowID: child.WorkflowID, RunID: child.RunID},
+		})
+	}
+	events = append(events, &types.HistoryEvent{
+		EventType:  types.EventTypeExecutionCompleted,
+		Timestamp:  time.Now(),
+		Attributes: &types.ExecutionCompletedAttributes{},
+	})
+	if err := svc.processEvents(ctx, parent, events); err != nil {
+		t.Fatalf("record parent: %v", err)
+	}
+
+	// Only the first child started is terminated; the other is abandoned.
+	want := []types.ExecutionStatus{types.ExecutionStatusTerminated, types.ExecutionStatusRunning}
+	for i, child := range children {
+		state, err := stateStore.GetMutableState(ctx, child)
+		if err != nil {
+			t.Fatalf("child %s state: %v", child.WorkflowID, err)
+		}
+		if state.ExecutionInfo.Status != want[i] {
+			t.Fatalf("child %s status = %v, want %v", child.WorkflowID, state.ExecutionInfo.Status, want[i])
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// DefaultHistoryPollTimeout is how long PollHistory waits for new events when
+// no timeout is given.
+const DefaultHistoryPollTimeout = 30 * time.Second
+
+// DefaultMaxHistoryPollWaiters is the default limit on concurrent PollHistory
+// calls waiting on one execution.
+const DefaultMaxHistoryPollWaiters = 64
+
+// historyPollPageSize bounds the events one PollHistory call returns.
+const historyPollPageSize = 1000
+
+// ErrTooManyHistoryPollers is returned by PollHistory when an execution
+// already has the maximum number of waiting pollers.
+var ErrTooManyHistoryPollers = errors.New("too many history pollers waiting on execution")
+
+// historyNotifier wakes PollHistory callers waiting on an execution when new
+// events are recorded for it. Each execution with waiters has one channel,
+// closed and replaced on every notification.
+type historyNotifier struct {
+	maxWaiters int
+
+	mu      sync.Mutex
+	waiters map[types.ExecutionKey]*historyWaiters
+}
+
+type historyWaiters struct {
+	ch    chan struct{}
+	count int
+}
+
+func newHistoryNotifier(maxWaiters int) *historyNotifier {
+	return &historyNotifier{
+		maxWaiters: maxWaiters,
+		waiters:    make(map[types.ExecutionKey]*historyWaiters),
+	}
+}
+
+// wait registers a waiter on key. The returned channel is closed on the next
+// notification; release must be called once the waiter is done.
+func (n *historyNotifier) wait(key types.ExecutionKey) (<-chan struct{}, func(), error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	w, ok := n.waiters[key]
+	if !ok {
+		w = &historyWaiters{ch: make(chan struct{})}
+		n.waiters[key] = w
+	}
+	if w.count >= n.maxWaiters {
+		return nil, nil, ErrTooManyHistoryPollers
+	}
+	w.count++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			w.count--
+			if w.count == 0 && n.waiters[key] == w {
+				delete(n.waiters, key)
+			}
+		})
+	}
+	return w.ch, release, nil
+}
+
+// notify wakes the waiters on key. When the execution closed no more events
+// will follow, so its entry is dropped rather than rearmed.
+func (n *historyNotifier) notify(key types.ExecutionKey, closed bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	w, ok := n.waiters[key]
+	if !ok {
+		return
+	}
+	close(w.ch)
+	if closed {
+		delete(n.waiters, key)
+		return
+	}
+	w.ch = make(chan struct{})
+}
+
+// PollHistory returns the events of key after afterEventID, up to a page. If
+// there are none yet it blocks until new events are recorded, longPollTimeout
+// (default DefaultHistoryPollTimeout) elapses or ctx ends, returning no events
+// on timeout. It also returns no events without waiting once the execution
+// has closed and all its events were read.
+func (s *Service) PollHistory(ctx context.Context, key types.ExecutionKey, afterEventID int64, longPollTimeout time.Duration) ([]*types.HistoryEvent, error) {
+	if longPollTimeout <= 0 {
+		longPollTimeout = DefaultHistoryPollTimeout
+	}
+	timer := time.NewTimer(longPollTimeout)
+	defer timer.Stop()
+
+	for {
+		// Register before reading so events recorded in between still wake
+		// the poller.
+		notified, release, err := s.historyPollers.wait(key)
+		if err != nil {
+			return nil, err
+		}
+
+		events, closed, err := s.eventsAfter(ctx, key, afterEventID)
+		if err != nil || len(events) > 0 || closed {
+			release()
+			return events, err
+		}
+
+		select {
+		case <-notified:
+			release()
+		case <-timer.C:
+			release()
+			return nil, nil
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// eventsAfter returns up to a page of the events of key after afterEventID,
+// and whether the execution has closed.
+func (s *Service) eventsAfter(ctx context.Context, key types.ExecutionKey, afterEventID int64) ([]*types.HistoryEvent, bool, error) {
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	closed := executionClosed(state)
+
+	lastEventID := state.NextEventID - 1
+	if lastEventID <= afterEventID {
+		return nil, closed, nil
+	}
+	if lastEventID-afterEventID > historyPollPageSize {
+		lastEventID = afterEventID + historyPollPageSize
+	}
+
+	events, err := s.eventStore.GetEvents(ctx, key, afterEventID+1, lastEventID)
+	if err != nil {
+		return nil, false, err
+	}
+	s.metrics.RecordEventRetrieved(len(events))
+	return events, closed, nil
+}
+
+func executionClosed(state *engine.MutableState) bool {
+	return state.ExecutionInfo != nil && state.ExecutionInfo.Status != types.ExecutionStatusRunning
+}
@@ -0,0 +1,93 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestPollHistoryWaitsForNewEvents(t *testing.T) {
+	ctx := context.Background()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		StateStore:            stateStore,
+		MaxHistoryPollWaiters: 1,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	// Nothing new: the poll times out empty-handed.
+	events, err := svc.PollHistory(ctx, key, 0, 10*time.Millisecond)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("idle poll = %d events, %v; want none", len(events), err)
+	}
+
+	type pollResult struct {
+		events []*types.HistoryEvent
+		err    error
+	}
+	done := make(chan pollResult, 1)
+	go func() {
+		events, err := svc.PollHistory(ctx, key, 0, 5*time.Second)
+		done <- pollResult{events, err}
+	}()
+	waitForPollers(t, svc, key, 1)
+
+	if _, err := svc.PollHistory(ctx, key, 0, time.Second); !errors.Is(err, ErrTooManyHistoryPollers) {
+		t.Fatalf("second poller error = %v, want ErrTooManyHistoryPollers", err)
+	}
+
+	err = svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType:  types.EventTypeSignalReceived,
+		Timestamp:  time.Now(),
+		Attributes: &types.SignalReceivedAttributes{SignalName: "go"},
+	})
+	if err != nil {
+		t.Fatalf("record signal: %v", err)
+	}
+
+	select {
+	case result := <-done:
+		if result.err != nil || len(result.events) != 1 || result.events[0].EventType != types.EventTypeSignalReceived {
+			t.Fatalf("woken poll = %+v, %v; want the signal event", result.events, result.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("poll was not woken by the new event")
+	}
+	waitForPollers(t, svc, key, 0)
+}
+
+// waitForPollers waits until n pollers are registered on key.
+func waitForPollers(t *testing.T, svc *Service, key types.ExecutionKey, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		svc.historyPollers.mu.Lock()
+		count := 0
+		if w, ok := svc.historyPollers.waiters[key]; ok {
+			count = w.count
+		}
+		svc.historyPollers.mu.Unlock()
+		if count == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d pollers registered, want %d", count, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
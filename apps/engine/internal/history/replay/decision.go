@@ -0,0 +1,73 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+)
+
+// DecisionMode is the mode of a DecisionRequest. A decider in replay mode
+// decides from the history in the request instead of fetching it, and only
+// returns its commands; nothing is sent to history.
+const DecisionMode = "replay"
+
+// DecisionRequest asks a decider for the commands it would send for one
+// recorded workflow task. History holds the events that preceded the task,
+// encoded as a protojson History.
+type DecisionRequest struct {
+	Mode            string          `json:"mode"`
+	Namespace       string          `json:"namespace"`
+	WorkflowID      string          `json:"workflow_id"`
+	RunID           string          `json:"run_id"`
+	DecisionEventID int64           `json:"decision_event_id"`
+	History         json.RawMessage `json:"history"`
+}
+
+// DecisionResponse holds the commands a decider returned, each encoded as a
+// protojson Command.
+type DecisionResponse struct {
+	Commands []json.RawMessage `json:"commands"`
+}
+
+// EncodeHistory encodes events for DecisionRequest.History.
+func EncodeHistory(events []*historyv1.HistoryEvent) (json.RawMessage, error) {
+	return protojson.Marshal(&historyv1.History{Events: events})
+}
+
+// Events decodes the request's history.
+func (r *DecisionRequest) Events() ([]*historyv1.HistoryEvent, error) {
+	var history historyv1.History
+	if err := protojson.Unmarshal(r.History, &history); err != nil {
+		return nil, fmt.Errorf("invalid history: %w", err)
+	}
+	return history.GetEvents(), nil
+}
+
+// NewDecisionResponse encodes a decider's commands.
+func NewDecisionResponse(commands []*historyv1.Command) (*DecisionResponse, error) {
+	resp := &DecisionResponse{Commands: make([]json.RawMessage, len(commands))}
+	for i, cmd := range commands {
+		data, err := protojson.Marshal(cmd)
+		if err != nil {
+			return nil, fmt.Errorf("command %d: %w", i, err)
+		}
+		resp.Commands[i] = data
+	}
+	return resp, nil
+}
+
+// Decode decodes the response's commands.
+func (r *DecisionResponse) Decode() ([]*historyv1.Command, error) {
+	commands := make([]*historyv1.Command, len(r.Commands))
+	for i, data := range r.Commands {
+		cmd := &historyv1.Command{}
+		if err := protojson.Unmarshal(data, cmd); err != nil {
+			return nil, fmt.Errorf("invalid command %d: %w", i, err)
+		}
+		commands[i] = cmd
+	}
+	return commands, nil
+}
@@ -0,0 +1,271 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/replay"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// ErrHistoryIncomplete is returned when replaying an execution whose history
+// no longer starts at its first event, e.g. after compaction.
+var ErrHistoryIncomplete = errors.New("history does not start with the execution's first event")
+
+// replayDecisionTimeout bounds each call to the decider.
+const replayDecisionTimeout = 30 * time.Second
+
+// ReplayValidation is the outcome of replaying a closed execution's history
+// through a decider.
+type ReplayValidation struct {
+	NamespaceID string `json:"namespace_id"`
+	WorkflowID  string `json:"workflow_id"`
+	RunID       string `json:"run_id"`
+	// DecisionsReplayed counts the recorded workflow tasks sent to the
+	// decider, including the one that diverged.
+	DecisionsReplayed int `json:"decisions_replayed"`
+	// Divergence is the first decision whose commands differ from history,
+	// or nil when the decider reproduced every decision.
+	Divergence *ReplayDivergence `json:"divergence,omitempty"`
+}
+
+// Deterministic reports whether the decider reproduced every decision.
+func (v *ReplayValidation) Deterministic() bool {
+	return v.Divergence == nil
+}
+
+// ReplayDivergence is the first command on which a decider and history
+// disagree. Recorded is empty when the decider sent a command history has no
+// record of, and Replayed is empty when it left out a recorded one.
+type ReplayDivergence struct {
+	// DecisionEventID is the WorkflowTaskCompleted event of the decision.
+	DecisionEventID int64  `json:"decision_event_id"`
+	CommandIndex    int    `json:"command_index"`
+	Recorded        string `json:"recorded,omitempty"`
+	Replayed        string `json:"replayed,omitempty"`
+}
+
+// ReplayHistory checks that a decider still makes the decisions recorded in
+// a closed execution's history, so workflow changes can be tested against
+// past runs before they ship. Each recorded workflow task is sent to the
+// decider at deciderEndpoint in replay mode with the events that preceded
+// it, and the commands it returns are compared with the events the task
+// recorded. Replay stops at the first divergence. Nothing is persisted.
+func (s *Service) ReplayHistory(ctx context.Context, key types.ExecutionKey, deciderEndpoint string) (*ReplayValidation, error) {
+	if deciderEndpoint == "" {
+		return nil, errors.New("decider endpoint is required")
+	}
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if info := state.ExecutionInfo; info != nil {
+		if info.Status == types.ExecutionStatusRunning {
+			return nil, ErrExecutionRunning
+		}
+		if key.RunID == "" {
+			key.RunID = info.RunID
+		}
+	}
+
+	events, err := s.eventStore.GetEvents(ctx, key, 1, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 || events[0].EventType != types.EventTypeExecutionStarted {
+		return nil, ErrHistoryIncomplete
+	}
+	protoEvents := make([]*historyv1.HistoryEvent, len(events))
+	for i, event := range events {
+		protoEvents[i] = internalEventToProto(event)
+	}
+
+	result := &ReplayValidation{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+	}
+	client := &http.Client{Timeout: replayDecisionTimeout}
+	for i, event := range events {
+		if event.EventType != types.EventTypeWorkflowTaskCompleted {
+			continue
+		}
+		history, err := replay.EncodeHistory(protoEvents[:i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode history before event %d: %w", event.EventID, err)
+		}
+		commands, err := requestReplayDecision(ctx, client, deciderEndpoint, &replay.DecisionRequest{
+			Mode:            replay.DecisionMode,
+			Namespace:       key.NamespaceID,
+			WorkflowID:      key.WorkflowID,
+			RunID:           key.RunID,
+			DecisionEventID: event.EventID,
+			History:         history,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("decision at event %d: %w", event.EventID, err)
+		}
+		result.DecisionsReplayed++
+
+		recorded := recordedDecision(events, i)
+		replayed := make([]string, 0, len(commands))
+		for _, cmd := range commands {
+			if description := describeCommand(cmd); description != "" {
+				replayed = append(replayed, description)
+			}
+		}
+		if divergence := compareDecision(recorded, replayed); divergence != nil {
+			divergence.DecisionEventID = event.EventID
+			result.Divergence = divergence
+			break
+		}
+	}
+
+	s.logger.Info("replayed execution history",
+		"workflow_id", key.WorkflowID,
+		"run_id", key.RunID,
+		"decisions", result.DecisionsReplayed,
+		"deterministic", result.Deterministic(),
+	)
+	return result, nil
+}
+
+func requestReplayDecision(ctx context.Context, client *http.Client, endpoint string, decision *replay.DecisionRequest) ([]*historyv1.Command, error) {
+	body, err := json.Marshal(decision)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("decider returned %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+
+	var decided replay.DecisionResponse
+	if err := json.Unmarshal(data, &decided); err != nil {
+		return nil, fmt.Errorf("invalid decider response: %w", err)
+	}
+	return decided.Decode()
+}
+
+// recordedDecision describes the events recorded for the commands of the
+// workflow task completed at events[completed]. They are appended right after
+// its WorkflowTaskCompleted event, so they end at the first event no command
+// produces.
+func recordedDecision(events []*types.HistoryEvent, completed int) []string {
+	var recorded []string
+	startedChildren := make(map[string]bool)
+	for _, event := range events[:completed] {
+		if attrs, ok := event.Attributes.(*types.ChildWorkflowStartedAttributes); ok {
+			startedChildren[attrs.NodeID] = true
+		}
+	}
+	for _, event := range events[completed+1:] {
+		var description string
+		switch attrs := event.Attributes.(type) {
+		case *types.ChildWorkflowStartedAttributes:
+			startedChildren[attrs.NodeID] = true
+			description = childWorkflowDescription(attrs.NodeID)
+		case *types.ChildWorkflowCompletedAttributes:
+			// A child rejected for its depth is recorded as closed at once;
+			// any other close comes from a child started earlier.
+			if !startedChildren[attrs.NodeID] {
+				description = childWorkflowDescription(attrs.NodeID)
+			}
+		case *types.MarkerRecordedAttributes:
+			if attrs.MarkerName == types.VersionMarkerName {
+				version, _ := strconv.ParseInt(string(attrs.Details[types.VersionMarkerVersionKey]), 10, 32)
+				description = versionMarkerDescription(string(attrs.Details[types.VersionMarkerChangeIDKey]), int32(version))
+			}
+		default:
+			switch event.EventType {
+			case types.EventTypeNodeScheduled:
+				description = scheduleNodeDescription(nodeScheduledInfo(event))
+			case types.EventTypeExecutionCompleted:
+				description = "CompleteWorkflowExecution"
+			case types.EventTypeExecutionFailed:
+				description = "FailWorkflowExecution"
+			}
+		}
+		if description == "" {
+			break
+		}
+		recorded = append(recorded, description)
+	}
+	return recorded
+}
+
+// describeCommand describes a command the way recordedDecision describes the
+// event it records, or returns "" for a command history records nothing for.
+func describeCommand(cmd *historyv1.Command) string {
+	switch cmd.GetCommandType() {
+	case historyv1.CommandType_COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK:
+		attrs := cmd.GetScheduleActivityTaskAttributes()
+		return scheduleNodeDescription(attrs.GetNodeId(), attrs.GetNodeType())
+	case historyv1.CommandType_COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION:
+		return "CompleteWorkflowExecution"
+	case historyv1.CommandType_COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION:
+		return "FailWorkflowExecution"
+	case historyv1.CommandType_COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:
+		if attrs := cmd.GetStartChildWorkflowExecutionAttributes(); attrs != nil {
+			return childWorkflowDescription(attrs.GetNodeId())
+		}
+	case historyv1.CommandType_COMMAND_TYPE_RECORD_VERSION_MARKER:
+		if attrs := cmd.GetRecordVersionMarkerAttributes(); attrs.GetChangeId() != "" {
+			return versionMarkerDescription(attrs.GetChangeId(), attrs.GetVersion())
+		}
+	}
+	return ""
+}
+
+func scheduleNodeDescription(nodeID, nodeType string) string {
+	return fmt.Sprintf("ScheduleNode(node=%s, type=%s)", nodeID, nodeType)
+}
+
+func childWorkflowDescription(nodeID string) string {
+	return fmt.Sprintf("StartChildWorkflow(node=%s)", nodeID)
+}
+
+func versionMarkerDescription(changeID string, version int32) string {
+	return fmt.Sprintf("RecordVersionMarker(change=%s, version=%d)", changeID, version)
+}
+
+// compareDecision returns the first command on which recorded and replayed
+// differ, or nil if they match.
+func compareDecision(recorded, replayed []string) *ReplayDivergence {
+	for i := 0; i < len(recorded) || i < len(replayed); i++ {
+		var want, got string
+		if i < len(recorded) {
+			want = recorded[i]
+		}
+		if i < len(replayed) {
+			got = replayed[i]
+		}
+		if want != got {
+			return &ReplayDivergence{CommandIndex: i, Recorded: want, Replayed: got}
+		}
+	}
+	return nil
+}
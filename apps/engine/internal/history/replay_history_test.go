@@ -0,0 +1,103 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/replay"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestReplayHistoryDetectsDivergence(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newChildActivityTestService(t)
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+
+	record := func(eventType types.EventType, attrs any) {
+		t.Helper()
+		if err := svc.RecordEvent(ctx, key, &types.HistoryEvent{EventType: eventType, Timestamp: time.Now(), Attributes: attrs}); err != nil {
+			t.Fatalf("record %s: %v", eventType, err)
+		}
+	}
+	record(types.EventTypeExecutionStarted, &types.ExecutionStartedAttributes{WorkflowType: "order", TaskQueue: "default"})
+	record(types.EventTypeWorkflowTaskCompleted, &types.WorkflowTaskCompletedAttributes{ScheduledEventID: 1})
+	record(types.EventTypeMarkerRecorded, &types.MarkerRecordedAttributes{
+		MarkerName: types.VersionMarkerName,
+		Details:    map[string][]byte{types.VersionMarkerChangeIDKey: []byte("fetch-v2"), types.VersionMarkerVersionKey: []byte("2")},
+	})
+	record(types.EventTypeNodeScheduled, &historyv1.HistoryEvent_NodeScheduledAttributes{
+		NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{NodeId: "fetch", NodeType: "http", TaskQueue: &apiv1.TaskQueue{Name: "default"}},
+	})
+	record(types.EventTypeNodeCompleted, &types.NodeCompletedAttributes{ScheduledEventID: 4})
+	record(types.EventTypeWorkflowTaskCompleted, &types.WorkflowTaskCompletedAttributes{ScheduledEventID: 5})
+
+	if _, err := svc.ReplayHistory(ctx, key, "http://decider.invalid"); !errors.Is(err, ErrExecutionRunning) {
+		t.Fatalf("replay of running execution: err = %v, want ErrExecutionRunning", err)
+	}
+	record(types.EventTypeExecutionCompleted, &types.ExecutionCompletedAttributes{})
+
+	// The decider schedules notify instead of completing once fetch is done,
+	// unless it is told to keep the recorded behaviour.
+	changed := false
+	decider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req replay.DecisionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Mode != replay.DecisionMode {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		events, err := req.Events()
+		if err != nil || int64(len(events)) != req.DecisionEventID-1 {
+			http.Error(w, "history does not end before the decision", http.StatusBadRequest)
+			return
+		}
+
+		var commands []*historyv1.Command
+		if len(events) == 1 {
+			commands = []*historyv1.Command{
+				{CommandType: historyv1.CommandType_COMMAND_TYPE_RECORD_VERSION_MARKER, Attributes: &historyv1.Command_RecordVersionMarkerAttributes{
+					RecordVersionMarkerAttributes: &historyv1.RecordVersionMarkerCommandAttributes{ChangeId: "fetch-v2", Version: 2},
+				}},
+				{CommandType: historyv1.CommandType_COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK, Attributes: &historyv1.Command_ScheduleActivityTaskAttributes{
+					ScheduleActivityTaskAttributes: &historyv1.ScheduleActivityTaskCommandAttributes{NodeId: "fetch", NodeType: "http"},
+				}},
+			}
+		} else if changed {
+			commands = []*historyv1.Command{{CommandType: historyv1.CommandType_COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK, Attributes: &historyv1.Command_ScheduleActivityTaskAttributes{
+				ScheduleActivityTaskAttributes: &historyv1.ScheduleActivityTaskCommandAttributes{NodeId: "notify", NodeType: "email"},
+			}}}
+		} else {
+			commands = []*historyv1.Command{{CommandType: historyv1.CommandType_COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION}}
+		}
+		resp, _ := replay.NewDecisionResponse(commands)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer decider.Close()
+
+	result, err := svc.ReplayHistory(ctx, key, decider.URL)
+	if err != nil || !result.Deterministic() || result.DecisionsReplayed != 2 {
+		t.Fatalf("replay with unchanged decider = %+v, %v", result, err)
+	}
+
+	changed = true
+	result, err = svc.ReplayHistory(ctx, key, decider.URL)
+	if err != nil || result.Deterministic() {
+		t.Fatalf("replay with changed decider = %+v, %v", result, err)
+	}
+	want := ReplayDivergence{DecisionEventID: 6, CommandIndex: 0, Recorded: "CompleteWorkflowExecution", Replayed: "ScheduleNode(node=notify, type=email)"}
+	if *result.Divergence != want {
+		t.Fatalf("divergence = %+v, want %+v", *result.Divergence, want)
+	}
+
+	// Replay records nothing.
+	events, err := svc.GetHistory(ctx, key, 1, 100)
+	if err != nil || len(events) != 7 {
+		t.Fatalf("history after replay has %d events, %v", len(events), err)
+	}
+}
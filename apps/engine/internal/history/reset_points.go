@@ -0,0 +1,74 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// ErrInvalidResetPoint is returned when a reset targets an event that is not
+// a completed workflow task.
+var ErrInvalidResetPoint = errors.New("event is not a valid reset point")
+
+// resetPointScanPageSize is the number of events read per store call.
+const resetPointScanPageSize = 1000
+
+// ResetPoint is a completed workflow task an execution can be reset to.
+type ResetPoint struct {
+	EventID   int64
+	Label     string
+	Timestamp time.Time
+}
+
+// ListResetPoints returns the WorkflowTaskCompleted events of an execution in
+// history order. Resetting to one of them replays the run up to the end of
+// that workflow task.
+func (s *Service) ListResetPoints(ctx context.Context, key types.ExecutionKey) ([]ResetPoint, error) {
+	if _, err := s.stateStore.GetMutableState(ctx, key); err != nil {
+		return nil, err
+	}
+
+	points := []ResetPoint{}
+
+	firstEventID := int64(1)
+	for {
+		events, err := s.eventStore.GetEventsByType(ctx, key,
+			[]types.EventType{types.EventTypeWorkflowTaskCompleted}, firstEventID, resetPointScanPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workflow task events: %w", err)
+		}
+
+		for _, event := range events {
+			points = append(points, ResetPoint{
+				EventID:   event.EventID,
+				Label:     fmt.Sprintf("after workflow task #%d", len(points)+1),
+				Timestamp: event.Timestamp,
+			})
+		}
+
+		if len(events) < resetPointScanPageSize {
+			break
+		}
+		firstEventID = events[len(events)-1].EventID + 1
+	}
+
+	return points, nil
+}
+
+// validateResetPoint checks that resetEventID is one of the execution's reset
+// points.
+func (s *Service) validateResetPoint(ctx context.Context, key types.ExecutionKey, resetEventID int64) error {
+	points, err := s.ListResetPoints(ctx, key)
+	if err != nil {
+		return err
+	}
+	for _, point := range points {
+		if point.EventID == resetEventID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrInvalidResetPoint, resetEventID)
+}
@@ -15,12 +15,17 @@ import (
 	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
 	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
 	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+	"github.com/linkflow/engine/internal/controlplane"
 	"github.com/linkflow/engine/internal/history/archival"
+	"github.com/linkflow/engine/internal/history/audit"
 	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/events"
 	"github.com/linkflow/engine/internal/history/ndc"
 	"github.com/linkflow/engine/internal/history/shard"
 	"github.com/linkflow/engine/internal/history/types"
 	"github.com/linkflow/engine/internal/history/visibility"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -28,13 +33,39 @@ var (
 	ErrServiceNotRunning     = errors.New("history service is not running")
 	ErrServiceAlreadyRunning = errors.New("history service is already running")
 	ErrEventNotFound         = errors.New("event not found")
+	ErrMatchingBackpressure  = errors.New("matching task queue is over its hard limit")
+
+	// errDuplicateRequest is returned by applyAndPersist when the request ID
+	// was already applied to the execution.
+	errDuplicateRequest = errors.New("request already applied")
+)
+
+// Tasks refused by Matching backpressure are retried with exponential backoff.
+const (
+	defaultBackpressureRetryInterval = 200 * time.Millisecond
+	maxBackpressureRetryInterval     = 10 * time.Second
+	maxBackpressureRetries           = 12
 )
 
+// DefaultMaxChildWorkflowDepth is the default limit on nested child workflows.
+const DefaultMaxChildWorkflowDepth = 5
+
+// DefaultMaxStateConflictRetries is the default number of times processEvents
+// retries after a mutable state version conflict.
+const DefaultMaxStateConflictRetries = 3
+
+// DefaultMaxAppliedRequests is the default number of client request IDs an
+// execution remembers for deduplication.
+const DefaultMaxAppliedRequests = 1000
+
 // EventStore defines the interface for storing and retrieving history events.
 type EventStore interface {
 	AppendEvents(ctx context.Context, key types.ExecutionKey, events []*types.HistoryEvent, expectedVersion int64) error
 	GetEvents(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64) ([]*types.HistoryEvent, error)
 	GetEventCount(ctx context.Context, key types.ExecutionKey) (int64, error)
+	GetEventsByType(ctx context.Context, key types.ExecutionKey, eventTypes []types.EventType, firstEventID int64, limit int) ([]*types.HistoryEvent, error)
+	GetEventCountByType(ctx context.Context, key types.ExecutionKey, eventTypes []types.EventType) (int64, error)
+	DeleteEvents(ctx context.Context, key types.ExecutionKey) error
 }
 
 // MutableStateStore defines the interface for storing workflow mutable state.
@@ -42,6 +73,7 @@ type MutableStateStore interface {
 	GetMutableState(ctx context.Context, key types.ExecutionKey) (*engine.MutableState, error)
 	UpdateMutableState(ctx context.Context, key types.ExecutionKey, state *engine.MutableState, expectedVersion int64) error
 	ListRunningExecutions(ctx context.Context) ([]types.ExecutionKey, error)
+	DeleteMutableState(ctx context.Context, key types.ExecutionKey) error
 }
 
 // ShardController manages shard ownership and distribution.
@@ -49,6 +81,9 @@ type ShardController interface {
 	Start() error
 	GetShardForExecution(key types.ExecutionKey) (shard.Shard, error)
 	GetShardIDForExecution(key types.ExecutionKey) int32
+	NumShards() int32
+	OwnedShards() []int32
+	Rebalance() []int32
 	Stop()
 }
 
@@ -57,6 +92,17 @@ type Metrics interface {
 	RecordEventRecorded(eventType types.EventType)
 	RecordEventRetrieved(count int)
 	RecordServiceLatency(operation string, duration time.Duration)
+	RecordStateConflictRetry()
+	RecordEventsCompacted(count int)
+	// RecordStateConflict counts a mutable state version conflict, whether or
+	// not it is retried.
+	RecordStateConflict(namespace string)
+	// RecordStateConflictRetryDepth reports how many times the current update
+	// has been retried; it drops back to zero once the update settles.
+	RecordStateConflictRetryDepth(namespace string, depth int)
+	// RecordSignalThrottled counts a signal rejected by the per-execution
+	// signal rate limit.
+	RecordSignalThrottled(namespace string)
 }
 
 // noopMetrics is a no-op implementation of Metrics.
@@ -65,6 +111,11 @@ type noopMetrics1 struct{}
 func (noopMetrics1) RecordEventRecorded(types.EventType)        {}
 func (noopMetrics1) RecordEventRetrieved(int)                   {}
 func (noopMetrics1) RecordServiceLatency(string, time.Duration) {}
+func (noopMetrics1) RecordStateConflictRetry()                  {}
+func (noopMetrics1) RecordEventsCompacted(int)                  {}
+func (noopMetrics1) RecordStateConflict(string)                 {}
+func (noopMetrics1) RecordStateConflictRetryDepth(string, int)  {}
+func (noopMetrics1) RecordSignalThrottled(string)               {}
 
 // Service provides workflow history management capabilities.
 type Service struct {
@@ -79,11 +130,40 @@ type Service struct {
 	replicator      *ndc.Replicator
 	metrics         Metrics
 	logger          *slog.Logger
+	maxChildDepth   int32
+	maxCloseFanOut  int
+	maxConflicts    int
+	statsCache      *executionStatsCache
+
+	// backpressureRetry is the first delay before re-sending a task that
+	// Matching refused for backpressure.
+	backpressureRetry time.Duration
+
+	executionCounter  *controlplane.ExecutionCounter
+	reconcileInterval time.Duration
 
-	running    bool
-	mu         sync.RWMutex
-	wg         sync.WaitGroup
-	stopCh     chan struct{}
+	compaction EventCompactionConfig
+
+	historyPollers *historyNotifier
+
+	maxSignals int
+
+	maxAppliedRequests int
+
+	// signalLimits rate limits signals per execution; signalConfig, when
+	// set, is re-read every signalRefreshInterval for new limits.
+	signalLimits          *signalRateLimiter
+	signalConfig          DynamicConfigProvider
+	signalRefreshInterval time.Duration
+
+	auditSink    audit.Sink
+	auditRecords chan audit.Record
+	auditStats   auditCounters
+
+	running bool
+	mu      sync.RWMutex
+	wg      sync.WaitGroup
+	stopCh  chan struct{}
 }
 
 // Config holds configuration for the history service.
@@ -98,6 +178,81 @@ type Config struct {
 	Replicator      *ndc.Replicator      // optional
 	Logger          *slog.Logger
 	Metrics         Metrics
+
+	// MaxChildWorkflowDepth limits child workflow nesting (default DefaultMaxChildWorkflowDepth).
+	MaxChildWorkflowDepth int32
+
+	// MaxParentCloseFanOut bounds how many children one parent close applies
+	// its ParentClosePolicy to; the rest are abandoned with a warning
+	// (default DefaultMaxParentCloseFanOut).
+	MaxParentCloseFanOut int
+
+	// MaxStateConflictRetries bounds how often processEvents re-applies events
+	// after a mutable state version conflict (default DefaultMaxStateConflictRetries).
+	MaxStateConflictRetries int
+
+	// MaxAppliedRequests bounds the client request IDs an execution keeps
+	// for deduplication; the oldest are forgotten first, after which a retry
+	// of them is applied again (default DefaultMaxAppliedRequests).
+	MaxAppliedRequests int
+
+	// ExecutionCounter is the namespace running-execution counter the frontend
+	// enforces MaxConcurrentExecutions with (optional). History releases slots
+	// on close and reconciles it against visibility.
+	ExecutionCounter *controlplane.ExecutionCounter
+
+	// ConcurrencyReconcileInterval is how often ExecutionCounter is reset from
+	// visibility (default DefaultConcurrencyReconcileInterval).
+	ConcurrencyReconcileInterval time.Duration
+
+	// DefaultEncoding is the payload encoding new events are stored with when
+	// the event store supports it (default JSON). Stored events of any
+	// encoding remain readable.
+	DefaultEncoding events.PayloadEncoding
+
+	// AuditSink receives a record for each lifecycle transition (default
+	// audit.NoopSink). Records are flushed asynchronously; sink errors are
+	// logged and counted in AuditStats.
+	AuditSink audit.Sink
+
+	// AuditBufferSize is the number of audit records buffered for the sink
+	// (default DefaultAuditBufferSize).
+	AuditBufferSize int
+
+	// EventCompaction trims old events already covered by a snapshot in the
+	// namespaces that opt in (optional). It needs SnapshotStore and an event
+	// store that implements EventTrimmer.
+	EventCompaction EventCompactionConfig
+
+	// MaxHistoryPollWaiters bounds the PollHistory calls waiting on one
+	// execution at a time (default DefaultMaxHistoryPollWaiters).
+	MaxHistoryPollWaiters int
+
+	// MaxBufferedSignals bounds the signals an execution buffers until
+	// wait_signal nodes take them; further signals are rejected with
+	// ErrSignalBufferFull (default DefaultMaxBufferedSignals).
+	MaxBufferedSignals int
+
+	// SignalRateLimits limits how fast each execution accepts signals;
+	// signals over the limit are rejected with ErrSignalRateLimited before
+	// they reach the signal buffer (default: unlimited).
+	SignalRateLimits *controlplane.SignalRateLimitConfig
+
+	// DynamicConfig, when set, is polled every SignalRateLimitRefreshInterval
+	// (default 30s) for the controlplane.SignalRateLimitsConfigKey limits.
+	DynamicConfig                  DynamicConfigProvider
+	SignalRateLimitRefreshInterval time.Duration
+
+	// HashChainNamespaces lists the namespaces whose events are chained by
+	// hash on append, when the event store implements HashChainSetter, so
+	// VerifyHistoryIntegrity can detect tampering (optional).
+	HashChainNamespaces []string
+}
+
+// PayloadEncodingSetter is implemented by event stores that can write events
+// in more than one payload encoding.
+type PayloadEncodingSetter interface {
+	SetPayloadEncoding(encoding events.PayloadEncoding)
 }
 
 // NewService creates a new history service with default config.
@@ -128,19 +283,93 @@ func NewServiceWithConfig(cfg Config) *Service {
 	if metrics == nil {
 		metrics = noopMetrics1{}
 	}
+	maxChildDepth := cfg.MaxChildWorkflowDepth
+	if maxChildDepth <= 0 {
+		maxChildDepth = DefaultMaxChildWorkflowDepth
+	}
+	maxCloseFanOut := cfg.MaxParentCloseFanOut
+	if maxCloseFanOut <= 0 {
+		maxCloseFanOut = DefaultMaxParentCloseFanOut
+	}
+	maxConflictRetries := cfg.MaxStateConflictRetries
+	if maxConflictRetries <= 0 {
+		maxConflictRetries = DefaultMaxStateConflictRetries
+	}
+	reconcileInterval := cfg.ConcurrencyReconcileInterval
+	if reconcileInterval <= 0 {
+		reconcileInterval = DefaultConcurrencyReconcileInterval
+	}
+	auditSink := cfg.AuditSink
+	if auditSink == nil {
+		auditSink = audit.NoopSink{}
+	}
+	auditBufferSize := cfg.AuditBufferSize
+	if auditBufferSize <= 0 {
+		auditBufferSize = DefaultAuditBufferSize
+	}
+	compaction := cfg.EventCompaction
+	if compaction.Interval <= 0 {
+		compaction.Interval = DefaultEventCompactionInterval
+	}
+	maxPollWaiters := cfg.MaxHistoryPollWaiters
+	if maxPollWaiters <= 0 {
+		maxPollWaiters = DefaultMaxHistoryPollWaiters
+	}
+	maxBufferedSignals := cfg.MaxBufferedSignals
+	if maxBufferedSignals <= 0 {
+		maxBufferedSignals = DefaultMaxBufferedSignals
+	}
+	maxAppliedRequests := cfg.MaxAppliedRequests
+	if maxAppliedRequests <= 0 {
+		maxAppliedRequests = DefaultMaxAppliedRequests
+	}
+	signalRefreshInterval := cfg.SignalRateLimitRefreshInterval
+	if signalRefreshInterval <= 0 {
+		signalRefreshInterval = DefaultSignalRateLimitRefreshInterval
+	}
+	signalLimits := newSignalRateLimiter()
+	if cfg.SignalRateLimits != nil {
+		signalLimits.setConfig(*cfg.SignalRateLimits)
+	}
+	if setter, ok := cfg.EventStore.(PayloadEncodingSetter); ok {
+		setter.SetPayloadEncoding(cfg.DefaultEncoding)
+	}
+	if len(cfg.HashChainNamespaces) > 0 {
+		if setter, ok := cfg.EventStore.(HashChainSetter); ok {
+			setter.SetHashChainNamespaces(cfg.HashChainNamespaces)
+		} else {
+			cfg.Logger.Warn("event hash chains disabled: event store cannot chain hashes")
+		}
+	}
 	return &Service{
-		shardController: cfg.ShardController,
-		eventStore:      cfg.EventStore,
-		stateStore:      cfg.StateStore,
-		visibilityStore: cfg.VisibilityStore,
-		matchingClient:  cfg.MatchingClient,
-		historyEngine:   engine.NewEngine(cfg.Logger),
-		snapshotStore:   cfg.SnapshotStore,
-		archiver:        cfg.Archiver,
-		replicator:      cfg.Replicator,
-		metrics:         metrics,
-		logger:          cfg.Logger,
-		running:         false,
+		shardController:       cfg.ShardController,
+		eventStore:            cfg.EventStore,
+		stateStore:            cfg.StateStore,
+		visibilityStore:       cfg.VisibilityStore,
+		matchingClient:        cfg.MatchingClient,
+		backpressureRetry:     defaultBackpressureRetryInterval,
+		historyEngine:         engine.NewEngine(cfg.Logger),
+		snapshotStore:         cfg.SnapshotStore,
+		archiver:              cfg.Archiver,
+		replicator:            cfg.Replicator,
+		metrics:               metrics,
+		logger:                cfg.Logger,
+		maxChildDepth:         maxChildDepth,
+		maxCloseFanOut:        maxCloseFanOut,
+		maxConflicts:          maxConflictRetries,
+		statsCache:            newExecutionStatsCache(DefaultStatsCacheSize),
+		executionCounter:      cfg.ExecutionCounter,
+		reconcileInterval:     reconcileInterval,
+		compaction:            compaction,
+		historyPollers:        newHistoryNotifier(maxPollWaiters),
+		maxSignals:            maxBufferedSignals,
+		maxAppliedRequests:    maxAppliedRequests,
+		signalLimits:          signalLimits,
+		signalConfig:          cfg.DynamicConfig,
+		signalRefreshInterval: signalRefreshInterval,
+		auditSink:             auditSink,
+		auditRecords:          make(chan audit.Record, auditBufferSize),
+		running:               false,
 	}
 }
 
@@ -164,6 +393,10 @@ func (s *Service) Start(ctx context.Context) error {
 	s.running = true
 
 	s.startTimeoutChecker()
+	s.startConcurrencyReconciler()
+	s.startAuditFlusher()
+	s.startEventCompactor()
+	s.startSignalRateLimitRefresher()
 
 	return nil
 }
@@ -199,12 +432,108 @@ func (s *Service) IsRunning() bool {
 
 // RecordEvent is legacy/direct event recording. Kept for backward compatibility or direct calls.
 func (s *Service) RecordEvent(ctx context.Context, key types.ExecutionKey, event *types.HistoryEvent) error {
+	events := []*types.HistoryEvent{event}
+
+	// A signal completes the nodes waiting on it in the same batch, which
+	// wakes the decider.
+	if signal, ok := event.Attributes.(*types.SignalReceivedAttributes); ok && event.EventType == types.EventTypeSignalReceived {
+		if !s.signalLimits.allow(key) {
+			s.metrics.RecordSignalThrottled(key.NamespaceID)
+			return ErrSignalRateLimited
+		}
+		waiters, err := s.signalWaiterEvents(ctx, key, signal)
+		if err != nil {
+			return err
+		}
+		events = append(events, waiters...)
+	}
+
+	if fired, ok := event.Attributes.(*types.TimerFiredAttributes); ok && event.EventType == types.EventTypeTimerFired {
+		return s.recordTimerFired(ctx, key, event, fired.TimerID)
+	}
+
 	// Re-route to standard event processing which includes task dispatching
-	return s.processEvents(ctx, key, []*types.HistoryEvent{event})
+	return s.processEvents(ctx, key, events)
+}
+
+// recordTimerFired records a TimerFired event at most once per timer. A
+// timer service retrying after its acknowledgement was lost gets success,
+// with event.EventID set to the event already recorded, rather than a
+// timer-not-found or out-of-order error.
+func (s *Service) recordTimerFired(ctx context.Context, key types.ExecutionKey, event *types.HistoryEvent, timerID string) error {
+	firedEvent := func() (int64, bool) {
+		state, err := s.stateStore.GetMutableState(ctx, key)
+		if err != nil {
+			return 0, false
+		}
+		return state.GetFiredTimer(timerID)
+	}
+
+	duplicate := func(eventID int64) error {
+		s.logger.Info("duplicate timer fired ignored",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("timer_id", timerID),
+			slog.Int64("event_id", eventID),
+		)
+		event.EventID = eventID
+		return nil
+	}
+
+	if eventID, ok := firedEvent(); ok {
+		return duplicate(eventID)
+	}
+	err := s.processEvents(ctx, key, []*types.HistoryEvent{event})
+	if err != nil {
+		// A concurrent attempt may have recorded it first.
+		if eventID, ok := firedEvent(); ok {
+			return duplicate(eventID)
+		}
+	}
+	return err
 }
 
 // processEvents is the core event processing loop that persists events and dispatches tasks
 func (s *Service) processEvents(ctx context.Context, key types.ExecutionKey, events []*types.HistoryEvent) error {
+	_, _, err := s.processEventsOnce(ctx, key, "", events)
+	return err
+}
+
+// processEventsOnce is processEvents keyed by a client request ID: events are
+// appended at most once per request ID and execution. It returns the ID of
+// the first event the request appended, and whether the request had already
+// been applied, in which case nothing is appended or dispatched.
+func (s *Service) processEventsOnce(ctx context.Context, key types.ExecutionKey, requestID string, events []*types.HistoryEvent) (int64, bool, error) {
+	var requestIDs []string
+	if requestID != "" {
+		requestIDs = []string{requestID}
+	}
+	state, err := s.processEventBatch(ctx, key, requestIDs, events)
+	if errors.Is(err, errDuplicateRequest) {
+		eventID, _ := state.GetAppliedRequest(requestID)
+		s.logger.Info("duplicate request ignored",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("run_id", key.RunID),
+			slog.String("request_id", requestID),
+			slog.Int64("event_id", eventID),
+		)
+		return eventID, true, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(events) == 0 {
+		return 0, false, nil
+	}
+	return events[0].EventID, false, nil
+}
+
+// processEventBatch appends events made by one or more client requests in a
+// single update of the mutable state. requestIDs[i] names the request that
+// appended events[i]; empty IDs are not recorded. If any request was already
+// applied, nothing is appended and errDuplicateRequest is returned with the
+// current state.
+func (s *Service) processEventBatch(ctx context.Context, key types.ExecutionKey, requestIDs []string, events []*types.HistoryEvent) (*engine.MutableState, error) {
 	start := time.Now()
 	defer func() {
 		s.metrics.RecordServiceLatency("ProcessEvents", time.Since(start))
@@ -215,58 +544,35 @@ func (s *Service) processEvents(ctx context.Context, key types.ExecutionKey, eve
 	s.mu.RUnlock()
 
 	if !running {
-		return ErrServiceNotRunning
+		return nil, ErrServiceNotRunning
 	}
 
 	_, err := s.shardController.GetShardForExecution(key)
 	if err != nil {
-		return err
-	}
-
-	state, err := s.stateStore.GetMutableState(ctx, key)
-	if err != nil {
-		if errors.Is(err, types.ErrExecutionNotFound) {
-			// Create new mutable state if it doesn't exist
-			state = engine.NewMutableState(&types.ExecutionInfo{
-				NamespaceID: key.NamespaceID,
-				WorkflowID:  key.WorkflowID,
-				RunID:       key.RunID,
-			})
-		} else {
-			return err
-		}
+		return nil, err
 	}
 
-	expectedVersion := state.DBVersion
-
-	// Apply all events to state and assign IDs
-	for _, event := range events {
-		if event.EventID == 0 {
-			event.EventID = state.NextEventID
-		}
-		if err := s.historyEngine.ProcessEvent(state, event); err != nil {
-			return err
-		}
+	// Nothing is recorded for a caller that has already given up.
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	// Persist events
-	if err := s.eventStore.AppendEvents(ctx, key, events, expectedVersion); err != nil {
-		return err
+	state, err := s.withStateRetry(ctx, key, requestIDs, events)
+	if err != nil {
+		return state, err
 	}
 
-	state.DBVersion++
-
-	// Update mutable state
-	if err := s.stateStore.UpdateMutableState(ctx, key, state, expectedVersion); err != nil {
-		s.logger.Warn("failed to update mutable state", "error", err, "workflow_id", key.WorkflowID)
-		return err
-	}
+	// Wake history pollers waiting for these events
+	s.historyPollers.notify(key, executionClosed(state))
 
 	// Metrics
 	for _, event := range events {
 		s.metrics.RecordEventRecorded(event.EventType)
 	}
 
+	// Audit trail, flushed in the background
+	s.emitAudit(key, events)
+
 	// Record Visibility
 	if s.visibilityStore != nil {
 		for _, event := range events {
@@ -274,11 +580,20 @@ func (s *Service) processEvents(ctx context.Context, key types.ExecutionKey, eve
 		}
 	}
 
-	// Dispatch tasks to Matching Service based on new state/events
+	// Dispatch tasks to Matching Service based on new state/events. The events
+	// are already committed, so their tasks are sent even if the caller has
+	// given up in the meantime.
 	if s.matchingClient != nil {
+		dispatchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
 		// We dispatch tasks for the LAST event usually, or iterate all
 		for _, event := range events {
-			if err := s.dispatchTasks(ctx, key, event, state); err != nil {
+			if err := s.dispatchTasks(dispatchCtx, key, event, state); err != nil {
+				if errors.Is(err, ErrMatchingBackpressure) {
+					s.logger.Warn("task dispatch rejected by matching backpressure, retrying",
+						"error", err, "workflow_id", key.WorkflowID, "event_id", event.EventID)
+					continue
+				}
 				s.logger.Error("failed to dispatch tasks to matching", "error", err)
 			}
 		}
@@ -300,21 +615,43 @@ func (s *Service) processEvents(ctx context.Context, key types.ExecutionKey, eve
 	// Archival on execution close (Feature 8)
 	if s.archiver != nil {
 		for _, event := range events {
-			if event.EventType == types.EventTypeExecutionCompleted || event.EventType == types.EventTypeExecutionFailed {
-				allEvents, err := s.eventStore.GetEvents(ctx, key, 1, state.NextEventID-1)
-				if err != nil {
-					s.logger.Warn("failed to fetch events for archival", "error", err, "workflow_id", key.WorkflowID)
-					break
-				}
-				if err := s.archiver.Archive(ctx, &archival.ArchiveRequest{
+			if isExecutionCloseEvent(event.EventType) {
+				s.archiveExecution(ctx, key, event, state)
+				break
+			}
+		}
+	}
+
+	// Child workflow close propagates to the parent's decider
+	if state.ExecutionInfo != nil && state.ExecutionInfo.ParentWorkflowID != "" {
+		for _, event := range events {
+			if attrs := childCloseAttributes(key, state, event); attrs != nil {
+				parentKey := types.ExecutionKey{
 					NamespaceID: key.NamespaceID,
-					ExecutionID: key.RunID,
-					WorkflowID:  key.WorkflowID,
-					Events:      allEvents,
-					ClosedAt:    event.Timestamp,
-				}); err != nil {
-					s.logger.Warn("failed to archive execution", "error", err, "workflow_id", key.WorkflowID)
+					WorkflowID:  state.ExecutionInfo.ParentWorkflowID,
+					RunID:       state.ExecutionInfo.ParentRunID,
 				}
+				s.notifyParentOfChildClose(ctx, parentKey, attrs)
+				break
+			}
+		}
+	}
+
+	// A closing parent applies each running child's ParentClosePolicy
+	if len(state.PendingChildren) > 0 {
+		for _, event := range events {
+			if isExecutionCloseEvent(event.EventType) {
+				s.applyParentClosePolicies(ctx, key, state)
+				break
+			}
+		}
+	}
+
+	// Free the namespace concurrency slot held by a top-level execution
+	if s.executionCounter != nil && state.ExecutionInfo != nil && state.ExecutionInfo.ParentWorkflowID == "" {
+		for _, event := range events {
+			if isExecutionCloseEvent(event.EventType) {
+				s.releaseExecutionSlot(ctx, key)
 				break
 			}
 		}
@@ -333,20 +670,26 @@ func (s *Service) processEvents(ctx context.Context, key types.ExecutionKey, eve
 		}()
 	}
 
-	return nil
+	return state, nil
 }
 
 func (s *Service) recordVisibility(ctx context.Context, key types.ExecutionKey, event *types.HistoryEvent, state *engine.MutableState) {
 	switch event.EventType {
 	case types.EventTypeExecutionStarted:
-		attr := event.Attributes.(*historyv1.HistoryEvent_ExecutionStartedAttributes)
+		var memo *commonv1.Memo
+		if attr, ok := event.Attributes.(*historyv1.HistoryEvent_ExecutionStartedAttributes); ok {
+			memo = attr.ExecutionStartedAttributes.Memo
+		}
 		s.visibilityStore.RecordWorkflowExecutionStarted(ctx, &visibility.RecordWorkflowExecutionStartedRequest{
-			NamespaceID:  key.NamespaceID,
-			Execution:    &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
-			WorkflowType: &apiv1.WorkflowType{Name: state.ExecutionInfo.WorkflowTypeName}, // Simplified
-			StartTime:    event.Timestamp,
-			Status:       commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING,
-			Memo:         attr.ExecutionStartedAttributes.Memo,
+			NamespaceID:      key.NamespaceID,
+			Execution:        &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+			WorkflowType:     &apiv1.WorkflowType{Name: state.ExecutionInfo.WorkflowTypeName}, // Simplified
+			StartTime:        event.Timestamp,
+			Status:           commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING,
+			Memo:             memo,
+			ParentWorkflowID: state.ExecutionInfo.ParentWorkflowID,
+			ParentRunID:      state.ExecutionInfo.ParentRunID,
+			CorrelationID:    state.ExecutionInfo.CorrelationID,
 		})
 
 	case types.EventTypeExecutionCompleted:
@@ -366,6 +709,58 @@ func (s *Service) recordVisibility(ctx context.Context, key types.ExecutionKey,
 			CloseTime:    event.Timestamp,
 			Status:       commonv1.ExecutionStatus_EXECUTION_STATUS_FAILED,
 		})
+
+	case types.EventTypeExecutionTerminated:
+		s.visibilityStore.RecordWorkflowExecutionClosed(ctx, &visibility.RecordWorkflowExecutionClosedRequest{
+			NamespaceID:  key.NamespaceID,
+			Execution:    &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+			WorkflowType: &apiv1.WorkflowType{Name: state.ExecutionInfo.WorkflowTypeName},
+			CloseTime:    event.Timestamp,
+			Status:       commonv1.ExecutionStatus_EXECUTION_STATUS_TERMINATED,
+		})
+
+	case types.EventTypeExecutionTimedOut, types.EventTypeExecutionCanceled:
+		s.visibilityStore.RecordWorkflowExecutionClosed(ctx, &visibility.RecordWorkflowExecutionClosedRequest{
+			NamespaceID:  key.NamespaceID,
+			Execution:    &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+			WorkflowType: &apiv1.WorkflowType{Name: state.ExecutionInfo.WorkflowTypeName},
+			CloseTime:    event.Timestamp,
+			Status:       internalExecutionStatusToProto(executionCloseStatus(event.EventType)),
+		})
+	}
+}
+
+// archiveExecution archives the history of an execution closed by
+// closeEvent. Only the execution's first close event archives it, so a close
+// event appended to an already closed execution, e.g. by a force terminate,
+// does not archive it again.
+func (s *Service) archiveExecution(ctx context.Context, key types.ExecutionKey, closeEvent *types.HistoryEvent, state *engine.MutableState) {
+	allEvents, err := s.eventStore.GetEvents(ctx, key, 1, state.NextEventID-1)
+	if err != nil {
+		s.logger.Warn("failed to fetch events for archival", "error", err, "workflow_id", key.WorkflowID)
+		return
+	}
+	for _, event := range allEvents {
+		if !isExecutionCloseEvent(event.EventType) {
+			continue
+		}
+		if event.EventID != closeEvent.EventID {
+			s.logger.Debug("skipping archival: execution was already closed",
+				"workflow_id", key.WorkflowID, "closed_at_event", event.EventID, "event_id", closeEvent.EventID)
+			return
+		}
+		break
+	}
+
+	if err := s.archiver.Archive(ctx, &archival.ArchiveRequest{
+		NamespaceID: key.NamespaceID,
+		ExecutionID: key.RunID,
+		WorkflowID:  key.WorkflowID,
+		Events:      allEvents,
+		Status:      executionCloseStatus(closeEvent.EventType),
+		ClosedAt:    closeEvent.Timestamp,
+	}); err != nil {
+		s.logger.Warn("failed to archive execution", "error", err, "workflow_id", key.WorkflowID)
 	}
 }
 
@@ -394,6 +789,46 @@ func (s *Service) RespondWorkflowTaskCompleted(ctx context.Context, req *history
 	}
 	newEvents = append(newEvents, completedEvent)
 
+	// Child workflows are created only after the parent's events are persisted,
+	// so a fast child can never report completion before its start is recorded.
+	var childStarts []*pendingChildStart
+	var currentState *engine.MutableState
+	loadState := func() (*engine.MutableState, error) {
+		if currentState == nil {
+			state, err := s.stateStore.GetMutableState(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+			currentState = state
+		}
+		return currentState, nil
+	}
+	versionMarkers := make(map[string]bool)
+
+	// Reject the whole decision when its commands contradict each other or
+	// the workflow definition; the task is failed so the decider runs again.
+	state, err := loadState()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCommands(req.Commands, workflowNodeIDs(state.ExecutionInfo.Input)); err != nil {
+		s.logger.Warn("rejected workflow task commands",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("run_id", key.RunID),
+			slog.String("error", err.Error()),
+		)
+		if _, failErr := s.RespondWorkflowTaskFailed(ctx, &historyv1.RespondWorkflowTaskFailedRequest{
+			Namespace:         req.Namespace,
+			WorkflowExecution: req.WorkflowExecution,
+			TaskToken:         req.TaskToken,
+			Identity:          req.Identity,
+			Failure:           &commonv1.Failure{Message: err.Error()},
+		}); failErr != nil {
+			return nil, failErr
+		}
+		return nil, err
+	}
+
 	// Process Commands
 	for _, cmd := range req.Commands {
 		switch cmd.CommandType {
@@ -401,7 +836,7 @@ func (s *Service) RespondWorkflowTaskCompleted(ctx context.Context, req *history
 			attr := cmd.GetScheduleActivityTaskAttributes()
 
 			scheduledEvent := &types.HistoryEvent{
-				EventType: types.EventType(commonv1.EventType_EVENT_TYPE_NODE_SCHEDULED),
+				EventType: types.EventTypeNodeScheduled,
 				Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
 					NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{
 						NodeId:    attr.NodeId,
@@ -409,6 +844,9 @@ func (s *Service) RespondWorkflowTaskCompleted(ctx context.Context, req *history
 						Name:      attr.Name,
 						TaskQueue: &apiv1.TaskQueue{Name: attr.TaskQueue, Kind: commonv1.TaskQueueKind_TASK_QUEUE_KIND_NORMAL},
 						Input:     attr.Input,
+
+						StartToCloseTimeout:    attr.StartToCloseTimeout,
+						ScheduleToCloseTimeout: attr.ScheduleToCloseTimeout,
 					},
 				},
 			}
@@ -417,7 +855,7 @@ func (s *Service) RespondWorkflowTaskCompleted(ctx context.Context, req *history
 		case historyv1.CommandType_COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION:
 			attr := cmd.GetCompleteWorkflowExecutionAttributes()
 			completeEvent := &types.HistoryEvent{
-				EventType: types.EventType(commonv1.EventType_EVENT_TYPE_EXECUTION_COMPLETED),
+				EventType: types.EventTypeExecutionCompleted,
 				Attributes: &historyv1.HistoryEvent_ExecutionCompletedAttributes{
 					ExecutionCompletedAttributes: &historyv1.ExecutionCompletedEventAttributes{
 						Result: attr.Result,
@@ -429,7 +867,7 @@ func (s *Service) RespondWorkflowTaskCompleted(ctx context.Context, req *history
 		case historyv1.CommandType_COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION:
 			attr := cmd.GetFailWorkflowExecutionAttributes()
 			failEvent := &types.HistoryEvent{
-				EventType: types.EventType(commonv1.EventType_EVENT_TYPE_EXECUTION_FAILED),
+				EventType: types.EventTypeExecutionFailed,
 				Attributes: &historyv1.HistoryEvent_ExecutionFailedAttributes{
 					ExecutionFailedAttributes: &historyv1.ExecutionFailedEventAttributes{
 						Failure: attr.Failure,
@@ -437,6 +875,52 @@ func (s *Service) RespondWorkflowTaskCompleted(ctx context.Context, req *history
 				},
 			}
 			newEvents = append(newEvents, failEvent)
+
+		case historyv1.CommandType_COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:
+			attr := cmd.GetStartChildWorkflowExecutionAttributes()
+			if attr == nil {
+				continue
+			}
+
+			parentState, err := loadState()
+			if err != nil {
+				return nil, err
+			}
+
+			child, event := s.prepareChildWorkflow(key, parentState, attr)
+			if child != nil {
+				childStarts = append(childStarts, child)
+			}
+			newEvents = append(newEvents, event)
+
+		case historyv1.CommandType_COMMAND_TYPE_RECORD_VERSION_MARKER:
+			attr := cmd.GetRecordVersionMarkerAttributes()
+			if attr == nil || attr.ChangeId == "" {
+				continue
+			}
+
+			state, err := loadState()
+			if err != nil {
+				return nil, err
+			}
+			// Only the first version recorded for a change counts; deciders
+			// replaying history read that one back instead of choosing again.
+			if _, recorded := state.VersionMarkers[attr.ChangeId]; recorded || versionMarkers[attr.ChangeId] {
+				continue
+			}
+			versionMarkers[attr.ChangeId] = true
+
+			markerEvent := &types.HistoryEvent{
+				EventType: types.EventTypeMarkerRecorded,
+				Attributes: &types.MarkerRecordedAttributes{
+					MarkerName: types.VersionMarkerName,
+					Details: map[string][]byte{
+						types.VersionMarkerChangeIDKey: []byte(attr.ChangeId),
+						types.VersionMarkerVersionKey:  []byte(strconv.FormatInt(int64(attr.Version), 10)),
+					},
+				},
+			}
+			newEvents = append(newEvents, markerEvent)
 		}
 	}
 
@@ -444,6 +928,23 @@ func (s *Service) RespondWorkflowTaskCompleted(ctx context.Context, req *history
 		return nil, err
 	}
 
+	for _, child := range childStarts {
+		if err := s.startChildWorkflow(ctx, child); err != nil {
+			s.logger.Error("failed to start child workflow",
+				slog.String("parent_workflow_id", key.WorkflowID),
+				slog.String("child_workflow_id", child.key.WorkflowID),
+				slog.String("error", err.Error()),
+			)
+			s.notifyParentOfChildClose(ctx, key, &types.ChildWorkflowCompletedAttributes{
+				NodeID:        child.nodeID,
+				WorkflowID:    child.key.WorkflowID,
+				RunID:         child.key.RunID,
+				Status:        types.ExecutionStatusFailed,
+				FailureReason: fmt.Sprintf("failed to start child workflow: %v", err),
+			})
+		}
+	}
+
 	return &historyv1.RespondWorkflowTaskCompletedResponse{ActivityTasksScheduled: true}, nil
 }
 
@@ -470,23 +971,18 @@ func (s *Service) RespondWorkflowTaskFailed(ctx context.Context, req *historyv1.
 }
 
 func (s *Service) RespondActivityTaskCompleted(ctx context.Context, req *historyv1.RespondActivityTaskCompletedRequest) (*historyv1.RespondActivityTaskCompletedResponse, error) {
-	key := types.ExecutionKey{
-		NamespaceID: req.Namespace,
-		WorkflowID:  req.WorkflowExecution.WorkflowId,
-		RunID:       req.WorkflowExecution.RunId,
+	if len(req.GetTaskToken()) > 0 {
+		eventID, duplicate, err := s.completeAsyncActivity(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &historyv1.RespondActivityTaskCompletedResponse{EventId: eventID, Duplicate: duplicate}, nil
 	}
 
+	key := activityCompletionKey(req)
+
 	// Event: ActivityTaskCompleted (NodeCompleted)
-	event := &types.HistoryEvent{
-		EventType: types.EventType(commonv1.EventType_EVENT_TYPE_NODE_COMPLETED),
-		Attributes: &historyv1.HistoryEvent_NodeCompletedAttributes{
-			NodeCompletedAttributes: &historyv1.NodeCompletedEventAttributes{
-				ScheduledEventId: req.ScheduledEventId,
-				Result:           req.Result,
-				Identity:         req.Identity,
-			},
-		},
-	}
+	event := nodeCompletedEvent(req)
 
 	// Also Schedule a new WorkflowTask to wake up the decider
 	// We need to know the workflow's task queue. We can get it from MutableState in processEvents
@@ -494,14 +990,44 @@ func (s *Service) RespondActivityTaskCompleted(ctx context.Context, req *history
 	// Actually, processEvents should handle the "auto-scheduling" of WorkflowTask when a Node completes.
 	// Let's rely on dispatchTasks logic for that.
 
-	if err := s.processEvents(ctx, key, []*types.HistoryEvent{event}); err != nil {
+	eventID, duplicate, err := s.processEventsOnce(ctx, key, req.GetRequestId(), []*types.HistoryEvent{event})
+	if err != nil {
 		return nil, err
 	}
 
-	return &historyv1.RespondActivityTaskCompletedResponse{}, nil
+	return &historyv1.RespondActivityTaskCompletedResponse{EventId: eventID, Duplicate: duplicate}, nil
+}
+
+func activityCompletionKey(req *historyv1.RespondActivityTaskCompletedRequest) types.ExecutionKey {
+	return types.ExecutionKey{
+		NamespaceID: req.GetNamespace(),
+		WorkflowID:  req.GetWorkflowExecution().GetWorkflowId(),
+		RunID:       req.GetWorkflowExecution().GetRunId(),
+	}
+}
+
+func nodeCompletedEvent(req *historyv1.RespondActivityTaskCompletedRequest) *types.HistoryEvent {
+	return &types.HistoryEvent{
+		EventType: types.EventTypeNodeCompleted,
+		Attributes: &historyv1.HistoryEvent_NodeCompletedAttributes{
+			NodeCompletedAttributes: &historyv1.NodeCompletedEventAttributes{
+				ScheduledEventId: req.ScheduledEventId,
+				Result:           req.Result,
+				Identity:         req.Identity,
+			},
+		},
+	}
 }
 
 func (s *Service) RespondActivityTaskFailed(ctx context.Context, req *historyv1.RespondActivityTaskFailedRequest) (*historyv1.RespondActivityTaskFailedResponse, error) {
+	if len(req.GetTaskToken()) > 0 {
+		eventID, duplicate, err := s.failAsyncActivity(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &historyv1.RespondActivityTaskFailedResponse{EventId: eventID, Duplicate: duplicate}, nil
+	}
+
 	key := types.ExecutionKey{
 		NamespaceID: req.Namespace,
 		WorkflowID:  req.WorkflowExecution.WorkflowId,
@@ -509,7 +1035,7 @@ func (s *Service) RespondActivityTaskFailed(ctx context.Context, req *historyv1.
 	}
 
 	event := &types.HistoryEvent{
-		EventType: types.EventType(commonv1.EventType_EVENT_TYPE_NODE_FAILED),
+		EventType: types.EventTypeNodeFailed,
 		Attributes: &historyv1.HistoryEvent_NodeFailedAttributes{
 			NodeFailedAttributes: &historyv1.NodeFailedEventAttributes{
 				ScheduledEventId: req.ScheduledEventId,
@@ -519,11 +1045,12 @@ func (s *Service) RespondActivityTaskFailed(ctx context.Context, req *historyv1.
 		},
 	}
 
-	if err := s.processEvents(ctx, key, []*types.HistoryEvent{event}); err != nil {
+	eventID, duplicate, err := s.processEventsOnce(ctx, key, req.GetRequestId(), []*types.HistoryEvent{event})
+	if err != nil {
 		return nil, err
 	}
 
-	return &historyv1.RespondActivityTaskFailedResponse{}, nil
+	return &historyv1.RespondActivityTaskFailedResponse{EventId: eventID, Duplicate: duplicate}, nil
 }
 
 func (s *Service) dispatchTasks(ctx context.Context, key types.ExecutionKey, event *types.HistoryEvent, state *engine.MutableState) error {
@@ -553,8 +1080,8 @@ func (s *Service) dispatchTasks(ctx context.Context, key types.ExecutionKey, eve
 		// The generic task struct in Matching service has a 'Config' field.
 		// We should extract it from Input or attributes.
 
-	case types.EventTypeNodeCompleted, types.EventTypeNodeFailed:
-		// When a node completes/fails, we dispatch a Workflow Task to wake up the decider
+	case types.EventTypeNodeCompleted, types.EventTypeNodeFailed, types.EventTypeChildWorkflowCompleted:
+		// When a node or child workflow completes/fails, we dispatch a Workflow Task to wake up the decider
 		taskType = commonv1.TaskType_TASK_TYPE_WORKFLOW_TASK
 		if state.ExecutionInfo != nil {
 			taskQueue = state.ExecutionInfo.TaskQueue
@@ -594,28 +1121,468 @@ func (s *Service) dispatchTasks(ctx context.Context, key types.ExecutionKey, eve
 		ScheduledEventId: event.EventID,
 	}
 
-	_, err := s.matchingClient.AddTask(ctx, req)
+	err := s.addMatchingTask(ctx, req)
+	if errors.Is(err, ErrMatchingBackpressure) {
+		// The event is already persisted, so its task must still reach Matching.
+		s.retryMatchingTask(req)
+	}
 	return err
 }
 
-// GetHistory, GetMutableState, etc. remain unchanged...
-func (s *Service) GetHistory(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64) ([]*types.HistoryEvent, error) {
-	return s.eventStore.GetEvents(ctx, key, firstEventID, lastEventID)
-}
+// addMatchingTask sends a task to Matching. A RESOURCE_EXHAUSTED rejection is
+// reported as ErrMatchingBackpressure, and a soft-limit warning is logged so
+// operators can see producers outpacing workers before tasks are refused.
+func (s *Service) addMatchingTask(ctx context.Context, req *matchingv1.AddTaskRequest) error {
+	resp, err := s.matchingClient.AddTask(ctx, req)
+	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			return fmt.Errorf("%w: task queue %q", ErrMatchingBackpressure, req.GetTaskQueue().GetName())
+		}
+		return err
+	}
 
-func (s *Service) GetMutableState(ctx context.Context, key types.ExecutionKey) (*engine.MutableState, error) {
-	return s.stateStore.GetMutableState(ctx, key)
+	if resp.GetBackpressureState() == matchingv1.BackpressureState_BACKPRESSURE_STATE_WARNING {
+		s.logger.Warn("matching task queue above soft limit",
+			slog.String("task_queue", req.GetTaskQueue().GetName()),
+			slog.String("workflow_id", req.GetWorkflowExecution().GetWorkflowId()),
+		)
+	}
+	return nil
 }
 
-func (s *Service) GetShardForExecution(key types.ExecutionKey) (shard.Shard, error) {
-	return s.shardController.GetShardForExecution(key)
-}
+// retryMatchingTask re-sends a task Matching refused for backpressure in the
+// background, backing off exponentially from s.backpressureRetry up to
+// maxBackpressureRetryInterval. It gives up after maxBackpressureRetries
+// attempts, on any other error, or when the service stops.
+func (s *Service) retryMatchingTask(req *matchingv1.AddTaskRequest) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.running {
+		return
+	}
+	stopCh := s.stopCh
 
-func (s *Service) GetShardIDForExecution(key types.ExecutionKey) int32 {
-	return s.shardController.GetShardIDForExecution(key)
-}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		logAttrs := []any{
+			slog.String("task_queue", req.GetTaskQueue().GetName()),
+			slog.String("workflow_id", req.GetWorkflowExecution().GetWorkflowId()),
+			slog.Int64("scheduled_event_id", req.GetScheduledEventId()),
+		}
+		interval := s.backpressureRetry
+		for attempt := 1; attempt <= maxBackpressureRetries; attempt++ {
+			timer := time.NewTimer(interval)
+			select {
+			case <-stopCh:
+				timer.Stop()
+				s.logger.Warn("task dispatch retry abandoned: service stopping", logAttrs...)
+				return
+			case <-timer.C:
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err := s.addMatchingTask(ctx, req)
+			cancel()
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, ErrMatchingBackpressure) {
+				s.logger.Error("task dispatch retry failed", append(logAttrs, "error", err)...)
+				return
+			}
+			interval = min(interval*2, maxBackpressureRetryInterval)
+		}
+		s.logger.Error("task dispatch retries exhausted under matching backpressure", logAttrs...)
+	}()
+}
+
+// pendingChildStart is a child execution accepted by the parent's decider but
+// not yet created.
+type pendingChildStart struct {
+	key          types.ExecutionKey
+	parent       types.ExecutionKey
+	nodeID       string
+	workflowType string
+	taskQueue    string
+	input        []byte
+	timeout      time.Duration
+	depth        int32
+	closePolicy  types.ParentClosePolicy
+}
+
+// prepareChildWorkflow validates a START_CHILD_WORKFLOW_EXECUTION command and
+// returns the parent event to record. When the nesting limit is exceeded no
+// child is created and the parent instead records an immediate failure.
+func (s *Service) prepareChildWorkflow(parent types.ExecutionKey, parentState *engine.MutableState, attr *historyv1.StartChildWorkflowExecutionCommandAttributes) (*pendingChildStart, *types.HistoryEvent) {
+	var parentDepth int32
+	parentTaskQueue := "default"
+	if parentState.ExecutionInfo != nil {
+		parentDepth = parentState.ExecutionInfo.ChildDepth
+		if parentState.ExecutionInfo.TaskQueue != "" {
+			parentTaskQueue = parentState.ExecutionInfo.TaskQueue
+		}
+	}
+
+	workflowID := attr.GetWorkflowId()
+	if workflowID == "" {
+		workflowID = fmt.Sprintf("%s-%s", parent.WorkflowID, attr.GetNodeId())
+	}
+	childKey := types.ExecutionKey{
+		NamespaceID: parent.NamespaceID,
+		WorkflowID:  workflowID,
+		RunID:       generateRunID(),
+	}
+
+	depth := parentDepth + 1
+	if depth > s.maxChildDepth {
+		s.logger.Warn("child workflow rejected: max nesting depth exceeded",
+			slog.String("parent_workflow_id", parent.WorkflowID),
+			slog.String("node_id", attr.GetNodeId()),
+			slog.Int("depth", int(depth)),
+			slog.Int("max_depth", int(s.maxChildDepth)),
+		)
+		return nil, &types.HistoryEvent{
+			EventType: types.EventTypeChildWorkflowCompleted,
+			Timestamp: time.Now(),
+			Attributes: &types.ChildWorkflowCompletedAttributes{
+				NodeID:        attr.GetNodeId(),
+				WorkflowID:    childKey.WorkflowID,
+				Status:        types.ExecutionStatusFailed,
+				FailureReason: fmt.Sprintf("max child workflow depth %d exceeded", s.maxChildDepth),
+			},
+		}
+	}
+
+	taskQueue := attr.GetTaskQueue()
+	if taskQueue == "" {
+		taskQueue = parentTaskQueue
+	}
+
+	child := &pendingChildStart{
+		key:          childKey,
+		parent:       parent,
+		nodeID:       attr.GetNodeId(),
+		workflowType: attr.GetWorkflowType().GetName(),
+		taskQueue:    taskQueue,
+		timeout:      attr.GetExecutionTimeout().AsDuration(),
+		depth:        depth,
+	}
+	if input := attr.GetInput(); input != nil && len(input.GetPayloads()) > 0 {
+		child.input = input.GetPayloads()[0].GetData()
+	}
+
+	policy, ok := parentClosePolicyFromProto(attr.GetParentClosePolicy())
+	if !ok {
+		s.logger.Warn("unknown parent close policy, using terminate",
+			slog.String("parent_workflow_id", parent.WorkflowID),
+			slog.String("node_id", attr.GetNodeId()),
+			slog.String("policy", attr.GetParentClosePolicy().String()),
+		)
+	}
+	child.closePolicy = policy
+
+	return child, &types.HistoryEvent{
+		EventType: types.EventTypeChildWorkflowStarted,
+		Timestamp: time.Now(),
+		Attributes: &types.ChildWorkflowStartedAttributes{
+			NodeID:            child.nodeID,
+			WorkflowID:        childKey.WorkflowID,
+			RunID:             childKey.RunID,
+			WorkflowType:      child.workflowType,
+			ParentClosePolicy: child.closePolicy,
+		},
+	}
+}
+
+// startChildWorkflow creates the child execution linked to its parent and
+// dispatches its first workflow task.
+func (s *Service) startChildWorkflow(ctx context.Context, child *pendingChildStart) error {
+	parent := child.parent
+	startedEvent := &types.HistoryEvent{
+		EventType: types.EventTypeExecutionStarted,
+		Timestamp: time.Now(),
+		Attributes: &types.ExecutionStartedAttributes{
+			WorkflowType:     child.workflowType,
+			TaskQueue:        child.taskQueue,
+			Input:            child.input,
+			ExecutionTimeout: child.timeout,
+			ParentExecution:  &parent,
+			ParentNodeID:     child.nodeID,
+			ChildDepth:       child.depth,
+			Initiator:        "parent",
+		},
+	}
+
+	if err := s.processEvents(ctx, child.key, []*types.HistoryEvent{startedEvent}); err != nil {
+		return err
+	}
+
+	if s.matchingClient != nil {
+		taskReq := &matchingv1.AddTaskRequest{
+			Namespace: child.key.NamespaceID,
+			TaskQueue: &matchingv1.TaskQueue{
+				Name: child.taskQueue,
+				Kind: commonv1.TaskQueueKind_TASK_QUEUE_KIND_NORMAL,
+			},
+			TaskType: commonv1.TaskType_TASK_TYPE_WORKFLOW_TASK,
+			WorkflowExecution: &commonv1.WorkflowExecution{
+				WorkflowId: child.key.WorkflowID,
+				RunId:      child.key.RunID,
+			},
+			ScheduledEventId: startedEvent.EventID,
+		}
+		if err := s.addMatchingTask(ctx, taskReq); err != nil {
+			return fmt.Errorf("failed to dispatch child workflow task: %w", err)
+		}
+	}
+
+	s.logger.Info("child workflow started",
+		slog.String("parent_workflow_id", parent.WorkflowID),
+		slog.String("parent_run_id", parent.RunID),
+		slog.String("child_workflow_id", child.key.WorkflowID),
+		slog.String("child_run_id", child.key.RunID),
+		slog.Int("depth", int(child.depth)),
+	)
+
+	return nil
+}
+
+// childCloseAttributes returns the ChildWorkflowCompleted attributes to record
+// on the parent when event closes a child execution, or nil otherwise.
+func childCloseAttributes(key types.ExecutionKey, state *engine.MutableState, event *types.HistoryEvent) *types.ChildWorkflowCompletedAttributes {
+	attrs := &types.ChildWorkflowCompletedAttributes{
+		NodeID:     state.ExecutionInfo.ParentNodeID,
+		WorkflowID: key.WorkflowID,
+		RunID:      key.RunID,
+	}
+
+	switch event.EventType {
+	case types.EventTypeExecutionCompleted:
+		attrs.Status = types.ExecutionStatusCompleted
+		switch a := event.Attributes.(type) {
+		case *types.ExecutionCompletedAttributes:
+			attrs.Result = a.Result
+		case *historyv1.HistoryEvent_ExecutionCompletedAttributes:
+			if payloads := a.ExecutionCompletedAttributes.GetResult().GetPayloads(); len(payloads) > 0 {
+				attrs.Result = payloads[0].GetData()
+			}
+		}
+	case types.EventTypeExecutionFailed:
+		attrs.Status = types.ExecutionStatusFailed
+		attrs.FailureReason = "child workflow failed"
+		switch a := event.Attributes.(type) {
+		case *types.ExecutionFailedAttributes:
+			attrs.FailureReason = a.Reason
+		case *historyv1.HistoryEvent_ExecutionFailedAttributes:
+			if msg := a.ExecutionFailedAttributes.GetFailure().GetMessage(); msg != "" {
+				attrs.FailureReason = msg
+			}
+		}
+	case types.EventTypeExecutionTerminated:
+		attrs.Status = types.ExecutionStatusTerminated
+		attrs.FailureReason = "child workflow terminated"
+		if a, ok := event.Attributes.(*types.ExecutionTerminatedAttributes); ok && a.Reason != "" {
+			attrs.FailureReason = a.Reason
+		}
+	case types.EventTypeExecutionTimedOut:
+		attrs.Status = types.ExecutionStatusTimedOut
+		attrs.FailureReason = "child workflow timed out"
+	case types.EventTypeExecutionCanceled:
+		attrs.Status = types.ExecutionStatusCanceled
+		attrs.FailureReason = "child workflow canceled"
+	default:
+		return nil
+	}
+
+	return attrs
+}
+
+// notifyParentOfChildClose records a ChildWorkflowCompleted event on the parent,
+// which wakes the parent's decider, and resolves the async activity that
+// waits on the child, if any. Optimistic lock conflicts with concurrent
+// parent updates are retried, as are activities that stopped waiting in the
+// meantime, e.g. because they timed out.
+func (s *Service) notifyParentOfChildClose(ctx context.Context, parent types.ExecutionKey, attrs *types.ChildWorkflowCompletedAttributes) {
+	const maxAttempts = 3
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		state, stateErr := s.stateStore.GetMutableState(ctx, parent)
+		// A parent that already closed, typically the one whose
+		// ParentClosePolicy terminated this child, has no decider left to wake.
+		if stateErr == nil && !state.IsWorkflowExecutionRunning() {
+			return
+		}
+
+		events := []*types.HistoryEvent{{
+			EventType:  types.EventTypeChildWorkflowCompleted,
+			Timestamp:  time.Now(),
+			Attributes: attrs,
+		}}
+		if stateErr == nil {
+			if resolved := childActivityEvent(state, attrs); resolved != nil {
+				events = append(events, resolved)
+			}
+		}
+		err = s.processEvents(ctx, parent, events)
+		if err == nil || !(errors.Is(err, types.ErrOptimisticLock) || errors.Is(err, engine.ErrNodeNotPending)) {
+			break
+		}
+	}
+
+	if err != nil {
+		s.logger.Error("failed to notify parent of child workflow close",
+			slog.String("parent_workflow_id", parent.WorkflowID),
+			slog.String("parent_run_id", parent.RunID),
+			slog.String("child_workflow_id", attrs.WorkflowID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// withStateRetry loads the mutable state, applies events on top of it and
+// persists both. On a version conflict the state is re-read and the events are
+// re-applied, up to s.maxConflicts times with a short backoff.
+func (s *Service) withStateRetry(ctx context.Context, key types.ExecutionKey, requestIDs []string, events []*types.HistoryEvent) (*engine.MutableState, error) {
+	// Only IDs assigned here are reassigned on retry; caller-provided IDs are kept.
+	autoID := make([]bool, len(events))
+	for i, event := range events {
+		autoID[i] = event.EventID == 0
+	}
+
+	retried := false
+	defer func() {
+		if retried {
+			s.metrics.RecordStateConflictRetryDepth(key.NamespaceID, 0)
+		}
+	}()
+
+	for attempt := 0; ; attempt++ {
+		state, err := s.applyAndPersist(ctx, key, requestIDs, events)
+		if err == nil || errors.Is(err, errDuplicateRequest) {
+			return state, err
+		}
+		if !errors.Is(err, types.ErrOptimisticLock) {
+			return nil, err
+		}
+		s.metrics.RecordStateConflict(key.NamespaceID)
+		if attempt >= s.maxConflicts {
+			s.logger.Warn("mutable state version conflict retries exhausted",
+				slog.String("namespace_id", key.NamespaceID),
+				slog.String("workflow_id", key.WorkflowID),
+				slog.String("run_id", key.RunID),
+				slog.Int("attempts", attempt+1),
+			)
+			return nil, err
+		}
+
+		retried = true
+		s.metrics.RecordStateConflictRetry()
+		s.metrics.RecordStateConflictRetryDepth(key.NamespaceID, attempt+1)
+		s.logger.Debug("mutable state version conflict, retrying",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("run_id", key.RunID),
+			slog.Int("attempt", attempt+1),
+		)
+
+		for i, event := range events {
+			if autoID[i] {
+				event.EventID = 0
+			}
+		}
+
+		backoff := time.Duration(attempt+1) * 10 * time.Millisecond
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (s *Service) applyAndPersist(ctx context.Context, key types.ExecutionKey, requestIDs []string, events []*types.HistoryEvent) (*engine.MutableState, error) {
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		if errors.Is(err, types.ErrExecutionNotFound) {
+			// Create new mutable state if it doesn't exist
+			state = engine.NewMutableState(&types.ExecutionInfo{
+				NamespaceID: key.NamespaceID,
+				WorkflowID:  key.WorkflowID,
+				RunID:       key.RunID,
+			})
+		} else {
+			return nil, err
+		}
+	}
+
+	for _, requestID := range requestIDs {
+		if requestID == "" {
+			continue
+		}
+		if _, ok := state.GetAppliedRequest(requestID); ok {
+			return state, errDuplicateRequest
+		}
+	}
+
+	expectedVersion := state.DBVersion
+
+	// Apply all events to state and assign IDs
+	for _, event := range events {
+		if event.EventID == 0 {
+			event.EventID = state.NextEventID
+		}
+		if err := s.historyEngine.ProcessEvent(state, event); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, requestID := range requestIDs {
+		if requestID != "" && i < len(events) {
+			state.AddAppliedRequest(requestID, events[i].EventID)
+		}
+	}
+	state.TrimAppliedRequests(s.maxAppliedRequests)
+
+	// Persist events
+	if err := s.eventStore.AppendEvents(ctx, key, events, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	state.DBVersion++
+
+	// Update mutable state
+	if err := s.stateStore.UpdateMutableState(ctx, key, state, expectedVersion); err != nil {
+		s.logger.Warn("failed to update mutable state", "error", err, "workflow_id", key.WorkflowID)
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// GetHistory, GetMutableState, etc. remain unchanged...
+func (s *Service) GetHistory(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64) ([]*types.HistoryEvent, error) {
+	return s.eventStore.GetEvents(ctx, key, firstEventID, lastEventID)
+}
+
+func (s *Service) GetMutableState(ctx context.Context, key types.ExecutionKey) (*engine.MutableState, error) {
+	return s.stateStore.GetMutableState(ctx, key)
+}
+
+func (s *Service) GetShardForExecution(key types.ExecutionKey) (shard.Shard, error) {
+	return s.shardController.GetShardForExecution(key)
+}
+
+func (s *Service) GetShardIDForExecution(key types.ExecutionKey) int32 {
+	return s.shardController.GetShardIDForExecution(key)
+}
 
 func (s *Service) ResetExecution(ctx context.Context, key types.ExecutionKey, reason string, resetEventID int64) (string, error) {
+	if err := s.validateResetPoint(ctx, key, resetEventID); err != nil {
+		return "", err
+	}
+
 	// 1. Fetch events up to resetEventID
 	events, err := s.eventStore.GetEvents(ctx, key, 1, resetEventID)
 	if err != nil {
@@ -683,7 +1650,7 @@ func (s *Service) ResetExecution(ctx context.Context, key types.ExecutionKey, re
 			},
 			ScheduledEventId: newState.NextEventID - 1,
 		}
-		if _, err := s.matchingClient.AddTask(ctx, taskReq); err != nil {
+		if err := s.addMatchingTask(ctx, taskReq); err != nil {
 			s.logger.Warn("failed to dispatch workflow task after reset", "error", err, "workflow_id", newKey.WorkflowID)
 		}
 	}
@@ -700,9 +1667,171 @@ func (s *Service) ResetExecution(ctx context.Context, key types.ExecutionKey, re
 	return newRunID, nil
 }
 
+// forceTerminateMaxAttempts bounds optimistic-lock retries in ForceTerminate.
+const forceTerminateMaxAttempts = 5
+
+// defaultForceTerminateIdentity is recorded for force terminations by an
+// unknown operator.
+const defaultForceTerminateIdentity = "admin-force-terminate"
+
+// ForceTerminate is a break-glass operation that writes a terminal
+// ExecutionTerminated event for an execution that normal termination cannot
+// clear. Engine close validation and task dispatch are skipped, optimistic
+// lock conflicts are retried against freshly loaded mutable state, and the
+// execution is marked closed in visibility. The terminated event records
+// identity, or defaultForceTerminateIdentity when it is empty.
+func (s *Service) ForceTerminate(ctx context.Context, key types.ExecutionKey, reason, identity string) error {
+	s.mu.RLock()
+	running := s.running
+	s.mu.RUnlock()
+
+	if !running {
+		return ErrServiceNotRunning
+	}
+
+	if identity == "" {
+		identity = defaultForceTerminateIdentity
+	}
+
+	s.logger.Warn("FORCE TERMINATE requested, bypassing normal close logic",
+		slog.String("namespace_id", key.NamespaceID),
+		slog.String("workflow_id", key.WorkflowID),
+		slog.String("run_id", key.RunID),
+		slog.String("reason", reason),
+		slog.String("identity", identity),
+	)
+
+	var lastErr error
+	for attempt := 1; attempt <= forceTerminateMaxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := s.forceTerminateOnce(ctx, key, reason, identity)
+		if err == nil {
+			s.logger.Warn("FORCE TERMINATE completed",
+				slog.String("workflow_id", key.WorkflowID),
+				slog.String("run_id", key.RunID),
+				slog.Int("attempts", attempt),
+			)
+			return nil
+		}
+		if !errors.Is(err, types.ErrOptimisticLock) {
+			s.logger.Error("FORCE TERMINATE failed",
+				slog.String("workflow_id", key.WorkflowID),
+				slog.String("run_id", key.RunID),
+				slog.String("error", err.Error()),
+			)
+			return err
+		}
+
+		lastErr = err
+		s.logger.Warn("FORCE TERMINATE hit optimistic lock conflict, retrying with fresh state",
+			slog.String("workflow_id", key.WorkflowID),
+			slog.String("run_id", key.RunID),
+			slog.Int("attempt", attempt),
+		)
+	}
+
+	s.logger.Error("FORCE TERMINATE gave up",
+		slog.String("workflow_id", key.WorkflowID),
+		slog.String("run_id", key.RunID),
+		slog.Int("attempts", forceTerminateMaxAttempts),
+	)
+	return fmt.Errorf("force terminate failed after %d attempts: %w", forceTerminateMaxAttempts, lastErr)
+}
+
+func (s *Service) forceTerminateOnce(ctx context.Context, key types.ExecutionKey, reason, identity string) error {
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	// History may have run ahead of mutable state; never reuse an existing
+	// event ID, since duplicate appends are silently ignored by the store.
+	eventCount, err := s.eventStore.GetEventCount(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	var event *types.HistoryEvent
+	if eventCount >= state.NextEventID {
+		// A previous attempt may have persisted the event but lost the state update.
+		tail, err := s.eventStore.GetEvents(ctx, key, eventCount, eventCount)
+		if err == nil && len(tail) == 1 && tail[0].EventType == types.EventTypeExecutionTerminated {
+			event = tail[0]
+		}
+	}
+
+	appendEvent := event == nil
+	if appendEvent {
+		eventID := state.NextEventID
+		if eventCount+1 > eventID {
+			eventID = eventCount + 1
+		}
+		event = &types.HistoryEvent{
+			EventID:   eventID,
+			EventType: types.EventTypeExecutionTerminated,
+			Timestamp: time.Now(),
+			Attributes: &types.ExecutionTerminatedAttributes{
+				Reason:   reason,
+				Identity: identity,
+			},
+		}
+	}
+
+	expectedVersion := state.DBVersion
+	wasRunning := state.ExecutionInfo != nil && state.ExecutionInfo.Status == types.ExecutionStatusRunning
+
+	// Apply directly to state, skipping engine validation which rejects
+	// close events for executions it does not consider running.
+	if err := state.ApplyEvent(event); err != nil {
+		return err
+	}
+
+	if appendEvent {
+		if err := s.eventStore.AppendEvents(ctx, key, []*types.HistoryEvent{event}, expectedVersion); err != nil {
+			return err
+		}
+		s.metrics.RecordEventRecorded(event.EventType)
+	}
+
+	state.DBVersion++
+
+	if err := s.stateStore.UpdateMutableState(ctx, key, state, expectedVersion); err != nil {
+		return err
+	}
+
+	// Force terminate may append to an already closed execution.
+	s.statsCache.remove(key)
+	s.historyPollers.notify(key, true)
+
+	if s.visibilityStore != nil {
+		s.recordVisibility(ctx, key, event, state)
+	}
+
+	if s.archiver != nil {
+		s.archiveExecution(ctx, key, event, state)
+	}
+
+	if s.executionCounter != nil && wasRunning && state.ExecutionInfo.ParentWorkflowID == "" {
+		s.releaseExecutionSlot(ctx, key)
+	}
+
+	if wasRunning {
+		s.applyParentClosePolicies(ctx, key, state)
+	}
+
+	return nil
+}
+
 func (s *Service) ListWorkflowExecutions(ctx context.Context, req *historyv1.ListWorkflowExecutionsRequest) (*historyv1.ListWorkflowExecutionsResponse, error) {
 	if s.visibilityStore == nil {
-		return nil, errors.New("visibility store not initialized")
+		return nil, ErrVisibilityNotConfigured
+	}
+	// Reject a query the store cannot apply before listing anything.
+	if _, err := visibility.CompileQuery(req.Query); err != nil {
+		return nil, err
 	}
 
 	visReq := &visibility.ListRequest{
@@ -710,15 +1839,32 @@ func (s *Service) ListWorkflowExecutions(ctx context.Context, req *historyv1.Lis
 		PageSize:      int(req.PageSize),
 		NextPageToken: req.NextPageToken,
 		Query:         req.Query,
+		Status:        req.Status,
 	}
 
-	resp, err := s.visibilityStore.ListOpenWorkflowExecutions(ctx, visReq)
+	var resp *visibility.ListResponse
+	var err error
+	switch listModeFor(req) {
+	case historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_CLOSED:
+		resp, err = s.visibilityStore.ListClosedWorkflowExecutions(ctx, visReq)
+	case historyv1.ExecutionStatusFilter_EXECUTION_STATUS_FILTER_ALL:
+		resp, err = s.listAllWorkflowExecutions(ctx, visReq)
+	default:
+		resp, err = s.visibilityStore.ListOpenWorkflowExecutions(ctx, visReq)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	executions := make([]*historyv1.WorkflowExecutionInfo, len(resp.Executions))
-	for i, exec := range resp.Executions {
+	return &historyv1.ListWorkflowExecutionsResponse{
+		Executions:    executionInfosToProto(resp.Executions),
+		NextPageToken: resp.NextPageToken,
+	}, nil
+}
+
+func executionInfosToProto(infos []*visibility.WorkflowExecutionInfo) []*historyv1.WorkflowExecutionInfo {
+	executions := make([]*historyv1.WorkflowExecutionInfo, len(infos))
+	for i, exec := range infos {
 		startProto := timestamppb.New(exec.StartTime)
 		closeProto := timestamppb.New(exec.CloseTime)
 
@@ -732,11 +1878,7 @@ func (s *Service) ListWorkflowExecutions(ctx context.Context, req *historyv1.Lis
 			Memo:          exec.Memo,
 		}
 	}
-
-	return &historyv1.ListWorkflowExecutionsResponse{
-		Executions:    executions,
-		NextPageToken: resp.NextPageToken,
-	}, nil
+	return executions
 }
 
 // GetHistoryPageRequest is the request for paginated history retrieval.
@@ -744,6 +1886,10 @@ type GetHistoryPageRequest struct {
 	Key       types.ExecutionKey
 	PageSize  int32
 	PageToken string // base64 encoded last event ID
+
+	// EventTypes optionally restricts the page to the given event types.
+	// When empty, all events are returned.
+	EventTypes []types.EventType
 }
 
 // GetHistoryPageResponse is the response for paginated history retrieval.
@@ -775,15 +1921,32 @@ func (s *Service) GetHistoryPage(ctx context.Context, req *GetHistoryPageRequest
 
 	// Fetch pageSize+1 events to determine if there's a next page
 	fetchSize := int64(req.PageSize) + 1
-	events, err := s.eventStore.GetEvents(ctx, req.Key, startEventID, startEventID+fetchSize-1)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get events: %w", err)
-	}
 
-	// Get total count
-	totalEvents, err := s.eventStore.GetEventCount(ctx, req.Key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get event count: %w", err)
+	var events []*types.HistoryEvent
+	var totalEvents int64
+	var err error
+
+	if len(req.EventTypes) > 0 {
+		events, err = s.eventStore.GetEventsByType(ctx, req.Key, req.EventTypes, startEventID, int(fetchSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events: %w", err)
+		}
+
+		totalEvents, err = s.eventStore.GetEventCountByType(ctx, req.Key, req.EventTypes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event count: %w", err)
+		}
+	} else {
+		events, err = s.eventStore.GetEvents(ctx, req.Key, startEventID, startEventID+fetchSize-1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events: %w", err)
+		}
+
+		// Get total count
+		totalEvents, err = s.eventStore.GetEventCount(ctx, req.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event count: %w", err)
+		}
 	}
 
 	resp := &GetHistoryPageResponse{
@@ -856,6 +2019,8 @@ func (s *Service) checkExecutionTimeouts(ctx context.Context) {
 			continue
 		}
 
+		s.checkAsyncActivityTimeouts(ctx, key, state)
+
 		if state.ExecutionInfo == nil || state.ExecutionInfo.ExecutionTimeout <= 0 {
 			continue
 		}
@@ -0,0 +1,445 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/shard"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestService returns a started history service that is stopped when the
+// test ends. ShardController, EventStore, StateStore and Logger default to a
+// single shard, in-memory stores and a discarded log when cfg leaves them unset.
+func newTestService(t *testing.T, cfg Config) *Service {
+	t.Helper()
+	if cfg.ShardController == nil {
+		cfg.ShardController = shard.NewController(1)
+	}
+	if cfg.EventStore == nil {
+		cfg.EventStore = store.NewMemoryEventStore()
+	}
+	if cfg.StateStore == nil {
+		cfg.StateStore = store.NewMemoryMutableStateStore()
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	svc := NewServiceWithConfig(cfg)
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { svc.Stop(context.Background()) })
+	return svc
+}
+
+func TestAppliedRequestsKeepOnlyTheNewest(t *testing.T) {
+	ctx := context.Background()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{StateStore: stateStore, MaxAppliedRequests: 2})
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	startArchivalTestExecution(t, svc, key)
+
+	signal := func(requestID string) bool {
+		t.Helper()
+		_, duplicate, err := svc.processEventsOnce(ctx, key, requestID, []*types.HistoryEvent{{
+			EventType:  types.EventTypeSignalReceived,
+			Attributes: &types.SignalReceivedAttributes{SignalName: requestID},
+		}})
+		if err != nil {
+			t.Fatalf("signal %s: %v", requestID, err)
+		}
+		return duplicate
+	}
+	for _, requestID := range []string{"req-1", "req-2", "req-3"} {
+		if signal(requestID) {
+			t.Fatalf("%s reported as duplicate", requestID)
+		}
+	}
+
+	state, err := stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if len(state.AppliedRequests) != 2 {
+		t.Fatalf("applied requests = %v, want the newest 2", state.AppliedRequests)
+	}
+	if !signal("req-3") {
+		t.Fatal("retry of a remembered request was applied again")
+	}
+	if signal("req-1") {
+		t.Fatal("forgotten request reported as duplicate")
+	}
+}
+
+func TestRespondActivityTaskCompletedDeduplicatesRequestID(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	pending, err := svc.RecordActivityTaskPending(ctx, &historyv1.RecordActivityTaskPendingRequest{
+		Namespace:         key.NamespaceID,
+		WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+		ScheduledEventId:  5,
+		NodeId:            "node-1",
+	})
+	if err != nil {
+		t.Fatalf("record pending: %v", err)
+	}
+
+	req := &historyv1.RespondActivityTaskCompletedRequest{
+		TaskToken: pending.TaskToken,
+		Result:    &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: []byte(`{"ok":true}`)}}},
+		RequestId: "activity/5",
+	}
+
+	first, err := svc.RespondActivityTaskCompleted(ctx, req)
+	if err != nil {
+		t.Fatalf("first completion: %v", err)
+	}
+	if first.Duplicate || first.EventId == 0 {
+		t.Fatalf("unexpected first response %+v", first)
+	}
+
+	// The worker retries after losing the acknowledgement.
+	second, err := svc.RespondActivityTaskCompleted(ctx, req)
+	if err != nil {
+		t.Fatalf("duplicate completion: %v", err)
+	}
+	if !second.Duplicate || second.EventId != first.EventId {
+		t.Fatalf("expected duplicate of event %d, got %+v", first.EventId, second)
+	}
+
+	completed, err := eventStore.GetEventCountByType(ctx, key, []types.EventType{types.EventTypeNodeCompleted})
+	if err != nil {
+		t.Fatalf("event count: %v", err)
+	}
+	if completed != 1 {
+		t.Fatalf("expected 1 completion event, got %d", completed)
+	}
+
+	// Without the request ID the retry is rejected rather than re-applied.
+	req.RequestId = ""
+	if _, err := svc.RespondActivityTaskCompleted(ctx, req); err == nil {
+		t.Fatal("expected completion without request ID to fail")
+	}
+}
+
+func TestResetExecutionRequiresResetPoint(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	eventTypes := []types.EventType{
+		types.EventTypeExecutionStarted,
+		types.EventTypeWorkflowTaskScheduled,
+		types.EventTypeWorkflowTaskStarted,
+		types.EventTypeWorkflowTaskCompleted,
+		types.EventTypeNodeScheduled,
+		types.EventTypeWorkflowTaskScheduled,
+		types.EventTypeWorkflowTaskStarted,
+		types.EventTypeWorkflowTaskCompleted,
+	}
+	events := make([]*types.HistoryEvent, len(eventTypes))
+	for i, eventType := range eventTypes {
+		events[i] = &types.HistoryEvent{EventID: int64(i + 1), EventType: eventType}
+	}
+	if err := eventStore.AppendEvents(ctx, key, events, 0); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	points, err := svc.ListResetPoints(ctx, key)
+	if err != nil {
+		t.Fatalf("ListResetPoints: %v", err)
+	}
+	if len(points) != 2 || points[0].EventID != 4 || points[1].EventID != 8 {
+		t.Fatalf("unexpected reset points %+v", points)
+	}
+	if points[1].Label != "after workflow task #2" {
+		t.Fatalf("label = %q", points[1].Label)
+	}
+
+	if _, err := svc.ResetExecution(ctx, key, "bad point", 5); !errors.Is(err, ErrInvalidResetPoint) {
+		t.Fatalf("expected ErrInvalidResetPoint, got %v", err)
+	}
+}
+
+func TestRespondActivityTaskCompletedRejectsClosedNode(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+	err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType: types.EventTypeNodeScheduled,
+		Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+			NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{
+				NodeId:    "fetch",
+				NodeType:  "http",
+				TaskQueue: &apiv1.TaskQueue{Name: "default"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("schedule node: %v", err)
+	}
+
+	complete := func(scheduledEventID int64, requestID string) error {
+		_, err := NewGRPCServer(svc).RespondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{
+			Namespace:         key.NamespaceID,
+			WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+			ScheduledEventId:  scheduledEventID,
+			RequestId:         requestID,
+		})
+		return err
+	}
+	if err := complete(1, "first"); err != nil {
+		t.Fatalf("first completion: %v", err)
+	}
+
+	// A redelivered task completes the node again under a new request ID,
+	// and a misrouted one names an event that never scheduled a node.
+	for _, scheduledEventID := range []int64{1, 42} {
+		err := complete(scheduledEventID, fmt.Sprintf("stale/%d", scheduledEventID))
+		st, _ := status.FromError(err)
+		if st.Code() != codes.FailedPrecondition {
+			t.Fatalf("scheduled event %d: expected FailedPrecondition, got %v", scheduledEventID, err)
+		}
+		var reason string
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				reason = info.GetReason()
+			}
+		}
+		if reason != ErrorReasonNodeNotPending {
+			t.Fatalf("scheduled event %d: reason = %q", scheduledEventID, reason)
+		}
+	}
+
+	completed, err := eventStore.GetEventCountByType(ctx, key, []types.EventType{types.EventTypeNodeCompleted})
+	if err != nil {
+		t.Fatalf("event count: %v", err)
+	}
+	if completed != 1 {
+		t.Fatalf("expected 1 completion event, got %d", completed)
+	}
+}
+
+func TestRespondWorkflowTaskCompletedRecordsVersionMarkerOnce(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore: eventStore,
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	respond := func(version int32) {
+		t.Helper()
+		_, err := svc.RespondWorkflowTaskCompleted(ctx, &historyv1.RespondWorkflowTaskCompletedRequest{
+			Namespace:         key.NamespaceID,
+			WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+			Commands: []*historyv1.Command{{
+				CommandType: historyv1.CommandType_COMMAND_TYPE_RECORD_VERSION_MARKER,
+				Attributes: &historyv1.Command_RecordVersionMarkerAttributes{
+					RecordVersionMarkerAttributes: &historyv1.RecordVersionMarkerCommandAttributes{
+						ChangeId: "retry-backoff",
+						Version:  version,
+					},
+				},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("respond workflow task completed: %v", err)
+		}
+	}
+	respond(2)
+	// A later decision for the same change must not override the first.
+	respond(3)
+
+	markers, err := eventStore.GetEventCountByType(ctx, key, []types.EventType{types.EventTypeMarkerRecorded})
+	if err != nil {
+		t.Fatalf("event count: %v", err)
+	}
+	if markers != 1 {
+		t.Fatalf("expected 1 marker event, got %d", markers)
+	}
+
+	desc, err := svc.DescribeWorkflowExecution(ctx, key)
+	if err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	if len(desc.VersionMarkers) != 1 || desc.VersionMarkers[0].ChangeID != "retry-backoff" || desc.VersionMarkers[0].Version != 2 {
+		t.Fatalf("version markers = %+v, want retry-backoff at version 2", desc.VersionMarkers)
+	}
+}
+
+func TestSetVariableCompletionUpdatesVariables(t *testing.T) {
+	ctx := context.Background()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-vars", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	complete := func(nodeID, nodeType, result string) {
+		t.Helper()
+		scheduled := &types.HistoryEvent{
+			EventType:  types.EventTypeNodeScheduled,
+			Attributes: &types.NodeScheduledAttributes{NodeID: nodeID, NodeType: nodeType},
+		}
+		if err := svc.processEvents(ctx, key, []*types.HistoryEvent{scheduled}); err != nil {
+			t.Fatalf("schedule %s: %v", nodeID, err)
+		}
+		completed := &types.HistoryEvent{
+			EventType: types.EventTypeNodeCompleted,
+			Attributes: &types.NodeCompletedAttributes{
+				NodeID:           nodeID,
+				ScheduledEventID: scheduled.EventID,
+				Result:           []byte(result),
+			},
+		}
+		if err := svc.processEvents(ctx, key, []*types.HistoryEvent{completed}); err != nil {
+			t.Fatalf("complete %s: %v", nodeID, err)
+		}
+	}
+
+	complete("set-count", types.NodeTypeSetVariable, `{"name":"count","value":1}`)
+	complete("set-count-again", types.NodeTypeSetVariable, `{"name":"count","value":2}`)
+	// Other nodes that happen to return the same shape do not set variables.
+	complete("http", "action_http_request", `{"name":"other","value":true}`)
+
+	state, err := stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if len(state.Variables) != 1 || string(state.Variables["count"]) != "2" {
+		t.Fatalf("variables = %v", state.Variables)
+	}
+}
+
+// cancelOnAppendEventStore cancels the caller's context once its events are
+// committed.
+type cancelOnAppendEventStore struct {
+	*store.MemoryEventStore
+	cancel context.CancelFunc
+}
+
+func (s *cancelOnAppendEventStore) AppendEvents(ctx context.Context, key types.ExecutionKey, events []*types.HistoryEvent, expectedVersion int64) error {
+	if err := s.MemoryEventStore.AppendEvents(ctx, key, events, expectedVersion); err != nil {
+		return err
+	}
+	s.cancel()
+	return nil
+}
+
+func TestDispatchSurvivesCallerCanceledAfterCommit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	matching := &backpressureMatchingClient{}
+	svc := newTestService(t, Config{
+		EventStore:     &cancelOnAppendEventStore{MemoryEventStore: store.NewMemoryEventStore(), cancel: cancel},
+		MatchingClient: matching,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+	_ = svc.processEvents(ctx, key, []*types.HistoryEvent{
+		{
+			EventType:  types.EventTypeExecutionStarted,
+			Timestamp:  time.Now(),
+			Attributes: &types.ExecutionStartedAttributes{WorkflowType: "order", TaskQueue: "default"},
+		},
+		{
+			EventType: types.EventTypeWorkflowTaskScheduled,
+			Timestamp: time.Now(),
+			Attributes: &historyv1.HistoryEvent_WorkflowTaskScheduledAttributes{
+				WorkflowTaskScheduledAttributes: &historyv1.WorkflowTaskScheduledEventAttributes{
+					TaskQueue: &apiv1.TaskQueue{Name: "default"},
+				},
+			},
+		},
+	})
+
+	if ctx.Err() == nil {
+		t.Fatal("caller context was not canceled by the commit")
+	}
+	if _, accepted := matching.snapshot(); len(accepted) != 1 {
+		t.Fatalf("dispatched %d tasks after the caller gave up, want the committed workflow task", len(accepted))
+	}
+}
@@ -2,6 +2,7 @@ package shard
 
 import (
 	"errors"
+	"sort"
 	"sync"
 
 	"github.com/linkflow/engine/internal/history/types"
@@ -111,3 +112,41 @@ func (c *Controller) isShardOwned(shardID int32) bool {
 	_, ok := c.shards[shardID]
 	return ok
 }
+
+// NumShards returns the total number of shards executions are hashed over.
+func (c *Controller) NumShards() int32 {
+	return c.numShards
+}
+
+// OwnedShards returns the IDs of the shards this host owns, in order.
+func (c *Controller) OwnedShards() []int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ids := make([]int32, 0, len(c.shards))
+	for id := range c.shards {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Rebalance acquires every shard this host should own but does not, and
+// returns the IDs it acquired. It does nothing unless the controller is
+// running.
+func (c *Controller) Rebalance() []int32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	acquired := []int32{}
+	if c.status != statusRunning {
+		return acquired
+	}
+	for i := int32(0); i < c.numShards; i++ {
+		if _, ok := c.shards[i]; !ok {
+			c.shards[i] = &ShardImpl{id: i}
+			acquired = append(acquired, i)
+		}
+	}
+	return acquired
+}
@@ -0,0 +1,49 @@
+package history
+
+import (
+	"context"
+	"fmt"
+)
+
+// ShardInfo describes one history shard.
+type ShardInfo struct {
+	ShardID           int32
+	Owned             bool
+	RunningExecutions int64
+}
+
+// DescribeShards reports every shard with whether this host owns it and how
+// many running executions hash to it.
+func (s *Service) DescribeShards(ctx context.Context) ([]ShardInfo, error) {
+	keys, err := s.stateStore.ListRunningExecutions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list running executions: %w", err)
+	}
+
+	shards := make([]ShardInfo, s.shardController.NumShards())
+	for i := range shards {
+		shards[i].ShardID = int32(i)
+	}
+	for _, id := range s.shardController.OwnedShards() {
+		if int(id) < len(shards) {
+			shards[id].Owned = true
+		}
+	}
+	for _, key := range keys {
+		id := s.shardController.GetShardIDForExecution(key)
+		if int(id) < len(shards) {
+			shards[id].RunningExecutions++
+		}
+	}
+	return shards, nil
+}
+
+// RebalanceShards acquires the shards this host is missing and returns their
+// IDs.
+func (s *Service) RebalanceShards() []int32 {
+	acquired := s.shardController.Rebalance()
+	if len(acquired) > 0 {
+		s.logger.Info("acquired shards in rebalance", "shards", acquired)
+	}
+	return acquired
+}
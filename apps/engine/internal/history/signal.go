@@ -0,0 +1,114 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// waitSignalBranch is the branch a node completed by its signal takes. Nodes
+// that time out with a timeout result take the branch that result names.
+const waitSignalBranch = "signal"
+
+// DefaultMaxBufferedSignals is the default limit on signals buffered for an
+// execution before new ones are rejected.
+const DefaultMaxBufferedSignals = 100
+
+// ErrSignalBufferFull is returned for a signal no node is waiting on when
+// the execution already buffers the maximum number of signals.
+var ErrSignalBufferFull = errors.New("execution signal buffer is full")
+
+// signalWaiterEvents returns a NodeCompleted event for every async activity
+// of key waiting on the received signal, in the order they were scheduled.
+// Each completes with the signal name and input, and "output" naming the
+// branch taken, as condition nodes report it. A signal nobody waits on is
+// buffered when applied, and rejected with ErrSignalBufferFull when the
+// buffer is at its limit.
+func (s *Service) signalWaiterEvents(ctx context.Context, key types.ExecutionKey, signal *types.SignalReceivedAttributes) ([]*types.HistoryEvent, error) {
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var waiters []*types.ActivityInfo
+	for _, ai := range state.PendingActivities {
+		if ai.AsyncNonce != "" && ai.WaitSignal != "" && ai.WaitSignal == signal.SignalName {
+			waiters = append(waiters, ai)
+		}
+	}
+	if len(waiters) == 0 {
+		if len(state.SignalBuffer) >= s.maxSignals {
+			return nil, ErrSignalBufferFull
+		}
+		return nil, nil
+	}
+	sort.Slice(waiters, func(i, j int) bool {
+		return waiters[i].ScheduledEventID < waiters[j].ScheduledEventID
+	})
+
+	result, err := signalResult(signal.SignalName, signal.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events := make([]*types.HistoryEvent, 0, len(waiters))
+	for _, ai := range waiters {
+		events = append(events, &types.HistoryEvent{
+			EventType: types.EventTypeNodeCompleted,
+			Timestamp: now,
+			Attributes: &types.NodeCompletedAttributes{
+				NodeID:           ai.ActivityID,
+				ScheduledEventID: ai.ScheduledEventID,
+				StartedEventID:   ai.StartedEventID,
+				Result:           result,
+			},
+		})
+	}
+	return events, nil
+}
+
+// bufferedSignalEvent returns a NodeCompleted event delivering the earliest
+// buffered signal the node starting in started waits on, or nil when none
+// has arrived yet.
+func bufferedSignalEvent(state *engine.MutableState, started *types.NodeStartedAttributes) (*types.HistoryEvent, error) {
+	if started.WaitSignal == "" {
+		return nil, nil
+	}
+	signal := state.NextBufferedSignal(started.WaitSignal)
+	if signal == nil {
+		return nil, nil
+	}
+
+	result, err := signalResult(signal.SignalName, signal.Input)
+	if err != nil {
+		return nil, err
+	}
+	return &types.HistoryEvent{
+		EventType: types.EventTypeNodeCompleted,
+		Timestamp: time.Now(),
+		Attributes: &types.NodeCompletedAttributes{
+			NodeID:           started.NodeID,
+			ScheduledEventID: started.ScheduledEventID,
+			Result:           result,
+			SignalEventID:    signal.EventID,
+		},
+	}, nil
+}
+
+func signalResult(name string, input []byte) ([]byte, error) {
+	return json.Marshal(struct {
+		Output     string          `json:"output"`
+		SignalName string          `json:"signal_name"`
+		Payload    json.RawMessage `json:"payload,omitempty"`
+	}{
+		Output:     waitSignalBranch,
+		SignalName: name,
+		Payload:    rawJSON(input),
+	})
+}
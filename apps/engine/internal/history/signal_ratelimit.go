@@ -0,0 +1,162 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// DefaultSignalRateLimitRefreshInterval is how often signal rate limits are
+// re-read from the dynamic config provider.
+const DefaultSignalRateLimitRefreshInterval = 30 * time.Second
+
+// minSignalLimiterSweep is the number of tracked executions below which idle
+// signal buckets are not swept.
+const minSignalLimiterSweep = 1024
+
+// ErrSignalRateLimited is returned for a signal sent to an execution faster
+// than its namespace's signal rate limit allows.
+var ErrSignalRateLimited = errors.New("execution signal rate limit exceeded")
+
+// DynamicConfigProvider resolves dynamic configuration by key, e.g. the
+// control plane.
+type DynamicConfigProvider interface {
+	GetConfig(ctx context.Context, key string) (json.RawMessage, error)
+}
+
+// signalRateLimiter keeps a token bucket per execution. Buckets are created
+// on an execution's first signal and dropped once they have refilled, since
+// a full bucket behaves like a new one.
+type signalRateLimiter struct {
+	mu        sync.Mutex
+	config    controlplane.SignalRateLimitConfig
+	buckets   map[types.ExecutionKey]*rate.Limiter
+	nextSweep int
+}
+
+func newSignalRateLimiter() *signalRateLimiter {
+	return &signalRateLimiter{
+		buckets:   make(map[types.ExecutionKey]*rate.Limiter),
+		nextSweep: minSignalLimiterSweep,
+	}
+}
+
+// setConfig replaces the limits. Executions start over with full buckets.
+func (l *signalRateLimiter) setConfig(cfg controlplane.SignalRateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = cfg
+	l.buckets = make(map[types.ExecutionKey]*rate.Limiter)
+	l.nextSweep = minSignalLimiterSweep
+}
+
+func (l *signalRateLimiter) currentConfig() controlplane.SignalRateLimitConfig {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.config
+}
+
+// allow reports whether key may accept a signal now, taking a token if so.
+func (l *signalRateLimiter) allow(key types.ExecutionKey) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, ok := l.config.Namespaces[key.NamespaceID]
+	if !ok {
+		limit = l.config.Default
+	}
+	if limit.SignalsPerSecond <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= l.nextSweep {
+			l.sweep(now)
+		}
+		burst := limit.BurstSize
+		if burst <= 0 {
+			burst = max(1, int(limit.SignalsPerSecond))
+		}
+		bucket = rate.NewLimiter(rate.Limit(limit.SignalsPerSecond), burst)
+		l.buckets[key] = bucket
+	}
+	return bucket.AllowN(now, 1)
+}
+
+// sweep drops the buckets that have refilled.
+func (l *signalRateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.TokensAt(now) >= float64(bucket.Burst()) {
+			delete(l.buckets, key)
+		}
+	}
+	l.nextSweep = max(minSignalLimiterSweep, 2*len(l.buckets))
+}
+
+// SetSignalRateLimits replaces the per-execution signal rate limits.
+func (s *Service) SetSignalRateLimits(cfg controlplane.SignalRateLimitConfig) {
+	s.signalLimits.setConfig(cfg)
+	s.logger.Info("signal rate limits updated",
+		slog.Float64("default_signals_per_second", cfg.Default.SignalsPerSecond),
+		slog.Int("namespaces", len(cfg.Namespaces)),
+	)
+}
+
+func (s *Service) startSignalRateLimitRefresher() {
+	if s.signalConfig == nil {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		s.refreshSignalRateLimits()
+		ticker := time.NewTicker(s.signalRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.refreshSignalRateLimits()
+			}
+		}
+	}()
+}
+
+func (s *Service) refreshSignalRateLimits() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.signalRefreshInterval)
+	defer cancel()
+
+	raw, err := s.signalConfig.GetConfig(ctx, controlplane.SignalRateLimitsConfigKey)
+	if err != nil {
+		if !errors.Is(err, controlplane.ErrConfigKeyNotFound) {
+			s.logger.Warn("failed to load signal rate limits", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	var cfg controlplane.SignalRateLimitConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		s.logger.Warn("invalid signal rate limits", slog.String("error", err.Error()))
+		return
+	}
+	// Unchanged limits keep the executions' buckets.
+	if reflect.DeepEqual(cfg, s.signalLimits.currentConfig()) {
+		return
+	}
+	s.SetSignalRateLimits(cfg)
+}
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type throttleCountingMetrics struct {
+	noopMetrics1
+	mu        sync.Mutex
+	throttled map[string]int
+}
+
+func (m *throttleCountingMetrics) RecordSignalThrottled(namespace string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.throttled[namespace]++
+}
+
+func TestSignalRateLimitPerExecution(t *testing.T) {
+	ctx := context.Background()
+	stateStore := store.NewMemoryMutableStateStore()
+	metrics := &throttleCountingMetrics{throttled: make(map[string]int)}
+	svc := newTestService(t, Config{
+		StateStore: stateStore,
+		Metrics:    metrics,
+		// The buffer outlasts the limiter, so the limiter is what rejects.
+		MaxBufferedSignals: 10,
+		SignalRateLimits: &controlplane.SignalRateLimitConfig{
+			Default: controlplane.SignalRateLimit{SignalsPerSecond: 0.001, BurstSize: 3},
+		},
+	})
+
+	signal := func(key types.ExecutionKey) error {
+		return svc.RecordEvent(ctx, key, &types.HistoryEvent{
+			EventType:  types.EventTypeSignalReceived,
+			Timestamp:  time.Now(),
+			Attributes: &types.SignalReceivedAttributes{SignalName: "ping"},
+		})
+	}
+	seed := func(key types.ExecutionKey) {
+		state := engine.NewMutableState(&types.ExecutionInfo{
+			NamespaceID: key.NamespaceID,
+			WorkflowID:  key.WorkflowID,
+			RunID:       key.RunID,
+			Status:      types.ExecutionStatusRunning,
+		})
+		if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+			t.Fatalf("seed state: %v", err)
+		}
+	}
+
+	busy := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-busy", RunID: "run-1"}
+	quiet := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-quiet", RunID: "run-1"}
+	seed(busy)
+	seed(quiet)
+
+	for i := 0; i < 3; i++ {
+		if err := signal(busy); err != nil {
+			t.Fatalf("signal %d within burst: %v", i, err)
+		}
+	}
+	err := signal(busy)
+	if !errors.Is(err, ErrSignalRateLimited) {
+		t.Fatalf("signal over the limit error = %v, want ErrSignalRateLimited", err)
+	}
+	if code := status.Code(NewGRPCServer(svc).toGRPCError(err)); code != codes.ResourceExhausted {
+		t.Fatalf("gRPC code = %s, want ResourceExhausted", code)
+	}
+
+	// Rejected signals are not buffered, and other executions keep their own
+	// budget.
+	state, err := stateStore.GetMutableState(ctx, busy)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if len(state.SignalBuffer) != 3 {
+		t.Fatalf("buffered %d signals, want 3", len(state.SignalBuffer))
+	}
+	if err := signal(quiet); err != nil {
+		t.Fatalf("signal to another execution: %v", err)
+	}
+	if metrics.throttled["default"] != 1 {
+		t.Fatalf("throttled count = %v, want 1 for default", metrics.throttled)
+	}
+
+	// A namespace override lifts the limit.
+	svc.SetSignalRateLimits(controlplane.SignalRateLimitConfig{
+		Default:    controlplane.SignalRateLimit{SignalsPerSecond: 0.001, BurstSize: 3},
+		Namespaces: map[string]controlplane.SignalRateLimit{"default": {}},
+	})
+	for i := 0; i < 5; i++ {
+		if err := signal(busy); err != nil {
+			t.Fatalf("unlimited signal %d: %v", i, err)
+		}
+	}
+}
+
+func TestSignalRateLimiterSweepsRefilledBuckets(t *testing.T) {
+	l := newSignalRateLimiter()
+	l.setConfig(controlplane.SignalRateLimitConfig{
+		Default: controlplane.SignalRateLimit{SignalsPerSecond: 1000, BurstSize: 1},
+	})
+	for i := 0; i < 3*minSignalLimiterSweep; i++ {
+		l.allow(types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf", RunID: fmt.Sprintf("run-%d", i)})
+		// Let each bucket refill before the next sweep.
+		if i%minSignalLimiterSweep == minSignalLimiterSweep-1 {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	if len(l.buckets) > 2*minSignalLimiterSweep {
+		t.Fatalf("tracking %d executions, expected refilled buckets to be swept", len(l.buckets))
+	}
+}
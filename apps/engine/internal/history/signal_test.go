@@ -0,0 +1,166 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestSignalCompletesWaitingActivities(t *testing.T) {
+	ctx := context.Background()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		StateStore: stateStore,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+		Status:      types.ExecutionStatusRunning,
+	})
+	if err := stateStore.UpdateMutableState(ctx, key, state, 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	wait := func(scheduledEventID int64, nodeID, signalName string, timeout time.Duration, timeoutResult string) {
+		req := &historyv1.RecordActivityTaskPendingRequest{
+			Namespace:              key.NamespaceID,
+			WorkflowExecution:      &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+			ScheduledEventId:       scheduledEventID,
+			NodeId:                 nodeID,
+			ScheduleToCloseTimeout: durationpb.New(timeout),
+			WaitSignalName:         signalName,
+		}
+		if timeoutResult != "" {
+			req.TimeoutResult = &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: []byte(timeoutResult)}}}
+		}
+		if _, err := svc.RecordActivityTaskPending(ctx, req); err != nil {
+			t.Fatalf("record pending %s: %v", nodeID, err)
+		}
+	}
+	wait(5, "approve", "approved", time.Hour, "")
+	wait(6, "escalate", "escalated", time.Nanosecond, `{"output":"timeout","timed_out":true}`)
+
+	err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType:  types.EventTypeSignalReceived,
+		Timestamp:  time.Now(),
+		Attributes: &types.SignalReceivedAttributes{SignalName: "approved", Input: []byte(`{"by":"ann"}`)},
+	})
+	if err != nil {
+		t.Fatalf("record signal: %v", err)
+	}
+
+	state, err = stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	result := state.CompletedNodes["approve"]
+	if result == nil || string(result.Output) != `{"output":"signal","signal_name":"approved","payload":{"by":"ann"}}` {
+		t.Fatalf("approve result = %+v, want the signal payload", result)
+	}
+	if _, ok := state.PendingActivities[6]; !ok {
+		t.Fatal("escalate completed by a signal it was not waiting on")
+	}
+
+	// The escalation times out and takes its timeout result instead of failing.
+	svc.checkAsyncActivityTimeouts(ctx, key, state)
+	state, err = stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	result = state.CompletedNodes["escalate"]
+	if result == nil || result.FailureReason != "" || string(result.Output) != `{"output":"timeout","timed_out":true}` {
+		t.Fatalf("escalate result = %+v, want the timeout result", result)
+	}
+}
+
+func TestSignalBufferKeepsArrivalOrderOnReplay(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewMemoryEventStore()
+	stateStore := store.NewMemoryMutableStateStore()
+	svc := newTestService(t, Config{
+		EventStore:         eventStore,
+		StateStore:         stateStore,
+		MaxBufferedSignals: 3,
+	})
+
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "wf-1", RunID: "run-1"}
+	newState := func() *engine.MutableState {
+		return engine.NewMutableState(&types.ExecutionInfo{
+			NamespaceID: key.NamespaceID,
+			WorkflowID:  key.WorkflowID,
+			RunID:       key.RunID,
+			Status:      types.ExecutionStatusRunning,
+		})
+	}
+	if err := stateStore.UpdateMutableState(ctx, key, newState(), 0); err != nil {
+		t.Fatalf("seed state: %v", err)
+	}
+
+	signal := func(name, input string) error {
+		return svc.RecordEvent(ctx, key, &types.HistoryEvent{
+			EventType:  types.EventTypeSignalReceived,
+			Timestamp:  time.Now(),
+			Attributes: &types.SignalReceivedAttributes{SignalName: name, Input: []byte(input)},
+		})
+	}
+	for _, s := range []struct{ name, input string }{{"approved", `1`}, {"escalated", `2`}, {"approved", `3`}} {
+		if err := signal(s.name, s.input); err != nil {
+			t.Fatalf("signal %s: %v", s.name, err)
+		}
+	}
+	if err := signal("approved", `4`); !errors.Is(err, ErrSignalBufferFull) {
+		t.Fatalf("signal beyond the buffer limit error = %v, want ErrSignalBufferFull", err)
+	}
+
+	// A node that starts waiting takes the earliest matching buffered signal.
+	_, err := svc.RecordActivityTaskPending(ctx, &historyv1.RecordActivityTaskPendingRequest{
+		Namespace:         key.NamespaceID,
+		WorkflowExecution: &commonv1.WorkflowExecution{WorkflowId: key.WorkflowID, RunId: key.RunID},
+		ScheduledEventId:  10,
+		NodeId:            "approve",
+		WaitSignalName:    "approved",
+	})
+	if err != nil {
+		t.Fatalf("record pending: %v", err)
+	}
+	live, err := stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if result := live.CompletedNodes["approve"]; result == nil || string(result.Output) != `{"output":"signal","signal_name":"approved","payload":1}` {
+		t.Fatalf("approve result = %+v, want the first approved signal", result)
+	}
+
+	events, err := eventStore.GetEvents(ctx, key, 1, live.NextEventID-1)
+	if err != nil {
+		t.Fatalf("get events: %v", err)
+	}
+	replayed := newState()
+	for _, event := range events {
+		if err := replayed.ApplyEvent(event); err != nil {
+			t.Fatalf("apply event %d: %v", event.EventID, err)
+		}
+	}
+
+	var got []string
+	for i, s := range replayed.SignalBuffer {
+		got = append(got, s.SignalName+"="+string(s.Input))
+		if s.EventID != live.SignalBuffer[i].EventID {
+			t.Fatalf("replayed signal %d = %+v, live %+v", i, s, live.SignalBuffer[i])
+		}
+	}
+	if len(got) != 2 || got[0] != "escalated=2" || got[1] != "approved=3" || len(live.SignalBuffer) != 2 {
+		t.Fatalf("replayed buffer = %v, want [escalated=2 approved=3]", got)
+	}
+}
@@ -0,0 +1,245 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// ErrEventIDOutOfRange is returned when a state lookup targets an event the
+// execution has not recorded.
+var ErrEventIDOutOfRange = errors.New("event ID out of range")
+
+// StateAtEvent is an execution's mutable state as it was right after an
+// event was applied.
+type StateAtEvent struct {
+	EventID int64
+	Event   *types.HistoryEvent
+	State   *engine.MutableState
+	// SnapshotEventID is the last event of the snapshot the replay started
+	// from, or 0 when it replayed from the first event.
+	SnapshotEventID int64
+}
+
+// DescribeStateAtEvent rebuilds the mutable state of an execution as of
+// eventID by replaying its history into a fresh state, starting from the
+// latest snapshot taken before that event when one exists. It is read-only:
+// nothing is persisted and no tasks are dispatched.
+func (s *Service) DescribeStateAtEvent(ctx context.Context, key types.ExecutionKey, eventID int64) (*StateAtEvent, error) {
+	current, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if lastEventID := current.NextEventID - 1; eventID < 1 || eventID > lastEventID {
+		return nil, fmt.Errorf("%w: %d is not between 1 and %d", ErrEventIDOutOfRange, eventID, lastEventID)
+	}
+
+	result := &StateAtEvent{EventID: eventID}
+	state := engine.NewMutableState(&types.ExecutionInfo{
+		NamespaceID: key.NamespaceID,
+		WorkflowID:  key.WorkflowID,
+		RunID:       key.RunID,
+	})
+	if s.snapshotStore != nil {
+		snapshot, err := s.snapshotStore.GetLatestSnapshot(ctx, key)
+		if err != nil {
+			s.logger.Debug("no snapshot for state replay", "error", err, "workflow_id", key.WorkflowID)
+		} else if snapshot != nil && snapshot.State != nil && snapshot.LastEventID < eventID {
+			state = snapshot.State.Clone()
+			result.SnapshotEventID = snapshot.LastEventID
+		}
+	}
+
+	firstEventID := result.SnapshotEventID + 1
+	events, err := s.eventStore.GetEvents(ctx, key, firstEventID, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
+	}
+	if int64(len(events)) != eventID-firstEventID+1 {
+		return nil, fmt.Errorf("history is missing events between %d and %d", firstEventID, eventID)
+	}
+
+	for _, event := range events {
+		if err := state.ApplyEvent(event); err != nil {
+			return nil, fmt.Errorf("failed to replay event %d: %w", event.EventID, err)
+		}
+	}
+
+	result.Event = events[len(events)-1]
+	result.State = state
+	return result, nil
+}
+
+// stateView is the JSON form of a replayed mutable state.
+type stateView struct {
+	EventID           int64                      `json:"event_id"`
+	EventType         string                     `json:"event_type"`
+	EventTime         time.Time                  `json:"event_time"`
+	SnapshotEventID   int64                      `json:"snapshot_event_id,omitempty"`
+	Execution         executionView              `json:"execution"`
+	NextEventID       int64                      `json:"next_event_id"`
+	PendingNodes      []types.PendingNodeInfo    `json:"pending_nodes"`
+	PendingActivities []activityView             `json:"pending_activities"`
+	PendingTimers     []types.TimerInfo          `json:"pending_timers"`
+	PendingChildren   []types.ChildExecutionInfo `json:"pending_children"`
+	CompletedNodes    map[string]nodeResultView  `json:"completed_nodes"`
+	VersionMarkers    []types.VersionMarker      `json:"version_markers"`
+	BufferedSignals   []signalView               `json:"buffered_signals"`
+	Variables         map[string]json.RawMessage `json:"variables"`
+}
+
+type executionView struct {
+	WorkflowType string          `json:"workflow_type"`
+	TaskQueue    string          `json:"task_queue"`
+	Status       string          `json:"status"`
+	Input        json.RawMessage `json:"input,omitempty"`
+	StartTime    time.Time       `json:"start_time"`
+	CloseTime    *time.Time      `json:"close_time,omitempty"`
+}
+
+type activityView struct {
+	ScheduledEventID int64     `json:"scheduled_event_id"`
+	StartedEventID   int64     `json:"started_event_id,omitempty"`
+	ActivityID       string    `json:"activity_id"`
+	ActivityType     string    `json:"activity_type,omitempty"`
+	Attempt          int32     `json:"attempt"`
+	StartedTime      time.Time `json:"started_time"`
+	Async            bool      `json:"async"`
+}
+
+type signalView struct {
+	EventID      int64           `json:"event_id"`
+	SignalName   string          `json:"signal_name"`
+	Input        json.RawMessage `json:"input,omitempty"`
+	ReceivedTime time.Time       `json:"received_time"`
+}
+
+type nodeResultView struct {
+	CompletedTime time.Time       `json:"completed_time"`
+	Output        json.RawMessage `json:"output,omitempty"`
+	FailureReason string          `json:"failure_reason,omitempty"`
+}
+
+// JSON renders the replayed state: the execution, its pending nodes,
+// activities, timers and children, the outputs of the nodes completed by then,
+// the recorded version markers, the signals still buffered and the workflow
+// variables. Lists are ordered by event ID.
+func (r *StateAtEvent) JSON() ([]byte, error) {
+	state := r.State
+	view := stateView{
+		EventID:           r.EventID,
+		EventType:         r.Event.EventType.String(),
+		EventTime:         r.Event.Timestamp,
+		SnapshotEventID:   r.SnapshotEventID,
+		NextEventID:       state.NextEventID,
+		PendingNodes:      []types.PendingNodeInfo{},
+		PendingActivities: []activityView{},
+		PendingTimers:     []types.TimerInfo{},
+		PendingChildren:   []types.ChildExecutionInfo{},
+		CompletedNodes:    make(map[string]nodeResultView, len(state.CompletedNodes)),
+		VersionMarkers:    []types.VersionMarker{},
+		BufferedSignals:   []signalView{},
+		Variables:         make(map[string]json.RawMessage, len(state.Variables)),
+	}
+
+	if info := state.ExecutionInfo; info != nil {
+		view.Execution = executionView{
+			WorkflowType: info.WorkflowTypeName,
+			TaskQueue:    info.TaskQueue,
+			Status:       commonv1.ExecutionStatus(info.Status).String(),
+			Input:        rawJSON(info.Input),
+			StartTime:    info.StartTime,
+		}
+		if !info.CloseTime.IsZero() {
+			closeTime := info.CloseTime
+			view.Execution.CloseTime = &closeTime
+		}
+	}
+
+	for _, node := range state.PendingNodes {
+		view.PendingNodes = append(view.PendingNodes, *node)
+	}
+	sort.Slice(view.PendingNodes, func(i, j int) bool {
+		return view.PendingNodes[i].ScheduledEventID < view.PendingNodes[j].ScheduledEventID
+	})
+
+	for _, ai := range state.PendingActivities {
+		view.PendingActivities = append(view.PendingActivities, activityView{
+			ScheduledEventID: ai.ScheduledEventID,
+			StartedEventID:   ai.StartedEventID,
+			ActivityID:       ai.ActivityID,
+			ActivityType:     ai.ActivityType,
+			Attempt:          ai.Attempt,
+			StartedTime:      ai.StartedTime,
+			Async:            ai.AsyncNonce != "",
+		})
+	}
+	sort.Slice(view.PendingActivities, func(i, j int) bool {
+		return view.PendingActivities[i].ScheduledEventID < view.PendingActivities[j].ScheduledEventID
+	})
+
+	for _, timer := range state.PendingTimers {
+		view.PendingTimers = append(view.PendingTimers, *timer)
+	}
+	sort.Slice(view.PendingTimers, func(i, j int) bool {
+		return view.PendingTimers[i].StartedEventID < view.PendingTimers[j].StartedEventID
+	})
+
+	for _, child := range state.PendingChildren {
+		view.PendingChildren = append(view.PendingChildren, *child)
+	}
+	sort.Slice(view.PendingChildren, func(i, j int) bool {
+		return view.PendingChildren[i].StartedEventID < view.PendingChildren[j].StartedEventID
+	})
+
+	for nodeID, result := range state.CompletedNodes {
+		view.CompletedNodes[nodeID] = nodeResultView{
+			CompletedTime: result.CompletedTime,
+			Output:        rawJSON(result.Output),
+			FailureReason: result.FailureReason,
+		}
+	}
+
+	for _, marker := range state.VersionMarkers {
+		view.VersionMarkers = append(view.VersionMarkers, *marker)
+	}
+	sort.Slice(view.VersionMarkers, func(i, j int) bool {
+		return view.VersionMarkers[i].EventID < view.VersionMarkers[j].EventID
+	})
+
+	// The buffer is already in arrival order.
+	for _, signal := range state.SignalBuffer {
+		view.BufferedSignals = append(view.BufferedSignals, signalView{
+			EventID:      signal.EventID,
+			SignalName:   signal.SignalName,
+			Input:        rawJSON(signal.Input),
+			ReceivedTime: signal.ReceivedTime,
+		})
+	}
+
+	for name, value := range state.Variables {
+		view.Variables[name] = rawJSON(value)
+	}
+
+	return json.Marshal(view)
+}
+
+// rawJSON returns data as raw JSON, quoting it as a string when it is not
+// valid JSON.
+func rawJSON(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	if json.Valid(data) {
+		return data
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
@@ -0,0 +1,234 @@
+package history
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// DefaultStatsCacheSize bounds how many closed-execution stats are cached.
+const DefaultStatsCacheSize = 10000
+
+// statsScanPageSize is the number of node events read per store call.
+const statsScanPageSize = 1000
+
+var nodeEventTypes = []types.EventType{
+	types.EventTypeNodeScheduled,
+	types.EventTypeNodeStarted,
+	types.EventTypeNodeCompleted,
+	types.EventTypeNodeFailed,
+	types.EventTypeNodeTimedOut,
+}
+
+// NodeStatusCounts counts scheduled node attempts by their latest status.
+type NodeStatusCounts struct {
+	Scheduled int64
+	Running   int64
+	Completed int64
+	Failed    int64
+	TimedOut  int64
+}
+
+// ExecutionStats summarizes a workflow execution.
+type ExecutionStats struct {
+	Key        types.ExecutionKey
+	Status     types.ExecutionStatus
+	StartTime  time.Time
+	CloseTime  time.Time
+	Duration   time.Duration
+	EventCount int64
+	NodeCounts NodeStatusCounts
+}
+
+// DescribeExecutionStats returns timing, event and node statistics for an
+// execution, resolving an empty key.RunID to the workflow's current run.
+// Stats for closed executions are cached by their resolved key since their
+// history no longer changes.
+func (s *Service) DescribeExecutionStats(ctx context.Context, key types.ExecutionKey) (*ExecutionStats, error) {
+	if key.RunID == "" && s.visibilityStore != nil {
+		runID, err := s.visibilityStore.GetCurrentRunID(ctx, key.NamespaceID, key.WorkflowID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve current run: %w", err)
+		}
+		if runID == "" {
+			return nil, types.ErrExecutionNotFound
+		}
+		key.RunID = runID
+	}
+
+	if stats, ok := s.statsCache.get(key); ok {
+		return stats, nil
+	}
+
+	state, err := s.stateStore.GetMutableState(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	eventCount, err := s.eventStore.GetEventCount(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event count: %w", err)
+	}
+
+	nodeCounts, err := s.countNodeStatuses(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ExecutionStats{
+		Key:        key,
+		EventCount: eventCount,
+		NodeCounts: nodeCounts,
+	}
+
+	closed := false
+	if info := state.ExecutionInfo; info != nil {
+		stats.Status = info.Status
+		stats.StartTime = info.StartTime
+		stats.CloseTime = info.CloseTime
+		closed = info.Status != types.ExecutionStatusRunning && info.Status != types.ExecutionStatusUnspecified
+	}
+
+	switch {
+	case stats.StartTime.IsZero():
+	case closed && !stats.CloseTime.IsZero():
+		stats.Duration = stats.CloseTime.Sub(stats.StartTime)
+	default:
+		stats.Duration = time.Since(stats.StartTime)
+	}
+
+	if closed && key.RunID != "" {
+		s.statsCache.put(key, stats)
+	}
+
+	return stats, nil
+}
+
+// countNodeStatuses scans the node events of an execution once and counts each
+// scheduled attempt by the last status it reached.
+func (s *Service) countNodeStatuses(ctx context.Context, key types.ExecutionKey) (NodeStatusCounts, error) {
+	latest := make(map[int64]types.EventType)
+
+	firstEventID := int64(1)
+	for {
+		events, err := s.eventStore.GetEventsByType(ctx, key, nodeEventTypes, firstEventID, statsScanPageSize)
+		if err != nil {
+			return NodeStatusCounts{}, fmt.Errorf("failed to get node events: %w", err)
+		}
+
+		for _, event := range events {
+			scheduledID := nodeScheduledEventID(event)
+			if scheduledID == 0 {
+				continue
+			}
+			latest[scheduledID] = event.EventType
+		}
+
+		if len(events) < statsScanPageSize {
+			break
+		}
+		firstEventID = events[len(events)-1].EventID + 1
+	}
+
+	var counts NodeStatusCounts
+	for _, eventType := range latest {
+		switch eventType {
+		case types.EventTypeNodeScheduled:
+			counts.Scheduled++
+		case types.EventTypeNodeStarted:
+			counts.Running++
+		case types.EventTypeNodeCompleted:
+			counts.Completed++
+		case types.EventTypeNodeFailed:
+			counts.Failed++
+		case types.EventTypeNodeTimedOut:
+			counts.TimedOut++
+		}
+	}
+	return counts, nil
+}
+
+// nodeScheduledEventID returns the ID of the NodeScheduled event a node event
+// belongs to, or 0 if it cannot be determined.
+func nodeScheduledEventID(event *types.HistoryEvent) int64 {
+	if event.EventType == types.EventTypeNodeScheduled {
+		return event.EventID
+	}
+
+	switch attrs := event.Attributes.(type) {
+	case *types.NodeStartedAttributes:
+		return attrs.ScheduledEventID
+	case *types.NodeCompletedAttributes:
+		return attrs.ScheduledEventID
+	case *types.NodeFailedAttributes:
+		return attrs.ScheduledEventID
+	case *historyv1.HistoryEvent_NodeStartedAttributes:
+		return attrs.NodeStartedAttributes.GetScheduledEventId()
+	case *historyv1.HistoryEvent_NodeCompletedAttributes:
+		return attrs.NodeCompletedAttributes.GetScheduledEventId()
+	case *historyv1.HistoryEvent_NodeFailedAttributes:
+		return attrs.NodeFailedAttributes.GetScheduledEventId()
+	case *historyv1.HistoryEvent_NodeTimedOutAttributes:
+		return attrs.NodeTimedOutAttributes.GetScheduledEventId()
+	}
+	return 0
+}
+
+// executionStatsCache is a bounded FIFO cache of closed-execution stats.
+type executionStatsCache struct {
+	mu      sync.Mutex
+	maxSize int
+	entries map[types.ExecutionKey]*list.Element
+	order   *list.List
+}
+
+func newExecutionStatsCache(maxSize int) *executionStatsCache {
+	return &executionStatsCache{
+		maxSize: maxSize,
+		entries: make(map[types.ExecutionKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *executionStatsCache) get(key types.ExecutionKey) (*ExecutionStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return elem.Value.(*ExecutionStats), true
+}
+
+func (c *executionStatsCache) put(key types.ExecutionKey, stats *ExecutionStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = stats
+		return
+	}
+
+	for c.order.Len() >= c.maxSize {
+		oldest := c.order.Front()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*ExecutionStats).Key)
+	}
+	c.entries[key] = c.order.PushBack(stats)
+}
+
+func (c *executionStatsCache) remove(key types.ExecutionKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
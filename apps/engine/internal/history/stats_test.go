@@ -0,0 +1,93 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"github.com/linkflow/engine/internal/history/visibility"
+)
+
+// currentRunVisibilityStore resolves workflows to their current run.
+type currentRunVisibilityStore struct {
+	visibility.Store
+	currentRuns map[string]string
+}
+
+func (m *currentRunVisibilityStore) GetCurrentRunID(_ context.Context, _, workflowID string) (string, error) {
+	return m.currentRuns[workflowID], nil
+}
+
+// countingNodeEventStore counts the node event scans stats computations make.
+type countingNodeEventStore struct {
+	*store.MemoryEventStore
+	scans int
+}
+
+func (s *countingNodeEventStore) GetEventsByType(ctx context.Context, key types.ExecutionKey, eventTypes []types.EventType, firstEventID int64, limit int) ([]*types.HistoryEvent, error) {
+	s.scans++
+	return s.MemoryEventStore.GetEventsByType(ctx, key, eventTypes, firstEventID, limit)
+}
+
+func TestDescribeExecutionStatsCachesResolvedRuns(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		wantErr   error
+		wantScans int
+	}{
+		{name: "explicit run", requestID: "run-2", wantScans: 1},
+		{name: "current run", requestID: "", wantScans: 1},
+		{name: "unknown run", requestID: "run-1", wantErr: types.ErrExecutionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			eventStore := &countingNodeEventStore{MemoryEventStore: store.NewMemoryEventStore()}
+			stateStore := store.NewMemoryMutableStateStore()
+			svc := newTestService(t, Config{
+				EventStore:      eventStore,
+				StateStore:      stateStore,
+				VisibilityStore: &currentRunVisibilityStore{currentRuns: map[string]string{"order": "run-2"}},
+			})
+
+			start := time.Now().Add(-time.Minute)
+			closed := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-2"}
+			state := engine.NewMutableState(&types.ExecutionInfo{
+				NamespaceID: closed.NamespaceID,
+				WorkflowID:  closed.WorkflowID,
+				RunID:       closed.RunID,
+				Status:      types.ExecutionStatusCompleted,
+				StartTime:   start,
+				CloseTime:   start.Add(30 * time.Second),
+			})
+			if err := stateStore.UpdateMutableState(ctx, closed, state, 0); err != nil {
+				t.Fatalf("seed state: %v", err)
+			}
+
+			key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: tt.requestID}
+			for i := 0; i < 2; i++ {
+				stats, err := svc.DescribeExecutionStats(ctx, key)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("DescribeExecutionStats error = %v, want %v", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("DescribeExecutionStats: %v", err)
+				}
+				if stats.Key != closed || stats.Duration != 30*time.Second {
+					t.Fatalf("stats = %+v, want run-2 lasting 30s", stats)
+				}
+			}
+			if eventStore.scans != tt.wantScans {
+				t.Fatalf("node event scans = %d, want %d (second call served from cache)", eventStore.scans, tt.wantScans)
+			}
+		})
+	}
+}
@@ -11,10 +11,14 @@ type EventStore interface {
 	AppendEvents(ctx context.Context, key types.ExecutionKey, events []*types.HistoryEvent, expectedVersion int64) error
 	GetEvents(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64) ([]*types.HistoryEvent, error)
 	GetEventCount(ctx context.Context, key types.ExecutionKey) (int64, error)
+	GetEventsByType(ctx context.Context, key types.ExecutionKey, eventTypes []types.EventType, firstEventID int64, limit int) ([]*types.HistoryEvent, error)
+	GetEventCountByType(ctx context.Context, key types.ExecutionKey, eventTypes []types.EventType) (int64, error)
+	DeleteEvents(ctx context.Context, key types.ExecutionKey) error
 }
 
 type MutableStateStore interface {
 	GetMutableState(ctx context.Context, key types.ExecutionKey) (*engine.MutableState, error)
 	UpdateMutableState(ctx context.Context, key types.ExecutionKey, state *engine.MutableState, expectedVersion int64) error
 	ListRunningExecutions(ctx context.Context) ([]types.ExecutionKey, error)
+	DeleteMutableState(ctx context.Context, key types.ExecutionKey) error
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/events"
 	"github.com/linkflow/engine/internal/history/types"
 )
 
@@ -16,25 +18,79 @@ func keyToString(key types.ExecutionKey) executionKeyString {
 }
 
 type MemoryEventStore struct {
-	mu     sync.RWMutex
-	events map[executionKeyString][]*types.HistoryEvent
+	mu        sync.RWMutex
+	events    map[executionKeyString][]*types.HistoryEvent
+	hashes    map[executionKeyString]map[int64]types.EventHash
+	hashChain map[string]bool
 }
 
 func NewMemoryEventStore() *MemoryEventStore {
 	return &MemoryEventStore{
 		events: make(map[executionKeyString][]*types.HistoryEvent),
+		hashes: make(map[executionKeyString]map[int64]types.EventHash),
 	}
 }
 
-func (s *MemoryEventStore) AppendEvents(ctx context.Context, key types.ExecutionKey, events []*types.HistoryEvent, expectedVersion int64) error {
+// SetHashChainNamespaces makes the events of the given namespaces carry a
+// hash chain, computed on append.
+func (s *MemoryEventStore) SetHashChainNamespaces(namespaces []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hashChain = make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		s.hashChain[ns] = true
+	}
+}
+
+func (s *MemoryEventStore) AppendEvents(ctx context.Context, key types.ExecutionKey, evts []*types.HistoryEvent, expectedVersion int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	k := keyToString(key)
-	s.events[k] = append(s.events[k], events...)
+	if s.hashChain[key.NamespaceID] && len(evts) > 0 {
+		if s.hashes[k] == nil {
+			s.hashes[k] = make(map[int64]types.EventHash)
+		}
+		var prevHash []byte
+		if n := len(s.events[k]); n > 0 {
+			prevHash = s.hashes[k][s.events[k][n-1].EventID].Hash
+		}
+		for _, event := range evts {
+			hash, err := events.ChainHash(prevHash, event)
+			if err != nil {
+				return fmt.Errorf("failed to hash event %d: %w", event.EventID, err)
+			}
+			s.hashes[k][event.EventID] = types.EventHash{EventID: event.EventID, PrevHash: prevHash, Hash: hash}
+			prevHash = hash
+		}
+	}
+	s.events[k] = append(s.events[k], evts...)
 	return nil
 }
 
+// GetEventHashes returns the hash chain links of an execution's events
+// within the specified range. Events written without a hash have empty
+// links.
+func (s *MemoryEventStore) GetEventHashes(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64) ([]types.EventHash, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	k := keyToString(key)
+	var result []types.EventHash
+	for _, e := range s.events[k] {
+		if e.EventID < firstEventID || e.EventID > lastEventID {
+			continue
+		}
+		h, ok := s.hashes[k][e.EventID]
+		if !ok {
+			h = types.EventHash{EventID: e.EventID}
+		}
+		result = append(result, h)
+	}
+	return result, nil
+}
+
 func (s *MemoryEventStore) GetEvents(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64) ([]*types.HistoryEvent, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -60,6 +116,78 @@ func (s *MemoryEventStore) GetEventCount(ctx context.Context, key types.Executio
 	return int64(len(s.events[k])), nil
 }
 
+func (s *MemoryEventStore) GetEventsByType(ctx context.Context, key types.ExecutionKey, eventTypes []types.EventType, firstEventID int64, limit int) ([]*types.HistoryEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	k := keyToString(key)
+	var result []*types.HistoryEvent
+
+	for _, e := range s.events[k] {
+		if e.EventID < firstEventID || !containsEventType(eventTypes, e.EventType) {
+			continue
+		}
+		result = append(result, e)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (s *MemoryEventStore) GetEventCountByType(ctx context.Context, key types.ExecutionKey, eventTypes []types.EventType) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, e := range s.events[keyToString(key)] {
+		if containsEventType(eventTypes, e.EventType) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *MemoryEventStore) DeleteEvents(ctx context.Context, key types.ExecutionKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.events, keyToString(key))
+	delete(s.hashes, keyToString(key))
+	return nil
+}
+
+// TrimEvents deletes the events between firstEventID and lastEventID,
+// inclusive, recorded before olderThan. It returns how many were deleted.
+func (s *MemoryEventStore) TrimEvents(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64, olderThan time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := keyToString(key)
+	kept := s.events[k][:0]
+	var trimmed int64
+	for _, e := range s.events[k] {
+		if e.EventID >= firstEventID && e.EventID <= lastEventID && e.Timestamp.Before(olderThan) {
+			delete(s.hashes[k], e.EventID)
+			trimmed++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	s.events[k] = kept
+	return trimmed, nil
+}
+
+func containsEventType(eventTypes []types.EventType, eventType types.EventType) bool {
+	for _, et := range eventTypes {
+		if et == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 type MemoryMutableStateStore struct {
 	mu     sync.RWMutex
 	states map[executionKeyString]*engine.MutableState
@@ -78,10 +206,7 @@ func (s *MemoryMutableStateStore) GetMutableState(ctx context.Context, key types
 	k := keyToString(key)
 	state, ok := s.states[k]
 	if !ok {
-		// Return specific error if desired, or let caller handle it.
-		// Service expects ErrExecutionNotFound or similar if implementing proper handling.
-		// For now returning error is enough.
-		return nil, fmt.Errorf("execution not found")
+		return nil, types.ErrExecutionNotFound
 	}
 	return state.Clone(), nil
 }
@@ -95,6 +220,14 @@ func (s *MemoryMutableStateStore) UpdateMutableState(ctx context.Context, key ty
 	return nil
 }
 
+func (s *MemoryMutableStateStore) DeleteMutableState(ctx context.Context, key types.ExecutionKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, keyToString(key))
+	return nil
+}
+
 func (s *MemoryMutableStateStore) ListRunningExecutions(ctx context.Context) ([]types.ExecutionKey, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
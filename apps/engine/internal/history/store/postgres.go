@@ -22,7 +22,9 @@ import (
 type PostgresEventStore struct {
 	pool       *pgxpool.Pool
 	serializer *events.Serializer
+	encoding   events.PayloadEncoding
 	shardCount int32
+	hashChain  map[string]bool
 }
 
 // NewPostgresEventStore creates a new PostgreSQL-backed event store.
@@ -34,6 +36,23 @@ func NewPostgresEventStore(pool *pgxpool.Pool, shardCount int32) *PostgresEventS
 	}
 }
 
+// SetPayloadEncoding sets the encoding new events are written with. Events
+// are always read back in whatever encoding they were stored with.
+func (s *PostgresEventStore) SetPayloadEncoding(encoding events.PayloadEncoding) {
+	s.encoding = encoding
+}
+
+// SetHashChainNamespaces makes the events of the given namespaces carry a
+// hash chain, computed on append, that VerifyHistoryIntegrity checks.
+// Chaining costs a read and a hash per append, so it is opt-in; events of
+// other namespaces are written without hashes.
+func (s *PostgresEventStore) SetHashChainNamespaces(namespaces []string) {
+	s.hashChain = make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		s.hashChain[ns] = true
+	}
+}
+
 // AppendEvents appends events to the history for an execution.
 func (s *PostgresEventStore) AppendEvents(
 	ctx context.Context,
@@ -68,29 +87,72 @@ func (s *PostgresEventStore) AppendEvents(
 	// Get shard ID for this execution
 	shardID := getShardIDForExecution(key, s.shardCount)
 
+	// Chain onto the hash of the event before the batch. It has none when the
+	// execution started before its namespace opted in; the chain starts here.
+	hashed := s.hashChain[key.NamespaceID]
+	var prevHash []byte
+	if hashed {
+		err := tx.QueryRow(ctx, `
+			SELECT hash FROM history_events
+			WHERE namespace_id = $1 AND workflow_id = $2 AND run_id = $3 AND event_id < $4
+			ORDER BY event_id DESC
+			LIMIT 1
+		`, key.NamespaceID, key.WorkflowID, key.RunID, evts[0].EventID).Scan(&prevHash)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to read previous event hash: %w", err)
+		}
+	}
+
 	// Insert events
 	for _, event := range evts {
-		data, err := s.serializer.Serialize(event)
+		data, err := s.serializer.SerializePayload(event, s.encoding)
 		if err != nil {
 			return fmt.Errorf("failed to serialize event: %w", err)
 		}
 
-		_, err = tx.Exec(ctx, `
-			INSERT INTO history_events (
-				shard_id, namespace_id, workflow_id, run_id,
-				event_id, event_type, version, timestamp, data
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		`,
-			shardID,
-			key.NamespaceID,
-			key.WorkflowID,
-			key.RunID,
-			event.EventID,
-			int16(event.EventType),
-			event.Version,
-			event.Timestamp,
-			data,
-		)
+		if hashed {
+			hash, err := events.ChainHash(prevHash, event)
+			if err != nil {
+				return fmt.Errorf("failed to hash event %d: %w", event.EventID, err)
+			}
+			_, err = tx.Exec(ctx, `
+				INSERT INTO history_events (
+					shard_id, namespace_id, workflow_id, run_id,
+					event_id, event_type, version, timestamp, data,
+					prev_hash, hash
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			`,
+				shardID,
+				key.NamespaceID,
+				key.WorkflowID,
+				key.RunID,
+				event.EventID,
+				int16(event.EventType),
+				event.Version,
+				event.Timestamp,
+				data,
+				prevHash,
+				hash,
+			)
+			prevHash = hash
+		} else {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO history_events (
+					shard_id, namespace_id, workflow_id, run_id,
+					event_id, event_type, version, timestamp, data
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			`,
+				shardID,
+				key.NamespaceID,
+				key.WorkflowID,
+				key.RunID,
+				event.EventID,
+				int16(event.EventType),
+				event.Version,
+				event.Timestamp,
+				data,
+			)
+		}
 		if err != nil {
 			var pgErr *pgconn.PgError
 			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -129,6 +191,72 @@ func (s *PostgresEventStore) GetEvents(
 	}
 	defer rows.Close()
 
+	return s.scanEvents(rows)
+}
+
+// GetEventHashes returns the hash chain links of an execution's events
+// within the specified range. Events written without a hash have empty
+// links.
+func (s *PostgresEventStore) GetEventHashes(
+	ctx context.Context,
+	key types.ExecutionKey,
+	firstEventID, lastEventID int64,
+) ([]types.EventHash, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT event_id, prev_hash, hash
+		FROM history_events
+		WHERE namespace_id = $1 AND workflow_id = $2 AND run_id = $3
+		  AND event_id >= $4 AND event_id <= $5
+		ORDER BY event_id ASC
+	`, key.NamespaceID, key.WorkflowID, key.RunID, firstEventID, lastEventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event hashes: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []types.EventHash
+	for rows.Next() {
+		var h types.EventHash
+		if err := rows.Scan(&h.EventID, &h.PrevHash, &h.Hash); err != nil {
+			return nil, fmt.Errorf("failed to scan event hash: %w", err)
+		}
+		hashes = append(hashes, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event hashes: %w", err)
+	}
+	return hashes, nil
+}
+
+// GetEventsByType retrieves up to limit events of the given types, starting at
+// firstEventID. The type filter is applied in the query so pagination over the
+// filtered set does not require loading the full history.
+func (s *PostgresEventStore) GetEventsByType(
+	ctx context.Context,
+	key types.ExecutionKey,
+	eventTypes []types.EventType,
+	firstEventID int64,
+	limit int,
+) ([]*types.HistoryEvent, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT event_id, event_type, version, timestamp, data
+		FROM history_events
+		WHERE namespace_id = $1 AND workflow_id = $2 AND run_id = $3
+		  AND event_id >= $4 AND event_type = ANY($5)
+		ORDER BY event_id ASC
+		LIMIT $6
+	`, key.NamespaceID, key.WorkflowID, key.RunID, firstEventID, eventTypeCodes(eventTypes), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanEvents(rows)
+}
+
+// scanEvents decodes the event_id, event_type, version, timestamp and data
+// columns of rows into history events.
+func (s *PostgresEventStore) scanEvents(rows pgx.Rows) ([]*types.HistoryEvent, error) {
 	var events []*types.HistoryEvent
 	for rows.Next() {
 		var eventID int64
@@ -141,7 +269,7 @@ func (s *PostgresEventStore) GetEvents(
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
 
-		event, err := s.serializer.Deserialize(data)
+		event, err := s.serializer.DeserializePayload(data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to deserialize event %d: %w", eventID, err)
 		}
@@ -188,6 +316,21 @@ func (s *PostgresEventStore) DeleteEvents(ctx context.Context, key types.Executi
 	return nil
 }
 
+// TrimEvents deletes the events between firstEventID and lastEventID,
+// inclusive, recorded before olderThan. It returns how many were deleted.
+func (s *PostgresEventStore) TrimEvents(ctx context.Context, key types.ExecutionKey, firstEventID, lastEventID int64, olderThan time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM history_events
+		WHERE namespace_id = $1 AND workflow_id = $2 AND run_id = $3
+		  AND event_id BETWEEN $4 AND $5
+		  AND timestamp < $6
+	`, key.NamespaceID, key.WorkflowID, key.RunID, firstEventID, lastEventID, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to trim events: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
 // GetEventCount returns the total number of events for an execution.
 func (s *PostgresEventStore) GetEventCount(ctx context.Context, key types.ExecutionKey) (int64, error) {
 	var count int64
@@ -201,6 +344,20 @@ func (s *PostgresEventStore) GetEventCount(ctx context.Context, key types.Execut
 	return count, nil
 }
 
+// GetEventCountByType returns the number of events of the given types for an execution.
+func (s *PostgresEventStore) GetEventCountByType(ctx context.Context, key types.ExecutionKey, eventTypes []types.EventType) (int64, error) {
+	var count int64
+	err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM history_events
+		WHERE namespace_id = $1 AND workflow_id = $2 AND run_id = $3
+		  AND event_type = ANY($4)
+	`, key.NamespaceID, key.WorkflowID, key.RunID, eventTypeCodes(eventTypes)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get event count: %w", err)
+	}
+	return count, nil
+}
+
 // PostgresMutableStateStore implements MutableStateStore using PostgreSQL.
 type PostgresMutableStateStore struct {
 	pool       *pgxpool.Pool
@@ -420,6 +577,15 @@ func getShardIDForExecution(key types.ExecutionKey, shardCount int32) int32 {
 	return int32(hash % uint32(shardCount))
 }
 
+// eventTypeCodes converts event types to the smallint codes stored in history_events.
+func eventTypeCodes(eventTypes []types.EventType) []int16 {
+	codes := make([]int16, len(eventTypes))
+	for i, et := range eventTypes {
+		codes[i] = int16(et)
+	}
+	return codes
+}
+
 // calculateChecksum creates a simple checksum for data integrity.
 func calculateChecksum(data []byte) []byte {
 	var sum uint32
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+func TestRecordTimerFiredIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	svc, eventStore, stateStore := newChildActivityTestService(t)
+	key := types.ExecutionKey{NamespaceID: "default", WorkflowID: "reminder", RunID: "run-1"}
+	seedRunningExecution(t, stateStore, key, "")
+
+	err := svc.RecordEvent(ctx, key, &types.HistoryEvent{
+		EventType:  types.EventTypeTimerStarted,
+		Timestamp:  time.Now(),
+		Attributes: &types.TimerStartedAttributes{TimerID: "wait-1", StartToFire: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("start timer: %v", err)
+	}
+
+	// The timer service addresses each attempt at the next event ID it read.
+	fire := func() *types.HistoryEvent {
+		t.Helper()
+		state, err := stateStore.GetMutableState(ctx, key)
+		if err != nil {
+			t.Fatalf("get state: %v", err)
+		}
+		return &types.HistoryEvent{
+			EventID:    state.NextEventID,
+			EventType:  types.EventTypeTimerFired,
+			Timestamp:  time.Now(),
+			Attributes: &types.TimerFiredAttributes{TimerID: "wait-1"},
+		}
+	}
+
+	first := fire()
+	if err := svc.RecordEvent(ctx, key, first); err != nil {
+		t.Fatalf("record timer fired: %v", err)
+	}
+
+	// The acknowledgement is lost and the timer service retries, both with
+	// the stale event ID and after re-reading the state.
+	stale := *first
+	stale.Attributes = &types.TimerFiredAttributes{TimerID: "wait-1"}
+	for name, retry := range map[string]*types.HistoryEvent{"stale": &stale, "fresh": fire()} {
+		if err := svc.RecordEvent(ctx, key, retry); err != nil {
+			t.Fatalf("%s retry: %v", name, err)
+		}
+		if retry.EventID != first.EventID {
+			t.Fatalf("%s retry reported event %d, want the recorded event %d", name, retry.EventID, first.EventID)
+		}
+	}
+
+	fired, err := eventStore.GetEventsByType(ctx, key, []types.EventType{types.EventTypeTimerFired}, 1, 10)
+	if err != nil {
+		t.Fatalf("get events: %v", err)
+	}
+	if len(fired) != 1 {
+		t.Fatalf("expected one TimerFired event, got %d", len(fired))
+	}
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	"github.com/linkflow/engine/internal/history/types"
+)
+
+// MaxExecutionTreeNodes bounds the executions GetExecutionTree returns.
+const MaxExecutionTreeNodes = 1000
+
+// ErrVisibilityNotConfigured is returned by operations that read the
+// visibility store when the service has none.
+var ErrVisibilityNotConfigured = errors.New("visibility store not initialized")
+
+// ExecutionTreeNode is an execution and the child workflows it started.
+type ExecutionTreeNode struct {
+	Key          types.ExecutionKey
+	WorkflowType string
+	Status       commonv1.ExecutionStatus
+	StartTime    time.Time
+	CloseTime    time.Time // Zero while running
+	Children     []*ExecutionTreeNode
+}
+
+// ExecutionTree is the hierarchy of child workflows below a root execution.
+type ExecutionTree struct {
+	Root      *ExecutionTreeNode
+	NodeCount int
+	// Truncated reports that descendants were left out because the tree is
+	// deeper than the maximum child workflow depth or larger than
+	// MaxExecutionTreeNodes.
+	Truncated bool
+}
+
+// GetExecutionTree returns rootKey and its descendant executions, following
+// the parent links recorded in visibility level by level. Children are
+// ordered by start time.
+func (s *Service) GetExecutionTree(ctx context.Context, rootKey types.ExecutionKey) (*ExecutionTree, error) {
+	if s.visibilityStore == nil {
+		return nil, ErrVisibilityNotConfigured
+	}
+
+	state, err := s.stateStore.GetMutableState(ctx, rootKey)
+	if err != nil {
+		return nil, err
+	}
+	root := &ExecutionTreeNode{Key: rootKey}
+	if info := state.ExecutionInfo; info != nil {
+		if info.RunID != "" {
+			root.Key.RunID = info.RunID
+		}
+		root.WorkflowType = info.WorkflowTypeName
+		root.Status = internalExecutionStatusToProto(info.Status)
+		root.StartTime = info.StartTime
+		root.CloseTime = info.CloseTime
+	}
+
+	tree := &ExecutionTree{Root: root, NodeCount: 1}
+	level := []*ExecutionTreeNode{root}
+	for depth := int32(1); len(level) > 0; depth++ {
+		if depth > s.maxChildDepth {
+			truncated, err := s.anyChildExecutions(ctx, rootKey.NamespaceID, level)
+			if err != nil {
+				return nil, err
+			}
+			tree.Truncated = truncated
+			break
+		}
+
+		var next []*ExecutionTreeNode
+		for _, parent := range level {
+			remaining := MaxExecutionTreeNodes - tree.NodeCount
+			// Ask for one more than fits to learn whether any were left out.
+			children, err := s.visibilityStore.ListChildExecutions(ctx, rootKey.NamespaceID, parent.Key.WorkflowID, parent.Key.RunID, remaining+1)
+			if err != nil {
+				return nil, err
+			}
+			if len(children) > remaining {
+				children = children[:remaining]
+				tree.Truncated = true
+			}
+
+			for _, child := range children {
+				node := &ExecutionTreeNode{
+					Key: types.ExecutionKey{
+						NamespaceID: rootKey.NamespaceID,
+						WorkflowID:  child.Execution.GetWorkflowId(),
+						RunID:       child.Execution.GetRunId(),
+					},
+					WorkflowType: child.Type.GetName(),
+					Status:       child.Status,
+					StartTime:    child.StartTime,
+					CloseTime:    child.CloseTime,
+				}
+				parent.Children = append(parent.Children, node)
+				next = append(next, node)
+			}
+			tree.NodeCount += len(children)
+			if tree.Truncated {
+				return tree, nil
+			}
+		}
+		level = next
+	}
+	return tree, nil
+}
+
+// anyChildExecutions reports whether any of nodes started a child workflow.
+func (s *Service) anyChildExecutions(ctx context.Context, namespaceID string, nodes []*ExecutionTreeNode) (bool, error) {
+	for _, node := range nodes {
+		children, err := s.visibilityStore.ListChildExecutions(ctx, namespaceID, node.Key.WorkflowID, node.Key.RunID, 1)
+		if err != nil {
+			return false, err
+		}
+		if len(children) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
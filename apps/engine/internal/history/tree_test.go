@@ -0,0 +1,89 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	"github.com/linkflow/engine/internal/history/engine"
+	"github.com/linkflow/engine/internal/history/store"
+	"github.com/linkflow/engine/internal/history/types"
+	"github.com/linkflow/engine/internal/history/visibility"
+)
+
+// childVisibilityStore lists the children recorded for each parent run.
+type childVisibilityStore struct {
+	visibility.Store
+	children map[string][]*visibility.WorkflowExecutionInfo
+}
+
+func (m *childVisibilityStore) add(parentWorkflowID, parentRunID, workflowID string, status commonv1.ExecutionStatus) {
+	key := parentWorkflowID + "/" + parentRunID
+	m.children[key] = append(m.children[key], &visibility.WorkflowExecutionInfo{
+		Execution: &commonv1.WorkflowExecution{WorkflowId: workflowID, RunId: workflowID + "-run"},
+		StartTime: time.Unix(int64(len(m.children[key])), 0),
+		Status:    status,
+	})
+}
+
+func (m *childVisibilityStore) ListChildExecutions(_ context.Context, _, parentWorkflowID, parentRunID string, limit int) ([]*visibility.WorkflowExecutionInfo, error) {
+	children := m.children[parentWorkflowID+"/"+parentRunID]
+	if len(children) > limit {
+		children = children[:limit]
+	}
+	return children, nil
+}
+
+func TestGetExecutionTreeWalksDescendants(t *testing.T) {
+	ctx := context.Background()
+	vis := &childVisibilityStore{children: make(map[string][]*visibility.WorkflowExecutionInfo)}
+	vis.add("order", "run-1", "charge", commonv1.ExecutionStatus_EXECUTION_STATUS_COMPLETED)
+	vis.add("order", "run-1", "ship", commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING)
+	vis.add("ship", "ship-run", "label", commonv1.ExecutionStatus_EXECUTION_STATUS_FAILED)
+
+	newService := func(maxDepth int32) *Service {
+		stateStore := store.NewMemoryMutableStateStore()
+		svc := newTestService(t, Config{
+			StateStore:            stateStore,
+			VisibilityStore:       vis,
+			MaxChildWorkflowDepth: maxDepth,
+		})
+		state := engine.NewMutableState(&types.ExecutionInfo{
+			NamespaceID: "default",
+			WorkflowID:  "order",
+			RunID:       "run-1",
+			Status:      types.ExecutionStatusRunning,
+		})
+		if err := stateStore.UpdateMutableState(ctx, types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}, state, 0); err != nil {
+			t.Fatalf("seed state: %v", err)
+		}
+		return svc
+	}
+	rootKey := types.ExecutionKey{NamespaceID: "default", WorkflowID: "order", RunID: "run-1"}
+
+	tree, err := newService(0).GetExecutionTree(ctx, rootKey)
+	if err != nil {
+		t.Fatalf("GetExecutionTree: %v", err)
+	}
+	root := tree.Root
+	if tree.NodeCount != 4 || tree.Truncated || root.Status != commonv1.ExecutionStatus_EXECUTION_STATUS_RUNNING {
+		t.Fatalf("tree = %+v, root %+v, want 4 nodes untruncated", tree, root)
+	}
+	if len(root.Children) != 2 || root.Children[0].Key.WorkflowID != "charge" || root.Children[1].Key.WorkflowID != "ship" {
+		t.Fatalf("root children = %+v, want charge then ship", root.Children)
+	}
+	ship := root.Children[1]
+	if len(ship.Children) != 1 || ship.Children[0].Key.WorkflowID != "label" || ship.Children[0].Status != commonv1.ExecutionStatus_EXECUTION_STATUS_FAILED {
+		t.Fatalf("ship children = %+v, want the failed label workflow", ship.Children)
+	}
+
+	// A depth limit of one keeps the direct children and reports the rest.
+	tree, err = newService(1).GetExecutionTree(ctx, rootKey)
+	if err != nil {
+		t.Fatalf("GetExecutionTree with depth 1: %v", err)
+	}
+	if tree.NodeCount != 3 || !tree.Truncated || len(tree.Root.Children[1].Children) != 0 {
+		t.Fatalf("depth-limited tree = %+v, want 3 nodes and truncated", tree)
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 )
@@ -38,35 +39,43 @@ const (
 	EventTypeWorkflowTaskCompleted
 	EventTypeWorkflowTaskFailed
 	EventTypeWorkflowTaskTimedOut
+	EventTypeChildWorkflowStarted
+	EventTypeChildWorkflowCompleted
+	EventTypeExecutionTimedOut
+	EventTypeExecutionCanceled
 )
 
 func (e EventType) String() string {
 	names := map[EventType]string{
-		EventTypeUnspecified:           "Unspecified",
-		EventTypeExecutionStarted:      "ExecutionStarted",
-		EventTypeExecutionCompleted:    "ExecutionCompleted",
-		EventTypeExecutionFailed:       "ExecutionFailed",
-		EventTypeExecutionTerminated:   "ExecutionTerminated",
-		EventTypeNodeScheduled:         "NodeScheduled",
-		EventTypeNodeStarted:           "NodeStarted",
-		EventTypeNodeCompleted:         "NodeCompleted",
-		EventTypeNodeFailed:            "NodeFailed",
-		EventTypeNodeTimedOut:          "NodeTimedOut",
-		EventTypeTimerStarted:          "TimerStarted",
-		EventTypeTimerFired:            "TimerFired",
-		EventTypeTimerCanceled:         "TimerCanceled",
-		EventTypeActivityScheduled:     "ActivityScheduled",
-		EventTypeActivityStarted:       "ActivityStarted",
-		EventTypeActivityCompleted:     "ActivityCompleted",
-		EventTypeActivityFailed:        "ActivityFailed",
-		EventTypeActivityTimedOut:      "ActivityTimedOut",
-		EventTypeSignalReceived:        "SignalReceived",
-		EventTypeMarkerRecorded:        "MarkerRecorded",
-		EventTypeWorkflowTaskScheduled: "WorkflowTaskScheduled",
-		EventTypeWorkflowTaskStarted:   "WorkflowTaskStarted",
-		EventTypeWorkflowTaskCompleted: "WorkflowTaskCompleted",
-		EventTypeWorkflowTaskFailed:    "WorkflowTaskFailed",
-		EventTypeWorkflowTaskTimedOut:  "WorkflowTaskTimedOut",
+		EventTypeUnspecified:            "Unspecified",
+		EventTypeExecutionStarted:       "ExecutionStarted",
+		EventTypeExecutionCompleted:     "ExecutionCompleted",
+		EventTypeExecutionFailed:        "ExecutionFailed",
+		EventTypeExecutionTerminated:    "ExecutionTerminated",
+		EventTypeNodeScheduled:          "NodeScheduled",
+		EventTypeNodeStarted:            "NodeStarted",
+		EventTypeNodeCompleted:          "NodeCompleted",
+		EventTypeNodeFailed:             "NodeFailed",
+		EventTypeNodeTimedOut:           "NodeTimedOut",
+		EventTypeTimerStarted:           "TimerStarted",
+		EventTypeTimerFired:             "TimerFired",
+		EventTypeTimerCanceled:          "TimerCanceled",
+		EventTypeActivityScheduled:      "ActivityScheduled",
+		EventTypeActivityStarted:        "ActivityStarted",
+		EventTypeActivityCompleted:      "ActivityCompleted",
+		EventTypeActivityFailed:         "ActivityFailed",
+		EventTypeActivityTimedOut:       "ActivityTimedOut",
+		EventTypeSignalReceived:         "SignalReceived",
+		EventTypeMarkerRecorded:         "MarkerRecorded",
+		EventTypeWorkflowTaskScheduled:  "WorkflowTaskScheduled",
+		EventTypeWorkflowTaskStarted:    "WorkflowTaskStarted",
+		EventTypeWorkflowTaskCompleted:  "WorkflowTaskCompleted",
+		EventTypeWorkflowTaskFailed:     "WorkflowTaskFailed",
+		EventTypeWorkflowTaskTimedOut:   "WorkflowTaskTimedOut",
+		EventTypeChildWorkflowStarted:   "ChildWorkflowStarted",
+		EventTypeChildWorkflowCompleted: "ChildWorkflowCompleted",
+		EventTypeExecutionTimedOut:      "ExecutionTimedOut",
+		EventTypeExecutionCanceled:      "ExecutionCanceled",
 	}
 	if name, ok := names[e]; ok {
 		return name
@@ -83,6 +92,7 @@ const (
 	ExecutionStatusFailed
 	ExecutionStatusTerminated
 	ExecutionStatusTimedOut
+	ExecutionStatusCanceled
 )
 
 type ExecutionKey struct {
@@ -106,6 +116,11 @@ type ExecutionInfo struct {
 	TaskTimeout       time.Duration
 	LastEventTaskID   int64
 	LastProcessedNode string
+	ParentWorkflowID  string
+	ParentRunID       string
+	ParentNodeID      string
+	ChildDepth        int32
+	CorrelationID     string
 }
 
 type ActivityInfo struct {
@@ -124,6 +139,24 @@ type ActivityInfo struct {
 	StartToClose     time.Duration
 	HeartbeatDetails []byte
 	LastHeartbeat    time.Time
+	AsyncNonce       string // non-empty while waiting on an async completion
+	WaitSignal       string // signal that completes the async activity, if any
+	TimeoutResult    []byte // completes the async activity on timeout instead of failing it
+}
+
+// PendingNodeInfo is a scheduled node that has not yet completed, failed or
+// timed out.
+type PendingNodeInfo struct {
+	ScheduledEventID int64
+	NodeID           string
+	NodeType         string
+	ScheduledTime    time.Time
+
+	// Set once a worker records that it began executing the node. Attempt
+	// is that worker's attempt number, starting at 1.
+	StartedEventID int64
+	StartedTime    time.Time
+	Attempt        int32
 }
 
 type TimerInfo struct {
@@ -140,6 +173,11 @@ type NodeResult struct {
 	Output         []byte
 	FailureReason  string
 	FailureDetails []byte
+
+	// StartedTime and Attempts come from the node's last recorded start;
+	// both are zero when no start was recorded.
+	StartedTime time.Time
+	Attempts    int32
 }
 
 type HistoryEvent struct {
@@ -151,6 +189,15 @@ type HistoryEvent struct {
 	Attributes any
 }
 
+// EventHash is an event's link in its execution's hash chain: Hash covers
+// PrevHash, the hash of the event before it, and the event itself. Events
+// appended while their namespace did not chain hashes have neither.
+type EventHash struct {
+	EventID  int64
+	PrevHash []byte
+	Hash     []byte
+}
+
 type ExecutionStartedAttributes struct {
 	WorkflowType     string
 	TaskQueue        string
@@ -159,7 +206,12 @@ type ExecutionStartedAttributes struct {
 	RunTimeout       time.Duration
 	TaskTimeout      time.Duration
 	ParentExecution  *ExecutionKey
+	ParentNodeID     string
+	ChildDepth       int32
 	Initiator        string
+	// CorrelationID is the caller's own identifier for the run, such as an
+	// order ID. Several runs may share one.
+	CorrelationID string
 }
 
 type ExecutionCompletedAttributes struct {
@@ -187,6 +239,12 @@ type NodeStartedAttributes struct {
 	NodeID           string
 	ScheduledEventID int64
 	Identity         string
+
+	// Set when the node completes out-of-band (async activity).
+	AsyncNonce      string
+	ScheduleToClose time.Duration
+	WaitSignal      string
+	TimeoutResult   []byte
 }
 
 type NodeCompletedAttributes struct {
@@ -195,6 +253,7 @@ type NodeCompletedAttributes struct {
 	StartedEventID   int64
 	Result           []byte
 	Logs             []byte
+	SignalEventID    int64 // buffered signal the completion delivered, if any
 }
 
 type NodeFailedAttributes struct {
@@ -273,6 +332,40 @@ type MarkerRecordedAttributes struct {
 	Details    map[string][]byte
 }
 
+// Version markers record the version a decider chose for a workflow change.
+const (
+	VersionMarkerName        = "Version"
+	VersionMarkerChangeIDKey = "change_id"
+	VersionMarkerVersionKey  = "version" // Decimal string
+)
+
+// VersionMarker is the version recorded for a workflow change.
+type VersionMarker struct {
+	ChangeID string
+	Version  int32
+	EventID  int64
+}
+
+// NodeTypeSetVariable is the node type whose completions update the
+// execution's workflow variables.
+const NodeTypeSetVariable = "set_variable"
+
+// SetVariableResult is the output of a set_variable node: the variable it
+// sets and its new JSON value.
+type SetVariableResult struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+// BufferedSignal is a received signal no wait_signal node has taken yet.
+type BufferedSignal struct {
+	EventID      int64
+	SignalName   string
+	Input        []byte
+	Identity     string
+	ReceivedTime time.Time
+}
+
 type WorkflowTaskScheduledAttributes struct {
 	TaskQueue    string
 	StartToClose time.Duration
@@ -307,3 +400,48 @@ type WorkflowTaskTimedOutAttributes struct {
 	StartedEventID   int64
 	TimeoutType      string
 }
+
+// ParentClosePolicy decides what happens to a running child workflow when its
+// parent closes.
+type ParentClosePolicy string
+
+const (
+	// ParentClosePolicyTerminate terminates the child. It is the default.
+	ParentClosePolicyTerminate ParentClosePolicy = "terminate"
+	// ParentClosePolicyAbandon leaves the child running on its own.
+	ParentClosePolicyAbandon ParentClosePolicy = "abandon"
+	// ParentClosePolicyRequestCancel sends the child a CancelRequestedSignalName
+	// signal and leaves it to wind down.
+	ParentClosePolicyRequestCancel ParentClosePolicy = "request_cancel"
+)
+
+// CancelRequestedSignalName is the signal a child receives when its parent
+// closes under ParentClosePolicyRequestCancel.
+const CancelRequestedSignalName = "cancel_requested"
+
+type ChildExecutionInfo struct {
+	NodeID            string
+	WorkflowID        string
+	RunID             string
+	WorkflowType      string
+	StartedEventID    int64
+	StartedTime       time.Time
+	ParentClosePolicy ParentClosePolicy
+}
+
+type ChildWorkflowStartedAttributes struct {
+	NodeID            string
+	WorkflowID        string
+	RunID             string
+	WorkflowType      string
+	ParentClosePolicy ParentClosePolicy
+}
+
+type ChildWorkflowCompletedAttributes struct {
+	NodeID        string
+	WorkflowID    string
+	RunID         string
+	Status        ExecutionStatus
+	Result        []byte
+	FailureReason string
+}
@@ -13,7 +13,10 @@ type ListRequest struct {
 	NamespaceID   string
 	PageSize      int
 	NextPageToken []byte
-	Query         string // Simple query support (e.g. "WorkflowType = 'foo'")
+	Query         string // Visibility query (e.g. "WorkflowType = 'foo'"), see CompileQuery
+
+	// Status restricts the listing to one execution status (0 = any).
+	Status commonv1.ExecutionStatus
 }
 
 // ListResponse contains the list of executions.
@@ -31,6 +34,7 @@ type WorkflowExecutionInfo struct {
 	Status        commonv1.ExecutionStatus
 	HistoryLength int64
 	Memo          *commonv1.Memo
+	CorrelationID string
 }
 
 // Store defines the interface for visibility storage.
@@ -39,6 +43,18 @@ type Store interface {
 	RecordWorkflowExecutionClosed(ctx context.Context, req *RecordWorkflowExecutionClosedRequest) error
 	ListOpenWorkflowExecutions(ctx context.Context, req *ListRequest) (*ListResponse, error)
 	ListClosedWorkflowExecutions(ctx context.Context, req *ListRequest) (*ListResponse, error)
+	// CountOpenTopLevelExecutions counts running executions that are not child workflows.
+	CountOpenTopLevelExecutions(ctx context.Context, namespaceID string) (int64, error)
+	// ListChildExecutions lists up to limit child workflows started by the
+	// given parent run, oldest first.
+	ListChildExecutions(ctx context.Context, namespaceID, parentWorkflowID, parentRunID string, limit int) ([]*WorkflowExecutionInfo, error)
+	// ListExecutionsByCorrelationID lists the runs started with the given
+	// correlation ID in any status, most recently started first.
+	ListExecutionsByCorrelationID(ctx context.Context, namespaceID, correlationID string, pageSize int, nextPageToken []byte) (*ListResponse, error)
+	DeleteWorkflowExecution(ctx context.Context, namespaceID, runID string) error
+	// GetCurrentRunID returns the most recently started run of a workflow, or
+	// "" if it has none.
+	GetCurrentRunID(ctx context.Context, namespaceID, workflowID string) (string, error)
 	// TODO: Add generic ListWorkflowExecutions with query support
 }
 
@@ -49,6 +65,14 @@ type RecordWorkflowExecutionStartedRequest struct {
 	StartTime    time.Time
 	Status       commonv1.ExecutionStatus
 	Memo         *commonv1.Memo
+
+	// Set for child workflows.
+	ParentWorkflowID string
+	ParentRunID      string
+
+	// CorrelationID is stored in its own indexed column, empty when the
+	// run was started without one.
+	CorrelationID string
 }
 
 type RecordWorkflowExecutionClosedRequest struct {
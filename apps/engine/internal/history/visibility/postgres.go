@@ -3,6 +3,8 @@ package visibility
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -20,7 +22,8 @@ func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
 	return &PostgresStore{pool: pool}
 }
 
-// Ensure the table exists:
+// Schema, created by scripts/migrations/010_executions_visibility.up.sql and
+// 011_executions_visibility_correlation.up.sql:
 // CREATE TABLE executions_visibility (
 //     namespace_id VARCHAR(64) NOT NULL,
 //     workflow_id VARCHAR(255) NOT NULL,
@@ -31,18 +34,24 @@ func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
 //     status INT NOT NULL,
 //     history_length BIGINT,
 //     memo BYTEA,
+//     parent_workflow_id VARCHAR(255) NOT NULL DEFAULT '',
+//     parent_run_id VARCHAR(64) NOT NULL DEFAULT '',
+//     correlation_id VARCHAR(255) NOT NULL DEFAULT '',
 //     PRIMARY KEY (namespace_id, run_id)
 // );
 // CREATE INDEX idx_visibility_open ON executions_visibility (namespace_id, start_time DESC) WHERE status = 1;
 // CREATE INDEX idx_visibility_closed ON executions_visibility (namespace_id, close_time DESC) WHERE status != 1;
+// CREATE INDEX idx_visibility_parent ON executions_visibility (namespace_id, parent_workflow_id, parent_run_id) WHERE parent_workflow_id != '';
+// CREATE INDEX idx_visibility_correlation ON executions_visibility (namespace_id, correlation_id, start_time DESC, run_id DESC) WHERE correlation_id != '';
 
 func (s *PostgresStore) RecordWorkflowExecutionStarted(ctx context.Context, req *RecordWorkflowExecutionStartedRequest) error {
 	memoBytes, _ := json.Marshal(req.Memo)
 
 	_, err := s.pool.Exec(ctx, `
 		INSERT INTO executions_visibility (
-			namespace_id, workflow_id, run_id, workflow_type, start_time, status, memo
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			namespace_id, workflow_id, run_id, workflow_type, start_time, status, memo,
+			parent_workflow_id, parent_run_id, correlation_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (namespace_id, run_id) DO UPDATE SET
 			status = $6, start_time = $5, memo = $7
 	`,
@@ -53,6 +62,9 @@ func (s *PostgresStore) RecordWorkflowExecutionStarted(ctx context.Context, req
 		req.StartTime,
 		int32(req.Status),
 		memoBytes,
+		req.ParentWorkflowID,
+		req.ParentRunID,
+		req.CorrelationID,
 	)
 	return err
 }
@@ -83,73 +95,166 @@ func (s *PostgresStore) ListClosedWorkflowExecutions(ctx context.Context, req *L
 	return s.listExecutions(ctx, req, false)
 }
 
-func (s *PostgresStore) listExecutions(ctx context.Context, req *ListRequest, open bool) (*ListResponse, error) {
-	limit := req.PageSize
+func (s *PostgresStore) DeleteWorkflowExecution(ctx context.Context, namespaceID, runID string) error {
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM executions_visibility
+		WHERE namespace_id = $1 AND run_id = $2
+	`, namespaceID, runID)
+	return err
+}
+
+func (s *PostgresStore) GetCurrentRunID(ctx context.Context, namespaceID, workflowID string) (string, error) {
+	var runID string
+	err := s.pool.QueryRow(ctx, `
+		SELECT run_id
+		FROM executions_visibility
+		WHERE namespace_id = $1 AND workflow_id = $2
+		ORDER BY start_time DESC, run_id DESC
+		LIMIT 1
+	`, namespaceID, workflowID).Scan(&runID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return runID, err
+}
+
+func (s *PostgresStore) CountOpenTopLevelExecutions(ctx context.Context, namespaceID string) (int64, error) {
+	var count int64
+	err := s.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM executions_visibility
+		WHERE namespace_id = $1 AND status = 1 AND COALESCE(parent_workflow_id, '') = ''
+	`, namespaceID).Scan(&count)
+	return count, err
+}
+
+func (s *PostgresStore) ListChildExecutions(ctx context.Context, namespaceID, parentWorkflowID, parentRunID string, limit int) ([]*WorkflowExecutionInfo, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT workflow_id, run_id, workflow_type, start_time, close_time, status, history_length
+		FROM executions_visibility
+		WHERE namespace_id = $1 AND parent_workflow_id = $2 AND parent_run_id = $3
+		ORDER BY start_time, run_id
+		LIMIT $4
+	`, namespaceID, parentWorkflowID, parentRunID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var infos []*WorkflowExecutionInfo
+	for rows.Next() {
+		var wid, rid, wtype string
+		var start, close *time.Time
+		var status int32
+		var historyLength *int64
+
+		if err := rows.Scan(&wid, &rid, &wtype, &start, &close, &status, &historyLength); err != nil {
+			return nil, err
+		}
+
+		info := &WorkflowExecutionInfo{
+			Execution: &commonv1.WorkflowExecution{WorkflowId: wid, RunId: rid},
+			Type:      &apiv1.WorkflowType{Name: wtype},
+			Status:    commonv1.ExecutionStatus(status),
+		}
+		if start != nil {
+			info.StartTime = *start
+		}
+		if close != nil {
+			info.CloseTime = *close
+		}
+		if historyLength != nil {
+			info.HistoryLength = *historyLength
+		}
+		infos = append(infos, info)
+	}
+	return infos, rows.Err()
+}
+
+func (s *PostgresStore) ListExecutionsByCorrelationID(ctx context.Context, namespaceID, correlationID string, pageSize int, nextPageToken []byte) (*ListResponse, error) {
+	limit := pageSize
 	if limit == 0 {
 		limit = 100
 	}
 
-	// Decode cursor from NextPageToken (format: "timestamp|run_id")
-	var cursorTime *time.Time
-	var cursorRunID string
-	if len(req.NextPageToken) > 0 {
-		parts := strings.SplitN(string(req.NextPageToken), "|", 2)
+	query := `
+		SELECT workflow_id, run_id, workflow_type, start_time, close_time, status, history_length
+		FROM executions_visibility
+		WHERE namespace_id = $1 AND correlation_id = $2`
+	args := []interface{}{namespaceID, correlationID}
+
+	// Same "timestamp|run_id" cursor as listExecutions, on start_time.
+	if len(nextPageToken) > 0 {
+		parts := strings.SplitN(string(nextPageToken), "|", 2)
 		if len(parts) == 2 {
-			t, err := time.Parse(time.RFC3339Nano, parts[0])
-			if err == nil {
-				cursorTime = &t
-				cursorRunID = parts[1]
+			if t, err := time.Parse(time.RFC3339Nano, parts[0]); err == nil {
+				args = append(args, t, parts[1])
+				query += ` AND (start_time, run_id) < ($3, $4)`
 			}
 		}
 	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(` ORDER BY start_time DESC, run_id DESC LIMIT $%d`, len(args))
 
-	var rows pgx.Rows
-	var err error
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	if cursorTime != nil {
-		var query string
-		if open {
-			query = `
-				SELECT workflow_id, run_id, workflow_type, start_time, close_time, status, memo
-				FROM executions_visibility
-				WHERE namespace_id = $1 AND status = 1
-				  AND (start_time, run_id) < ($4, $5)
-				ORDER BY start_time DESC, run_id DESC
-				LIMIT $2
-			`
-		} else {
-			query = `
-				SELECT workflow_id, run_id, workflow_type, start_time, close_time, status, memo
-				FROM executions_visibility
-				WHERE namespace_id = $1 AND status != 1
-				  AND (close_time, run_id) < ($4, $5)
-				ORDER BY close_time DESC, run_id DESC
-				LIMIT $2
-			`
-		}
-		rows, err = s.pool.Query(ctx, query, req.NamespaceID, limit+1, nil, *cursorTime, cursorRunID)
-	} else {
-		var query string
-		if open {
-			query = `
-				SELECT workflow_id, run_id, workflow_type, start_time, close_time, status, memo
-				FROM executions_visibility
-				WHERE namespace_id = $1 AND status = 1
-				ORDER BY start_time DESC, run_id DESC
-				LIMIT $2
-			`
-		} else {
-			query = `
-				SELECT workflow_id, run_id, workflow_type, start_time, close_time, status, memo
-				FROM executions_visibility
-				WHERE namespace_id = $1 AND status != 1
-				ORDER BY close_time DESC, run_id DESC
-				LIMIT $2
-			`
+	var infos []*WorkflowExecutionInfo
+	for rows.Next() {
+		var wid, rid, wtype string
+		var start, close *time.Time
+		var status int32
+		var historyLength *int64
+
+		if err := rows.Scan(&wid, &rid, &wtype, &start, &close, &status, &historyLength); err != nil {
+			return nil, err
+		}
+
+		info := &WorkflowExecutionInfo{
+			Execution:     &commonv1.WorkflowExecution{WorkflowId: wid, RunId: rid},
+			Type:          &apiv1.WorkflowType{Name: wtype},
+			Status:        commonv1.ExecutionStatus(status),
+			CorrelationID: correlationID,
 		}
-		rows, err = s.pool.Query(ctx, query, req.NamespaceID, limit+1)
+		if start != nil {
+			info.StartTime = *start
+		}
+		if close != nil {
+			info.CloseTime = *close
+		}
+		if historyLength != nil {
+			info.HistoryLength = *historyLength
+		}
+		infos = append(infos, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp := &ListResponse{}
+	if len(infos) > limit {
+		infos = infos[:limit]
+		last := infos[len(infos)-1]
+		resp.NextPageToken = []byte(last.StartTime.Format(time.RFC3339Nano) + "|" + last.Execution.RunId)
+	}
+	resp.Executions = infos
+	return resp, nil
+}
+
+func (s *PostgresStore) listExecutions(ctx context.Context, req *ListRequest, open bool) (*ListResponse, error) {
+	limit := req.PageSize
+	if limit == 0 {
+		limit = 100
+	}
+	query, args, err := listExecutionsQuery(req, open, limit)
+	if err != nil {
+		return nil, err
 	}
 
+	rows, err := s.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -206,3 +311,54 @@ func (s *PostgresStore) listExecutions(ctx context.Context, req *ListRequest, op
 	resp.Executions = infos
 	return resp, nil
 }
+
+// listExecutionsQuery builds the SELECT for one page of the open or closed
+// listing, fetching limit+1 rows so the caller can tell whether another page
+// follows.
+func listExecutionsQuery(req *ListRequest, open bool, limit int) (string, []interface{}, error) {
+	filter, err := CompileQuery(req.Query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Decode cursor from NextPageToken (format: "timestamp|run_id")
+	var cursorTime *time.Time
+	var cursorRunID string
+	if len(req.NextPageToken) > 0 {
+		parts := strings.SplitN(string(req.NextPageToken), "|", 2)
+		if len(parts) == 2 {
+			t, err := time.Parse(time.RFC3339Nano, parts[0])
+			if err == nil {
+				cursorTime = &t
+				cursorRunID = parts[1]
+			}
+		}
+	}
+
+	sortColumn := "close_time"
+	query := `
+		SELECT workflow_id, run_id, workflow_type, start_time, close_time, status, memo
+		FROM executions_visibility
+		WHERE namespace_id = $1`
+	if open {
+		sortColumn = "start_time"
+		query += ` AND status = 1`
+	} else {
+		query += ` AND status != 1`
+	}
+	args := []interface{}{req.NamespaceID}
+
+	if req.Status != commonv1.ExecutionStatus_EXECUTION_STATUS_UNSPECIFIED {
+		args = append(args, int32(req.Status))
+		query += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+	query, args = filter.AppendSQL(query, args)
+	if cursorTime != nil {
+		args = append(args, *cursorTime, cursorRunID)
+		query += fmt.Sprintf(` AND (%s, run_id) < ($%d, $%d)`, sortColumn, len(args)-1, len(args))
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(` ORDER BY %s DESC, run_id DESC LIMIT $%d`, sortColumn, len(args))
+
+	return query, args, nil
+}
@@ -0,0 +1,246 @@
+package visibility
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	queryparser "github.com/linkflow/engine/internal/visibility"
+)
+
+// ErrInvalidQuery is returned for a ListRequest.Query that does not parse or
+// names a field, operator or clause the listing cannot apply.
+var ErrInvalidQuery = errors.New("invalid visibility query")
+
+type queryField struct {
+	column string
+	kind   fieldKind
+}
+
+type fieldKind int
+
+const (
+	stringField fieldKind = iota
+	statusField
+	timeField
+)
+
+// queryFields maps the lower-cased query field names to their
+// executions_visibility columns.
+var queryFields = map[string]queryField{
+	"workflowid":       {column: "workflow_id", kind: stringField},
+	"runid":            {column: "run_id", kind: stringField},
+	"workflowtype":     {column: "workflow_type", kind: stringField},
+	"workflowtypename": {column: "workflow_type", kind: stringField},
+	"correlationid":    {column: "correlation_id", kind: stringField},
+	"executionstatus":  {column: "status", kind: statusField},
+	"status":           {column: "status", kind: statusField},
+	"starttime":        {column: "start_time", kind: timeField},
+	"closetime":        {column: "close_time", kind: timeField},
+}
+
+// predicate is one condition of a compiled query.
+type predicate struct {
+	field    queryField
+	operator string
+	value    interface{} // string, commonv1.ExecutionStatus or time.Time
+	like     *regexp.Regexp
+}
+
+// Query is a visibility query compiled against the executions_visibility
+// columns. The zero value matches every execution.
+type Query struct {
+	predicates []predicate
+}
+
+// CompileQuery parses query with the visibility grammar and resolves every
+// condition to a column, so a query is either applied in full or rejected
+// with ErrInvalidQuery. ORDER BY is rejected because listings have a
+// fixed order.
+func CompileQuery(query string) (*Query, error) {
+	parsed, err := queryparser.ParseQuery(strings.TrimSpace(query))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	if parsed.OrderBy != "" {
+		return nil, fmt.Errorf("%w: ORDER BY is not supported", ErrInvalidQuery)
+	}
+
+	compiled := &Query{predicates: make([]predicate, 0, len(parsed.Filters))}
+	for _, filter := range parsed.Filters {
+		p, err := compilePredicate(filter)
+		if err != nil {
+			return nil, err
+		}
+		compiled.predicates = append(compiled.predicates, p)
+	}
+	return compiled, nil
+}
+
+func compilePredicate(filter queryparser.Filter) (predicate, error) {
+	field, ok := queryFields[strings.ToLower(filter.Field)]
+	if !ok {
+		return predicate{}, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, filter.Field)
+	}
+	op := strings.ToUpper(filter.Operator)
+	raw, _ := filter.Value.(string)
+	p := predicate{field: field, operator: op}
+
+	switch field.kind {
+	case stringField:
+		switch op {
+		case "=", "!=":
+			p.value = raw
+		case "LIKE":
+			p.value = raw
+			p.like = likePattern(raw)
+		default:
+			return predicate{}, fmt.Errorf("%w: operator %s on %s", ErrInvalidQuery, filter.Operator, filter.Field)
+		}
+	case statusField:
+		if op != "=" && op != "!=" {
+			return predicate{}, fmt.Errorf("%w: operator %s on %s", ErrInvalidQuery, filter.Operator, filter.Field)
+		}
+		status, ok := parseExecutionStatus(raw)
+		if !ok {
+			return predicate{}, fmt.Errorf("%w: unknown execution status %q", ErrInvalidQuery, raw)
+		}
+		p.value = status
+	case timeField:
+		switch op {
+		case "=", "!=", ">", ">=", "<", "<=":
+		default:
+			return predicate{}, fmt.Errorf("%w: operator %s on %s", ErrInvalidQuery, filter.Operator, filter.Field)
+		}
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return predicate{}, fmt.Errorf("%w: %s must be an RFC 3339 time", ErrInvalidQuery, filter.Field)
+		}
+		p.value = t
+	}
+	return p, nil
+}
+
+// parseExecutionStatus accepts status names such as "Running", "TimedOut"
+// or "timed_out".
+func parseExecutionStatus(name string) (commonv1.ExecutionStatus, bool) {
+	normalize := func(s string) string { return strings.ToUpper(strings.ReplaceAll(s, "_", "")) }
+	want := normalize(name)
+	if want == "CANCELED" {
+		want = "CANCELLED"
+	}
+	for value, enumName := range commonv1.ExecutionStatus_name {
+		status := commonv1.ExecutionStatus(value)
+		if status == commonv1.ExecutionStatus_EXECUTION_STATUS_UNSPECIFIED {
+			continue
+		}
+		if normalize(strings.TrimPrefix(enumName, "EXECUTION_STATUS_")) == want {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// likePattern translates a SQL LIKE pattern into an anchored regexp.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// AppendSQL appends the query's predicates to a WHERE clause, numbering
+// placeholders after the existing args.
+func (q *Query) AppendSQL(sql string, args []interface{}) (string, []interface{}) {
+	if q == nil {
+		return sql, args
+	}
+	for _, p := range q.predicates {
+		value := p.value
+		if status, ok := value.(commonv1.ExecutionStatus); ok {
+			value = int32(status)
+		}
+		args = append(args, value)
+		sql += fmt.Sprintf(` AND %s %s $%d`, p.field.column, p.operator, len(args))
+	}
+	return sql, args
+}
+
+// Matches reports whether info satisfies every predicate, with the same
+// semantics as the SQL AppendSQL produces.
+func (q *Query) Matches(info *WorkflowExecutionInfo) bool {
+	if q == nil {
+		return true
+	}
+	for _, p := range q.predicates {
+		if !p.matches(info) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p predicate) matches(info *WorkflowExecutionInfo) bool {
+	switch p.field.kind {
+	case statusField:
+		equal := info.Status == p.value.(commonv1.ExecutionStatus)
+		return equal == (p.operator == "=")
+	case timeField:
+		t := info.StartTime
+		if p.field.column == "close_time" {
+			t = info.CloseTime
+		}
+		if t.IsZero() {
+			// NULL close_time never satisfies a comparison.
+			return false
+		}
+		want := p.value.(time.Time)
+		switch p.operator {
+		case "=":
+			return t.Equal(want)
+		case "!=":
+			return !t.Equal(want)
+		case ">":
+			return t.After(want)
+		case ">=":
+			return !t.Before(want)
+		case "<":
+			return t.Before(want)
+		default:
+			return !t.After(want)
+		}
+	}
+
+	var actual string
+	switch p.field.column {
+	case "workflow_id":
+		actual = info.Execution.GetWorkflowId()
+	case "run_id":
+		actual = info.Execution.GetRunId()
+	case "workflow_type":
+		actual = info.Type.GetName()
+	case "correlation_id":
+		actual = info.CorrelationID
+	}
+	switch p.operator {
+	case "=":
+		return actual == p.value
+	case "!=":
+		return actual != p.value
+	default:
+		return p.like.MatchString(actual)
+	}
+}
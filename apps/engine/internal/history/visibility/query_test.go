@@ -0,0 +1,102 @@
+package visibility
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+)
+
+func TestListExecutionsQueryAppliesFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "no query",
+			wantWhere: "WHERE namespace_id = $1 AND status != 1 ORDER BY",
+			wantArgs:  []interface{}{"default", 11},
+		},
+		{
+			name:      "type and status",
+			query:     "WorkflowType = 'order' AND ExecutionStatus = 'Failed'",
+			wantWhere: "WHERE namespace_id = $1 AND status != 1 AND workflow_type = $2 AND status = $3 ORDER BY",
+			wantArgs:  []interface{}{"default", "order", int32(commonv1.ExecutionStatus_EXECUTION_STATUS_FAILED), 11},
+		},
+		{
+			name:      "like and time",
+			query:     "WorkflowId LIKE 'order-%' AND CloseTime < '2026-01-01T00:00:00Z'",
+			wantWhere: "WHERE namespace_id = $1 AND status != 1 AND workflow_id LIKE $2 AND close_time < $3 ORDER BY",
+			wantArgs:  []interface{}{"default", "order-%", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := listExecutionsQuery(&ListRequest{NamespaceID: "default", Query: tt.query}, false, 10)
+			if err != nil {
+				t.Fatalf("listExecutionsQuery() error = %v", err)
+			}
+			if got := strings.Join(strings.Fields(sql), " "); !strings.Contains(got, tt.wantWhere) {
+				t.Errorf("sql = %q, want it to contain %q", got, tt.wantWhere)
+			}
+			if fmt.Sprint(args) != fmt.Sprint(tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompileQuery(t *testing.T) {
+	info := &WorkflowExecutionInfo{
+		Execution:     &commonv1.WorkflowExecution{WorkflowId: "order-42", RunId: "run-1"},
+		Type:          &apiv1.WorkflowType{Name: "order"},
+		Status:        commonv1.ExecutionStatus_EXECUTION_STATUS_TIMED_OUT,
+		StartTime:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		CorrelationID: "po-7",
+	}
+
+	tests := []struct {
+		query   string
+		want    bool
+		wantErr bool
+	}{
+		{query: "", want: true},
+		{query: "WorkflowType = 'order'", want: true},
+		{query: "WorkflowType = 'Order'", want: false},
+		{query: "WorkflowId LIKE 'order-__'", want: true},
+		{query: "ExecutionStatus = 'TimedOut' AND CorrelationId = 'po-7'", want: true},
+		{query: "ExecutionStatus != 'timed_out'", want: false},
+		{query: "StartTime > '2026-03-01T00:00:00Z'", want: true},
+		{query: "CloseTime < '2030-01-01T00:00:00Z'", want: false},
+		{query: "Owner = 'alice'", wantErr: true},
+		{query: "ExecutionStatus > 'Running'", wantErr: true},
+		{query: "StartTime = 'yesterday'", wantErr: true},
+		{query: "WorkflowId IN ('a', 'b')", wantErr: true},
+		{query: "WorkflowType = 'order' ORDER BY StartTime", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := CompileQuery(tt.query)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidQuery) {
+					t.Fatalf("CompileQuery() error = %v, want ErrInvalidQuery", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompileQuery() error = %v", err)
+			}
+			if got := q.Matches(info); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,15 @@ type Metrics struct {
 	TasksTimedOut   atomic.Int64
 	TasksDLQ        atomic.Int64
 	TasksRejected   atomic.Int64
+	TasksPoisonPill atomic.Int64
+
+	// TasksScheduleToStartTimedOut counts tasks dropped because no worker
+	// started them within their schedule-to-start timeout.
+	TasksScheduleToStartTimedOut atomic.Int64
+
+	// StickyMisses counts tasks on a sticky queue that their bound worker
+	// did not take within the sticky grace period.
+	StickyMisses atomic.Int64
 
 	QueueDepth    atomic.Int64
 	InFlightCount atomic.Int64
@@ -35,12 +44,23 @@ type MetricsSnapshot struct {
 	TasksTimedOut   int64
 	TasksDLQ        int64
 	TasksRejected   int64
+	TasksPoisonPill int64
 	QueueDepth      int64
 	InFlightCount   int64
 	PollerCount     int64
 	P50Latency      time.Duration
 	P95Latency      time.Duration
 	P99Latency      time.Duration
+	// OldestTaskAge is how long the longest-waiting pending task has been
+	// scheduled. Metrics does not see the store, so Snapshot leaves it zero
+	// and callers fill it in from TaskQueue.OldestTaskAge.
+	OldestTaskAge time.Duration
+	// TasksScheduleToStartTimedOut counts tasks dropped because no worker
+	// started them within their schedule-to-start timeout.
+	TasksScheduleToStartTimedOut int64
+	// StickyMisses counts tasks on a sticky queue that their bound worker
+	// did not take within the sticky grace period.
+	StickyMisses int64
 }
 
 func NewMetrics() *Metrics {
@@ -73,6 +93,22 @@ func (m *Metrics) TaskRejected() {
 	m.TasksRejected.Add(1)
 }
 
+// PoisonPillDetected counts a task sent to the DLQ as a suspected poison pill.
+func (m *Metrics) PoisonPillDetected() {
+	m.TasksPoisonPill.Add(1)
+}
+
+// ScheduleToStartTimedOut counts a task dropped because it waited in the
+// queue longer than its schedule-to-start timeout.
+func (m *Metrics) ScheduleToStartTimedOut() {
+	m.TasksScheduleToStartTimedOut.Add(1)
+}
+
+// StickyMissed counts a sticky task that fell back from its bound worker.
+func (m *Metrics) StickyMissed() {
+	m.StickyMisses.Add(1)
+}
+
 func (m *Metrics) SetQueueDepth(n int64) {
 	m.QueueDepth.Store(n)
 }
@@ -110,12 +146,16 @@ func (m *Metrics) Snapshot() MetricsSnapshot {
 		TasksTimedOut:   m.TasksTimedOut.Load(),
 		TasksDLQ:        m.TasksDLQ.Load(),
 		TasksRejected:   m.TasksRejected.Load(),
+		TasksPoisonPill: m.TasksPoisonPill.Load(),
 		QueueDepth:      m.QueueDepth.Load(),
 		InFlightCount:   m.InFlightCount.Load(),
 		PollerCount:     m.PollerCount.Load(),
 		P50Latency:      p50,
 		P95Latency:      p95,
 		P99Latency:      p99,
+
+		TasksScheduleToStartTimedOut: m.TasksScheduleToStartTimedOut.Load(),
+		StickyMisses:                 m.StickyMisses.Load(),
 	}
 }
 
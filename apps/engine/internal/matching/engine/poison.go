@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	// DefaultPoisonPillThreshold is how many unacked deliveries within the
+	// window mark a task as a suspected poison pill.
+	DefaultPoisonPillThreshold = 5
+
+	// DefaultPoisonPillWindow is the period over which unacked deliveries of a
+	// task are counted.
+	DefaultPoisonPillWindow = 30 * time.Minute
+
+	// PoisonPillReason is the DLQ reason for tasks that keep expiring unacked.
+	PoisonPillReason = "suspected poison pill"
+)
+
+// deliveryRecord counts a task's lease expiries since firstExpiry.
+type deliveryRecord struct {
+	expiries    int32
+	firstExpiry time.Time
+}
+
+// recordExpiredDeliveryLocked counts an unacked delivery of taskID and returns
+// the number of unacked deliveries within the current window. It tracks the
+// task ID rather than Attempt, so tasks whose attempt counter is reset by a
+// re-add are still caught. Caller must hold tq.mu.
+func (tq *TaskQueue) recordExpiredDeliveryLocked(taskID string, now time.Time) int32 {
+	rec, ok := tq.deliveries[taskID]
+	if !ok || now.Sub(rec.firstExpiry) > tq.poisonWindow {
+		rec = &deliveryRecord{firstExpiry: now}
+		tq.deliveries[taskID] = rec
+	}
+	rec.expiries++
+	return rec.expiries
+}
+
+// pruneDeliveriesLocked drops records whose window has passed. Caller must
+// hold tq.mu.
+func (tq *TaskQueue) pruneDeliveriesLocked(now time.Time) {
+	for taskID, rec := range tq.deliveries {
+		if now.Sub(rec.firstExpiry) > tq.poisonWindow {
+			delete(tq.deliveries, taskID)
+		}
+	}
+}
+
+// sendPoisonPillToDLQLocked moves a task that exceeded the poison pill
+// threshold to the DLQ. Caller must hold tq.mu.
+func (tq *TaskQueue) sendPoisonPillToDLQLocked(task *Task, deliveries int32, now time.Time) {
+	delete(tq.deliveries, task.ID)
+
+	tq.logger.Warn("routing suspected poison pill task to DLQ",
+		slog.String("task_queue", tq.name),
+		slog.String("task_id", task.ID),
+		slog.String("namespace", task.Namespace),
+		slog.String("workflow_id", task.WorkflowID),
+		slog.String("run_id", task.RunID),
+		slog.String("node_id", task.ActivityID),
+		slog.String("node_type", task.ActivityType),
+		slog.Int("deliveries", int(deliveries)),
+		slog.Duration("window", tq.poisonWindow),
+	)
+
+	entry := &DLQEntry{
+		Task:      task,
+		Reason:    PoisonPillReason,
+		FailedAt:  now,
+		Attempts:  task.Attempt,
+		LastError: "lease timeout",
+	}
+	if err := tq.dlq.Add(entry); err != nil {
+		tq.logger.Error("failed to add task to DLQ",
+			slog.String("task_id", task.ID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	tq.metrics.TaskSentToDLQ()
+	tq.metrics.PoisonPillDetected()
+}
@@ -107,3 +107,42 @@ func (s *PriorityTaskStore) Len(ctx context.Context) (int64, error) {
 	defer s.mu.Unlock()
 	return int64(len(s.taskIndex)), nil
 }
+
+// OldestTask returns the earliest scheduled task at the head of any priority
+// level. Each level is FIFO, so its head is its longest-waiting task.
+func (s *PriorityTaskStore) OldestTask(ctx context.Context) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var oldest *Task
+	for i := 0; i < numPriorityLevels; i++ {
+		elem := s.buckets[i].Front()
+		if elem == nil {
+			continue
+		}
+		task := elem.Value.(*Task)
+		if oldest == nil || task.ScheduledTime.Before(oldest.ScheduledTime) {
+			oldest = task
+		}
+	}
+	return oldest, nil
+}
+
+func (s *PriorityTaskStore) RemoveExpired(ctx context.Context, now time.Time) ([]*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []*Task
+	for i := 0; i < numPriorityLevels; i++ {
+		for elem := s.buckets[i].Front(); elem != nil; {
+			next := elem.Next()
+			if task := elem.Value.(*Task); task.scheduleToStartExpired(now) {
+				s.buckets[i].Remove(elem)
+				delete(s.taskIndex, task.ID)
+				expired = append(expired, task)
+			}
+			elem = next
+		}
+	}
+	return expired, nil
+}
@@ -9,6 +9,7 @@ import (
 // for sticky task queues, allowing workflow tasks to be pinned to specific workers.
 type StickyAffinity struct {
 	affinityMap map[string]affinityRecord // workflowID -> record
+	workerSeen  map[string]time.Time      // identity -> last poll
 	mu          sync.Mutex
 }
 
@@ -21,6 +22,7 @@ type affinityRecord struct {
 func NewStickyAffinity() *StickyAffinity {
 	return &StickyAffinity{
 		affinityMap: make(map[string]affinityRecord),
+		workerSeen:  make(map[string]time.Time),
 	}
 }
 
@@ -56,7 +58,35 @@ func (sa *StickyAffinity) IsExpired(workflowID string, timeout time.Duration) bo
 	if !ok {
 		return true
 	}
-	return time.Since(rec.lastSeen) > timeout
+	lastSeen := rec.lastSeen
+	if seen := sa.workerSeen[rec.identity]; seen.After(lastSeen) {
+		lastSeen = seen
+	}
+	return time.Since(lastSeen) > timeout
+}
+
+// Renew extends the affinity of a workflow that is still bound to identity,
+// so a slow but live worker keeps its sticky cache mid-task. It returns false
+// when the workflow is bound to another worker or to none.
+func (sa *StickyAffinity) Renew(workflowID, identity string) bool {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	rec, ok := sa.affinityMap[workflowID]
+	if !ok || rec.identity != identity {
+		return false
+	}
+	rec.lastSeen = time.Now()
+	sa.affinityMap[workflowID] = rec
+	return true
+}
+
+// RenewWorker extends every affinity bound to identity. A worker that polls
+// is alive, so its workflows stay bound to it.
+func (sa *StickyAffinity) RenewWorker(identity string) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	sa.workerSeen[identity] = time.Now()
 }
 
 // Touch updates the last-seen time for a workflow affinity.
@@ -15,6 +15,7 @@ type Task struct {
 	WorkflowID       string
 	RunID            string
 	Namespace        string
+	TaskQueue        string // Name of the queue within Namespace
 	ActivityID       string
 	ActivityType     string
 	Input            []byte
@@ -24,6 +25,20 @@ type Task struct {
 	Priority         int32
 	TaskType         int32
 	ScheduledEventID int64
+
+	// ScheduleToStartTimeout is how long the task may wait in the queue for
+	// a worker. A task still queued after ScheduledTime plus this timeout is
+	// dropped instead of dispatched. Zero never expires.
+	ScheduleToStartTimeout time.Duration
+}
+
+// scheduleToStartExpired reports whether the task waited longer than its
+// ScheduleToStartTimeout as of now.
+func (t *Task) scheduleToStartExpired(now time.Time) bool {
+	if t.ScheduleToStartTimeout <= 0 || t.ScheduledTime.IsZero() {
+		return false
+	}
+	return now.After(t.ScheduledTime.Add(t.ScheduleToStartTimeout))
 }
 
 type Poller struct {
@@ -15,18 +15,36 @@ import (
 )
 
 const (
-	DefaultLeaseTimeout = 60 * time.Second
-	DefaultMaxRetries   = 3
+	DefaultLeaseTimeout    = 60 * time.Second
+	DefaultMaxRetries      = 3
+	DefaultLongPollTimeout = 30 * time.Second
+
+	// DefaultStickyGracePeriod is how long a task may wait on a sticky queue
+	// for its bound worker before it falls back.
+	DefaultStickyGracePeriod = 10 * time.Second
 )
 
+// stickyRequeueBackoff is how long a poller of a shared store waits after
+// putting back a task bound to another worker.
+const stickyRequeueBackoff = 100 * time.Millisecond
+
 var ErrTaskExists = errors.New("task already exists")
 
 // TaskStore defines the interface for task persistence.
 type TaskStore interface {
 	AddTask(ctx context.Context, task *Task) error
+	// PollTask removes and returns the next task, or nil when there is none.
+	// Stores shared between matching hosts block for up to timeout waiting
+	// for one; in-process stores return immediately.
 	PollTask(ctx context.Context, timeout time.Duration) (*Task, error)
 	AckTask(ctx context.Context, taskID string) (bool, error)
 	Len(ctx context.Context) (int64, error)
+	// OldestTask returns the longest-waiting pending task without removing
+	// it, or nil when the store is empty.
+	OldestTask(ctx context.Context) (*Task, error)
+	// RemoveExpired removes and returns the pending tasks whose
+	// schedule-to-start timeout passed before now.
+	RemoveExpired(ctx context.Context, now time.Time) ([]*Task, error)
 }
 
 // MemoryTaskStore is an in-memory implementation of TaskStore.
@@ -99,6 +117,32 @@ func (s *MemoryTaskStore) Len(ctx context.Context) (int64, error) {
 	return int64(s.tasks.Len()), nil
 }
 
+func (s *MemoryTaskStore) OldestTask(ctx context.Context) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem := s.tasks.Front(); elem != nil {
+		return elem.Value.(*Task), nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryTaskStore) RemoveExpired(ctx context.Context, now time.Time) ([]*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []*Task
+	for elem := s.tasks.Front(); elem != nil; {
+		next := elem.Next()
+		if task := elem.Value.(*Task); task.scheduleToStartExpired(now) {
+			s.tasks.Remove(elem)
+			delete(s.tasksMap, task.ID)
+			expired = append(expired, task)
+		}
+		elem = next
+	}
+	return expired, nil
+}
+
 // RedisTaskStore is a Redis-backed implementation of TaskStore.
 type RedisTaskStore struct {
 	client        *redis.Client
@@ -127,19 +171,21 @@ func (s *RedisTaskStore) PollTask(ctx context.Context, timeout time.Duration) (*
 		return nil, err
 	}
 
-	// LMOVE atomically moves a task from the main queue to the processing queue.
-	// If the worker crashes, the task remains in the processing queue for redelivery.
-	result, err := s.client.LMove(ctx, s.queueKey, s.processingKey, "LEFT", "RIGHT").Result()
+	// BLMOVE atomically moves a task from the main queue to the processing
+	// queue, blocking until one arrives. If the worker crashes, the task
+	// remains in the processing queue for redelivery. A zero BLMOVE timeout
+	// blocks forever, so non-positive timeouts use a plain LMOVE.
+	var result string
+	var err error
+	if timeout > 0 {
+		result, err = s.client.BLMove(ctx, s.queueKey, s.processingKey, "LEFT", "RIGHT", timeout).Result()
+	} else {
+		result, err = s.client.LMove(ctx, s.queueKey, s.processingKey, "LEFT", "RIGHT").Result()
+	}
+	if err == redis.Nil {
+		return nil, nil
+	}
 	if err != nil {
-		if err == redis.Nil {
-			// No tasks available; sleep briefly to avoid busy-spinning
-			select {
-			case <-time.After(timeout):
-				return nil, nil
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			}
-		}
 		return nil, err
 	}
 
@@ -178,6 +224,52 @@ func (s *RedisTaskStore) Len(ctx context.Context) (int64, error) {
 	return s.client.LLen(ctx, s.queueKey).Result()
 }
 
+// OldestTask reads the head of the queue list with LINDEX, which is O(1) and
+// leaves the task in place.
+func (s *RedisTaskStore) OldestTask(ctx context.Context) (*Task, error) {
+	result, err := s.client.LIndex(ctx, s.queueKey, 0).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var task Task
+	if err := json.Unmarshal([]byte(result), &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// RemoveExpired scans the queue list and LREMs the expired entries. A task
+// another host polls in the meantime is not returned.
+func (s *RedisTaskStore) RemoveExpired(ctx context.Context, now time.Time) ([]*Task, error) {
+	items, err := s.client.LRange(ctx, s.queueKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []*Task
+	for _, item := range items {
+		var task Task
+		if err := json.Unmarshal([]byte(item), &task); err != nil {
+			continue
+		}
+		if !task.scheduleToStartExpired(now) {
+			continue
+		}
+		removed, err := s.client.LRem(ctx, s.queueKey, 1, item).Result()
+		if err != nil {
+			return expired, err
+		}
+		if removed > 0 {
+			expired = append(expired, &task)
+		}
+	}
+	return expired, nil
+}
+
 // TaskQueueConfig holds optional configuration for NewTaskQueue.
 type TaskQueueConfig struct {
 	DLQ            *DeadLetterQueue
@@ -186,12 +278,40 @@ type TaskQueueConfig struct {
 	WAL            *WAL
 	StickyAffinity *StickyAffinity
 	Logger         *slog.Logger
+
+	// PoisonPillThreshold is the number of unacked deliveries within
+	// PoisonPillWindow after which a task goes straight to the DLQ,
+	// regardless of MaxRetries (default DefaultPoisonPillThreshold).
+	PoisonPillThreshold int32
+	PoisonPillWindow    time.Duration
+
+	// LongPollTimeout is how long Poll waits for a task before returning
+	// nil (default DefaultLongPollTimeout).
+	LongPollTimeout time.Duration
+
+	// OnScheduleToStartTimeout is called, on its own goroutine, with each
+	// task dropped because no worker started it within its
+	// ScheduleToStartTimeout, so the timeout can be recorded on the
+	// workflow. Nil only counts and logs the drop.
+	OnScheduleToStartTimeout func(*Task)
+
+	// StickyGracePeriod is how long a task on a sticky queue may wait for
+	// the worker its workflow is bound to (default
+	// DefaultStickyGracePeriod). After that it is a sticky miss: any worker
+	// may take it, and FallbackStickyTasks hands it to OnStickyMiss.
+	StickyGracePeriod time.Duration
+
+	// OnStickyMiss is called with each task FallbackStickyTasks takes off
+	// the sticky queue, e.g. to add it to the workflow's normal queue. Nil
+	// leaves the task on the sticky queue, unbound.
+	OnStickyMiss func(*Task)
 }
 
 type TaskQueue struct {
 	name           string
 	kind           TaskQueueKind
 	store          TaskStore
+	sharedStore    bool // store is shared with other hosts and blocks in PollTask
 	pollers        *list.List
 	rateLimiter    *rate.Limiter
 	metrics        *Metrics
@@ -200,6 +320,8 @@ type TaskQueue struct {
 	inFlightExpiry map[string]time.Time
 	leaseTimeout   time.Duration
 
+	longPollTimeout time.Duration
+
 	// DLQ support
 	dlq        *DeadLetterQueue
 	maxRetries int32
@@ -211,7 +333,16 @@ type TaskQueue struct {
 	wal *WAL
 
 	// Sticky queue support
-	stickyAffinity *StickyAffinity
+	stickyAffinity    *StickyAffinity
+	stickyGracePeriod time.Duration
+	onStickyMiss      func(*Task)
+
+	// Poison pill detection
+	deliveries      map[string]*deliveryRecord
+	poisonThreshold int32
+	poisonWindow    time.Duration
+
+	onScheduleToStartTimeout func(*Task)
 
 	logger *slog.Logger
 }
@@ -240,6 +371,20 @@ func NewTaskQueueWithConfig(name string, kind TaskQueueKind, rateLimit float64,
 		maxRetries = DefaultMaxRetries
 	}
 
+	poisonThreshold := cfg.PoisonPillThreshold
+	if poisonThreshold <= 0 {
+		poisonThreshold = DefaultPoisonPillThreshold
+	}
+	poisonWindow := cfg.PoisonPillWindow
+	if poisonWindow <= 0 {
+		poisonWindow = DefaultPoisonPillWindow
+	}
+
+	longPollTimeout := cfg.LongPollTimeout
+	if longPollTimeout <= 0 {
+		longPollTimeout = DefaultLongPollTimeout
+	}
+
 	bp := cfg.Backpressure
 	if bp == nil {
 		bp = NewBackpressure(DefaultSoftLimit, DefaultHardLimit, logger)
@@ -252,23 +397,37 @@ func NewTaskQueueWithConfig(name string, kind TaskQueueKind, rateLimit float64,
 			sa = NewStickyAffinity()
 		}
 	}
+	stickyGracePeriod := cfg.StickyGracePeriod
+	if stickyGracePeriod <= 0 {
+		stickyGracePeriod = DefaultStickyGracePeriod
+	}
 
 	return &TaskQueue{
-		name:           name,
-		kind:           kind,
-		store:          store,
-		pollers:        list.New(),
-		rateLimiter:    rate.NewLimiter(rate.Limit(rateLimit), burst),
-		metrics:        NewMetrics(),
-		inFlight:       make(map[string]*Task),
-		inFlightExpiry: make(map[string]time.Time),
-		leaseTimeout:   DefaultLeaseTimeout,
-		dlq:            cfg.DLQ,
-		maxRetries:     maxRetries,
-		backpressure:   bp,
-		wal:            cfg.WAL,
-		stickyAffinity: sa,
-		logger:         logger,
+		name:            name,
+		kind:            kind,
+		store:           store,
+		sharedStore:     redisClient != nil,
+		pollers:         list.New(),
+		rateLimiter:     rate.NewLimiter(rate.Limit(rateLimit), burst),
+		metrics:         NewMetrics(),
+		inFlight:        make(map[string]*Task),
+		inFlightExpiry:  make(map[string]time.Time),
+		leaseTimeout:    DefaultLeaseTimeout,
+		longPollTimeout: longPollTimeout,
+		dlq:             cfg.DLQ,
+		maxRetries:      maxRetries,
+		backpressure:    bp,
+		wal:             cfg.WAL,
+		stickyAffinity:  sa,
+		deliveries:      make(map[string]*deliveryRecord),
+		poisonThreshold: poisonThreshold,
+		poisonWindow:    poisonWindow,
+		logger:          logger,
+
+		onScheduleToStartTimeout: cfg.OnScheduleToStartTimeout,
+
+		stickyGracePeriod: stickyGracePeriod,
+		onStickyMiss:      cfg.OnStickyMiss,
 	}
 }
 
@@ -284,6 +443,14 @@ func (tq *TaskQueue) Metrics() *Metrics {
 	return tq.metrics
 }
 
+// BackpressureState returns the pressure level evaluated by the last AddTask.
+func (tq *TaskQueue) BackpressureState() BackpressureState {
+	if tq.backpressure == nil {
+		return BackpressureNormal
+	}
+	return tq.backpressure.State()
+}
+
 func (tq *TaskQueue) AddTask(task *Task) error {
 	tq.mu.Lock()
 	defer tq.mu.Unlock()
@@ -297,6 +464,12 @@ func (tq *TaskQueue) AddTask(task *Task) error {
 
 	tq.metrics.TaskAdded()
 
+	// A task recovered or forwarded after its deadline is never dispatched.
+	if task.scheduleToStartExpired(time.Now()) {
+		tq.expireTaskLocked(task)
+		return nil
+	}
+
 	// Sticky affinity: bind workflow to any existing worker, or leave unbound
 	if tq.kind == TaskQueueKindSticky && tq.stickyAffinity != nil {
 		// If there's no existing affinity, the task is available to any worker.
@@ -327,6 +500,9 @@ func (tq *TaskQueue) AddTask(task *Task) error {
 	return nil
 }
 
+// Poll long-polls for a task. It returns nil without an error when no task
+// arrives within the queue's long-poll timeout, and ctx.Err() when ctx ends
+// first.
 func (tq *TaskQueue) Poll(ctx context.Context, identity string) (*Task, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -339,61 +515,300 @@ func (tq *TaskQueue) Poll(ctx context.Context, identity string) (*Task, error) {
 		return nil, ErrRateLimited
 	}
 	tq.mu.Unlock()
+	tq.renewStickyWorker(identity)
 
-	for {
-		task, err := tq.store.PollTask(ctx, time.Second)
-		if err != nil {
+	deadline := time.Now().Add(tq.longPollTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	var task *Task
+	var err error
+	if tq.sharedStore {
+		task, err = tq.pollSharedStore(ctx, identity, deadline)
+	} else {
+		task, err = tq.pollLocalStore(ctx, identity, deadline)
+	}
+	if task != nil {
+		// Update queue depth gauge
+		depth, _ := tq.store.Len(context.Background())
+		tq.metrics.SetQueueDepth(depth)
+	}
+	return task, err
+}
+
+// TryPoll takes a task that is already available without waiting for one.
+// It returns nil when the queue has no task for identity. An empty queue
+// does not count against the rate limit, so callers may poll many queues in
+// turn.
+func (tq *TaskQueue) TryPoll(ctx context.Context, identity string) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tq.renewStickyWorker(identity)
+	depth, err := tq.store.Len(ctx)
+	if err != nil || depth == 0 {
+		return nil, err
+	}
+
+	tq.mu.Lock()
+	if !tq.rateLimiter.Allow() {
+		tq.mu.Unlock()
+		return nil, ErrRateLimited
+	}
+
+	var task *Task
+	if tq.sharedStore {
+		tq.mu.Unlock()
+		task, err = tq.store.PollTask(ctx, 0)
+		if err != nil || task == nil {
+			return nil, err
+		}
+		tq.mu.Lock()
+		if task.scheduleToStartExpired(time.Now()) {
+			tq.expireTaskLocked(task)
+			tq.mu.Unlock()
+			return nil, nil
+		}
+		claimed := tq.claimStickyLocked(task, identity)
+		if claimed {
+			tq.startTaskLocked(task)
+		}
+		tq.mu.Unlock()
+		if !claimed {
+			return nil, tq.store.AddTask(ctx, task)
+		}
+	} else {
+		task, err = tq.takeStoredTaskLocked(ctx, identity)
+		tq.mu.Unlock()
+		if err != nil || task == nil {
 			return nil, err
 		}
+	}
 
-		if task != nil {
-			// Sticky queue: check affinity
-			if tq.kind == TaskQueueKindSticky && tq.stickyAffinity != nil {
-				boundIdentity, hasBind := tq.stickyAffinity.GetIdentity(task.WorkflowID)
-				if hasBind && boundIdentity != identity {
-					// Check if the affinity has expired (worker didn't poll in time)
-					if !tq.stickyAffinity.IsExpired(task.WorkflowID, tq.leaseTimeout) {
-						// Put task back and continue polling
-						_ = tq.store.AddTask(ctx, task)
-						if err := ctx.Err(); err != nil {
-							return nil, err
-						}
-						// Brief backoff to avoid busy-spinning
-						time.Sleep(100 * time.Millisecond)
-						continue
-					}
-					// Affinity expired, allow any worker
-					tq.stickyAffinity.Remove(task.WorkflowID)
-				}
-				// Bind or refresh affinity
-				tq.stickyAffinity.Bind(task.WorkflowID, identity)
+	depth, _ = tq.store.Len(context.Background())
+	tq.metrics.SetQueueDepth(depth)
+	return task, nil
+}
+
+// pollLocalStore takes a stored task or, when there is none, registers a
+// poller that AddTask hands the next task to. Checking the store and
+// registering happen under tq.mu so a task added in between is not missed.
+func (tq *TaskQueue) pollLocalStore(ctx context.Context, identity string, deadline time.Time) (*Task, error) {
+	tq.mu.Lock()
+	task, err := tq.takeStoredTaskLocked(ctx, identity)
+	if err != nil || task != nil {
+		tq.mu.Unlock()
+		return task, err
+	}
+
+	poller := &Poller{
+		Identity:  identity,
+		ResultCh:  make(chan *Task, 1),
+		CreatedAt: time.Now(),
+	}
+	elem := tq.pollers.PushBack(poller)
+	tq.metrics.SetPollerCount(int64(tq.pollers.Len()))
+	tq.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case task := <-poller.ResultCh:
+		return task, nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+	}
+
+	tq.mu.Lock()
+	tq.pollers.Remove(elem)
+	tq.metrics.SetPollerCount(int64(tq.pollers.Len()))
+	tq.mu.Unlock()
+
+	// The task may have been handed over while the wait ended. It is already
+	// in flight, so return it rather than leave it to the lease timeout.
+	select {
+	case task := <-poller.ResultCh:
+		return task, nil
+	default:
+		return nil, err
+	}
+}
+
+// takeStoredTaskLocked pops the first stored task this identity may run.
+// Tasks bound to another sticky worker are put back and tasks past their
+// schedule-to-start timeout are dropped; each stored task is looked at most
+// once per call.
+func (tq *TaskQueue) takeStoredTaskLocked(ctx context.Context, identity string) (*Task, error) {
+	pending, err := tq.store.Len(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for ; pending > 0; pending-- {
+		task, err := tq.store.PollTask(ctx, 0)
+		if err != nil || task == nil {
+			return nil, err
+		}
+		if task.scheduleToStartExpired(time.Now()) {
+			tq.expireTaskLocked(task)
+			continue
+		}
+		if !tq.claimStickyLocked(task, identity) {
+			if err := tq.store.AddTask(ctx, task); err != nil {
+				return nil, err
 			}
+			continue
+		}
+		tq.startTaskLocked(task)
+		return task, nil
+	}
+	return nil, nil
+}
 
-			tq.mu.Lock()
-			tq.inFlight[task.ID] = task
-			tq.inFlightExpiry[task.ID] = time.Now().Add(tq.leaseTimeout)
-			tq.metrics.SetInFlightCount(int64(len(tq.inFlight)))
-			tq.mu.Unlock()
+// pollSharedStore blocks in the store until a task arrives or the deadline
+// passes. Tasks added by other matching hosts only reach this host through
+// the store, so registered pollers would miss them.
+func (tq *TaskQueue) pollSharedStore(ctx context.Context, identity string, deadline time.Time) (*Task, error) {
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, nil
+		}
 
-			// Update queue depth gauge
-			depth, _ := tq.store.Len(context.Background())
-			tq.metrics.SetQueueDepth(depth)
+		task, err := tq.store.PollTask(ctx, remaining)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			if task != nil {
+				_ = tq.store.AddTask(context.Background(), task)
+			}
+			return nil, err
+		}
+		if task == nil {
+			continue
+		}
 
-			tq.metrics.TaskDispatched()
-			tq.metrics.RecordLatency(time.Since(task.ScheduledTime))
+		tq.mu.Lock()
+		if task.scheduleToStartExpired(time.Now()) {
+			tq.expireTaskLocked(task)
+			tq.mu.Unlock()
+			continue
+		}
+		claimed := tq.claimStickyLocked(task, identity)
+		if claimed {
+			tq.startTaskLocked(task)
+		}
+		tq.mu.Unlock()
+		if claimed {
 			return task, nil
 		}
 
-		if err := ctx.Err(); err != nil {
+		// Put the task back for its worker and back off so this poller does
+		// not immediately take it again.
+		if err := tq.store.AddTask(ctx, task); err != nil {
 			return nil, err
 		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(stickyRequeueBackoff):
+		}
+	}
+}
+
+// claimStickyLocked reports whether identity may run task. On a sticky queue
+// a task bound to another worker is refused until that binding expires or
+// the task has waited out the sticky grace period; an accepted task is bound
+// to identity.
+func (tq *TaskQueue) claimStickyLocked(task *Task, identity string) bool {
+	if tq.kind != TaskQueueKindSticky || tq.stickyAffinity == nil {
+		return true
+	}
+	if boundIdentity, hasBind := tq.stickyAffinity.GetIdentity(task.WorkflowID); hasBind && boundIdentity != identity {
+		if !tq.stickyAffinity.IsExpired(task.WorkflowID, tq.leaseTimeout) {
+			if !tq.stickyGraceExpired(task, time.Now()) {
+				return false
+			}
+			// The bound worker is alive but has not taken the task in
+			// time; let another worker rebuild the workflow's state.
+			tq.metrics.StickyMissed()
+		}
+		tq.stickyAffinity.Remove(task.WorkflowID)
 	}
+	// Bind or refresh affinity
+	tq.stickyAffinity.Bind(task.WorkflowID, identity)
+	return true
+}
+
+// startTaskLocked marks a task dispatched to a poller as in flight.
+func (tq *TaskQueue) startTaskLocked(task *Task) {
+	task.StartedTime = time.Now()
+	tq.inFlight[task.ID] = task
+	tq.inFlightExpiry[task.ID] = time.Now().Add(tq.leaseTimeout)
+	tq.metrics.SetInFlightCount(int64(len(tq.inFlight)))
+
+	tq.metrics.TaskDispatched()
+	tq.metrics.RecordLatency(time.Since(task.ScheduledTime))
+}
+
+// expireTaskLocked drops a task that waited longer than its
+// schedule-to-start timeout. The task was already taken from the store; the
+// WAL records it as done so recovery does not bring it back.
+func (tq *TaskQueue) expireTaskLocked(task *Task) {
+	tq.metrics.ScheduleToStartTimedOut()
+	tq.logger.Warn("dropping task past its schedule-to-start timeout",
+		slog.String("task_queue", tq.name),
+		slog.String("task_id", task.ID),
+		slog.String("workflow_id", task.WorkflowID),
+		slog.Duration("schedule_to_start_timeout", task.ScheduleToStartTimeout),
+	)
+
+	if tq.wal != nil {
+		if err := tq.wal.WriteComplete(task.ID); err != nil {
+			tq.logger.Error("failed to write WAL completion", slog.String("task_id", task.ID), slog.String("error", err.Error()))
+		}
+	}
+	if tq.onScheduleToStartTimeout != nil {
+		go tq.onScheduleToStartTimeout(task)
+	}
+}
+
+// DropExpiredTasks removes queued tasks whose schedule-to-start timeout has
+// passed, so they are failed even when no worker polls the queue. It returns
+// the number of tasks dropped.
+func (tq *TaskQueue) DropExpiredTasks() int {
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	expired, err := tq.store.RemoveExpired(context.Background(), time.Now())
+	if err != nil {
+		tq.logger.Error("failed to remove expired tasks",
+			slog.String("task_queue", tq.name),
+			slog.String("error", err.Error()),
+		)
+	}
+	for _, task := range expired {
+		tq.expireTaskLocked(task)
+	}
+
+	if len(expired) > 0 {
+		depth, _ := tq.store.Len(context.Background())
+		tq.metrics.SetQueueDepth(depth)
+	}
+	return len(expired)
 }
 
 func (tq *TaskQueue) CompleteTask(taskID string) bool {
 	tq.mu.Lock()
 	defer tq.mu.Unlock()
 
+	delete(tq.deliveries, taskID)
+
 	if _, exists := tq.inFlight[taskID]; exists {
 		delete(tq.inFlight, taskID)
 		delete(tq.inFlightExpiry, taskID)
@@ -429,29 +844,23 @@ func (tq *TaskQueue) FailTask(taskID string) {
 	tq.metrics.TaskFailed()
 }
 
+// tryDispatchLocked hands task to the longest-waiting poller allowed to run
+// it. ResultCh is buffered and pollers only leave the list under tq.mu, so
+// the send never blocks.
 func (tq *TaskQueue) tryDispatchLocked(task *Task) bool {
-	elem := tq.pollers.Front()
-	if elem == nil {
-		return false
-	}
-
-	poller := elem.Value.(*Poller)
-	tq.pollers.Remove(elem)
+	for elem := tq.pollers.Front(); elem != nil; elem = elem.Next() {
+		poller := elem.Value.(*Poller)
+		if !tq.claimStickyLocked(task, poller.Identity) {
+			continue
+		}
 
-	// Sticky affinity: bind the workflow to this poller's identity
-	if tq.kind == TaskQueueKindSticky && tq.stickyAffinity != nil {
-		tq.stickyAffinity.Bind(task.WorkflowID, poller.Identity)
+		tq.pollers.Remove(elem)
+		tq.metrics.SetPollerCount(int64(tq.pollers.Len()))
+		tq.startTaskLocked(task)
+		poller.ResultCh <- task
+		return true
 	}
-
-	task.StartedTime = time.Now()
-	tq.inFlight[task.ID] = task
-	tq.inFlightExpiry[task.ID] = time.Now().Add(tq.leaseTimeout)
-	tq.metrics.SetInFlightCount(int64(len(tq.inFlight)))
-	poller.ResultCh <- task
-
-	tq.metrics.TaskDispatched()
-	tq.metrics.RecordLatency(time.Since(task.ScheduledTime))
-	return true
+	return false
 }
 
 func (tq *TaskQueue) PendingTaskCount() int {
@@ -459,12 +868,147 @@ func (tq *TaskQueue) PendingTaskCount() int {
 	return int(len)
 }
 
+// OldestTaskAge returns how long the longest-waiting pending task has been
+// scheduled, or zero when the queue is empty or the store cannot be read.
+func (tq *TaskQueue) OldestTaskAge() time.Duration {
+	task, err := tq.store.OldestTask(context.Background())
+	if err != nil {
+		tq.logger.Warn("failed to read oldest task",
+			slog.String("task_queue", tq.name),
+			slog.String("error", err.Error()),
+		)
+		return 0
+	}
+	if task == nil || task.ScheduledTime.IsZero() {
+		return 0
+	}
+	if age := time.Since(task.ScheduledTime); age > 0 {
+		return age
+	}
+	return 0
+}
+
 func (tq *TaskQueue) PollerCount() int {
 	tq.mu.Lock()
 	defer tq.mu.Unlock()
 	return tq.pollers.Len()
 }
 
+// StickyWorker returns the worker identity workflowID's tasks are bound to on
+// a sticky queue, or "" when any worker may take them.
+func (tq *TaskQueue) StickyWorker(workflowID string) string {
+	if tq.kind != TaskQueueKindSticky || tq.stickyAffinity == nil {
+		return ""
+	}
+	identity, ok := tq.stickyAffinity.GetIdentity(workflowID)
+	if !ok || tq.stickyAffinity.IsExpired(workflowID, tq.leaseTimeout) {
+		return ""
+	}
+	return identity
+}
+
+// renewStickyWorker keeps the workflows bound to a polling worker bound to it.
+func (tq *TaskQueue) renewStickyWorker(identity string) {
+	if tq.kind == TaskQueueKindSticky && tq.stickyAffinity != nil && identity != "" {
+		tq.stickyAffinity.RenewWorker(identity)
+	}
+}
+
+// stickyGraceExpired reports whether task has waited on the sticky queue
+// longer than the sticky grace period as of now.
+func (tq *TaskQueue) stickyGraceExpired(task *Task, now time.Time) bool {
+	return !task.ScheduledTime.IsZero() && now.Sub(task.ScheduledTime) > tq.stickyGracePeriod
+}
+
+// HeartbeatTask extends the lease of an in-flight task and, on a sticky
+// queue, renews its workflow's affinity to identity. It returns false when
+// the task is not in flight on this queue.
+func (tq *TaskQueue) HeartbeatTask(taskID, identity string) bool {
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	task, ok := tq.inFlight[taskID]
+	if !ok {
+		return false
+	}
+	tq.inFlightExpiry[taskID] = time.Now().Add(tq.leaseTimeout)
+	if tq.kind == TaskQueueKindSticky && tq.stickyAffinity != nil && identity != "" {
+		tq.stickyAffinity.Renew(task.WorkflowID, identity)
+	}
+	return true
+}
+
+// FallbackStickyTasks takes the tasks that waited on a sticky queue longer
+// than the sticky grace period off it and hands them to OnStickyMiss. Without
+// OnStickyMiss they stay queued but are unbound, so any worker may take them.
+// It returns the number of sticky misses.
+func (tq *TaskQueue) FallbackStickyTasks() int {
+	if tq.kind != TaskQueueKindSticky || tq.stickyAffinity == nil {
+		return 0
+	}
+
+	tq.mu.Lock()
+	ctx := context.Background()
+	now := time.Now()
+	var missed []*Task
+	misses := 0
+	pending, _ := tq.store.Len(ctx)
+	for ; pending > 0; pending-- {
+		task, err := tq.store.PollTask(ctx, 0)
+		if err != nil || task == nil {
+			break
+		}
+		if !tq.stickyGraceExpired(task, now) {
+			if err := tq.store.AddTask(ctx, task); err != nil {
+				tq.logger.Error("failed to requeue sticky task",
+					slog.String("task_id", task.ID),
+					slog.String("error", err.Error()),
+				)
+			}
+			continue
+		}
+
+		_, bound := tq.stickyAffinity.GetIdentity(task.WorkflowID)
+		tq.stickyAffinity.Remove(task.WorkflowID)
+		if tq.onStickyMiss == nil {
+			if err := tq.store.AddTask(ctx, task); err != nil {
+				tq.logger.Error("failed to requeue sticky task",
+					slog.String("task_id", task.ID),
+					slog.String("error", err.Error()),
+				)
+			}
+			if !bound {
+				// Already unbound by an earlier pass.
+				continue
+			}
+		} else {
+			if tq.wal != nil {
+				if err := tq.wal.WriteComplete(task.ID); err != nil {
+					tq.logger.Error("failed to write WAL completion", slog.String("task_id", task.ID), slog.String("error", err.Error()))
+				}
+			}
+			missed = append(missed, task)
+		}
+		misses++
+		tq.metrics.StickyMissed()
+	}
+	depth, _ := tq.store.Len(ctx)
+	tq.metrics.SetQueueDepth(depth)
+	tq.mu.Unlock()
+
+	// The callback may add the task to another queue, so it runs without
+	// tq.mu held.
+	for _, task := range missed {
+		tq.logger.Info("sticky task fell back from its worker",
+			slog.String("task_queue", tq.name),
+			slog.String("task_id", task.ID),
+			slog.String("workflow_id", task.WorkflowID),
+		)
+		tq.onStickyMiss(task)
+	}
+	return misses
+}
+
 func (tq *TaskQueue) RequeueExpiredTasks() int {
 	tq.mu.Lock()
 	defer tq.mu.Unlock()
@@ -480,6 +1024,15 @@ func (tq *TaskQueue) RequeueExpiredTasks() int {
 
 			tq.metrics.TaskTimedOut()
 
+			// A task that keeps expiring without an ack likely crashes its
+			// worker; stop redelivering it before it takes down more pollers.
+			if tq.dlq != nil {
+				if deliveries := tq.recordExpiredDeliveryLocked(taskID, now); deliveries > tq.poisonThreshold {
+					tq.sendPoisonPillToDLQLocked(task, deliveries, now)
+					continue
+				}
+			}
+
 			// Check if task has exceeded max retries
 			if tq.dlq != nil && task.Attempt >= tq.maxRetries {
 				entry := &DLQEntry{
@@ -513,6 +1066,7 @@ func (tq *TaskQueue) RequeueExpiredTasks() int {
 		}
 	}
 
+	tq.pruneDeliveriesLocked(now)
 	tq.metrics.SetInFlightCount(int64(len(tq.inFlight)))
 
 	return requeued
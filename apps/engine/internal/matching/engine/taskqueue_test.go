@@ -197,3 +197,440 @@ func TestTaskQueue_RateLimiting(t *testing.T) {
 func taskID(i int) string {
 	return fmt.Sprintf("task-%d", i)
 }
+
+func TestTaskQueue_PoisonPillSentToDLQ(t *testing.T) {
+	dlq := NewDeadLetterQueue(10, nil)
+	tq := NewTaskQueueWithConfig("test-queue", TaskQueueKindNormal, 1000, 100, nil, TaskQueueConfig{
+		DLQ:                 dlq,
+		MaxRetries:          100,
+		PoisonPillThreshold: 2,
+		PoisonPillWindow:    time.Minute,
+	})
+	tq.leaseTimeout = time.Millisecond
+
+	if err := tq.AddTask(&Task{ID: "task-1", WorkflowID: "workflow-1", ActivityID: "node-1", ScheduledTime: time.Now()}); err != nil {
+		t.Fatalf("AddTask error = %v", err)
+	}
+
+	for delivery := 1; delivery <= 3; delivery++ {
+		task, err := tq.Poll(context.Background(), "worker-1")
+		if err != nil || task == nil {
+			t.Fatalf("delivery %d: Poll = %v, %v", delivery, task, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		tq.RequeueExpiredTasks()
+	}
+
+	if tq.PendingTaskCount() != 0 {
+		t.Errorf("PendingTaskCount = %d, want 0", tq.PendingTaskCount())
+	}
+	entries := dlq.List()
+	if len(entries) != 1 || entries[0].Reason != PoisonPillReason {
+		t.Fatalf("DLQ entries = %+v, want one %q entry", entries, PoisonPillReason)
+	}
+	if got := tq.Metrics().Snapshot().TasksPoisonPill; got != 1 {
+		t.Errorf("TasksPoisonPill = %d, want 1", got)
+	}
+}
+
+func TestTaskQueue_AckResetsPoisonPillCount(t *testing.T) {
+	dlq := NewDeadLetterQueue(10, nil)
+	tq := NewTaskQueueWithConfig("test-queue", TaskQueueKindNormal, 1000, 100, nil, TaskQueueConfig{
+		DLQ:                 dlq,
+		MaxRetries:          100,
+		PoisonPillThreshold: 1,
+		PoisonPillWindow:    time.Minute,
+	})
+	tq.leaseTimeout = time.Millisecond
+
+	for round := 0; round < 3; round++ {
+		if err := tq.AddTask(&Task{ID: "task-1", WorkflowID: "workflow-1", ScheduledTime: time.Now()}); err != nil {
+			t.Fatalf("AddTask error = %v", err)
+		}
+		if _, err := tq.Poll(context.Background(), "worker-1"); err != nil {
+			t.Fatalf("Poll error = %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		tq.RequeueExpiredTasks()
+
+		// The redelivery succeeds and is acked.
+		if _, err := tq.Poll(context.Background(), "worker-1"); err != nil {
+			t.Fatalf("Poll error = %v", err)
+		}
+		if !tq.CompleteTask("task-1") {
+			t.Fatal("CompleteTask should return true for in-flight task")
+		}
+	}
+
+	if dlq.Len() != 0 {
+		t.Errorf("DLQ length = %d, want 0", dlq.Len())
+	}
+}
+
+func TestTaskQueue_LongPollReceivesLaterTask(t *testing.T) {
+	tq := NewTaskQueue("test-queue", TaskQueueKindNormal, 1000, 100, nil)
+
+	done := make(chan *Task, 1)
+	go func() {
+		task, err := tq.Poll(context.Background(), "worker-1")
+		if err != nil {
+			t.Errorf("Poll error = %v", err)
+		}
+		done <- task
+	}()
+
+	waitForPollers(t, tq, 1)
+	if err := tq.AddTask(&Task{ID: "task-1", ScheduledTime: time.Now()}); err != nil {
+		t.Fatalf("AddTask error = %v", err)
+	}
+
+	select {
+	case task := <-done:
+		if task == nil || task.ID != "task-1" {
+			t.Fatalf("Poll returned %+v, want task-1", task)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("long-poller was not handed the task")
+	}
+	if tq.PendingTaskCount() != 0 || tq.PollerCount() != 0 {
+		t.Errorf("pending = %d, pollers = %d; want 0, 0", tq.PendingTaskCount(), tq.PollerCount())
+	}
+}
+
+func TestTaskQueue_LongPollTimeout(t *testing.T) {
+	tq := NewTaskQueueWithConfig("test-queue", TaskQueueKindNormal, 1000, 100, nil, TaskQueueConfig{
+		LongPollTimeout: 50 * time.Millisecond,
+	})
+
+	start := time.Now()
+	task, err := tq.Poll(context.Background(), "worker-1")
+	if err != nil || task != nil {
+		t.Fatalf("Poll = %+v, %v; want nil, nil", task, err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Poll returned after %s, before the long-poll timeout", elapsed)
+	}
+	if tq.PollerCount() != 0 {
+		t.Errorf("PollerCount = %d after timeout, want 0", tq.PollerCount())
+	}
+
+	// A task added after the poller gave up stays queued.
+	if err := tq.AddTask(&Task{ID: "task-1", ScheduledTime: time.Now()}); err != nil {
+		t.Fatalf("AddTask error = %v", err)
+	}
+	if tq.PendingTaskCount() != 1 {
+		t.Errorf("PendingTaskCount = %d, want 1", tq.PendingTaskCount())
+	}
+}
+
+func TestTaskQueue_StickyHandoffSkipsOtherWorkers(t *testing.T) {
+	tq := NewTaskQueue("sticky:test", TaskQueueKindSticky, 1000, 100, nil)
+	tq.stickyAffinity.Bind("workflow-1", "worker-2")
+
+	results := make(map[string]chan *Task)
+	for _, identity := range []string{"worker-1", "worker-2"} {
+		ch := make(chan *Task, 1)
+		results[identity] = ch
+		go func(identity string) {
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			task, _ := tq.Poll(ctx, identity)
+			ch <- task
+		}(identity)
+		waitForPollers(t, tq, len(results))
+	}
+
+	if err := tq.AddTask(&Task{ID: "task-1", WorkflowID: "workflow-1", ScheduledTime: time.Now()}); err != nil {
+		t.Fatalf("AddTask error = %v", err)
+	}
+
+	if task := <-results["worker-2"]; task == nil || task.ID != "task-1" {
+		t.Fatalf("bound worker got %+v, want task-1", task)
+	}
+	if task := <-results["worker-1"]; task != nil {
+		t.Fatalf("other worker got %+v, want nothing", task)
+	}
+}
+
+func waitForPollers(t *testing.T, tq *TaskQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for tq.PollerCount() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d pollers", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// countingTaskStore counts PollTask calls to show how often an idle poller
+// touches the store.
+type countingTaskStore struct {
+	TaskStore
+	polls int
+}
+
+func (s *countingTaskStore) PollTask(ctx context.Context, timeout time.Duration) (*Task, error) {
+	s.polls++
+	return s.TaskStore.PollTask(ctx, timeout)
+}
+
+// BenchmarkTaskQueue_IdlePoll long-polls an empty queue. store_polls/op stays
+// at zero: the poller blocks until its deadline instead of re-reading the
+// store in a loop, so ns/op is the long-poll timeout with no CPU spent.
+func BenchmarkTaskQueue_IdlePoll(b *testing.B) {
+	tq := NewTaskQueueWithConfig("bench-queue", TaskQueueKindNormal, 1e9, 1e9, nil, TaskQueueConfig{
+		LongPollTimeout: 5 * time.Millisecond,
+	})
+	store := &countingTaskStore{TaskStore: tq.store}
+	tq.store = store
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := tq.Poll(context.Background(), "worker"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(store.polls)/float64(b.N), "store_polls/op")
+}
+
+func TestTaskQueue_OldestTaskAge(t *testing.T) {
+	tq := NewTaskQueue("test-queue", TaskQueueKindNormal, 1000, 100, nil)
+	if age := tq.OldestTaskAge(); age != 0 {
+		t.Fatalf("empty queue OldestTaskAge = %v, want 0", age)
+	}
+
+	now := time.Now()
+	tasks := []*Task{
+		{ID: "urgent", Priority: 0, ScheduledTime: now.Add(-time.Minute)},
+		{ID: "stale", Priority: 9, ScheduledTime: now.Add(-10 * time.Minute)},
+	}
+	for _, task := range tasks {
+		if err := tq.AddTask(task); err != nil {
+			t.Fatalf("AddTask error = %v", err)
+		}
+	}
+
+	// The low-priority task is dispatched last but has waited longest.
+	if age := tq.OldestTaskAge(); age < 10*time.Minute || age > 11*time.Minute {
+		t.Fatalf("OldestTaskAge = %v, want about 10m", age)
+	}
+
+	if _, err := tq.TryPoll(context.Background(), "worker-1"); err != nil {
+		t.Fatalf("TryPoll error = %v", err)
+	}
+	if age := tq.OldestTaskAge(); age < 10*time.Minute {
+		t.Fatalf("OldestTaskAge after polling the urgent task = %v, want about 10m", age)
+	}
+}
+
+func TestTaskQueue_PollSkipsTasksPastScheduleToStart(t *testing.T) {
+	dropped := make(chan *Task, 1)
+	tq := NewTaskQueueWithConfig("test-queue", TaskQueueKindNormal, 1000, 100, nil, TaskQueueConfig{
+		LongPollTimeout:          10 * time.Millisecond,
+		OnScheduleToStartTimeout: func(task *Task) { dropped <- task },
+	})
+
+	now := time.Now()
+	tasks := []*Task{
+		{ID: "stale", ScheduledTime: now, ScheduleToStartTimeout: 5 * time.Millisecond},
+		{ID: "fresh", ScheduledTime: now, ScheduleToStartTimeout: time.Minute},
+	}
+	for _, task := range tasks {
+		if err := tq.AddTask(task); err != nil {
+			t.Fatalf("AddTask error = %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	polled, err := tq.Poll(context.Background(), "worker-1")
+	if err != nil {
+		t.Fatalf("Poll error = %v", err)
+	}
+	if polled == nil || polled.ID != "fresh" {
+		t.Fatalf("Poll returned %+v, want the fresh task", polled)
+	}
+
+	select {
+	case task := <-dropped:
+		if task.ID != "stale" {
+			t.Fatalf("dropped task %q, want stale", task.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("schedule-to-start timeout was not reported")
+	}
+	if n := tq.Metrics().Snapshot().TasksScheduleToStartTimedOut; n != 1 {
+		t.Fatalf("TasksScheduleToStartTimedOut = %d, want 1", n)
+	}
+
+	if polled, err := tq.Poll(context.Background(), "worker-1"); err != nil || polled != nil {
+		t.Fatalf("second Poll = %+v, %v; want no task", polled, err)
+	}
+}
+
+func TestTaskQueue_DropExpiredTasks(t *testing.T) {
+	var mu sync.Mutex
+	var dropped []string
+	done := make(chan struct{}, 2)
+	tq := NewTaskQueueWithConfig("test-queue", TaskQueueKindNormal, 1000, 100, nil, TaskQueueConfig{
+		OnScheduleToStartTimeout: func(task *Task) {
+			mu.Lock()
+			dropped = append(dropped, task.ID)
+			mu.Unlock()
+			done <- struct{}{}
+		},
+	})
+
+	now := time.Now()
+	tasks := []*Task{
+		{ID: "stale-urgent", Priority: 0, ScheduledTime: now, ScheduleToStartTimeout: 5 * time.Millisecond},
+		{ID: "stale-low", Priority: 9, ScheduledTime: now, ScheduleToStartTimeout: 5 * time.Millisecond},
+		{ID: "fresh", ScheduledTime: now, ScheduleToStartTimeout: time.Minute},
+		{ID: "no-timeout", ScheduledTime: now},
+	}
+	for _, task := range tasks {
+		if err := tq.AddTask(task); err != nil {
+			t.Fatalf("AddTask error = %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if n := tq.DropExpiredTasks(); n != 2 {
+		t.Fatalf("DropExpiredTasks = %d, want 2", n)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("schedule-to-start timeout was not reported")
+		}
+	}
+	mu.Lock()
+	if len(dropped) != 2 {
+		t.Fatalf("reported %v, want both stale tasks", dropped)
+	}
+	mu.Unlock()
+
+	if tq.PendingTaskCount() != 2 {
+		t.Fatalf("PendingTaskCount = %d, want 2", tq.PendingTaskCount())
+	}
+	if n := tq.Metrics().Snapshot().TasksScheduleToStartTimedOut; n != 2 {
+		t.Fatalf("TasksScheduleToStartTimedOut = %d, want 2", n)
+	}
+	if n := tq.DropExpiredTasks(); n != 0 {
+		t.Fatalf("second DropExpiredTasks = %d, want 0", n)
+	}
+}
+
+func TestStickyAffinity_Renew(t *testing.T) {
+	sa := NewStickyAffinity()
+	sa.Bind("workflow-1", "worker-1")
+	backdate := func() {
+		rec := sa.affinityMap["workflow-1"]
+		rec.lastSeen = time.Now().Add(-time.Minute)
+		sa.affinityMap["workflow-1"] = rec
+		delete(sa.workerSeen, "worker-1")
+	}
+
+	backdate()
+	if !sa.IsExpired("workflow-1", time.Second) {
+		t.Fatal("backdated affinity not expired")
+	}
+	if sa.Renew("workflow-1", "worker-2") || sa.Renew("workflow-2", "worker-1") {
+		t.Fatal("renewed an affinity bound to another worker or to none")
+	}
+	if !sa.Renew("workflow-1", "worker-1") || sa.IsExpired("workflow-1", time.Second) {
+		t.Fatal("renewal by the bound worker did not extend the affinity")
+	}
+
+	// A poll by the bound worker renews all of its workflows.
+	backdate()
+	sa.RenewWorker("worker-2")
+	if !sa.IsExpired("workflow-1", time.Second) {
+		t.Fatal("another worker's poll renewed the affinity")
+	}
+	sa.RenewWorker("worker-1")
+	if sa.IsExpired("workflow-1", time.Second) {
+		t.Fatal("the bound worker's poll did not renew the affinity")
+	}
+}
+
+func TestTaskQueue_StickyHeartbeatRenewsLease(t *testing.T) {
+	tq := NewTaskQueue("sticky:test", TaskQueueKindSticky, 1000, 100, nil)
+	if err := tq.AddTask(&Task{ID: "task-1", WorkflowID: "workflow-1", ScheduledTime: time.Now()}); err != nil {
+		t.Fatalf("AddTask error = %v", err)
+	}
+	task, err := tq.TryPoll(context.Background(), "worker-1")
+	if err != nil || task == nil {
+		t.Fatalf("TryPoll = %+v, %v", task, err)
+	}
+
+	// The worker is slow: its binding and lease would both have expired.
+	rec := tq.stickyAffinity.affinityMap["workflow-1"]
+	rec.lastSeen = time.Now().Add(-2 * tq.leaseTimeout)
+	tq.stickyAffinity.affinityMap["workflow-1"] = rec
+	delete(tq.stickyAffinity.workerSeen, "worker-1")
+	tq.inFlightExpiry["task-1"] = time.Now().Add(-time.Second)
+	if tq.StickyWorker("workflow-1") != "" {
+		t.Fatal("backdated binding still held")
+	}
+
+	if !tq.HeartbeatTask("task-1", "worker-1") {
+		t.Fatal("heartbeat for an in-flight task reported not found")
+	}
+	if worker := tq.StickyWorker("workflow-1"); worker != "worker-1" {
+		t.Fatalf("StickyWorker = %q after heartbeat, want worker-1", worker)
+	}
+	if requeued := tq.RequeueExpiredTasks(); requeued != 0 {
+		t.Fatalf("requeued %d tasks with a renewed lease", requeued)
+	}
+	if tq.HeartbeatTask("task-2", "worker-1") {
+		t.Fatal("heartbeat for an unknown task succeeded")
+	}
+}
+
+func TestTaskQueue_StickyGracePeriodRebindsAndFallsBack(t *testing.T) {
+	var missed []*Task
+	tq := NewTaskQueueWithConfig("sticky:test", TaskQueueKindSticky, 1000, 100, nil, TaskQueueConfig{
+		StickyGracePeriod: time.Second,
+		OnStickyMiss:      func(task *Task) { missed = append(missed, task) },
+	})
+	tq.stickyAffinity.Bind("workflow-1", "worker-1")
+	tq.stickyAffinity.Bind("workflow-2", "worker-1")
+
+	// Within the grace period a task waits for its live bound worker.
+	if err := tq.AddTask(&Task{ID: "fresh", WorkflowID: "workflow-1", ScheduledTime: time.Now()}); err != nil {
+		t.Fatalf("AddTask error = %v", err)
+	}
+	if task, _ := tq.TryPoll(context.Background(), "worker-2"); task != nil {
+		t.Fatalf("other worker took %s within the grace period", task.ID)
+	}
+
+	// After it, another worker may take the task and the workflow is
+	// rebound to that worker.
+	if err := tq.AddTask(&Task{ID: "late", WorkflowID: "workflow-2", ScheduledTime: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("AddTask error = %v", err)
+	}
+	task, err := tq.TryPoll(context.Background(), "worker-2")
+	if err != nil || task == nil || task.ID != "late" {
+		t.Fatalf("TryPoll = %+v, %v; want the late task", task, err)
+	}
+	if worker := tq.StickyWorker("workflow-2"); worker != "worker-2" {
+		t.Fatalf("StickyWorker = %q, want rebound to worker-2", worker)
+	}
+
+	// A task nobody takes in time falls back off the queue.
+	if err := tq.AddTask(&Task{ID: "stranded", WorkflowID: "workflow-3", ScheduledTime: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("AddTask error = %v", err)
+	}
+	tq.stickyAffinity.Bind("workflow-3", "worker-1")
+	if n := tq.FallbackStickyTasks(); n != 1 || len(missed) != 1 || missed[0].ID != "stranded" {
+		t.Fatalf("FallbackStickyTasks = %d, missed %v; want the stranded task", n, missed)
+	}
+	if tq.StickyWorker("workflow-3") != "" || tq.PendingTaskCount() != 1 {
+		t.Fatalf("after fallback: worker %q, %d pending; want unbound and only the fresh task", tq.StickyWorker("workflow-3"), tq.PendingTaskCount())
+	}
+	if n := tq.Metrics().Snapshot().StickyMisses; n != 2 {
+		t.Fatalf("StickyMisses = %d, want 2", n)
+	}
+}
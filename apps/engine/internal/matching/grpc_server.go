@@ -4,10 +4,15 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
 	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
 	"github.com/linkflow/engine/internal/matching/engine"
@@ -81,17 +86,36 @@ func (s *GRPCServer) AddTask(ctx context.Context, req *matchingv1.AddTaskRequest
 		WorkflowID:       req.WorkflowExecution.GetWorkflowId(),
 		RunID:            req.WorkflowExecution.GetRunId(),
 		Namespace:        req.Namespace,
+		TaskQueue:        queueName,
 		ScheduledTime:    scheduledAt,
 		TaskType:         int32(req.TaskType),
 		ScheduledEventID: req.ScheduledEventId,
 		ActivityID:       fmt.Sprintf("%d", req.ScheduledEventId),
 	}
+	if req.TaskType == commonv1.TaskType_TASK_TYPE_ACTIVITY_TASK {
+		task.ScheduleToStartTimeout = s.service.scheduleToStartTimeout
+	}
 
-	if err = s.service.AddTask(ctx, queueName, task); err != nil {
+	state, err := s.service.AddTask(ctx, req.Namespace, queueName, task)
+	if err != nil {
+		if errors.Is(err, engine.ErrBackpressure) {
+			return nil, status.Errorf(codes.ResourceExhausted, "task queue %q in namespace %q: %v", queueName, req.Namespace, err)
+		}
 		return nil, err
 	}
 
-	return &matchingv1.AddTaskResponse{}, nil
+	return &matchingv1.AddTaskResponse{BackpressureState: toProtoBackpressureState(state)}, nil
+}
+
+func toProtoBackpressureState(state engine.BackpressureState) matchingv1.BackpressureState {
+	switch state {
+	case engine.BackpressureWarning:
+		return matchingv1.BackpressureState_BACKPRESSURE_STATE_WARNING
+	case engine.BackpressureCritical:
+		return matchingv1.BackpressureState_BACKPRESSURE_STATE_CRITICAL
+	default:
+		return matchingv1.BackpressureState_BACKPRESSURE_STATE_NORMAL
+	}
 }
 
 func (s *GRPCServer) PollTask(ctx context.Context, req *matchingv1.PollTaskRequest) (*matchingv1.PollTaskResponse, error) {
@@ -100,10 +124,8 @@ func (s *GRPCServer) PollTask(ctx context.Context, req *matchingv1.PollTaskReque
 		queueName = "default"
 	}
 
-	// Auto-create task queue if it doesn't exist (workers poll before tasks arrive)
-	s.service.GetOrCreateTaskQueue(queueName, engine.TaskQueueKindNormal)
-
-	task, err := s.service.PollTask(ctx, queueName, req.Identity)
+	// PollTask creates the queue if it doesn't exist (workers poll before tasks arrive)
+	task, err := s.service.PollTask(ctx, req.Namespace, queueName, req.Identity)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +165,7 @@ func (s *GRPCServer) PollTask(ctx context.Context, req *matchingv1.PollTaskReque
 }
 
 func (s *GRPCServer) CompleteTask(ctx context.Context, req *matchingv1.CompleteTaskRequest) (*matchingv1.CompleteTaskResponse, error) {
-	_, queueName, taskID, err := parseTaskToken(req.GetTaskToken())
+	namespace, queueName, taskID, err := parseTaskToken(req.GetTaskToken())
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +173,7 @@ func (s *GRPCServer) CompleteTask(ctx context.Context, req *matchingv1.CompleteT
 		return nil, fmt.Errorf("invalid task token")
 	}
 
-	if err := s.service.CompleteTask(ctx, queueName, taskID); err != nil && err != ErrTaskNotFound {
+	if err := s.service.CompleteTask(ctx, namespace, queueName, taskID); err != nil && err != ErrTaskNotFound {
 		return nil, err
 	}
 
@@ -163,10 +185,78 @@ func (s *GRPCServer) QueryWorkflow(ctx context.Context, req *matchingv1.Matching
 	return &matchingv1.MatchingServiceQueryWorkflowResponse{}, nil
 }
 
+// HeartbeatTask extends the lease of the task and keeps its workflow bound to
+// the heartbeating worker. A heartbeat for a task that is no longer in
+// flight is ignored.
 func (s *GRPCServer) HeartbeatTask(ctx context.Context, req *matchingv1.HeartbeatTaskRequest) (*matchingv1.HeartbeatTaskResponse, error) {
+	namespace, queueName, taskID, err := parseTaskToken(req.GetTaskToken())
+	if err != nil {
+		return nil, err
+	}
+	if queueName == "" || taskID == "" {
+		return nil, fmt.Errorf("invalid task token")
+	}
+
+	err = s.service.HeartbeatTask(ctx, namespace, queueName, taskID, req.GetIdentity())
+	if err != nil && err != ErrTaskNotFound && err != ErrTaskQueueNotFound {
+		return nil, err
+	}
 	return &matchingv1.HeartbeatTaskResponse{CancelRequested: false}, nil
 }
 
+func (s *GRPCServer) ListDLQTasks(ctx context.Context, req *matchingv1.ListDLQTasksRequest) (*matchingv1.ListDLQTasksResponse, error) {
+	entries := s.service.GetDLQEntries()
+	resp := &matchingv1.ListDLQTasksResponse{
+		Tasks: make([]*matchingv1.DLQTask, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		resp.Tasks = append(resp.Tasks, &matchingv1.DLQTask{
+			TaskId:    entry.Task.ID,
+			Namespace: entry.Task.Namespace,
+			WorkflowExecution: &commonv1.WorkflowExecution{
+				WorkflowId: entry.Task.WorkflowID,
+				RunId:      entry.Task.RunID,
+			},
+			TaskType:         commonv1.TaskType(entry.Task.TaskType),
+			ScheduledEventId: entry.Task.ScheduledEventID,
+			Reason:           entry.Reason,
+			Attempts:         entry.Attempts,
+			LastError:        entry.LastError,
+			FailedTime:       timestamppb.New(entry.FailedAt),
+		})
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) ReplayDLQTasks(ctx context.Context, req *matchingv1.ReplayDLQTasksRequest) (*matchingv1.ReplayDLQTasksResponse, error) {
+	taskIDs := req.GetTaskIds()
+	if req.GetAll() {
+		taskIDs = taskIDs[:0:0]
+		for _, entry := range s.service.GetDLQEntries() {
+			taskIDs = append(taskIDs, entry.Task.ID)
+		}
+	} else if len(taskIDs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "task_ids or all is required")
+	}
+
+	resp := &matchingv1.ReplayDLQTasksResponse{
+		ReplayedTaskIds: make([]string, 0, len(taskIDs)),
+		Failures:        make(map[string]string),
+	}
+	for _, taskID := range taskIDs {
+		if err := s.service.RetryDLQTask(ctx, taskID); err != nil {
+			resp.Failures[taskID] = err.Error()
+			continue
+		}
+		resp.ReplayedTaskIds = append(resp.ReplayedTaskIds, taskID)
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) PurgeDLQ(ctx context.Context, req *matchingv1.PurgeDLQRequest) (*matchingv1.PurgeDLQResponse, error) {
+	return &matchingv1.PurgeDLQResponse{Purged: int32(s.service.PurgeDLQ())}, nil
+}
+
 func parseTaskToken(token []byte) (namespace string, queueName string, taskID string, err error) {
 	parts := strings.SplitN(string(token), "|", 4)
 	if len(parts) < 4 {
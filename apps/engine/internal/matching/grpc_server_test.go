@@ -1,7 +1,14 @@
 package matching
 
 import (
+	"context"
 	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
 )
 
 func TestGenerateTaskID(t *testing.T) {
@@ -89,3 +96,44 @@ func TestGenerateSecureToken(t *testing.T) {
 		t.Error("generateSecureToken() should produce unique tokens")
 	}
 }
+
+func TestAddTaskBackpressure(t *testing.T) {
+	svc := NewService(Config{
+		BackpressureSoftLimit: 2,
+		BackpressureHardLimit: 3,
+	})
+	server := NewGRPCServer(svc)
+
+	addTask := func(eventID int64) (*matchingv1.AddTaskResponse, error) {
+		return server.AddTask(context.Background(), &matchingv1.AddTaskRequest{
+			Namespace: "default",
+			TaskQueue: &matchingv1.TaskQueue{Name: "bp-queue"},
+			TaskType:  commonv1.TaskType_TASK_TYPE_ACTIVITY_TASK,
+			WorkflowExecution: &commonv1.WorkflowExecution{
+				WorkflowId: "workflow-1",
+				RunId:      "run-1",
+			},
+			ScheduledEventId: eventID,
+		})
+	}
+
+	want := []matchingv1.BackpressureState{
+		matchingv1.BackpressureState_BACKPRESSURE_STATE_NORMAL,
+		matchingv1.BackpressureState_BACKPRESSURE_STATE_NORMAL,
+		matchingv1.BackpressureState_BACKPRESSURE_STATE_WARNING,
+	}
+	for i, state := range want {
+		resp, err := addTask(int64(i + 1))
+		if err != nil {
+			t.Fatalf("AddTask %d error = %v", i+1, err)
+		}
+		if resp.GetBackpressureState() != state {
+			t.Errorf("AddTask %d state = %v, want %v", i+1, resp.GetBackpressureState(), state)
+		}
+	}
+
+	// The queue is now at the hard limit, so the next task is rejected.
+	if _, err := addTask(4); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("AddTask at hard limit error = %v, want code %v", err, codes.ResourceExhausted)
+	}
+}
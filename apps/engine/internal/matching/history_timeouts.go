@@ -0,0 +1,57 @@
+package matching
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/matching/engine"
+)
+
+// ActivityTaskFailer is the part of the history client HistoryTimeoutReporter
+// uses.
+type ActivityTaskFailer interface {
+	RespondActivityTaskFailed(ctx context.Context, in *historyv1.RespondActivityTaskFailedRequest, opts ...grpc.CallOption) (*historyv1.RespondActivityTaskFailedResponse, error)
+}
+
+// HistoryTimeoutReporter fails activities whose task timed out before a
+// worker started it, so the workflow's retry policy or failure handling
+// takes over instead of the node waiting forever.
+type HistoryTimeoutReporter struct {
+	history ActivityTaskFailer
+}
+
+// NewHistoryTimeoutReporter returns a TaskTimeoutReporter that reports
+// through the history service.
+func NewHistoryTimeoutReporter(history ActivityTaskFailer) *HistoryTimeoutReporter {
+	return &HistoryTimeoutReporter{history: history}
+}
+
+// ReportScheduleToStartTimeout records a TIMEOUT failure for the activity
+// task. The request ID is derived from the task so a repeated report is
+// deduplicated by history.
+func (r *HistoryTimeoutReporter) ReportScheduleToStartTimeout(ctx context.Context, task *engine.Task) error {
+	if task.TaskType != int32(commonv1.TaskType_TASK_TYPE_ACTIVITY_TASK) {
+		return nil
+	}
+
+	_, err := r.history.RespondActivityTaskFailed(ctx, &historyv1.RespondActivityTaskFailedRequest{
+		Namespace: task.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: task.WorkflowID,
+			RunId:      task.RunID,
+		},
+		ScheduledEventId: task.ScheduledEventID,
+		Failure: &commonv1.Failure{
+			Message:     fmt.Sprintf("activity task was not started within its schedule-to-start timeout of %s", task.ScheduleToStartTimeout),
+			Source:      "matching",
+			FailureType: commonv1.FailureType_FAILURE_TYPE_TIMEOUT,
+		},
+		Identity:  "matching",
+		RequestId: "schedule-to-start-timeout/" + task.ID,
+	})
+	return err
+}
@@ -21,7 +21,14 @@ func NewRing(replicas int) *Ring {
 }
 
 func (r *Ring) Add(partitionID int32) {
-	for i := 0; i < r.replicas; i++ {
+	r.AddWeighted(partitionID, 1)
+}
+
+// AddWeighted adds a partition with weight times as many virtual nodes as
+// Add, so it owns proportionally more of the ring. A weight of 1 places the
+// partition exactly where Add does.
+func (r *Ring) AddWeighted(partitionID int32, weight int) {
+	for i := 0; i < r.replicas*weight; i++ {
 		key := strconv.Itoa(int(partitionID)) + "-" + strconv.Itoa(i)
 		h := r.hash(key)
 
@@ -1,6 +1,8 @@
 package partition
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -9,8 +11,14 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrInvalidWeights is returned by SetWeights for weights that are negative,
+// name unknown partitions, or leave no partition to route to.
+var ErrInvalidWeights = errors.New("invalid partition weights")
+
 type Manager struct {
 	numPartitions int32
+	replicas      int
+	weights       map[int32]int
 	partitions    map[int32]*Partition
 	hashRing      *Ring
 	redisClient   *redis.Client
@@ -18,8 +26,9 @@ type Manager struct {
 }
 
 type Partition struct {
-	ID          int32
-	TaskQueues  map[string]*engine.TaskQueue
+	ID         int32
+	TaskQueues map[string]*engine.TaskQueue
+	// Load counts the tasks added to the partition's queues.
 	Load        atomic.Int64
 	LastActive  time.Time
 	redisClient *redis.Client
@@ -29,6 +38,7 @@ type Partition struct {
 func NewManager(numPartitions int32, replicas int, redisClient *redis.Client) *Manager {
 	m := &Manager{
 		numPartitions: numPartitions,
+		replicas:      replicas,
 		partitions:    make(map[int32]*Partition),
 		hashRing:      NewRing(replicas),
 		redisClient:   redisClient,
@@ -65,6 +75,61 @@ func (m *Manager) NumPartitions() int32 {
 	return m.numPartitions
 }
 
+// SetWeights rebuilds the hash ring so each partition owns a share of it
+// proportional to its weight. Partitions without a weight get 1, and a weight
+// of 0 stops new task queues from being placed on a partition. Task queues
+// already placed stay where they are.
+func (m *Manager) SetWeights(weights map[int32]int) error {
+	total := 0
+	for id := int32(0); id < m.numPartitions; id++ {
+		weight, ok := weights[id]
+		if !ok {
+			weight = 1
+		}
+		if weight < 0 {
+			return fmt.Errorf("%w: partition %d has weight %d", ErrInvalidWeights, id, weight)
+		}
+		total += weight
+	}
+	for id := range weights {
+		if id < 0 || id >= m.numPartitions {
+			return fmt.Errorf("%w: unknown partition %d", ErrInvalidWeights, id)
+		}
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: every partition has weight 0", ErrInvalidWeights)
+	}
+
+	ring := NewRing(m.replicas)
+	applied := make(map[int32]int, len(weights))
+	for id := int32(0); id < m.numPartitions; id++ {
+		weight, ok := weights[id]
+		if !ok {
+			weight = 1
+		}
+		if weight > 0 {
+			ring.AddWeighted(id, weight)
+		}
+		applied[id] = weight
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hashRing = ring
+	m.weights = applied
+	return nil
+}
+
+// Weight returns the partition's routing weight.
+func (m *Manager) Weight(partitionID int32) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if weight, ok := m.weights[partitionID]; ok {
+		return weight
+	}
+	return 1
+}
+
 func (p *Partition) GetOrCreateTaskQueue(name string, kind engine.TaskQueueKind, rateLimit float64, burst int) *engine.TaskQueue {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -99,3 +164,10 @@ func (p *Partition) GetTaskQueue(name string) *engine.TaskQueue {
 	defer p.mu.RUnlock()
 	return p.TaskQueues[name]
 }
+
+// TaskQueueCount returns the number of task queues placed on the partition.
+func (p *Partition) TaskQueueCount() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.TaskQueues)
+}
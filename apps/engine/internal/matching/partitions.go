@@ -0,0 +1,151 @@
+package matching
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+	"github.com/linkflow/engine/internal/matching/engine"
+)
+
+// TaskQueuePartitions describes where a task queue's tasks, and those of one
+// workflow in particular, are routed.
+type TaskQueuePartitions struct {
+	Namespace  string `json:"namespace"`
+	TaskQueue  string `json:"task_queue"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+	// Partition is the partition the queue's tasks are routed to. A queue
+	// lives on a single partition, so the workflow's tasks go there too.
+	Partition int32 `json:"partition"`
+	// RingPartition is the partition the hash ring selects for the queue
+	// now. It differs from Partition when weights changed after the queue
+	// was placed, and is where the queue goes if it is recreated.
+	RingPartition int32 `json:"ring_partition"`
+	// Placed reports whether the queue exists on this host. When it does
+	// not, Partition is the ring's selection.
+	Placed bool `json:"placed"`
+	// StickyQueues lists the sticky queues whose worker holds an unexpired
+	// binding for the workflow.
+	StickyQueues []StickyQueueBinding `json:"sticky_queues,omitempty"`
+	// Partitions holds the queue's stats on every partition, ordered by ID.
+	Partitions []TaskQueuePartitionInfo `json:"partitions"`
+}
+
+// TaskQueuePartitionInfo is a task queue's share of one partition.
+type TaskQueuePartitionInfo struct {
+	ID     int32 `json:"id"`
+	Weight int   `json:"weight"`
+	// Owner reports whether the partition holds the queue; the depth and
+	// poller count are zero on other partitions.
+	Owner        bool  `json:"owner"`
+	PendingTasks int   `json:"pending_tasks"`
+	Pollers      int   `json:"pollers"`
+	TaskQueues   int   `json:"task_queues"`
+	TasksAdded   int64 `json:"tasks_added"`
+}
+
+// StickyQueueBinding is a sticky queue a workflow's tasks are pinned to.
+type StickyQueueBinding struct {
+	TaskQueue string `json:"task_queue"`
+	Worker    string `json:"worker"`
+	Partition int32  `json:"partition"`
+}
+
+// DescribeTaskQueuePartitions reports the partition the namespace's queue,
+// and so workflowID's tasks on it, routes to, with the queue's depth and
+// poller count on each partition. It is meant for debugging uneven load and
+// sticky affinity; the queue is not created when it does not exist.
+func (s *Service) DescribeTaskQueuePartitions(namespace, queueName, workflowID string) *TaskQueuePartitions {
+	key := taskQueueKey{namespace: namespace, name: queueName}
+	ringPartition := s.partitionMgr.GetPartitionForTaskQueue(key.storeName()).ID
+
+	s.mu.RLock()
+	tq := s.taskQueues[key]
+	placed := s.queuePartitions[key]
+	var sticky []StickyQueueBinding
+	if workflowID != "" {
+		for stickyKey, stickyQueue := range s.taskQueues {
+			if stickyQueue.Kind() != engine.TaskQueueKindSticky {
+				continue
+			}
+			if worker := stickyQueue.StickyWorker(workflowID); worker != "" {
+				sticky = append(sticky, StickyQueueBinding{
+					TaskQueue: stickyKey.name,
+					Worker:    worker,
+					Partition: s.queuePartitions[stickyKey].ID,
+				})
+			}
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(sticky, func(i, j int) bool { return sticky[i].TaskQueue < sticky[j].TaskQueue })
+
+	desc := &TaskQueuePartitions{
+		Namespace:     namespace,
+		TaskQueue:     queueName,
+		WorkflowID:    workflowID,
+		Partition:     ringPartition,
+		RingPartition: ringPartition,
+		Placed:        tq != nil,
+		StickyQueues:  sticky,
+	}
+	if placed != nil {
+		desc.Partition = placed.ID
+	}
+
+	for _, p := range s.Partitions() {
+		info := TaskQueuePartitionInfo{
+			ID:         p.ID,
+			Weight:     p.Weight,
+			Owner:      tq != nil && p.ID == desc.Partition,
+			TaskQueues: p.TaskQueues,
+			TasksAdded: p.TasksAdded,
+		}
+		if info.Owner {
+			info.PendingTasks = tq.PendingTaskCount()
+			info.Pollers = tq.PollerCount()
+		}
+		desc.Partitions = append(desc.Partitions, info)
+	}
+	return desc
+}
+
+// DescribeTaskQueuePartitions describes the routing of the request's task
+// queue and workflow.
+func (s *GRPCServer) DescribeTaskQueuePartitions(ctx context.Context, req *matchingv1.DescribeTaskQueuePartitionsRequest) (*matchingv1.DescribeTaskQueuePartitionsResponse, error) {
+	if req.GetTaskQueue() == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_queue is required")
+	}
+
+	desc := s.service.DescribeTaskQueuePartitions(req.GetNamespace(), req.GetTaskQueue(), req.GetWorkflowId())
+	resp := &matchingv1.DescribeTaskQueuePartitionsResponse{
+		Namespace:     desc.Namespace,
+		TaskQueue:     desc.TaskQueue,
+		WorkflowId:    desc.WorkflowID,
+		Partition:     desc.Partition,
+		RingPartition: desc.RingPartition,
+		Placed:        desc.Placed,
+	}
+	for _, binding := range desc.StickyQueues {
+		resp.StickyQueues = append(resp.StickyQueues, &matchingv1.StickyQueueBinding{
+			TaskQueue: binding.TaskQueue,
+			Worker:    binding.Worker,
+			Partition: binding.Partition,
+		})
+	}
+	for _, p := range desc.Partitions {
+		resp.Partitions = append(resp.Partitions, &matchingv1.TaskQueuePartitionInfo{
+			Id:           p.ID,
+			Weight:       int32(p.Weight),
+			Owner:        p.Owner,
+			PendingTasks: int32(p.PendingTasks),
+			Pollers:      int32(p.Pollers),
+			TaskQueues:   int32(p.TaskQueues),
+			TasksAdded:   p.TasksAdded,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,93 @@
+package matching
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	matchingv1 "github.com/linkflow/engine/api/gen/linkflow/matching/v1"
+	"github.com/linkflow/engine/internal/matching/engine"
+)
+
+func TestDescribeTaskQueuePartitions(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(Config{
+		NumPartitions:   4,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		LongPollTimeout: 50 * time.Millisecond,
+	})
+
+	for _, id := range []string{"t1", "t2"} {
+		if _, err := svc.AddTask(ctx, "default", "orders", &engine.Task{ID: id, Namespace: "default", TaskQueue: "orders", WorkflowID: "wf-1", ScheduledTime: time.Now()}); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+	}
+	// worker-a takes a task of wf-1 from its sticky queue, binding wf-1 to it.
+	sticky := svc.GetOrCreateStickyQueue("worker-a")
+	if err := sticky.AddTask(&engine.Task{ID: "s1", TaskQueue: sticky.Name(), WorkflowID: "wf-1", ScheduledTime: time.Now()}); err != nil {
+		t.Fatalf("add sticky task: %v", err)
+	}
+	if task, err := sticky.Poll(ctx, "worker-a"); err != nil || task == nil {
+		t.Fatalf("sticky poll = %+v, %v", task, err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	matchingv1.RegisterMatchingServiceServer(server, NewGRPCServer(svc))
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := matchingv1.NewMatchingServiceClient(conn)
+
+	desc, err := client.DescribeTaskQueuePartitions(ctx, &matchingv1.DescribeTaskQueuePartitionsRequest{Namespace: "default", TaskQueue: "orders", WorkflowId: "wf-1"})
+	if err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	if !desc.GetPlaced() || desc.GetPartition() != desc.GetRingPartition() || len(desc.GetPartitions()) != 4 {
+		t.Fatalf("describe = %+v", desc)
+	}
+	for _, p := range desc.GetPartitions() {
+		if p.GetOwner() != (p.GetId() == desc.GetPartition()) {
+			t.Fatalf("partition %d owner = %v, want only partition %d", p.GetId(), p.GetOwner(), desc.GetPartition())
+		}
+		if p.GetOwner() && p.GetPendingTasks() != 2 {
+			t.Fatalf("owning partition = %+v, want 2 pending tasks", p)
+		}
+	}
+	if sticky := desc.GetStickyQueues(); len(sticky) != 1 || sticky[0].GetWorker() != "worker-a" || sticky[0].GetTaskQueue() != "sticky:worker-a" {
+		t.Fatalf("sticky queues = %+v, want wf-1 bound to worker-a", sticky)
+	}
+
+	// A queue that does not exist yet reports where it would be placed,
+	// without being created.
+	desc, err = client.DescribeTaskQueuePartitions(ctx, &matchingv1.DescribeTaskQueuePartitionsRequest{Namespace: "default", TaskQueue: "refunds"})
+	if err != nil {
+		t.Fatalf("describe missing queue: %v", err)
+	}
+	if desc.GetPlaced() || len(desc.GetStickyQueues()) != 0 {
+		t.Fatalf("describe missing queue = %+v", desc)
+	}
+	if _, err := svc.GetTaskQueue("default", "refunds"); err != ErrTaskQueueNotFound {
+		t.Fatalf("describe created the queue: %v", err)
+	}
+
+	if _, err := client.DescribeTaskQueuePartitions(ctx, &matchingv1.DescribeTaskQueuePartitionsRequest{Namespace: "default"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without a task queue, got %v", err)
+	}
+}
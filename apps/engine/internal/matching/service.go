@@ -2,27 +2,125 @@ package matching
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/linkflow/engine/internal/controlplane"
 	"github.com/linkflow/engine/internal/matching/engine"
 	"github.com/linkflow/engine/internal/matching/partition"
+	"github.com/linkflow/engine/internal/observability/metrics"
 	"github.com/redis/go-redis/v9"
 )
 
 const (
 	defaultRateLimit = 1000.0
 	defaultBurst     = 100
+
+	// anyNamespacePollInterval is how often a poll that is not bound to a
+	// namespace looks for tasks again while all matching queues are empty.
+	anyNamespacePollInterval = 100 * time.Millisecond
+
+	// defaultQueueName is the queue of tasks recorded without one.
+	defaultQueueName = "default"
+
+	// stickyQueuePrefix starts the name of each worker's sticky queue.
+	stickyQueuePrefix = "sticky:"
+
+	defaultPartitionWeightRefreshInterval = 30 * time.Second
 )
 
+// NamespaceConfigProvider resolves namespace configuration, e.g. the control plane.
+type NamespaceConfigProvider interface {
+	GetNamespace(ctx context.Context, name string) (*controlplane.NamespaceConfig, error)
+}
+
+// DynamicConfigProvider resolves dynamic configuration by key, e.g. the
+// control plane.
+type DynamicConfigProvider interface {
+	GetConfig(ctx context.Context, key string) (json.RawMessage, error)
+}
+
+// TaskTimeoutReporter records on a workflow that one of its tasks was
+// dropped because no worker started it within its schedule-to-start timeout,
+// e.g. by failing the activity in history.
+type TaskTimeoutReporter interface {
+	ReportScheduleToStartTimeout(ctx context.Context, task *engine.Task) error
+}
+
+// reportTimeoutDeadline bounds each call to the TaskTimeoutReporter.
+const reportTimeoutDeadline = 10 * time.Second
+
+// PartitionInfo describes one of the service's task queue partitions.
+type PartitionInfo struct {
+	ID         int32
+	Weight     int
+	TaskQueues int
+	TasksAdded int64
+}
+
+// taskQueueKey identifies a task queue within a namespace. The empty
+// namespace holds the un-namespaced queues created before queues were keyed
+// by namespace, so tasks persisted under the old names are still served.
+type taskQueueKey struct {
+	namespace string
+	name      string
+}
+
+// storeName is the name the queue is partitioned and persisted under.
+func (k taskQueueKey) storeName() string {
+	if k.namespace == "" {
+		return k.name
+	}
+	return k.namespace + "/" + k.name
+}
+
+// TaskQueueInfo describes one of a namespace's task queues.
+type TaskQueueInfo struct {
+	Name              string
+	Kind              engine.TaskQueueKind
+	PendingTasks      int
+	Pollers           int
+	BackpressureState engine.BackpressureState
+	OldestTaskAge     time.Duration
+}
+
 type Service struct {
 	partitionMgr *partition.Manager
-	taskQueues   map[string]*engine.TaskQueue
+	taskQueues   map[taskQueueKey]*engine.TaskQueue
 	logger       *slog.Logger
+	metrics      *metrics.ServiceMetrics
 	mu           sync.RWMutex
 
+	// queuePartitions records the partition each task queue was placed on,
+	// which stays put when partition weights change.
+	queuePartitions map[taskQueueKey]*partition.Partition
+
+	// dynamicConfig supplies partition weights, re-read every
+	// weightRefreshInterval; nil keeps the configured weights.
+	dynamicConfig         DynamicConfigProvider
+	weightRefreshInterval time.Duration
+	weightsMu             sync.Mutex
+	appliedWeights        map[int32]int
+
+	// namespaces supplies per-namespace backpressure limits; nil uses the
+	// service limits for every namespace.
+	namespaces NamespaceConfigProvider
+
+	// redisClient records which namespaces have a queue of a given name so
+	// polls that are not bound to a namespace find queues created on other
+	// hosts.
+	redisClient *redis.Client
+
+	// nextPollQueue rotates the queue polls that are not bound to a
+	// namespace start from.
+	nextPollQueue atomic.Uint64
+
 	stopCh  chan struct{}
 	wg      sync.WaitGroup
 	running bool
@@ -33,6 +131,19 @@ type Service struct {
 	// WAL for crash recovery
 	wal    *engine.WAL
 	walDir string
+
+	softLimit int
+	hardLimit int
+
+	poisonPillThreshold int32
+	poisonPillWindow    time.Duration
+
+	longPollTimeout time.Duration
+
+	scheduleToStartTimeout time.Duration
+	taskTimeouts           TaskTimeoutReporter
+
+	stickyGracePeriod time.Duration
 }
 
 type Config struct {
@@ -41,6 +152,49 @@ type Config struct {
 	Logger        *slog.Logger
 	RedisClient   *redis.Client
 	WALDir        string
+
+	// Per-queue backpressure limits. Zero uses the engine defaults.
+	BackpressureSoftLimit int
+	BackpressureHardLimit int
+
+	// Tasks that expire unacked more than PoisonPillThreshold times within
+	// PoisonPillWindow are sent to the DLQ. Zero uses the engine defaults.
+	PoisonPillThreshold int32
+	PoisonPillWindow    time.Duration
+
+	// LongPollTimeout is how long a poll waits for a task before returning
+	// an empty response. Zero uses the engine default.
+	LongPollTimeout time.Duration
+
+	// ScheduleToStartTimeout is how long an activity task may wait for a
+	// worker before it is dropped and reported to TaskTimeouts. Zero lets
+	// tasks wait indefinitely. Workflow tasks never time out: history has no
+	// way to fail one, so dropping it would stall the run.
+	ScheduleToStartTimeout time.Duration
+	TaskTimeouts           TaskTimeoutReporter
+
+	// StickyGracePeriod is how long a task on a sticky queue waits for its
+	// bound worker before it falls back to the workflow's normal queue and
+	// counts as a sticky miss. Zero uses the engine default.
+	StickyGracePeriod time.Duration
+
+	// Namespaces overrides the backpressure limits of a namespace's queues
+	// with its TaskQueueSoftLimit and TaskQueueHardLimit. Limits are read
+	// when a queue is created.
+	Namespaces NamespaceConfigProvider
+
+	// PartitionWeights routes proportionally more new task queues, and so
+	// more tasks, to heavier partitions. Partitions without a weight get 1.
+	PartitionWeights map[int32]int
+
+	// DynamicConfig, when set, is polled every PartitionWeightRefreshInterval
+	// (default 30s) for the controlplane.PartitionWeightsConfigKey weights.
+	DynamicConfig                  DynamicConfigProvider
+	PartitionWeightRefreshInterval time.Duration
+
+	// Metrics receives per-partition task counts (default
+	// metrics.DefaultRegistry).
+	Metrics *metrics.Registry
 }
 
 func NewService(cfg Config) *Service {
@@ -53,44 +207,92 @@ func NewService(cfg Config) *Service {
 	if cfg.Logger == nil {
 		cfg.Logger = slog.Default()
 	}
+	if cfg.BackpressureSoftLimit <= 0 {
+		cfg.BackpressureSoftLimit = engine.DefaultSoftLimit
+	}
+	if cfg.BackpressureHardLimit <= 0 {
+		cfg.BackpressureHardLimit = engine.DefaultHardLimit
+	}
+	if cfg.PartitionWeightRefreshInterval <= 0 {
+		cfg.PartitionWeightRefreshInterval = defaultPartitionWeightRefreshInterval
+	}
+
+	s := &Service{
+		partitionMgr:    partition.NewManager(cfg.NumPartitions, cfg.Replicas, cfg.RedisClient),
+		taskQueues:      make(map[taskQueueKey]*engine.TaskQueue),
+		queuePartitions: make(map[taskQueueKey]*partition.Partition),
+		logger:          cfg.Logger,
+		metrics:         metrics.NewServiceMetrics(cfg.Metrics, "matching"),
+		namespaces:      cfg.Namespaces,
+		redisClient:     cfg.RedisClient,
+		dlq:             engine.NewDeadLetterQueue(10000, cfg.Logger),
+		walDir:          cfg.WALDir,
+		softLimit:       cfg.BackpressureSoftLimit,
+		hardLimit:       cfg.BackpressureHardLimit,
+
+		poisonPillThreshold: cfg.PoisonPillThreshold,
+		poisonPillWindow:    cfg.PoisonPillWindow,
+
+		longPollTimeout: cfg.LongPollTimeout,
+
+		scheduleToStartTimeout: cfg.ScheduleToStartTimeout,
+		taskTimeouts:           cfg.TaskTimeouts,
+
+		stickyGracePeriod: cfg.StickyGracePeriod,
+
+		dynamicConfig:         cfg.DynamicConfig,
+		weightRefreshInterval: cfg.PartitionWeightRefreshInterval,
+	}
 
-	return &Service{
-		partitionMgr: partition.NewManager(cfg.NumPartitions, cfg.Replicas, cfg.RedisClient),
-		taskQueues:   make(map[string]*engine.TaskQueue),
-		logger:       cfg.Logger,
-		dlq:          engine.NewDeadLetterQueue(10000, cfg.Logger),
-		walDir:       cfg.WALDir,
+	if err := s.SetPartitionWeights(cfg.PartitionWeights); err != nil {
+		s.logger.Warn("ignoring partition weights", slog.String("error", err.Error()))
 	}
+	return s
 }
 
-func (s *Service) AddTask(ctx context.Context, taskQueueName string, task *engine.Task) error {
-	tq := s.GetOrCreateTaskQueue(taskQueueName, engine.TaskQueueKindNormal)
+// AddTask enqueues a task on the namespace's queue and returns the queue's
+// backpressure state so callers can tell producers to slow down before the
+// hard limit is reached. Each namespace's queues have their own limits, so a
+// backlog in one namespace does not hold back another's.
+func (s *Service) AddTask(ctx context.Context, namespace, taskQueueName string, task *engine.Task) (engine.BackpressureState, error) {
+	tq := s.GetOrCreateTaskQueue(namespace, taskQueueName, engine.TaskQueueKindNormal)
 	if err := tq.AddTask(task); err != nil {
 		if errors.Is(err, engine.ErrTaskExists) {
 			s.logger.Warn("task already exists",
 				slog.String("task_id", task.ID),
+				slog.String("namespace", namespace),
 				slog.String("task_queue", taskQueueName),
 			)
-			return nil
+			return tq.BackpressureState(), nil
 		}
 
 		if errors.Is(err, engine.ErrBackpressure) {
 			s.logger.Warn("task rejected by backpressure",
 				slog.String("task_id", task.ID),
+				slog.String("namespace", namespace),
 				slog.String("task_queue", taskQueueName),
 			)
-			return err
+			return engine.BackpressureCritical, err
 		}
 
 		s.logger.Error("failed to add task",
 			slog.String("task_id", task.ID),
+			slog.String("namespace", namespace),
 			slog.String("task_queue", taskQueueName),
 			slog.String("error", err.Error()),
 		)
-		return err
+		return tq.BackpressureState(), err
 	}
 
-	return nil
+	s.mu.RLock()
+	p := s.queuePartitions[taskQueueKey{namespace: namespace, name: taskQueueName}]
+	s.mu.RUnlock()
+	if p != nil {
+		p.Load.Add(1)
+		s.metrics.PartitionTaskAdded(p.ID)
+	}
+
+	return tq.BackpressureState(), nil
 }
 
 func (s *Service) CompleteTaskByID(ctx context.Context, taskID string) error {
@@ -110,96 +312,366 @@ func (s *Service) CompleteTaskByID(ctx context.Context, taskID string) error {
 	return ErrTaskNotFound
 }
 
-func (s *Service) CompleteTask(ctx context.Context, taskQueueName string, taskID string) error {
-	s.mu.RLock()
-	tq, exists := s.taskQueues[taskQueueName]
-	s.mu.RUnlock()
+// CompleteTask acknowledges a task of the namespace's queue. Tokens issued
+// before queues were keyed by namespace name an un-namespaced queue, which is
+// tried when the namespaced queue does not know the task.
+func (s *Service) CompleteTask(ctx context.Context, namespace, taskQueueName string, taskID string) error {
+	keys := []taskQueueKey{{namespace: namespace, name: taskQueueName}}
+	if namespace != "" {
+		keys = append(keys, taskQueueKey{name: taskQueueName})
+	}
 
-	if !exists {
+	found := false
+	for _, key := range keys {
+		s.mu.RLock()
+		tq, exists := s.taskQueues[key]
+		s.mu.RUnlock()
+		if !exists {
+			continue
+		}
+		found = true
+		if tq.CompleteTask(taskID) {
+			return nil
+		}
+	}
+
+	if !found {
 		return ErrTaskQueueNotFound
 	}
+	return ErrTaskNotFound
+}
 
-	if !tq.CompleteTask(taskID) {
-		return ErrTaskNotFound
+// PollTask long-polls the namespace's queue. A poll without a namespace, as
+// sent by workers that serve every namespace, takes tasks from the queues
+// named taskQueueName in all namespaces instead.
+func (s *Service) PollTask(ctx context.Context, namespace, taskQueueName string, identity string) (*engine.Task, error) {
+	// Creating the queue loads one persisted in Redis that this host has not
+	// served yet.
+	tq := s.GetOrCreateTaskQueue(namespace, taskQueueName, engine.TaskQueueKindNormal)
+	if namespace == "" {
+		return s.pollAnyNamespace(ctx, taskQueueName, identity)
 	}
 
-	return nil
+	task, err := tq.Poll(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// pollAnyNamespace takes the first available task from the queues named
+// queueName across namespaces, waiting up to the long-poll timeout for one.
+// Each attempt starts from a different queue so a namespace with a deep
+// backlog cannot starve the others.
+func (s *Service) pollAnyNamespace(ctx context.Context, queueName string, identity string) (*engine.Task, error) {
+	timeout := s.longPollTimeout
+	if timeout <= 0 {
+		timeout = engine.DefaultLongPollTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	for {
+		queues := s.queuesNamed(ctx, queueName)
+		if len(queues) > 0 {
+			start := int(s.nextPollQueue.Add(1) % uint64(len(queues)))
+			for i := range queues {
+				task, err := queues[(start+i)%len(queues)].TryPoll(ctx, identity)
+				if errors.Is(err, engine.ErrRateLimited) {
+					continue
+				}
+				if err != nil || task != nil {
+					return task, err
+				}
+			}
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return nil, nil
+		}
+		if wait > anyNamespacePollInterval {
+			wait = anyNamespacePollInterval
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
 }
 
-func (s *Service) PollTask(ctx context.Context, taskQueueName string, identity string) (*engine.Task, error) {
+// queuesNamed returns the normal queues called name in every namespace,
+// including the un-namespaced one, ordered by namespace. With Redis, queues
+// other hosts created are loaded first.
+func (s *Service) queuesNamed(ctx context.Context, name string) []*engine.TaskQueue {
+	if s.redisClient != nil {
+		namespaces, err := s.redisClient.SMembers(ctx, queueNamespacesKey(name)).Result()
+		if err != nil {
+			s.logger.Warn("failed to list task queue namespaces",
+				slog.String("task_queue", name),
+				slog.String("error", err.Error()),
+			)
+		}
+		for _, namespace := range namespaces {
+			s.GetOrCreateTaskQueue(namespace, name, engine.TaskQueueKindNormal)
+		}
+	}
+
 	s.mu.RLock()
-	tq, exists := s.taskQueues[taskQueueName]
+	keys := make([]taskQueueKey, 0, len(s.taskQueues))
+	for key, tq := range s.taskQueues {
+		if key.name == name && tq.Kind() == engine.TaskQueueKindNormal {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].namespace < keys[j].namespace })
+	queues := make([]*engine.TaskQueue, len(keys))
+	for i, key := range keys {
+		queues[i] = s.taskQueues[key]
+	}
 	s.mu.RUnlock()
+	return queues
+}
 
-	if !exists {
-		// If queue is not in memory, we might still have it in Redis if persistence is enabled.
-		// But s.taskQueues tracks active queues.
-		// Let's try to "create" (load) it.
-		tq = s.GetOrCreateTaskQueue(taskQueueName, engine.TaskQueueKindNormal)
+// queueNamespacesKey is the Redis set of namespaces with a queue called name.
+func queueNamespacesKey(name string) string {
+	return "matching:taskqueue_namespaces:" + name
+}
+
+// namespaceLimits returns the backpressure limits for the namespace's queues.
+func (s *Service) namespaceLimits(namespace string) (softLimit, hardLimit int) {
+	softLimit, hardLimit = s.softLimit, s.hardLimit
+	if s.namespaces == nil || namespace == "" {
+		return softLimit, hardLimit
 	}
 
-	task, err := tq.Poll(ctx, identity)
+	cfg, err := s.namespaces.GetNamespace(context.Background(), namespace)
 	if err != nil {
-		return nil, err
+		return softLimit, hardLimit
 	}
-
-	return task, nil
+	if cfg.TaskQueueSoftLimit > 0 {
+		softLimit = cfg.TaskQueueSoftLimit
+	}
+	if cfg.TaskQueueHardLimit > 0 {
+		hardLimit = cfg.TaskQueueHardLimit
+	}
+	return softLimit, hardLimit
 }
 
-func (s *Service) GetOrCreateTaskQueue(name string, kind engine.TaskQueueKind) *engine.TaskQueue {
+func (s *Service) GetOrCreateTaskQueue(namespace, name string, kind engine.TaskQueueKind) *engine.TaskQueue {
+	key := taskQueueKey{namespace: namespace, name: name}
 	s.mu.RLock()
-	tq, exists := s.taskQueues[name]
+	tq, exists := s.taskQueues[key]
 	s.mu.RUnlock()
 
 	if exists {
 		return tq
 	}
 
+	softLimit, hardLimit := s.namespaceLimits(namespace)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if tq, exists = s.taskQueues[name]; exists {
+	if tq, exists = s.taskQueues[key]; exists {
 		return tq
 	}
 
-	partition := s.partitionMgr.GetPartitionForTaskQueue(name)
-	tq = partition.GetOrCreateTaskQueueWithConfig(name, kind, defaultRateLimit, defaultBurst, engine.TaskQueueConfig{
-		DLQ:    s.dlq,
-		WAL:    s.wal,
-		Logger: s.logger,
-	})
-	s.taskQueues[name] = tq
+	partition := s.partitionMgr.GetPartitionForTaskQueue(key.storeName())
+	cfg := engine.TaskQueueConfig{
+		DLQ:          s.dlq,
+		Backpressure: engine.NewBackpressure(softLimit, hardLimit, s.logger),
+		WAL:          s.wal,
+		Logger:       s.logger,
+
+		PoisonPillThreshold: s.poisonPillThreshold,
+		PoisonPillWindow:    s.poisonPillWindow,
+
+		LongPollTimeout: s.longPollTimeout,
+
+		OnScheduleToStartTimeout: s.reportScheduleToStartTimeout,
+	}
+	if kind == engine.TaskQueueKindSticky {
+		cfg.StickyGracePeriod = s.stickyGracePeriod
+		cfg.OnStickyMiss = s.fallbackStickyTask
+	}
+	tq = partition.GetOrCreateTaskQueueWithConfig(key.storeName(), kind, defaultRateLimit, defaultBurst, cfg)
+	s.taskQueues[key] = tq
+	s.queuePartitions[key] = partition
+
+	if s.redisClient != nil && namespace != "" && kind == engine.TaskQueueKindNormal {
+		if err := s.redisClient.SAdd(context.Background(), queueNamespacesKey(name), namespace).Err(); err != nil {
+			s.logger.Warn("failed to record task queue namespace",
+				slog.String("namespace", namespace),
+				slog.String("task_queue", name),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
 
 	s.logger.Info("created task queue",
+		slog.String("namespace", namespace),
 		slog.String("name", name),
 		slog.Int("kind", int(kind)),
 		slog.Int("partition", int(partition.ID)),
+		slog.Int("soft_limit", softLimit),
+		slog.Int("hard_limit", hardLimit),
 	)
 
 	return tq
 }
 
 // GetOrCreateStickyQueue creates or retrieves a sticky task queue for a specific worker identity.
+// Sticky queues belong to a worker rather than a namespace.
 func (s *Service) GetOrCreateStickyQueue(workerIdentity string) *engine.TaskQueue {
-	name := "sticky:" + workerIdentity
-	return s.GetOrCreateTaskQueue(name, engine.TaskQueueKindSticky)
+	name := stickyQueuePrefix + workerIdentity
+	return s.GetOrCreateTaskQueue("", name, engine.TaskQueueKindSticky)
 }
 
-func (s *Service) GetTaskQueue(name string) (*engine.TaskQueue, error) {
+func (s *Service) GetTaskQueue(namespace, name string) (*engine.TaskQueue, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	tq, exists := s.taskQueues[name]
+	tq, exists := s.taskQueues[taskQueueKey{namespace: namespace, name: name}]
 	if !exists {
 		return nil, ErrTaskQueueNotFound
 	}
 	return tq, nil
 }
 
+// ListTaskQueues returns the namespace's active task queues ordered by name.
+// The empty namespace lists the un-namespaced queues.
+func (s *Service) ListTaskQueues(namespace string) []TaskQueueInfo {
+	s.mu.RLock()
+	queues := make(map[string]*engine.TaskQueue)
+	for key, tq := range s.taskQueues {
+		if key.namespace == namespace {
+			queues[key.name] = tq
+		}
+	}
+	s.mu.RUnlock()
+
+	infos := make([]TaskQueueInfo, 0, len(queues))
+	for name, tq := range queues {
+		infos = append(infos, TaskQueueInfo{
+			Name:              name,
+			Kind:              tq.Kind(),
+			PendingTasks:      tq.PendingTaskCount(),
+			Pollers:           tq.PollerCount(),
+			BackpressureState: tq.BackpressureState(),
+			OldestTaskAge:     tq.OldestTaskAge(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
 func (s *Service) PartitionManager() *partition.Manager {
 	return s.partitionMgr
 }
 
+// SetPartitionWeights changes how new task queues are spread over the
+// partitions; see partition.Manager.SetWeights. Existing queues keep their
+// partition. Nil or empty weights weight every partition equally.
+func (s *Service) SetPartitionWeights(weights map[int32]int) error {
+	s.weightsMu.Lock()
+	defer s.weightsMu.Unlock()
+
+	if err := s.partitionMgr.SetWeights(weights); err != nil {
+		return err
+	}
+	s.appliedWeights = make(map[int32]int, len(weights))
+	for id := int32(0); id < s.partitionMgr.NumPartitions(); id++ {
+		weight := s.partitionMgr.Weight(id)
+		s.appliedWeights[id] = weight
+		s.metrics.PartitionWeight(id, weight)
+	}
+	if len(weights) > 0 {
+		s.logger.Info("partition weights updated", slog.Any("weights", s.appliedWeights))
+	}
+	return nil
+}
+
+// Partitions returns each partition's weight, task queue count, and the
+// number of tasks added to it, ordered by partition ID.
+func (s *Service) Partitions() []PartitionInfo {
+	infos := make([]PartitionInfo, 0, s.partitionMgr.NumPartitions())
+	for id := int32(0); id < s.partitionMgr.NumPartitions(); id++ {
+		p := s.partitionMgr.GetPartition(id)
+		infos = append(infos, PartitionInfo{
+			ID:         id,
+			Weight:     s.partitionMgr.Weight(id),
+			TaskQueues: p.TaskQueueCount(),
+			TasksAdded: p.Load.Load(),
+		})
+	}
+	return infos
+}
+
+// refreshPartitionWeights applies the control-plane partition weights when
+// they differ from the ones in effect.
+func (s *Service) refreshPartitionWeights(ctx context.Context) {
+	raw, err := s.dynamicConfig.GetConfig(ctx, controlplane.PartitionWeightsConfigKey)
+	if err != nil {
+		if !errors.Is(err, controlplane.ErrConfigKeyNotFound) {
+			s.logger.Warn("failed to load partition weights", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	var cfg controlplane.PartitionWeightConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		s.logger.Warn("invalid partition weights", slog.String("error", err.Error()))
+		return
+	}
+
+	s.weightsMu.Lock()
+	unchanged := true
+	for id := int32(0); id < s.partitionMgr.NumPartitions(); id++ {
+		weight, ok := cfg.Weights[id]
+		if !ok {
+			weight = 1
+		}
+		if s.appliedWeights[id] != weight {
+			unchanged = false
+			break
+		}
+	}
+	s.weightsMu.Unlock()
+	if unchanged {
+		return
+	}
+
+	if err := s.SetPartitionWeights(cfg.Weights); err != nil {
+		s.logger.Warn("ignoring partition weights", slog.String("error", err.Error()))
+	}
+}
+
+func (s *Service) runPartitionWeightRefresher(ctx context.Context) {
+	defer s.wg.Done()
+
+	s.refreshPartitionWeights(ctx)
+	ticker := time.NewTicker(s.weightRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.refreshPartitionWeights(ctx)
+		}
+	}
+}
+
 // GetDLQEntries returns all entries in the dead letter queue.
 func (s *Service) GetDLQEntries() []*engine.DLQEntry {
 	return s.dlq.List()
@@ -212,13 +684,7 @@ func (s *Service) RetryDLQTask(ctx context.Context, taskID string) error {
 		return err
 	}
 
-	// Determine the queue name from the task's namespace or use default
-	queueName := "default"
-	if task.Namespace != "" {
-		queueName = task.Namespace
-	}
-
-	tq := s.GetOrCreateTaskQueue(queueName, engine.TaskQueueKindNormal)
+	tq := s.GetOrCreateTaskQueue(task.Namespace, taskQueueName(task), engine.TaskQueueKindNormal)
 	return tq.AddTask(task)
 }
 
@@ -227,23 +693,39 @@ func (s *Service) PurgeDLQ() int {
 	return s.dlq.Purge()
 }
 
-// GetAllMetrics returns a snapshot of metrics for every active task queue.
+// taskQueueName returns the queue a task was added to. Tasks persisted
+// before the queue was recorded on them belong to the default queue.
+func taskQueueName(task *engine.Task) string {
+	if task.TaskQueue == "" {
+		return defaultQueueName
+	}
+	return task.TaskQueue
+}
+
+// GetAllMetrics returns a snapshot of metrics for every active task queue,
+// keyed by "namespace/queue" (or the bare name for un-namespaced queues).
 func (s *Service) GetAllMetrics() map[string]*engine.MetricsSnapshot {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
+	queues := make(map[string]*engine.TaskQueue, len(s.taskQueues))
+	for key, tq := range s.taskQueues {
+		queues[key.storeName()] = tq
+	}
+	s.mu.RUnlock()
 
-	result := make(map[string]*engine.MetricsSnapshot, len(s.taskQueues))
-	for name, tq := range s.taskQueues {
+	// The oldest task may be read from Redis, so it is looked up outside s.mu.
+	result := make(map[string]*engine.MetricsSnapshot, len(queues))
+	for name, tq := range queues {
 		snap := tq.Metrics().Snapshot()
+		snap.OldestTaskAge = tq.OldestTaskAge()
 		result[name] = &snap
 	}
 	return result
 }
 
 // GetQueueStats returns a metrics snapshot for a specific queue.
-func (s *Service) GetQueueStats(queueName string) (*engine.MetricsSnapshot, error) {
+func (s *Service) GetQueueStats(namespace, queueName string) (*engine.MetricsSnapshot, error) {
 	s.mu.RLock()
-	tq, exists := s.taskQueues[queueName]
+	tq, exists := s.taskQueues[taskQueueKey{namespace: namespace, name: queueName}]
 	s.mu.RUnlock()
 
 	if !exists {
@@ -251,6 +733,7 @@ func (s *Service) GetQueueStats(queueName string) (*engine.MetricsSnapshot, erro
 	}
 
 	snap := tq.Metrics().Snapshot()
+	snap.OldestTaskAge = tq.OldestTaskAge()
 	return &snap, nil
 }
 
@@ -279,11 +762,7 @@ func (s *Service) Start(ctx context.Context) error {
 		} else if len(tasks) > 0 {
 			s.mu.Unlock()
 			for _, task := range tasks {
-				queueName := "default"
-				if task.Namespace != "" {
-					queueName = task.Namespace
-				}
-				tq := s.GetOrCreateTaskQueue(queueName, engine.TaskQueueKindNormal)
+				tq := s.GetOrCreateTaskQueue(task.Namespace, taskQueueName(task), engine.TaskQueueKindNormal)
 				if err := tq.AddTask(task); err != nil && !errors.Is(err, engine.ErrTaskExists) {
 					s.logger.Error("failed to recover task",
 						slog.String("task_id", task.ID),
@@ -307,6 +786,11 @@ func (s *Service) Start(ctx context.Context) error {
 	s.wg.Add(1)
 	go s.runLeaseReaper(ctx)
 
+	if s.dynamicConfig != nil {
+		s.wg.Add(1)
+		go s.runPartitionWeightRefresher(ctx)
+	}
+
 	s.logger.Info("matching service started")
 	return nil
 }
@@ -348,6 +832,8 @@ func (s *Service) runLeaseReaper(ctx context.Context) {
 			return
 		case <-ticker.C:
 			s.requeueExpiredTasks()
+			s.dropExpiredTasks()
+			s.fallbackStickyTasks()
 		}
 	}
 }
@@ -370,3 +856,113 @@ func (s *Service) requeueExpiredTasks() {
 		s.logger.Info("requeued expired tasks", slog.Int("count", totalRequeued))
 	}
 }
+
+// dropExpiredTasks drops queued tasks past their schedule-to-start timeout
+// from every queue, including queues no worker polls.
+func (s *Service) dropExpiredTasks() {
+	s.mu.RLock()
+	queues := make([]*engine.TaskQueue, 0, len(s.taskQueues))
+	for _, tq := range s.taskQueues {
+		queues = append(queues, tq)
+	}
+	s.mu.RUnlock()
+
+	totalDropped := 0
+	for _, tq := range queues {
+		totalDropped += tq.DropExpiredTasks()
+	}
+
+	if totalDropped > 0 {
+		s.logger.Info("dropped tasks past their schedule-to-start timeout", slog.Int("count", totalDropped))
+	}
+}
+
+// fallbackStickyTasks moves the tasks their sticky worker did not take in
+// time to their normal queues.
+func (s *Service) fallbackStickyTasks() {
+	s.mu.RLock()
+	queues := make([]*engine.TaskQueue, 0, len(s.taskQueues))
+	for _, tq := range s.taskQueues {
+		if tq.Kind() == engine.TaskQueueKindSticky {
+			queues = append(queues, tq)
+		}
+	}
+	s.mu.RUnlock()
+
+	totalMissed := 0
+	for _, tq := range queues {
+		totalMissed += tq.FallbackStickyTasks()
+	}
+
+	if totalMissed > 0 {
+		s.logger.Info("sticky tasks fell back to their normal queues", slog.Int("count", totalMissed))
+	}
+}
+
+// fallbackStickyTask adds a task its sticky worker did not take in time to
+// the workflow's normal queue, where any worker may take it.
+func (s *Service) fallbackStickyTask(task *engine.Task) {
+	queueName := taskQueueName(task)
+	if strings.HasPrefix(queueName, stickyQueuePrefix) {
+		// The task does not name its normal queue.
+		queueName = defaultQueueName
+	}
+	s.metrics.StickyMiss(task.Namespace, queueName)
+	if _, err := s.AddTask(context.Background(), task.Namespace, queueName, task); err != nil {
+		s.logger.Error("failed to fall back sticky task",
+			slog.String("task_id", task.ID),
+			slog.String("namespace", task.Namespace),
+			slog.String("task_queue", queueName),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// HeartbeatTask extends the lease of an in-flight task and renews its
+// workflow's sticky affinity to identity. Like CompleteTask it falls back to
+// the un-namespaced queue.
+func (s *Service) HeartbeatTask(ctx context.Context, namespace, taskQueueName, taskID, identity string) error {
+	keys := []taskQueueKey{{namespace: namespace, name: taskQueueName}}
+	if namespace != "" {
+		keys = append(keys, taskQueueKey{name: taskQueueName})
+	}
+
+	found := false
+	for _, key := range keys {
+		s.mu.RLock()
+		tq, exists := s.taskQueues[key]
+		s.mu.RUnlock()
+		if !exists {
+			continue
+		}
+		found = true
+		if tq.HeartbeatTask(taskID, identity) {
+			return nil
+		}
+	}
+
+	if !found {
+		return ErrTaskQueueNotFound
+	}
+	return ErrTaskNotFound
+}
+
+// reportScheduleToStartTimeout counts a task a queue dropped unstarted and
+// reports it to the configured TaskTimeoutReporter.
+func (s *Service) reportScheduleToStartTimeout(task *engine.Task) {
+	s.metrics.ScheduleToStartTimeout(task.Namespace, taskQueueName(task))
+	if s.taskTimeouts == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeoutDeadline)
+	defer cancel()
+	if err := s.taskTimeouts.ReportScheduleToStartTimeout(ctx, task); err != nil {
+		s.logger.Error("failed to report schedule-to-start timeout",
+			slog.String("task_id", task.ID),
+			slog.String("namespace", task.Namespace),
+			slog.String("workflow_id", task.WorkflowID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,169 @@
+package matching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/controlplane"
+	"github.com/linkflow/engine/internal/matching/engine"
+	"github.com/linkflow/engine/internal/matching/partition"
+	"github.com/linkflow/engine/internal/observability/metrics"
+)
+
+func TestServiceIsolatesNamespaceQueues(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	namespaces := controlplane.NewService(controlplane.Config{Logger: logger})
+	_ = namespaces.CreateNamespace(ctx, &controlplane.NamespaceConfig{ID: "noisy", Name: "noisy", TaskQueueSoftLimit: 1, TaskQueueHardLimit: 2})
+	svc := NewService(Config{
+		Logger:          logger,
+		Namespaces:      namespaces,
+		LongPollTimeout: 50 * time.Millisecond,
+	})
+
+	add := func(namespace, id string) (engine.BackpressureState, error) {
+		return svc.AddTask(ctx, namespace, "default", &engine.Task{ID: id, Namespace: namespace, TaskQueue: "default", ScheduledTime: time.Now()})
+	}
+	for _, id := range []string{"n1", "n2"} {
+		if _, err := add("noisy", id); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+	}
+	if _, err := add("noisy", "n3"); !errors.Is(err, engine.ErrBackpressure) {
+		t.Fatalf("third noisy task error = %v, want backpressure at the namespace's hard limit", err)
+	}
+	if state, err := add("quiet", "q1"); err != nil || state != engine.BackpressureNormal {
+		t.Fatalf("quiet task = %v, %v, want accepted with normal pressure", state, err)
+	}
+
+	if queues := svc.ListTaskQueues("noisy"); len(queues) != 1 || queues[0].Name != "default" || queues[0].PendingTasks != 2 {
+		t.Fatalf("noisy queues = %+v", queues)
+	}
+
+	// A namespaced poll only sees its own namespace.
+	task, err := svc.PollTask(ctx, "quiet", "default", "worker")
+	if err != nil || task == nil || task.ID != "q1" {
+		t.Fatalf("quiet poll = %+v, %v, want q1", task, err)
+	}
+	if task, err := svc.PollTask(ctx, "quiet", "default", "worker"); err != nil || task != nil {
+		t.Fatalf("empty quiet poll = %+v, %v, want nothing", task, err)
+	}
+	if err := svc.CompleteTask(ctx, "quiet", "default", "q1"); err != nil {
+		t.Fatalf("complete q1: %v", err)
+	}
+
+	// A poll without a namespace drains every namespace's queue.
+	_, _ = add("quiet", "q2")
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		task, err := svc.PollTask(ctx, "", "default", "worker")
+		if err != nil || task == nil {
+			t.Fatalf("poll %d = %+v, %v", i, task, err)
+		}
+		seen[task.ID] = true
+	}
+	if !seen["n1"] || !seen["n2"] || !seen["q2"] {
+		t.Fatalf("polled %v, want n1, n2 and q2", seen)
+	}
+}
+
+func TestServiceRoutesTaskQueuesByPartitionWeight(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cp := controlplane.NewService(controlplane.Config{Logger: logger})
+	svc := NewService(Config{
+		NumPartitions:    4,
+		Logger:           logger,
+		PartitionWeights: map[int32]int{0: 7},
+		DynamicConfig:    cp,
+		Metrics:          metrics.NewRegistry(),
+	})
+
+	addQueues := func(prefix string, n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			queue := fmt.Sprintf("%s-%d", prefix, i)
+			if _, err := svc.AddTask(ctx, "default", queue, &engine.Task{ID: queue, TaskQueue: queue, ScheduledTime: time.Now()}); err != nil {
+				t.Fatalf("add %s: %v", queue, err)
+			}
+		}
+	}
+	addQueues("weighted", 1000)
+
+	partitions := svc.Partitions()
+	if partitions[0].Weight != 7 || partitions[1].Weight != 1 {
+		t.Fatalf("weights = %+v", partitions)
+	}
+	// Partition 0 owns 70% of the ring; allow for hashing noise.
+	if partitions[0].TasksAdded < 600 {
+		t.Fatalf("partition 0 received %d of 1000 tasks, want about 700", partitions[0].TasksAdded)
+	}
+	for _, p := range partitions[1:] {
+		if p.TasksAdded > 150 {
+			t.Fatalf("partition %d received %d of 1000 tasks, want about 100", p.ID, p.TasksAdded)
+		}
+	}
+
+	// Weights from the control plane replace the configured ones; existing
+	// queues stay on their partition.
+	if err := cp.SetConfig(ctx, controlplane.PartitionWeightsConfigKey, json.RawMessage(`{"weights":{"0":0}}`)); err != nil {
+		t.Fatalf("set config: %v", err)
+	}
+	svc.refreshPartitionWeights(ctx)
+	before := svc.Partitions()[0].TaskQueues
+	addQueues("drained", 200)
+	if after := svc.Partitions()[0]; after.Weight != 0 || after.TaskQueues != before {
+		t.Fatalf("partition 0 = %+v, want weight 0 and no new queues (had %d)", after, before)
+	}
+
+	if err := svc.SetPartitionWeights(map[int32]int{0: 0, 1: 0, 2: 0, 3: 0}); !errors.Is(err, partition.ErrInvalidWeights) {
+		t.Fatalf("all-zero weights error = %v, want ErrInvalidWeights", err)
+	}
+	if err := svc.SetPartitionWeights(map[int32]int{9: 2}); !errors.Is(err, partition.ErrInvalidWeights) {
+		t.Fatalf("unknown partition error = %v, want ErrInvalidWeights", err)
+	}
+}
+
+func TestServiceStickyMissFallsBackToNormalQueue(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(Config{
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+		LongPollTimeout:   50 * time.Millisecond,
+		StickyGracePeriod: time.Second,
+		Metrics:           metrics.NewRegistry(),
+	})
+
+	sticky := svc.GetOrCreateStickyQueue("worker-a")
+	if err := sticky.AddTask(&engine.Task{ID: "s1", Namespace: "default", TaskQueue: "orders", WorkflowID: "wf-1", ScheduledTime: time.Now()}); err != nil {
+		t.Fatalf("add sticky task: %v", err)
+	}
+	if task, err := sticky.TryPoll(ctx, "worker-a"); err != nil || task == nil {
+		t.Fatalf("sticky poll = %+v, %v", task, err)
+	}
+	if err := svc.HeartbeatTask(ctx, "", sticky.Name(), "s1", "worker-a"); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	if err := svc.HeartbeatTask(ctx, "", sticky.Name(), "missing", "worker-a"); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("heartbeat for an unknown task = %v, want ErrTaskNotFound", err)
+	}
+
+	// worker-a is still bound to wf-1 but does not take its next task.
+	if err := sticky.AddTask(&engine.Task{ID: "s2", Namespace: "default", TaskQueue: "orders", WorkflowID: "wf-1", ScheduledTime: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("add sticky task: %v", err)
+	}
+	svc.fallbackStickyTasks()
+
+	task, err := svc.PollTask(ctx, "default", "orders", "worker-b")
+	if err != nil || task == nil || task.ID != "s2" {
+		t.Fatalf("normal queue poll = %+v, %v; want the fallen back task", task, err)
+	}
+	if sticky.PendingTaskCount() != 0 || sticky.StickyWorker("wf-1") != "" {
+		t.Fatalf("sticky queue still holds %d tasks, bound to %q", sticky.PendingTaskCount(), sticky.StickyWorker("wf-1"))
+	}
+}
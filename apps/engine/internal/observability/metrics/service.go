@@ -151,6 +151,26 @@ func (m *ServiceMetrics) TaskQueueDepth(taskQueue string, depth int64) {
 	}).Set(float64(depth))
 }
 
+// ScheduleToStartTimeout records a task dropped because no worker started
+// it within its schedule-to-start timeout.
+func (m *ServiceMetrics) ScheduleToStartTimeout(namespace, taskQueue string) {
+	m.registry.Counter("linkflow_task_schedule_to_start_timeouts_total", Labels{
+		"service":    m.service,
+		"namespace":  namespace,
+		"task_queue": taskQueue,
+	}).Inc()
+}
+
+// StickyMiss records a task that its sticky worker did not take within the
+// grace period, and so fell back to its normal queue.
+func (m *ServiceMetrics) StickyMiss(namespace, taskQueue string) {
+	m.registry.Counter("linkflow_task_sticky_misses_total", Labels{
+		"service":    m.service,
+		"namespace":  namespace,
+		"task_queue": taskQueue,
+	}).Inc()
+}
+
 // --- Timer Metrics ---
 
 // TimerScheduled records a scheduled timer.
@@ -215,6 +235,52 @@ func (m *ServiceMetrics) HistorySize(eventCount int64) {
 	}, []float64{10, 50, 100, 500, 1000, 5000, 10000}).Observe(float64(eventCount))
 }
 
+// HistoryEventsRetrieved records history events read by clients.
+func (m *ServiceMetrics) HistoryEventsRetrieved(count int) {
+	m.registry.Counter("linkflow_history_events_retrieved_total", Labels{
+		"service": m.service,
+	}).Add(int64(count))
+}
+
+// HistoryStateConflict records a mutable state version conflict.
+func (m *ServiceMetrics) HistoryStateConflict(namespace string) {
+	m.registry.Counter("linkflow_history_state_conflicts_total", Labels{
+		"service":   m.service,
+		"namespace": namespace,
+	}).Inc()
+}
+
+// HistorySignalThrottled records a signal rejected by the per-execution
+// signal rate limit.
+func (m *ServiceMetrics) HistorySignalThrottled(namespace string) {
+	m.registry.Counter("linkflow_history_signals_throttled_total", Labels{
+		"service":   m.service,
+		"namespace": namespace,
+	}).Inc()
+}
+
+// HistoryStateConflictRetried records a retry after a state conflict.
+func (m *ServiceMetrics) HistoryStateConflictRetried() {
+	m.registry.Counter("linkflow_history_state_conflict_retries_total", Labels{
+		"service": m.service,
+	}).Inc()
+}
+
+// HistoryStateConflictRetryDepth sets the current conflict retry depth.
+func (m *ServiceMetrics) HistoryStateConflictRetryDepth(namespace string, depth int) {
+	m.registry.Gauge("linkflow_history_state_conflict_retry_depth", Labels{
+		"service":   m.service,
+		"namespace": namespace,
+	}).Set(float64(depth))
+}
+
+// HistoryEventsCompacted records history events removed by compaction.
+func (m *ServiceMetrics) HistoryEventsCompacted(count int) {
+	m.registry.Counter("linkflow_history_events_compacted_total", Labels{
+		"service": m.service,
+	}).Add(int64(count))
+}
+
 // --- Cache Metrics ---
 
 // CacheHit records a cache hit.
@@ -258,6 +324,24 @@ func (m *ServiceMetrics) ShardsOwned(count int) {
 	}).Set(float64(count))
 }
 
+// --- Partition Metrics ---
+
+// PartitionTaskAdded records a task added to a task queue partition.
+func (m *ServiceMetrics) PartitionTaskAdded(partitionID int32) {
+	m.registry.Counter("linkflow_partition_tasks_total", Labels{
+		"service":   m.service,
+		"partition": intToStr(int64(partitionID)),
+	}).Inc()
+}
+
+// PartitionWeight sets the routing weight of a task queue partition.
+func (m *ServiceMetrics) PartitionWeight(partitionID int32, weight int) {
+	m.registry.Gauge("linkflow_partition_weight", Labels{
+		"service":   m.service,
+		"partition": intToStr(int64(partitionID)),
+	}).Set(float64(weight))
+}
+
 // --- gRPC Metrics ---
 
 // GRPCRequestReceived records a received gRPC request.
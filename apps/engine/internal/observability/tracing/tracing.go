@@ -21,7 +21,8 @@ func (sc SpanContext) IsValid() bool {
 	return sc.TraceID != "" && sc.SpanID != ""
 }
 
-// Span represents a unit of work within a trace.
+// Span represents a unit of work within a trace. All methods are no-ops on a
+// nil span, which a nil Tracer starts.
 type Span struct {
 	Name       string
 	Context    SpanContext
@@ -53,6 +54,9 @@ type SpanEvent struct {
 
 // End finishes the span.
 func (s *Span) End() {
+	if s == nil {
+		return
+	}
 	s.mu.Lock()
 	s.EndTime = time.Now()
 	s.mu.Unlock()
@@ -64,6 +68,9 @@ func (s *Span) End() {
 
 // SetStatus sets the span status.
 func (s *Span) SetStatus(status SpanStatus, message string) {
+	if s == nil {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.Status = status
@@ -72,6 +79,9 @@ func (s *Span) SetStatus(status SpanStatus, message string) {
 
 // SetAttribute sets an attribute on the span.
 func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.Attributes == nil {
@@ -82,6 +92,9 @@ func (s *Span) SetAttribute(key string, value interface{}) {
 
 // SetAttributes sets multiple attributes on the span.
 func (s *Span) SetAttributes(attrs map[string]interface{}) {
+	if s == nil {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.Attributes == nil {
@@ -94,6 +107,9 @@ func (s *Span) SetAttributes(attrs map[string]interface{}) {
 
 // AddEvent adds an event to the span.
 func (s *Span) AddEvent(name string, attrs map[string]interface{}) {
+	if s == nil {
+		return
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.Events = append(s.Events, SpanEvent{
@@ -105,7 +121,7 @@ func (s *Span) AddEvent(name string, attrs map[string]interface{}) {
 
 // RecordError records an error in the span.
 func (s *Span) RecordError(err error) {
-	if err == nil {
+	if s == nil || err == nil {
 		return
 	}
 	s.AddEvent("exception", map[string]interface{}{
@@ -117,6 +133,9 @@ func (s *Span) RecordError(err error) {
 
 // Duration returns the span duration.
 func (s *Span) Duration() time.Duration {
+	if s == nil {
+		return 0
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.EndTime.IsZero() {
@@ -208,8 +227,12 @@ func NewTracer(config TracerConfig) *Tracer {
 	}
 }
 
-// Start creates and starts a new span.
+// Start creates and starts a new span. A nil tracer returns ctx unchanged
+// and a nil span, so tracing can be left unconfigured.
 func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
 	parentCtx := SpanFromContext(ctx)
 
 	var traceID, parentSpanID string
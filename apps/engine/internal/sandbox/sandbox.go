@@ -11,6 +11,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -42,8 +44,17 @@ type ExecutionRequest struct {
 	CPULimit    float64 // cores
 	Mode        ExecutionMode
 	Environment map[string]string
+
+	// Secrets are added to the process environment after the safe
+	// environment. Unlike Environment they are never written to the work
+	// directory, their values are redacted from the ExecutionResult, and the
+	// map is cleared once the run ends. Names must pass isValidSecretName.
+	Secrets map[string]string
 }
 
+// redactedSecret replaces secret values in execution results.
+const redactedSecret = "[REDACTED]"
+
 // that can be passed to sandboxed processes.
 var safeEnvVars = map[string]bool{
 	"PATH":   true,
@@ -65,10 +76,12 @@ type ExecutionResult struct {
 
 // Sandbox provides isolated code execution.
 type Sandbox struct {
-	logger   *slog.Logger
-	workDir  string
-	runtimes map[string]Runtime
-	mu       sync.RWMutex
+	logger           *slog.Logger
+	workDir          string
+	maxMemoryBytes   int64
+	maxExecutionTime time.Duration
+	runtimes         map[string]Runtime
+	mu               sync.RWMutex
 }
 
 // Runtime represents a language runtime.
@@ -98,11 +111,19 @@ func NewSandbox(config Config) (*Sandbox, error) {
 	if config.WorkDir == "" {
 		config.WorkDir = os.TempDir()
 	}
+	if config.MaxMemoryBytes <= 0 {
+		config.MaxMemoryBytes = 128 * 1024 * 1024 // 128MB
+	}
+	if config.MaxExecutionTime <= 0 {
+		config.MaxExecutionTime = 30 * time.Second
+	}
 
 	sandbox := &Sandbox{
-		logger:   config.Logger,
-		workDir:  config.WorkDir,
-		runtimes: make(map[string]Runtime),
+		logger:           config.Logger,
+		workDir:          config.WorkDir,
+		maxMemoryBytes:   config.MaxMemoryBytes,
+		maxExecutionTime: config.MaxExecutionTime,
+		runtimes:         make(map[string]Runtime),
 	}
 
 	// Register built-in runtimes (they enforce ExecutionModeContainer; host-based execution is disabled)
@@ -135,12 +156,20 @@ func (s *Sandbox) Execute(ctx context.Context, req *ExecutionRequest) (*Executio
 		return nil, fmt.Errorf("runtime not available: %s", req.Language)
 	}
 
-	// Set defaults
-	if req.Timeout == 0 {
-		req.Timeout = 30 * time.Second
+	// Secrets only live for the run; clear them even if it never starts.
+	defer clear(req.Secrets)
+	for name := range req.Secrets {
+		if !isValidSecretName(name) {
+			return nil, fmt.Errorf("invalid secret name: %q", name)
+		}
+	}
+
+	// Set defaults; requests may lower the limits but never raise them
+	if req.Timeout <= 0 || req.Timeout > s.maxExecutionTime {
+		req.Timeout = s.maxExecutionTime
 	}
-	if req.MemoryLimit == 0 {
-		req.MemoryLimit = 128 * 1024 * 1024 // 128MB
+	if req.MemoryLimit <= 0 || req.MemoryLimit > s.maxMemoryBytes {
+		req.MemoryLimit = s.maxMemoryBytes
 	}
 
 	// Execute with timeout context
@@ -151,6 +180,7 @@ func (s *Sandbox) Execute(ctx context.Context, req *ExecutionRequest) (*Executio
 	result, err := runtime.Execute(ctx, req)
 	if result != nil {
 		result.Duration = time.Since(start)
+		redactSecrets(result, req.Secrets)
 	}
 
 	return result, err
@@ -218,7 +248,8 @@ console.log(JSON.stringify({ __output: output }));
 
 	// SECURITY: Only pass explicitly allowed environment variables
 	// Never inherit the full parent environment
-	cmd.Env = buildSafeEnv(req.Environment)
+	cmd.Env = withSecrets(buildSafeEnv(req.Environment), req.Secrets)
+	defer wipeEnv(cmd.Env)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -314,7 +345,8 @@ print(json.dumps({"__output": output}))
 	cmd.Dir = tmpDir
 
 	// SECURITY: Only pass explicitly allowed environment variables
-	cmd.Env = buildSafeEnv(req.Environment)
+	cmd.Env = withSecrets(buildSafeEnv(req.Environment), req.Secrets)
+	defer wipeEnv(cmd.Env)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -407,6 +439,8 @@ func (r *BashRuntime) Execute(ctx context.Context, req *ExecutionRequest) (*Exec
 			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
 		}
 	}
+	cmd.Env = withSecrets(cmd.Env, req.Secrets)
+	defer wipeEnv(cmd.Env)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -490,11 +524,20 @@ func (r *ContainerRuntime) Execute(ctx context.Context, req *ExecutionRequest) (
 		"--pids-limit", "100", // Limit process count
 		"--ulimit", "nofile=100:200", // Limit open files
 		"--tmpfs", "/tmp:rw,noexec,nosuid,size=64m", // Writable /tmp with limits
-		r.image,
 	}
+	// Pass secrets by name only so their values stay out of the docker
+	// command line; docker copies them from its own environment.
+	for name := range req.Secrets {
+		args = append(args, "-e", name)
+	}
+	args = append(args, r.image)
 	args = append(args, r.command...)
 
 	cmd := exec.CommandContext(ctx, "docker", args...)
+	if len(req.Secrets) > 0 {
+		cmd.Env = withSecrets(os.Environ(), req.Secrets)
+		defer wipeEnv(cmd.Env)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -538,6 +581,95 @@ func buildSafeEnv(requestedEnv map[string]string) []string {
 	return env
 }
 
+// withSecrets appends secrets to an environment built by buildSafeEnv. They
+// are added after the safe environment so they are never part of it, and
+// names are checked by isValidSecretName rather than isValidEnvKey.
+func withSecrets(env []string, secrets map[string]string) []string {
+	for name, value := range secrets {
+		if isValidSecretName(name) {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// wipeEnv drops the entries of an environment that may hold secrets once
+// the process has exited, so they are not kept reachable by the command.
+func wipeEnv(env []string) {
+	clear(env)
+}
+
+// isValidSecretName reports whether name may be used for a secret: an
+// upper-case identifier such as API_KEY that does not change how the
+// process or its runtime is loaded.
+func isValidSecretName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "LD_") || strings.HasPrefix(name, "DYLD_") || safeEnvVars[name] {
+		return false
+	}
+	switch name {
+	case "BASH_ENV", "ENV", "IFS", "SHELL", "PROMPT_COMMAND", "NODE_OPTIONS", "PYTHONPATH", "PYTHONSTARTUP":
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c >= 'A' && c <= 'Z', c == '_':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// redactSecrets replaces every secret value in the result's output and
+// streams with redactedSecret.
+func redactSecrets(result *ExecutionResult, secrets map[string]string) {
+	values := make([]string, 0, len(secrets))
+	for _, value := range secrets {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	// Replace longer values first so a secret containing another is not
+	// left partly visible.
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	redact := func(s string) string {
+		for _, value := range values {
+			s = strings.ReplaceAll(s, value, redactedSecret)
+		}
+		return s
+	}
+	result.Stdout = redact(result.Stdout)
+	result.Stderr = redact(result.Stderr)
+	for key, value := range result.Output {
+		result.Output[key] = redactValue(value, redact)
+	}
+}
+
+// redactValue applies redact to every string within a decoded JSON value.
+func redactValue(v interface{}, redact func(string) string) interface{} {
+	switch val := v.(type) {
+	case string:
+		return redact(val)
+	case map[string]interface{}:
+		for key, item := range val {
+			val[key] = redactValue(item, redact)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactValue(item, redact)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
 // Returns false for keys that could be used for injection attacks.
 func isValidEnvKey(key string) bool {
 	if key == "" {
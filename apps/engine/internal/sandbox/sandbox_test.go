@@ -0,0 +1,50 @@
+package sandbox
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExecuteInjectsAndRedactsSecrets(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+	sb, err := NewSandbox(Config{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err != nil {
+		t.Fatalf("new sandbox: %v", err)
+	}
+
+	secrets := map[string]string{"API_KEY": "sk-live-123"}
+	result, err := sb.Execute(context.Background(), &ExecutionRequest{
+		Code:     `grep -q sk-live input.json && echo leaked; echo "{\"auth\": \"Bearer $API_KEY\"}"; echo "$API_KEY" >&2`,
+		Language: "bash",
+		Input:    map[string]interface{}{"id": 1},
+		Mode:     ExecutionModeContainer,
+		Secrets:  secrets,
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if result.Output["auth"] != "Bearer [REDACTED]" || strings.TrimSpace(result.Stderr) != "[REDACTED]" {
+		t.Fatalf("result = %+v, want the secret injected and redacted", result)
+	}
+	if len(secrets) != 0 {
+		t.Fatalf("secrets were not cleared after the run: %v", secrets)
+	}
+
+	for _, name := range []string{"LD_PRELOAD", "PATH", "api_key", "1KEY"} {
+		_, err := sb.Execute(context.Background(), &ExecutionRequest{
+			Code:     "true",
+			Language: "bash",
+			Mode:     ExecutionModeContainer,
+			Secrets:  map[string]string{name: "x"},
+		})
+		if err == nil {
+			t.Fatalf("secret name %q was accepted", name)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package pii finds and masks personally identifiable information in text.
+// The built-in patterns are shared by anything that must scrub data before
+// it is logged or sent on, such as the redact node.
+package pii
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pattern is a kind of PII recognized in text. Matches of Regexp are PII
+// only if Valid, when set, accepts them; this keeps, say, order numbers
+// from being taken for card numbers.
+type Pattern struct {
+	Name   string
+	Regexp *regexp.Regexp
+	Valid  func(match string) bool
+}
+
+var (
+	// Email matches email addresses.
+	Email = Pattern{
+		Name:   "email",
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+
+	// CreditCard matches 13 to 19 digit card numbers, optionally grouped
+	// with spaces or dashes, that pass the Luhn check.
+	CreditCard = Pattern{
+		Name:   "credit_card",
+		Regexp: regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+		Valid:  luhnValid,
+	}
+
+	// SSN matches US social security numbers written as 123-45-6789 or
+	// 123 45 6789, excluding numbers that are never issued.
+	SSN = Pattern{
+		Name:   "ssn",
+		Regexp: regexp.MustCompile(`\b\d{3}[ \-]\d{2}[ \-]\d{4}\b`),
+		Valid:  ssnValid,
+	}
+)
+
+var builtin = map[string]Pattern{
+	Email.Name:      Email,
+	CreditCard.Name: CreditCard,
+	SSN.Name:        SSN,
+}
+
+// Builtin returns the built-in pattern named name.
+func Builtin(name string) (Pattern, bool) {
+	p, ok := builtin[name]
+	return p, ok
+}
+
+// BuiltinNames returns the names of the built-in patterns, sorted.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtin))
+	for name := range builtin {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Builtins returns every built-in pattern, ordered by name.
+func Builtins() []Pattern {
+	patterns := make([]Pattern, 0, len(builtin))
+	for _, name := range BuiltinNames() {
+		patterns = append(patterns, builtin[name])
+	}
+	return patterns
+}
+
+// Replace replaces every valid match of p in s with mask(match) and returns
+// the result with the number of matches replaced.
+func (p Pattern) Replace(s string, mask func(string) string) (string, int) {
+	count := 0
+	out := p.Regexp.ReplaceAllStringFunc(s, func(match string) string {
+		if p.Valid != nil && !p.Valid(match) {
+			return match
+		}
+		count++
+		return mask(match)
+	})
+	return out, count
+}
+
+// Strategy is how a value is masked.
+type Strategy string
+
+const (
+	// StrategyFull replaces the whole value with Masked.
+	StrategyFull Strategy = "full"
+	// StrategyLast4 keeps the last four characters, as in ****1111.
+	StrategyLast4 Strategy = "last4"
+	// StrategyHash replaces the value with a short SHA-256 digest, so equal
+	// values can still be matched up without revealing them.
+	StrategyHash Strategy = "hash"
+)
+
+// Masked is what StrategyFull leaves of a value, and the prefix of
+// StrategyLast4.
+const Masked = "****"
+
+// ParseStrategy returns the strategy named s and whether it is known.
+func ParseStrategy(s string) (Strategy, bool) {
+	switch strategy := Strategy(s); strategy {
+	case StrategyFull, StrategyLast4, StrategyHash:
+		return strategy, true
+	}
+	return "", false
+}
+
+// Mask masks value with strategy. Unknown strategies mask fully.
+func Mask(value string, strategy Strategy) string {
+	switch strategy {
+	case StrategyLast4:
+		// Values too short to hide anything behind four characters are
+		// masked fully.
+		if n := utf8.RuneCountInString(value); n > 4 {
+			runes := []rune(value)
+			return Masked + string(runes[n-4:])
+		}
+	case StrategyHash:
+		sum := sha256.Sum256([]byte(value))
+		return fmt.Sprintf("sha256:%s", hex.EncodeToString(sum[:])[:16])
+	}
+	return Masked
+}
+
+// digits returns the digits of s.
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func luhnValid(match string) bool {
+	number := digits(match)
+	if len(number) < 13 || len(number) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func ssnValid(match string) bool {
+	number := digits(match)
+	area, group, serial := number[:3], number[3:5], number[5:]
+	if area == "000" || area == "666" || area[0] == '9' {
+		return false
+	}
+	return group != "00" && serial != "0000"
+}
@@ -0,0 +1,57 @@
+package pii
+
+import "testing"
+
+func TestBuiltinPatterns(t *testing.T) {
+	for _, tc := range []struct {
+		pattern Pattern
+		text    string
+		want    string
+	}{
+		{Email, "contact jane.doe+news@mail.example.co.uk today", "contact **** today"},
+		{Email, "no address here@", "no address here@"},
+		{CreditCard, "card 4111 1111 1111 1111 on file", "card **** on file"},
+		{CreditCard, "card 5500-0000-0000-0004", "card ****"},
+		{CreditCard, "amex 378282246310005", "amex ****"},
+		{CreditCard, "order 1234567890123", "order 1234567890123"}, // fails the Luhn check
+		{SSN, "ssn 123-45-6789", "ssn ****"},
+		{SSN, "ssn 123 45 6789", "ssn ****"},
+		{SSN, "never issued 666-45-6789, 900-12-3456, 123-00-6789", "never issued 666-45-6789, 900-12-3456, 123-00-6789"},
+		{SSN, "phone 555-123-4567", "phone 555-123-4567"},
+	} {
+		got, _ := tc.pattern.Replace(tc.text, func(string) string { return Masked })
+		if got != tc.want {
+			t.Errorf("%s.Replace(%q) = %q, want %q", tc.pattern.Name, tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestReplaceCountsMatches(t *testing.T) {
+	got, n := Email.Replace("a@example.com, b@example.org", func(m string) string { return Mask(m, StrategyLast4) })
+	if got != "****.com, ****.org" || n != 2 {
+		t.Fatalf("Replace = %q, %d; want two masked addresses", got, n)
+	}
+}
+
+func TestMask(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		strategy Strategy
+		want     string
+	}{
+		{"4111 1111 1111 1111", StrategyFull, "****"},
+		{"4111 1111 1111 1111", StrategyLast4, "****1111"},
+		{"123-45-6789", StrategyLast4, "****6789"},
+		{"1234", StrategyLast4, "****"},
+		{"hunter2", Strategy("bogus"), "****"},
+	} {
+		if got := Mask(tc.value, tc.strategy); got != tc.want {
+			t.Errorf("Mask(%q, %s) = %q, want %q", tc.value, tc.strategy, got, tc.want)
+		}
+	}
+
+	hashed := Mask("jane@example.com", StrategyHash)
+	if hashed != Mask("jane@example.com", StrategyHash) || hashed == Mask("john@example.com", StrategyHash) {
+		t.Errorf("hash masking is not a stable per-value digest: %q", hashed)
+	}
+}
@@ -0,0 +1,105 @@
+package timer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPWorkflowStarter starts scheduled workflows through the frontend's
+// HTTP API.
+type HTTPWorkflowStarter struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewHTTPWorkflowStarter creates a starter for the frontend at baseURL.
+func NewHTTPWorkflowStarter(baseURL string) *HTTPWorkflowStarter {
+	return &HTTPWorkflowStarter{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+type frontendStartRequest struct {
+	WorkspaceID    string          `json:"workspace_id"`
+	WorkflowID     string          `json:"workflow_id"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Input          json.RawMessage `json:"input,omitempty"`
+	TaskQueue      string          `json:"task_queue,omitempty"`
+}
+
+type frontendExecution struct {
+	RunID  string `json:"run_id"`
+	Status string `json:"status"`
+}
+
+func (s *HTTPWorkflowStarter) StartWorkflow(ctx context.Context, req *ScheduledStart) (string, error) {
+	body, err := json.Marshal(frontendStartRequest{
+		WorkspaceID:    req.NamespaceID,
+		WorkflowID:     req.WorkflowID,
+		IdempotencyKey: req.RequestID,
+		Input:          req.Input,
+		TaskQueue:      req.TaskQueue,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal start request: %w", err)
+	}
+
+	var started frontendExecution
+	if _, err := s.do(ctx, http.MethodPost, "/api/v1/workflows/execute", body, &started); err != nil {
+		return "", err
+	}
+	return started.RunID, nil
+}
+
+// IsExecutionRunning reports whether runID is the workflow's latest run and
+// is still open. A run that has been superseded by a newer one, or no longer
+// exists, is not running.
+func (s *HTTPWorkflowStarter) IsExecutionRunning(ctx context.Context, namespaceID, workflowID, runID string) (bool, error) {
+	var execution frontendExecution
+	path := "/api/v1/workspaces/" + url.PathEscape(namespaceID) + "/executions/" + url.PathEscape(workflowID)
+	status, err := s.do(ctx, http.MethodGet, path, nil, &execution)
+	if status == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return execution.RunID == runID && (execution.Status == "running" || execution.Status == "pending"), nil
+}
+
+func (s *HTTPWorkflowStarter) do(ctx context.Context, method, path string, body []byte, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create frontend request: %w", err)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("frontend request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("frontend returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to decode frontend response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
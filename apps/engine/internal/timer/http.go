@@ -0,0 +1,126 @@
+package timer
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CreateTimerRequest is the body of POST /timers.
+type CreateTimerRequest struct {
+	Namespace  string          `json:"namespace"`
+	WorkflowID string          `json:"workflow_id"`
+	RunID      string          `json:"run_id"`
+	TimerID    string          `json:"timer_id"`
+	FireAt     time.Time       `json:"fire_at"`
+	TaskToken  string          `json:"task_token"`
+	Result     json.RawMessage `json:"result,omitempty"`
+}
+
+// RegisterHTTPRoutes registers the timer API on mux.
+func (s *Service) RegisterHTTPRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /timers", s.handleCreateTimer)
+	mux.HandleFunc("GET /timers", s.handleListTimers)
+}
+
+// RequireToken wraps the timer and schedule API so that every request must
+// carry token as a bearer token. With an empty token the API is disabled and
+// all requests are rejected.
+func RequireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			http.Error(w, "timer API is not enabled", http.StatusForbidden)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TimerResponse describes a pending timer in GET /timers.
+type TimerResponse struct {
+	TimerID   string    `json:"timer_id"`
+	FireAt    time.Time `json:"fire_at"`
+	TaskToken string    `json:"task_token,omitempty"`
+}
+
+// ListTimersResponse is the body returned by GET /timers.
+type ListTimersResponse struct {
+	Timers []TimerResponse `json:"timers"`
+}
+
+func (s *Service) handleCreateTimer(w http.ResponseWriter, r *http.Request) {
+	var req CreateTimerRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Namespace == "" || req.WorkflowID == "" || req.RunID == "" || req.TimerID == "" || req.FireAt.IsZero() {
+		http.Error(w, "namespace, workflow_id, run_id, timer_id and fire_at are required", http.StatusBadRequest)
+		return
+	}
+
+	err := s.CreateTimer(r.Context(), &Timer{
+		NamespaceID: req.Namespace,
+		WorkflowID:  req.WorkflowID,
+		RunID:       req.RunID,
+		TimerID:     req.TimerID,
+		FireTime:    req.FireAt,
+		Version:     1,
+		TaskToken:   req.TaskToken,
+		Result:      req.Result,
+	})
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusCreated)
+	case errors.Is(err, ErrTimerAlreadyExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrServiceNotRunning):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		s.logger.Error("failed to create timer",
+			slog.String("timer_id", req.TimerID),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to create timer", http.StatusInternalServerError)
+	}
+}
+
+func (s *Service) handleListTimers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	namespaceID, workflowID, runID := query.Get("namespace"), query.Get("workflow_id"), query.Get("run_id")
+	if namespaceID == "" || workflowID == "" || runID == "" {
+		http.Error(w, "namespace, workflow_id and run_id are required", http.StatusBadRequest)
+		return
+	}
+
+	timers, err := s.ListPendingTimers(r.Context(), namespaceID, workflowID, runID)
+	if err != nil {
+		s.logger.Error("failed to list timers",
+			slog.String("workflow_id", workflowID),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to list timers", http.StatusInternalServerError)
+		return
+	}
+
+	resp := ListTimersResponse{Timers: make([]TimerResponse, 0, len(timers))}
+	for _, t := range timers {
+		resp.Timers = append(resp.Timers, TimerResponse{
+			TimerID:   t.TimerID,
+			FireAt:    t.FireTime,
+			TaskToken: t.TaskToken,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,43 @@
+package timer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "valid token", token: "secret", header: "Bearer secret", want: http.StatusBadRequest},
+		{name: "missing header", token: "secret", want: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", header: "Bearer other", want: http.StatusUnauthorized},
+		{name: "wrong scheme", token: "secret", header: "Basic secret", want: http.StatusUnauthorized},
+		{name: "api disabled", header: "Bearer ", want: http.StatusForbidden},
+	}
+
+	svc := NewService(nil, nil, DefaultConfig())
+	api := http.NewServeMux()
+	svc.RegisterHTTPRoutes(api)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// An empty body reaches the create handler as a bad request
+			// only once the token has been accepted.
+			req := httptest.NewRequest(http.MethodPost, "/timers", strings.NewReader(""))
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			RequireToken(tt.token, api).ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("POST /timers status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
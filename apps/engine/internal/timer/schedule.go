@@ -0,0 +1,469 @@
+package timer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrScheduleNotFound      = errors.New("schedule not found")
+	ErrScheduleAlreadyExists = errors.New("schedule already exists")
+	ErrInvalidSchedule       = errors.New("invalid schedule")
+)
+
+// OverlapPolicy decides what a schedule does when it fires while the
+// execution it last started is still running.
+type OverlapPolicy string
+
+const (
+	// OverlapSkip drops the fire. It is the default.
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapAllow starts another execution alongside the running one.
+	OverlapAllow OverlapPolicy = "allow"
+	// OverlapBuffer queues the fire and starts it once the running
+	// execution closes.
+	OverlapBuffer OverlapPolicy = "buffer"
+)
+
+// Schedule starts a workflow execution each time its cron expression fires.
+// Unlike workflow timers it lives outside any execution.
+type Schedule struct {
+	NamespaceID    string
+	ScheduleID     string
+	WorkflowID     string
+	CronExpression string
+	// Timezone is the IANA zone the cron expression is evaluated in,
+	// UTC when empty.
+	Timezone      string
+	TaskQueue     string
+	Input         []byte
+	OverlapPolicy OverlapPolicy
+	Paused        bool
+
+	LastFireTime time.Time
+	NextFireTime time.Time
+	// LastRunID is the run the schedule started most recently; overlap
+	// policies check whether it is still running.
+	LastRunID string
+	// BufferedStarts counts fires held back by OverlapBuffer.
+	BufferedStarts int
+
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ScheduleStore defines the interface for schedule persistence.
+type ScheduleStore interface {
+	// CreateSchedule creates a new schedule
+	CreateSchedule(ctx context.Context, schedule *Schedule) error
+	// GetSchedule retrieves a schedule by ID
+	GetSchedule(ctx context.Context, namespaceID, scheduleID string) (*Schedule, error)
+	// UpdateSchedule updates a schedule whose stored version is Version-1
+	UpdateSchedule(ctx context.Context, schedule *Schedule) error
+	// DeleteSchedule deletes a schedule
+	DeleteSchedule(ctx context.Context, namespaceID, scheduleID string) error
+	// ListSchedules returns all schedules of a namespace
+	ListSchedules(ctx context.Context, namespaceID string) ([]*Schedule, error)
+	// GetDueSchedules returns unpaused schedules that are due to fire at now
+	// or have buffered starts
+	GetDueSchedules(ctx context.Context, now time.Time, limit int) ([]*Schedule, error)
+}
+
+// ScheduledStart is a workflow start requested by a schedule.
+type ScheduledStart struct {
+	NamespaceID string
+	WorkflowID  string
+	TaskQueue   string
+	Input       []byte
+	// RequestID is the same for every attempt at one start so a retry
+	// does not start a second execution.
+	RequestID string
+}
+
+// WorkflowStarter starts workflow executions for the scheduler, normally
+// through the frontend.
+type WorkflowStarter interface {
+	// StartWorkflow starts an execution and returns its run ID
+	StartWorkflow(ctx context.Context, req *ScheduledStart) (string, error)
+	// IsExecutionRunning reports whether the run is still open
+	IsExecutionRunning(ctx context.Context, namespaceID, workflowID, runID string) (bool, error)
+}
+
+// SchedulerConfig holds the configuration for the scheduler.
+type SchedulerConfig struct {
+	ScanInterval time.Duration
+	BatchSize    int
+	// MaxBufferedStarts caps the fires OverlapBuffer holds per schedule;
+	// further fires are dropped.
+	MaxBufferedStarts int
+	Logger            *slog.Logger
+}
+
+// DefaultSchedulerConfig returns the default scheduler configuration.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		ScanInterval:      time.Second,
+		BatchSize:         100,
+		MaxBufferedStarts: 10,
+	}
+}
+
+// Scheduler starts workflow executions on cron schedules.
+type Scheduler struct {
+	store   ScheduleStore
+	starter WorkflowStarter
+	config  SchedulerConfig
+	logger  *slog.Logger
+	now     func() time.Time
+
+	stopCh  chan struct{}
+	running bool
+	mu      sync.RWMutex
+	wg      sync.WaitGroup
+}
+
+// NewScheduler creates a new scheduler.
+func NewScheduler(store ScheduleStore, starter WorkflowStarter, config SchedulerConfig) *Scheduler {
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.ScanInterval <= 0 {
+		config.ScanInterval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.MaxBufferedStarts <= 0 {
+		config.MaxBufferedStarts = 10
+	}
+
+	return &Scheduler{
+		store:   store,
+		starter: starter,
+		config:  config,
+		logger:  config.Logger,
+		now:     time.Now,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start starts firing due schedules.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return errors.New("scheduler is already running")
+	}
+	s.running = true
+	s.stopCh = make(chan struct{})
+	s.mu.Unlock()
+
+	s.logger.Info("starting scheduler", slog.Duration("scan_interval", s.config.ScanInterval))
+
+	s.wg.Add(1)
+	go s.run(ctx)
+	return nil
+}
+
+// Stop stops the scheduler.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.running = false
+	close(s.stopCh)
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("scheduler stopped")
+	case <-ctx.Done():
+		s.logger.Warn("scheduler stop timed out")
+	}
+	return nil
+}
+
+// CreateSchedule validates and stores a new schedule. Its first fire is the
+// next cron time after now.
+func (s *Scheduler) CreateSchedule(ctx context.Context, schedule *Schedule) error {
+	if schedule.ScheduleID == "" {
+		schedule.ScheduleID = schedule.WorkflowID
+	}
+	cron, loc, err := schedule.validate()
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	schedule.NextFireTime = cron.Next(now.In(loc))
+	schedule.LastFireTime = time.Time{}
+	schedule.LastRunID = ""
+	schedule.BufferedStarts = 0
+	schedule.Version = 1
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	s.logger.Info("creating schedule",
+		slog.String("namespace", schedule.NamespaceID),
+		slog.String("schedule_id", schedule.ScheduleID),
+		slog.String("cron", schedule.CronExpression),
+		slog.Time("next_fire_time", schedule.NextFireTime),
+	)
+	return s.store.CreateSchedule(ctx, schedule)
+}
+
+// UpdateSchedule replaces the definition of an existing schedule (workflow,
+// cron expression, timezone, task queue, input and overlap policy) and
+// recomputes its next fire time. Fire history and pause state are kept.
+func (s *Scheduler) UpdateSchedule(ctx context.Context, update *Schedule) (*Schedule, error) {
+	return s.modify(ctx, update.NamespaceID, update.ScheduleID, func(schedule *Schedule) error {
+		schedule.WorkflowID = update.WorkflowID
+		schedule.CronExpression = update.CronExpression
+		schedule.Timezone = update.Timezone
+		schedule.TaskQueue = update.TaskQueue
+		schedule.Input = update.Input
+		schedule.OverlapPolicy = update.OverlapPolicy
+		if schedule.OverlapPolicy != OverlapBuffer {
+			schedule.BufferedStarts = 0
+		}
+		return nil
+	})
+}
+
+// PauseSchedule stops a schedule from firing until it is resumed.
+func (s *Scheduler) PauseSchedule(ctx context.Context, namespaceID, scheduleID string) (*Schedule, error) {
+	return s.modify(ctx, namespaceID, scheduleID, func(schedule *Schedule) error {
+		schedule.Paused = true
+		return nil
+	})
+}
+
+// ResumeSchedule resumes a paused schedule. Fires missed while paused are
+// not made up; the next fire is the next cron time after now.
+func (s *Scheduler) ResumeSchedule(ctx context.Context, namespaceID, scheduleID string) (*Schedule, error) {
+	return s.modify(ctx, namespaceID, scheduleID, func(schedule *Schedule) error {
+		schedule.Paused = false
+		return nil
+	})
+}
+
+// modify applies change to the stored schedule and saves it with a
+// recomputed next fire time.
+func (s *Scheduler) modify(ctx context.Context, namespaceID, scheduleID string, change func(*Schedule) error) (*Schedule, error) {
+	schedule, err := s.store.GetSchedule(ctx, namespaceID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(schedule); err != nil {
+		return nil, err
+	}
+	cron, loc, err := schedule.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	schedule.NextFireTime = cron.Next(now.In(loc))
+	schedule.Version++
+	schedule.UpdatedAt = now
+	if err := s.store.UpdateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetSchedule retrieves a schedule.
+func (s *Scheduler) GetSchedule(ctx context.Context, namespaceID, scheduleID string) (*Schedule, error) {
+	return s.store.GetSchedule(ctx, namespaceID, scheduleID)
+}
+
+// ListSchedules returns the schedules of a namespace.
+func (s *Scheduler) ListSchedules(ctx context.Context, namespaceID string) ([]*Schedule, error) {
+	return s.store.ListSchedules(ctx, namespaceID)
+}
+
+// DeleteSchedule deletes a schedule. Executions it already started are not
+// affected.
+func (s *Scheduler) DeleteSchedule(ctx context.Context, namespaceID, scheduleID string) error {
+	return s.store.DeleteSchedule(ctx, namespaceID, scheduleID)
+}
+
+// IsRunning returns whether the scheduler is running.
+func (s *Scheduler) IsRunning() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.running
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.ScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.fireDueSchedules(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) fireDueSchedules(ctx context.Context) {
+	now := s.now()
+	schedules, err := s.store.GetDueSchedules(ctx, now, s.config.BatchSize)
+	if err != nil {
+		s.logger.Error("failed to get due schedules", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.fire(ctx, schedule, now); err != nil {
+			s.logger.Error("failed to fire schedule",
+				slog.String("namespace", schedule.NamespaceID),
+				slog.String("schedule_id", schedule.ScheduleID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// fire handles one due schedule: it applies the overlap policy, starts the
+// workflow if the policy allows and records the fire. A schedule that was
+// down for several fires fires once; missed fires are not backfilled. On
+// error nothing is recorded and the next scan retries with the same request
+// ID.
+func (s *Scheduler) fire(ctx context.Context, schedule *Schedule, now time.Time) error {
+	cron, loc, err := schedule.validate()
+	if err != nil {
+		return err
+	}
+
+	running := false
+	if schedule.LastRunID != "" && schedule.OverlapPolicy != OverlapAllow {
+		running, err = s.starter.IsExecutionRunning(ctx, schedule.NamespaceID, schedule.WorkflowID, schedule.LastRunID)
+		if err != nil {
+			return fmt.Errorf("failed to check last run: %w", err)
+		}
+	}
+
+	start := false
+	if due := !schedule.NextFireTime.IsZero() && !schedule.NextFireTime.After(now); due {
+		switch {
+		case !running:
+			start = true
+		case schedule.OverlapPolicy == OverlapBuffer && schedule.BufferedStarts < s.config.MaxBufferedStarts:
+			schedule.BufferedStarts++
+		default:
+			s.logger.Info("schedule fire skipped, previous run still open",
+				slog.String("schedule_id", schedule.ScheduleID),
+				slog.String("run_id", schedule.LastRunID),
+			)
+		}
+		schedule.LastFireTime = schedule.NextFireTime
+		schedule.NextFireTime = cron.Next(now.In(loc))
+	} else if schedule.BufferedStarts > 0 && !running {
+		schedule.BufferedStarts--
+		start = true
+	}
+
+	if start {
+		runID, err := s.starter.StartWorkflow(ctx, &ScheduledStart{
+			NamespaceID: schedule.NamespaceID,
+			WorkflowID:  schedule.WorkflowID,
+			TaskQueue:   schedule.TaskQueue,
+			Input:       schedule.Input,
+			RequestID:   scheduledRequestID(schedule),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start workflow: %w", err)
+		}
+		schedule.LastRunID = runID
+
+		s.logger.Info("schedule started workflow",
+			slog.String("schedule_id", schedule.ScheduleID),
+			slog.String("workflow_id", schedule.WorkflowID),
+			slog.String("run_id", runID),
+		)
+	}
+
+	schedule.Version++
+	schedule.UpdatedAt = now
+	if err := s.store.UpdateSchedule(ctx, schedule); err != nil {
+		if errors.Is(err, ErrOptimisticLockConflict) {
+			s.logger.Debug("schedule changed while firing",
+				slog.String("schedule_id", schedule.ScheduleID))
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// scheduledRequestID derives the start's request ID from the schedule
+// version the fire is claimed at, so it is stable across retries of the
+// same fire and distinct between fires and recreated schedules.
+func scheduledRequestID(schedule *Schedule) string {
+	name := schedule.NamespaceID + "/" + schedule.ScheduleID + "/" +
+		strconv.FormatInt(schedule.CreatedAt.UnixNano(), 10) + "/" +
+		strconv.FormatInt(schedule.Version, 10)
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+}
+
+// validate checks the schedule definition, defaulting the overlap policy,
+// and returns its parsed cron expression and location.
+func (s *Schedule) validate() (*CronSchedule, *time.Location, error) {
+	if s.NamespaceID == "" || s.ScheduleID == "" || s.WorkflowID == "" {
+		return nil, nil, fmt.Errorf("%w: namespace, schedule ID and workflow ID are required", ErrInvalidSchedule)
+	}
+	switch s.OverlapPolicy {
+	case "":
+		s.OverlapPolicy = OverlapSkip
+	case OverlapSkip, OverlapAllow, OverlapBuffer:
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown overlap policy %q", ErrInvalidSchedule, s.OverlapPolicy)
+	}
+	if len(s.Input) > 0 {
+		var input map[string]json.RawMessage
+		if err := json.Unmarshal(s.Input, &input); err != nil {
+			return nil, nil, fmt.Errorf("%w: input must be a JSON object", ErrInvalidSchedule)
+		}
+	}
+
+	cron, err := ParseCron(s.CronExpression)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, s.Timezone)
+	}
+	if cron.Next(time.Now().In(loc)).IsZero() {
+		return nil, nil, fmt.Errorf("%w: cron expression %q never fires", ErrInvalidSchedule, s.CronExpression)
+	}
+	return cron, loc, nil
+}
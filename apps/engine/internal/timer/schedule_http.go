@@ -0,0 +1,216 @@
+package timer
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ScheduleRequest is the body of POST /schedules and
+// PUT /schedules/{namespace}/{schedule_id}.
+type ScheduleRequest struct {
+	Namespace      string          `json:"namespace"`
+	ScheduleID     string          `json:"schedule_id,omitempty"`
+	WorkflowID     string          `json:"workflow_id"`
+	CronExpression string          `json:"cron_expression"`
+	Timezone       string          `json:"timezone,omitempty"`
+	TaskQueue      string          `json:"task_queue,omitempty"`
+	Input          json.RawMessage `json:"input,omitempty"`
+	OverlapPolicy  OverlapPolicy   `json:"overlap_policy,omitempty"`
+}
+
+// ScheduleResponse describes a schedule.
+type ScheduleResponse struct {
+	Namespace      string          `json:"namespace"`
+	ScheduleID     string          `json:"schedule_id"`
+	WorkflowID     string          `json:"workflow_id"`
+	CronExpression string          `json:"cron_expression"`
+	Timezone       string          `json:"timezone,omitempty"`
+	TaskQueue      string          `json:"task_queue,omitempty"`
+	Input          json.RawMessage `json:"input,omitempty"`
+	OverlapPolicy  OverlapPolicy   `json:"overlap_policy"`
+	Paused         bool            `json:"paused"`
+	LastFireTime   *time.Time      `json:"last_fire_time,omitempty"`
+	NextFireTime   *time.Time      `json:"next_fire_time,omitempty"`
+	LastRunID      string          `json:"last_run_id,omitempty"`
+	BufferedStarts int             `json:"buffered_starts,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// ListSchedulesResponse is the body returned by GET /schedules.
+type ListSchedulesResponse struct {
+	Schedules []ScheduleResponse `json:"schedules"`
+}
+
+// RegisterHTTPRoutes registers the schedule API on mux.
+func (s *Scheduler) RegisterHTTPRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /schedules", s.handleCreateSchedule)
+	mux.HandleFunc("GET /schedules", s.handleListSchedules)
+	mux.HandleFunc("GET /schedules/{namespace}/{schedule_id}", s.handleGetSchedule)
+	mux.HandleFunc("PUT /schedules/{namespace}/{schedule_id}", s.handleUpdateSchedule)
+	mux.HandleFunc("DELETE /schedules/{namespace}/{schedule_id}", s.handleDeleteSchedule)
+	mux.HandleFunc("POST /schedules/{namespace}/{schedule_id}/pause", s.handlePauseSchedule)
+	mux.HandleFunc("POST /schedules/{namespace}/{schedule_id}/resume", s.handleResumeSchedule)
+}
+
+func (s *Scheduler) handleCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeScheduleRequest(w, r)
+	if !ok {
+		return
+	}
+
+	schedule := req.schedule()
+	if err := s.CreateSchedule(r.Context(), schedule); err != nil {
+		s.writeScheduleError(w, "create", schedule.ScheduleID, err)
+		return
+	}
+	writeSchedule(w, http.StatusCreated, schedule)
+}
+
+func (s *Scheduler) handleListSchedules(w http.ResponseWriter, r *http.Request) {
+	namespaceID, workflowID := r.URL.Query().Get("namespace"), r.URL.Query().Get("workflow_id")
+	if namespaceID == "" {
+		http.Error(w, "namespace is required", http.StatusBadRequest)
+		return
+	}
+
+	schedules, err := s.ListSchedules(r.Context(), namespaceID)
+	if err != nil {
+		s.writeScheduleError(w, "list", "", err)
+		return
+	}
+
+	resp := ListSchedulesResponse{Schedules: make([]ScheduleResponse, 0, len(schedules))}
+	for _, schedule := range schedules {
+		if workflowID == "" || schedule.WorkflowID == workflowID {
+			resp.Schedules = append(resp.Schedules, scheduleResponse(schedule))
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Scheduler) handleGetSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, err := s.GetSchedule(r.Context(), r.PathValue("namespace"), r.PathValue("schedule_id"))
+	if err != nil {
+		s.writeScheduleError(w, "get", r.PathValue("schedule_id"), err)
+		return
+	}
+	writeSchedule(w, http.StatusOK, schedule)
+}
+
+func (s *Scheduler) handleUpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeScheduleRequest(w, r)
+	if !ok {
+		return
+	}
+
+	update := req.schedule()
+	update.NamespaceID = r.PathValue("namespace")
+	update.ScheduleID = r.PathValue("schedule_id")
+	schedule, err := s.UpdateSchedule(r.Context(), update)
+	if err != nil {
+		s.writeScheduleError(w, "update", update.ScheduleID, err)
+		return
+	}
+	writeSchedule(w, http.StatusOK, schedule)
+}
+
+func (s *Scheduler) handleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	if err := s.DeleteSchedule(r.Context(), r.PathValue("namespace"), r.PathValue("schedule_id")); err != nil {
+		s.writeScheduleError(w, "delete", r.PathValue("schedule_id"), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Scheduler) handlePauseSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, err := s.PauseSchedule(r.Context(), r.PathValue("namespace"), r.PathValue("schedule_id"))
+	if err != nil {
+		s.writeScheduleError(w, "pause", r.PathValue("schedule_id"), err)
+		return
+	}
+	writeSchedule(w, http.StatusOK, schedule)
+}
+
+func (s *Scheduler) handleResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, err := s.ResumeSchedule(r.Context(), r.PathValue("namespace"), r.PathValue("schedule_id"))
+	if err != nil {
+		s.writeScheduleError(w, "resume", r.PathValue("schedule_id"), err)
+		return
+	}
+	writeSchedule(w, http.StatusOK, schedule)
+}
+
+func decodeScheduleRequest(w http.ResponseWriter, r *http.Request) (*ScheduleRequest, bool) {
+	var req ScheduleRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+func (req *ScheduleRequest) schedule() *Schedule {
+	return &Schedule{
+		NamespaceID:    req.Namespace,
+		ScheduleID:     req.ScheduleID,
+		WorkflowID:     req.WorkflowID,
+		CronExpression: req.CronExpression,
+		Timezone:       req.Timezone,
+		TaskQueue:      req.TaskQueue,
+		Input:          req.Input,
+		OverlapPolicy:  req.OverlapPolicy,
+	}
+}
+
+func (s *Scheduler) writeScheduleError(w http.ResponseWriter, op, scheduleID string, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidSchedule):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrScheduleNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrScheduleAlreadyExists), errors.Is(err, ErrOptimisticLockConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		s.logger.Error("failed to "+op+" schedule",
+			slog.String("schedule_id", scheduleID),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to "+op+" schedule", http.StatusInternalServerError)
+	}
+}
+
+func writeSchedule(w http.ResponseWriter, status int, schedule *Schedule) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(scheduleResponse(schedule))
+}
+
+func scheduleResponse(schedule *Schedule) ScheduleResponse {
+	resp := ScheduleResponse{
+		Namespace:      schedule.NamespaceID,
+		ScheduleID:     schedule.ScheduleID,
+		WorkflowID:     schedule.WorkflowID,
+		CronExpression: schedule.CronExpression,
+		Timezone:       schedule.Timezone,
+		TaskQueue:      schedule.TaskQueue,
+		Input:          schedule.Input,
+		OverlapPolicy:  schedule.OverlapPolicy,
+		Paused:         schedule.Paused,
+		LastRunID:      schedule.LastRunID,
+		BufferedStarts: schedule.BufferedStarts,
+		CreatedAt:      schedule.CreatedAt,
+		UpdatedAt:      schedule.UpdatedAt,
+	}
+	if !schedule.LastFireTime.IsZero() {
+		resp.LastFireTime = &schedule.LastFireTime
+	}
+	if !schedule.NextFireTime.IsZero() && !schedule.Paused {
+		resp.NextFireTime = &schedule.NextFireTime
+	}
+	return resp
+}
@@ -0,0 +1,189 @@
+package timer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	base := time.Date(2026, time.March, 6, 10, 7, 30, 0, time.UTC) // a Friday
+	for expr, want := range map[string]time.Time{
+		"*/15 * * * *":    time.Date(2026, time.March, 6, 10, 15, 0, 0, time.UTC),
+		"0 9 * * mon-fri": time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC),
+		"30 2 1 * *":      time.Date(2026, time.April, 1, 2, 30, 0, 0, time.UTC),
+		"0 0 13 * 5":      time.Date(2026, time.March, 13, 0, 0, 0, 0, time.UTC),
+		"@hourly":         time.Date(2026, time.March, 6, 11, 0, 0, 0, time.UTC),
+		"0 0 * * 7":       time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC),
+	} {
+		cron, err := ParseCron(expr)
+		if err != nil {
+			t.Fatalf("%s: %v", expr, err)
+		}
+		if got := cron.Next(base); !got.Equal(want) {
+			t.Errorf("%s: next = %s, want %s", expr, got, want)
+		}
+	}
+
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "0 0 * foo *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("%s: expected a parse error", expr)
+		}
+	}
+}
+
+type scheduleMemoryStore struct {
+	schedules map[string]*Schedule
+}
+
+func (s *scheduleMemoryStore) CreateSchedule(_ context.Context, schedule *Schedule) error {
+	clone := *schedule
+	s.schedules[schedule.ScheduleID] = &clone
+	return nil
+}
+
+func (s *scheduleMemoryStore) GetSchedule(_ context.Context, _, scheduleID string) (*Schedule, error) {
+	schedule, ok := s.schedules[scheduleID]
+	if !ok {
+		return nil, ErrScheduleNotFound
+	}
+	clone := *schedule
+	return &clone, nil
+}
+
+func (s *scheduleMemoryStore) UpdateSchedule(_ context.Context, schedule *Schedule) error {
+	if s.schedules[schedule.ScheduleID].Version != schedule.Version-1 {
+		return ErrOptimisticLockConflict
+	}
+	clone := *schedule
+	s.schedules[schedule.ScheduleID] = &clone
+	return nil
+}
+
+func (s *scheduleMemoryStore) DeleteSchedule(_ context.Context, _, scheduleID string) error {
+	delete(s.schedules, scheduleID)
+	return nil
+}
+
+func (s *scheduleMemoryStore) ListSchedules(_ context.Context, _ string) ([]*Schedule, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *scheduleMemoryStore) GetDueSchedules(_ context.Context, now time.Time, _ int) ([]*Schedule, error) {
+	var due []*Schedule
+	for _, schedule := range s.schedules {
+		if !schedule.Paused && (schedule.BufferedStarts > 0 || !schedule.NextFireTime.After(now)) {
+			clone := *schedule
+			due = append(due, &clone)
+		}
+	}
+	return due, nil
+}
+
+type recordingStarter struct {
+	starts  []*ScheduledStart
+	running bool
+}
+
+func (s *recordingStarter) StartWorkflow(_ context.Context, req *ScheduledStart) (string, error) {
+	s.starts = append(s.starts, req)
+	return req.RequestID, nil
+}
+
+func (s *recordingStarter) IsExecutionRunning(context.Context, string, string, string) (bool, error) {
+	return s.running, nil
+}
+
+func newTestScheduler(t *testing.T, policy OverlapPolicy) (*Scheduler, *scheduleMemoryStore, *recordingStarter, *time.Time) {
+	t.Helper()
+	store := &scheduleMemoryStore{schedules: make(map[string]*Schedule)}
+	starter := &recordingStarter{}
+	scheduler := NewScheduler(store, starter, SchedulerConfig{
+		MaxBufferedStarts: 1,
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	now := time.Date(2026, time.March, 6, 10, 0, 30, 0, time.UTC)
+	scheduler.now = func() time.Time { return now }
+
+	err := scheduler.CreateSchedule(context.Background(), &Schedule{
+		NamespaceID:    "default",
+		WorkflowID:     "nightly-report",
+		CronExpression: "* * * * *",
+		OverlapPolicy:  policy,
+	})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	return scheduler, store, starter, &now
+}
+
+func TestSchedulerOverlapPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("skip", func(t *testing.T) {
+		scheduler, store, starter, now := newTestScheduler(t, OverlapSkip)
+
+		*now = now.Add(time.Minute)
+		scheduler.fireDueSchedules(ctx)
+		starter.running = true
+		*now = now.Add(time.Minute)
+		scheduler.fireDueSchedules(ctx)
+
+		if len(starter.starts) != 1 {
+			t.Fatalf("expected the overlapping fire to be skipped, got %d starts", len(starter.starts))
+		}
+		schedule := store.schedules["nightly-report"]
+		if !schedule.LastFireTime.Equal(time.Date(2026, time.March, 6, 10, 2, 0, 0, time.UTC)) || schedule.BufferedStarts != 0 {
+			t.Fatalf("unexpected schedule state %+v", schedule)
+		}
+	})
+
+	t.Run("buffer", func(t *testing.T) {
+		scheduler, store, starter, now := newTestScheduler(t, OverlapBuffer)
+
+		*now = now.Add(time.Minute)
+		scheduler.fireDueSchedules(ctx)
+		starter.running = true
+		for i := 0; i < 2; i++ {
+			*now = now.Add(time.Minute)
+			scheduler.fireDueSchedules(ctx)
+		}
+		if buffered := store.schedules["nightly-report"].BufferedStarts; buffered != 1 {
+			t.Fatalf("buffered starts = %d, want the configured maximum of 1", buffered)
+		}
+
+		starter.running = false
+		*now = now.Add(time.Second)
+		scheduler.fireDueSchedules(ctx)
+		if len(starter.starts) != 2 || store.schedules["nightly-report"].BufferedStarts != 0 {
+			t.Fatalf("expected the buffered fire to start once the run closed, got %d starts", len(starter.starts))
+		}
+		if starter.starts[0].RequestID == starter.starts[1].RequestID {
+			t.Fatal("buffered start reused the request ID of the first start")
+		}
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		scheduler, _, starter, now := newTestScheduler(t, OverlapAllow)
+
+		if _, err := scheduler.PauseSchedule(ctx, "default", "nightly-report"); err != nil {
+			t.Fatalf("pause: %v", err)
+		}
+		*now = now.Add(time.Hour)
+		scheduler.fireDueSchedules(ctx)
+		if len(starter.starts) != 0 {
+			t.Fatalf("paused schedule started %d workflows", len(starter.starts))
+		}
+
+		resumed, err := scheduler.ResumeSchedule(ctx, "default", "nightly-report")
+		if err != nil {
+			t.Fatalf("resume: %v", err)
+		}
+		if want := now.Truncate(time.Minute).Add(time.Minute); !resumed.NextFireTime.Equal(want) {
+			t.Fatalf("next fire after resume = %s, want %s", resumed.NextFireTime, want)
+		}
+	})
+}
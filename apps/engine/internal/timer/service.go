@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 )
@@ -37,6 +38,12 @@ type Timer struct {
 	Version     int64
 	CreatedAt   time.Time
 	FiredAt     time.Time
+
+	// TaskToken, when set, identifies an async activity waiting on this
+	// timer. Firing completes the activity with Result instead of recording
+	// a TimerFired event.
+	TaskToken string
+	Result    []byte
 }
 
 // TimerCallback is called when a timer fires.
@@ -62,6 +69,8 @@ type Store interface {
 type HistoryClient interface {
 	// RecordTimerFired records that a timer has fired
 	RecordTimerFired(ctx context.Context, namespaceID, workflowID, runID, timerID string) error
+	// CompleteActivity completes the async activity identified by taskToken
+	CompleteActivity(ctx context.Context, taskToken string, result []byte) error
 }
 
 // Config holds the configuration for the timer service.
@@ -259,6 +268,26 @@ func (s *Service) GetTimer(ctx context.Context, namespaceID, workflowID, runID,
 	return s.store.GetTimer(ctx, namespaceID, workflowID, runID, timerID)
 }
 
+// ListPendingTimers returns the timers of an execution that have not fired
+// or been canceled, soonest first.
+func (s *Service) ListPendingTimers(ctx context.Context, namespaceID, workflowID, runID string) ([]*Timer, error) {
+	timers, err := s.store.GetTimersByExecution(ctx, namespaceID, workflowID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]*Timer, 0, len(timers))
+	for _, timer := range timers {
+		if timer.Status == TimerStatusPending {
+			pending = append(pending, timer)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].FireTime.Before(pending[j].FireTime)
+	})
+	return pending, nil
+}
+
 // runScanner scans for due timers and sends them to the processor.
 func (s *Service) runScanner(ctx context.Context) {
 	defer s.wg.Done()
@@ -384,7 +413,7 @@ func (s *Service) processTimer(ctx context.Context, timer *Timer) {
 		return
 	}
 
-	if err := s.historyClient.RecordTimerFired(ctx, current.NamespaceID, current.WorkflowID, current.RunID, current.TimerID); err != nil {
+	if err := s.fire(ctx, current); err != nil {
 		s.logger.Error("failed to record timer fired",
 			slog.String("timer_id", timer.TimerID),
 			slog.String("error", err.Error()),
@@ -408,6 +437,15 @@ func (s *Service) processTimer(ctx context.Context, timer *Timer) {
 	)
 }
 
+// fire reports a claimed timer to history, completing the waiting activity
+// for activity timers.
+func (s *Service) fire(ctx context.Context, timer *Timer) error {
+	if timer.TaskToken != "" {
+		return s.historyClient.CompleteActivity(ctx, timer.TaskToken, timer.Result)
+	}
+	return s.historyClient.RecordTimerFired(ctx, timer.NamespaceID, timer.WorkflowID, timer.RunID, timer.TimerID)
+}
+
 // getShardID calculates the shard ID for a timer.
 func (s *Service) getShardID(namespaceID, workflowID string) int32 {
 	data := namespaceID + "/" + workflowID
@@ -11,14 +11,16 @@ import (
 
 // MemoryStore is an in-memory implementation of the timer store.
 type MemoryStore struct {
-	timers map[string]*timer.Timer
-	mu     sync.RWMutex
+	timers    map[string]*timer.Timer
+	schedules map[string]*timer.Schedule
+	mu        sync.RWMutex
 }
 
 // NewMemoryStore creates a new in-memory timer store.
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		timers: make(map[string]*timer.Timer),
+		timers:    make(map[string]*timer.Timer),
+		schedules: make(map[string]*timer.Schedule),
 	}
 }
 
@@ -152,3 +154,110 @@ func (s *MemoryStore) Count() int {
 	defer s.mu.RUnlock()
 	return len(s.timers)
 }
+
+// CreateSchedule creates a new schedule.
+func (s *MemoryStore) CreateSchedule(ctx context.Context, sc *timer.Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := sc.NamespaceID + "/" + sc.ScheduleID
+	if _, exists := s.schedules[key]; exists {
+		return timer.ErrScheduleAlreadyExists
+	}
+
+	clone := *sc
+	s.schedules[key] = &clone
+	return nil
+}
+
+// GetSchedule retrieves a schedule by ID.
+func (s *MemoryStore) GetSchedule(ctx context.Context, namespaceID, scheduleID string) (*timer.Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sc, exists := s.schedules[namespaceID+"/"+scheduleID]
+	if !exists {
+		return nil, timer.ErrScheduleNotFound
+	}
+
+	clone := *sc
+	return &clone, nil
+}
+
+// UpdateSchedule updates a schedule.
+func (s *MemoryStore) UpdateSchedule(ctx context.Context, sc *timer.Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := sc.NamespaceID + "/" + sc.ScheduleID
+	existing, exists := s.schedules[key]
+	if !exists {
+		return timer.ErrScheduleNotFound
+	}
+	if existing.Version != sc.Version-1 {
+		return timer.ErrOptimisticLockConflict
+	}
+
+	clone := *sc
+	s.schedules[key] = &clone
+	return nil
+}
+
+// DeleteSchedule deletes a schedule.
+func (s *MemoryStore) DeleteSchedule(ctx context.Context, namespaceID, scheduleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := namespaceID + "/" + scheduleID
+	if _, exists := s.schedules[key]; !exists {
+		return timer.ErrScheduleNotFound
+	}
+
+	delete(s.schedules, key)
+	return nil
+}
+
+// ListSchedules returns all schedules of a namespace.
+func (s *MemoryStore) ListSchedules(ctx context.Context, namespaceID string) ([]*timer.Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var schedules []*timer.Schedule
+	for _, sc := range s.schedules {
+		if sc.NamespaceID == namespaceID {
+			clone := *sc
+			schedules = append(schedules, &clone)
+		}
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].ScheduleID < schedules[j].ScheduleID
+	})
+	return schedules, nil
+}
+
+// GetDueSchedules returns unpaused schedules that are due or have buffered
+// starts.
+func (s *MemoryStore) GetDueSchedules(ctx context.Context, now time.Time, limit int) ([]*timer.Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var due []*timer.Schedule
+	for _, sc := range s.schedules {
+		if sc.Paused {
+			continue
+		}
+		if sc.BufferedStarts > 0 || (!sc.NextFireTime.IsZero() && !sc.NextFireTime.After(now)) {
+			clone := *sc
+			due = append(due, &clone)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextFireTime.Before(due[j].NextFireTime)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/linkflow/engine/internal/timer"
@@ -27,8 +28,8 @@ func (s *PostgresStore) CreateTimer(ctx context.Context, t *timer.Timer) error {
 	_, err := s.pool.Exec(ctx, `
 		INSERT INTO timers (
 			shard_id, namespace_id, workflow_id, run_id, timer_id,
-			fire_time, status, version, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			fire_time, status, version, created_at, task_token, result
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`,
 		t.ShardID,
 		t.NamespaceID,
@@ -39,6 +40,8 @@ func (s *PostgresStore) CreateTimer(ctx context.Context, t *timer.Timer) error {
 		int16(t.Status),
 		t.Version,
 		t.CreatedAt,
+		t.TaskToken,
+		t.Result,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create timer: %w", err)
@@ -54,7 +57,7 @@ func (s *PostgresStore) GetTimer(ctx context.Context, namespaceID, workflowID, r
 
 	err := s.pool.QueryRow(ctx, `
 		SELECT shard_id, namespace_id, workflow_id, run_id, timer_id,
-			   fire_time, status, version, created_at, fired_at
+			   fire_time, status, version, created_at, fired_at, task_token, result
 		FROM timers
 		WHERE namespace_id = $1 AND workflow_id = $2 AND run_id = $3 AND timer_id = $4
 	`, namespaceID, workflowID, runID, timerID).Scan(
@@ -68,6 +71,8 @@ func (s *PostgresStore) GetTimer(ctx context.Context, namespaceID, workflowID, r
 		&t.Version,
 		&t.CreatedAt,
 		&firedAt,
+		&t.TaskToken,
+		&t.Result,
 	)
 
 	if err != nil {
@@ -130,7 +135,7 @@ func (s *PostgresStore) DeleteTimer(ctx context.Context, namespaceID, workflowID
 func (s *PostgresStore) GetDueTimers(ctx context.Context, shardID int32, fireTime time.Time, limit int) ([]*timer.Timer, error) {
 	rows, err := s.pool.Query(ctx, `
 		SELECT shard_id, namespace_id, workflow_id, run_id, timer_id,
-			   fire_time, status, version, created_at, fired_at
+			   fire_time, status, version, created_at, fired_at, task_token, result
 		FROM timers
 		WHERE shard_id = $1 AND status = $2 AND fire_time <= $3
 		ORDER BY fire_time ASC
@@ -158,6 +163,8 @@ func (s *PostgresStore) GetDueTimers(ctx context.Context, shardID int32, fireTim
 			&t.Version,
 			&t.CreatedAt,
 			&firedAt,
+			&t.TaskToken,
+			&t.Result,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan timer: %w", err)
 		}
@@ -175,7 +182,7 @@ func (s *PostgresStore) GetDueTimers(ctx context.Context, shardID int32, fireTim
 func (s *PostgresStore) GetTimersByExecution(ctx context.Context, namespaceID, workflowID, runID string) ([]*timer.Timer, error) {
 	rows, err := s.pool.Query(ctx, `
 		SELECT shard_id, namespace_id, workflow_id, run_id, timer_id,
-			   fire_time, status, version, created_at, fired_at
+			   fire_time, status, version, created_at, fired_at, task_token, result
 		FROM timers
 		WHERE namespace_id = $1 AND workflow_id = $2 AND run_id = $3
 		ORDER BY fire_time ASC
@@ -201,6 +208,8 @@ func (s *PostgresStore) GetTimersByExecution(ctx context.Context, namespaceID, w
 			&t.Version,
 			&t.CreatedAt,
 			&firedAt,
+			&t.TaskToken,
+			&t.Result,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan timer: %w", err)
 		}
@@ -225,3 +234,198 @@ func (s *PostgresStore) CleanupFiredTimers(ctx context.Context, olderThan time.T
 	}
 	return tag.RowsAffected(), nil
 }
+
+const scheduleColumns = `namespace_id, schedule_id, workflow_id, cron_expression, timezone,
+	task_queue, input, overlap_policy, paused, last_fire_time, next_fire_time,
+	last_run_id, buffered_starts, version, created_at, updated_at`
+
+// CreateSchedule creates a new schedule.
+func (s *PostgresStore) CreateSchedule(ctx context.Context, sc *timer.Schedule) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO workflow_schedules (`+scheduleColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`,
+		sc.NamespaceID,
+		sc.ScheduleID,
+		sc.WorkflowID,
+		sc.CronExpression,
+		sc.Timezone,
+		sc.TaskQueue,
+		sc.Input,
+		string(sc.OverlapPolicy),
+		sc.Paused,
+		nullableTime(sc.LastFireTime),
+		nullableTime(sc.NextFireTime),
+		sc.LastRunID,
+		sc.BufferedStarts,
+		sc.Version,
+		sc.CreatedAt,
+		sc.UpdatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return timer.ErrScheduleAlreadyExists
+		}
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+	return nil
+}
+
+// GetSchedule retrieves a schedule by ID.
+func (s *PostgresStore) GetSchedule(ctx context.Context, namespaceID, scheduleID string) (*timer.Schedule, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM workflow_schedules
+		WHERE namespace_id = $1 AND schedule_id = $2
+	`, namespaceID, scheduleID)
+
+	sc, err := scanSchedule(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, timer.ErrScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	return sc, nil
+}
+
+// UpdateSchedule updates a schedule.
+func (s *PostgresStore) UpdateSchedule(ctx context.Context, sc *timer.Schedule) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE workflow_schedules
+		SET workflow_id = $1, cron_expression = $2, timezone = $3, task_queue = $4,
+			input = $5, overlap_policy = $6, paused = $7, last_fire_time = $8,
+			next_fire_time = $9, last_run_id = $10, buffered_starts = $11,
+			version = $12, updated_at = $13
+		WHERE namespace_id = $14 AND schedule_id = $15 AND version = $16
+	`,
+		sc.WorkflowID,
+		sc.CronExpression,
+		sc.Timezone,
+		sc.TaskQueue,
+		sc.Input,
+		string(sc.OverlapPolicy),
+		sc.Paused,
+		nullableTime(sc.LastFireTime),
+		nullableTime(sc.NextFireTime),
+		sc.LastRunID,
+		sc.BufferedStarts,
+		sc.Version,
+		sc.UpdatedAt,
+		sc.NamespaceID,
+		sc.ScheduleID,
+		sc.Version-1, // Expected version
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := s.GetSchedule(ctx, sc.NamespaceID, sc.ScheduleID); err != nil {
+			return err
+		}
+		return timer.ErrOptimisticLockConflict
+	}
+	return nil
+}
+
+// DeleteSchedule deletes a schedule.
+func (s *PostgresStore) DeleteSchedule(ctx context.Context, namespaceID, scheduleID string) error {
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM workflow_schedules
+		WHERE namespace_id = $1 AND schedule_id = $2
+	`, namespaceID, scheduleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return timer.ErrScheduleNotFound
+	}
+	return nil
+}
+
+// ListSchedules returns all schedules of a namespace.
+func (s *PostgresStore) ListSchedules(ctx context.Context, namespaceID string) ([]*timer.Schedule, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM workflow_schedules
+		WHERE namespace_id = $1
+		ORDER BY schedule_id ASC
+	`, namespaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	return collectSchedules(rows)
+}
+
+// GetDueSchedules returns unpaused schedules that are due or have buffered
+// starts.
+func (s *PostgresStore) GetDueSchedules(ctx context.Context, now time.Time, limit int) ([]*timer.Schedule, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM workflow_schedules
+		WHERE paused = FALSE AND (next_fire_time <= $1 OR buffered_starts > 0)
+		ORDER BY next_fire_time ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due schedules: %w", err)
+	}
+	return collectSchedules(rows)
+}
+
+func collectSchedules(rows pgx.Rows) ([]*timer.Schedule, error) {
+	defer rows.Close()
+
+	var schedules []*timer.Schedule
+	for rows.Next() {
+		sc, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, sc)
+	}
+	return schedules, rows.Err()
+}
+
+func scanSchedule(row pgx.Row) (*timer.Schedule, error) {
+	var sc timer.Schedule
+	var overlapPolicy string
+	var lastFireTime, nextFireTime *time.Time
+	if err := row.Scan(
+		&sc.NamespaceID,
+		&sc.ScheduleID,
+		&sc.WorkflowID,
+		&sc.CronExpression,
+		&sc.Timezone,
+		&sc.TaskQueue,
+		&sc.Input,
+		&overlapPolicy,
+		&sc.Paused,
+		&lastFireTime,
+		&nextFireTime,
+		&sc.LastRunID,
+		&sc.BufferedStarts,
+		&sc.Version,
+		&sc.CreatedAt,
+		&sc.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	sc.OverlapPolicy = timer.OverlapPolicy(overlapPolicy)
+	if lastFireTime != nil {
+		sc.LastFireTime = *lastFireTime
+	}
+	if nextFireTime != nil {
+		sc.NextFireTime = *nextFireTime
+	}
+	return &sc, nil
+}
+
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
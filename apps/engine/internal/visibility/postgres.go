@@ -192,45 +192,11 @@ func (s *PostgresStore) ListExecutions(ctx context.Context, req *ListRequest) (*
 
 	var executions []*ExecutionInfo
 	for rows.Next() {
-		var info ExecutionInfo
-		var status int16
-		var closeTime *time.Time
-		var searchAttrsJSON []byte
-		var parentWorkflowID, parentRunID *string
-
-		if err := rows.Scan(
-			&info.NamespaceID,
-			&info.WorkflowID,
-			&info.RunID,
-			&info.WorkflowTypeName,
-			&status,
-			&info.StartTime,
-			&closeTime,
-			&info.ExecutionTime,
-			&info.Memo,
-			&searchAttrsJSON,
-			&info.TaskQueue,
-			&parentWorkflowID,
-			&parentRunID,
-		); err != nil {
+		info, err := scanExecutionRow(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan execution: %w", err)
 		}
-
-		info.Status = ExecutionStatus(status)
-		if closeTime != nil {
-			info.CloseTime = *closeTime
-		}
-		if len(searchAttrsJSON) > 0 {
-			json.Unmarshal(searchAttrsJSON, &info.SearchAttributes)
-		}
-		if parentWorkflowID != nil {
-			info.ParentWorkflowID = *parentWorkflowID
-		}
-		if parentRunID != nil {
-			info.ParentRunID = *parentRunID
-		}
-
-		executions = append(executions, &info)
+		executions = append(executions, info)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -253,6 +219,81 @@ func (s *PostgresStore) ListExecutions(ctx context.Context, req *ListRequest) (*
 	}, nil
 }
 
+// ScanExecutions returns the next batch of executions after the request's cursor,
+// using (start_time, run_id) as the keyset.
+func (s *PostgresStore) ScanExecutions(ctx context.Context, req *ScanRequest) ([]*ExecutionInfo, error) {
+	query, err := ParseQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		SELECT namespace_id, workflow_id, run_id, workflow_type_name,
+			   status, start_time, close_time, execution_time,
+			   memo, search_attributes, task_queue,
+			   parent_workflow_id, parent_run_id
+		FROM visibility
+		WHERE namespace_id = $1
+	`
+	args := []interface{}{req.NamespaceID}
+	argIdx := 2
+
+	for _, filter := range query.Filters {
+		col := mapFieldToColumn(filter.Field)
+		if col == "" {
+			continue
+		}
+		sql += fmt.Sprintf(" AND %s %s $%d", col, mapOperator(filter.Operator), argIdx)
+		args = append(args, filter.Value)
+		argIdx++
+	}
+
+	if !req.StartTimeFrom.IsZero() {
+		sql += fmt.Sprintf(" AND start_time >= $%d", argIdx)
+		args = append(args, req.StartTimeFrom)
+		argIdx++
+	}
+	if !req.StartTimeTo.IsZero() {
+		sql += fmt.Sprintf(" AND start_time < $%d", argIdx)
+		args = append(args, req.StartTimeTo)
+		argIdx++
+	}
+	if req.ClosedOnly {
+		sql += fmt.Sprintf(" AND status != $%d", argIdx)
+		args = append(args, int32(ExecutionStatusRunning))
+		argIdx++
+	}
+	if !req.AfterStart.IsZero() || req.AfterRunID != "" {
+		sql += fmt.Sprintf(" AND (start_time, run_id) > ($%d, $%d)", argIdx, argIdx+1)
+		args = append(args, req.AfterStart, req.AfterRunID)
+		argIdx += 2
+	}
+
+	sql += fmt.Sprintf(" ORDER BY start_time ASC, run_id ASC LIMIT $%d", argIdx)
+	args = append(args, req.Limit)
+
+	rows, err := s.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan executions: %w", err)
+	}
+	defer rows.Close()
+
+	executions := make([]*ExecutionInfo, 0, req.Limit)
+	for rows.Next() {
+		info, err := scanExecutionRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan execution: %w", err)
+		}
+		executions = append(executions, info)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating executions: %w", err)
+	}
+
+	return executions, nil
+}
+
 // CountExecutions counts executions matching the criteria.
 func (s *PostgresStore) CountExecutions(ctx context.Context, req *CountRequest) (*CountResponse, error) {
 	query, err := ParseQuery(req.Query)
@@ -294,6 +335,49 @@ func (s *PostgresStore) DeleteExecution(ctx context.Context, namespaceID, workfl
 	return nil
 }
 
+// scanExecutionRow scans a row selected with the standard visibility column list.
+func scanExecutionRow(row pgx.Row) (*ExecutionInfo, error) {
+	var info ExecutionInfo
+	var status int16
+	var closeTime *time.Time
+	var searchAttrsJSON []byte
+	var parentWorkflowID, parentRunID *string
+
+	if err := row.Scan(
+		&info.NamespaceID,
+		&info.WorkflowID,
+		&info.RunID,
+		&info.WorkflowTypeName,
+		&status,
+		&info.StartTime,
+		&closeTime,
+		&info.ExecutionTime,
+		&info.Memo,
+		&searchAttrsJSON,
+		&info.TaskQueue,
+		&parentWorkflowID,
+		&parentRunID,
+	); err != nil {
+		return nil, err
+	}
+
+	info.Status = ExecutionStatus(status)
+	if closeTime != nil {
+		info.CloseTime = *closeTime
+	}
+	if len(searchAttrsJSON) > 0 {
+		json.Unmarshal(searchAttrsJSON, &info.SearchAttributes)
+	}
+	if parentWorkflowID != nil {
+		info.ParentWorkflowID = *parentWorkflowID
+	}
+	if parentRunID != nil {
+		info.ParentRunID = *parentRunID
+	}
+
+	return &info, nil
+}
+
 func mapFieldToColumn(field string) string {
 	mapping := map[string]string{
 		"ExecutionStatus":  "status",
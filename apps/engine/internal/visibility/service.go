@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"strings"
 	"sync"
@@ -86,6 +88,27 @@ type CountResponse struct {
 	Count int64
 }
 
+// ScanRequest contains parameters for a keyset-paginated scan ordered by
+// (StartTime, RunID) ascending.
+type ScanRequest struct {
+	NamespaceID   string
+	Query         string
+	StartTimeFrom time.Time // inclusive, ignored when zero
+	StartTimeTo   time.Time // exclusive, ignored when zero
+	AfterStart    time.Time // keyset cursor: return rows after (AfterStart, AfterRunID)
+	AfterRunID    string
+	Limit         int32
+	ClosedOnly    bool // excludes running executions
+}
+
+// ExportQuery selects the closed executions written by ExportExecutions.
+type ExportQuery struct {
+	Query         string    // SQL-like query for filtering
+	StartTimeFrom time.Time // inclusive, ignored when zero
+	StartTimeTo   time.Time // exclusive, ignored when zero
+	BatchSize     int32
+}
+
 // Store defines the interface for visibility persistence.
 type Store interface {
 	// RecordExecutionStarted records a started execution
@@ -100,6 +123,8 @@ type Store interface {
 	ListExecutions(ctx context.Context, req *ListRequest) (*ListResponse, error)
 	// CountExecutions counts executions matching the criteria
 	CountExecutions(ctx context.Context, req *CountRequest) (*CountResponse, error)
+	// ScanExecutions returns the next batch of executions after the request's cursor
+	ScanExecutions(ctx context.Context, req *ScanRequest) ([]*ExecutionInfo, error)
 	// DeleteExecution deletes an execution record
 	DeleteExecution(ctx context.Context, namespaceID, workflowID, runID string) error
 }
@@ -253,6 +278,111 @@ func (s *Service) ListClosedExecutions(ctx context.Context, namespaceID string,
 	})
 }
 
+// ExportExecutions streams closed executions matching query to w as
+// newline-delimited JSON, oldest first. Running executions are left out: their
+// records are still changing. Rows are fetched in batches using keyset pagination so
+// memory stays bounded regardless of result size. If w implements
+// http.Flusher-style Flush(), it is flushed after every batch.
+func (s *Service) ExportExecutions(ctx context.Context, namespaceID string, query ExportQuery, w io.Writer) (int64, error) {
+	if _, err := ParseQuery(query.Query); err != nil {
+		return 0, err
+	}
+
+	batchSize := query.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	if batchSize > 1000 {
+		batchSize = 1000
+	}
+
+	flusher, _ := w.(interface{ Flush() })
+	encoder := json.NewEncoder(w)
+
+	req := &ScanRequest{
+		NamespaceID:   namespaceID,
+		Query:         query.Query,
+		StartTimeFrom: query.StartTimeFrom,
+		StartTimeTo:   query.StartTimeTo,
+		Limit:         batchSize,
+		ClosedOnly:    true,
+	}
+
+	var written int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		batch, err := s.store.ScanExecutions(ctx, req)
+		if err != nil {
+			return written, err
+		}
+
+		for _, info := range batch {
+			if err := encoder.Encode(toExportRecord(info)); err != nil {
+				return written, fmt.Errorf("failed to write export record: %w", err)
+			}
+			written++
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if len(batch) < int(batchSize) {
+			return written, nil
+		}
+
+		last := batch[len(batch)-1]
+		req.AfterStart = last.StartTime
+		req.AfterRunID = last.RunID
+	}
+}
+
+// exportRecord is the NDJSON representation of an exported execution.
+type exportRecord struct {
+	NamespaceID      string                 `json:"namespace_id"`
+	WorkflowID       string                 `json:"workflow_id"`
+	RunID            string                 `json:"run_id"`
+	WorkflowTypeName string                 `json:"workflow_type_name"`
+	Status           string                 `json:"status"`
+	StartTime        *time.Time             `json:"start_time,omitempty"`
+	CloseTime        *time.Time             `json:"close_time,omitempty"`
+	ExecutionTime    *time.Time             `json:"execution_time,omitempty"`
+	TaskQueue        string                 `json:"task_queue"`
+	Memo             json.RawMessage        `json:"memo,omitempty"`
+	SearchAttributes map[string]interface{} `json:"search_attributes,omitempty"`
+	ParentWorkflowID string                 `json:"parent_workflow_id,omitempty"`
+	ParentRunID      string                 `json:"parent_run_id,omitempty"`
+}
+
+func toExportRecord(info *ExecutionInfo) exportRecord {
+	rec := exportRecord{
+		NamespaceID:      info.NamespaceID,
+		WorkflowID:       info.WorkflowID,
+		RunID:            info.RunID,
+		WorkflowTypeName: info.WorkflowTypeName,
+		Status:           info.Status.String(),
+		TaskQueue:        info.TaskQueue,
+		SearchAttributes: info.SearchAttributes,
+		ParentWorkflowID: info.ParentWorkflowID,
+		ParentRunID:      info.ParentRunID,
+	}
+	if json.Valid(info.Memo) {
+		rec.Memo = info.Memo
+	}
+	if !info.StartTime.IsZero() {
+		rec.StartTime = &info.StartTime
+	}
+	if !info.CloseTime.IsZero() {
+		rec.CloseTime = &info.CloseTime
+	}
+	if !info.ExecutionTime.IsZero() {
+		rec.ExecutionTime = &info.ExecutionTime
+	}
+	return rec
+}
+
 // CountExecutions counts executions matching the query.
 func (s *Service) CountExecutions(ctx context.Context, req *CountRequest) (*CountResponse, error) {
 	return s.store.CountExecutions(ctx, req)
@@ -394,5 +524,5 @@ func parseCondition(cond string) (*Filter, error) {
 		}
 	}
 
-	return nil, ErrInvalidQuery
+	return nil, fmt.Errorf("%w: unrecognized condition %q", ErrInvalidQuery, cond)
 }
@@ -0,0 +1,58 @@
+package visibility
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestExportExecutionsSkipsRunningExecutions(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(NewMemoryStore(), Config{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"completed", "running", "failed"} {
+		if err := svc.RecordExecutionStarted(ctx, &ExecutionInfo{
+			NamespaceID:      "default",
+			WorkflowID:       id,
+			RunID:            "run-" + id,
+			WorkflowTypeName: "order",
+			StartTime:        base.Add(time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatalf("start %s: %v", id, err)
+		}
+	}
+	if err := svc.RecordExecutionCompleted(ctx, "default", "completed", "run-completed", nil); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if err := svc.RecordExecutionFailed(ctx, "default", "failed", "run-failed", "boom"); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+
+	var out bytes.Buffer
+	written, err := svc.ExportExecutions(ctx, "default", ExportQuery{BatchSize: 1}, &out)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	var got []string
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var rec exportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("decode %q: %v", scanner.Text(), err)
+		}
+		if rec.Status == ExecutionStatusRunning.String() {
+			t.Errorf("exported running execution %s", rec.WorkflowID)
+		}
+		got = append(got, rec.WorkflowID)
+	}
+	if written != 2 || len(got) != 2 || got[0] != "completed" || got[1] != "failed" {
+		t.Fatalf("exported %d records %v, want [completed failed]", written, got)
+	}
+}
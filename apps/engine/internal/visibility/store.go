@@ -138,6 +138,54 @@ func (s *MemoryStore) ListExecutions(ctx context.Context, req *ListRequest) (*Li
 	}, nil
 }
 
+// ScanExecutions returns the next batch of executions after the request's cursor.
+func (s *MemoryStore) ScanExecutions(ctx context.Context, req *ScanRequest) ([]*ExecutionInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query, err := ParseQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*ExecutionInfo
+	for _, info := range s.executions {
+		if info.NamespaceID != req.NamespaceID || !s.matchesQuery(info, query) {
+			continue
+		}
+		if req.ClosedOnly && info.Status == ExecutionStatusRunning {
+			continue
+		}
+		if !req.StartTimeFrom.IsZero() && info.StartTime.Before(req.StartTimeFrom) {
+			continue
+		}
+		if !req.StartTimeTo.IsZero() && !info.StartTime.Before(req.StartTimeTo) {
+			continue
+		}
+		if !req.AfterStart.IsZero() || req.AfterRunID != "" {
+			if info.StartTime.Before(req.AfterStart) ||
+				(info.StartTime.Equal(req.AfterStart) && info.RunID <= req.AfterRunID) {
+				continue
+			}
+		}
+		clone := *info
+		matches = append(matches, &clone)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].StartTime.Equal(matches[j].StartTime) {
+			return matches[i].StartTime.Before(matches[j].StartTime)
+		}
+		return matches[i].RunID < matches[j].RunID
+	})
+
+	if req.Limit > 0 && len(matches) > int(req.Limit) {
+		matches = matches[:req.Limit]
+	}
+
+	return matches, nil
+}
+
 // CountExecutions counts executions matching the criteria.
 func (s *MemoryStore) CountExecutions(ctx context.Context, req *CountRequest) (*CountResponse, error) {
 	s.mu.RLock()
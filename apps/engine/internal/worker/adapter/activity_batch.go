@@ -0,0 +1,57 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ActivityCompletionResult is history's answer to one completion of a batch.
+type ActivityCompletionResult struct {
+	Response *historyv1.RespondActivityTaskCompletedResponse
+	Err      error
+}
+
+// RespondActivityTaskCompletedBatch sends activity completions to history in
+// one call and returns a result per request, in request order. An error
+// means the batch as a whole failed, e.g. with codes.Unimplemented from a
+// history service that does not serve batches; some of its completions may
+// still have been recorded.
+func (c *HistoryClient) RespondActivityTaskCompletedBatch(ctx context.Context, reqs []*historyv1.RespondActivityTaskCompletedRequest) ([]ActivityCompletionResult, error) {
+	resp, err := c.client.RespondActivityTaskCompletedBatch(ctx, &historyv1.RespondActivityTaskCompletedBatchRequest{Requests: reqs})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.GetResults()) != len(reqs) {
+		return nil, fmt.Errorf("history answered %d of %d completions", len(resp.GetResults()), len(reqs))
+	}
+
+	results := make([]ActivityCompletionResult, len(reqs))
+	for i, reply := range resp.GetResults() {
+		if code := codes.Code(reply.GetErrorCode()); code != codes.OK {
+			results[i].Err = completionError(code, reply)
+			continue
+		}
+		results[i].Response = reply.GetResponse()
+	}
+	return results, nil
+}
+
+// completionError rebuilds the status a completion of a batch was rejected
+// with, so IsNodeNotPending recognizes it like the error of a single
+// completion.
+func completionError(code codes.Code, reply *historyv1.ActivityCompletionResult) error {
+	st := status.New(code, reply.GetErrorMessage())
+	if reply.GetErrorReason() == "" {
+		return st.Err()
+	}
+	withReason, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reply.GetErrorReason(), Domain: "history.linkflow"})
+	if err != nil {
+		return st.Err()
+	}
+	return withReason.Err()
+}
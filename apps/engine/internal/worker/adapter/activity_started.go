@@ -0,0 +1,26 @@
+package adapter
+
+import (
+	"context"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+)
+
+// RecordActivityTaskStarted records in history that this worker began the
+// given attempt of the node scheduled by scheduledEventID, and returns the
+// NodeStarted event ID. It fails with a node-not-pending error (see
+// IsNodeNotPending) when the node has already closed.
+func (c *HistoryClient) RecordActivityTaskStarted(ctx context.Context, namespace string, execution *commonv1.WorkflowExecution, scheduledEventID int64, attempt int32, identity string) (int64, error) {
+	resp, err := c.client.RecordActivityTaskStarted(ctx, &historyv1.RecordActivityTaskStartedRequest{
+		Namespace:         namespace,
+		WorkflowExecution: execution,
+		ScheduledEventId:  scheduledEventID,
+		Attempt:           attempt,
+		Identity:          identity,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.GetEventId(), nil
+}
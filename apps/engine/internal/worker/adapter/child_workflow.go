@@ -0,0 +1,17 @@
+package adapter
+
+import (
+	"context"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+)
+
+// StartChildWorkflowForActivity asks history to start the child workflow
+// described by attrs for the pending async activity identified by taskToken.
+// History completes the activity with the child's result when it closes.
+func (c *HistoryClient) StartChildWorkflowForActivity(ctx context.Context, taskToken string, attrs *historyv1.StartChildWorkflowExecutionCommandAttributes) (*historyv1.StartChildWorkflowForActivityResponse, error) {
+	return c.client.StartChildWorkflowForActivity(ctx, &historyv1.StartChildWorkflowForActivityRequest{
+		TaskToken:  []byte(taskToken),
+		Attributes: attrs,
+	})
+}
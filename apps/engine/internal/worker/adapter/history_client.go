@@ -5,9 +5,32 @@ import (
 
 	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
 	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// nodeNotPendingReason is the ErrorInfo reason history attaches when it
+// rejects a result for a node that is not pending.
+const nodeNotPendingReason = "NODE_NOT_PENDING"
+
+// IsNodeNotPending reports whether history rejected an activity result
+// because its node already closed or was never scheduled. Such a result is
+// never accepted, so retrying it is pointless.
+func IsNodeNotPending(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.FailedPrecondition {
+		return false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() == nodeNotPendingReason {
+			return true
+		}
+	}
+	return false
+}
+
 type HistoryClient struct {
 	client historyv1.HistoryServiceClient
 }
@@ -67,6 +90,10 @@ func (c *HistoryClient) RespondActivityTaskCompleted(ctx context.Context, req *h
 	return c.client.RespondActivityTaskCompleted(ctx, req)
 }
 
+func (c *HistoryClient) RecordActivityTaskPending(ctx context.Context, req *historyv1.RecordActivityTaskPendingRequest) (*historyv1.RecordActivityTaskPendingResponse, error) {
+	return c.client.RecordActivityTaskPending(ctx, req)
+}
+
 func (c *HistoryClient) RespondActivityTaskFailed(ctx context.Context, req *historyv1.RespondActivityTaskFailedRequest) (*historyv1.RespondActivityTaskFailedResponse, error) {
 	return c.client.RespondActivityTaskFailed(ctx, req)
 }
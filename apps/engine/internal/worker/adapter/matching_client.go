@@ -22,8 +22,9 @@ func NewMatchingClient(conn *grpc.ClientConn) *MatchingClient {
 }
 
 func (c *MatchingClient) PollTask(ctx context.Context, taskQueue string, identity string) (*poller.Task, error) {
+	// Workers serve every namespace, so the poll names no namespace and
+	// matching hands out tasks from the queue in each namespace in turn.
 	req := &matchingv1.PollTaskRequest{
-		Namespace: "default",
 		TaskQueue: &matchingv1.TaskQueue{
 			Name: taskQueue,
 			Kind: commonv1.TaskQueueKind_TASK_QUEUE_KIND_NORMAL,
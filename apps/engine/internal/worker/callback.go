@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkflow/engine/internal/worker/retry"
+)
+
+// DefaultCallbackRetryPolicy returns the retry policy used for legacy
+// callbacks when Config.CallbackRetryPolicy is unset.
+func DefaultCallbackRetryPolicy() *retry.Policy {
+	return &retry.Policy{
+		InitialInterval:    time.Second,
+		BackoffCoefficient: 2.0,
+		MaximumInterval:    30 * time.Second,
+		MaximumAttempts:    3,
+	}
+}
+
+// callbackStatusError is returned when the callback receiver answers with a
+// 4xx or 5xx status.
+type callbackStatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *callbackStatusError) Error() string {
+	return fmt.Sprintf("callback returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryable reports whether the receiver may accept the callback later.
+// Client errors other than 429 Too Many Requests are terminal.
+func (e *callbackStatusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds
+// or as an HTTP-date. It returns 0 when the header is absent or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// deliverLegacyCallback posts a callback body, retrying network errors, 5xx
+// and 429 responses with exponential backoff. A Retry-After header from the
+// receiver replaces the computed backoff; if it asks for longer than the
+// policy's MaximumInterval the callback is abandoned rather than sent early.
+func (s *Service) deliverLegacyCallback(callbackURL string, body []byte, jobID, status string) {
+	policy := s.callbackRetry
+	if policy == nil {
+		policy = DefaultCallbackRetryPolicy()
+	}
+	sleep := s.callbackSleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	for attempt := int32(1); ; attempt++ {
+		reqCtx, cancel := context.WithTimeout(context.Background(), s.callbackHTTP.Timeout)
+		err := s.postLegacyCallback(reqCtx, callbackURL, body)
+		cancel()
+
+		if err == nil {
+			return
+		}
+
+		s.logger.Warn("failed to send workflow callback",
+			slog.String("job_id", jobID),
+			slog.String("status", status),
+			slog.Int("attempt", int(attempt)),
+			slog.String("error", err.Error()),
+		)
+
+		var retryAfter time.Duration
+		var statusErr *callbackStatusError
+		if errors.As(err, &statusErr) {
+			if !statusErr.retryable() {
+				return
+			}
+			retryAfter = statusErr.RetryAfter
+		}
+		if attempt >= policy.MaximumAttempts {
+			return
+		}
+
+		delay := retry.CalculateBackoff(policy, attempt)
+		if retryAfter > 0 {
+			if policy.MaximumInterval > 0 && retryAfter > policy.MaximumInterval {
+				s.logger.Warn("abandoning workflow callback, Retry-After exceeds maximum interval",
+					slog.String("job_id", jobID),
+					slog.Duration("retry_after", retryAfter),
+					slog.Duration("maximum_interval", policy.MaximumInterval),
+				)
+				return
+			}
+			delay = retryAfter
+		}
+		sleep(delay)
+	}
+}
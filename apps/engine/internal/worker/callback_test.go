@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/worker/executor"
+	"github.com/linkflow/engine/internal/worker/retry"
+)
+
+type callbackReceiver struct {
+	server   *httptest.Server
+	requests atomic.Int32
+}
+
+// newCallbackReceiver serves the given responses in order, repeating the last
+// one once they run out.
+func newCallbackReceiver(t *testing.T, responses ...func(w http.ResponseWriter)) *callbackReceiver {
+	t.Helper()
+	r := &callbackReceiver{}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := int(r.requests.Add(1))
+		if n > len(responses) {
+			n = len(responses)
+		}
+		responses[n-1](w)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func respond(status int, retryAfter string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(status)
+	}
+}
+
+func newCallbackTestService(policy *retry.Policy) (*Service, *[]time.Duration) {
+	var sleeps []time.Duration
+	svc := &Service{
+		callbackHTTP:  &http.Client{Timeout: 5 * time.Second},
+		callbackRetry: policy,
+		callbackSleep: func(d time.Duration) { sleeps = append(sleeps, d) },
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	return svc, &sleeps
+}
+
+func testCallbackPayload(url string) *executor.JobPayload {
+	return &executor.JobPayload{
+		JobID:         "job-1",
+		CallbackToken: "token",
+		ExecutionID:   42,
+		CallbackURL:   url,
+	}
+}
+
+func TestLegacyCallbackExponentialBackoff(t *testing.T) {
+	receiver := newCallbackReceiver(t, respond(http.StatusServiceUnavailable, ""))
+	policy := &retry.Policy{
+		InitialInterval:    100 * time.Millisecond,
+		BackoffCoefficient: 2.0,
+		MaximumInterval:    10 * time.Second,
+		MaximumAttempts:    4,
+	}
+	svc, sleeps := newCallbackTestService(policy)
+
+	svc.sendLegacyCallback(testCallbackPayload(receiver.server.URL), "completed", time.Second, nil, nil)
+
+	if got := receiver.requests.Load(); got != 4 {
+		t.Fatalf("expected 4 attempts, got %d", got)
+	}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	if len(*sleeps) != len(expected) {
+		t.Fatalf("expected %d backoffs, got %v", len(expected), *sleeps)
+	}
+	for i, base := range expected {
+		low, high := base*8/10, base*12/10
+		if d := (*sleeps)[i]; d < low || d > high {
+			t.Errorf("backoff %d = %v, want within [%v, %v]", i+1, d, low, high)
+		}
+	}
+}
+
+func TestLegacyCallbackHonorsRetryAfter(t *testing.T) {
+	date := time.Now().Add(5 * time.Second).UTC().Format(http.TimeFormat)
+	receiver := newCallbackReceiver(t,
+		respond(http.StatusServiceUnavailable, "2"),
+		respond(http.StatusTooManyRequests, date),
+		respond(http.StatusOK, ""),
+	)
+	svc, sleeps := newCallbackTestService(&retry.Policy{
+		InitialInterval:    10 * time.Millisecond,
+		BackoffCoefficient: 2.0,
+		MaximumInterval:    time.Minute,
+		MaximumAttempts:    5,
+	})
+
+	svc.sendLegacyCallback(testCallbackPayload(receiver.server.URL), "failed", time.Second, nil, nil)
+
+	if got := receiver.requests.Load(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+	if len(*sleeps) != 2 {
+		t.Fatalf("expected 2 waits, got %v", *sleeps)
+	}
+	if (*sleeps)[0] != 2*time.Second {
+		t.Errorf("Retry-After seconds: waited %v, want 2s", (*sleeps)[0])
+	}
+	if d := (*sleeps)[1]; d < 3*time.Second || d > 5*time.Second {
+		t.Errorf("Retry-After date: waited %v, want between 3s and 5s", d)
+	}
+}
+
+func TestLegacyCallbackClientErrorIsTerminal(t *testing.T) {
+	receiver := newCallbackReceiver(t, respond(http.StatusBadRequest, "1"))
+	svc, sleeps := newCallbackTestService(DefaultCallbackRetryPolicy())
+
+	svc.sendLegacyCallback(testCallbackPayload(receiver.server.URL), "completed", time.Second, nil, nil)
+
+	if got := receiver.requests.Load(); got != 1 {
+		t.Fatalf("expected 1 attempt, got %d", got)
+	}
+	if len(*sleeps) != 0 {
+		t.Fatalf("expected no retries, got %v", *sleeps)
+	}
+}
+
+func TestLegacyCallbackRetryAfterBeyondMaximumInterval(t *testing.T) {
+	receiver := newCallbackReceiver(t, respond(http.StatusServiceUnavailable, "120"))
+	svc, sleeps := newCallbackTestService(DefaultCallbackRetryPolicy())
+
+	svc.sendLegacyCallback(testCallbackPayload(receiver.server.URL), "completed", time.Second, nil, nil)
+
+	if got := receiver.requests.Load(); got != 1 {
+		t.Fatalf("expected 1 attempt, got %d", got)
+	}
+	if len(*sleeps) != 0 {
+		t.Fatalf("expected callback to be abandoned, got waits %v", *sleeps)
+	}
+}
+
+func TestLegacyCallbackRetriesNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	svc, sleeps := newCallbackTestService(DefaultCallbackRetryPolicy())
+	svc.sendLegacyCallback(testCallbackPayload(url), "completed", time.Second, nil, nil)
+
+	if len(*sleeps) != 2 {
+		t.Fatalf("expected 2 retries for 3 attempts, got %v", *sleeps)
+	}
+}
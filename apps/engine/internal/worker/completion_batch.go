@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/worker/adapter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultCompletionBatchWindow = 10 * time.Millisecond
+	defaultCompletionBatchSize   = 100
+
+	// completionBatchTimeout bounds one batch call to history.
+	completionBatchTimeout = 30 * time.Second
+)
+
+// CompletionBatchConfig controls batching of activity completions. When
+// enabled, completions reported within Window of the first one are sent to
+// history in a single call of at most MaxBatch completions.
+type CompletionBatchConfig struct {
+	Enabled  bool
+	Window   time.Duration // default 10ms
+	MaxBatch int           // default 100
+}
+
+// activityCompleter is the part of the history client the batcher uses.
+type activityCompleter interface {
+	RespondActivityTaskCompleted(ctx context.Context, req *historyv1.RespondActivityTaskCompletedRequest) (*historyv1.RespondActivityTaskCompletedResponse, error)
+	RespondActivityTaskCompletedBatch(ctx context.Context, reqs []*historyv1.RespondActivityTaskCompletedRequest) ([]adapter.ActivityCompletionResult, error)
+}
+
+type pendingCompletion struct {
+	ctx  context.Context
+	req  *historyv1.RespondActivityTaskCompletedRequest
+	done chan adapter.ActivityCompletionResult
+}
+
+// completionBatcher collects activity completions and sends them to history
+// in batches. Batches are sent one at a time by a single goroutine, so
+// completions reach history in the order they were reported.
+type completionBatcher struct {
+	client   activityCompleter
+	window   time.Duration
+	maxBatch int
+	logger   *slog.Logger
+
+	queue chan *pendingCompletion
+
+	mu          sync.Mutex
+	stopCh      chan struct{}
+	stopped     chan struct{}
+	unsupported bool // history does not serve batches
+}
+
+func newCompletionBatcher(client activityCompleter, cfg CompletionBatchConfig, logger *slog.Logger) *completionBatcher {
+	if cfg.Window <= 0 {
+		cfg.Window = defaultCompletionBatchWindow
+	}
+	if cfg.MaxBatch <= 0 {
+		cfg.MaxBatch = defaultCompletionBatchSize
+	}
+	return &completionBatcher{
+		client:   client,
+		window:   cfg.Window,
+		maxBatch: cfg.MaxBatch,
+		logger:   logger,
+		queue:    make(chan *pendingCompletion, cfg.MaxBatch),
+	}
+}
+
+func (b *completionBatcher) start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopCh != nil {
+		return
+	}
+	b.stopCh = make(chan struct{})
+	b.stopped = make(chan struct{})
+	go b.run(b.stopCh, b.stopped)
+}
+
+// stop ends the batching goroutine once it has sent what it collected.
+func (b *completionBatcher) stop() {
+	b.mu.Lock()
+	stopCh, stopped := b.stopCh, b.stopped
+	b.stopCh, b.stopped = nil, nil
+	b.mu.Unlock()
+	if stopCh == nil {
+		return
+	}
+	close(stopCh)
+	<-stopped
+}
+
+// Complete queues a completion for the next batch and waits for history's
+// answer to it.
+func (b *completionBatcher) Complete(ctx context.Context, req *historyv1.RespondActivityTaskCompletedRequest) (*historyv1.RespondActivityTaskCompletedResponse, error) {
+	p := &pendingCompletion{ctx: ctx, req: req, done: make(chan adapter.ActivityCompletionResult, 1)}
+	select {
+	case b.queue <- p:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case result := <-p.done:
+		return result.Response, result.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (b *completionBatcher) run(stopCh <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+	for {
+		var batch []*pendingCompletion
+		select {
+		case p := <-b.queue:
+			batch = append(batch, p)
+		case <-stopCh:
+			return
+		}
+
+		timer := time.NewTimer(b.window)
+	collect:
+		for len(batch) < b.maxBatch {
+			select {
+			case p := <-b.queue:
+				batch = append(batch, p)
+			case <-timer.C:
+				break collect
+			case <-stopCh:
+				break collect
+			}
+		}
+		timer.Stop()
+		b.flush(batch)
+	}
+}
+
+func (b *completionBatcher) flush(batch []*pendingCompletion) {
+	// Completions whose task has already given up are not sent, as they
+	// would not be without batching.
+	live := batch[:0]
+	for _, p := range batch {
+		if err := p.ctx.Err(); err != nil {
+			p.done <- adapter.ActivityCompletionResult{Err: err}
+			continue
+		}
+		live = append(live, p)
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	unsupported := b.unsupported
+	b.mu.Unlock()
+	if len(live) == 1 || unsupported {
+		b.completeEach(live)
+		return
+	}
+
+	reqs := make([]*historyv1.RespondActivityTaskCompletedRequest, len(live))
+	for i, p := range live {
+		reqs[i] = p.req
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionBatchTimeout)
+	results, err := b.client.RespondActivityTaskCompletedBatch(ctx, reqs)
+	cancel()
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			b.mu.Lock()
+			b.unsupported = true
+			b.mu.Unlock()
+			b.logger.Warn("history does not serve batched activity completions; completing one by one")
+		} else {
+			b.logger.Warn("activity completion batch failed, completing one by one",
+				slog.Int("completions", len(live)),
+				slog.String("error", err.Error()),
+			)
+		}
+		// Completions carry request IDs, so any history recorded before the
+		// batch failed are not recorded twice.
+		b.completeEach(live)
+		return
+	}
+
+	for i, p := range live {
+		p.done <- results[i]
+	}
+}
+
+func (b *completionBatcher) completeEach(batch []*pendingCompletion) {
+	for _, p := range batch {
+		resp, err := b.client.RespondActivityTaskCompleted(p.ctx, p.req)
+		p.done <- adapter.ActivityCompletionResult{Response: resp, Err: err}
+	}
+}
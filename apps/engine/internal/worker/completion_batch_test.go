@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/worker/adapter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeCompleter struct {
+	mu         sync.Mutex
+	batchErr   error
+	batches    [][]int64
+	singles    []int64
+	rejectNode int64
+}
+
+func (f *fakeCompleter) answer(req *historyv1.RespondActivityTaskCompletedRequest) (*historyv1.RespondActivityTaskCompletedResponse, error) {
+	if req.ScheduledEventId == f.rejectNode {
+		return nil, errors.New("node not pending")
+	}
+	return &historyv1.RespondActivityTaskCompletedResponse{EventId: req.ScheduledEventId + 100}, nil
+}
+
+func (f *fakeCompleter) RespondActivityTaskCompleted(_ context.Context, req *historyv1.RespondActivityTaskCompletedRequest) (*historyv1.RespondActivityTaskCompletedResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.singles = append(f.singles, req.ScheduledEventId)
+	return f.answer(req)
+}
+
+func (f *fakeCompleter) RespondActivityTaskCompletedBatch(_ context.Context, reqs []*historyv1.RespondActivityTaskCompletedRequest) ([]adapter.ActivityCompletionResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.batchErr != nil {
+		return nil, f.batchErr
+	}
+	ids := make([]int64, len(reqs))
+	results := make([]adapter.ActivityCompletionResult, len(reqs))
+	for i, req := range reqs {
+		ids[i] = req.ScheduledEventId
+		results[i].Response, results[i].Err = f.answer(req)
+	}
+	f.batches = append(f.batches, ids)
+	return results, nil
+}
+
+func completeConcurrently(t *testing.T, b *completionBatcher, ids []int64) map[int64]error {
+	t.Helper()
+	var mu sync.Mutex
+	errs := make(map[int64]error)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			resp, err := b.Complete(context.Background(), &historyv1.RespondActivityTaskCompletedRequest{ScheduledEventId: id})
+			if err == nil && resp.GetEventId() != id+100 {
+				err = errors.New("mismatched response")
+			}
+			mu.Lock()
+			errs[id] = err
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestCompletionBatcherSendsConcurrentCompletionsTogether(t *testing.T) {
+	client := &fakeCompleter{rejectNode: 3}
+	b := newCompletionBatcher(client, CompletionBatchConfig{Window: 50 * time.Millisecond, MaxBatch: 4}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	b.start()
+	defer b.stop()
+
+	errs := completeConcurrently(t, b, []int64{1, 2, 3, 4, 5})
+	for id, err := range errs {
+		if (err != nil) != (id == 3) {
+			t.Fatalf("completion %d: unexpected error %v", id, err)
+		}
+	}
+
+	total := 0
+	for _, batch := range client.batches {
+		if len(batch) > 4 {
+			t.Fatalf("batch exceeds MaxBatch: %v", batch)
+		}
+		total += len(batch)
+	}
+	total += len(client.singles)
+	if total != 5 || len(client.batches) == 0 {
+		t.Fatalf("expected 5 completions sent mostly in batches, got batches %v singles %v", client.batches, client.singles)
+	}
+}
+
+func TestCompletionBatcherFallsBackWhenHistoryLacksBatches(t *testing.T) {
+	client := &fakeCompleter{batchErr: status.Error(codes.Unimplemented, "unknown service")}
+	b := newCompletionBatcher(client, CompletionBatchConfig{Window: 50 * time.Millisecond}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	b.start()
+	defer b.stop()
+
+	for id, err := range completeConcurrently(t, b, []int64{1, 2, 3}) {
+		if err != nil {
+			t.Fatalf("completion %d: %v", id, err)
+		}
+	}
+	if len(client.singles) != 3 {
+		t.Fatalf("expected 3 individual completions, got %v", client.singles)
+	}
+	if !b.unsupported {
+		t.Fatal("expected batching to be switched off")
+	}
+}
@@ -3,6 +3,7 @@ package executor
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,8 +12,16 @@ import (
 	"time"
 )
 
+// Models used when the node config does not name one.
+const (
+	defaultOpenAIModel    = "gpt-4o"
+	defaultAnthropicModel = "claude-3-5-sonnet-20241022"
+)
+
 // AIExecutor handles AI/LLM operations (OpenAI, Anthropic, etc.)
 type AIExecutor struct {
+	BaseExecutor
+
 	client        *http.Client
 	defaultOpenAI string
 	defaultClaude string
@@ -37,7 +46,8 @@ type AIConfig struct {
 	Messages     []AIMessage `json:"messages"`
 	Prompt       string      `json:"prompt"` // Simple single prompt
 
-	// Streaming (for future)
+	// Stream consumes the completion as server-sent events and reports the
+	// partial text through the progress callback as it arrives.
 	Stream bool `json:"stream"`
 
 	// Custom endpoint
@@ -183,13 +193,58 @@ func (e *AIExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execute
 		}, nil
 	}
 
+	// The fingerprint leaves out the API key and whether the completion is
+	// streamed: both runs produce the same completion.
+	requestBytes, _ := json.Marshal(map[string]interface{}{
+		"provider":    config.Provider,
+		"model":       config.Model,
+		"endpoint":    config.Endpoint,
+		"messages":    messages,
+		"max_tokens":  config.MaxTokens,
+		"temperature": config.Temperature,
+		"top_p":       config.TopP,
+	})
+	requestFingerprint := fmt.Sprintf("%x", sha256.Sum256(requestBytes))
+
+	if req.Deterministic != nil && req.Deterministic.Mode == "replay" {
+		for _, fixture := range req.Deterministic.Fixtures {
+			if fixture.RequestFingerprint != requestFingerprint {
+				continue
+			}
+
+			logs = append(logs, LogEntry{
+				Timestamp: time.Now(),
+				Level:     "INFO",
+				Message:   "Replaying AI response from deterministic fixture",
+			})
+			return &ExecuteResponse{
+				Output:   fixture.Response,
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: "missing deterministic replay fixture for AI request",
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
 	var aiResp AIResponse
 	var err error
 
-	switch config.Provider {
-	case "openai":
+	switch {
+	case config.Provider == "openai" && config.Stream:
+		aiResp, err = e.streamOpenAI(ctx, config, messages, newAIStreamProgress(req.Progress, config.MaxTokens), &logs)
+	case config.Provider == "openai":
 		aiResp, err = e.callOpenAI(ctx, config, messages, &logs)
-	case "anthropic":
+	case config.Provider == "anthropic" && config.Stream:
+		aiResp, err = e.streamAnthropic(ctx, config, messages, newAIStreamProgress(req.Progress, config.MaxTokens), &logs)
+	case config.Provider == "anthropic":
 		aiResp, err = e.callAnthropic(ctx, config, messages, &logs)
 	default:
 		return &ExecuteResponse{
@@ -222,7 +277,7 @@ func (e *AIExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execute
 		}, nil
 	}
 
-	aiResp.Timestamp = time.Now().Format(time.RFC3339)
+	aiResp.Timestamp = req.Deterministic.Now().Format(time.RFC3339)
 
 	logs = append(logs, LogEntry{
 		Timestamp: time.Now(),
@@ -243,7 +298,14 @@ func (e *AIExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execute
 	}
 
 	return &ExecuteResponse{
-		Output:   output,
+		Output: output,
+		DeterministicFixtures: []DeterministicFixture{{
+			RequestFingerprint: requestFingerprint,
+			NodeID:             req.NodeID,
+			NodeType:           req.NodeType,
+			Request:            requestBytes,
+			Response:           output,
+		}},
 		Logs:     logs,
 		Duration: time.Since(start),
 	}, nil
@@ -255,7 +317,7 @@ func (e *AIExecutor) callOpenAI(ctx context.Context, config AIConfig, messages [
 
 	model := config.Model
 	if model == "" {
-		model = "gpt-4o"
+		model = defaultOpenAIModel
 	}
 	response.Model = model
 
@@ -265,30 +327,10 @@ func (e *AIExecutor) callOpenAI(ctx context.Context, config AIConfig, messages [
 		Message:   fmt.Sprintf("Calling OpenAI API with model %s", model),
 	})
 
-	// Build request payload
-	payload := map[string]interface{}{
-		"model":       model,
-		"messages":    messages,
-		"max_tokens":  config.MaxTokens,
-		"temperature": config.Temperature,
-	}
-	if config.TopP > 0 {
-		payload["top_p"] = config.TopP
-	}
-
-	body, _ := json.Marshal(payload)
-
-	endpoint := config.Endpoint
-	if endpoint == "" {
-		endpoint = "https://api.openai.com/v1/chat/completions"
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	req, err := newOpenAIRequest(ctx, config, model, messages, false)
 	if err != nil {
 		return response, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+config.APIKey)
 
 	resp, err := e.client.Do(req)
 	if err != nil {
@@ -299,14 +341,7 @@ func (e *AIExecutor) callOpenAI(ctx context.Context, config AIConfig, messages [
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != 200 {
-		var errResp struct {
-			Error struct {
-				Message string `json:"message"`
-				Type    string `json:"type"`
-			} `json:"error"`
-		}
-		json.Unmarshal(respBody, &errResp)
-		return response, fmt.Errorf("OpenAI API error: %s (%s)", errResp.Error.Message, errResp.Error.Type)
+		return response, openAIError(respBody)
 	}
 
 	var openAIResp struct {
@@ -342,13 +377,57 @@ func (e *AIExecutor) callOpenAI(ctx context.Context, config AIConfig, messages [
 	return response, nil
 }
 
+// newOpenAIRequest builds a chat completions request. A streamed request also
+// asks for the token usage, which OpenAI otherwise leaves out of streams.
+func newOpenAIRequest(ctx context.Context, config AIConfig, model string, messages []AIMessage, stream bool) (*http.Request, error) {
+	payload := map[string]interface{}{
+		"model":       model,
+		"messages":    messages,
+		"max_tokens":  config.MaxTokens,
+		"temperature": config.Temperature,
+	}
+	if config.TopP > 0 {
+		payload["top_p"] = config.TopP
+	}
+	if stream {
+		payload["stream"] = true
+		payload["stream_options"] = map[string]interface{}{"include_usage": true}
+	}
+
+	body, _ := json.Marshal(payload)
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.openai.com/v1/chat/completions"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+config.APIKey)
+	return req, nil
+}
+
+func openAIError(respBody []byte) error {
+	var errResp struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"error"`
+	}
+	json.Unmarshal(respBody, &errResp)
+	return fmt.Errorf("OpenAI API error: %s (%s)", errResp.Error.Message, errResp.Error.Type)
+}
+
 func (e *AIExecutor) callAnthropic(ctx context.Context, config AIConfig, messages []AIMessage, logs *[]LogEntry) (AIResponse, error) {
 	var response AIResponse
 	response.Provider = "anthropic"
 
 	model := config.Model
 	if model == "" {
-		model = "claude-3-5-sonnet-20241022"
+		model = defaultAnthropicModel
 	}
 	response.Model = model
 
@@ -358,51 +437,10 @@ func (e *AIExecutor) callAnthropic(ctx context.Context, config AIConfig, message
 		Message:   fmt.Sprintf("Calling Anthropic API with model %s", model),
 	})
 
-	// Extract system message and convert to Anthropic format
-	var systemPrompt string
-	var anthropicMessages []map[string]string
-
-	for _, msg := range messages {
-		if msg.Role == "system" {
-			systemPrompt = msg.Content
-		} else {
-			anthropicMessages = append(anthropicMessages, map[string]string{
-				"role":    msg.Role,
-				"content": msg.Content,
-			})
-		}
-	}
-
-	// Build request payload
-	payload := map[string]interface{}{
-		"model":      model,
-		"messages":   anthropicMessages,
-		"max_tokens": config.MaxTokens,
-	}
-	if systemPrompt != "" {
-		payload["system"] = systemPrompt
-	}
-	if config.Temperature > 0 {
-		payload["temperature"] = config.Temperature
-	}
-	if config.TopP > 0 {
-		payload["top_p"] = config.TopP
-	}
-
-	body, _ := json.Marshal(payload)
-
-	endpoint := config.Endpoint
-	if endpoint == "" {
-		endpoint = "https://api.anthropic.com/v1/messages"
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	req, err := newAnthropicRequest(ctx, config, model, messages, false)
 	if err != nil {
 		return response, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", config.APIKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
 
 	resp, err := e.client.Do(req)
 	if err != nil {
@@ -413,14 +451,7 @@ func (e *AIExecutor) callAnthropic(ctx context.Context, config AIConfig, message
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != 200 {
-		var errResp struct {
-			Error struct {
-				Type    string `json:"type"`
-				Message string `json:"message"`
-			} `json:"error"`
-		}
-		json.Unmarshal(respBody, &errResp)
-		return response, fmt.Errorf("Anthropic API error: %s (%s)", errResp.Error.Message, errResp.Error.Type)
+		return response, anthropicError(respBody)
 	}
 
 	var anthropicResp struct {
@@ -457,3 +488,66 @@ func (e *AIExecutor) callAnthropic(ctx context.Context, config AIConfig, message
 
 	return response, nil
 }
+
+// newAnthropicRequest builds a messages API request. Anthropic takes the
+// system prompt as a separate field rather than as a message.
+func newAnthropicRequest(ctx context.Context, config AIConfig, model string, messages []AIMessage, stream bool) (*http.Request, error) {
+	var systemPrompt string
+	var anthropicMessages []map[string]string
+
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			systemPrompt = msg.Content
+		} else {
+			anthropicMessages = append(anthropicMessages, map[string]string{
+				"role":    msg.Role,
+				"content": msg.Content,
+			})
+		}
+	}
+
+	payload := map[string]interface{}{
+		"model":      model,
+		"messages":   anthropicMessages,
+		"max_tokens": config.MaxTokens,
+	}
+	if systemPrompt != "" {
+		payload["system"] = systemPrompt
+	}
+	if config.Temperature > 0 {
+		payload["temperature"] = config.Temperature
+	}
+	if config.TopP > 0 {
+		payload["top_p"] = config.TopP
+	}
+	if stream {
+		payload["stream"] = true
+	}
+
+	body, _ := json.Marshal(payload)
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.anthropic.com/v1/messages"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", config.APIKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	return req, nil
+}
+
+func anthropicError(respBody []byte) error {
+	var errResp struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.Unmarshal(respBody, &errResp)
+	return fmt.Errorf("Anthropic API error: %s (%s)", errResp.Error.Message, errResp.Error.Type)
+}
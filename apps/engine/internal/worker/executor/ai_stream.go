@@ -0,0 +1,290 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// aiStreamProgressInterval is the least time between two progress
+	// reports while a completion streams.
+	aiStreamProgressInterval = 500 * time.Millisecond
+	// aiStreamMinProgress and aiStreamMaxProgress bound the progress reported
+	// while streaming, below the 80 the worker reports once the node is done.
+	aiStreamMinProgress = 10
+	aiStreamMaxProgress = 75
+	// maxSSELineBytes caps a single server-sent event line.
+	maxSSELineBytes = 1024 * 1024
+)
+
+// aiStreamProgress accumulates a streamed completion and reports it, at most
+// once per aiStreamProgressInterval, with a progress estimate that grows with
+// the tokens received against max_tokens.
+type aiStreamProgress struct {
+	report     func(progress int, partial string)
+	maxTokens  int
+	tokens     int
+	text       strings.Builder
+	lastReport time.Time
+}
+
+func newAIStreamProgress(report func(progress int, partial string), maxTokens int) *aiStreamProgress {
+	return &aiStreamProgress{report: report, maxTokens: maxTokens}
+}
+
+// add appends a delta of the completion. Each delta counts as one token,
+// which is how both providers stream.
+func (p *aiStreamProgress) add(delta string) {
+	if delta == "" {
+		return
+	}
+	p.text.WriteString(delta)
+	p.tokens++
+
+	if p.report == nil || time.Since(p.lastReport) < aiStreamProgressInterval {
+		return
+	}
+	p.lastReport = time.Now()
+	p.report(p.progress(), p.text.String())
+}
+
+func (p *aiStreamProgress) progress() int {
+	if p.maxTokens <= 0 {
+		return aiStreamMinProgress
+	}
+	progress := aiStreamMinProgress + (aiStreamMaxProgress-aiStreamMinProgress)*p.tokens/p.maxTokens
+	if progress > aiStreamMaxProgress {
+		return aiStreamMaxProgress
+	}
+	return progress
+}
+
+func (p *aiStreamProgress) content() string {
+	return p.text.String()
+}
+
+// readSSE calls handle with the data of each server-sent event in r until
+// handle returns an error, which readSSE returns, or r is exhausted, which
+// means the stream ended early. Multi-line data is joined with newlines;
+// comments and fields other than data are skipped.
+func readSSE(r io.Reader, handle func(data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineBytes)
+
+	var data bytes.Buffer
+	dispatch := func() error {
+		if data.Len() == 0 {
+			return nil
+		}
+		defer data.Reset()
+		return handle(data.Bytes())
+	}
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			if err := dispatch(); err != nil {
+				return err
+			}
+			continue
+		}
+		value, ok := bytes.CutPrefix(line, []byte("data:"))
+		if !ok {
+			continue
+		}
+		if data.Len() > 0 {
+			data.WriteByte('\n')
+		}
+		data.Write(bytes.TrimPrefix(value, []byte(" ")))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := dispatch(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// errStopSSE is returned by a readSSE handler once the stream is complete.
+var errStopSSE = errors.New("stop reading events")
+
+func (e *AIExecutor) streamOpenAI(ctx context.Context, config AIConfig, messages []AIMessage, progress *aiStreamProgress, logs *[]LogEntry) (AIResponse, error) {
+	var response AIResponse
+	response.Provider = "openai"
+
+	model := config.Model
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	response.Model = model
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Streaming from OpenAI API with model %s", model),
+	})
+
+	req, err := newOpenAIRequest(ctx, config, model, messages, true)
+	if err != nil {
+		return response, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return response, openAIError(respBody)
+	}
+
+	err = readSSE(resp.Body, func(data []byte) error {
+		if string(data) == "[DONE]" {
+			return errStopSSE
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+				FinishReason string `json:"finish_reason"`
+			} `json:"choices"`
+			Usage *struct {
+				PromptTokens     int `json:"prompt_tokens"`
+				CompletionTokens int `json:"completion_tokens"`
+				TotalTokens      int `json:"total_tokens"`
+			} `json:"usage"`
+			Error *struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return fmt.Errorf("failed to parse OpenAI stream event: %w", err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("OpenAI API error: %s (%s)", chunk.Error.Message, chunk.Error.Type)
+		}
+
+		if len(chunk.Choices) > 0 {
+			progress.add(chunk.Choices[0].Delta.Content)
+			if chunk.Choices[0].FinishReason != "" {
+				response.FinishReason = chunk.Choices[0].FinishReason
+			}
+		}
+		// With include_usage the last chunk carries the usage and no choices.
+		if chunk.Usage != nil {
+			response.Usage = AIUsage{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+		return nil
+	})
+	if !errors.Is(err, errStopSSE) {
+		return response, fmt.Errorf("failed to read completion stream: %w", err)
+	}
+
+	response.Content = progress.content()
+	return response, nil
+}
+
+func (e *AIExecutor) streamAnthropic(ctx context.Context, config AIConfig, messages []AIMessage, progress *aiStreamProgress, logs *[]LogEntry) (AIResponse, error) {
+	var response AIResponse
+	response.Provider = "anthropic"
+
+	model := config.Model
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	response.Model = model
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Streaming from Anthropic API with model %s", model),
+	})
+
+	req, err := newAnthropicRequest(ctx, config, model, messages, true)
+	if err != nil {
+		return response, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return response, anthropicError(respBody)
+	}
+
+	// Every Anthropic event repeats its name as the type field of its data.
+	err = readSSE(resp.Body, func(data []byte) error {
+		var event struct {
+			Type    string `json:"type"`
+			Message struct {
+				Usage struct {
+					InputTokens int `json:"input_tokens"`
+				} `json:"usage"`
+			} `json:"message"`
+			Delta struct {
+				Type       string `json:"type"`
+				Text       string `json:"text"`
+				StopReason string `json:"stop_reason"`
+			} `json:"delta"`
+			Usage struct {
+				OutputTokens int `json:"output_tokens"`
+			} `json:"usage"`
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to parse Anthropic stream event: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			response.Usage.PromptTokens = event.Message.Usage.InputTokens
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" {
+				progress.add(event.Delta.Text)
+			}
+		case "message_delta":
+			if event.Delta.StopReason != "" {
+				response.FinishReason = event.Delta.StopReason
+			}
+			response.Usage.CompletionTokens = event.Usage.OutputTokens
+		case "message_stop":
+			return errStopSSE
+		case "error":
+			return fmt.Errorf("Anthropic API error: %s (%s)", event.Error.Message, event.Error.Type)
+		}
+		return nil
+	})
+	if !errors.Is(err, errStopSSE) {
+		return response, fmt.Errorf("failed to read completion stream: %w", err)
+	}
+
+	response.Content = progress.content()
+	response.Usage.TotalTokens = response.Usage.PromptTokens + response.Usage.CompletionTokens
+	return response, nil
+}
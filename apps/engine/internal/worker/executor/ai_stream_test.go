@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAIExecutorStreamsOpenAICompletion(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["stream"] != true {
+			t.Errorf("stream = %v, want true", payload["stream"])
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"Hel", "lo", " world"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q},\"finish_reason\":null}]}\n\n", token)
+		}
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":4,\"completion_tokens\":3,\"total_tokens\":7}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	config, _ := json.Marshal(AIConfig{
+		Provider: "openai",
+		APIKey:   "test",
+		Prompt:   "Say hello",
+		Stream:   true,
+		Endpoint: server.URL,
+	})
+	var partials []string
+	resp, err := NewAIExecutor().Execute(context.Background(), &ExecuteRequest{
+		NodeID:   "ai-1",
+		NodeType: "ai",
+		Config:   config,
+		Progress: func(progress int, partial string) {
+			if progress < aiStreamMinProgress || progress > aiStreamMaxProgress {
+				t.Errorf("progress = %d, want between %d and %d", progress, aiStreamMinProgress, aiStreamMaxProgress)
+			}
+			partials = append(partials, partial)
+		},
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("Execute() = %v, %+v", err, resp.Error)
+	}
+
+	var out AIResponse
+	if err := json.Unmarshal(resp.Output, &out); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if out.Content != "Hello world" || out.FinishReason != "stop" || out.Usage.TotalTokens != 7 {
+		t.Fatalf("output = %+v, want the full completion with usage", out)
+	}
+	// Reports are throttled, so only the first token is reported here.
+	if len(partials) != 1 || partials[0] != "Hel" {
+		t.Fatalf("partials = %q, want [\"Hel\"]", partials)
+	}
+
+	// Replaying the recorded fixture returns the final completion without
+	// calling the provider.
+	if len(resp.DeterministicFixtures) != 1 {
+		t.Fatalf("fixtures = %d, want 1", len(resp.DeterministicFixtures))
+	}
+	replay, err := NewAIExecutor().Execute(context.Background(), &ExecuteRequest{
+		NodeID:        "ai-1",
+		NodeType:      "ai",
+		Config:        config,
+		Deterministic: &DeterministicContext{Mode: "replay", Fixtures: resp.DeterministicFixtures},
+	})
+	if err != nil || replay.Error != nil {
+		t.Fatalf("replay Execute() = %v, %+v", err, replay.Error)
+	}
+	if string(replay.Output) != string(resp.Output) || calls != 1 {
+		t.Fatalf("replay output = %s after %d calls, want the recorded output after 1 call", replay.Output, calls)
+	}
+}
+
+func TestReadSSEReportsTruncatedStream(t *testing.T) {
+	events := 0
+	err := readSSE(strings.NewReader("data: {\"type\":\"ping\"}\n\n"), func(data []byte) error {
+		events++
+		return nil
+	})
+	if events != 1 || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("readSSE() = %v after %d events, want io.ErrUnexpectedEOF after 1 event", err, events)
+	}
+}
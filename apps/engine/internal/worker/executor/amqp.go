@@ -0,0 +1,405 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	amqpDefaultMaxMessages = 10
+	amqpDefaultTimeout     = 5 * time.Second
+	amqpPollInterval       = 100 * time.Millisecond
+)
+
+// errAMQPUnroutable is returned when the broker returns a mandatory message
+// because no queue is bound for its routing key.
+var errAMQPUnroutable = errors.New("message is unroutable")
+
+// AMQPExecutor publishes to and consumes from RabbitMQ.
+type AMQPExecutor struct {
+	BaseExecutor
+
+	conns map[string]*amqp.Connection
+	mu    sync.Mutex
+}
+
+// AMQPConfig represents the configuration for an AMQP node.
+type AMQPConfig struct {
+	URL        string `json:"url"`
+	Exchange   string `json:"exchange"`
+	RoutingKey string `json:"routing_key"`
+	Queue      string `json:"queue"`
+	Operation  string `json:"operation"` // publish, consume
+
+	// Publish
+	Body        json.RawMessage        `json:"body"` // Strings are sent as-is, other values as JSON (default: node input)
+	ContentType string                 `json:"content_type"`
+	Headers     map[string]interface{} `json:"headers"`
+
+	// Consume
+	MaxMessages int    `json:"max_messages"` // Messages to drain (default 10)
+	Timeout     int    `json:"timeout"`      // Seconds to wait for the first message (default 5)
+	Ack         string `json:"ack"`          // ack (default), requeue, reject
+}
+
+// AMQPPublishResponse is the output of a publish operation.
+type AMQPPublishResponse struct {
+	Published   bool   `json:"published"`
+	Confirmed   bool   `json:"confirmed"`
+	Exchange    string `json:"exchange"`
+	RoutingKey  string `json:"routing_key"`
+	DeliveryTag uint64 `json:"delivery_tag"`
+}
+
+// AMQPMessage is a message drained by a consume operation.
+type AMQPMessage struct {
+	Body        interface{}            `json:"body"`
+	Exchange    string                 `json:"exchange"`
+	RoutingKey  string                 `json:"routing_key"`
+	ContentType string                 `json:"content_type,omitempty"`
+	MessageID   string                 `json:"message_id,omitempty"`
+	Headers     map[string]interface{} `json:"headers,omitempty"`
+	Redelivered bool                   `json:"redelivered"`
+	Timestamp   *time.Time             `json:"timestamp,omitempty"`
+}
+
+// AMQPConsumeResponse is the output of a consume operation.
+type AMQPConsumeResponse struct {
+	Queue    string        `json:"queue"`
+	Count    int           `json:"count"`
+	Messages []AMQPMessage `json:"messages"`
+	Ack      string        `json:"ack"`
+}
+
+// NewAMQPExecutor creates a new AMQP executor.
+func NewAMQPExecutor() *AMQPExecutor {
+	return &AMQPExecutor{
+		conns: make(map[string]*amqp.Connection),
+	}
+}
+
+// Close closes all cached broker connections.
+func (e *AMQPExecutor) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for url, conn := range e.conns {
+		_ = conn.Close()
+		delete(e.conns, url)
+	}
+}
+
+func (e *AMQPExecutor) NodeType() string {
+	return "action_amqp"
+}
+
+func (e *AMQPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: errorType},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config AMQPConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse amqp config: %v", err), ErrorTypeNonRetryable)
+	}
+	if err := config.validate(); err != nil {
+		return failed(err.Error(), ErrorTypeNonRetryable)
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Starting AMQP %s for node %s", config.Operation, req.NodeID),
+	})
+
+	ch, err := e.channel(ctx, config.URL)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to open amqp channel: %v", err), amqpErrorType(err))
+	}
+	defer ch.Close()
+
+	var result interface{}
+	switch config.Operation {
+	case "publish":
+		result, err = e.publish(ctx, ch, config, req.Input, &logs)
+	case "consume":
+		result, err = e.consume(ctx, ch, config, &logs)
+	}
+	if err != nil {
+		return failed(err.Error(), amqpErrorType(err))
+	}
+
+	output, err := json.Marshal(result)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err), ErrorTypeNonRetryable)
+	}
+
+	return &ExecuteResponse{
+		Output:   output,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+func (c *AMQPConfig) validate() error {
+	if c.URL == "" {
+		return errors.New("url is required")
+	}
+	switch c.Operation {
+	case "publish":
+		if c.Exchange == "" && c.RoutingKey == "" && c.Queue == "" {
+			return errors.New("publish requires an exchange, routing_key or queue")
+		}
+	case "consume":
+		if c.Queue == "" {
+			return errors.New("consume requires a queue")
+		}
+		switch c.Ack {
+		case "", "ack", "requeue", "reject":
+		default:
+			return fmt.Errorf("unknown ack mode: %s", c.Ack)
+		}
+	default:
+		return fmt.Errorf("unknown operation: %s", c.Operation)
+	}
+	return nil
+}
+
+// channel opens a channel on a cached connection, redialing when the broker
+// closed the previous one.
+func (e *AMQPExecutor) channel(ctx context.Context, url string) (*amqp.Channel, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	conn, ok := e.conns[url]
+	if !ok || conn.IsClosed() {
+		dialer := &net.Dialer{Timeout: 10 * time.Second}
+		var err error
+		conn, err = amqp.DialConfig(url, amqp.Config{
+			Heartbeat: 10 * time.Second,
+			Dial: func(network, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+		})
+		if err != nil {
+			delete(e.conns, url)
+			return nil, err
+		}
+		e.conns[url] = conn
+	}
+	return conn.Channel()
+}
+
+func (e *AMQPExecutor) publish(ctx context.Context, ch *amqp.Channel, config AMQPConfig, input json.RawMessage, logs *[]LogEntry) (*AMQPPublishResponse, error) {
+	routingKey := config.RoutingKey
+	if routingKey == "" && config.Exchange == "" {
+		// The default exchange routes by queue name.
+		routingKey = config.Queue
+	}
+
+	body, contentType := amqpBody(config.Body, input)
+	if config.ContentType != "" {
+		contentType = config.ContentType
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	returns := ch.NotifyReturn(make(chan amqp.Return, 1))
+
+	ctx, cancel := context.WithTimeout(ctx, amqpDefaultTimeout)
+	defer cancel()
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, config.Exchange, routingKey, true, false, amqp.Publishing{
+		ContentType:  contentType,
+		Headers:      amqpTable(config.Headers),
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("publish failed: %w", err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("publish confirmation failed: %w", err)
+	}
+
+	// The broker returns an unroutable mandatory message before confirming it.
+	select {
+	case ret := <-returns:
+		return nil, fmt.Errorf("%w: %d %s (exchange %q, routing key %q)",
+			errAMQPUnroutable, ret.ReplyCode, ret.ReplyText, ret.Exchange, ret.RoutingKey)
+	default:
+	}
+	if !acked {
+		return nil, errors.New("broker rejected the message")
+	}
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Published %d bytes to exchange %q with routing key %q", len(body), config.Exchange, routingKey),
+	})
+
+	return &AMQPPublishResponse{
+		Published:   true,
+		Confirmed:   true,
+		Exchange:    config.Exchange,
+		RoutingKey:  routingKey,
+		DeliveryTag: confirmation.DeliveryTag,
+	}, nil
+}
+
+// consume drains up to MaxMessages from the queue. It waits up to Timeout for
+// the first message and returns as soon as the queue is empty after that.
+func (e *AMQPExecutor) consume(ctx context.Context, ch *amqp.Channel, config AMQPConfig, logs *[]LogEntry) (*AMQPConsumeResponse, error) {
+	maxMessages := config.MaxMessages
+	if maxMessages <= 0 {
+		maxMessages = amqpDefaultMaxMessages
+	}
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = amqpDefaultTimeout
+	}
+	ackMode := config.Ack
+	if ackMode == "" {
+		ackMode = "ack"
+	}
+
+	deadline := time.Now().Add(timeout)
+	response := &AMQPConsumeResponse{Queue: config.Queue, Messages: []AMQPMessage{}, Ack: ackMode}
+	var lastTag uint64
+
+	for len(response.Messages) < maxMessages {
+		delivery, ok, err := ch.Get(config.Queue, false)
+		if err != nil {
+			return nil, fmt.Errorf("consume failed: %w", err)
+		}
+		if !ok {
+			if len(response.Messages) > 0 || time.Now().After(deadline) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(amqpPollInterval):
+			}
+			continue
+		}
+
+		lastTag = delivery.DeliveryTag
+		response.Messages = append(response.Messages, amqpMessage(delivery))
+	}
+
+	if lastTag > 0 {
+		var err error
+		switch ackMode {
+		case "ack":
+			err = ch.Ack(lastTag, true)
+		case "requeue":
+			err = ch.Nack(lastTag, true, true)
+		case "reject":
+			err = ch.Nack(lastTag, true, false)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to %s messages: %w", ackMode, err)
+		}
+	}
+	response.Count = len(response.Messages)
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Consumed %d messages from queue %q (%s)", response.Count, config.Queue, ackMode),
+	})
+
+	return response, nil
+}
+
+// amqpBody encodes the publish body. A JSON string is sent as its text;
+// anything else is sent as JSON.
+func amqpBody(body, input json.RawMessage) ([]byte, string) {
+	if len(body) == 0 {
+		body = input
+	}
+	var text string
+	if err := json.Unmarshal(body, &text); err == nil {
+		return []byte(text), "text/plain"
+	}
+	return body, "application/json"
+}
+
+// amqpTable converts decoded JSON headers to a header table; nested objects
+// must be tables themselves.
+func amqpTable(headers map[string]interface{}) amqp.Table {
+	if headers == nil {
+		return nil
+	}
+	table := make(amqp.Table, len(headers))
+	for k, v := range headers {
+		if nested, ok := v.(map[string]interface{}); ok {
+			table[k] = amqpTable(nested)
+			continue
+		}
+		table[k] = v
+	}
+	return table
+}
+
+func amqpMessage(d amqp.Delivery) AMQPMessage {
+	msg := AMQPMessage{
+		Exchange:    d.Exchange,
+		RoutingKey:  d.RoutingKey,
+		ContentType: d.ContentType,
+		MessageID:   d.MessageId,
+		Headers:     d.Headers,
+		Redelivered: d.Redelivered,
+	}
+	if !d.Timestamp.IsZero() {
+		ts := d.Timestamp
+		msg.Timestamp = &ts
+	}
+
+	var body interface{}
+	if json.Unmarshal(d.Body, &body) == nil {
+		msg.Body = body
+	} else {
+		msg.Body = string(d.Body)
+	}
+	return msg
+}
+
+// amqpErrorType classifies broker errors. Missing or mismatched resources,
+// refused access and unroutable messages need a config change; connection and
+// channel failures are transient.
+func amqpErrorType(err error) string {
+	if errors.Is(err, errAMQPUnroutable) {
+		return ErrorTypeNonRetryable
+	}
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		switch amqpErr.Code {
+		case amqp.NotFound, amqp.PreconditionFailed, amqp.AccessRefused, amqp.NotAllowed, amqp.NoRoute:
+			return ErrorTypeNonRetryable
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTypeTimeout
+	}
+	return ErrorTypeRetryable
+}
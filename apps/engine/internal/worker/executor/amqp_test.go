@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestAMQPExecutorRejectsInvalidConfig(t *testing.T) {
+	e := NewAMQPExecutor()
+	for _, config := range []string{
+		`{"operation":"publish","exchange":"events"}`,
+		`{"url":"amqp://localhost","operation":"consume"}`,
+		`{"url":"amqp://localhost","operation":"consume","queue":"q","ack":"maybe"}`,
+		`{"url":"amqp://localhost","operation":"delete","queue":"q"}`,
+	} {
+		resp, err := e.Execute(context.Background(), &ExecuteRequest{NodeID: "mq-1", Config: json.RawMessage(config)})
+		if err != nil {
+			t.Fatalf("Execute error: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+			t.Fatalf("config %s: expected non-retryable error, got %+v", config, resp.Error)
+		}
+	}
+}
+
+func TestAMQPErrorType(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("publish failed: %w", &amqp.Error{Code: amqp.NotFound, Reason: "no exchange 'events'"}), ErrorTypeNonRetryable},
+		{&amqp.Error{Code: amqp.PreconditionFailed, Reason: "inequivalent arg 'durable'"}, ErrorTypeNonRetryable},
+		{fmt.Errorf("%w: 312 NO_ROUTE", errAMQPUnroutable), ErrorTypeNonRetryable},
+		{amqp.ErrClosed, ErrorTypeRetryable},
+		{&amqp.Error{Code: amqp.ConnectionForced, Reason: "broker shutdown"}, ErrorTypeRetryable},
+		{context.DeadlineExceeded, ErrorTypeTimeout},
+	}
+	for _, c := range cases {
+		if got := amqpErrorType(c.err); got != c.want {
+			t.Errorf("amqpErrorType(%v) = %s, want %s", c.err, got, c.want)
+		}
+	}
+}
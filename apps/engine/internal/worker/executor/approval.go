@@ -5,16 +5,43 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/linkflow/engine/internal/approval"
 )
 
-// ApprovalExecutor creates a deterministic pause point for human-in-the-loop review.
-// It intentionally returns a non-retryable approval-required error so the API can
-// generate an inbox item and resume using a new execution once approved.
-type ApprovalExecutor struct{}
+// defaultApprovalTimeout is how long an approval node waits for a decision
+// when its config sets no timeout.
+const defaultApprovalTimeout = 24 * time.Hour
+
+// maxApprovalTitleLength matches the approvals.title column.
+const maxApprovalTitleLength = 255
+
+// ApprovalExecutor pauses a node until an approver decides on it. The node
+// waits as an async activity and an approval request with its completion
+// token is stored; the frontend approval endpoints complete the node with the
+// decision, which history records with who decided and when. Approval takes
+// the node's "approved" branch. Rejection, or no decision before the timeout,
+// fails the node, or with on_reject "continue" completes it on its "rejected"
+// branch.
+//
+// Without a store the executor keeps its original behaviour: it fails with
+// a non-retryable APPROVAL_REQUIRED error so the API can generate an inbox
+// item and resume using a new execution once approved.
+type ApprovalExecutor struct {
+	BaseExecutor
+
+	store approval.Store
+}
 
 type ApprovalConfig struct {
 	Title       string                 `json:"title"`
 	Description string                 `json:"description"`
+	Reason      string                 `json:"reason"`    // Shown to approvers; defaults to the description
+	Approvers   []string               `json:"approvers"` // Identities that may decide; empty allows anyone
+	Timeout     int                    `json:"timeout"`   // Seconds to wait for a decision (0 = 24h)
+	OnReject    string                 `json:"on_reject"` // fail (default) or continue
 	Payload     map[string]interface{} `json:"payload"`
 }
 
@@ -22,39 +49,157 @@ func NewApprovalExecutor() *ApprovalExecutor {
 	return &ApprovalExecutor{}
 }
 
+// WithStore sets the store approval requests are created in.
+func (e *ApprovalExecutor) WithStore(store approval.Store) *ApprovalExecutor {
+	e.store = store
+	return e
+}
+
 func (e *ApprovalExecutor) NodeType() string {
-	return "action_approval"
+	return "approval"
+}
+
+func (e *ApprovalExecutor) Aliases() []string {
+	return []string{"action_approval"}
+}
+
+var approvalInputSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "title": {"type": "string", "maxLength": 255},
+    "description": {"type": "string"},
+    "reason": {"type": "string", "description": "Why approval is needed, shown to approvers"},
+    "approvers": {"type": "array", "items": {"type": "string"}, "description": "Identities allowed to decide; empty allows anyone"},
+    "timeout": {"type": "integer", "minimum": 0, "description": "Seconds to wait for a decision, 0 for 24 hours"},
+    "on_reject": {"type": "string", "enum": ["fail", "continue"], "default": "fail"},
+    "payload": {"type": "object"}
+  }
+}`)
+
+var approvalOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["output", "approval_id", "approved"],
+  "properties": {
+    "output": {"type": "string", "enum": ["approved", "rejected"], "description": "Output branch to take"},
+    "approval_id": {"type": "string"},
+    "approved": {"type": "boolean"},
+    "decided_by": {"type": "string"},
+    "comment": {"type": "string"},
+    "decided_at": {"type": "string", "format": "date-time"},
+    "timed_out": {"type": "boolean"}
+  }
+}`)
+
+func (e *ApprovalExecutor) InputSchema() json.RawMessage {
+	return approvalInputSchema
+}
+
+func (e *ApprovalExecutor) OutputSchema() json.RawMessage {
+	return approvalOutputSchema
 }
 
 func (e *ApprovalExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
-	_ = ctx
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	fail := func(message string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: message,
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
 
 	config := ApprovalConfig{}
-	_ = json.Unmarshal(req.Config, &config)
+	if len(req.Config) > 0 {
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			return fail(fmt.Sprintf("failed to parse approval config: %v", err))
+		}
+	}
 
 	title := config.Title
 	if title == "" {
 		title = "Approval required"
 	}
 
-	message := fmt.Sprintf("APPROVAL_REQUIRED: %s", title)
-	if config.Description != "" {
-		message = fmt.Sprintf("%s - %s", message, config.Description)
+	if e.store == nil {
+		message := fmt.Sprintf("APPROVAL_REQUIRED: %s", title)
+		if config.Description != "" {
+			message = fmt.Sprintf("%s - %s", message, config.Description)
+		}
+		logs = append(logs, LogEntry{
+			Timestamp: time.Now().UTC(),
+			Level:     "INFO",
+			Message:   "approval checkpoint reached",
+		})
+		return fail(message)
+	}
+
+	if len(title) > maxApprovalTitleLength {
+		return fail(fmt.Sprintf("title exceeds %d characters", maxApprovalTitleLength))
+	}
+	if config.Timeout < 0 {
+		return fail("timeout must not be negative")
+	}
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = defaultApprovalTimeout
+	}
+	reason := config.Reason
+	if reason == "" {
+		reason = config.Description
+	}
+
+	id := uuid.NewString()
+	pending := &PendingActivity{ScheduleToCloseTimeout: timeout}
+	switch config.OnReject {
+	case "", approval.OnRejectFail:
+		config.OnReject = approval.OnRejectFail
+	case approval.OnRejectContinue:
+		pending.TimeoutResult, _ = json.Marshal(approval.TimeoutResult(id))
+	default:
+		return fail(fmt.Sprintf("unknown on_reject: %s", config.OnReject))
+	}
+
+	var payload json.RawMessage
+	if config.Payload != nil {
+		payload, _ = json.Marshal(config.Payload)
+	}
+	// The request is created once history holds the node open, since only
+	// then is there a token to complete it with. If creating it fails the
+	// node stays pending until it times out.
+	pending.OnPending = func(ctx context.Context, taskToken string) error {
+		now := time.Now()
+		return e.store.CreateApproval(ctx, &approval.Approval{
+			ID:         id,
+			Namespace:  req.Namespace,
+			WorkflowID: req.WorkflowID,
+			RunID:      req.RunID,
+			NodeID:     req.NodeID,
+			TaskToken:  taskToken,
+			Title:      title,
+			Reason:     reason,
+			Approvers:  config.Approvers,
+			Payload:    payload,
+			OnReject:   config.OnReject,
+			Status:     approval.StatusPending,
+			CreatedAt:  now,
+			ExpiresAt:  now.Add(timeout),
+		})
 	}
 
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Node %s waiting for approval %s", req.NodeID, id),
+	})
+
 	return &ExecuteResponse{
-		Output: nil,
-		Error: &ExecutionError{
-			Message: message,
-			Type:    ErrorTypeNonRetryable,
-		},
-		Logs: []LogEntry{
-			{
-				Timestamp: time.Now().UTC(),
-				Level:     "INFO",
-				Message:   "approval checkpoint reached",
-			},
-		},
-		Duration: 0,
+		Pending:  pending,
+		Logs:     logs,
+		Duration: time.Since(start),
 	}, nil
 }
@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/approval"
+)
+
+type recordingApprovalStore struct {
+	approval.Store
+	created []*approval.Approval
+}
+
+func (s *recordingApprovalStore) CreateApproval(_ context.Context, a *approval.Approval) error {
+	s.created = append(s.created, a)
+	return nil
+}
+
+func approvalRequest(config string) *ExecuteRequest {
+	return &ExecuteRequest{
+		NodeType:   "approval",
+		NodeID:     "approve-1",
+		WorkflowID: "wf-1",
+		RunID:      "run-1",
+		Namespace:  "default",
+		Config:     json.RawMessage(config),
+	}
+}
+
+func TestApprovalExecutorWaitsForDecision(t *testing.T) {
+	store := &recordingApprovalStore{}
+	e := NewApprovalExecutor().WithStore(store)
+
+	resp, err := e.Execute(context.Background(), approvalRequest(`{"title": "Refund", "reason": "over limit", "approvers": ["alice"], "timeout": 3600}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error.Message)
+	}
+	if resp.Pending == nil || resp.Pending.OnPending == nil {
+		t.Fatal("expected a pending activity")
+	}
+	if resp.Pending.ScheduleToCloseTimeout != time.Hour {
+		t.Fatalf("timeout = %v, want 1h", resp.Pending.ScheduleToCloseTimeout)
+	}
+	if resp.Pending.TimeoutResult != nil {
+		t.Fatal("expected a timeout to fail the node by default")
+	}
+
+	if err := resp.Pending.OnPending(context.Background(), "token-1"); err != nil {
+		t.Fatalf("OnPending error: %v", err)
+	}
+	if len(store.created) != 1 {
+		t.Fatalf("expected one approval, got %d", len(store.created))
+	}
+	created := store.created[0]
+	if created.TaskToken != "token-1" || created.NodeID != "approve-1" || created.RunID != "run-1" {
+		t.Fatalf("approval not tied to the node: %+v", created)
+	}
+	if created.Status != approval.StatusPending || created.OnReject != approval.OnRejectFail {
+		t.Fatalf("status %s on_reject %s, want pending and fail", created.Status, created.OnReject)
+	}
+	if created.ExpiresAt.Sub(created.CreatedAt) != time.Hour {
+		t.Fatalf("expiry %v after creation, want 1h", created.ExpiresAt.Sub(created.CreatedAt))
+	}
+	if !created.CanDecide("alice") || created.CanDecide("bob") {
+		t.Fatal("expected only alice to be allowed to decide")
+	}
+}
+
+func TestApprovalExecutorContinueTimesOutOnRejectedBranch(t *testing.T) {
+	e := NewApprovalExecutor().WithStore(&recordingApprovalStore{})
+
+	resp, err := e.Execute(context.Background(), approvalRequest(`{"on_reject": "continue"}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Pending == nil {
+		t.Fatal("expected a pending activity")
+	}
+	if resp.Pending.ScheduleToCloseTimeout != defaultApprovalTimeout {
+		t.Fatalf("timeout = %v, want the default", resp.Pending.ScheduleToCloseTimeout)
+	}
+	var result approval.Result
+	if err := json.Unmarshal(resp.Pending.TimeoutResult, &result); err != nil {
+		t.Fatalf("timeout result is not JSON: %v", err)
+	}
+	if result.Output != approval.BranchRejected || !result.TimedOut || result.ApprovalID == "" {
+		t.Fatalf("timeout result = %+v, want timed out on the rejected branch", result)
+	}
+}
+
+func TestApprovalExecutorRejectsInvalidConfig(t *testing.T) {
+	e := NewApprovalExecutor().WithStore(&recordingApprovalStore{})
+
+	for _, config := range []string{
+		`{"timeout": -1}`,
+		`{"on_reject": "retry"}`,
+		`{"title": "` + strings.Repeat("x", 256) + `"}`,
+	} {
+		resp, err := e.Execute(context.Background(), approvalRequest(config))
+		if err != nil {
+			t.Fatalf("Execute error: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+			t.Errorf("config %s: expected a non-retryable error, got %+v", config, resp.Error)
+		}
+	}
+}
+
+func TestApprovalExecutorWithoutStore(t *testing.T) {
+	resp, err := NewApprovalExecutor().Execute(context.Background(), approvalRequest(`{"title": "Refund"}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Pending != nil {
+		t.Fatal("expected no pending activity without a store")
+	}
+	if resp.Error == nil || !strings.HasPrefix(resp.Error.Message, "APPROVAL_REQUIRED: Refund") {
+		t.Fatalf("expected the APPROVAL_REQUIRED error, got %+v", resp.Error)
+	}
+}
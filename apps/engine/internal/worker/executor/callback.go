@@ -0,0 +1,153 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// AsyncCallbackExecutor pauses a node until an external system reports its
+// result through the async-activity endpoints. The completion token is
+// optionally POSTed to a notify URL so third-party jobs or approval tools know
+// where to report back.
+type AsyncCallbackExecutor struct {
+	BaseExecutor
+
+	client  *http.Client
+	baseURL string
+}
+
+// AsyncCallbackConfig represents the configuration for a wait_callback node.
+type AsyncCallbackConfig struct {
+	NotifyURL string                 `json:"notify_url"` // Receives the completion token (optional)
+	Payload   map[string]interface{} `json:"payload"`    // Extra data sent with the notification
+	Timeout   int                    `json:"timeout"`    // ScheduleToClose timeout in seconds (0 = worker default)
+}
+
+// NewAsyncCallbackExecutor creates a new async callback executor.
+func NewAsyncCallbackExecutor() *AsyncCallbackExecutor {
+	return &AsyncCallbackExecutor{
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: newSSRFSafeTransport(),
+		},
+		baseURL: strings.TrimRight(os.Getenv("ASYNC_CALLBACK_BASE_URL"), "/"),
+	}
+}
+
+// WithBaseURL sets the public frontend URL used to build callback links.
+func (e *AsyncCallbackExecutor) WithBaseURL(baseURL string) *AsyncCallbackExecutor {
+	e.baseURL = strings.TrimRight(baseURL, "/")
+	return e
+}
+
+func (e *AsyncCallbackExecutor) NodeType() string {
+	return "wait_callback"
+}
+
+func (e *AsyncCallbackExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	var config AsyncCallbackConfig
+	if len(req.Config) > 0 {
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: fmt.Sprintf("failed to parse wait_callback config: %v", err),
+					Type:    ErrorTypeNonRetryable,
+				},
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+	}
+
+	if config.Timeout < 0 {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: "timeout must not be negative",
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	if config.NotifyURL != "" {
+		parsed, err := url.Parse(config.NotifyURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: "notify_url must be an http or https URL",
+					Type:    ErrorTypeNonRetryable,
+				},
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Node %s waiting for external callback", req.NodeID),
+	})
+
+	pending := &PendingActivity{
+		ScheduleToCloseTimeout: time.Duration(config.Timeout) * time.Second,
+	}
+	if config.NotifyURL != "" {
+		pending.OnPending = func(ctx context.Context, taskToken string) error {
+			return e.notify(ctx, config, req, taskToken)
+		}
+	}
+
+	return &ExecuteResponse{
+		Pending:  pending,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+func (e *AsyncCallbackExecutor) notify(ctx context.Context, config AsyncCallbackConfig, req *ExecuteRequest, taskToken string) error {
+	escaped := url.PathEscape(taskToken)
+	body, err := json.Marshal(map[string]interface{}{
+		"task_token":   taskToken,
+		"workflow_id":  req.WorkflowID,
+		"run_id":       req.RunID,
+		"node_id":      req.NodeID,
+		"complete_url": e.baseURL + "/api/v1/async-activities/" + escaped + "/complete",
+		"fail_url":     e.baseURL + "/api/v1/async-activities/" + escaped + "/fail",
+		"input":        req.Input,
+		"payload":      config.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, config.NotifyURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("notification request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("notify_url returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 )
 
 type CodeExecutor struct {
+	BaseExecutor
+
 	// For future WASM/container execution
 }
 
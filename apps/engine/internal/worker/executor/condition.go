@@ -70,6 +70,73 @@ func (e *ConditionExecutor) NodeType() string {
 	return "condition"
 }
 
+func (e *ConditionExecutor) Aliases() []string {
+	return []string{"logic_condition"}
+}
+
+var conditionInputSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "mode": {"type": "string", "enum": ["if", "switch", "expression"], "default": "if"},
+    "conditions": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["field", "operator"],
+        "properties": {
+          "field": {"type": "string"},
+          "operator": {"type": "string", "examples": ["eq", "ne", "gt", "lt", "gte", "lte", "contains", "startsWith", "endsWith", "matches", "in", "empty", "exists"]},
+          "value": {},
+          "output": {"type": "string"}
+        }
+      }
+    },
+    "switch_value": {"type": "string"},
+    "cases": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "value": {},
+          "output": {"type": "string"}
+        }
+      }
+    },
+    "default_output": {"type": "string"},
+    "expression": {"type": "string"}
+  }
+}`)
+
+var conditionOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["matched", "output", "matched_rule"],
+  "properties": {
+    "matched": {"type": "boolean"},
+    "output": {"type": "string", "description": "Output branch to take"},
+    "matched_rule": {"type": "integer", "description": "Index of the matched condition, -1 for the default branch"},
+    "eval_results": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "index": {"type": "integer"},
+          "field": {"type": "string"},
+          "result": {"type": "boolean"},
+          "message": {"type": "string"}
+        }
+      }
+    }
+  }
+}`)
+
+func (e *ConditionExecutor) InputSchema() json.RawMessage {
+	return conditionInputSchema
+}
+
+func (e *ConditionExecutor) OutputSchema() json.RawMessage {
+	return conditionOutputSchema
+}
+
 func (e *ConditionExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
 	start := time.Now()
 	logs := make([]LogEntry, 0)
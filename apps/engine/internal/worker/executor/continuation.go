@@ -0,0 +1,29 @@
+package executor
+
+import (
+	"encoding/json"
+)
+
+// nodeContinuationKey marks a node result that is not final: the workflow
+// schedules the node again with the result as its input.
+const nodeContinuationKey = "__continue"
+
+// NewNodeContinuation encodes state as a node result that makes the workflow
+// run the node again, passing the result back as the node's input.
+func NewNodeContinuation(state interface{}) (json.RawMessage, error) {
+	return json.Marshal(map[string]interface{}{nodeContinuationKey: state})
+}
+
+// ParseNodeContinuation returns the state carried by a continuation result,
+// reporting false for any other data.
+func ParseNodeContinuation(data []byte) (json.RawMessage, bool) {
+	if len(data) == 0 || data[0] != '{' {
+		return nil, false
+	}
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, false
+	}
+	state, ok := wrapper[nodeContinuationKey]
+	return state, ok
+}
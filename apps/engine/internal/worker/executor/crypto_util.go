@@ -0,0 +1,250 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// cryptoUtilHashes are the algorithms hash and hmac accept.
+var cryptoUtilHashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+var errCryptoUtilInputMissing = errors.New("value or field is required")
+
+// CryptoUtilExecutor encodes, decodes and hashes values, so small data
+// munging steps don't need a script node. It only transforms its input;
+// uuid_generate draws from the deterministic context so replays see the same
+// IDs.
+type CryptoUtilExecutor struct{}
+
+// CryptoUtilConfig represents the configuration for a crypto_util node.
+type CryptoUtilConfig struct {
+	Operation string `json:"operation"` // base64_encode, base64_decode, hex_encode, hex_decode, hash, hmac, uuid_generate
+
+	// The value to transform: the literal value, or else the input field
+	// (dot notation) named by field. Non-string fields are transformed as
+	// their JSON encoding.
+	Value *string `json:"value"`
+	Field string  `json:"field"`
+
+	Algorithm string `json:"algorithm"` // hash, hmac: md5, sha1, sha256, sha512 (default sha256)
+	Key       string `json:"key"`       // hmac: the signing key, usually a {"$secret": ...} reference
+	Encoding  string `json:"encoding"`  // hash, hmac: hex or base64 digest (default hex)
+	URLSafe   bool   `json:"url_safe"`  // base64: use the URL-safe alphabet without padding
+}
+
+// CryptoUtilResponse is the output of a crypto_util node.
+type CryptoUtilResponse struct {
+	Operation string `json:"operation"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Value     string `json:"value"`
+}
+
+// NewCryptoUtilExecutor creates a new crypto_util executor.
+func NewCryptoUtilExecutor() *CryptoUtilExecutor {
+	return &CryptoUtilExecutor{}
+}
+
+func (e *CryptoUtilExecutor) NodeType() string {
+	return "crypto_util"
+}
+
+var cryptoUtilInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["operation"],
+  "properties": {
+    "operation": {"type": "string", "enum": ["base64_encode", "base64_decode", "hex_encode", "hex_decode", "hash", "hmac", "uuid_generate"]},
+    "value": {"type": "string", "description": "Literal value to transform"},
+    "field": {"type": "string", "description": "Input field (dot notation) to transform when value is not set"},
+    "algorithm": {"type": "string", "enum": ["md5", "sha1", "sha256", "sha512"], "default": "sha256"},
+    "key": {"type": "string", "description": "HMAC signing key"},
+    "encoding": {"type": "string", "enum": ["hex", "base64"], "default": "hex"},
+    "url_safe": {"type": "boolean", "default": false}
+  }
+}`)
+
+var cryptoUtilOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["operation", "value"],
+  "properties": {
+    "operation": {"type": "string"},
+    "algorithm": {"type": "string"},
+    "value": {"type": "string"}
+  }
+}`)
+
+func (e *CryptoUtilExecutor) InputSchema() json.RawMessage {
+	return cryptoUtilInputSchema
+}
+
+func (e *CryptoUtilExecutor) OutputSchema() json.RawMessage {
+	return cryptoUtilOutputSchema
+}
+
+// UsesSecrets reports that the hmac key may reference a secret.
+func (e *CryptoUtilExecutor) UsesSecrets() bool {
+	return true
+}
+
+func (e *CryptoUtilExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: ErrorTypeNonRetryable},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config CryptoUtilConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse crypto_util config: %v", err))
+	}
+
+	result := CryptoUtilResponse{Operation: config.Operation}
+	if config.Operation == "uuid_generate" {
+		result.Value = req.Deterministic.UUID()
+	} else {
+		input, err := config.input(req.Input)
+		if err != nil {
+			return failed(err.Error())
+		}
+		switch config.Operation {
+		case "base64_encode":
+			result.Value = config.base64Encoding().EncodeToString([]byte(input))
+		case "base64_decode":
+			// Encoders disagree on padding, so accept input with or without it.
+			decoded, err := config.base64Encoding().WithPadding(base64.NoPadding).DecodeString(strings.TrimRight(input, "="))
+			if err != nil {
+				return failed(fmt.Sprintf("invalid base64: %v", err))
+			}
+			if result.Value, err = cryptoUtilText(decoded); err != nil {
+				return failed(err.Error())
+			}
+		case "hex_encode":
+			result.Value = hex.EncodeToString([]byte(input))
+		case "hex_decode":
+			decoded, err := hex.DecodeString(input)
+			if err != nil {
+				return failed(fmt.Sprintf("invalid hex: %v", err))
+			}
+			if result.Value, err = cryptoUtilText(decoded); err != nil {
+				return failed(err.Error())
+			}
+		case "hash", "hmac":
+			algorithm := config.Algorithm
+			if algorithm == "" {
+				algorithm = "sha256"
+			}
+			newHash, ok := cryptoUtilHashes[algorithm]
+			if !ok {
+				return failed(fmt.Sprintf("unsupported algorithm: %s (use md5, sha1, sha256 or sha512)", algorithm))
+			}
+			var h hash.Hash
+			if config.Operation == "hmac" {
+				if config.Key == "" {
+					return failed("key is required for hmac")
+				}
+				h = hmac.New(newHash, []byte(config.Key))
+			} else {
+				h = newHash()
+			}
+			h.Write([]byte(input))
+			result.Algorithm = algorithm
+			switch config.Encoding {
+			case "", "hex":
+				result.Value = hex.EncodeToString(h.Sum(nil))
+			case "base64":
+				result.Value = base64.StdEncoding.EncodeToString(h.Sum(nil))
+			default:
+				return failed(fmt.Sprintf("unsupported encoding: %s (use hex or base64)", config.Encoding))
+			}
+		default:
+			return failed(fmt.Sprintf("unknown operation: %s", config.Operation))
+		}
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("crypto_util %s completed for node %s", config.Operation, req.NodeID),
+	})
+
+	output, err := json.Marshal(result)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err))
+	}
+	return &ExecuteResponse{
+		Output:   output,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// input returns the value to transform: the literal value, or the field of
+// the node input it names. Strings are used as they are and other values as
+// their JSON encoding, with numbers kept as written.
+func (c *CryptoUtilConfig) input(raw json.RawMessage) (string, error) {
+	if c.Value != nil {
+		return *c.Value, nil
+	}
+	if c.Field == "" {
+		return "", errCryptoUtilInputMissing
+	}
+
+	var data map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return "", fmt.Errorf("field %s requires an object input: %v", c.Field, err)
+	}
+	value := getFieldValue(data, c.Field)
+	switch v := value.(type) {
+	case nil:
+		return "", fmt.Errorf("field %s not found in input", c.Field)
+	case string:
+		return v, nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode field %s: %v", c.Field, err)
+		}
+		return string(encoded), nil
+	}
+}
+
+func (c *CryptoUtilConfig) base64Encoding() *base64.Encoding {
+	if c.URLSafe {
+		return base64.RawURLEncoding
+	}
+	return base64.StdEncoding
+}
+
+// cryptoUtilText returns decoded bytes as a string, which the JSON output can
+// only carry faithfully when they are valid UTF-8.
+func cryptoUtilText(decoded []byte) (string, error) {
+	if !utf8.Valid(decoded) {
+		return "", errors.New("decoded value is not valid UTF-8 text")
+	}
+	return string(decoded), nil
+}
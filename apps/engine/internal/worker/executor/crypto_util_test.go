@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestCryptoUtilExecutorOperations(t *testing.T) {
+	executor := NewCryptoUtilExecutor()
+	run := func(config, input string) (*ExecuteResponse, CryptoUtilResponse) {
+		t.Helper()
+		resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+			NodeID: "crypto",
+			Config: json.RawMessage(config),
+			Input:  json.RawMessage(input),
+		})
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		var output CryptoUtilResponse
+		_ = json.Unmarshal(resp.Output, &output)
+		return resp, output
+	}
+
+	input := `{"user":{"email":"ada@example.com","id":42}}`
+	for _, tc := range []struct {
+		config string
+		want   string
+	}{
+		{`{"operation":"base64_encode","value":"hi?>"}`, "aGk/Pg=="},
+		{`{"operation":"base64_encode","value":"hi?>","url_safe":true}`, "aGk_Pg"},
+		{`{"operation":"base64_decode","value":"aGk/Pg"}`, "hi?>"},
+		{`{"operation":"hex_encode","field":"user.email"}`, "616461406578616d706c652e636f6d"},
+		{`{"operation":"hex_decode","value":"6869"}`, "hi"},
+		{`{"operation":"hash","value":"abc"}`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`{"operation":"hash","algorithm":"md5","value":"abc"}`, "900150983cd24fb0d6963f7d28e17f72"},
+		{`{"operation":"hash","algorithm":"sha1","field":"user.id","encoding":"base64"}`, "ks/Os51X2RTtixTQ43ZD3geXrlY="},
+		{`{"operation":"hmac","algorithm":"sha256","key":"key","value":"The quick brown fox jumps over the lazy dog"}`, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"},
+	} {
+		resp, output := run(tc.config, input)
+		if resp.Error != nil || output.Value != tc.want {
+			t.Errorf("%s = %q, %+v; want %q", tc.config, output.Value, resp.Error, tc.want)
+		}
+	}
+
+	// Replays hand back the UUID recorded for the node.
+	det := &DeterministicContext{Mode: "capture"}
+	det.BindNode("crypto", "crypto_util")
+	resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+		NodeID:        "crypto",
+		Config:        json.RawMessage(`{"operation":"uuid_generate"}`),
+		Deterministic: det,
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("uuid_generate: %v, %+v", err, resp.Error)
+	}
+	var generated CryptoUtilResponse
+	_ = json.Unmarshal(resp.Output, &generated)
+	fixture, ok := det.GeneratedFixture()
+	if !ok {
+		t.Fatal("uuid_generate recorded no fixture")
+	}
+	replay := &DeterministicContext{Mode: "replay", Fixtures: []DeterministicFixture{fixture}}
+	replay.BindNode("crypto", "crypto_util")
+	if got := replay.UUID(); got != generated.Value {
+		t.Fatalf("replayed UUID %s, want %s", got, generated.Value)
+	}
+
+	for _, config := range []string{
+		`{"operation":"encrypt","value":"x"}`,
+		`{"operation":"hash","algorithm":"sha3","value":"x"}`,
+		`{"operation":"hmac","value":"x"}`,
+		`{"operation":"hash","field":"user.missing"}`,
+		`{"operation":"hex_decode","value":"zz"}`,
+		`{"operation":"base64_decode","value":"/w"}`,
+		`{"operation":"base64_encode"}`,
+	} {
+		resp, _ := run(config, input)
+		if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+			t.Errorf("%s error = %+v, want non-retryable", config, resp.Error)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CSVExecutor converts between CSV text and arrays of objects, so
+// integrations exchanging CSV don't need a script node. Rows are read and
+// written one at a time rather than materializing the whole table.
+type CSVExecutor struct{}
+
+// CSVConfig represents the configuration for a transform_csv node.
+type CSVConfig struct {
+	Mode      string `json:"mode"`       // parse, generate
+	Delimiter string `json:"delimiter"`  // Single character (default ",")
+	HasHeader *bool  `json:"has_header"` // Parse: first row names the columns; generate: write a header row (default true)
+
+	// Columns orders the generated columns (default: the first row's keys,
+	// sorted). When parsing CSV without a header row it names the columns,
+	// which otherwise default to column_1, column_2, ...
+	Columns []string `json:"columns"`
+
+	// Parse
+	CSV        *string `json:"csv"`         // CSV text (default: node input, a JSON string)
+	InferTypes bool    `json:"infer_types"` // Convert numbers and booleans (default: all values are strings)
+
+	// Generate
+	Rows json.RawMessage `json:"rows"` // Array of objects (default: node input, or its "rows" field)
+}
+
+// NewCSVExecutor creates a new CSV executor.
+func NewCSVExecutor() *CSVExecutor {
+	return &CSVExecutor{}
+}
+
+func (e *CSVExecutor) NodeType() string {
+	return "transform_csv"
+}
+
+var csvInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["mode"],
+  "properties": {
+    "mode": {"type": "string", "enum": ["parse", "generate"]},
+    "delimiter": {"type": "string", "minLength": 1, "maxLength": 4, "default": ","},
+    "has_header": {"type": "boolean", "default": true},
+    "columns": {"type": "array", "items": {"type": "string"}},
+    "csv": {"type": "string", "description": "CSV text to parse, defaults to the node input"},
+    "infer_types": {"type": "boolean", "default": false},
+    "rows": {"type": "array", "items": {"type": "object"}, "description": "Rows to generate, defaults to the node input"}
+  }
+}`)
+
+var csvOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["count"],
+  "properties": {
+    "rows": {"type": "array", "items": {"type": "object"}},
+    "columns": {"type": "array", "items": {"type": "string"}},
+    "csv": {"type": "string"},
+    "count": {"type": "integer"}
+  }
+}`)
+
+func (e *CSVExecutor) InputSchema() json.RawMessage {
+	return csvInputSchema
+}
+
+func (e *CSVExecutor) OutputSchema() json.RawMessage {
+	return csvOutputSchema
+}
+
+func (e *CSVExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: ErrorTypeNonRetryable},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config CSVConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse transform_csv config: %v", err))
+	}
+	delimiter, err := config.delimiter()
+	if err != nil {
+		return failed(err.Error())
+	}
+
+	var output []byte
+	var count int
+	switch config.Mode {
+	case "parse":
+		text, err := config.text(req.Input)
+		if err != nil {
+			return failed(err.Error())
+		}
+		output, count, err = parseCSV(ctx, strings.NewReader(text), delimiter, config)
+		if err != nil {
+			return failed(fmt.Sprintf("failed to parse csv: %v", err))
+		}
+	case "generate":
+		rows := config.Rows
+		if len(rows) == 0 {
+			rows = req.Input
+		}
+		output, count, err = generateCSV(ctx, bytes.NewReader(rows), delimiter, config)
+		if err != nil {
+			return failed(fmt.Sprintf("failed to generate csv: %v", err))
+		}
+	default:
+		return failed(fmt.Sprintf("unknown mode: %s", config.Mode))
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("CSV %s processed %d rows for node %s", config.Mode, count, req.NodeID),
+	})
+
+	return &ExecuteResponse{
+		Output:   output,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+func (c *CSVConfig) delimiter() (rune, error) {
+	if c.Delimiter == "" {
+		return ',', nil
+	}
+	r, size := utf8.DecodeRuneInString(c.Delimiter)
+	if size != len(c.Delimiter) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("invalid delimiter %q: must be a single character other than a quote or newline", c.Delimiter)
+	}
+	return r, nil
+}
+
+func (c *CSVConfig) hasHeader() bool {
+	return c.HasHeader == nil || *c.HasHeader
+}
+
+// text returns the CSV text to parse: the csv setting, or else the node
+// input, which must be a JSON string.
+func (c *CSVConfig) text(input json.RawMessage) (string, error) {
+	if c.CSV != nil {
+		return *c.CSV, nil
+	}
+	var text string
+	if err := json.Unmarshal(input, &text); err != nil {
+		return "", errors.New("csv is required when the node input is not a string")
+	}
+	return text, nil
+}
+
+// parseCSV reads records one at a time and encodes each as an object keyed
+// by the column names, without holding the parsed table in memory.
+func parseCSV(ctx context.Context, r io.Reader, delimiter rune, config CSVConfig) ([]byte, int, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.ReuseRecord = true
+
+	columns := config.Columns
+	if config.hasHeader() {
+		header, err := reader.Read()
+		if err == io.EOF {
+			return csvParseOutput(nil, columns, 0)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		columns = make([]string, len(header))
+		copy(columns, header)
+		if len(columns) > 0 {
+			// Spreadsheets often prefix exports with a byte order mark.
+			columns[0] = strings.TrimPrefix(columns[0], "\ufeff")
+		}
+	}
+
+	var rows bytes.Buffer
+	rows.WriteByte('[')
+	count := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if count%1000 == 0 && ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		if columns == nil {
+			columns = csvDefaultColumns(len(record))
+		}
+
+		row := make(map[string]interface{}, len(record))
+		for i, value := range record {
+			name := ""
+			if i < len(columns) {
+				name = columns[i]
+			}
+			if name == "" {
+				name = fmt.Sprintf("column_%d", i+1)
+			}
+			if config.InferTypes {
+				row[name] = inferCSVValue(value)
+			} else {
+				row[name] = value
+			}
+		}
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			return nil, 0, err
+		}
+		if count > 0 {
+			rows.WriteByte(',')
+		}
+		rows.Write(encoded)
+		count++
+	}
+	rows.WriteByte(']')
+
+	return csvParseOutput(rows.Bytes(), columns, count)
+}
+
+func csvParseOutput(rows []byte, columns []string, count int) ([]byte, int, error) {
+	if rows == nil {
+		rows = []byte("[]")
+	}
+	if columns == nil {
+		columns = []string{}
+	}
+	output, err := json.Marshal(struct {
+		Rows    json.RawMessage `json:"rows"`
+		Columns []string        `json:"columns"`
+		Count   int             `json:"count"`
+	}{rows, columns, count})
+	return output, count, err
+}
+
+func csvDefaultColumns(n int) []string {
+	columns := make([]string, n)
+	for i := range columns {
+		columns[i] = fmt.Sprintf("column_%d", i+1)
+	}
+	return columns
+}
+
+// inferCSVValue converts booleans and numbers, keeping everything else, such
+// as zero-padded codes, as a string.
+func inferCSVValue(value string) interface{} {
+	switch value {
+	case "true", "TRUE", "True":
+		return true
+	case "false", "FALSE", "False":
+		return false
+	}
+	if value == "" || (len(value) > 1 && value[0] == '0' && value[1] != '.') {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "xXpP_") {
+		lower := strings.ToLower(value)
+		if lower != "inf" && lower != "+inf" && lower != "-inf" && lower != "infinity" && lower != "nan" {
+			return json.Number(value)
+		}
+	}
+	return value
+}
+
+// generateCSV decodes rows one at a time from a JSON array, or from the
+// "rows" field of an object such as a parse result, and writes each as a
+// record.
+func generateCSV(ctx context.Context, r io.Reader, delimiter rune, config CSVConfig) ([]byte, int, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := seekCSVRows(decoder); err != nil {
+		return nil, 0, err
+	}
+
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	writer.Comma = delimiter
+
+	columns := config.Columns
+	count := 0
+	var record []string
+	for decoder.More() {
+		if count%1000 == 0 && ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return nil, 0, fmt.Errorf("row %d: %w", count+1, err)
+		}
+
+		if count == 0 {
+			if len(columns) == 0 {
+				for key := range row {
+					columns = append(columns, key)
+				}
+				sort.Strings(columns)
+			}
+			if config.hasHeader() {
+				if err := writer.Write(columns); err != nil {
+					return nil, 0, err
+				}
+			}
+			record = make([]string, len(columns))
+		}
+
+		for i, column := range columns {
+			value, err := csvFieldValue(row[column])
+			if err != nil {
+				return nil, 0, fmt.Errorf("row %d, column %q: %w", count+1, column, err)
+			}
+			record[i] = value
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, 0, err
+		}
+		count++
+	}
+	if count == 0 && len(columns) > 0 && config.hasHeader() {
+		if err := writer.Write(columns); err != nil {
+			return nil, 0, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, 0, err
+	}
+
+	output, err := json.Marshal(struct {
+		CSV     string   `json:"csv"`
+		Columns []string `json:"columns"`
+		Count   int      `json:"count"`
+	}{out.String(), columns, count})
+	return output, count, err
+}
+
+// seekCSVRows positions decoder inside the array of rows to generate.
+func seekCSVRows(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return errors.New("rows must be an array of objects")
+	}
+	if token == json.Delim('[') {
+		return nil
+	}
+	if token != json.Delim('{') {
+		return errors.New("rows must be an array of objects")
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if key == "rows" {
+			if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+				return errors.New("rows must be an array of objects")
+			}
+			return nil
+		}
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	return errors.New("rows must be an array of objects")
+}
+
+// csvFieldValue formats a JSON value as a CSV field. Nested objects and
+// arrays are written as JSON.
+func csvFieldValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		encoded, err := json.Marshal(v)
+		return string(encoded), err
+	}
+}
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func runCSV(t *testing.T, config, input string) map[string]interface{} {
+	t.Helper()
+	resp, err := NewCSVExecutor().Execute(context.Background(), &ExecuteRequest{
+		NodeID: "csv-1",
+		Config: json.RawMessage(config),
+		Input:  json.RawMessage(input),
+	})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("Execute failed: %s", resp.Error.Message)
+	}
+	var output map[string]interface{}
+	if err := json.Unmarshal(resp.Output, &output); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	return output
+}
+
+func TestCSVExecutorParse(t *testing.T) {
+	text, _ := json.Marshal("\ufeffname;note;qty\n\"Doe; Jane\";\"said \"\"hi\"\"\nthen left\";007\nBob;;3\n")
+
+	output := runCSV(t, `{"mode":"parse","delimiter":";"}`, string(text))
+	rows := output["rows"].([]interface{})
+	if len(rows) != 2 || output["count"] != float64(2) {
+		t.Fatalf("rows = %v, want 2", rows)
+	}
+	first := rows[0].(map[string]interface{})
+	if first["name"] != "Doe; Jane" || first["note"] != "said \"hi\"\nthen left" || first["qty"] != "007" {
+		t.Fatalf("first row = %v", first)
+	}
+
+	output = runCSV(t, `{"mode":"parse","delimiter":";","infer_types":true}`, string(text))
+	second := output["rows"].([]interface{})[1].(map[string]interface{})
+	if second["qty"] != float64(3) || second["note"] != "" {
+		t.Fatalf("inferred row = %v", second)
+	}
+	if first := output["rows"].([]interface{})[0].(map[string]interface{}); first["qty"] != "007" {
+		t.Fatalf("zero-padded qty inferred as %v, want the string", first["qty"])
+	}
+
+	output = runCSV(t, `{"mode":"parse","has_header":false,"csv":"a,b\nc,d\n"}`, `null`)
+	if first := output["rows"].([]interface{})[0].(map[string]interface{}); first["column_1"] != "a" || first["column_2"] != "b" {
+		t.Fatalf("headerless row = %v", first)
+	}
+}
+
+func TestCSVExecutorGenerate(t *testing.T) {
+	input := `{"rows":[{"name":"Doe, Jane","qty":7,"tags":["a"]},{"name":"say \"hi\"","active":true}],"count":2}`
+
+	output := runCSV(t, `{"mode":"generate","columns":["name","qty","active","tags"]}`, input)
+	want := "name,qty,active,tags\n\"Doe, Jane\",7,,\"[\"\"a\"\"]\"\n\"say \"\"hi\"\"\",,true,\n"
+	if output["csv"] != want {
+		t.Fatalf("csv = %q, want %q", output["csv"], want)
+	}
+
+	output = runCSV(t, `{"mode":"generate","delimiter":"\t","has_header":false}`, `[{"b":"2","a":"1"}]`)
+	if output["csv"] != "1\t2\n" {
+		t.Fatalf("tab-delimited csv = %q", output["csv"])
+	}
+}
+
+func TestCSVExecutorRejectsInvalidConfig(t *testing.T) {
+	for _, tc := range []struct{ config, input string }{
+		{`{"mode":"parse","delimiter":"ab"}`, `"a"`},
+		{`{"mode":"parse"}`, `{"not":"text"}`},
+		{`{"mode":"parse"}`, `"a,b\n1\n"`},
+		{`{"mode":"generate"}`, `"a,b"`},
+		{`{"mode":"zip"}`, `[]`},
+	} {
+		resp, err := NewCSVExecutor().Execute(context.Background(), &ExecuteRequest{Config: json.RawMessage(tc.config), Input: json.RawMessage(tc.input)})
+		if err != nil {
+			t.Fatalf("Execute error: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+			t.Fatalf("config %s with input %s: expected non-retryable error, got %+v", tc.config, tc.input, resp.Error)
+		}
+	}
+}
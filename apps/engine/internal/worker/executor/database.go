@@ -12,6 +12,8 @@ import (
 
 // DatabaseExecutor handles database operations.
 type DatabaseExecutor struct {
+	BaseExecutor
+
 	pools map[string]*pgxpool.Pool
 	mu    sync.Mutex
 }
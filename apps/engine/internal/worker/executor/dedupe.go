@@ -0,0 +1,171 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	dedupeDefaultTTL       = 24 * time.Hour
+	dedupeDefaultKeyPrefix = "linkflow:dedupe:"
+)
+
+// DedupeExecutor marks events as seen in Redis so workflows fed by
+// at-least-once sources can skip repeats. The first execution for a key
+// returns duplicate=false; later ones within the TTL return duplicate=true.
+type DedupeExecutor struct {
+	BaseExecutor
+
+	client *redis.Client
+}
+
+// DedupeConfig represents the configuration for a dedupe node.
+type DedupeConfig struct {
+	Fields []string `json:"fields"` // Input fields (dot notation) forming the key (default: whole input)
+	TTL    int      `json:"ttl"`    // Seconds a key is remembered (default 86400)
+	Scope  string   `json:"scope"`  // Optional key namespace, e.g. to dedupe per workflow
+}
+
+// DedupeResponse is the output of a dedupe node.
+type DedupeResponse struct {
+	Duplicate bool   `json:"duplicate"`
+	Key       string `json:"key"`
+}
+
+var dedupeOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["duplicate", "key"],
+  "properties": {
+    "duplicate": {"type": "boolean", "description": "True when the key was already seen within the TTL"},
+    "key": {"type": "string"}
+  }
+}`)
+
+// NewDedupeExecutor creates a new dedupe executor. A Redis client must be set
+// with WithRedis before it can run.
+func NewDedupeExecutor() *DedupeExecutor {
+	return &DedupeExecutor{}
+}
+
+// WithRedis sets the Redis client used to record keys. The client's pool is
+// shared by all dedupe nodes the worker runs.
+func (e *DedupeExecutor) WithRedis(client *redis.Client) *DedupeExecutor {
+	e.client = client
+	return e
+}
+
+func (e *DedupeExecutor) NodeType() string {
+	return "dedupe"
+}
+
+func (e *DedupeExecutor) OutputSchema() json.RawMessage {
+	return dedupeOutputSchema
+}
+
+func (e *DedupeExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: errorType},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config DedupeConfig
+	if len(req.Config) > 0 {
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			return failed(fmt.Sprintf("failed to parse dedupe config: %v", err), ErrorTypeNonRetryable)
+		}
+	}
+	if config.TTL < 0 {
+		return failed("ttl must not be negative", ErrorTypeNonRetryable)
+	}
+	if e.client == nil {
+		return failed("dedupe requires a Redis connection; set REDIS_URL on the worker", ErrorTypeNonRetryable)
+	}
+
+	hash, err := dedupeHash(req.Input, config.Fields)
+	if err != nil {
+		return failed(err.Error(), ErrorTypeNonRetryable)
+	}
+	key := dedupeDefaultKeyPrefix + req.Namespace + ":"
+	if config.Scope != "" {
+		key += config.Scope + ":"
+	}
+	key += hash
+
+	ttl := dedupeDefaultTTL
+	if config.TTL > 0 {
+		ttl = time.Duration(config.TTL) * time.Second
+	}
+
+	stored, err := e.client.SetNX(ctx, key, req.RunID, ttl).Result()
+	if err != nil {
+		errorType := ErrorTypeRetryable
+		if errors.Is(err, context.DeadlineExceeded) {
+			errorType = ErrorTypeTimeout
+		}
+		return failed(fmt.Sprintf("failed to record dedupe key: %v", err), errorType)
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Dedupe key %s duplicate=%v", key, !stored),
+	})
+
+	output, err := json.Marshal(DedupeResponse{Duplicate: !stored, Key: key})
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err), ErrorTypeNonRetryable)
+	}
+
+	return &ExecuteResponse{
+		Output:   output,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// dedupeHash hashes the selected input fields. The JSON is canonicalized
+// first: object keys are sorted and numbers keep their original text, so the
+// same event hashes the same regardless of field order.
+func dedupeHash(input json.RawMessage, fields []string) (string, error) {
+	var data interface{}
+	if len(input) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(input))
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return "", fmt.Errorf("failed to parse input: %v", err)
+		}
+	}
+
+	var selected interface{} = data
+	if len(fields) > 0 {
+		object, _ := data.(map[string]interface{})
+		values := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			values[field] = getFieldValue(object, field)
+		}
+		selected = values
+	}
+
+	// encoding/json writes map keys in sorted order, which makes the
+	// re-encoded value canonical.
+	canonical, err := json.Marshal(selected)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize input: %v", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
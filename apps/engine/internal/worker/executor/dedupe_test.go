@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestDedupeHashIgnoresFieldOrder(t *testing.T) {
+	a, err := dedupeHash(json.RawMessage(`{"event":{"id":"evt_1","source":"stripe"},"amount":10.50,"attempt":1}`), []string{"event", "amount"})
+	if err != nil {
+		t.Fatalf("dedupeHash error: %v", err)
+	}
+	b, err := dedupeHash(json.RawMessage(`{"attempt":2,"amount":10.50,"event":{"source":"stripe","id":"evt_1"}}`), []string{"amount", "event"})
+	if err != nil {
+		t.Fatalf("dedupeHash error: %v", err)
+	}
+	if a != b {
+		t.Fatalf("expected equal hashes for reordered input, got %s and %s", a, b)
+	}
+
+	c, err := dedupeHash(json.RawMessage(`{"event":{"id":"evt_2","source":"stripe"},"amount":10.50}`), []string{"event", "amount"})
+	if err != nil {
+		t.Fatalf("dedupeHash error: %v", err)
+	}
+	if a == c {
+		t.Fatal("expected different hashes for different events")
+	}
+
+	whole1, _ := dedupeHash(json.RawMessage(`{"b":1,"a":[1,{"y":2,"x":1}]}`), nil)
+	whole2, _ := dedupeHash(json.RawMessage(`{"a":[1,{"x":1,"y":2}],"b":1}`), nil)
+	if whole1 != whole2 {
+		t.Fatal("expected whole-input hashes to ignore key order")
+	}
+}
+
+func TestDedupeExecutorRequiresRedis(t *testing.T) {
+	resp, err := NewDedupeExecutor().Execute(context.Background(), &ExecuteRequest{
+		NodeType: "dedupe",
+		Config:   json.RawMessage(`{"fields":["id"]}`),
+		Input:    json.RawMessage(`{"id":"evt_1"}`),
+	})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("expected non-retryable error without redis, got %+v", resp.Error)
+	}
+}
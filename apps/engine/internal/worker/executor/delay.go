@@ -2,17 +2,54 @@ package executor
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"time"
 )
 
+// DefaultDelayInlineThreshold is the longest delay a DelayExecutor waits
+// out in-process before handing it to the timer service.
+const DefaultDelayInlineThreshold = 30 * time.Second
+
+// delayTimerGrace is added to the wake time when bounding how long a delay
+// activity may stay pending, covering timer scan and fire latency.
+const delayTimerGrace = 5 * time.Minute
+
+// TimerScheduler schedules timers that complete a pending activity when
+// they fire.
+type TimerScheduler interface {
+	ScheduleActivityTimer(ctx context.Context, req *ActivityTimerRequest) error
+}
+
+// ActivityTimerRequest asks the timer service to complete the activity
+// identified by TaskToken with Result at FireAt.
+type ActivityTimerRequest struct {
+	Namespace  string          `json:"namespace"`
+	WorkflowID string          `json:"workflow_id"`
+	RunID      string          `json:"run_id"`
+	TimerID    string          `json:"timer_id"`
+	TaskToken  string          `json:"task_token"`
+	FireAt     time.Time       `json:"fire_at"`
+	Result     json.RawMessage `json:"result,omitempty"`
+}
+
 // DelayExecutor handles delay/wait nodes.
-type DelayExecutor struct{}
+type DelayExecutor struct {
+	BaseExecutor
+
+	timers          TimerScheduler
+	inlineThreshold time.Duration
+	logger          *slog.Logger
+}
 
 // DelayConfig represents the configuration for a delay node.
 type DelayConfig struct {
+	// Mode is "duration" (default) or "until".
+	Mode string `json:"mode"`
+
 	// Duration in various units (only one should be set)
 	Seconds      int `json:"seconds"`
 	Minutes      int `json:"minutes"`
@@ -31,13 +68,40 @@ type DelayConfig struct {
 type DelayResponse struct {
 	StartedAt  string `json:"started_at"`
 	EndedAt    string `json:"ended_at"`
+	WakeAt     string `json:"wake_at"`
 	Duration   string `json:"duration"`
 	DurationMs int64  `json:"duration_ms"`
 }
 
 // NewDelayExecutor creates a new delay executor.
 func NewDelayExecutor() *DelayExecutor {
-	return &DelayExecutor{}
+	return &DelayExecutor{
+		inlineThreshold: DefaultDelayInlineThreshold,
+		logger:          slog.Default(),
+	}
+}
+
+// WithTimerScheduler sets the scheduler used for delays longer than the
+// inline threshold. Without one, every delay blocks the poller.
+func (e *DelayExecutor) WithTimerScheduler(timers TimerScheduler) *DelayExecutor {
+	e.timers = timers
+	return e
+}
+
+// WithInlineThreshold sets the longest delay waited out in-process.
+func (e *DelayExecutor) WithInlineThreshold(threshold time.Duration) *DelayExecutor {
+	if threshold >= 0 {
+		e.inlineThreshold = threshold
+	}
+	return e
+}
+
+// WithLogger sets the logger.
+func (e *DelayExecutor) WithLogger(logger *slog.Logger) *DelayExecutor {
+	if logger != nil {
+		e.logger = logger
+	}
+	return e
 }
 
 func (e *DelayExecutor) NodeType() string {
@@ -54,6 +118,32 @@ func (e *DelayExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 		Message:   fmt.Sprintf("Starting delay execution for node %s", req.NodeID),
 	})
 
+	requestBytes, _ := json.Marshal(map[string]interface{}{
+		"node_id": req.NodeID,
+		"config":  json.RawMessage(req.Config),
+	})
+	requestFingerprint := fmt.Sprintf("%x", sha256.Sum256(requestBytes))
+
+	// Replays reuse the recorded wake time instead of resolving a relative
+	// delay against the current clock, and never wait.
+	if req.Deterministic != nil && req.Deterministic.Mode == "replay" {
+		for _, fixture := range req.Deterministic.Fixtures {
+			if fixture.RequestFingerprint != requestFingerprint {
+				continue
+			}
+			logs = append(logs, LogEntry{
+				Timestamp: time.Now(),
+				Level:     "INFO",
+				Message:   "Replaying recorded delay",
+			})
+			return &ExecuteResponse{
+				Output:   fixture.Response,
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+	}
+
 	var config DelayConfig
 	if err := json.Unmarshal(req.Config, &config); err != nil {
 		return &ExecuteResponse{
@@ -66,6 +156,30 @@ func (e *DelayExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 		}, nil
 	}
 
+	switch config.Mode {
+	case "", "duration":
+	case "until":
+		if config.Until == "" {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: "'until' is required in until mode",
+					Type:    ErrorTypeNonRetryable,
+				},
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+	default:
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("unknown delay mode %q", config.Mode),
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
 	// Calculate the delay duration
 	var delayDuration time.Duration
 
@@ -83,7 +197,7 @@ func (e *DelayExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 				Duration: time.Since(start),
 			}, nil
 		}
-		delayDuration = time.Until(untilTime)
+		delayDuration = untilTime.Sub(start)
 		if delayDuration < 0 {
 			delayDuration = 0 // Already past the time
 		}
@@ -133,76 +247,117 @@ func (e *DelayExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 		delayDuration = maxDelay
 	}
 
-	// For short delays (≤30s), block in-process to avoid timer overhead
-	const shortDelayThreshold = 30 * time.Second
-
-	if delayDuration <= shortDelayThreshold {
-		logs = append(logs, LogEntry{
-			Timestamp: time.Now(),
-			Level:     "INFO",
-			Message:   fmt.Sprintf("Short delay: waiting %v in-process", delayDuration),
-		})
+	wakeAt := start.Add(delayDuration)
+	fixture := DeterministicFixture{
+		RequestFingerprint: requestFingerprint,
+		NodeID:             req.NodeID,
+		NodeType:           req.NodeType,
+		Request:            requestBytes,
+	}
 
-		select {
-		case <-ctx.Done():
-			return &ExecuteResponse{
-				Error: &ExecutionError{
-					Message: "delay was canceled",
-					Type:    ErrorTypeNonRetryable,
-				},
-				Logs:     logs,
-				Duration: time.Since(start),
-			}, nil
-		case <-time.After(delayDuration):
-			// Delay completed
-		}
+	if delayDuration > e.inlineThreshold && e.timers != nil {
+		return e.scheduleTimer(req, start, wakeAt, fixture, logs)
+	}
 
-		return e.buildCompletedResponse(start, logs)
+	if delayDuration > e.inlineThreshold {
+		e.logger.Warn("no timer scheduler configured, blocking poller for long delay",
+			slog.String("workflow_id", req.WorkflowID),
+			slog.String("node_id", req.NodeID),
+			slog.Duration("delay", delayDuration),
+		)
 	}
 
-	// For long delays (>30s), return immediately with timer metadata.
-	// This avoids blocking a worker goroutine for extended periods.
-	// The workflow executor should use the Timer service to schedule a wake-up.
 	logs = append(logs, LogEntry{
 		Timestamp: time.Now(),
 		Level:     "INFO",
-		Message:   fmt.Sprintf("Long delay requested (%v): returning timer request instead of blocking", delayDuration),
+		Message:   fmt.Sprintf("Waiting %v in-process", delayDuration),
 	})
 
-	resumeAt := time.Now().Add(delayDuration)
-	timerResponse := map[string]interface{}{
-		"timer_requested": true,
-		"delay_duration":  delayDuration.String(),
-		"delay_seconds":   int64(delayDuration.Seconds()),
-		"resume_at":       resumeAt.Format(time.RFC3339),
-		"started_at":      start.Format(time.RFC3339),
+	timer := time.NewTimer(delayDuration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: "delay was canceled",
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	case <-timer.C:
+		// Delay completed
 	}
 
-	output, err := json.Marshal(timerResponse)
+	return e.buildCompletedResponse(start, wakeAt, fixture, logs)
+}
+
+// scheduleTimer frees the poller for a long delay: the activity stays
+// pending until the timer service fires and completes it with the delay
+// result, which is fixed up front since the wake time is already known.
+func (e *DelayExecutor) scheduleTimer(req *ExecuteRequest, start, wakeAt time.Time, fixture DeterministicFixture, logs []LogEntry) (*ExecuteResponse, error) {
+	delayDuration := wakeAt.Sub(start)
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Long delay (%v): scheduling timer for %s", delayDuration, wakeAt.Format(time.RFC3339)),
+	})
+
+	output, err := json.Marshal(DelayResponse{
+		StartedAt:  start.Format(time.RFC3339),
+		EndedAt:    wakeAt.Format(time.RFC3339),
+		WakeAt:     wakeAt.Format(time.RFC3339),
+		Duration:   delayDuration.String(),
+		DurationMs: delayDuration.Milliseconds(),
+	})
 	if err != nil {
 		return &ExecuteResponse{
 			Error: &ExecutionError{
-				Message: fmt.Sprintf("failed to marshal timer response: %v", err),
+				Message: fmt.Sprintf("failed to marshal response: %v", err),
 				Type:    ErrorTypeNonRetryable,
 			},
 			Logs:     logs,
 			Duration: time.Since(start),
 		}, nil
 	}
+	fixture.Response = output
 
 	return &ExecuteResponse{
-		Output: output,
-		Logs:   logs,
+		Pending: &PendingActivity{
+			ScheduleToCloseTimeout: delayDuration + delayTimerGrace,
+			OnPending: func(ctx context.Context, taskToken string) error {
+				return e.timers.ScheduleActivityTimer(ctx, &ActivityTimerRequest{
+					Namespace:  req.Namespace,
+					WorkflowID: req.WorkflowID,
+					RunID:      req.RunID,
+					TimerID:    delayTimerID(req.NodeID, taskToken),
+					TaskToken:  taskToken,
+					FireAt:     wakeAt,
+					Result:     output,
+				})
+			},
+		},
+		DeterministicFixtures: []DeterministicFixture{fixture},
+		Logs:                  logs,
 		Metadata: map[string]string{
 			"timer_requested":  "true",
 			"timer_duration_s": strconv.FormatInt(int64(delayDuration.Seconds()), 10),
-			"resume_at":        resumeAt.Format(time.RFC3339),
+			"resume_at":        wakeAt.Format(time.RFC3339),
 		},
 		Duration: time.Since(start),
 	}, nil
 }
 
-func (e *DelayExecutor) buildCompletedResponse(start time.Time, logs []LogEntry) (*ExecuteResponse, error) {
+// delayTimerID derives a timer ID from the task token, so a node that runs
+// more than once in an execution (loops, retries) gets a timer per run.
+func delayTimerID(nodeID, taskToken string) string {
+	sum := sha256.Sum256([]byte(taskToken))
+	return fmt.Sprintf("delay-%s-%x", nodeID, sum[:8])
+}
+
+func (e *DelayExecutor) buildCompletedResponse(start, wakeAt time.Time, fixture DeterministicFixture, logs []LogEntry) (*ExecuteResponse, error) {
 	endTime := time.Now()
 
 	logs = append(logs, LogEntry{
@@ -214,6 +369,7 @@ func (e *DelayExecutor) buildCompletedResponse(start time.Time, logs []LogEntry)
 	response := DelayResponse{
 		StartedAt:  start.Format(time.RFC3339),
 		EndedAt:    endTime.Format(time.RFC3339),
+		WakeAt:     wakeAt.Format(time.RFC3339),
 		Duration:   time.Since(start).String(),
 		DurationMs: time.Since(start).Milliseconds(),
 	}
@@ -230,9 +386,12 @@ func (e *DelayExecutor) buildCompletedResponse(start time.Time, logs []LogEntry)
 		}, nil
 	}
 
+	fixture.Response = output
+
 	return &ExecuteResponse{
-		Output:   output,
-		Logs:     logs,
-		Duration: time.Since(start),
+		Output:                output,
+		DeterministicFixtures: []DeterministicFixture{fixture},
+		Logs:                  logs,
+		Duration:              time.Since(start),
 	}, nil
 }
@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type recordingTimerScheduler struct {
+	requests []*ActivityTimerRequest
+}
+
+func (s *recordingTimerScheduler) ScheduleActivityTimer(_ context.Context, req *ActivityTimerRequest) error {
+	s.requests = append(s.requests, req)
+	return nil
+}
+
+func delayRequest(config string) *ExecuteRequest {
+	return &ExecuteRequest{
+		NodeType:   "delay",
+		NodeID:     "wait-1",
+		WorkflowID: "wf-1",
+		RunID:      "run-1",
+		Namespace:  "default",
+		Config:     json.RawMessage(config),
+	}
+}
+
+func TestDelayExecutorShortDelayBlocksInline(t *testing.T) {
+	timers := &recordingTimerScheduler{}
+	e := NewDelayExecutor().WithTimerScheduler(timers)
+
+	resp, err := e.Execute(context.Background(), delayRequest(`{"milliseconds": 10}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error != nil || resp.Pending != nil {
+		t.Fatalf("expected inline completion, got error=%v pending=%v", resp.Error, resp.Pending)
+	}
+	if len(timers.requests) != 0 {
+		t.Fatalf("expected no timer for a short delay, got %d", len(timers.requests))
+	}
+	if len(resp.DeterministicFixtures) != 1 {
+		t.Fatalf("expected a recorded fixture, got %d", len(resp.DeterministicFixtures))
+	}
+}
+
+func TestDelayExecutorLongDelaySchedulesTimer(t *testing.T) {
+	timers := &recordingTimerScheduler{}
+	e := NewDelayExecutor().WithTimerScheduler(timers).WithInlineThreshold(time.Second)
+
+	until := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	resp, err := e.Execute(context.Background(), delayRequest(`{"mode": "until", "until": "`+until.Format(time.RFC3339)+`"}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error.Message)
+	}
+	if resp.Pending == nil || resp.Pending.OnPending == nil {
+		t.Fatal("expected a pending activity for a long delay")
+	}
+	if resp.Pending.ScheduleToCloseTimeout < 2*time.Hour-time.Minute {
+		t.Fatalf("timeout %v shorter than the delay", resp.Pending.ScheduleToCloseTimeout)
+	}
+
+	if err := resp.Pending.OnPending(context.Background(), "token-1"); err != nil {
+		t.Fatalf("OnPending error: %v", err)
+	}
+	if len(timers.requests) != 1 {
+		t.Fatalf("expected one timer, got %d", len(timers.requests))
+	}
+	timer := timers.requests[0]
+	if !timer.FireAt.Equal(until) || timer.TaskToken != "token-1" || timer.RunID != "run-1" {
+		t.Fatalf("unexpected timer request %+v", timer)
+	}
+
+	var result DelayResponse
+	if err := json.Unmarshal(timer.Result, &result); err != nil {
+		t.Fatalf("timer result: %v", err)
+	}
+	if result.WakeAt != until.Format(time.RFC3339) {
+		t.Fatalf("wake_at = %s, want %s", result.WakeAt, until.Format(time.RFC3339))
+	}
+	if len(resp.DeterministicFixtures) != 1 || string(resp.DeterministicFixtures[0].Response) != string(timer.Result) {
+		t.Fatal("expected the fixture to record the timer result")
+	}
+}
+
+func TestDelayExecutorReplayUsesRecordedWakeTime(t *testing.T) {
+	timers := &recordingTimerScheduler{}
+	e := NewDelayExecutor().WithTimerScheduler(timers).WithInlineThreshold(time.Second)
+
+	live, err := e.Execute(context.Background(), delayRequest(`{"duration": "1h"}`))
+	if err != nil || live.Pending == nil {
+		t.Fatalf("expected pending live run, got %v", err)
+	}
+
+	req := delayRequest(`{"duration": "1h"}`)
+	req.Deterministic = &DeterministicContext{Mode: "replay", Fixtures: live.DeterministicFixtures}
+	replayed, err := e.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if replayed.Pending != nil || replayed.Error != nil {
+		t.Fatal("expected replay to complete immediately")
+	}
+	if string(replayed.Output) != string(live.DeterministicFixtures[0].Response) {
+		t.Fatalf("replay output %s, want recorded %s", replayed.Output, live.DeterministicFixtures[0].Response)
+	}
+}
+
+func TestDelayExecutorUntilModeRequiresUntil(t *testing.T) {
+	resp, err := NewDelayExecutor().Execute(context.Background(), delayRequest(`{"mode": "until", "seconds": 5}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error == nil {
+		t.Fatal("expected an error when until is missing")
+	}
+}
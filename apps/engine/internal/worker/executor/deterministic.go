@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// generatedValues is the fixture response recording the UUIDs and timestamps
+// an activity generated, in the order it asked for them.
+type generatedValues struct {
+	UUIDs []string    `json:"uuids,omitempty"`
+	Times []time.Time `json:"times,omitempty"`
+}
+
+// generatedValueSource hands out generated values for one node: recorded ones
+// first when replaying, fresh ones otherwise.
+type generatedValueSource struct {
+	mu       sync.Mutex
+	nodeID   string
+	nodeType string
+	capture  bool
+	replay   generatedValues
+	recorded generatedValues
+}
+
+// generatedValuesRequest is the fixture request for the values a node
+// generated. Nodes run at most once per execution, so the node ID is enough.
+func generatedValuesRequest(nodeID string) []byte {
+	requestBytes, _ := json.Marshal(map[string]interface{}{
+		"node_id": nodeID,
+		"kind":    "generated_values",
+	})
+	return requestBytes
+}
+
+func generatedValuesFingerprint(nodeID string) string {
+	return fmt.Sprintf("%x", sha256.Sum256(generatedValuesRequest(nodeID)))
+}
+
+// BindNode prepares the context to hand out UUIDs and timestamps for a node.
+// In replay mode they come from the node's recorded fixture; in capture mode
+// they are recorded for GeneratedFixture. Without BindNode, UUID and Now
+// return fresh values.
+func (d *DeterministicContext) BindNode(nodeID, nodeType string) {
+	if d == nil {
+		return
+	}
+	source := &generatedValueSource{
+		nodeID:   nodeID,
+		nodeType: nodeType,
+		capture:  d.Mode != "replay",
+	}
+	if !source.capture {
+		fingerprint := generatedValuesFingerprint(nodeID)
+		for _, fixture := range d.Fixtures {
+			if fixture.RequestFingerprint == fingerprint {
+				_ = json.Unmarshal(fixture.Response, &source.replay)
+				break
+			}
+		}
+	}
+	d.generated = source
+}
+
+// UUID returns a random UUID, or the next recorded one when replaying. It is
+// safe to call on a nil context.
+func (d *DeterministicContext) UUID() string {
+	if d == nil || d.generated == nil {
+		return uuid.NewString()
+	}
+	s := d.generated
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.replay.UUIDs) > 0 {
+		id := s.replay.UUIDs[0]
+		s.replay.UUIDs = s.replay.UUIDs[1:]
+		return id
+	}
+	id := uuid.NewString()
+	if s.capture {
+		s.recorded.UUIDs = append(s.recorded.UUIDs, id)
+	}
+	return id
+}
+
+// Now returns the current time, or the next recorded one when replaying. It
+// is safe to call on a nil context.
+func (d *DeterministicContext) Now() time.Time {
+	if d == nil || d.generated == nil {
+		return time.Now()
+	}
+	s := d.generated
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.replay.Times) > 0 {
+		now := s.replay.Times[0]
+		s.replay.Times = s.replay.Times[1:]
+		return now
+	}
+	now := time.Now().UTC()
+	if s.capture {
+		s.recorded.Times = append(s.recorded.Times, now)
+	}
+	return now
+}
+
+// GeneratedFixture returns the fixture recording the values UUID and Now
+// handed out in capture mode. It reports false when nothing was generated.
+func (d *DeterministicContext) GeneratedFixture() (DeterministicFixture, bool) {
+	if d == nil || d.generated == nil {
+		return DeterministicFixture{}, false
+	}
+	s := d.generated
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.capture || (len(s.recorded.UUIDs) == 0 && len(s.recorded.Times) == 0) {
+		return DeterministicFixture{}, false
+	}
+	response, err := json.Marshal(s.recorded)
+	if err != nil {
+		return DeterministicFixture{}, false
+	}
+	return DeterministicFixture{
+		RequestFingerprint: generatedValuesFingerprint(s.nodeID),
+		NodeID:             s.nodeID,
+		NodeType:           s.nodeType,
+		Request:            generatedValuesRequest(s.nodeID),
+		Response:           response,
+	}, true
+}
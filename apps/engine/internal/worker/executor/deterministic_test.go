@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestDeterministicContextReplaysGeneratedValues(t *testing.T) {
+	capture := &DeterministicContext{Mode: "capture"}
+	capture.BindNode("send", "email")
+	id := capture.UUID()
+	now := capture.Now()
+
+	fixture, ok := capture.GeneratedFixture()
+	if !ok {
+		t.Fatal("expected a generated values fixture in capture mode")
+	}
+	if fixture.NodeID != "send" || fixture.RequestFingerprint != generatedValuesFingerprint("send") {
+		t.Fatalf("unexpected fixture: %+v", fixture)
+	}
+
+	replay := &DeterministicContext{Mode: "replay", Fixtures: []DeterministicFixture{fixture}}
+	replay.BindNode("send", "email")
+	if got := replay.UUID(); got != id {
+		t.Fatalf("replayed UUID = %q, want %q", got, id)
+	}
+	if got := replay.Now(); !got.Equal(now) {
+		t.Fatalf("replayed time = %v, want %v", got, now)
+	}
+	// Values beyond the recording are fresh, and replays record nothing.
+	if got := replay.UUID(); got == id || got == "" {
+		t.Fatalf("expected a fresh UUID after the recording ran out, got %q", got)
+	}
+	if _, ok := replay.GeneratedFixture(); ok {
+		t.Fatal("replay mode should not produce a generated values fixture")
+	}
+
+	var unset *DeterministicContext
+	if unset.UUID() == "" || unset.Now().IsZero() {
+		t.Fatal("nil context should generate fresh values")
+	}
+	if _, ok := unset.GeneratedFixture(); ok {
+		t.Fatal("nil context should not produce a fixture")
+	}
+}
+
+func TestOutputExecutorReplaysLoggedAt(t *testing.T) {
+	run := func(deterministic *DeterministicContext) (string, *DeterministicContext) {
+		t.Helper()
+		deterministic.BindNode("log", "output")
+		resp, err := NewOutputExecutor().Execute(context.Background(), &ExecuteRequest{
+			NodeID:        "log",
+			NodeType:      "output",
+			Config:        json.RawMessage(`{}`),
+			Input:         json.RawMessage(`{"ok":true}`),
+			Deterministic: deterministic,
+		})
+		if err != nil || resp.Error != nil {
+			t.Fatalf("execute: %v %+v", err, resp.Error)
+		}
+		var output map[string]interface{}
+		if err := json.Unmarshal(resp.Output, &output); err != nil {
+			t.Fatalf("decode output: %v", err)
+		}
+		return output["logged_at"].(string), deterministic
+	}
+
+	captured, capture := run(&DeterministicContext{Mode: "capture"})
+	fixture, ok := capture.GeneratedFixture()
+	if !ok {
+		t.Fatal("expected the output executor to record its timestamp")
+	}
+	fixture.Response = json.RawMessage(`{"times":["2020-01-02T03:04:05Z"]}`)
+
+	replayed, _ := run(&DeterministicContext{Mode: "replay", Fixtures: []DeterministicFixture{fixture}})
+	if replayed != "2020-01-02T03:04:05Z" {
+		t.Fatalf("replayed logged_at = %q (captured %q), want the recorded time", replayed, captured)
+	}
+}
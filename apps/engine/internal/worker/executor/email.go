@@ -16,6 +16,8 @@ import (
 
 // EmailExecutor handles email sending via SMTP.
 type EmailExecutor struct {
+	BaseExecutor
+
 	defaultHost string
 	defaultPort int
 	defaultFrom string
@@ -186,7 +188,7 @@ func (e *EmailExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 	}
 
 	// Build the email message
-	message := buildEmailMessage(config.From, config.To, config.Cc, subject, body, bodyHTML, config.ReplyTo)
+	message := buildEmailMessage(config.From, config.To, config.Cc, subject, body, bodyHTML, config.ReplyTo, req.Deterministic.Now())
 
 	// All recipients (To + Cc + Bcc)
 	allRecipients := make([]string, 0, len(config.To)+len(config.Cc)+len(config.Bcc))
@@ -240,7 +242,7 @@ func (e *EmailExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 	})
 
 	// Generate a pseudo message ID
-	messageID := fmt.Sprintf("<%d.%s@linkflow>", time.Now().UnixNano(), req.NodeID)
+	messageID := fmt.Sprintf("<%s.%s@linkflow>", req.Deterministic.UUID(), req.NodeID)
 
 	response := EmailResponse{
 		Success:    true,
@@ -282,7 +284,7 @@ func processTemplate(name, text string, vars map[string]interface{}) (string, er
 	return buf.String(), nil
 }
 
-func buildEmailMessage(from string, to, cc []string, subject, body, bodyHTML, replyTo string) []byte {
+func buildEmailMessage(from string, to, cc []string, subject, body, bodyHTML, replyTo string, date time.Time) []byte {
 	var buf bytes.Buffer
 
 	buf.WriteString(fmt.Sprintf("From: %s\r\n", from))
@@ -294,7 +296,7 @@ func buildEmailMessage(from string, to, cc []string, subject, body, bodyHTML, re
 		buf.WriteString(fmt.Sprintf("Reply-To: %s\r\n", replyTo))
 	}
 	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
-	buf.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z)))
+	buf.WriteString(fmt.Sprintf("Date: %s\r\n", date.UTC().Format(time.RFC1123Z)))
 	buf.WriteString("MIME-Version: 1.0\r\n")
 
 	if bodyHTML != "" {
@@ -0,0 +1,455 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	sendGridBaseURL     = "https://api.sendgrid.com"
+	mailgunBaseURL      = "https://api.mailgun.net"
+	mailgunEUBaseURL    = "https://api.eu.mailgun.net"
+	emailProviderMaxLen = 1 * 1024 * 1024
+)
+
+// TransactionalEmailConfig holds the fields shared by the SendGrid and Mailgun nodes.
+type TransactionalEmailConfig struct {
+	APIKey       string                 `json:"api_key"`
+	From         string                 `json:"from"`
+	FromName     string                 `json:"from_name"`
+	To           []string               `json:"to"`
+	Subject      string                 `json:"subject"`
+	Text         string                 `json:"text"`
+	HTML         string                 `json:"html"`
+	TemplateID   string                 `json:"template_id"`   // Provider-side template (optional)
+	TemplateData map[string]interface{} `json:"template_data"` // Dynamic template data
+}
+
+func (c *TransactionalEmailConfig) validate() string {
+	switch {
+	case c.APIKey == "":
+		return "api_key is required"
+	case c.From == "":
+		return "from is required"
+	case len(c.To) == 0:
+		return "to is required"
+	case c.TemplateID == "" && c.Subject == "":
+		return "subject is required when template_id is not set"
+	case c.TemplateID == "" && c.Text == "" && c.HTML == "":
+		return "text or html is required when template_id is not set"
+	}
+	return ""
+}
+
+func (c *TransactionalEmailConfig) operation() string {
+	if c.TemplateID != "" {
+		return "send_template"
+	}
+	return "send_email"
+}
+
+// SendGridExecutor sends transactional email through the SendGrid v3 Mail Send API.
+type SendGridExecutor struct {
+	BaseExecutor
+
+	client      *http.Client
+	baseURL     string
+	apiKey      string
+	defaultFrom string
+	limiter     *ConnectorRateLimiter
+}
+
+// SendGridConfig represents the configuration for a SendGrid node.
+type SendGridConfig struct {
+	TransactionalEmailConfig
+}
+
+// NewSendGridExecutor creates a new SendGrid executor with connection pooling.
+func NewSendGridExecutor() *SendGridExecutor {
+	return &SendGridExecutor{
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: newSSRFSafeTransport(),
+		},
+		baseURL:     sendGridBaseURL,
+		apiKey:      os.Getenv("SENDGRID_API_KEY"),
+		defaultFrom: os.Getenv("SENDGRID_FROM"),
+		limiter:     DefaultConnectorRateLimiter(),
+	}
+}
+
+// WithAPIKey sets the default API key.
+func (e *SendGridExecutor) WithAPIKey(apiKey string) *SendGridExecutor {
+	e.apiKey = apiKey
+	return e
+}
+
+// WithRateLimiter sets the limiter used to throttle SendGrid calls.
+func (e *SendGridExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *SendGridExecutor {
+	e.limiter = limiter
+	return e
+}
+
+func (e *SendGridExecutor) NodeType() string {
+	return "sendgrid"
+}
+
+func (e *SendGridExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Starting SendGrid execution for node %s", req.NodeID),
+	})
+
+	var config SendGridConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return emailConfigError(fmt.Sprintf("failed to parse SendGrid config: %v", err), logs, start), nil
+	}
+
+	if config.APIKey == "" {
+		config.APIKey = e.apiKey
+	}
+	if config.From == "" {
+		config.From = e.defaultFrom
+	}
+	if msg := config.validate(); msg != "" {
+		return emailConfigError(msg, logs, start), nil
+	}
+
+	recipients := make([]map[string]string, 0, len(config.To))
+	for _, to := range config.To {
+		recipients = append(recipients, map[string]string{"email": to})
+	}
+	personalization := map[string]interface{}{
+		"to": recipients,
+	}
+	if len(config.TemplateData) > 0 {
+		personalization["dynamic_template_data"] = config.TemplateData
+	}
+
+	from := map[string]string{"email": config.From}
+	if config.FromName != "" {
+		from["name"] = config.FromName
+	}
+
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{personalization},
+		"from":             from,
+	}
+	if config.Subject != "" {
+		payload["subject"] = config.Subject
+	}
+	if config.TemplateID != "" {
+		payload["template_id"] = config.TemplateID
+	} else {
+		content := make([]map[string]string, 0, 2)
+		if config.Text != "" {
+			content = append(content, map[string]string{"type": "text/plain", "value": config.Text})
+		}
+		if config.HTML != "" {
+			content = append(content, map[string]string{"type": "text/html", "value": config.HTML})
+		}
+		payload["content"] = content
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return emailConfigError(fmt.Sprintf("failed to marshal payload: %v", err), logs, start), nil
+	}
+
+	operation := config.operation()
+	waited, err := e.limiter.Acquire(ctx, "sendgrid")
+	if err != nil {
+		return rateLimitedResponse(req, "sendgrid", operation, "sendgrid", waited, err, logs, start), nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Sending email to %d recipient(s) via SendGrid", len(config.To)),
+	})
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return emailConfigError(fmt.Sprintf("failed to create request: %v", err), logs, start), nil
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+config.APIKey)
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return emailNetworkError(req, "sendgrid", operation, err, waited, logs, start), nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, emailProviderMaxLen))
+
+	attempt := newConnectorAttempt(req, "sendgrid", operation, "sendgrid", "success", start, waited)
+	attempt.StatusCode = int32(resp.StatusCode)
+
+	if resp.StatusCode >= 400 {
+		return emailProviderError(resp.StatusCode, "SendGrid", strings.TrimSpace(string(respBody)), attempt, logs, start), nil
+	}
+
+	// SendGrid answers 202 with an empty body; the message ID is in a header.
+	messageID := resp.Header.Get("X-Message-Id")
+
+	return emailSentResponse("sendgrid", messageID, resp.StatusCode, config.To, attempt, logs, start), nil
+}
+
+// MailgunExecutor sends transactional email through the Mailgun Messages API.
+type MailgunExecutor struct {
+	BaseExecutor
+
+	client        *http.Client
+	baseURL       string
+	apiKey        string
+	defaultDomain string
+	defaultFrom   string
+	limiter       *ConnectorRateLimiter
+}
+
+// MailgunConfig represents the configuration for a Mailgun node.
+type MailgunConfig struct {
+	TransactionalEmailConfig
+	Domain string `json:"domain"` // Sending domain
+	Region string `json:"region"` // "us" (default) or "eu"
+}
+
+// NewMailgunExecutor creates a new Mailgun executor with connection pooling.
+func NewMailgunExecutor() *MailgunExecutor {
+	return &MailgunExecutor{
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: newSSRFSafeTransport(),
+		},
+		apiKey:        os.Getenv("MAILGUN_API_KEY"),
+		defaultDomain: os.Getenv("MAILGUN_DOMAIN"),
+		defaultFrom:   os.Getenv("MAILGUN_FROM"),
+		limiter:       DefaultConnectorRateLimiter(),
+	}
+}
+
+// WithCredentials sets the default API key and sending domain.
+func (e *MailgunExecutor) WithCredentials(apiKey, domain string) *MailgunExecutor {
+	e.apiKey = apiKey
+	e.defaultDomain = domain
+	return e
+}
+
+// WithRateLimiter sets the limiter used to throttle Mailgun calls.
+func (e *MailgunExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *MailgunExecutor {
+	e.limiter = limiter
+	return e
+}
+
+func (e *MailgunExecutor) NodeType() string {
+	return "mailgun"
+}
+
+func (e *MailgunExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Starting Mailgun execution for node %s", req.NodeID),
+	})
+
+	var config MailgunConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return emailConfigError(fmt.Sprintf("failed to parse Mailgun config: %v", err), logs, start), nil
+	}
+
+	if config.APIKey == "" {
+		config.APIKey = e.apiKey
+	}
+	if config.Domain == "" {
+		config.Domain = e.defaultDomain
+	}
+	if config.From == "" {
+		config.From = e.defaultFrom
+	}
+	if msg := config.validate(); msg != "" {
+		return emailConfigError(msg, logs, start), nil
+	}
+	if config.Domain == "" {
+		return emailConfigError("domain is required", logs, start), nil
+	}
+
+	baseURL := e.baseURL
+	if baseURL == "" {
+		switch strings.ToLower(config.Region) {
+		case "", "us":
+			baseURL = mailgunBaseURL
+		case "eu":
+			baseURL = mailgunEUBaseURL
+		default:
+			return emailConfigError(fmt.Sprintf("unsupported region: %s", config.Region), logs, start), nil
+		}
+	}
+
+	from := config.From
+	if config.FromName != "" {
+		from = fmt.Sprintf("%s <%s>", config.FromName, config.From)
+	}
+
+	form := url.Values{}
+	form.Set("from", from)
+	for _, to := range config.To {
+		form.Add("to", to)
+	}
+	if config.Subject != "" {
+		form.Set("subject", config.Subject)
+	}
+	if config.TemplateID != "" {
+		form.Set("template", config.TemplateID)
+		if len(config.TemplateData) > 0 {
+			vars, err := json.Marshal(config.TemplateData)
+			if err != nil {
+				return emailConfigError(fmt.Sprintf("failed to marshal template_data: %v", err), logs, start), nil
+			}
+			form.Set("t:variables", string(vars))
+		}
+	} else {
+		if config.Text != "" {
+			form.Set("text", config.Text)
+		}
+		if config.HTML != "" {
+			form.Set("html", config.HTML)
+		}
+	}
+
+	operation := config.operation()
+	waited, err := e.limiter.Acquire(ctx, "mailgun")
+	if err != nil {
+		return rateLimitedResponse(req, "mailgun", operation, "mailgun", waited, err, logs, start), nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Sending email to %d recipient(s) via Mailgun", len(config.To)),
+	})
+
+	endpoint := fmt.Sprintf("%s/v3/%s/messages", baseURL, url.PathEscape(config.Domain))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return emailConfigError(fmt.Sprintf("failed to create request: %v", err), logs, start), nil
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.SetBasicAuth("api", config.APIKey)
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return emailNetworkError(req, "mailgun", operation, err, waited, logs, start), nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, emailProviderMaxLen))
+
+	attempt := newConnectorAttempt(req, "mailgun", operation, "mailgun", "success", start, waited)
+	attempt.StatusCode = int32(resp.StatusCode)
+
+	if resp.StatusCode >= 400 {
+		return emailProviderError(resp.StatusCode, "Mailgun", strings.TrimSpace(string(respBody)), attempt, logs, start), nil
+	}
+
+	var result struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(respBody, &result)
+
+	return emailSentResponse("mailgun", result.ID, resp.StatusCode, config.To, attempt, logs, start), nil
+}
+
+func emailConfigError(message string, logs []LogEntry, start time.Time) *ExecuteResponse {
+	return &ExecuteResponse{
+		Error: &ExecutionError{
+			Message: message,
+			Type:    ErrorTypeNonRetryable,
+		},
+		Logs:     logs,
+		Duration: time.Since(start),
+	}
+}
+
+func emailNetworkError(req *ExecuteRequest, provider, operation string, err error, waited time.Duration, logs []LogEntry, start time.Time) *ExecuteResponse {
+	attempt := newConnectorAttempt(req, provider, operation, provider, "network_error", start, waited)
+	attempt.ErrorCode = strings.ToUpper(provider) + "_REQUEST_FAILED"
+	attempt.ErrorMessage = err.Error()
+	return &ExecuteResponse{
+		Error: &ExecutionError{
+			Message: fmt.Sprintf("request failed: %v", err),
+			Type:    ErrorTypeRetryable,
+		},
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}
+}
+
+// emailProviderError maps a provider error status: throttling and server
+// errors are retryable, bad requests and auth failures are not.
+func emailProviderError(statusCode int, providerName, body string, attempt ConnectorAttempt, logs []LogEntry, start time.Time) *ExecuteResponse {
+	errorType := ErrorTypeNonRetryable
+	attempt.Status = "client_error"
+	switch {
+	case statusCode == 429:
+		errorType = ErrorTypeRetryable
+		attempt.Status = "throttled"
+		attempt.ErrorCode = "RATE_LIMITED"
+	case statusCode >= 500:
+		errorType = ErrorTypeRetryable
+		attempt.Status = "server_error"
+	case statusCode == 401 || statusCode == 403:
+		attempt.ErrorCode = "UNAUTHORIZED"
+	}
+	attempt.ErrorMessage = body
+
+	return &ExecuteResponse{
+		Error: &ExecutionError{
+			Message: fmt.Sprintf("%s error (status %d): %s", providerName, statusCode, body),
+			Type:    errorType,
+		},
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}
+}
+
+func emailSentResponse(provider, messageID string, statusCode int, recipients []string, attempt ConnectorAttempt, logs []LogEntry, start time.Time) *ExecuteResponse {
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Email accepted by %s (message_id=%s)", provider, messageID),
+	})
+
+	output, _ := json.Marshal(map[string]interface{}{
+		"success":     true,
+		"provider":    provider,
+		"message_id":  messageID,
+		"status_code": statusCode,
+		"recipients":  recipients,
+	})
+
+	return &ExecuteResponse{
+		Output:            output,
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}
+}
@@ -0,0 +1,217 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSendGridExecutor(t *testing.T) {
+	t.Parallel()
+
+	status := http.StatusAccepted
+	var auth string
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/mail/send" {
+			t.Errorf("request path = %s", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		received = nil
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("X-Message-Id", "sg-1")
+		w.WriteHeader(status)
+		if status >= 400 {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"denied"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	executor := NewSendGridExecutor().WithAPIKey("default-key").WithRateLimiter(NewConnectorRateLimiter(nil))
+	executor.client = server.Client()
+	executor.baseURL = server.URL
+
+	tests := []struct {
+		name      string
+		config    string
+		status    int
+		wantError string
+		check     func(t *testing.T)
+	}{
+		{
+			name:   "plain email",
+			config: `{"from":"ops@example.com","from_name":"Ops","to":["a@example.com","b@example.com"],"subject":"Hi","text":"hello","html":"<p>hello</p>"}`,
+			status: http.StatusAccepted,
+			check: func(t *testing.T) {
+				if auth != "Bearer default-key" {
+					t.Fatalf("authorization = %q", auth)
+				}
+				from, _ := received["from"].(map[string]interface{})
+				content, _ := received["content"].([]interface{})
+				personalizations, _ := received["personalizations"].([]interface{})
+				if from["name"] != "Ops" || received["subject"] != "Hi" || len(content) != 2 || len(personalizations) != 1 {
+					t.Fatalf("payload = %v", received)
+				}
+				if to, _ := personalizations[0].(map[string]interface{})["to"].([]interface{}); len(to) != 2 {
+					t.Fatalf("recipients = %v", personalizations[0])
+				}
+			},
+		},
+		{
+			name:   "dynamic template",
+			config: `{"api_key":"node-key","from":"ops@example.com","to":["a@example.com"],"template_id":"d-123","template_data":{"name":"Ann"}}`,
+			status: http.StatusAccepted,
+			check: func(t *testing.T) {
+				personalization, _ := received["personalizations"].([]interface{})[0].(map[string]interface{})
+				data, _ := personalization["dynamic_template_data"].(map[string]interface{})
+				if auth != "Bearer node-key" || received["template_id"] != "d-123" || data["name"] != "Ann" || received["content"] != nil {
+					t.Fatalf("payload = %v with %q", received, auth)
+				}
+			},
+		},
+		{name: "missing from", config: `{"to":["a@example.com"],"subject":"Hi","text":"hello"}`, wantError: ErrorTypeNonRetryable},
+		{name: "missing body", config: `{"from":"ops@example.com","to":["a@example.com"],"subject":"Hi"}`, wantError: ErrorTypeNonRetryable},
+		{name: "unauthorized", config: `{"from":"ops@example.com","to":["a@example.com"],"subject":"Hi","text":"hello"}`, status: http.StatusUnauthorized, wantError: ErrorTypeNonRetryable},
+		{name: "throttled", config: `{"from":"ops@example.com","to":["a@example.com"],"subject":"Hi","text":"hello"}`, status: http.StatusTooManyRequests, wantError: ErrorTypeRetryable},
+		{name: "server error", config: `{"from":"ops@example.com","to":["a@example.com"],"subject":"Hi","text":"hello"}`, status: http.StatusServiceUnavailable, wantError: ErrorTypeRetryable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status = tt.status
+			resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+				NodeType: "sendgrid",
+				NodeID:   "mail",
+				Config:   json.RawMessage(tt.config),
+				Attempt:  1,
+			})
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if tt.wantError != "" {
+				if resp.Error == nil || resp.Error.Type != tt.wantError {
+					t.Fatalf("error = %+v, want %s", resp.Error, tt.wantError)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %+v", resp.Error)
+			}
+			var output map[string]interface{}
+			_ = json.Unmarshal(resp.Output, &output)
+			if output["message_id"] != "sg-1" || output["provider"] != "sendgrid" {
+				t.Fatalf("output = %s", resp.Output)
+			}
+			tt.check(t)
+		})
+	}
+}
+
+func TestMailgunExecutor(t *testing.T) {
+	t.Parallel()
+
+	status := http.StatusOK
+	var path, user, password string
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		user, password, _ = r.BasicAuth()
+		_ = r.ParseForm()
+		form = r.PostForm
+		w.WriteHeader(status)
+		if status >= 400 {
+			_, _ = w.Write([]byte(`{"message":"Forbidden"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"<mg-1@example.com>","message":"Queued. Thank you."}`))
+	}))
+	defer server.Close()
+
+	executor := NewMailgunExecutor().WithCredentials("default-key", "mg.example.com").WithRateLimiter(NewConnectorRateLimiter(nil))
+	executor.client = server.Client()
+	executor.baseURL = server.URL
+
+	tests := []struct {
+		name      string
+		config    string
+		status    int
+		wantError string
+		check     func(t *testing.T)
+	}{
+		{
+			name:   "plain email",
+			config: `{"from":"ops@example.com","from_name":"Ops","to":["a@example.com","b@example.com"],"subject":"Hi","text":"hello"}`,
+			status: http.StatusOK,
+			check: func(t *testing.T) {
+				if path != "/v3/mg.example.com/messages" || user != "api" || password != "default-key" {
+					t.Fatalf("sent to %s as %s:%s", path, user, password)
+				}
+				if form.Get("from") != "Ops <ops@example.com>" || len(form["to"]) != 2 || form.Get("text") != "hello" || form.Get("subject") != "Hi" {
+					t.Fatalf("form = %v", form)
+				}
+			},
+		},
+		{
+			name:   "template on another domain",
+			config: `{"domain":"eu.example.com","from":"ops@example.com","to":["a@example.com"],"template_id":"welcome","template_data":{"name":"Ann"}}`,
+			status: http.StatusOK,
+			check: func(t *testing.T) {
+				if path != "/v3/eu.example.com/messages" || form.Get("template") != "welcome" || form.Get("t:variables") != `{"name":"Ann"}` {
+					t.Fatalf("sent %v to %s", form, path)
+				}
+			},
+		},
+		{name: "missing recipients", config: `{"from":"ops@example.com","subject":"Hi","text":"hello"}`, wantError: ErrorTypeNonRetryable},
+		{name: "forbidden", config: `{"from":"ops@example.com","to":["a@example.com"],"subject":"Hi","text":"hello"}`, status: http.StatusForbidden, wantError: ErrorTypeNonRetryable},
+		{name: "server error", config: `{"from":"ops@example.com","to":["a@example.com"],"subject":"Hi","text":"hello"}`, status: http.StatusBadGateway, wantError: ErrorTypeRetryable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status = tt.status
+			resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+				NodeType: "mailgun",
+				NodeID:   "mail",
+				Config:   json.RawMessage(tt.config),
+				Attempt:  1,
+			})
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if tt.wantError != "" {
+				if resp.Error == nil || resp.Error.Type != tt.wantError {
+					t.Fatalf("error = %+v, want %s", resp.Error, tt.wantError)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %+v", resp.Error)
+			}
+			var output map[string]interface{}
+			_ = json.Unmarshal(resp.Output, &output)
+			if output["message_id"] != "<mg-1@example.com>" || output["provider"] != "mailgun" {
+				t.Fatalf("output = %s", resp.Output)
+			}
+			tt.check(t)
+		})
+	}
+}
+
+func TestMailgunExecutorRegions(t *testing.T) {
+	t.Parallel()
+
+	executor := NewMailgunExecutor().WithCredentials("key", "mg.example.com")
+	resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+		NodeType: "mailgun",
+		Config:   json.RawMessage(`{"region":"ap","from":"ops@example.com","to":["a@example.com"],"subject":"Hi","text":"hello"}`),
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("unsupported region error = %+v", resp.Error)
+	}
+}
@@ -15,7 +15,9 @@ import (
 )
 
 type HTTPExecutor struct {
-	client *http.Client
+	client  *http.Client
+	limiter *ConnectorRateLimiter
+	budgets *retryBudgetTracker
 }
 
 type HTTPConfig struct {
@@ -24,6 +26,18 @@ type HTTPConfig struct {
 	Headers map[string]string `json:"headers"`
 	Body    json.RawMessage   `json:"body"`
 	Timeout int               `json:"timeout"`
+
+	// Encoding compresses the request body: gzip or deflate (default none).
+	Encoding string `json:"encoding,omitempty"`
+
+	// BodyMode "multipart" sends Fields and Files as multipart/form-data
+	// instead of Body (default json).
+	BodyMode string              `json:"body_mode,omitempty"`
+	Fields   map[string]string   `json:"fields,omitempty"`
+	Files    []HTTPMultipartFile `json:"files,omitempty"`
+
+	RetryBudget     *HTTPRetryBudget `json:"retry_budget,omitempty"`
+	RetryClassifier *RetryClassifier `json:"retry_classifier,omitempty"`
 }
 
 type HTTPResponse struct {
@@ -40,7 +54,7 @@ func NewHTTPExecutor() *HTTPExecutor {
 		MaxIdleConnsPerHost: 20,               // Max idle connections per host
 		MaxConnsPerHost:     50,               // Max total connections per host
 		IdleConnTimeout:     90 * time.Second, // How long idle connections stay in pool
-		DisableCompression:  false,            // Enable compression
+		DisableCompression:  true,             // Responses are decoded by decodeResponseBody
 		ForceAttemptHTTP2:   true,             // Prefer HTTP/2 when available
 	}
 
@@ -49,14 +63,126 @@ func NewHTTPExecutor() *HTTPExecutor {
 			Timeout:   30 * time.Second,
 			Transport: transport,
 		},
+		limiter: DefaultConnectorRateLimiter(),
+		budgets: newRetryBudgetTracker(),
 	}
 }
 
+// WithRateLimiter sets the limiter used to throttle outbound requests per host.
+func (e *HTTPExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *HTTPExecutor {
+	e.limiter = limiter
+	return e
+}
+
 func (e *HTTPExecutor) NodeType() string {
 	return "action_http_request"
 }
 
+// UsesSecrets reports that headers, URL and body may reference secrets.
+func (e *HTTPExecutor) UsesSecrets() bool {
+	return true
+}
+
+var httpInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["url"],
+  "properties": {
+    "method": {"type": "string", "examples": ["GET", "POST", "PUT", "PATCH", "DELETE"], "default": "GET"},
+    "url": {"type": "string", "format": "uri"},
+    "headers": {"type": "object", "additionalProperties": {"type": "string"}},
+    "body": {},
+    "timeout": {"type": "integer", "minimum": 0, "description": "Request timeout in seconds"},
+    "encoding": {"type": "string", "enum": ["", "identity", "gzip", "deflate"], "description": "Compress the request body"},
+    "body_mode": {"type": "string", "enum": ["", "json", "multipart"], "default": "json"},
+    "fields": {"type": "object", "additionalProperties": {"type": "string"}, "description": "Form fields sent in multipart mode"},
+    "files": {
+      "type": "array",
+      "description": "Files uploaded in multipart mode; at most 25MB in total",
+      "items": {
+        "type": "object",
+        "required": ["field", "content_base64"],
+        "properties": {
+          "field": {"type": "string"},
+          "filename": {"type": "string"},
+          "content_base64": {"type": "string", "contentEncoding": "base64"},
+          "content_type": {"type": "string", "default": "application/octet-stream"}
+        }
+      }
+    },
+    "retry_classifier": {
+      "type": "object",
+      "description": "Overrides the error type of the result; the first matching rule wins",
+      "properties": {
+        "rules": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "required": ["error_type"],
+            "properties": {
+              "status_codes": {"type": "array", "items": {"type": ["integer", "string"]}, "description": "Codes, ranges (400-499) or classes (5xx)"},
+              "body_path": {"type": "string", "description": "JSON path into the response body, e.g. $.error.code"},
+              "body_equals": {"description": "Value at body_path to match; any non-null value if unset"},
+              "error_contains": {"type": "string"},
+              "error_type": {"type": "string", "enum": ["RETRYABLE", "NON_RETRYABLE", "TIMEOUT"]},
+              "message": {"type": "string"}
+            }
+          }
+        }
+      }
+    },
+    "retry_budget": {
+      "type": "object",
+      "description": "Limits across all attempts; a retryable failure past either limit is terminal",
+      "properties": {
+        "max_attempts": {"type": "integer", "minimum": 0},
+        "max_total_seconds": {"type": "integer", "minimum": 0}
+      }
+    }
+  }
+}`)
+
+var httpOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["status_code", "headers"],
+  "properties": {
+    "status_code": {"type": "integer"},
+    "headers": {"type": "object", "additionalProperties": {"type": "string"}},
+    "body": {}
+  }
+}`)
+
+func (e *HTTPExecutor) InputSchema() json.RawMessage {
+	return httpInputSchema
+}
+
+func (e *HTTPExecutor) OutputSchema() json.RawMessage {
+	return httpOutputSchema
+}
+
+// UsesRetryClassifier reports that the executor classifies its own results,
+// so the retry budget sees the classified error type.
+func (e *HTTPExecutor) UsesRetryClassifier() bool {
+	return true
+}
+
 func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	classifier, err := ParseRetryClassifier(req.Config)
+	if err != nil {
+		return &ExecuteResponse{
+			Error: &ExecutionError{Message: err.Error(), Type: ErrorTypeNonRetryable},
+		}, nil
+	}
+	if budget := parseRetryBudget(req.Config); budget != nil {
+		return e.executeWithBudget(ctx, req, budget, classifier)
+	}
+	resp, err := e.execute(ctx, req)
+	if err == nil {
+		classifier.applyHTTP(resp)
+	}
+	return resp, err
+}
+
+func (e *HTTPExecutor) execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
 	start := time.Now()
 	logs := make([]LogEntry, 0)
 	connectorAttempts := make([]ConnectorAttempt, 0, 1)
@@ -84,6 +210,9 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 
 	if config.Method == "" {
 		config.Method = "GET"
+		if config.BodyMode == httpBodyModeMultipart {
+			config.Method = "POST"
+		}
 	}
 
 	if config.Timeout > 0 {
@@ -119,11 +248,45 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 		}, nil
 	}
 
+	var fingerprintBody interface{} = json.RawMessage(config.Body)
+	rawBody := []byte(config.Body)
+	var multipartReq *multipartBody
+	switch config.BodyMode {
+	case "", "json":
+	case httpBodyModeMultipart:
+		var err error
+		if multipartReq, err = buildMultipartBody(config.Fields, config.Files); err != nil {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: fmt.Sprintf("failed to build multipart body: %v", err),
+					Type:    ErrorTypeNonRetryable,
+				},
+				ConnectorAttempts:     connectorAttempts,
+				DeterministicFixtures: fixtures,
+				Logs:                  logs,
+				Duration:              time.Since(start),
+			}, nil
+		}
+		fingerprintBody = multipartReq.fingerprint
+		rawBody = multipartReq.body
+	default:
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("unsupported body_mode: %s", config.BodyMode),
+				Type:    ErrorTypeNonRetryable,
+			},
+			ConnectorAttempts:     connectorAttempts,
+			DeterministicFixtures: fixtures,
+			Logs:                  logs,
+			Duration:              time.Since(start),
+		}, nil
+	}
+
 	requestBytes, _ := json.Marshal(map[string]interface{}{
 		"method":  config.Method,
 		"url":     config.URL,
 		"headers": canonicalHeaders(config.Headers),
-		"body":    json.RawMessage(config.Body),
+		"body":    fingerprintBody,
 	})
 	requestFingerprint := fmt.Sprintf("%x", sha256.Sum256(requestBytes))
 
@@ -189,9 +352,55 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 		}, nil
 	}
 
+	provider := "http:" + parsedURL.Hostname()
+	waited, err := e.limiter.Acquire(ctx, provider)
+	if err != nil {
+		resp := rateLimitedResponse(req, "action_http_request", "request", provider, waited, err, logs, start)
+		resp.ConnectorAttempts[0].RequestFingerprint = requestFingerprint
+		resp.DeterministicFixtures = fixtures
+		return resp, nil
+	}
+	if waited > 0 {
+		logs = append(logs, LogEntry{
+			Timestamp: time.Now(),
+			Level:     "DEBUG",
+			Message:   fmt.Sprintf("Waited %s for %s rate limit", waited.Round(time.Millisecond), provider),
+		})
+	}
+
+	// The fingerprint above covers the uncompressed body, so compression
+	// does not affect deterministic replay.
+	requestBody, contentEncoding, err := encodeRequestBody(config.Encoding, rawBody)
+	if err != nil {
+		connectorAttempts = append(connectorAttempts, ConnectorAttempt{
+			NodeID:             req.NodeID,
+			ConnectorKey:       "action_http_request",
+			ConnectorOperation: "request",
+			Provider:           "http",
+			AttemptNo:          req.Attempt,
+			IsRetry:            req.Attempt > 1,
+			Status:             "client_error",
+			ErrorCode:          "HTTP_REQUEST_ENCODE_FAILED",
+			ErrorMessage:       err.Error(),
+			RequestFingerprint: requestFingerprint,
+			HappenedAt:         time.Now().UTC(),
+			Meta:               rateLimitMeta(waited),
+		})
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("failed to encode request body: %v", err),
+				Type:    ErrorTypeNonRetryable,
+			},
+			ConnectorAttempts:     connectorAttempts,
+			DeterministicFixtures: fixtures,
+			Logs:                  logs,
+			Duration:              time.Since(start),
+		}, nil
+	}
+
 	var bodyReader io.Reader
-	if len(config.Body) > 0 {
-		bodyReader = bytes.NewReader(config.Body)
+	if len(requestBody) > 0 {
+		bodyReader = bytes.NewReader(requestBody)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, config.Method, config.URL, bodyReader)
@@ -208,6 +417,7 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 			ErrorMessage:       err.Error(),
 			RequestFingerprint: requestFingerprint,
 			HappenedAt:         time.Now().UTC(),
+			Meta:               rateLimitMeta(waited),
 		})
 		return &ExecuteResponse{
 			Error: &ExecutionError{
@@ -224,6 +434,17 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 	for key, value := range config.Headers {
 		httpReq.Header.Set(key, value)
 	}
+	if multipartReq != nil {
+		// The boundary is generated per request, so it overrides any
+		// configured Content-Type.
+		httpReq.Header.Set("Content-Type", multipartReq.contentType)
+	}
+	if contentEncoding != "" && len(requestBody) > 0 {
+		httpReq.Header.Set("Content-Encoding", contentEncoding)
+	}
+	if httpReq.Header.Get("Accept-Encoding") == "" {
+		httpReq.Header.Set("Accept-Encoding", httpAcceptEncoding)
+	}
 
 	logs = append(logs, LogEntry{
 		Timestamp: time.Now(),
@@ -231,7 +452,7 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 		Message:   fmt.Sprintf("Sending %s request to %s", config.Method, config.URL),
 	})
 
-	resp, err := e.client.Do(httpReq)
+	resp, err := e.client.Do(traceHTTPRequest(httpReq))
 	if err != nil {
 		errorType := ErrorTypeRetryable
 		attemptStatus := "network_error"
@@ -254,6 +475,7 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 			ErrorMessage:       err.Error(),
 			RequestFingerprint: requestFingerprint,
 			HappenedAt:         time.Now().UTC(),
+			Meta:               rateLimitMeta(waited),
 		})
 
 		return &ExecuteResponse{
@@ -270,7 +492,12 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 	defer resp.Body.Close()
 
 	const maxResponseBody = 10 * 1024 * 1024 // 10MB
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody+1))
+	// The limit applies to the decompressed body.
+	bodyReader, decoded, err := decodeResponseBody(resp)
+	var body []byte
+	if err == nil {
+		body, err = io.ReadAll(io.LimitReader(bodyReader, maxResponseBody+1))
+	}
 	if err != nil {
 		connectorAttempts = append(connectorAttempts, ConnectorAttempt{
 			NodeID:             req.NodeID,
@@ -285,6 +512,7 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 			ErrorMessage:       err.Error(),
 			RequestFingerprint: requestFingerprint,
 			HappenedAt:         time.Now().UTC(),
+			Meta:               rateLimitMeta(waited),
 		})
 		return &ExecuteResponse{
 			Error: &ExecutionError{
@@ -312,6 +540,7 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 			ErrorMessage:       fmt.Sprintf("response body exceeds %d bytes limit", maxResponseBody),
 			RequestFingerprint: requestFingerprint,
 			HappenedAt:         time.Now().UTC(),
+			Meta:               rateLimitMeta(waited),
 		})
 		return &ExecuteResponse{
 			Error: &ExecutionError{
@@ -335,6 +564,11 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 	for key := range resp.Header {
 		headers[key] = resp.Header.Get(key)
 	}
+	if decoded {
+		// The headers describe the compressed body, not the one returned.
+		delete(headers, "Content-Encoding")
+		delete(headers, "Content-Length")
+	}
 
 	// Handle body - ensure it's valid JSON for marshaling
 	var jsonBody json.RawMessage
@@ -371,6 +605,7 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 			ErrorMessage:       err.Error(),
 			RequestFingerprint: requestFingerprint,
 			HappenedAt:         time.Now().UTC(),
+			Meta:               rateLimitMeta(waited),
 		})
 		return &ExecuteResponse{
 			Error: &ExecutionError{
@@ -410,6 +645,7 @@ func (e *HTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Execu
 		DurationMS:         time.Since(start).Milliseconds(),
 		RequestFingerprint: requestFingerprint,
 		HappenedAt:         time.Now().UTC(),
+		Meta:               rateLimitMeta(waited),
 	})
 
 	if resp.StatusCode >= 500 {
@@ -0,0 +1,460 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	httpBatchDefaultConcurrency = 5
+	httpBatchMaxConcurrency     = 50
+	httpBatchMaxItems           = 1000
+)
+
+// httpBatchPlaceholder matches {{name}} placeholders in the request template.
+var httpBatchPlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// BatchHTTPExecutor sends one request per item, built from a shared request
+// template, with a bounded number in flight. Each request goes through the
+// HTTP executor, so it shares its connection pool, rate limits and SSRF
+// checks, and is checked against the URL it resolves to.
+type BatchHTTPExecutor struct {
+	http *HTTPExecutor
+}
+
+// BatchHTTPConfig represents the configuration for an action_http_batch node.
+// Placeholders such as {{id}} in the request URL, headers, form fields and
+// body are replaced by the item's field of that name. A body string that is
+// exactly one placeholder takes the field's JSON value, keeping its type.
+type BatchHTTPConfig struct {
+	Request        HTTPConfig                   `json:"request"`
+	Items          []map[string]json.RawMessage `json:"items"`
+	MaxConcurrency int                          `json:"max_concurrency"` // Requests in flight (default 5, at most 50)
+	Timeout        int                          `json:"timeout"`         // Seconds for the whole batch (0 = no limit)
+	FailFast       bool                         `json:"fail_fast"`       // Stop at the first failed item and fail the node
+}
+
+// BatchHTTPResult is the outcome of one item, at the item's index in the
+// output.
+type BatchHTTPResult struct {
+	Index      int               `json:"index"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+	Error      *BatchHTTPError   `json:"error,omitempty"`
+}
+
+// BatchHTTPError describes why one item failed; Type is one of the
+// ErrorType constants.
+type BatchHTTPError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// BatchHTTPResponse is the output of an action_http_batch node.
+type BatchHTTPResponse struct {
+	Results   []BatchHTTPResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// NewBatchHTTPExecutor creates a batch executor that sends its requests
+// through httpExecutor.
+func NewBatchHTTPExecutor(httpExecutor *HTTPExecutor) *BatchHTTPExecutor {
+	return &BatchHTTPExecutor{http: httpExecutor}
+}
+
+func (e *BatchHTTPExecutor) NodeType() string {
+	return "action_http_batch"
+}
+
+var httpBatchInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["request", "items"],
+  "properties": {
+    "request": {
+      "type": "object",
+      "required": ["url"],
+      "description": "Request template; {{name}} placeholders are replaced by each item's fields",
+      "properties": {
+        "method": {"type": "string", "default": "GET"},
+        "url": {"type": "string"},
+        "headers": {"type": "object", "additionalProperties": {"type": "string"}},
+        "body": {},
+        "timeout": {"type": "integer", "minimum": 0, "description": "Per-request timeout in seconds"},
+        "encoding": {"type": "string", "enum": ["", "identity", "gzip", "deflate"]},
+        "body_mode": {"type": "string", "enum": ["", "json", "multipart"], "default": "json"},
+        "fields": {"type": "object", "additionalProperties": {"type": "string"}}
+      }
+    },
+    "items": {"type": "array", "maxItems": 1000, "items": {"type": "object"}},
+    "max_concurrency": {"type": "integer", "minimum": 0, "maximum": 50, "default": 5},
+    "timeout": {"type": "integer", "minimum": 0, "description": "Seconds for the whole batch, 0 for no limit"},
+    "fail_fast": {"type": "boolean", "default": false}
+  }
+}`)
+
+var httpBatchOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["results", "succeeded", "failed"],
+  "properties": {
+    "results": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["index"],
+        "properties": {
+          "index": {"type": "integer"},
+          "status_code": {"type": "integer"},
+          "headers": {"type": "object", "additionalProperties": {"type": "string"}},
+          "body": {},
+          "error": {
+            "type": "object",
+            "properties": {
+              "message": {"type": "string"},
+              "type": {"type": "string"}
+            }
+          }
+        }
+      }
+    },
+    "succeeded": {"type": "integer"},
+    "failed": {"type": "integer"}
+  }
+}`)
+
+func (e *BatchHTTPExecutor) InputSchema() json.RawMessage {
+	return httpBatchInputSchema
+}
+
+func (e *BatchHTTPExecutor) OutputSchema() json.RawMessage {
+	return httpBatchOutputSchema
+}
+
+func (e *BatchHTTPExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: ErrorTypeNonRetryable},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config BatchHTTPConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse http batch config: %v", err))
+	}
+	if err := config.validate(); err != nil {
+		return failed(err.Error())
+	}
+
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Sending %d requests for node %s, %d at a time", len(config.Items), req.NodeID, config.concurrency()),
+	})
+
+	results := make([]BatchHTTPResult, len(config.Items))
+	responses := make([]*ExecuteResponse, len(config.Items))
+	var (
+		mu        sync.Mutex
+		firstFail *ExecutionError
+		wg        sync.WaitGroup
+	)
+	sem := make(chan struct{}, config.concurrency())
+
+	for i, item := range config.Items {
+		results[i].Index = i
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			mu.Lock()
+			results[i].Error = httpBatchNotSent(ctx, firstFail != nil)
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, item map[string]json.RawMessage) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp := e.executeItem(ctx, req, config.Request, item)
+			responses[i] = resp
+			fillBatchHTTPResult(&results[i], resp)
+
+			if results[i].Error != nil && config.FailFast {
+				mu.Lock()
+				if firstFail == nil {
+					firstFail = &ExecutionError{
+						Message: fmt.Sprintf("item %d: %s", i, results[i].Error.Message),
+						Type:    results[i].Error.Type,
+					}
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	output := BatchHTTPResponse{Results: results}
+	var attempts []ConnectorAttempt
+	var fixtures []DeterministicFixture
+	for i := range results {
+		if results[i].Error != nil {
+			output.Failed++
+		} else {
+			output.Succeeded++
+		}
+		if responses[i] != nil {
+			attempts = append(attempts, responses[i].ConnectorAttempts...)
+			fixtures = append(fixtures, responses[i].DeterministicFixtures...)
+		}
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Batch completed: %d succeeded, %d failed", output.Succeeded, output.Failed),
+	})
+
+	encoded, err := json.Marshal(output)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err))
+	}
+
+	resp := &ExecuteResponse{
+		Output:                encoded,
+		ConnectorAttempts:     attempts,
+		DeterministicFixtures: fixtures,
+		Logs:                  logs,
+		Duration:              time.Since(start),
+	}
+	if firstFail != nil {
+		resp.Error = firstFail
+	} else if config.FailFast && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		resp.Error = &ExecutionError{Message: "http batch timed out", Type: ErrorTypeTimeout}
+	}
+	return resp, nil
+}
+
+func (c *BatchHTTPConfig) concurrency() int {
+	if c.MaxConcurrency <= 0 {
+		return httpBatchDefaultConcurrency
+	}
+	return c.MaxConcurrency
+}
+
+func (c *BatchHTTPConfig) validate() error {
+	if c.Request.URL == "" {
+		return errors.New("request.url is required")
+	}
+	if len(c.Items) > httpBatchMaxItems {
+		return fmt.Errorf("too many items: %d (max %d)", len(c.Items), httpBatchMaxItems)
+	}
+	if c.MaxConcurrency < 0 || c.MaxConcurrency > httpBatchMaxConcurrency {
+		return fmt.Errorf("max_concurrency must be between 0 and %d", httpBatchMaxConcurrency)
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if len(c.Request.Files) > 0 {
+		return errors.New("file uploads are not supported in batch requests")
+	}
+	if c.Request.RetryBudget != nil {
+		return errors.New("retry_budget is not supported in batch requests")
+	}
+	return nil
+}
+
+// executeItem resolves the request template for item and sends it through
+// the HTTP executor. Retrying a batch is left to the node's retry policy.
+func (e *BatchHTTPExecutor) executeItem(ctx context.Context, req *ExecuteRequest, template HTTPConfig, item map[string]json.RawMessage) *ExecuteResponse {
+	config, err := resolveBatchHTTPRequest(template, item)
+	if err != nil {
+		return &ExecuteResponse{Error: &ExecutionError{Message: err.Error(), Type: ErrorTypeNonRetryable}}
+	}
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return &ExecuteResponse{Error: &ExecutionError{Message: fmt.Sprintf("failed to encode request: %v", err), Type: ErrorTypeNonRetryable}}
+	}
+
+	itemReq := *req
+	itemReq.Config = encoded
+	itemReq.Progress = nil
+	resp, err := e.http.execute(ctx, &itemReq)
+	if err != nil {
+		return &ExecuteResponse{Error: &ExecutionError{Message: err.Error(), Type: ErrorTypeRetryable}}
+	}
+	return resp
+}
+
+// fillBatchHTTPResult copies the response and any error of one item into its
+// result. HTTP error statuses keep their response alongside the error.
+func fillBatchHTTPResult(result *BatchHTTPResult, resp *ExecuteResponse) {
+	if resp.Error != nil {
+		result.Error = &BatchHTTPError{Message: resp.Error.Message, Type: resp.Error.Type}
+	}
+	if len(resp.Output) == 0 {
+		return
+	}
+	var httpResp HTTPResponse
+	if err := json.Unmarshal(resp.Output, &httpResp); err != nil {
+		if result.Error == nil {
+			result.Error = &BatchHTTPError{Message: fmt.Sprintf("invalid response: %v", err), Type: ErrorTypeNonRetryable}
+		}
+		return
+	}
+	result.StatusCode = httpResp.StatusCode
+	result.Headers = httpResp.Headers
+	result.Body = httpResp.Body
+}
+
+// httpBatchNotSent is the error of an item skipped because the batch stopped
+// before its turn.
+func httpBatchNotSent(ctx context.Context, failedFast bool) *BatchHTTPError {
+	switch {
+	case failedFast:
+		return &BatchHTTPError{Message: "not sent: an earlier item failed", Type: ErrorTypeNonRetryable}
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &BatchHTTPError{Message: "not sent: batch timed out", Type: ErrorTypeTimeout}
+	default:
+		return &BatchHTTPError{Message: "not sent: batch canceled", Type: ErrorTypeRetryable}
+	}
+}
+
+// resolveBatchHTTPRequest returns template with the placeholders replaced by
+// the fields of item. Values are path-escaped in the URL.
+func resolveBatchHTTPRequest(template HTTPConfig, item map[string]json.RawMessage) (HTTPConfig, error) {
+	config := template
+	var err error
+
+	if config.URL, err = substituteBatchHTTPText(template.URL, item, url.PathEscape); err != nil {
+		return config, fmt.Errorf("url: %w", err)
+	}
+	if len(template.Headers) > 0 {
+		config.Headers = make(map[string]string, len(template.Headers))
+		for key, value := range template.Headers {
+			if config.Headers[key], err = substituteBatchHTTPText(value, item, nil); err != nil {
+				return config, fmt.Errorf("header %s: %w", key, err)
+			}
+		}
+	}
+	if len(template.Fields) > 0 {
+		config.Fields = make(map[string]string, len(template.Fields))
+		for key, value := range template.Fields {
+			if config.Fields[key], err = substituteBatchHTTPText(value, item, nil); err != nil {
+				return config, fmt.Errorf("field %s: %w", key, err)
+			}
+		}
+	}
+	if len(template.Body) > 0 {
+		if config.Body, err = substituteBatchHTTPBody(template.Body, item); err != nil {
+			return config, fmt.Errorf("body: %w", err)
+		}
+	}
+	return config, nil
+}
+
+// substituteBatchHTTPText replaces the placeholders in s with the text of
+// the item's fields: strings unquoted, other values as JSON.
+func substituteBatchHTTPText(s string, item map[string]json.RawMessage, escape func(string) string) (string, error) {
+	var missing string
+	out := httpBatchPlaceholder.ReplaceAllStringFunc(s, func(match string) string {
+		name := httpBatchPlaceholder.FindStringSubmatch(match)[1]
+		raw, ok := item[name]
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			return match
+		}
+		text := string(bytes.TrimSpace(raw))
+		var str string
+		if json.Unmarshal(raw, &str) == nil {
+			text = str
+		}
+		if escape != nil {
+			text = escape(text)
+		}
+		return text
+	})
+	if missing != "" {
+		return "", fmt.Errorf("item has no field %q", missing)
+	}
+	return out, nil
+}
+
+// substituteBatchHTTPBody replaces the placeholders in the strings of a JSON
+// body. A string that is exactly one placeholder becomes the field's value.
+func substituteBatchHTTPBody(body json.RawMessage, item map[string]json.RawMessage) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var walk func(v interface{}) (interface{}, error)
+	walk = func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case string:
+			if m := httpBatchPlaceholder.FindStringSubmatch(v); m != nil && m[0] == strings.TrimSpace(v) {
+				raw, ok := item[m[1]]
+				if !ok {
+					return nil, fmt.Errorf("item has no field %q", m[1])
+				}
+				return raw, nil
+			}
+			return substituteBatchHTTPText(v, item, nil)
+		case map[string]interface{}:
+			for key, elem := range v {
+				resolved, err := walk(elem)
+				if err != nil {
+					return nil, err
+				}
+				v[key] = resolved
+			}
+			return v, nil
+		case []interface{}:
+			for i, elem := range v {
+				resolved, err := walk(elem)
+				if err != nil {
+					return nil, err
+				}
+				v[i] = resolved
+			}
+			return v, nil
+		default:
+			return v, nil
+		}
+	}
+
+	resolved, err := walk(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resolved)
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestBatchHTTPExecutorOrdersResultsAndBlocksPrivateURLs(t *testing.T) {
+	t.Parallel()
+
+	fixture := func(id int) DeterministicFixture {
+		request, _ := json.Marshal(map[string]interface{}{
+			"method":  "POST",
+			"url":     fmt.Sprintf("https://example.com/users/%d", id),
+			"headers": map[string]string{"X-User": fmt.Sprint(id)},
+			"body":    json.RawMessage(fmt.Sprintf(`{"id":%d,"note":"user %d"}`, id, id)),
+		})
+		return DeterministicFixture{
+			RequestFingerprint: fmt.Sprintf("%x", sha256.Sum256(request)),
+			Response:           json.RawMessage(fmt.Sprintf(`{"status_code":200,"headers":{},"body":{"user":%d}}`, id)),
+		}
+	}
+
+	run := func(failFast bool) (*ExecuteResponse, BatchHTTPResponse) {
+		config, _ := json.Marshal(map[string]interface{}{
+			"request": map[string]interface{}{
+				"method":  "POST",
+				"url":     "https://{{host}}/users/{{id}}",
+				"headers": map[string]string{"X-User": "{{id}}"},
+				"body":    map[string]string{"id": "{{id}}", "note": "user {{ id }}"},
+			},
+			"items": []map[string]interface{}{
+				{"id": 1, "host": "example.com"},
+				{"id": 2, "host": "localhost"},
+				{"id": 3, "host": "example.com"},
+			},
+			"max_concurrency": 1,
+			"fail_fast":       failFast,
+		})
+		resp, err := NewBatchHTTPExecutor(NewHTTPExecutor()).Execute(context.Background(), &ExecuteRequest{
+			NodeType: "action_http_batch",
+			NodeID:   "enrich",
+			Config:   config,
+			Attempt:  1,
+			Deterministic: &DeterministicContext{
+				Mode:     "replay",
+				Fixtures: []DeterministicFixture{fixture(1), fixture(3)},
+			},
+		})
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		var output BatchHTTPResponse
+		if err := json.Unmarshal(resp.Output, &output); err != nil {
+			t.Fatalf("decode output %s: %v", resp.Output, err)
+		}
+		return resp, output
+	}
+
+	resp, output := run(false)
+	if resp.Error != nil {
+		t.Fatalf("collect-all batch failed: %+v", resp.Error)
+	}
+	if output.Succeeded != 2 || output.Failed != 1 || len(output.Results) != 3 {
+		t.Fatalf("output = %+v, want 2 succeeded and 1 failed", output)
+	}
+	for i, id := range []int{1, 0, 3} {
+		result := output.Results[i]
+		if result.Index != i {
+			t.Fatalf("result %d has index %d", i, result.Index)
+		}
+		if id == 0 {
+			if result.Error == nil || result.Error.Type != ErrorTypeNonRetryable {
+				t.Fatalf("private URL result = %+v, want a non-retryable error", result)
+			}
+			continue
+		}
+		if result.Error != nil || string(result.Body) != fmt.Sprintf(`{"user":%d}`, id) {
+			t.Fatalf("result %d = %+v, want the user %d response", i, result, id)
+		}
+	}
+
+	// With fail_fast the blocked item fails the node and the rest is not sent.
+	resp, output = run(true)
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("fail-fast error = %+v, want non-retryable", resp.Error)
+	}
+	if output.Failed != 2 || output.Results[2].Error == nil {
+		t.Fatalf("fail-fast output = %+v, want the last item skipped", output)
+	}
+}
@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// retryBudgetEntryTTL bounds how long the consumption of an unfinished node is
+// remembered; a retry arriving later starts a fresh time budget.
+const retryBudgetEntryTTL = 24 * time.Hour
+
+// HTTPRetryBudget caps how much an HTTP node may spend across all of its
+// attempts. Once either limit is reached a retryable failure becomes
+// terminal, so a slow host cannot burn the whole retry policy on timeouts.
+type HTTPRetryBudget struct {
+	MaxAttempts     int32 `json:"max_attempts"`      // Attempt cap (0 = no cap)
+	MaxTotalSeconds int   `json:"max_total_seconds"` // Cumulative time across attempts (0 = no limit)
+}
+
+func (b *HTTPRetryBudget) maxTotal() time.Duration {
+	return time.Duration(b.MaxTotalSeconds) * time.Second
+}
+
+// exhausted reports whether attempt, having used elapsed in total, leaves no
+// budget for another attempt.
+func (b *HTTPRetryBudget) exhausted(attempt int32, elapsed time.Duration) bool {
+	if b.MaxAttempts > 0 && attempt >= b.MaxAttempts {
+		return true
+	}
+	return b.MaxTotalSeconds > 0 && elapsed >= b.maxTotal()
+}
+
+// retryBudgetTracker remembers the time each node has spent on earlier
+// attempts in this worker process, keyed by execution and node.
+type retryBudgetTracker struct {
+	mu      sync.Mutex
+	entries map[string]*retryBudgetEntry
+}
+
+type retryBudgetEntry struct {
+	elapsed   time.Duration
+	updatedAt time.Time
+}
+
+func newRetryBudgetTracker() *retryBudgetTracker {
+	return &retryBudgetTracker{entries: make(map[string]*retryBudgetEntry)}
+}
+
+func retryBudgetKey(req *ExecuteRequest) string {
+	return req.Namespace + "/" + req.WorkflowID + "/" + req.RunID + "/" + req.NodeID
+}
+
+// used returns the time spent on earlier attempts. The first attempt always
+// starts from zero.
+func (t *retryBudgetTracker) used(key string, attempt int32) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range t.entries {
+		if now.Sub(entry.updatedAt) > retryBudgetEntryTTL {
+			delete(t.entries, k)
+		}
+	}
+
+	if attempt <= 1 {
+		delete(t.entries, key)
+		return 0
+	}
+	if entry, ok := t.entries[key]; ok {
+		return entry.elapsed
+	}
+	return 0
+}
+
+func (t *retryBudgetTracker) record(key string, elapsed time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries[key] = &retryBudgetEntry{elapsed: elapsed, updatedAt: time.Now()}
+}
+
+func (t *retryBudgetTracker) forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, key)
+}
+
+// executeWithBudget runs one attempt within the node's retry budget: the
+// attempt deadline is capped to the remaining time and a retryable failure
+// past the budget, as classified by classifier, is turned into a terminal one.
+func (e *HTTPExecutor) executeWithBudget(ctx context.Context, req *ExecuteRequest, budget *HTTPRetryBudget, classifier *RetryClassifier) (*ExecuteResponse, error) {
+	key := retryBudgetKey(req)
+	used := e.budgets.used(key, req.Attempt)
+
+	if budget.MaxTotalSeconds > 0 {
+		remaining := budget.maxTotal() - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, remaining)
+		defer cancel()
+	}
+
+	attemptStart := time.Now()
+	resp, err := e.execute(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	elapsed := used + time.Since(attemptStart)
+
+	classifier.applyHTTP(resp)
+	applyRetryBudget(resp, budget, req.Attempt, elapsed)
+
+	if resp.Error != nil && isRetryableErrorType(resp.Error.Type) {
+		e.budgets.record(key, elapsed)
+	} else {
+		e.budgets.forget(key)
+	}
+	return resp, nil
+}
+
+// applyRetryBudget records the budget consumption on the attempts of resp and
+// makes a retryable error terminal once the budget is spent.
+func applyRetryBudget(resp *ExecuteResponse, budget *HTTPRetryBudget, attempt int32, elapsed time.Duration) {
+	exhausted := resp.Error != nil && isRetryableErrorType(resp.Error.Type) && budget.exhausted(attempt, elapsed)
+
+	for i := range resp.ConnectorAttempts {
+		if resp.ConnectorAttempts[i].Meta == nil {
+			resp.ConnectorAttempts[i].Meta = make(map[string]interface{})
+		}
+		resp.ConnectorAttempts[i].Meta["retry_budget"] = map[string]interface{}{
+			"attempts_used":     attempt,
+			"max_attempts":      budget.MaxAttempts,
+			"elapsed_ms":        elapsed.Milliseconds(),
+			"max_total_seconds": budget.MaxTotalSeconds,
+			"exhausted":         exhausted,
+		}
+	}
+
+	if !exhausted {
+		return
+	}
+	resp.Error = &ExecutionError{
+		Message: fmt.Sprintf("%s (retry budget exhausted after %d attempts and %s)",
+			resp.Error.Message, attempt, elapsed.Round(time.Millisecond)),
+		Type: ErrorTypeNonRetryable,
+	}
+	resp.Logs = append(resp.Logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "WARN",
+		Message:   "Retry budget exhausted; failing without further retries",
+	})
+}
+
+func isRetryableErrorType(errorType string) bool {
+	return errorType == ErrorTypeRetryable || errorType == ErrorTypeTimeout
+}
+
+// parseRetryBudget extracts the retry budget from a node config, or nil if
+// none is set.
+func parseRetryBudget(config json.RawMessage) *HTTPRetryBudget {
+	var partial struct {
+		RetryBudget *HTTPRetryBudget `json:"retry_budget"`
+	}
+	if err := json.Unmarshal(config, &partial); err != nil || partial.RetryBudget == nil {
+		return nil
+	}
+	if partial.RetryBudget.MaxAttempts <= 0 && partial.RetryBudget.MaxTotalSeconds <= 0 {
+		return nil
+	}
+	return partial.RetryBudget
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// httpAcceptEncoding is sent when the node does not set Accept-Encoding.
+const httpAcceptEncoding = "gzip, deflate, br"
+
+// encodeRequestBody compresses body for the HTTPConfig encoding option and
+// returns the Content-Encoding to send with it.
+func encodeRequestBody(encoding string, body []byte) ([]byte, string, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+
+	switch strings.ToLower(encoding) {
+	case "", "identity":
+		return body, "", nil
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	default:
+		return nil, "", fmt.Errorf("unsupported request encoding: %s", encoding)
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), strings.ToLower(encoding), nil
+}
+
+// decodeResponseBody wraps resp.Body in a decompressor for its
+// Content-Encoding. It reports whether the body was decoded.
+func decodeResponseBody(resp *http.Response) (io.Reader, bool, error) {
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return resp.Body, false, nil
+	case "gzip", "x-gzip":
+		r, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, false, err
+		}
+		return r, true, nil
+	case "deflate":
+		// "deflate" should be zlib-wrapped, but some servers send raw deflate.
+		br := bufio.NewReader(resp.Body)
+		header, err := br.Peek(2)
+		if err != nil && err != io.EOF {
+			return nil, false, err
+		}
+		if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			r, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, false, err
+			}
+			return r, true, nil
+		}
+		return flate.NewReader(br), true, nil
+	case "br":
+		return brotli.NewReader(resp.Body), true, nil
+	default:
+		// Unknown encodings are passed through undecoded.
+		return resp.Body, false, nil
+	}
+}
@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// httpBodyModeMultipart sends HTTPConfig fields and files as
+// multipart/form-data instead of the JSON body.
+const httpBodyModeMultipart = "multipart"
+
+// maxMultipartUploadBytes caps the decoded size of all files in one request.
+const maxMultipartUploadBytes = 25 * 1024 * 1024 // 25MB
+
+// HTTPMultipartFile is a file part of a multipart/form-data request.
+type HTTPMultipartFile struct {
+	Field         string `json:"field"`
+	Filename      string `json:"filename"`
+	ContentBase64 string `json:"content_base64"`
+	ContentType   string `json:"content_type"` // Default application/octet-stream
+}
+
+// multipartBody is an encoded multipart/form-data request body.
+type multipartBody struct {
+	body        []byte
+	contentType string // Includes the boundary
+	// fingerprint describes the parts without the random boundary, so the
+	// request fingerprint is the same on every attempt.
+	fingerprint multipartFingerprint
+}
+
+type multipartFingerprint struct {
+	Fields map[string]string          `json:"fields"`
+	Files  []multipartFileFingerprint `json:"files"`
+}
+
+type multipartFileFingerprint struct {
+	Field       string `json:"field"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	SHA256      string `json:"sha256"`
+}
+
+var multipartQuoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// buildMultipartBody encodes fields, in name order, followed by files, in the
+// order given. The decoded file contents may total at most
+// maxMultipartUploadBytes.
+func buildMultipartBody(fields map[string]string, files []HTTPMultipartFile) (*multipartBody, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fingerprint := multipartFingerprint{
+		Fields: make(map[string]string, len(fields)),
+		Files:  make([]multipartFileFingerprint, 0, len(files)),
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := w.WriteField(name, fields[name]); err != nil {
+			return nil, err
+		}
+		fingerprint.Fields[name] = fields[name]
+	}
+
+	total := 0
+	for i, file := range files {
+		if file.Field == "" {
+			return nil, fmt.Errorf("files[%d]: field is required", i)
+		}
+		content, err := base64.StdEncoding.DecodeString(file.ContentBase64)
+		if err != nil {
+			return nil, fmt.Errorf("files[%d]: invalid content_base64: %w", i, err)
+		}
+		total += len(content)
+		if total > maxMultipartUploadBytes {
+			return nil, fmt.Errorf("uploads exceed %d bytes limit", maxMultipartUploadBytes)
+		}
+
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			multipartQuoteEscaper.Replace(file.Field), multipartQuoteEscaper.Replace(file.Filename)))
+		header.Set("Content-Type", contentType)
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(content); err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(content)
+		fingerprint.Files = append(fingerprint.Files, multipartFileFingerprint{
+			Field:       file.Field,
+			Filename:    file.Filename,
+			ContentType: contentType,
+			SHA256:      fmt.Sprintf("%x", sum),
+		})
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &multipartBody{
+		body:        buf.Bytes(),
+		contentType: w.FormDataContentType(),
+		fingerprint: fingerprint,
+	}, nil
+}
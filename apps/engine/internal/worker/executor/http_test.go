@@ -1,13 +1,23 @@
 package executor
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
 )
 
 func TestHTTPExecutorReplayFixtureHit(t *testing.T) {
@@ -133,3 +143,173 @@ func TestHTTPExecutorCaptureGeneratesFixture(t *testing.T) {
 		t.Fatalf("expected 1 connector attempt, got %d", len(resp.ConnectorAttempts))
 	}
 }
+
+func TestHTTPRetryBudgetMakesRetryableFailureTerminal(t *testing.T) {
+	t.Parallel()
+
+	budget := parseRetryBudget(json.RawMessage(`{"url":"https://example.com","retry_budget":{"max_attempts":3,"max_total_seconds":60}}`))
+	if budget == nil {
+		t.Fatal("expected retry budget to be parsed")
+	}
+
+	timeoutResponse := func() *ExecuteResponse {
+		return &ExecuteResponse{
+			Error:             &ExecutionError{Message: "HTTP request failed: timeout", Type: ErrorTypeTimeout},
+			ConnectorAttempts: []ConnectorAttempt{{Status: "timeout"}},
+		}
+	}
+
+	within := timeoutResponse()
+	applyRetryBudget(within, budget, 2, 20*time.Second)
+	if within.Error.Type != ErrorTypeTimeout {
+		t.Fatalf("expected retryable error within budget, got %s", within.Error.Type)
+	}
+	meta := within.ConnectorAttempts[0].Meta["retry_budget"].(map[string]interface{})
+	if meta["elapsed_ms"] != int64(20000) || meta["exhausted"] != false {
+		t.Fatalf("unexpected budget meta %v", meta)
+	}
+
+	for name, tc := range map[string]struct {
+		attempt int32
+		elapsed time.Duration
+	}{
+		"attempt cap": {attempt: 3, elapsed: 10 * time.Second},
+		"total time":  {attempt: 2, elapsed: 61 * time.Second},
+	} {
+		resp := timeoutResponse()
+		applyRetryBudget(resp, budget, tc.attempt, tc.elapsed)
+		if resp.Error.Type != ErrorTypeNonRetryable {
+			t.Fatalf("%s: expected terminal error, got %s", name, resp.Error.Type)
+		}
+		if resp.ConnectorAttempts[0].Meta["retry_budget"].(map[string]interface{})["exhausted"] != true {
+			t.Fatalf("%s: expected exhausted budget in meta", name)
+		}
+	}
+}
+
+func TestRetryBudgetTrackerAccumulatesAcrossAttempts(t *testing.T) {
+	t.Parallel()
+
+	tracker := newRetryBudgetTracker()
+	tracker.record("node", 15*time.Second)
+	if used := tracker.used("node", 2); used != 15*time.Second {
+		t.Fatalf("expected 15s used, got %v", used)
+	}
+	if used := tracker.used("node", 1); used != 0 {
+		t.Fatalf("expected first attempt to reset the budget, got %v", used)
+	}
+	if used := tracker.used("node", 2); used != 0 {
+		t.Fatalf("expected reset entry to stay cleared, got %v", used)
+	}
+}
+
+func TestHTTPEncodeRequestBodyGzip(t *testing.T) {
+	body := []byte(`{"hello":"world"}`)
+	encoded, contentEncoding, err := encodeRequestBody("gzip", body)
+	if err != nil {
+		t.Fatalf("encodeRequestBody error: %v", err)
+	}
+	if contentEncoding != "gzip" {
+		t.Fatalf("content encoding = %q, want gzip", contentEncoding)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	if decoded, _ := io.ReadAll(r); !bytes.Equal(decoded, body) {
+		t.Fatalf("round trip = %s, want %s", decoded, body)
+	}
+
+	if _, _, err := encodeRequestBody("zstd", body); err == nil {
+		t.Fatal("expected unsupported encoding error")
+	}
+}
+
+func TestHTTPDecodeResponseBody(t *testing.T) {
+	payload := []byte(`{"compressed":true}`)
+	compress := func(newWriter func(io.Writer) io.WriteCloser) []byte {
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		_, _ = w.Write(payload)
+		_ = w.Close()
+		return buf.Bytes()
+	}
+
+	zlibBody, _, err := encodeRequestBody("deflate", payload)
+	if err != nil {
+		t.Fatalf("encodeRequestBody error: %v", err)
+	}
+	cases := map[string]struct {
+		encoding string
+		body     []byte
+	}{
+		"gzip":        {"gzip", compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })},
+		"zlib":        {"deflate", zlibBody},
+		"raw deflate": {"deflate", compress(func(w io.Writer) io.WriteCloser { fw, _ := flate.NewWriter(w, flate.DefaultCompression); return fw })},
+		"brotli":      {"br", compress(func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) })},
+	}
+	for name, c := range cases {
+		resp := &http.Response{
+			Header: http.Header{"Content-Encoding": []string{c.encoding}},
+			Body:   io.NopCloser(bytes.NewReader(c.body)),
+		}
+		r, decoded, err := decodeResponseBody(resp)
+		if err != nil || !decoded {
+			t.Fatalf("%s: decodeResponseBody = %v, %v", name, decoded, err)
+		}
+		if got, err := io.ReadAll(r); err != nil || !bytes.Equal(got, payload) {
+			t.Fatalf("%s: decoded body = %s, %v", name, got, err)
+		}
+	}
+}
+
+func TestHTTPBuildMultipartBody(t *testing.T) {
+	files := []HTTPMultipartFile{
+		{Field: "attachment", Filename: `report "q1".csv`, ContentBase64: base64.StdEncoding.EncodeToString([]byte("a,b\n1,2\n")), ContentType: "text/csv"},
+		{Field: "attachment", Filename: "blob.bin", ContentBase64: base64.StdEncoding.EncodeToString([]byte{0, 1, 2})},
+	}
+	fields := map[string]string{"title": "Q1", "author": "ops"}
+
+	first, err := buildMultipartBody(fields, files)
+	if err != nil {
+		t.Fatalf("buildMultipartBody error: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(first.contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("content type = %q, %v", first.contentType, err)
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(first.body), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("ReadForm: %v", err)
+	}
+	if form.Value["title"][0] != "Q1" || form.Value["author"][0] != "ops" {
+		t.Fatalf("unexpected fields %v", form.Value)
+	}
+	attachments := form.File["attachment"]
+	if len(attachments) != 2 || attachments[0].Filename != `report "q1".csv` || attachments[0].Header.Get("Content-Type") != "text/csv" {
+		t.Fatalf("unexpected files %+v", attachments)
+	}
+	if attachments[1].Header.Get("Content-Type") != "application/octet-stream" {
+		t.Fatalf("default content type = %q", attachments[1].Header.Get("Content-Type"))
+	}
+
+	// Each build picks a new boundary, but the fingerprint only covers the parts.
+	second, _ := buildMultipartBody(fields, files)
+	if bytes.Equal(first.body, second.body) {
+		t.Fatal("expected a fresh boundary per build")
+	}
+	a, _ := json.Marshal(first.fingerprint)
+	b, _ := json.Marshal(second.fingerprint)
+	if !bytes.Equal(a, b) {
+		t.Fatalf("fingerprints differ: %s vs %s", a, b)
+	}
+
+	tooLarge := []HTTPMultipartFile{{Field: "f", ContentBase64: base64.StdEncoding.EncodeToString(make([]byte, maxMultipartUploadBytes+1))}}
+	if _, err := buildMultipartBody(nil, tooLarge); err == nil {
+		t.Fatal("expected upload size limit error")
+	}
+	if _, err := buildMultipartBody(nil, []HTTPMultipartFile{{Field: "f", ContentBase64: "not base64!"}}); err == nil {
+		t.Fatal("expected invalid base64 error")
+	}
+}
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/linkflow/engine/internal/observability/tracing"
+)
+
+// traceHTTPRequest adds DNS, connect, TLS and time-to-first-byte events to
+// the node span in the request context, timed from when the request is sent.
+// Without a span the request is returned as is.
+func traceHTTPRequest(req *http.Request) *http.Request {
+	span := tracing.SpanFromContext(req.Context())
+	if span == nil {
+		return req
+	}
+
+	sentAt := time.Now()
+	sinceSent := func() int64 { return time.Since(sentAt).Milliseconds() }
+	var mu sync.Mutex
+	var dnsStart, tlsStart time.Time
+	connectStarts := make(map[string]time.Time)
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			mu.Lock()
+			dnsStart = time.Now()
+			mu.Unlock()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			mu.Lock()
+			elapsed := time.Since(dnsStart)
+			mu.Unlock()
+			attrs := map[string]interface{}{"dns_ms": elapsed.Milliseconds(), "at_ms": sinceSent()}
+			if info.Err != nil {
+				attrs["error"] = info.Err.Error()
+			}
+			span.AddEvent("dns_done", attrs)
+		},
+		ConnectStart: func(network, addr string) {
+			mu.Lock()
+			connectStarts[network+" "+addr] = time.Now()
+			mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mu.Lock()
+			elapsed := time.Since(connectStarts[network+" "+addr])
+			mu.Unlock()
+			attrs := map[string]interface{}{"addr": addr, "connect_ms": elapsed.Milliseconds(), "at_ms": sinceSent()}
+			if err != nil {
+				attrs["error"] = err.Error()
+			}
+			span.AddEvent("connect_done", attrs)
+		},
+		TLSHandshakeStart: func() {
+			mu.Lock()
+			tlsStart = time.Now()
+			mu.Unlock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			mu.Lock()
+			elapsed := time.Since(tlsStart)
+			mu.Unlock()
+			attrs := map[string]interface{}{"tls_ms": elapsed.Milliseconds(), "at_ms": sinceSent()}
+			if err != nil {
+				attrs["error"] = err.Error()
+			}
+			span.AddEvent("tls_handshake_done", attrs)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			span.AddEvent("got_conn", map[string]interface{}{"reused": info.Reused, "at_ms": sinceSent()})
+		},
+		GotFirstResponseByte: func() {
+			span.AddEvent("first_response_byte", map[string]interface{}{"ttfb_ms": sinceSent()})
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
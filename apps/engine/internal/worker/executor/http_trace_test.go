@@ -0,0 +1,43 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linkflow/engine/internal/observability/tracing"
+)
+
+func TestTraceHTTPRequestRecordsConnectionEvents(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	tracer := tracing.NewTracer(tracing.TracerConfig{Exporter: tracing.NewInMemoryExporter()})
+	ctx, span := tracer.Start(context.Background(), "action_http_request")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	resp, err := ts.Client().Do(traceHTTPRequest(req))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	span.End()
+
+	seen := make(map[string]bool)
+	for _, event := range span.Events {
+		seen[event.Name] = true
+	}
+	for _, name := range []string{"connect_done", "got_conn", "first_response_byte"} {
+		if !seen[name] {
+			t.Errorf("no %s event in %v", name, seen)
+		}
+	}
+
+	// Without a span the request is left untouched.
+	plain, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	if traceHTTPRequest(plain) != plain {
+		t.Fatal("request without a span was wrapped")
+	}
+}
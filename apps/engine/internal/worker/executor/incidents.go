@@ -0,0 +1,475 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	pagerDutyEventsURL  = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieBaseURL     = "https://api.opsgenie.com"
+	opsgenieEUBaseURL   = "https://api.eu.opsgenie.com"
+	incidentProviderMax = 1 * 1024 * 1024
+)
+
+// IncidentConfig holds the fields shared by the PagerDuty and Opsgenie nodes.
+type IncidentConfig struct {
+	EventAction   string                 `json:"event_action"` // trigger, acknowledge or resolve
+	DedupKey      string                 `json:"dedup_key"`    // Incident key; derived from the node when a trigger omits it
+	Summary       string                 `json:"summary"`
+	Severity      string                 `json:"severity"` // critical, error, warning or info (default error)
+	Source        string                 `json:"source"`
+	Component     string                 `json:"component"`
+	Group         string                 `json:"group"`
+	CustomDetails map[string]interface{} `json:"custom_details"`
+}
+
+func (c *IncidentConfig) validate() string {
+	switch c.EventAction {
+	case "trigger":
+		if c.Summary == "" {
+			return "summary is required for trigger"
+		}
+		if c.Source == "" {
+			return "source is required for trigger"
+		}
+		switch c.Severity {
+		case "critical", "error", "warning", "info":
+		default:
+			return fmt.Sprintf("unsupported severity: %s", c.Severity)
+		}
+	case "acknowledge", "resolve":
+		if c.DedupKey == "" {
+			return fmt.Sprintf("dedup_key is required for %s", c.EventAction)
+		}
+	default:
+		return fmt.Sprintf("unsupported event_action: %s", c.EventAction)
+	}
+	return ""
+}
+
+// applyDefaults fills the severity and, for triggers, a dedup key derived
+// from the node so retries of the same node update one incident.
+func (c *IncidentConfig) applyDefaults(req *ExecuteRequest) {
+	if c.EventAction == "" {
+		c.EventAction = "trigger"
+	}
+	if c.Severity == "" {
+		c.Severity = "error"
+	}
+	if c.EventAction == "trigger" && c.DedupKey == "" {
+		sum := sha256.Sum256([]byte(req.WorkflowID + "/" + req.RunID + "/" + req.NodeID))
+		c.DedupKey = fmt.Sprintf("linkflow-%x", sum[:16])
+	}
+}
+
+// PagerDutyExecutor opens, acknowledges and resolves incidents through the
+// PagerDuty Events API v2.
+type PagerDutyExecutor struct {
+	BaseExecutor
+
+	client     *http.Client
+	eventsURL  string
+	routingKey string
+	limiter    *ConnectorRateLimiter
+}
+
+// PagerDutyConfig represents the configuration for a PagerDuty node.
+type PagerDutyConfig struct {
+	IncidentConfig
+	RoutingKey string `json:"routing_key"` // Integration key of the service
+}
+
+// NewPagerDutyExecutor creates a new PagerDuty executor with connection pooling.
+func NewPagerDutyExecutor() *PagerDutyExecutor {
+	return &PagerDutyExecutor{
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: newSSRFSafeTransport(),
+		},
+		eventsURL:  pagerDutyEventsURL,
+		routingKey: os.Getenv("PAGERDUTY_ROUTING_KEY"),
+		limiter:    DefaultConnectorRateLimiter(),
+	}
+}
+
+// WithRoutingKey sets the default routing key.
+func (e *PagerDutyExecutor) WithRoutingKey(routingKey string) *PagerDutyExecutor {
+	e.routingKey = routingKey
+	return e
+}
+
+// WithRateLimiter sets the limiter used to throttle PagerDuty calls.
+func (e *PagerDutyExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *PagerDutyExecutor {
+	e.limiter = limiter
+	return e
+}
+
+func (e *PagerDutyExecutor) NodeType() string {
+	return "pagerduty"
+}
+
+func (e *PagerDutyExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Starting PagerDuty execution for node %s", req.NodeID),
+	})
+
+	var config PagerDutyConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return incidentConfigError(fmt.Sprintf("failed to parse PagerDuty config: %v", err), logs, start), nil
+	}
+
+	if config.RoutingKey == "" {
+		config.RoutingKey = e.routingKey
+	}
+	if config.RoutingKey == "" {
+		return incidentConfigError("routing_key is required", logs, start), nil
+	}
+	config.applyDefaults(req)
+	if msg := config.validate(); msg != "" {
+		return incidentConfigError(msg, logs, start), nil
+	}
+
+	payload := map[string]interface{}{
+		"routing_key":  config.RoutingKey,
+		"event_action": config.EventAction,
+		"dedup_key":    config.DedupKey,
+	}
+	if config.EventAction == "trigger" {
+		event := map[string]interface{}{
+			"summary":  config.Summary,
+			"source":   config.Source,
+			"severity": config.Severity,
+		}
+		if config.Component != "" {
+			event["component"] = config.Component
+		}
+		if config.Group != "" {
+			event["group"] = config.Group
+		}
+		if len(config.CustomDetails) > 0 {
+			event["custom_details"] = config.CustomDetails
+		}
+		payload["payload"] = event
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return incidentConfigError(fmt.Sprintf("failed to marshal payload: %v", err), logs, start), nil
+	}
+
+	waited, err := e.limiter.Acquire(ctx, "pagerduty")
+	if err != nil {
+		return rateLimitedResponse(req, "pagerduty", config.EventAction, "pagerduty", waited, err, logs, start), nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Sending %s event for %s to PagerDuty", config.EventAction, config.DedupKey),
+	})
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.eventsURL, bytes.NewReader(body))
+	if err != nil {
+		return incidentConfigError(fmt.Sprintf("failed to create request: %v", err), logs, start), nil
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return incidentNetworkError(req, "pagerduty", config.EventAction, err, waited, logs, start), nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, incidentProviderMax))
+
+	attempt := newConnectorAttempt(req, "pagerduty", config.EventAction, "pagerduty", "success", start, waited)
+	attempt.StatusCode = int32(resp.StatusCode)
+
+	if resp.StatusCode >= 400 {
+		return incidentProviderError(resp.StatusCode, "PagerDuty", strings.TrimSpace(string(respBody)), attempt, logs, start), nil
+	}
+
+	var result struct {
+		Message  string `json:"message"`
+		DedupKey string `json:"dedup_key"`
+	}
+	_ = json.Unmarshal(respBody, &result)
+	if result.DedupKey == "" {
+		result.DedupKey = config.DedupKey
+	}
+
+	return incidentSentResponse("pagerduty", config.EventAction, result.DedupKey, result.Message, resp.StatusCode, attempt, logs, start), nil
+}
+
+// OpsgenieExecutor is the Opsgenie counterpart of PagerDutyExecutor. It takes
+// the same incident config and maps it onto the Alert API: the dedup key is
+// the alert alias, trigger creates the alert and resolve closes it.
+type OpsgenieExecutor struct {
+	BaseExecutor
+
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	limiter *ConnectorRateLimiter
+}
+
+// OpsgenieConfig represents the configuration for an Opsgenie node.
+type OpsgenieConfig struct {
+	IncidentConfig
+	APIKey string `json:"api_key"` // API integration key
+	Region string `json:"region"`  // "us" (default) or "eu"
+}
+
+// opsgeniePriorities maps incident severities onto Opsgenie alert priorities.
+var opsgeniePriorities = map[string]string{
+	"critical": "P1",
+	"error":    "P2",
+	"warning":  "P3",
+	"info":     "P5",
+}
+
+// NewOpsgenieExecutor creates a new Opsgenie executor with connection pooling.
+func NewOpsgenieExecutor() *OpsgenieExecutor {
+	return &OpsgenieExecutor{
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: newSSRFSafeTransport(),
+		},
+		apiKey:  os.Getenv("OPSGENIE_API_KEY"),
+		limiter: DefaultConnectorRateLimiter(),
+	}
+}
+
+// WithAPIKey sets the default API key.
+func (e *OpsgenieExecutor) WithAPIKey(apiKey string) *OpsgenieExecutor {
+	e.apiKey = apiKey
+	return e
+}
+
+// WithRateLimiter sets the limiter used to throttle Opsgenie calls.
+func (e *OpsgenieExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *OpsgenieExecutor {
+	e.limiter = limiter
+	return e
+}
+
+func (e *OpsgenieExecutor) NodeType() string {
+	return "opsgenie"
+}
+
+func (e *OpsgenieExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Starting Opsgenie execution for node %s", req.NodeID),
+	})
+
+	var config OpsgenieConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return incidentConfigError(fmt.Sprintf("failed to parse Opsgenie config: %v", err), logs, start), nil
+	}
+
+	if config.APIKey == "" {
+		config.APIKey = e.apiKey
+	}
+	if config.APIKey == "" {
+		return incidentConfigError("api_key is required", logs, start), nil
+	}
+	config.applyDefaults(req)
+	if msg := config.validate(); msg != "" {
+		return incidentConfigError(msg, logs, start), nil
+	}
+
+	baseURL := e.baseURL
+	if baseURL == "" {
+		switch strings.ToLower(config.Region) {
+		case "", "us":
+			baseURL = opsgenieBaseURL
+		case "eu":
+			baseURL = opsgenieEUBaseURL
+		default:
+			return incidentConfigError(fmt.Sprintf("unsupported region: %s", config.Region), logs, start), nil
+		}
+	}
+
+	alias := url.PathEscape(config.DedupKey)
+	var endpoint string
+	var payload map[string]interface{}
+	switch config.EventAction {
+	case "trigger":
+		endpoint = baseURL + "/v2/alerts"
+		payload = map[string]interface{}{
+			"message":  config.Summary,
+			"alias":    config.DedupKey,
+			"source":   config.Source,
+			"priority": opsgeniePriorities[config.Severity],
+		}
+		if config.Component != "" || config.Group != "" {
+			tags := make([]string, 0, 2)
+			for _, tag := range []string{config.Component, config.Group} {
+				if tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+			payload["tags"] = tags
+		}
+		if len(config.CustomDetails) > 0 {
+			details := make(map[string]string, len(config.CustomDetails))
+			for key, value := range config.CustomDetails {
+				details[key] = fmt.Sprint(value)
+			}
+			payload["details"] = details
+		}
+	case "acknowledge":
+		endpoint = baseURL + "/v2/alerts/" + alias + "/acknowledge?identifierType=alias"
+		payload = map[string]interface{}{"source": config.Source}
+	case "resolve":
+		endpoint = baseURL + "/v2/alerts/" + alias + "/close?identifierType=alias"
+		payload = map[string]interface{}{"source": config.Source}
+	}
+	if config.EventAction != "trigger" && config.Summary != "" {
+		payload["note"] = config.Summary
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return incidentConfigError(fmt.Sprintf("failed to marshal payload: %v", err), logs, start), nil
+	}
+
+	waited, err := e.limiter.Acquire(ctx, "opsgenie")
+	if err != nil {
+		return rateLimitedResponse(req, "opsgenie", config.EventAction, "opsgenie", waited, err, logs, start), nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Sending %s for alert %s to Opsgenie", config.EventAction, config.DedupKey),
+	})
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return incidentConfigError(fmt.Sprintf("failed to create request: %v", err), logs, start), nil
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "GenieKey "+config.APIKey)
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return incidentNetworkError(req, "opsgenie", config.EventAction, err, waited, logs, start), nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, incidentProviderMax))
+
+	attempt := newConnectorAttempt(req, "opsgenie", config.EventAction, "opsgenie", "success", start, waited)
+	attempt.StatusCode = int32(resp.StatusCode)
+
+	if resp.StatusCode >= 400 {
+		return incidentProviderError(resp.StatusCode, "Opsgenie", strings.TrimSpace(string(respBody)), attempt, logs, start), nil
+	}
+
+	var result struct {
+		Result string `json:"result"`
+	}
+	_ = json.Unmarshal(respBody, &result)
+
+	return incidentSentResponse("opsgenie", config.EventAction, config.DedupKey, result.Result, resp.StatusCode, attempt, logs, start), nil
+}
+
+func incidentConfigError(message string, logs []LogEntry, start time.Time) *ExecuteResponse {
+	return &ExecuteResponse{
+		Error: &ExecutionError{
+			Message: message,
+			Type:    ErrorTypeNonRetryable,
+		},
+		Logs:     logs,
+		Duration: time.Since(start),
+	}
+}
+
+func incidentNetworkError(req *ExecuteRequest, provider, operation string, err error, waited time.Duration, logs []LogEntry, start time.Time) *ExecuteResponse {
+	attempt := newConnectorAttempt(req, provider, operation, provider, "network_error", start, waited)
+	attempt.ErrorCode = strings.ToUpper(provider) + "_REQUEST_FAILED"
+	attempt.ErrorMessage = err.Error()
+	return &ExecuteResponse{
+		Error: &ExecutionError{
+			Message: fmt.Sprintf("request failed: %v", err),
+			Type:    ErrorTypeRetryable,
+		},
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}
+}
+
+// incidentProviderError maps a provider error status: throttling and server
+// errors are retryable; bad requests, auth failures and other client errors
+// are not.
+func incidentProviderError(statusCode int, providerName, body string, attempt ConnectorAttempt, logs []LogEntry, start time.Time) *ExecuteResponse {
+	errorType := ErrorTypeNonRetryable
+	attempt.Status = "client_error"
+	switch {
+	case statusCode == 429:
+		errorType = ErrorTypeRetryable
+		attempt.Status = "throttled"
+		attempt.ErrorCode = "RATE_LIMITED"
+	case statusCode >= 500:
+		errorType = ErrorTypeRetryable
+		attempt.Status = "server_error"
+	case statusCode == 401 || statusCode == 403:
+		attempt.ErrorCode = "UNAUTHORIZED"
+	}
+	attempt.ErrorMessage = body
+
+	return &ExecuteResponse{
+		Error: &ExecutionError{
+			Message: fmt.Sprintf("%s error (status %d): %s", providerName, statusCode, body),
+			Type:    errorType,
+		},
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}
+}
+
+func incidentSentResponse(provider, action, dedupKey, message string, statusCode int, attempt ConnectorAttempt, logs []LogEntry, start time.Time) *ExecuteResponse {
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("%s event accepted by %s (dedup_key=%s)", action, provider, dedupKey),
+	})
+
+	output, _ := json.Marshal(map[string]interface{}{
+		"success":      true,
+		"provider":     provider,
+		"event_action": action,
+		"dedup_key":    dedupKey,
+		"message":      message,
+		"status_code":  statusCode,
+	})
+
+	return &ExecuteResponse{
+		Output:            output,
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}
+}
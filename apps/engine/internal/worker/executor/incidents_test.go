@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPagerDutyExecutorTriggersAndMapsErrors(t *testing.T) {
+	t.Parallel()
+
+	status := http.StatusAccepted
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"success","message":"Event processed","dedup_key":"` + received["dedup_key"].(string) + `"}`))
+	}))
+	defer server.Close()
+
+	executor := NewPagerDutyExecutor().WithRoutingKey("env-key")
+	executor.client = server.Client()
+	executor.eventsURL = server.URL
+
+	run := func(config string) *ExecuteResponse {
+		resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+			NodeType:   "pagerduty",
+			NodeID:     "page",
+			WorkflowID: "wf-1",
+			RunID:      "run-1",
+			Config:     json.RawMessage(config),
+			Attempt:    1,
+		})
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		return resp
+	}
+
+	resp := run(`{"summary":"Checkout is down","source":"checkout","severity":"critical"}`)
+	if resp.Error != nil {
+		t.Fatalf("trigger failed: %+v", resp.Error)
+	}
+	var output struct {
+		DedupKey string `json:"dedup_key"`
+	}
+	_ = json.Unmarshal(resp.Output, &output)
+	payload, _ := received["payload"].(map[string]interface{})
+	if received["routing_key"] != "env-key" || received["event_action"] != "trigger" || payload["severity"] != "critical" {
+		t.Fatalf("sent event = %v", received)
+	}
+	if output.DedupKey == "" || output.DedupKey != received["dedup_key"] {
+		t.Fatalf("dedup_key = %q, want the key sent with the event", output.DedupKey)
+	}
+	if again := run(`{"summary":"Checkout is down","source":"checkout"}`); string(again.Output) == "" || received["dedup_key"] != output.DedupKey {
+		t.Fatalf("retried trigger used dedup_key %v, want %q", received["dedup_key"], output.DedupKey)
+	}
+
+	if resp := run(`{"event_action":"resolve"}`); resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("resolve without dedup_key = %+v, want a non-retryable error", resp.Error)
+	}
+
+	for code, want := range map[int]string{
+		http.StatusTooManyRequests:    ErrorTypeRetryable,
+		http.StatusServiceUnavailable: ErrorTypeRetryable,
+		http.StatusBadRequest:         ErrorTypeNonRetryable,
+		http.StatusUnauthorized:       ErrorTypeNonRetryable,
+	} {
+		status = code
+		resp := run(`{"event_action":"resolve","dedup_key":"incident-1"}`)
+		if resp.Error == nil || resp.Error.Type != want {
+			t.Fatalf("status %d error = %+v, want %s", code, resp.Error, want)
+		}
+	}
+}
@@ -17,8 +17,11 @@ import (
 
 // DiscordExecutor handles Discord webhook messages.
 type DiscordExecutor struct {
+	BaseExecutor
+
 	client       *http.Client
 	defaultToken string
+	limiter      *ConnectorRateLimiter
 }
 
 // DiscordConfig represents the configuration for a Discord node.
@@ -86,9 +89,16 @@ func NewDiscordExecutor() *DiscordExecutor {
 			Transport: transport,
 		},
 		defaultToken: defaultToken,
+		limiter:      DefaultConnectorRateLimiter(),
 	}
 }
 
+// WithRateLimiter sets the limiter used to throttle Discord calls.
+func (e *DiscordExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *DiscordExecutor {
+	e.limiter = limiter
+	return e
+}
+
 func (e *DiscordExecutor) NodeType() string {
 	return "discord"
 }
@@ -166,6 +176,11 @@ func (e *DiscordExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 		}, nil
 	}
 
+	waited, err := e.limiter.Acquire(ctx, "discord")
+	if err != nil {
+		return rateLimitedResponse(req, "discord", "webhook", "discord", waited, err, logs, start), nil
+	}
+
 	logs = append(logs, LogEntry{
 		Timestamp: time.Now(),
 		Level:     "INFO",
@@ -187,13 +202,17 @@ func (e *DiscordExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 
 	resp, err := e.client.Do(httpReq)
 	if err != nil {
+		attempt := newConnectorAttempt(req, "discord", "webhook", "discord", "network_error", start, waited)
+		attempt.ErrorCode = "DISCORD_REQUEST_FAILED"
+		attempt.ErrorMessage = err.Error()
 		return &ExecuteResponse{
 			Error: &ExecutionError{
 				Message: fmt.Sprintf("request failed: %v", err),
 				Type:    ErrorTypeRetryable,
 			},
-			Logs:     logs,
-			Duration: time.Since(start),
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
 		}, nil
 	}
 	defer resp.Body.Close()
@@ -207,25 +226,34 @@ func (e *DiscordExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 		})
 	}
 
+	attempt := newConnectorAttempt(req, "discord", "webhook", "discord", "success", start, waited)
+	attempt.StatusCode = int32(resp.StatusCode)
+
 	if resp.StatusCode == 429 {
+		attempt.Status = "throttled"
+		attempt.ErrorCode = "RATE_LIMITED"
 		return &ExecuteResponse{
 			Error: &ExecutionError{
 				Message: "rate limited by Discord",
 				Type:    ErrorTypeRetryable,
 			},
-			Logs:     logs,
-			Duration: time.Since(start),
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
 		}, nil
 	}
 
 	if resp.StatusCode >= 400 {
+		attempt.Status = "client_error"
+		attempt.ErrorMessage = string(respBody)
 		return &ExecuteResponse{
 			Error: &ExecutionError{
 				Message: fmt.Sprintf("Discord error: %s", string(respBody)),
 				Type:    ErrorTypeNonRetryable,
 			},
-			Logs:     logs,
-			Duration: time.Since(start),
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
 		}, nil
 	}
 
@@ -241,18 +269,22 @@ func (e *DiscordExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 	})
 
 	return &ExecuteResponse{
-		Output:   output,
-		Logs:     logs,
-		Duration: time.Since(start),
+		Output:            output,
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
 	}, nil
 }
 
 // TwilioExecutor handles Twilio SMS messages.
 type TwilioExecutor struct {
+	BaseExecutor
+
 	client      *http.Client
 	accountSid  string
 	authToken   string
 	defaultFrom string
+	limiter     *ConnectorRateLimiter
 }
 
 // TwilioConfig represents the configuration for a Twilio node.
@@ -283,6 +315,7 @@ func NewTwilioExecutor() *TwilioExecutor {
 		accountSid:  accountSid,
 		authToken:   authToken,
 		defaultFrom: defaultFrom,
+		limiter:     DefaultConnectorRateLimiter(),
 	}
 }
 
@@ -293,10 +326,21 @@ func (e *TwilioExecutor) WithCredentials(sid, token string) *TwilioExecutor {
 	return e
 }
 
+// WithRateLimiter sets the limiter used to throttle Twilio calls.
+func (e *TwilioExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *TwilioExecutor {
+	e.limiter = limiter
+	return e
+}
+
 func (e *TwilioExecutor) NodeType() string {
 	return "twilio"
 }
 
+// UsesSecrets reports that account_sid and auth_token may reference secrets.
+func (e *TwilioExecutor) UsesSecrets() bool {
+	return true
+}
+
 func (e *TwilioExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
 	start := time.Now()
 	logs := make([]LogEntry, 0)
@@ -358,6 +402,11 @@ func (e *TwilioExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exe
 
 	url := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", config.AccountSID)
 
+	waited, err := e.limiter.Acquire(ctx, "twilio")
+	if err != nil {
+		return rateLimitedResponse(req, "twilio", "send_sms", "twilio", waited, err, logs, start), nil
+	}
+
 	logs = append(logs, LogEntry{
 		Timestamp: time.Now(),
 		Level:     "INFO",
@@ -381,13 +430,17 @@ func (e *TwilioExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exe
 
 	resp, err := e.client.Do(httpReq)
 	if err != nil {
+		attempt := newConnectorAttempt(req, "twilio", "send_sms", "twilio", "network_error", start, waited)
+		attempt.ErrorCode = "TWILIO_REQUEST_FAILED"
+		attempt.ErrorMessage = err.Error()
 		return &ExecuteResponse{
 			Error: &ExecutionError{
 				Message: fmt.Sprintf("request failed: %v", err),
 				Type:    ErrorTypeRetryable,
 			},
-			Logs:     logs,
-			Duration: time.Since(start),
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
 		}, nil
 	}
 	defer resp.Body.Close()
@@ -401,18 +454,30 @@ func (e *TwilioExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exe
 		})
 	}
 
+	attempt := newConnectorAttempt(req, "twilio", "send_sms", "twilio", "success", start, waited)
+	attempt.StatusCode = int32(resp.StatusCode)
+
 	if resp.StatusCode >= 400 {
 		errorType := ErrorTypeRetryable
 		if resp.StatusCode == 400 || resp.StatusCode == 401 {
 			errorType = ErrorTypeNonRetryable
 		}
+		attempt.Status = "client_error"
+		if resp.StatusCode == 429 {
+			attempt.Status = "throttled"
+			attempt.ErrorCode = "RATE_LIMITED"
+		} else if resp.StatusCode >= 500 {
+			attempt.Status = "server_error"
+		}
+		attempt.ErrorMessage = string(respBody)
 		return &ExecuteResponse{
 			Error: &ExecutionError{
 				Message: fmt.Sprintf("Twilio error: %s", string(respBody)),
 				Type:    errorType,
 			},
-			Logs:     logs,
-			Duration: time.Since(start),
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
 		}, nil
 	}
 
@@ -423,21 +488,24 @@ func (e *TwilioExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exe
 	})
 
 	return &ExecuteResponse{
-		Output:   respBody,
-		Logs:     logs,
-		Duration: time.Since(start),
+		Output:            respBody,
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
 	}, nil
 }
 
-// StorageExecutor handles file storage operations (local filesystem, S3-compatible via HTTP).
+// StorageExecutor handles file storage operations (local filesystem, S3-compatible via HTTP, Google Cloud Storage).
 type StorageExecutor struct {
+	BaseExecutor
+
 	client    *http.Client
 	localRoot string
 }
 
 // StorageConfig represents the configuration for a storage node.
 type StorageConfig struct {
-	Provider  string `json:"provider"`  // "local" or "s3"
+	Provider  string `json:"provider"`  // "local", "s3" or "gcs"
 	Operation string `json:"operation"` // upload, download, delete, list, exists
 
 	// Path/Key configuration
@@ -457,6 +525,10 @@ type StorageConfig struct {
 	SecretKey string `json:"secret_key"`
 	Endpoint  string `json:"endpoint"` // For S3-compatible services
 
+	// GCS Configuration (uses Bucket); Application Default Credentials are
+	// used when no key is given
+	CredentialsJSON string `json:"credentials_json"` // Service account JSON key
+
 	// List Configuration
 	Prefix    string `json:"prefix"`
 	MaxKeys   int    `json:"max_keys"`
@@ -513,6 +585,12 @@ func (e *StorageExecutor) NodeType() string {
 	return "storage"
 }
 
+// UsesSecrets reports that provider keys, such as secret_key and
+// credentials_json, may reference secrets.
+func (e *StorageExecutor) UsesSecrets() bool {
+	return true
+}
+
 func (e *StorageExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
 	start := time.Now()
 	logs := make([]LogEntry, 0)
@@ -546,6 +624,8 @@ func (e *StorageExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 	switch config.Provider {
 	case "local":
 		response, err = e.executeLocal(ctx, config, &logs)
+	case "gcs":
+		response, err = e.executeGCS(ctx, config, &logs)
 	case "s3":
 		// S3 operations would require AWS SDK - for now return informative error
 		return &ExecuteResponse{
@@ -559,7 +639,7 @@ func (e *StorageExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 	default:
 		return &ExecuteResponse{
 			Error: &ExecutionError{
-				Message: fmt.Sprintf("unsupported storage provider: %s (supported: local, s3)", config.Provider),
+				Message: fmt.Sprintf("unsupported storage provider: %s (supported: local, s3, gcs)", config.Provider),
 				Type:    ErrorTypeNonRetryable,
 			},
 			Logs:     logs,
@@ -573,6 +653,9 @@ func (e *StorageExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 		if contains(errStr, "not found") || contains(errStr, "no such") || contains(errStr, "permission denied") {
 			errorType = ErrorTypeNonRetryable
 		}
+		if config.Provider == "gcs" {
+			errorType = gcsErrorType(err)
+		}
 
 		return &ExecuteResponse{
 			Error: &ExecutionError{
@@ -584,7 +667,10 @@ func (e *StorageExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 		}, nil
 	}
 
-	response.Success = true
+	// For exists, Success reports whether the file was found.
+	if config.Operation != "exists" {
+		response.Success = true
+	}
 	response.Operation = config.Operation
 	response.Duration = time.Since(start).String()
 
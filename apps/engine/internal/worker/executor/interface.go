@@ -9,8 +9,44 @@ import (
 type Executor interface {
 	Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error)
 	NodeType() string
+	// OutputSchema returns the JSON Schema of the node output, or nil if the
+	// executor does not declare one.
+	OutputSchema() json.RawMessage
 }
 
+// InputSchemaProvider is implemented by executors that declare the JSON Schema
+// of their node configuration.
+type InputSchemaProvider interface {
+	InputSchema() json.RawMessage
+}
+
+// AliasProvider is implemented by executors that also answer to other node
+// types, so a single instance serves all of them.
+type AliasProvider interface {
+	Aliases() []string
+}
+
+// ScopeConsumer is implemented by executors that evaluate expressions over
+// the whole execution rather than just their input. The worker fills
+// ExecuteRequest.Scope for them when UsesScope returns true.
+type ScopeConsumer interface {
+	UsesScope() bool
+}
+
+// JobPayloadConsumer is implemented by executors that need the payload the
+// execution was started with, e.g. to pass its credentials on. The worker
+// fills ExecuteRequest.Job for them when UsesJobPayload returns true.
+type JobPayloadConsumer interface {
+	UsesJobPayload() bool
+}
+
+// BaseExecutor provides empty schema declarations. Embed it in executors that
+// do not describe their input or output.
+type BaseExecutor struct{}
+
+func (BaseExecutor) InputSchema() json.RawMessage  { return nil }
+func (BaseExecutor) OutputSchema() json.RawMessage { return nil }
+
 type ExecuteRequest struct {
 	NodeType      string
 	NodeID        string
@@ -22,6 +58,17 @@ type ExecuteRequest struct {
 	Deterministic *DeterministicContext
 	Attempt       int32
 	Timeout       time.Duration
+	// Scope holds the execution's expression scope, as plain JSON values:
+	// "input" (workflow input), "nodes" (outputs by node ID), "vars"
+	// (workflow variables) and "context" (execution metadata such as
+	// run_id and attempt). Only set for a ScopeConsumer.
+	Scope map[string]interface{}
+	// Job is the payload the execution was started with, including its
+	// credentials. Only set for a JobPayloadConsumer.
+	Job *JobPayload
+	// Progress, if set, reports an estimate of how far the node has got
+	// (0-100) along with its partial output so far. It must not block.
+	Progress func(progress int, partial string)
 }
 
 type ExecuteResponse struct {
@@ -32,6 +79,24 @@ type ExecuteResponse struct {
 	Logs                  []LogEntry
 	Metadata              map[string]string // Optional executor metadata (e.g., timer_requested)
 	Duration              time.Duration
+	Pending               *PendingActivity // Set when the activity completes out-of-band
+}
+
+// PendingActivity marks an activity that stays open after Execute returns and
+// is completed later through the async-activity callback endpoints.
+type PendingActivity struct {
+	// ScheduleToCloseTimeout fails the activity if no completion arrives in
+	// time. Zero uses the worker default.
+	ScheduleToCloseTimeout time.Duration
+	// OnPending, if set, is called with the completion token once history has
+	// recorded the activity as pending.
+	OnPending func(ctx context.Context, taskToken string) error
+	// WaitSignal, if set, completes the activity when a signal of that name
+	// is sent to the execution.
+	WaitSignal string
+	// TimeoutResult, if set, completes the activity with this output when
+	// ScheduleToCloseTimeout elapses, instead of failing it.
+	TimeoutResult json.RawMessage
 }
 
 type DeterministicContext struct {
@@ -39,6 +104,9 @@ type DeterministicContext struct {
 	Seed              string                 `json:"seed"`
 	SourceExecutionID int                    `json:"source_execution_id"`
 	Fixtures          []DeterministicFixture `json:"fixtures"`
+
+	// generated backs UUID and Now once BindNode is called.
+	generated *generatedValueSource
 }
 
 type DeterministicFixture struct {
@@ -6,7 +6,9 @@ import (
 	"time"
 )
 
-type ManualExecutor struct{}
+type ManualExecutor struct {
+	BaseExecutor
+}
 
 func NewManualExecutor() *ManualExecutor {
 	return &ManualExecutor{}
@@ -16,6 +18,10 @@ func (e *ManualExecutor) NodeType() string {
 	return "trigger_manual"
 }
 
+func (e *ManualExecutor) Aliases() []string {
+	return []string{"trigger_schedule"}
+}
+
 func (e *ManualExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
 	// Pass input to output
 	// If input is empty, ensure valid JSON
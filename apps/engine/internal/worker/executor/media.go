@@ -0,0 +1,509 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	mediaOperationResize    = "resize"
+	mediaOperationConvert   = "convert"
+	mediaOperationThumbnail = "thumbnail"
+	mediaOperationTranscode = "transcode"
+
+	// defaultMediaTimeout bounds a media_process node without a timeout.
+	defaultMediaTimeout = 2 * time.Minute
+	// defaultMediaMemoryLimit caps the address space of the convert or
+	// ffmpeg process.
+	defaultMediaMemoryLimit = 512 * 1024 * 1024 // 512MB
+	// maxMediaInputBytes caps the decoded input file.
+	maxMediaInputBytes = 100 * 1024 * 1024 // 100MB
+	// maxMediaDimension bounds resize and thumbnail dimensions.
+	maxMediaDimension = 10000
+)
+
+// imageBinaryCandidates and videoBinaryCandidates are the backends
+// FindMediaBinaries looks for on PATH, in order of preference.
+var (
+	imageBinaryCandidates = []string{"magick", "convert"}
+	videoBinaryCandidates = []string{"ffmpeg"}
+)
+
+// mediaContentTypes are the output formats media_process nodes can produce.
+var mediaContentTypes = map[string]string{
+	"png":  "image/png",
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"gif":  "image/gif",
+	"webp": "image/webp",
+	"bmp":  "image/bmp",
+	"tiff": "image/tiff",
+	"mp4":  "video/mp4",
+	"webm": "video/webm",
+	"mov":  "video/quicktime",
+	"mkv":  "video/x-matroska",
+	"mp3":  "audio/mpeg",
+	"wav":  "audio/wav",
+	"ogg":  "audio/ogg",
+	"flac": "audio/flac",
+	"aac":  "audio/aac",
+}
+
+// mediaImageFormats are the formats ImageMagick writes for resize, convert
+// and thumbnail operations.
+var mediaImageFormats = map[string]bool{
+	"png": true, "jpg": true, "jpeg": true, "gif": true, "webp": true, "bmp": true, "tiff": true,
+}
+
+var (
+	mediaCodecPattern   = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+	mediaBitratePattern = regexp.MustCompile(`^[0-9]{1,6}[kM]?$`)
+	mediaNumberPattern  = regexp.MustCompile(`^[0-9]{1,6}(\.[0-9]{1,3})?$`)
+	mediaScalePattern   = regexp.MustCompile(`^(-1|-2|[0-9]{1,5}):(-1|-2|[0-9]{1,5})$`)
+	mediaPresetPattern  = regexp.MustCompile(`^(ultrafast|superfast|veryfast|faster|fast|medium|slow|slower|veryslow)$`)
+	mediaPixFmtPattern  = regexp.MustCompile(`^(yuv420p|yuv422p|yuv444p|rgb24|gray)$`)
+)
+
+// mediaTranscodeArgs are the ffmpeg options a transcode may pass, with the
+// pattern their value must match. A nil pattern marks an option without a
+// value. Anything else is rejected so node configs cannot inject flags such
+// as extra inputs or outputs.
+var mediaTranscodeArgs = map[string]*regexp.Regexp{
+	"-c:v":     mediaCodecPattern,
+	"-c:a":     mediaCodecPattern,
+	"-b:v":     mediaBitratePattern,
+	"-b:a":     mediaBitratePattern,
+	"-crf":     mediaNumberPattern,
+	"-r":       mediaNumberPattern,
+	"-ar":      mediaNumberPattern,
+	"-ac":      mediaNumberPattern,
+	"-ss":      mediaNumberPattern,
+	"-t":       mediaNumberPattern,
+	"-preset":  mediaPresetPattern,
+	"-pix_fmt": mediaPixFmtPattern,
+	"-vf":      regexp.MustCompile(`^scale=` + mediaScalePattern.String()[1:]),
+	"-an":      nil,
+	"-vn":      nil,
+}
+
+// errMissingMediaBinary is returned when the backend an operation needs is
+// not installed.
+var errMissingMediaBinary = errors.New("media binary not configured")
+
+// MediaExecutor resizes, converts and thumbnails images with ImageMagick and
+// transcodes audio and video with ffmpeg. The backend runs in its own work
+// directory with a scrubbed environment, the node timeout, and a cap on its
+// address space.
+type MediaExecutor struct {
+	BaseExecutor
+
+	imageBinary string
+	videoBinary string
+	memoryLimit int64
+}
+
+// MediaConfig represents the configuration for a media_process node.
+type MediaConfig struct {
+	Operation     string   `json:"operation"`      // resize, convert, thumbnail or transcode
+	ContentBase64 string   `json:"content_base64"` // The input file
+	Format        string   `json:"format"`         // Output format; defaults to png for images
+	Width         int      `json:"width"`
+	Height        int      `json:"height"`
+	KeepAspect    *bool    `json:"keep_aspect"` // Resize within width x height (default true)
+	Video         bool     `json:"video"`       // Thumbnail a video frame instead of an image
+	At            float64  `json:"at"`          // Seconds into the video to take the thumbnail from
+	Args          []string `json:"args"`        // Allowlisted ffmpeg options for transcode
+	Filename      string   `json:"filename"`
+	Timeout       int      `json:"timeout"`         // seconds
+	MemoryLimitMB int      `json:"memory_limit_mb"` // May lower, never raise, the worker limit
+}
+
+// MediaResponse is the output of a media_process node.
+type MediaResponse struct {
+	Content     string `json:"content"` // Base64 encoded file
+	ContentType string `json:"content_type"`
+	Format      string `json:"format"`
+	Size        int    `json:"size"`
+	Filename    string `json:"filename,omitempty"`
+}
+
+var mediaInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["operation", "content_base64"],
+  "properties": {
+    "operation": {"type": "string", "enum": ["resize", "convert", "thumbnail", "transcode"]},
+    "content_base64": {"type": "string", "contentEncoding": "base64"},
+    "format": {"type": "string", "description": "Output format such as png, jpg, webp, mp4 or mp3"},
+    "width": {"type": "integer", "minimum": 0, "maximum": 10000},
+    "height": {"type": "integer", "minimum": 0, "maximum": 10000},
+    "keep_aspect": {"type": "boolean", "default": true},
+    "video": {"type": "boolean", "description": "Thumbnail a video frame with ffmpeg"},
+    "at": {"type": "number", "minimum": 0, "description": "Seconds into the video to thumbnail"},
+    "args": {"type": "array", "items": {"type": "string"}, "description": "ffmpeg options for transcode, e.g. [\"-c:v\", \"libx264\", \"-crf\", \"23\"]"},
+    "filename": {"type": "string"},
+    "timeout": {"type": "integer", "minimum": 1},
+    "memory_limit_mb": {"type": "integer", "minimum": 1}
+  }
+}`)
+
+var mediaOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["content", "content_type", "format", "size"],
+  "properties": {
+    "content": {"type": "string", "contentEncoding": "base64"},
+    "content_type": {"type": "string"},
+    "format": {"type": "string"},
+    "size": {"type": "integer", "description": "File size in bytes"},
+    "filename": {"type": "string"}
+  }
+}`)
+
+// NewMediaExecutor creates a new media executor. Every operation fails until
+// its backend is set with WithBinaries.
+func NewMediaExecutor() *MediaExecutor {
+	return &MediaExecutor{memoryLimit: defaultMediaMemoryLimit}
+}
+
+// WithBinaries sets the ImageMagick and ffmpeg binaries. Either may be empty
+// to disable the operations that need it.
+func (e *MediaExecutor) WithBinaries(imageBinary, videoBinary string) *MediaExecutor {
+	e.imageBinary = imageBinary
+	e.videoBinary = videoBinary
+	return e
+}
+
+// WithMemoryLimit caps the address space of the backend process.
+func (e *MediaExecutor) WithMemoryLimit(bytes int64) *MediaExecutor {
+	if bytes > 0 {
+		e.memoryLimit = bytes
+	}
+	return e
+}
+
+// FindMediaBinaries resolves the ImageMagick and ffmpeg backends for
+// media_process nodes. Configured paths must exist; otherwise the first known
+// binary on PATH is used. A backend that is not installed is returned as "".
+func FindMediaBinaries(configuredImage, configuredVideo string) (imageBinary, videoBinary string, err error) {
+	if imageBinary, err = findMediaBinary(configuredImage, imageBinaryCandidates); err != nil {
+		return "", "", err
+	}
+	if videoBinary, err = findMediaBinary(configuredVideo, videoBinaryCandidates); err != nil {
+		return "", "", err
+	}
+	return imageBinary, videoBinary, nil
+}
+
+func findMediaBinary(configured string, candidates []string) (string, error) {
+	if configured != "" {
+		path, err := exec.LookPath(configured)
+		if err != nil {
+			return "", fmt.Errorf("media binary %q not found: %w", configured, err)
+		}
+		return path, nil
+	}
+	for _, name := range candidates {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+func (e *MediaExecutor) NodeType() string {
+	return "media_process"
+}
+
+func (e *MediaExecutor) InputSchema() json.RawMessage {
+	return mediaInputSchema
+}
+
+func (e *MediaExecutor) OutputSchema() json.RawMessage {
+	return mediaOutputSchema
+}
+
+func (e *MediaExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: errorType},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config MediaConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse media config: %v", err), ErrorTypeNonRetryable)
+	}
+	if config.ContentBase64 == "" {
+		return failed("content_base64 is required", ErrorTypeNonRetryable)
+	}
+	input, err := decodeBase64(config.ContentBase64)
+	if err != nil {
+		return failed(fmt.Sprintf("invalid base64 content: %v", err), ErrorTypeNonRetryable)
+	}
+	if len(input) > maxMediaInputBytes {
+		return failed(fmt.Sprintf("input file is %d bytes; the limit is %d", len(input), maxMediaInputBytes), ErrorTypeNonRetryable)
+	}
+
+	// Fail before running anything so a missing backend is reported as such.
+	binary, format, args, err := e.command(config)
+	if err != nil {
+		return failed(err.Error(), ErrorTypeNonRetryable)
+	}
+
+	timeout := defaultMediaTimeout
+	if req.Timeout > 0 {
+		timeout = req.Timeout
+	}
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	memoryLimit := e.memoryLimit
+	if limit := int64(config.MemoryLimitMB) * 1024 * 1024; limit > 0 && limit < memoryLimit {
+		memoryLimit = limit
+	}
+
+	output, err := runMediaCommand(ctx, binary, args, input, format, memoryLimit)
+	if err != nil {
+		if ctx.Err() != nil {
+			return failed(fmt.Sprintf("media %s timed out after %s", config.Operation, timeout), ErrorTypeTimeout)
+		}
+		return failed(fmt.Sprintf("media %s failed: %v", config.Operation, err), ErrorTypeNonRetryable)
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Media %s produced %s (%d bytes from %d)", config.Operation, format, len(output), len(input)),
+	})
+
+	result, err := json.Marshal(MediaResponse{
+		Content:     base64.StdEncoding.EncodeToString(output),
+		ContentType: mediaContentTypes[format],
+		Format:      format,
+		Size:        len(output),
+		Filename:    config.Filename,
+	})
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err), ErrorTypeNonRetryable)
+	}
+
+	return &ExecuteResponse{
+		Output:   result,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// command builds the backend invocation for an operation. The input and
+// output files are the placeholders mediaInputFile and mediaOutputFile,
+// which runMediaCommand resolves inside its work directory.
+func (e *MediaExecutor) command(config MediaConfig) (binary, format string, args []string, err error) {
+	format = strings.ToLower(strings.TrimPrefix(config.Format, "."))
+	operation := strings.ToLower(config.Operation)
+
+	useVideo := operation == mediaOperationTranscode || (operation == mediaOperationThumbnail && config.Video)
+	if format == "" {
+		switch {
+		case operation == mediaOperationTranscode:
+			return "", "", nil, fmt.Errorf("format is required for transcode")
+		case useVideo:
+			format = "jpg"
+		default:
+			format = "png"
+		}
+	}
+	if _, ok := mediaContentTypes[format]; !ok {
+		return "", "", nil, fmt.Errorf("unsupported format %q", config.Format)
+	}
+	if operation != mediaOperationTranscode && !mediaImageFormats[format] {
+		return "", "", nil, fmt.Errorf("%s produces images; %q is not an image format", operation, format)
+	}
+	if config.Width < 0 || config.Height < 0 || config.Width > maxMediaDimension || config.Height > maxMediaDimension {
+		return "", "", nil, fmt.Errorf("width and height must be between 0 and %d", maxMediaDimension)
+	}
+	if len(config.Args) > 0 && operation != mediaOperationTranscode {
+		return "", "", nil, fmt.Errorf("args are only supported for transcode")
+	}
+
+	geometry := func() (string, error) {
+		if config.Width == 0 && config.Height == 0 {
+			return "", fmt.Errorf("width or height is required for %s", operation)
+		}
+		g := ""
+		if config.Width > 0 {
+			g = strconv.Itoa(config.Width)
+		}
+		g += "x"
+		if config.Height > 0 {
+			g += strconv.Itoa(config.Height)
+		}
+		if config.KeepAspect != nil && !*config.KeepAspect && config.Width > 0 && config.Height > 0 {
+			g += "!"
+		}
+		return g, nil
+	}
+
+	switch operation {
+	case mediaOperationResize, mediaOperationThumbnail:
+		if operation == mediaOperationThumbnail && config.Video {
+			width := config.Width
+			if width == 0 {
+				width = 320
+			}
+			if config.At < 0 {
+				return "", "", nil, fmt.Errorf("at must not be negative")
+			}
+			args = []string{
+				"-nostdin", "-y", "-loglevel", "error",
+				"-ss", strconv.FormatFloat(config.At, 'f', 3, 64),
+				"-i", mediaInputFile,
+				"-frames:v", "1",
+				"-vf", fmt.Sprintf("scale=%d:-2", width),
+				mediaOutputFile,
+			}
+			break
+		}
+		g, gerr := geometry()
+		if gerr != nil {
+			return "", "", nil, gerr
+		}
+		flag := "-resize"
+		if operation == mediaOperationThumbnail {
+			flag = "-thumbnail"
+		}
+		// [0] takes the first frame of animated or multi-page input.
+		args = []string{mediaInputFile + "[0]", flag, g, mediaOutputFile}
+	case mediaOperationConvert:
+		args = []string{mediaInputFile, mediaOutputFile}
+	case mediaOperationTranscode:
+		transcodeArgs, aerr := validateTranscodeArgs(config.Args)
+		if aerr != nil {
+			return "", "", nil, aerr
+		}
+		args = append([]string{"-nostdin", "-y", "-loglevel", "error", "-i", mediaInputFile}, transcodeArgs...)
+		args = append(args, mediaOutputFile)
+	default:
+		return "", "", nil, fmt.Errorf("unsupported operation %q; use resize, convert, thumbnail or transcode", config.Operation)
+	}
+
+	if useVideo {
+		if e.videoBinary == "" {
+			return "", "", nil, fmt.Errorf("%w: %s requires ffmpeg; install it or set MEDIA_FFMPEG_BINARY on the worker", errMissingMediaBinary, operation)
+		}
+		return e.videoBinary, format, args, nil
+	}
+	if e.imageBinary == "" {
+		return "", "", nil, fmt.Errorf("%w: %s requires ImageMagick; install it or set MEDIA_CONVERT_BINARY on the worker", errMissingMediaBinary, operation)
+	}
+	return e.imageBinary, format, args, nil
+}
+
+// validateTranscodeArgs checks ffmpeg options against mediaTranscodeArgs.
+func validateTranscodeArgs(args []string) ([]string, error) {
+	validated := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		flag := args[i]
+		pattern, ok := mediaTranscodeArgs[flag]
+		if !ok {
+			return nil, fmt.Errorf("transcode option %q is not allowed", flag)
+		}
+		validated = append(validated, flag)
+		if pattern == nil {
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("transcode option %q needs a value", flag)
+		}
+		i++
+		if !pattern.MatchString(args[i]) {
+			return nil, fmt.Errorf("invalid value %q for transcode option %q", args[i], flag)
+		}
+		validated = append(validated, args[i])
+	}
+	return validated, nil
+}
+
+const (
+	mediaInputFile  = "\x00input"
+	mediaOutputFile = "\x00output"
+)
+
+// mediaLimitScript caps the address space in KiB before replacing the shell
+// with the backend, so the limit applies to the backend alone.
+const mediaLimitScript = `ulimit -v "$1" || exit 125; shift; exec "$@"`
+
+// runMediaCommand runs the backend over input in a fresh work directory and
+// returns the output file. The backend gets a minimal environment rather
+// than the worker's, which may hold secrets.
+func runMediaCommand(ctx context.Context, binary string, args []string, input []byte, format string, memoryLimit int64) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "media-process-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	inputPath := filepath.Join(dir, "input")
+	outputPath := filepath.Join(dir, "output."+format)
+	if err := os.WriteFile(inputPath, input, 0o600); err != nil {
+		return nil, err
+	}
+
+	resolved := make([]string, len(args))
+	for i, arg := range args {
+		switch {
+		case arg == mediaOutputFile:
+			resolved[i] = outputPath
+		case strings.HasPrefix(arg, mediaInputFile):
+			resolved[i] = inputPath + strings.TrimPrefix(arg, mediaInputFile)
+		default:
+			resolved[i] = arg
+		}
+	}
+
+	shellArgs := append([]string{"-c", mediaLimitScript, "media-process", strconv.FormatInt(memoryLimit/1024, 10), binary}, resolved...)
+	cmd := exec.CommandContext(ctx, "/bin/sh", shellArgs...)
+	cmd.Dir = dir
+	cmd.Env = []string{
+		"PATH=/usr/local/bin:/usr/bin:/bin",
+		"HOME=" + dir,
+		"TMPDIR=" + dir,
+		"MAGICK_TEMPORARY_PATH=" + dir,
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, commandError(err, tailString(stderr.String(), 1024))
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("no output file produced: %w", err)
+	}
+	return output, nil
+}
+
+// tailString returns at most the last n bytes of s.
+func tailString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeMediaBinary writes a stand-in for convert or ffmpeg that records its
+// arguments and copies the input file to the output file, its last argument.
+func fakeMediaBinary(t *testing.T) (binary, argsFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	binary = filepath.Join(dir, "fake-media")
+	argsFile = filepath.Join(dir, "args")
+	script := "#!/bin/sh\n" +
+		"printf '%s\\n' \"$@\" > " + argsFile + "\n" +
+		"for last; do :; done\n" +
+		"cp input \"$last\"\n"
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return binary, argsFile
+}
+
+func TestMediaExecutorResize(t *testing.T) {
+	binary, argsFile := fakeMediaBinary(t)
+	content := base64.StdEncoding.EncodeToString([]byte("image-bytes"))
+
+	resp, err := NewMediaExecutor().WithBinaries(binary, "").Execute(context.Background(), &ExecuteRequest{
+		NodeID: "resize",
+		Config: json.RawMessage(`{"operation": "resize", "content_base64": "` + content + `", "width": 200, "format": "webp"}`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("Execute error: %v %+v", err, resp.Error)
+	}
+
+	var out MediaResponse
+	if err := json.Unmarshal(resp.Output, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Content != content || out.ContentType != "image/webp" || out.Format != "webp" || out.Size != len("image-bytes") {
+		t.Fatalf("unexpected output %+v", out)
+	}
+
+	recorded, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := strings.Split(strings.TrimSpace(string(recorded)), "\n")
+	if len(args) != 4 || !strings.HasSuffix(args[0], "/input[0]") || args[1] != "-resize" || args[2] != "200x" || !strings.HasSuffix(args[3], "/output.webp") {
+		t.Fatalf("unexpected convert args %q", args)
+	}
+}
+
+func TestMediaExecutorRejectsUnsafeRequests(t *testing.T) {
+	binary, _ := fakeMediaBinary(t)
+	content := base64.StdEncoding.EncodeToString([]byte("video-bytes"))
+	e := NewMediaExecutor().WithBinaries("", binary)
+
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"missing binary", `{"operation": "resize", "content_base64": "` + content + `", "width": 10}`, "MEDIA_CONVERT_BINARY"},
+		{"extra output", `{"operation": "transcode", "content_base64": "` + content + `", "format": "mp4", "args": ["-c:v", "libx264", "/etc/passwd"]}`, `"/etc/passwd" is not allowed`},
+		{"flag as value", `{"operation": "transcode", "content_base64": "` + content + `", "format": "mp4", "args": ["-c:v", "-i"]}`, "invalid value"},
+		{"filter injection", `{"operation": "transcode", "content_base64": "` + content + `", "format": "mp4", "args": ["-vf", "scale=2:2,movie=/etc/passwd"]}`, "invalid value"},
+		{"unknown format", `{"operation": "transcode", "content_base64": "` + content + `", "format": "exe"}`, "unsupported format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := e.Execute(context.Background(), &ExecuteRequest{Config: json.RawMessage(tt.config)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable || !strings.Contains(resp.Error.Message, tt.want) {
+				t.Fatalf("expected non-retryable error containing %q, got %+v", tt.want, resp.Error)
+			}
+		})
+	}
+
+	resp, err := e.Execute(context.Background(), &ExecuteRequest{
+		Config: json.RawMessage(`{"operation": "transcode", "content_base64": "` + content + `", "format": "mp4", "args": ["-c:v", "libx264", "-crf", "23", "-an"]}`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("Execute error: %v %+v", err, resp.Error)
+	}
+}
@@ -0,0 +1,333 @@
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	mysqlDefaultTimeout  = 30 * time.Second
+	mysqlMaxOpenConns    = 10
+	mysqlMaxIdleConns    = 5
+	mysqlConnMaxLifetime = 5 * time.Minute
+)
+
+// mysqlTransientErrors are server errors worth retrying: lock wait timeout,
+// deadlock, too many connections and server shutdown.
+var mysqlTransientErrors = map[uint16]bool{
+	1205: true,
+	1213: true,
+	1040: true,
+	1053: true,
+}
+
+// MySQLExecutor runs queries against MySQL, keeping one bounded connection
+// pool per DSN.
+type MySQLExecutor struct {
+	pools map[string]*sql.DB
+	mu    sync.Mutex
+}
+
+// MySQLConfig represents the configuration for an action_mysql node.
+type MySQLConfig struct {
+	DSN     string        `json:"dsn"`     // e.g. user:pass@tcp(host:3306)/db
+	Query   string        `json:"query"`   // SQL with ? placeholders
+	Params  []interface{} `json:"params"`  // Query parameters
+	Mode    string        `json:"mode"`    // query (default), exec
+	Timeout int           `json:"timeout"` // Seconds (default 30)
+}
+
+// NewMySQLExecutor creates a new MySQL executor.
+func NewMySQLExecutor() *MySQLExecutor {
+	return &MySQLExecutor{
+		pools: make(map[string]*sql.DB),
+	}
+}
+
+// Close closes all cached connection pools.
+func (e *MySQLExecutor) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for dsn, db := range e.pools {
+		_ = db.Close()
+		delete(e.pools, dsn)
+	}
+}
+
+func (e *MySQLExecutor) NodeType() string {
+	return "action_mysql"
+}
+
+var mysqlInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["dsn", "query"],
+  "properties": {
+    "dsn": {"type": "string", "minLength": 1},
+    "query": {"type": "string", "minLength": 1},
+    "params": {"type": "array"},
+    "mode": {"type": "string", "enum": ["query", "exec"], "default": "query"},
+    "timeout": {"type": "integer", "minimum": 0, "description": "Seconds, 0 for the default of 30"}
+  }
+}`)
+
+var mysqlOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "rows": {"type": "array", "items": {"type": "object"}},
+    "rows_affected": {"type": "integer"},
+    "last_insert_id": {"type": "integer"},
+    "duration": {"type": "string"}
+  }
+}`)
+
+func (e *MySQLExecutor) InputSchema() json.RawMessage {
+	return mysqlInputSchema
+}
+
+func (e *MySQLExecutor) OutputSchema() json.RawMessage {
+	return mysqlOutputSchema
+}
+
+func (e *MySQLExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: errorType},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config MySQLConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse mysql config: %v", err), ErrorTypeNonRetryable)
+	}
+	if err := config.validate(); err != nil {
+		return failed(err.Error(), ErrorTypeNonRetryable)
+	}
+
+	db, err := e.pool(config.DSN)
+	if err != nil {
+		return failed(fmt.Sprintf("invalid dsn: %v", err), ErrorTypeNonRetryable)
+	}
+
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = mysqlDefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "DEBUG",
+		Message:   fmt.Sprintf("Executing %s for node %s: %s", config.mode(), req.NodeID, truncateString(config.Query, 200)),
+	})
+
+	var response DatabaseResponse
+	if config.mode() == "exec" {
+		response, err = mysqlExec(ctx, db, config)
+	} else {
+		response, err = mysqlQuery(ctx, db, config)
+	}
+	if err != nil {
+		return failed(err.Error(), mysqlErrorType(err))
+	}
+	response.Duration = time.Since(start).String()
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Query completed, %d rows affected", response.RowsAffected),
+	})
+
+	output, err := json.Marshal(response)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err), ErrorTypeNonRetryable)
+	}
+
+	return &ExecuteResponse{
+		Output:   output,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+func (c *MySQLConfig) mode() string {
+	if c.Mode == "" {
+		return "query"
+	}
+	return c.Mode
+}
+
+func (c *MySQLConfig) validate() error {
+	if c.DSN == "" {
+		return errors.New("dsn is required")
+	}
+	if strings.TrimSpace(c.Query) == "" {
+		return errors.New("query is required")
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	switch c.mode() {
+	case "query", "exec":
+	default:
+		return fmt.Errorf("unknown mode: %s", c.Mode)
+	}
+	return nil
+}
+
+// pool returns the cached connection pool for dsn, creating it on first use.
+// Time columns are always parsed so they are returned as RFC 3339 strings.
+func (e *MySQLExecutor) pool(dsn string) (*sql.DB, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if db, ok := e.pools[dsn]; ok {
+		return db, nil
+	}
+
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ParseTime = true
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(mysqlMaxOpenConns)
+	db.SetMaxIdleConns(mysqlMaxIdleConns)
+	db.SetConnMaxLifetime(mysqlConnMaxLifetime)
+	e.pools[dsn] = db
+	return db, nil
+}
+
+func mysqlQuery(ctx context.Context, db *sql.DB, config MySQLConfig) (DatabaseResponse, error) {
+	var response DatabaseResponse
+
+	rows, err := db.QueryContext(ctx, config.Query, config.Params...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return response, err
+	}
+
+	response.Rows = make([]map[string]interface{}, 0)
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return response, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			row[col.Name()] = mysqlValue(values[i], col.DatabaseTypeName())
+		}
+		response.Rows = append(response.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return response, err
+	}
+
+	response.RowsAffected = int64(len(response.Rows))
+	return response, nil
+}
+
+func mysqlExec(ctx context.Context, db *sql.DB, config MySQLConfig) (DatabaseResponse, error) {
+	var response DatabaseResponse
+
+	result, err := db.ExecContext(ctx, config.Query, config.Params...)
+	if err != nil {
+		return response, err
+	}
+
+	response.RowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return response, err
+	}
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return response, err
+	}
+	response.LastInsertID = lastInsertID
+	return response, nil
+}
+
+// mysqlValue converts a scanned column value to a JSON-serializable type.
+// The driver returns most columns as raw bytes, so they are decoded by the
+// column's type: integers and floats to numbers, DECIMAL to an exact JSON
+// number, JSON columns as-is, binary data that is not UTF-8 as base64 and
+// everything else to a string.
+func mysqlValue(v interface{}, typeName string) interface{} {
+	raw, ok := v.([]byte)
+	if !ok {
+		return convertValue(v)
+	}
+
+	s := string(raw)
+	switch strings.TrimPrefix(typeName, "UNSIGNED ") {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "YEAR":
+		if strings.HasPrefix(typeName, "UNSIGNED ") {
+			if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return n
+			}
+		} else if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case "FLOAT", "DOUBLE":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case "DECIMAL":
+		return json.Number(s)
+	case "JSON":
+		if json.Valid(raw) {
+			return json.RawMessage(raw)
+		}
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT", "GEOMETRY":
+		if !utf8.Valid(raw) {
+			return raw
+		}
+	}
+	return s
+}
+
+// mysqlErrorType classifies an error: errors reported by the server are
+// non-retryable apart from transient ones such as deadlocks, while failures
+// to reach it are retryable.
+func mysqlErrorType(err error) string {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if mysqlTransientErrors[mysqlErr.Number] {
+			return ErrorTypeRetryable
+		}
+		return ErrorTypeNonRetryable
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTypeTimeout
+	}
+	return ErrorTypeRetryable
+}
@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestMySQLExecutorRejectsInvalidConfig(t *testing.T) {
+	e := NewMySQLExecutor()
+	defer e.Close()
+	for _, config := range []string{
+		`{"query":"SELECT 1"}`,
+		`{"dsn":"root@tcp(localhost:3306)/app"}`,
+		`{"dsn":"root@tcp(localhost:3306)/app","query":"SELECT 1","mode":"stream"}`,
+		`{"dsn":"not a dsn","query":"SELECT 1"}`,
+	} {
+		resp, err := e.Execute(context.Background(), &ExecuteRequest{NodeID: "db-1", Config: json.RawMessage(config)})
+		if err != nil {
+			t.Fatalf("Execute error: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+			t.Fatalf("config %s: expected non-retryable error, got %+v", config, resp.Error)
+		}
+	}
+}
+
+func TestMySQLValue(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		typeName string
+		want     interface{}
+	}{
+		{[]byte("-42"), "INT", int64(-42)},
+		{[]byte("18446744073709551615"), "UNSIGNED BIGINT", uint64(18446744073709551615)},
+		{[]byte("2.5"), "DOUBLE", 2.5},
+		{[]byte("12345678901234567890.01"), "DECIMAL", json.Number("12345678901234567890.01")},
+		{[]byte(`{"a":1}`), "JSON", json.RawMessage(`{"a":1}`)},
+		{[]byte("hello"), "VARCHAR", "hello"},
+		{[]byte{0xff, 0x00}, "VARBINARY", []byte{0xff, 0x00}},
+		{int64(7), "BIGINT", int64(7)},
+		{nil, "INT", nil},
+	}
+	for _, c := range cases {
+		if got := mysqlValue(c.value, c.typeName); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("mysqlValue(%v, %s) = %#v, want %#v", c.value, c.typeName, got, c.want)
+		}
+	}
+}
+
+func TestMySQLErrorType(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{&mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"}, ErrorTypeNonRetryable},
+		{fmt.Errorf("query: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}), ErrorTypeNonRetryable},
+		{&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, ErrorTypeRetryable},
+		{mysql.ErrInvalidConn, ErrorTypeRetryable},
+		{context.DeadlineExceeded, ErrorTypeTimeout},
+	}
+	for _, c := range cases {
+		if got := mysqlErrorType(c.err); got != c.want {
+			t.Errorf("mysqlErrorType(%v) = %s, want %s", c.err, got, c.want)
+		}
+	}
+}
@@ -9,7 +9,9 @@ import (
 
 // OutputExecutor handles output_log nodes.
 // This node logs data and passes it through to the next node.
-type OutputExecutor struct{}
+type OutputExecutor struct {
+	BaseExecutor
+}
 
 // NewOutputExecutor creates a new output executor.
 func NewOutputExecutor() *OutputExecutor {
@@ -99,7 +101,7 @@ func (e *OutputExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exe
 	// Create output with metadata
 	result := map[string]interface{}{
 		"logged":      true,
-		"logged_at":   time.Now().UTC().Format(time.RFC3339),
+		"logged_at":   req.Deterministic.Now().UTC().Format(time.RFC3339),
 		"node_id":     req.NodeID,
 		"label":       config.Label,
 		"input":       inputData,
@@ -0,0 +1,322 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/linkflow/engine/internal/expression"
+)
+
+const (
+	pollUntilDefaultInterval    = 10 * time.Second
+	pollUntilDefaultMaxAttempts = 30
+	pollUntilMaxAttempts        = 1000
+)
+
+// PollUntilExecutor sends the same HTTP request until a completion condition
+// holds for the response. Waits up to the inline threshold happen in-process;
+// longer ones are handed to the timer service, which completes the activity
+// with a NodeContinuation so the workflow runs the node again for the next
+// attempt instead of holding a poller.
+type PollUntilExecutor struct {
+	http            *HTTPExecutor
+	expressions     *expression.Engine
+	timers          TimerScheduler
+	inlineThreshold time.Duration
+}
+
+// PollUntilConfig represents the configuration for a poll_until node.
+type PollUntilConfig struct {
+	Request HTTPConfig `json:"request"`
+	// Condition is evaluated against the response as
+	// {"status_code", "headers", "body", "attempt"}, e.g.
+	// "body.status == 'done'".
+	Condition   string `json:"condition"`
+	Interval    int    `json:"interval"`     // Seconds between attempts (default 10)
+	MaxAttempts int    `json:"max_attempts"` // Attempts before giving up (default 30, at most 1000)
+	Timeout     int    `json:"timeout"`      // Seconds for all attempts (0 = no limit)
+}
+
+// PollUntilResponse is the output of a poll_until node: the response that
+// satisfied the condition.
+type PollUntilResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers"`
+	Body       json.RawMessage   `json:"body"`
+	Attempts   int               `json:"attempts"`
+}
+
+// pollUntilState is carried between runs of a poll_until node whose waits
+// are handed to the timer service.
+type pollUntilState struct {
+	Attempts int       `json:"attempts"`
+	Deadline time.Time `json:"deadline,omitempty"`
+}
+
+// NewPollUntilExecutor creates a poll_until executor that sends its requests
+// through httpExecutor.
+func NewPollUntilExecutor(httpExecutor *HTTPExecutor) *PollUntilExecutor {
+	return &PollUntilExecutor{
+		http:            httpExecutor,
+		expressions:     expression.NewEngine(),
+		inlineThreshold: DefaultDelayInlineThreshold,
+	}
+}
+
+// WithTimerScheduler sets the scheduler used for intervals longer than the
+// inline threshold. Without one, every wait blocks the poller.
+func (e *PollUntilExecutor) WithTimerScheduler(timers TimerScheduler) *PollUntilExecutor {
+	e.timers = timers
+	return e
+}
+
+// WithInlineThreshold sets the longest interval waited out in-process.
+func (e *PollUntilExecutor) WithInlineThreshold(threshold time.Duration) *PollUntilExecutor {
+	if threshold >= 0 {
+		e.inlineThreshold = threshold
+	}
+	return e
+}
+
+func (e *PollUntilExecutor) NodeType() string {
+	return "poll_until"
+}
+
+var pollUntilInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["request", "condition"],
+  "properties": {
+    "request": {
+      "type": "object",
+      "required": ["url"],
+      "properties": {
+        "method": {"type": "string", "default": "GET"},
+        "url": {"type": "string"},
+        "headers": {"type": "object", "additionalProperties": {"type": "string"}},
+        "body": {},
+        "timeout": {"type": "integer", "minimum": 0, "description": "Per-request timeout in seconds"}
+      }
+    },
+    "condition": {"type": "string", "description": "Expression over status_code, headers, body and attempt"},
+    "interval": {"type": "integer", "minimum": 0, "default": 10},
+    "max_attempts": {"type": "integer", "minimum": 0, "maximum": 1000, "default": 30},
+    "timeout": {"type": "integer", "minimum": 0, "description": "Seconds for all attempts, 0 for no limit"}
+  }
+}`)
+
+var pollUntilOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["status_code", "attempts"],
+  "properties": {
+    "status_code": {"type": "integer"},
+    "headers": {"type": "object", "additionalProperties": {"type": "string"}},
+    "body": {},
+    "attempts": {"type": "integer"}
+  }
+}`)
+
+func (e *PollUntilExecutor) InputSchema() json.RawMessage {
+	return pollUntilInputSchema
+}
+
+func (e *PollUntilExecutor) OutputSchema() json.RawMessage {
+	return pollUntilOutputSchema
+}
+
+func (e *PollUntilExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+	var attempts []ConnectorAttempt
+	var fixtures []DeterministicFixture
+
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:                 &ExecutionError{Message: message, Type: errorType},
+			ConnectorAttempts:     attempts,
+			DeterministicFixtures: fixtures,
+			Logs:                  logs,
+			Duration:              time.Since(start),
+		}, nil
+	}
+
+	var config PollUntilConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse poll_until config: %v", err), ErrorTypeNonRetryable)
+	}
+	if config.Request.URL == "" {
+		return failed("request.url is required", ErrorTypeNonRetryable)
+	}
+	if config.Condition == "" {
+		return failed("condition is required", ErrorTypeNonRetryable)
+	}
+	if config.MaxAttempts > pollUntilMaxAttempts {
+		return failed(fmt.Sprintf("max_attempts must be at most %d", pollUntilMaxAttempts), ErrorTypeNonRetryable)
+	}
+	maxAttempts := config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = pollUntilDefaultMaxAttempts
+	}
+	interval := pollUntilDefaultInterval
+	if config.Interval > 0 {
+		interval = time.Duration(config.Interval) * time.Second
+	}
+
+	// A run resumed by the timer service carries the attempts made so far.
+	var state pollUntilState
+	if cont, ok := ParseNodeContinuation(req.Input); ok {
+		if err := json.Unmarshal(cont, &state); err != nil {
+			return failed(fmt.Sprintf("invalid poll_until state: %v", err), ErrorTypeNonRetryable)
+		}
+	} else if config.Timeout > 0 {
+		state.Deadline = start.Add(time.Duration(config.Timeout) * time.Second)
+	}
+
+	requestConfig, err := json.Marshal(config.Request)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to encode request: %v", err), ErrorTypeNonRetryable)
+	}
+	pollReq := *req
+	pollReq.Config = requestConfig
+
+	for {
+		state.Attempts++
+		resp, err := e.http.execute(ctx, &pollReq)
+		if err != nil {
+			return nil, err
+		}
+		for _, attempt := range resp.ConnectorAttempts {
+			if attempt.Meta == nil {
+				attempt.Meta = make(map[string]interface{})
+			}
+			attempt.Meta["poll_attempt"] = state.Attempts
+			attempts = append(attempts, attempt)
+		}
+		fixtures = append(fixtures, resp.DeterministicFixtures...)
+
+		if resp.Output == nil && resp.Error != nil && resp.Error.Type != ErrorTypeRetryable {
+			return failed(fmt.Sprintf("attempt %d: %s", state.Attempts, resp.Error.Message), resp.Error.Type)
+		}
+
+		if resp.Output != nil {
+			done, err := e.satisfied(config.Condition, resp.Output, state.Attempts)
+			if err != nil {
+				return failed(fmt.Sprintf("failed to evaluate condition: %v", err), ErrorTypeNonRetryable)
+			}
+			if done {
+				logs = append(logs, LogEntry{
+					Timestamp: time.Now(),
+					Level:     "INFO",
+					Message:   fmt.Sprintf("Condition met on attempt %d", state.Attempts),
+				})
+				return e.completed(resp.Output, state.Attempts, attempts, fixtures, logs, start)
+			}
+		}
+
+		if state.Attempts >= maxAttempts {
+			return failed(fmt.Sprintf("condition not met after %d attempts", state.Attempts), ErrorTypeNonRetryable)
+		}
+		next := time.Now().Add(interval)
+		if !state.Deadline.IsZero() && next.After(state.Deadline) {
+			return failed(fmt.Sprintf("condition not met before the %ds timeout (%d attempts)", config.Timeout, state.Attempts), ErrorTypeTimeout)
+		}
+
+		if interval > e.inlineThreshold && e.timers != nil {
+			return e.scheduleNextAttempt(req, state, next, attempts, fixtures, logs, start)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return failed("poll_until was canceled", ErrorTypeNonRetryable)
+		case <-timer.C:
+		}
+	}
+}
+
+// satisfied evaluates the completion condition against one response. A
+// condition that refers to a field the response lacks is not yet met.
+func (e *PollUntilExecutor) satisfied(condition string, output json.RawMessage, attempt int) (bool, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(output, &data); err != nil {
+		return false, err
+	}
+	data["attempt"] = attempt
+
+	done, err := e.expressions.EvaluateBool(condition, data)
+	if errors.Is(err, expression.ErrPathNotFound) {
+		return false, nil
+	}
+	return done, err
+}
+
+func (e *PollUntilExecutor) completed(output json.RawMessage, attemptCount int, attempts []ConnectorAttempt, fixtures []DeterministicFixture, logs []LogEntry, start time.Time) (*ExecuteResponse, error) {
+	var httpResp HTTPResponse
+	if err := json.Unmarshal(output, &httpResp); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(PollUntilResponse{
+		StatusCode: httpResp.StatusCode,
+		Headers:    httpResp.Headers,
+		Body:       httpResp.Body,
+		Attempts:   attemptCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteResponse{
+		Output:                encoded,
+		ConnectorAttempts:     attempts,
+		DeterministicFixtures: fixtures,
+		Logs:                  logs,
+		Duration:              time.Since(start),
+	}, nil
+}
+
+// scheduleNextAttempt frees the poller until the next attempt: the activity
+// stays pending until the timer service completes it with a continuation
+// holding the poll state, and the workflow then runs the node again.
+func (e *PollUntilExecutor) scheduleNextAttempt(req *ExecuteRequest, state pollUntilState, next time.Time, attempts []ConnectorAttempt, fixtures []DeterministicFixture, logs []LogEntry, start time.Time) (*ExecuteResponse, error) {
+	result, err := NewNodeContinuation(state)
+	if err != nil {
+		return nil, err
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Condition not met on attempt %d: scheduling the next attempt for %s", state.Attempts, next.Format(time.RFC3339)),
+	})
+
+	return &ExecuteResponse{
+		Pending: &PendingActivity{
+			ScheduleToCloseTimeout: time.Until(next) + delayTimerGrace,
+			OnPending: func(ctx context.Context, taskToken string) error {
+				sum := sha256.Sum256([]byte(taskToken))
+				return e.timers.ScheduleActivityTimer(ctx, &ActivityTimerRequest{
+					Namespace:  req.Namespace,
+					WorkflowID: req.WorkflowID,
+					RunID:      req.RunID,
+					TimerID:    fmt.Sprintf("poll-%s-%x", req.NodeID, sum[:8]),
+					TaskToken:  taskToken,
+					FireAt:     next,
+					Result:     result,
+				})
+			},
+		},
+		ConnectorAttempts:     attempts,
+		DeterministicFixtures: fixtures,
+		Logs:                  logs,
+		Metadata: map[string]string{
+			"timer_requested": "true",
+			"poll_attempts":   strconv.Itoa(state.Attempts),
+			"resume_at":       next.Format(time.RFC3339),
+		},
+		Duration: time.Since(start),
+	}, nil
+}
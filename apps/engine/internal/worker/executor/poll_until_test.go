@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestPollUntilExecutorOffloadsWaitsAndResumes(t *testing.T) {
+	t.Parallel()
+
+	request, _ := json.Marshal(map[string]interface{}{
+		"method":  "GET",
+		"url":     "https://example.com/jobs/42",
+		"headers": canonicalHeaders(nil),
+		"body":    json.RawMessage(nil),
+	})
+	deterministic := &DeterministicContext{
+		Mode: "replay",
+		Fixtures: []DeterministicFixture{{
+			RequestFingerprint: fmt.Sprintf("%x", sha256.Sum256(request)),
+			Response:           json.RawMessage(`{"status_code":200,"headers":{},"body":{"state":"running"}}`),
+		}},
+	}
+
+	timers := &recordingTimerScheduler{}
+	executor := NewPollUntilExecutor(NewHTTPExecutor()).WithTimerScheduler(timers)
+	run := func(config string, input json.RawMessage) *ExecuteResponse {
+		resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+			NodeType:      "poll_until",
+			NodeID:        "wait-for-job",
+			WorkflowID:    "wf-1",
+			RunID:         "run-1",
+			Namespace:     "default",
+			Config:        json.RawMessage(config),
+			Input:         input,
+			Attempt:       1,
+			Deterministic: deterministic,
+		})
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		return resp
+	}
+
+	config := `{"request":{"url":"https://example.com/jobs/42"},"condition":"attempt >= 2","interval":60}`
+	first := run(config, nil)
+	if first.Error != nil || first.Pending == nil {
+		t.Fatalf("first attempt = %+v, want a pending activity", first)
+	}
+	if err := first.Pending.OnPending(context.Background(), "token-1"); err != nil {
+		t.Fatalf("schedule timer: %v", err)
+	}
+	if len(timers.requests) != 1 || timers.requests[0].TaskToken != "token-1" {
+		t.Fatalf("timer requests = %+v, want one for the pending task", timers.requests)
+	}
+	if len(first.ConnectorAttempts) != 1 || first.ConnectorAttempts[0].Meta["poll_attempt"] != 1 {
+		t.Fatalf("connector attempts = %+v, want poll attempt 1", first.ConnectorAttempts)
+	}
+
+	// The timer completes the activity with a continuation, which the
+	// workflow passes back as the node's input.
+	second := run(config, timers.requests[0].Result)
+	if second.Error != nil {
+		t.Fatalf("resumed attempt failed: %+v", second.Error)
+	}
+	var output PollUntilResponse
+	if err := json.Unmarshal(second.Output, &output); err != nil {
+		t.Fatalf("decode output %s: %v", second.Output, err)
+	}
+	if output.Attempts != 2 || output.StatusCode != 200 || string(output.Body) != `{"state":"running"}` {
+		t.Fatalf("output = %s, want the second response", second.Output)
+	}
+
+	exhausted := run(`{"request":{"url":"https://example.com/jobs/42"},"condition":"body.state == 'done'","max_attempts":1}`, nil)
+	if exhausted.Error == nil || exhausted.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("exhausted poll = %+v, want a non-retryable error", exhausted.Error)
+	}
+
+	blocked := run(`{"request":{"url":"http://localhost/jobs/42"},"condition":"status_code == 200"}`, nil)
+	if blocked.Error == nil || blocked.Error.Type != ErrorTypeNonRetryable || blocked.Output != nil {
+		t.Fatalf("private URL poll = %+v, want a non-retryable error", blocked)
+	}
+}
@@ -0,0 +1,290 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrConnectorRateLimited is returned when a provider token cannot be acquired
+// before the configured deadline.
+var ErrConnectorRateLimited = errors.New("connector rate limit exceeded")
+
+// minConnectorLimiterSweep is the number of provider buckets below which idle
+// buckets are not swept.
+const minConnectorLimiterSweep = 1024
+
+// DefaultConnectorRateLimitRefreshInterval is how often Watch re-reads the
+// connector rate limits.
+const DefaultConnectorRateLimitRefreshInterval = 30 * time.Second
+
+// DefaultConnectorRateLimitMaxWait is how long Acquire waits for a token when
+// the configuration does not say.
+const DefaultConnectorRateLimitMaxWait = 2 * time.Second
+
+// ConnectorRateLimit is a token bucket for outbound calls to a single provider.
+type ConnectorRateLimit struct {
+	RequestsPerSecond float64
+	BurstSize         int
+}
+
+// ConnectorRateLimitConfig configures per-provider outbound rate limits.
+// Providers are keyed by name ("twilio", "slack", "discord") or "http:<host>"
+// for the generic HTTP executor; providers without a limit use Default, or
+// are not throttled when it is nil.
+type ConnectorRateLimitConfig struct {
+	Default   *ConnectorRateLimit
+	Providers map[string]ConnectorRateLimit
+	MaxWait   time.Duration
+}
+
+// ConnectorRateLimitSource supplies the current connector rate limits, e.g.
+// from the control plane. It returns nil when none are configured.
+type ConnectorRateLimitSource interface {
+	ConnectorRateLimits(ctx context.Context) (*ConnectorRateLimitConfig, error)
+}
+
+// ConnectorRateLimiter throttles outbound connector calls with a token bucket
+// per provider ("twilio", "slack", "discord", "http:<host>").
+type ConnectorRateLimiter struct {
+	mu       sync.RWMutex
+	limiters map[string]*rate.Limiter
+	config   *ConnectorRateLimitConfig
+	// nextSweep is the bucket count at which idle buckets are next swept, so
+	// one "http:<host>" bucket per distinct host does not accumulate.
+	nextSweep int
+}
+
+var defaultConnectorRateLimiter = NewConnectorRateLimiter(nil)
+
+// DefaultConnectorRateLimiter returns the limiter shared by executors that are
+// not given one explicitly. It does not throttle until configured with
+// ApplyConfig or Refresh.
+func DefaultConnectorRateLimiter() *ConnectorRateLimiter {
+	return defaultConnectorRateLimiter
+}
+
+// NewConnectorRateLimiter creates a limiter from the given configuration.
+func NewConnectorRateLimiter(cfg *ConnectorRateLimitConfig) *ConnectorRateLimiter {
+	l := &ConnectorRateLimiter{}
+	l.ApplyConfig(cfg)
+	return l
+}
+
+// ApplyConfig replaces the limiter configuration. Existing buckets are
+// discarded so new limits take effect immediately. A config without a max
+// wait gets DefaultConnectorRateLimitMaxWait rather than waiting indefinitely
+// for a token.
+func (l *ConnectorRateLimiter) ApplyConfig(cfg *ConnectorRateLimitConfig) {
+	cfg = withDefaultMaxWait(cfg)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.config = cfg
+	l.limiters = make(map[string]*rate.Limiter)
+	l.nextSweep = minConnectorLimiterSweep
+}
+
+func withDefaultMaxWait(cfg *ConnectorRateLimitConfig) *ConnectorRateLimitConfig {
+	if cfg == nil {
+		return &ConnectorRateLimitConfig{MaxWait: DefaultConnectorRateLimitMaxWait}
+	}
+	if cfg.MaxWait > 0 {
+		return cfg
+	}
+	withDefault := *cfg
+	withDefault.MaxWait = DefaultConnectorRateLimitMaxWait
+	return &withDefault
+}
+
+// Refresh reads the limits from source and applies them if they changed. A
+// source without limits keeps the current ones.
+func (l *ConnectorRateLimiter) Refresh(ctx context.Context, source ConnectorRateLimitSource) error {
+	cfg, err := source.ConnectorRateLimits(ctx)
+	if err != nil {
+		return fmt.Errorf("load connector rate limits: %w", err)
+	}
+	if cfg == nil {
+		return nil
+	}
+	next := withDefaultMaxWait(cfg)
+
+	l.mu.RLock()
+	unchanged := reflect.DeepEqual(next, l.config)
+	l.mu.RUnlock()
+	// Unchanged limits keep the providers' buckets.
+	if unchanged {
+		return nil
+	}
+	l.ApplyConfig(next)
+	return nil
+}
+
+// Watch refreshes the limits from source every interval until ctx is done.
+func (l *ConnectorRateLimiter) Watch(ctx context.Context, source ConnectorRateLimitSource, interval time.Duration, logger *slog.Logger) {
+	if interval <= 0 {
+		interval = DefaultConnectorRateLimitRefreshInterval
+	}
+
+	refresh := func() {
+		refreshCtx, cancel := context.WithTimeout(ctx, interval)
+		defer cancel()
+		if err := l.Refresh(refreshCtx, source); err != nil && ctx.Err() == nil {
+			logger.Warn("failed to refresh connector rate limits", slog.String("error", err.Error()))
+		}
+	}
+
+	refresh()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// Acquire blocks until a token for provider is available or the configured
+// max wait elapses. It returns how long the caller waited.
+func (l *ConnectorRateLimiter) Acquire(ctx context.Context, provider string) (time.Duration, error) {
+	start := time.Now()
+
+	limiter, maxWait := l.getOrCreateLimiter(provider)
+	if limiter == nil {
+		return 0, nil
+	}
+
+	reservation := limiter.Reserve()
+	if !reservation.OK() {
+		return 0, fmt.Errorf("%w for %s", ErrConnectorRateLimited, provider)
+	}
+
+	delay := reservation.Delay()
+	if delay == 0 {
+		return 0, nil
+	}
+	if maxWait > 0 && delay > maxWait {
+		reservation.Cancel()
+		return 0, fmt.Errorf("%w for %s: next token in %s", ErrConnectorRateLimited, provider, delay.Round(time.Millisecond))
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return time.Since(start), ctx.Err()
+	}
+}
+
+func (l *ConnectorRateLimiter) getOrCreateLimiter(provider string) (*rate.Limiter, time.Duration) {
+	l.mu.RLock()
+	limiter, ok := l.limiters[provider]
+	maxWait := l.config.MaxWait
+	l.mu.RUnlock()
+
+	if ok {
+		return limiter, maxWait
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limiter, ok = l.limiters[provider]; ok {
+		return limiter, l.config.MaxWait
+	}
+	if len(l.limiters) >= l.nextSweep {
+		l.sweepLocked(time.Now())
+	}
+
+	limit, found := l.config.Providers[provider]
+	if !found {
+		if l.config.Default == nil {
+			// Unconfigured providers are not throttled.
+			l.limiters[provider] = nil
+			return nil, l.config.MaxWait
+		}
+		limit = *l.config.Default
+	}
+
+	burst := limit.BurstSize
+	if burst < 1 {
+		burst = 1
+	}
+	limiter = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	l.limiters[provider] = limiter
+	return limiter, l.config.MaxWait
+}
+
+// sweepLocked drops buckets that are full again, which are equivalent to
+// fresh ones, and the placeholders of unthrottled providers. l.mu must be
+// held for writing.
+func (l *ConnectorRateLimiter) sweepLocked(now time.Time) {
+	for provider, limiter := range l.limiters {
+		if limiter == nil || limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(l.limiters, provider)
+		}
+	}
+	l.nextSweep = max(minConnectorLimiterSweep, 2*len(l.limiters))
+}
+
+// rateLimitedResponse builds the retryable response returned when a connector
+// could not acquire a token in time.
+func rateLimitedResponse(req *ExecuteRequest, connectorKey, operation, provider string, waited time.Duration, err error, logs []LogEntry, start time.Time) *ExecuteResponse {
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "WARN",
+		Message:   fmt.Sprintf("Throttled by %s rate limit: %v", provider, err),
+	})
+
+	attempt := newConnectorAttempt(req, connectorKey, operation, provider, "throttled", start, waited)
+	attempt.ErrorCode = "RATE_LIMITED"
+	attempt.ErrorMessage = err.Error()
+
+	return &ExecuteResponse{
+		Error: &ExecutionError{
+			Message: err.Error(),
+			Type:    ErrorTypeRetryable,
+		},
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}
+}
+
+// newConnectorAttempt records a single outbound connector call, including the
+// time spent waiting on the provider's rate limit.
+func newConnectorAttempt(req *ExecuteRequest, connectorKey, operation, provider, status string, start time.Time, waited time.Duration) ConnectorAttempt {
+	return ConnectorAttempt{
+		NodeID:             req.NodeID,
+		ConnectorKey:       connectorKey,
+		ConnectorOperation: operation,
+		Provider:           provider,
+		AttemptNo:          req.Attempt,
+		IsRetry:            req.Attempt > 1,
+		Status:             status,
+		DurationMS:         time.Since(start).Milliseconds(),
+		HappenedAt:         time.Now().UTC(),
+		Meta:               rateLimitMeta(waited),
+	}
+}
+
+func rateLimitMeta(waited time.Duration) map[string]interface{} {
+	return map[string]interface{}{
+		"rate_limit_wait_ms": waited.Milliseconds(),
+	}
+}
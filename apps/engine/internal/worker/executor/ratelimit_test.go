@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type fakeConnectorLimits struct {
+	config *ConnectorRateLimitConfig
+	err    error
+}
+
+func (f *fakeConnectorLimits) ConnectorRateLimits(context.Context) (*ConnectorRateLimitConfig, error) {
+	return f.config, f.err
+}
+
+func TestConnectorRateLimiterRefresh(t *testing.T) {
+	initial := &ConnectorRateLimitConfig{
+		Providers: map[string]ConnectorRateLimit{"slack": {RequestsPerSecond: 1, BurstSize: 3}},
+		MaxWait:   time.Second,
+	}
+	tests := []struct {
+		name        string
+		source      *fakeConnectorLimits
+		wantErr     bool
+		wantMaxWait time.Duration
+		wantSlack   float64
+	}{
+		{
+			name:        "no limits keeps the current ones",
+			source:      &fakeConnectorLimits{},
+			wantMaxWait: time.Second,
+			wantSlack:   1,
+		},
+		{
+			name: "omitted max wait gets the default",
+			source: &fakeConnectorLimits{config: &ConnectorRateLimitConfig{
+				Providers: map[string]ConnectorRateLimit{"slack": {RequestsPerSecond: 10, BurstSize: 1}},
+			}},
+			wantMaxWait: DefaultConnectorRateLimitMaxWait,
+			wantSlack:   10,
+		},
+		{
+			name: "explicit max wait",
+			source: &fakeConnectorLimits{config: &ConnectorRateLimitConfig{
+				Providers: map[string]ConnectorRateLimit{"slack": {RequestsPerSecond: 4, BurstSize: 1}},
+				MaxWait:   500 * time.Millisecond,
+			}},
+			wantMaxWait: 500 * time.Millisecond,
+			wantSlack:   4,
+		},
+		{
+			name:        "unavailable source keeps the current limits",
+			source:      &fakeConnectorLimits{err: errors.New("unavailable")},
+			wantErr:     true,
+			wantMaxWait: time.Second,
+			wantSlack:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewConnectorRateLimiter(initial)
+			err := l.Refresh(context.Background(), tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Refresh error = %v, want error %v", err, tt.wantErr)
+			}
+			limiter, maxWait := l.getOrCreateLimiter("slack")
+			if maxWait != tt.wantMaxWait {
+				t.Fatalf("max wait = %s, want %s", maxWait, tt.wantMaxWait)
+			}
+			if got := float64(limiter.Limit()); got != tt.wantSlack {
+				t.Fatalf("slack rate = %v, want %v", got, tt.wantSlack)
+			}
+		})
+	}
+}
+
+func TestConnectorRateLimiterRefreshKeepsBucketsWhenUnchanged(t *testing.T) {
+	source := &fakeConnectorLimits{config: &ConnectorRateLimitConfig{
+		Providers: map[string]ConnectorRateLimit{"slack": {RequestsPerSecond: 1, BurstSize: 1}},
+	}}
+	l := NewConnectorRateLimiter(nil)
+	if err := l.Refresh(context.Background(), source); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	before, _ := l.getOrCreateLimiter("slack")
+
+	if err := l.Refresh(context.Background(), source); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if after, _ := l.getOrCreateLimiter("slack"); after != before {
+		t.Fatal("unchanged limits replaced the provider's bucket")
+	}
+}
+
+func TestConnectorRateLimiterEvictsIdleHosts(t *testing.T) {
+	l := NewConnectorRateLimiter(&ConnectorRateLimitConfig{
+		Default: &ConnectorRateLimit{RequestsPerSecond: 1000, BurstSize: 1},
+	})
+
+	// A drained bucket must survive sweeps; a full one is equivalent to a new one.
+	busy, _ := l.getOrCreateLimiter("http:busy.example.com")
+	busy.SetLimit(0)
+	busy.Allow()
+
+	for i := 0; i < 4*minConnectorLimiterSweep; i++ {
+		l.getOrCreateLimiter(fmt.Sprintf("http:host-%d.example.com", i))
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if len(l.limiters) > 2*minConnectorLimiterSweep {
+		t.Fatalf("limiters = %d after %d hosts, want idle hosts evicted", len(l.limiters), 4*minConnectorLimiterSweep)
+	}
+	if l.limiters["http:busy.example.com"] != busy {
+		t.Fatal("drained bucket was evicted")
+	}
+}
@@ -0,0 +1,326 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkflow/engine/internal/security/pii"
+)
+
+// RedactExecutor returns a copy of its input with PII removed, so it can be
+// logged or sent to external services. Fields can be dropped or masked by
+// path, and strings anywhere in the input are scanned for the built-in PII
+// patterns (email, credit_card, ssn) and any custom ones. The input itself
+// is only passed on when keep_original is set.
+type RedactExecutor struct{}
+
+// RedactConfig represents the configuration for a redact node. Paths use dot
+// notation; "*" matches every key of an object or element of an array.
+type RedactConfig struct {
+	Drop       []string `json:"drop"`        // Paths removed from the output
+	MaskFields []string `json:"mask_fields"` // Paths whose whole value is masked
+	// Patterns names the built-in patterns to mask. Omitted means all of
+	// them; an empty list means none.
+	Patterns       []string              `json:"patterns"`
+	CustomPatterns []RedactCustomPattern `json:"custom_patterns"`
+	Strategy       string                `json:"strategy"`      // full (default), last4 or hash
+	KeepOriginal   bool                  `json:"keep_original"` // Include the unredacted input as "original"
+}
+
+// RedactCustomPattern is a regular expression whose matches are masked.
+type RedactCustomPattern struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"`
+}
+
+// RedactResponse is the output of a redact node.
+type RedactResponse struct {
+	Data json.RawMessage `json:"data"`
+	// Redactions is the number of values dropped or masked, and Paths
+	// where they were, sorted.
+	Redactions int             `json:"redactions"`
+	Paths      []string        `json:"paths"`
+	Original   json.RawMessage `json:"original,omitempty"`
+}
+
+var redactInputSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "drop": {"type": "array", "items": {"type": "string"}, "description": "Field paths (dot notation, * wildcard) removed from the output"},
+    "mask_fields": {"type": "array", "items": {"type": "string"}, "description": "Field paths whose whole value is masked"},
+    "patterns": {"type": "array", "items": {"type": "string", "enum": ["credit_card", "email", "ssn"]}, "description": "Built-in PII patterns to mask; all when omitted"},
+    "custom_patterns": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["regex"],
+        "properties": {"name": {"type": "string"}, "regex": {"type": "string"}}
+      }
+    },
+    "strategy": {"type": "string", "enum": ["full", "last4", "hash"], "default": "full"},
+    "keep_original": {"type": "boolean", "default": false}
+  }
+}`)
+
+var redactOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["data", "redactions", "paths"],
+  "properties": {
+    "data": {"description": "The input with PII removed"},
+    "redactions": {"type": "integer"},
+    "paths": {"type": "array", "items": {"type": "string"}},
+    "original": {"description": "The unredacted input, with keep_original"}
+  }
+}`)
+
+// NewRedactExecutor creates a new redact executor.
+func NewRedactExecutor() *RedactExecutor {
+	return &RedactExecutor{}
+}
+
+func (e *RedactExecutor) NodeType() string {
+	return "redact"
+}
+
+func (e *RedactExecutor) InputSchema() json.RawMessage {
+	return redactInputSchema
+}
+
+func (e *RedactExecutor) OutputSchema() json.RawMessage {
+	return redactOutputSchema
+}
+
+func (e *RedactExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+
+	failed := func(message string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: ErrorTypeNonRetryable},
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config RedactConfig
+	if len(req.Config) > 0 {
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			return failed(fmt.Sprintf("failed to parse redact config: %v", err))
+		}
+	}
+
+	r := &redactor{strategy: pii.StrategyFull, paths: make(map[string]struct{})}
+	if config.Strategy != "" {
+		strategy, ok := pii.ParseStrategy(config.Strategy)
+		if !ok {
+			return failed(fmt.Sprintf("unknown strategy: %s", config.Strategy))
+		}
+		r.strategy = strategy
+	}
+	if config.Patterns == nil {
+		r.patterns = pii.Builtins()
+	}
+	for _, name := range config.Patterns {
+		p, ok := pii.Builtin(name)
+		if !ok {
+			return failed(fmt.Sprintf("unknown pattern %q; built-in patterns are %s", name, strings.Join(pii.BuiltinNames(), ", ")))
+		}
+		r.patterns = append(r.patterns, p)
+	}
+	for i, custom := range config.CustomPatterns {
+		re, err := regexp.Compile(custom.Regex)
+		if err != nil {
+			return failed(fmt.Sprintf("custom_patterns[%d]: invalid regex: %v", i, err))
+		}
+		name := custom.Name
+		if name == "" {
+			name = fmt.Sprintf("custom_%d", i)
+		}
+		r.patterns = append(r.patterns, pii.Pattern{Name: name, Regexp: re})
+	}
+
+	// The input is decoded into a fresh value, so redacting never touches
+	// req.Input. Numbers keep their original text.
+	var data interface{}
+	if len(req.Input) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(req.Input))
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return failed(fmt.Sprintf("failed to parse input: %v", err))
+		}
+	}
+
+	for _, path := range config.Drop {
+		if path == "" {
+			return failed("drop paths must not be empty")
+		}
+		data = r.apply(data, strings.Split(path, "."), "", r.drop)
+	}
+	for _, path := range config.MaskFields {
+		if path == "" {
+			return failed("mask_fields paths must not be empty")
+		}
+		data = r.apply(data, strings.Split(path, "."), "", r.maskValue)
+	}
+	if len(r.patterns) > 0 {
+		data = r.scan(data, "")
+	}
+
+	redacted, err := json.Marshal(data)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal redacted data: %v", err))
+	}
+	resp := RedactResponse{Data: redacted, Redactions: r.count, Paths: r.sortedPaths()}
+	if config.KeepOriginal && len(req.Input) > 0 {
+		resp.Original = req.Input
+	}
+	output, err := json.Marshal(resp)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err))
+	}
+
+	return &ExecuteResponse{
+		Output: output,
+		Logs: []LogEntry{{
+			Timestamp: time.Now(),
+			Level:     "INFO",
+			Message:   fmt.Sprintf("Redacted %d value(s) at %d path(s)", r.count, len(resp.Paths)),
+		}},
+		Duration: time.Since(start),
+	}, nil
+}
+
+// redactor holds the state of one redaction pass.
+type redactor struct {
+	strategy pii.Strategy
+	patterns []pii.Pattern
+	count    int
+	paths    map[string]struct{}
+}
+
+// redactDropped marks a value the drop action removes.
+type redactDropped struct{}
+
+func (r *redactor) record(path string, n int) {
+	r.count += n
+	r.paths[path] = struct{}{}
+}
+
+func (r *redactor) sortedPaths() []string {
+	paths := make([]string, 0, len(r.paths))
+	for p := range r.paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (r *redactor) drop(_ interface{}, path string) interface{} {
+	r.record(path, 1)
+	return redactDropped{}
+}
+
+func (r *redactor) maskValue(value interface{}, path string) interface{} {
+	if value == nil {
+		return nil
+	}
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case json.Number:
+		text = v.String()
+	default:
+		// Objects, arrays and booleans have no text worth keeping a part of.
+		text = ""
+	}
+	r.record(path, 1)
+	return pii.Mask(text, r.strategy)
+}
+
+// apply calls action on the values at parts below value, replacing each
+// with the result. Missing paths are ignored.
+func (r *redactor) apply(value interface{}, parts []string, path string, action func(interface{}, string) interface{}) interface{} {
+	if len(parts) == 0 {
+		return action(value, path)
+	}
+	part, rest := parts[0], parts[1:]
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if part != "*" && part != key {
+				continue
+			}
+			if result := r.apply(child, rest, joinRedactPath(path, key), action); result == (redactDropped{}) {
+				delete(v, key)
+			} else {
+				v[key] = result
+			}
+		}
+		return v
+	case []interface{}:
+		kept := v[:0]
+		for i, child := range v {
+			if part == "*" || part == strconv.Itoa(i) {
+				child = r.apply(child, rest, joinRedactPath(path, strconv.Itoa(i)), action)
+			}
+			if child != (redactDropped{}) {
+				kept = append(kept, child)
+			}
+		}
+		return kept
+	}
+	return value
+}
+
+// scan masks pattern matches in every string and number below value.
+func (r *redactor) scan(value interface{}, path string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = r.scan(child, joinRedactPath(path, key))
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = r.scan(child, joinRedactPath(path, strconv.Itoa(i)))
+		}
+		return v
+	case string:
+		if masked, n := r.maskText(v); n > 0 {
+			r.record(path, n)
+			return masked
+		}
+	case json.Number:
+		// A card number or SSN stored as a number is replaced by its
+		// masked text.
+		if masked, n := r.maskText(v.String()); n > 0 {
+			r.record(path, n)
+			return masked
+		}
+	}
+	return value
+}
+
+func (r *redactor) maskText(s string) (string, int) {
+	total := 0
+	for _, p := range r.patterns {
+		var n int
+		s, n = p.Replace(s, func(match string) string { return pii.Mask(match, r.strategy) })
+		total += n
+	}
+	return s, total
+}
+
+func joinRedactPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func runRedact(t *testing.T, config, input string) (*ExecuteResponse, RedactResponse) {
+	t.Helper()
+	resp, err := NewRedactExecutor().Execute(context.Background(), &ExecuteRequest{
+		NodeType: "redact",
+		Config:   json.RawMessage(config),
+		Input:    json.RawMessage(input),
+	})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	var out RedactResponse
+	if resp.Error == nil {
+		if err := json.Unmarshal(resp.Output, &out); err != nil {
+			t.Fatalf("unmarshal output: %v", err)
+		}
+	}
+	return resp, out
+}
+
+func TestRedactExecutorMasksNestedPII(t *testing.T) {
+	input := `{
+		"customer": {"name": "Jane", "email": "jane@example.com", "ssn": "123-45-6789"},
+		"payments": [
+			{"card": "4111 1111 1111 1111", "amount": 10.50},
+			{"card": 5500000000000004, "amount": 3}
+		],
+		"note": "reach me at jane@example.com or john@example.org",
+		"order": "1234567890123"
+	}`
+	resp, out := runRedact(t, `{}`, input)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(out.Data, &data); err != nil {
+		t.Fatalf("unmarshal data: %v", err)
+	}
+	want := map[string]interface{}{
+		"customer": map[string]interface{}{"name": "Jane", "email": "****", "ssn": "****"},
+		"payments": []interface{}{
+			map[string]interface{}{"card": "****", "amount": 10.5},
+			map[string]interface{}{"card": "****", "amount": float64(3)},
+		},
+		"note":  "reach me at **** or ****",
+		"order": "1234567890123",
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Fatalf("data = %#v\nwant %#v", data, want)
+	}
+	if out.Redactions != 6 {
+		t.Errorf("redactions = %d, want 6", out.Redactions)
+	}
+	wantPaths := []string{"customer.email", "customer.ssn", "note", "payments.0.card", "payments.1.card"}
+	if !reflect.DeepEqual(out.Paths, wantPaths) {
+		t.Errorf("paths = %v, want %v", out.Paths, wantPaths)
+	}
+	if out.Original != nil {
+		t.Errorf("original included without keep_original: %s", out.Original)
+	}
+}
+
+func TestRedactExecutorDropsAndMasksFields(t *testing.T) {
+	config := `{
+		"drop": ["users.*.password", "debug"],
+		"mask_fields": ["users.*.phone"],
+		"patterns": ["credit_card"],
+		"strategy": "last4",
+		"keep_original": true
+	}`
+	input := `{"users":[{"email":"a@example.com","password":"hunter2","phone":"555-123-4567","card":"4111-1111-1111-1111"}],"debug":{"token":"x"}}`
+	resp, out := runRedact(t, config, input)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+
+	want := `{"users":[{"card":"****1111","email":"a@example.com","phone":"****4567"}]}`
+	if string(out.Data) != want {
+		t.Fatalf("data = %s, want %s", out.Data, want)
+	}
+	if string(out.Original) != input {
+		t.Errorf("original = %s, want the untouched input", out.Original)
+	}
+	wantPaths := []string{"debug", "users.0.card", "users.0.password", "users.0.phone"}
+	if !reflect.DeepEqual(out.Paths, wantPaths) {
+		t.Errorf("paths = %v, want %v", out.Paths, wantPaths)
+	}
+}
+
+func TestRedactExecutorCustomPatterns(t *testing.T) {
+	config := `{"patterns": [], "custom_patterns": [{"name": "api_key", "regex": "sk_live_[A-Za-z0-9]+"}], "strategy": "hash"}`
+	_, out := runRedact(t, config, `["key sk_live_abc123", "jane@example.com"]`)
+
+	var data []string
+	if err := json.Unmarshal(out.Data, &data); err != nil {
+		t.Fatalf("unmarshal data: %v", err)
+	}
+	if data[0] == "key sk_live_abc123" || data[0][:11] != "key sha256:" {
+		t.Errorf("custom pattern not hashed: %q", data[0])
+	}
+	if data[1] != "jane@example.com" {
+		t.Errorf("built-in pattern applied with patterns: []: %q", data[1])
+	}
+}
+
+func TestRedactExecutorRejectsBadConfig(t *testing.T) {
+	for _, config := range []string{
+		`{"custom_patterns": [{"regex": "("}]}`,
+		`{"patterns": ["passport"]}`,
+		`{"strategy": "first4"}`,
+		`{"drop": [""]}`,
+	} {
+		resp, _ := runRedact(t, config, `{}`)
+		if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+			t.Errorf("config %s: expected non-retryable error, got %+v", config, resp.Error)
+		}
+	}
+}
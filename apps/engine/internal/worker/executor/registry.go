@@ -3,14 +3,20 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
 
+// ErrDuplicateNodeType is returned when a node type or alias is registered twice.
+var ErrDuplicateNodeType = errors.New("duplicate node type registration")
+
 // Registry manages all available node executors.
 type Registry struct {
 	executors map[string]Executor
+	aliases   map[string]string // alias -> canonical node type
 	mu        sync.RWMutex
 }
 
@@ -18,34 +24,58 @@ type Registry struct {
 func NewRegistry() *Registry {
 	return &Registry{
 		executors: make(map[string]Executor),
+		aliases:   make(map[string]string),
 	}
 }
 
-// Register registers an executor for a node type.
+// Register registers an executor for its node type and any aliases it
+// declares through AliasProvider. Nothing is registered if any name is taken.
 func (r *Registry) Register(executor Executor) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	nodeType := executor.NodeType()
-	if _, exists := r.executors[nodeType]; exists {
-		return fmt.Errorf("executor for node type '%s' is already registered", nodeType)
+	names := []string{nodeType}
+	if p, ok := executor.(AliasProvider); ok {
+		names = append(names, p.Aliases()...)
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return fmt.Errorf("%w: %T declares node type '%s' more than once", ErrDuplicateNodeType, executor, name)
+		}
+		seen[name] = true
+
+		if existing, exists := r.executors[name]; exists {
+			return fmt.Errorf("%w: node type '%s' for %T is already registered by %T", ErrDuplicateNodeType, name, executor, existing)
+		}
+		if canonical, exists := r.aliases[name]; exists {
+			return fmt.Errorf("%w: node type '%s' for %T is already registered as an alias of '%s'", ErrDuplicateNodeType, name, executor, canonical)
+		}
 	}
 
 	r.executors[nodeType] = executor
+	for _, alias := range names[1:] {
+		r.aliases[alias] = nodeType
+	}
 	return nil
 }
 
 // MustRegister registers an executor, panicking on error.
 func (r *Registry) MustRegister(executor Executor) {
 	if err := r.Register(executor); err != nil {
-		panic(err)
+		panic(fmt.Sprintf("executor registry: %v", err))
 	}
 }
 
-// Get retrieves an executor by node type.
+// Get retrieves an executor by node type or alias.
 func (r *Registry) Get(nodeType string) (Executor, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	if canonical, ok := r.aliases[nodeType]; ok {
+		nodeType = canonical
+	}
 	executor, exists := r.executors[nodeType]
 	return executor, exists
 }
@@ -65,18 +95,78 @@ func (r *Registry) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteRe
 	return executor.Execute(ctx, req)
 }
 
-// NodeTypes returns all registered node types.
-func (r *Registry) NodeTypes() []string {
+// List returns every node type the registry answers, including aliases,
+// sorted by name.
+func (r *Registry) List() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	types := make([]string, 0, len(r.executors))
+	types := make([]string, 0, len(r.executors)+len(r.aliases))
 	for nodeType := range r.executors {
 		types = append(types, nodeType)
 	}
+	for alias := range r.aliases {
+		types = append(types, alias)
+	}
+	sort.Strings(types)
 	return types
 }
 
+// NodeTypes returns all registered node types, including aliases.
+func (r *Registry) NodeTypes() []string {
+	return r.List()
+}
+
+// Aliases returns a copy of the alias to canonical node type mapping.
+func (r *Registry) Aliases() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aliases := make(map[string]string, len(r.aliases))
+	for alias, nodeType := range r.aliases {
+		aliases[alias] = nodeType
+	}
+	return aliases
+}
+
+// NodeTypeInfo describes a registered node type and its declared schemas.
+type NodeTypeInfo struct {
+	NodeType     string          `json:"node_type"`
+	AliasOf      string          `json:"alias_of,omitempty"`
+	InputSchema  json.RawMessage `json:"input_schema,omitempty"`
+	OutputSchema json.RawMessage `json:"output_schema,omitempty"`
+}
+
+// Describe returns every registered node type and alias with its input and
+// output schemas, sorted by node type.
+func (r *Registry) Describe() []NodeTypeInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]NodeTypeInfo, 0, len(r.executors)+len(r.aliases))
+	for nodeType, executor := range r.executors {
+		infos = append(infos, describeExecutor(nodeType, executor))
+	}
+	for alias, nodeType := range r.aliases {
+		info := describeExecutor(alias, r.executors[nodeType])
+		info.AliasOf = nodeType
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].NodeType < infos[j].NodeType })
+	return infos
+}
+
+func describeExecutor(nodeType string, executor Executor) NodeTypeInfo {
+	info := NodeTypeInfo{
+		NodeType:     nodeType,
+		OutputSchema: executor.OutputSchema(),
+	}
+	if p, ok := executor.(InputSchemaProvider); ok {
+		info.InputSchema = p.InputSchema()
+	}
+	return info
+}
+
 // Count returns the number of registered executors.
 func (r *Registry) Count() int {
 	r.mu.RLock()
@@ -99,19 +189,24 @@ func DefaultRegistryInit() *Registry {
 	registry.MustRegister(NewSlackExecutor())
 	registry.MustRegister(NewDelayExecutor())
 	registry.MustRegister(NewDatabaseExecutor())
+	registry.MustRegister(NewAMQPExecutor())
+	registry.MustRegister(NewDedupeExecutor())
 	registry.MustRegister(NewAIExecutor())
 	registry.MustRegister(NewWebhookExecutor())
 	registry.MustRegister(NewTransformExecutor())
 	registry.MustRegister(NewLoopExecutor())
 	registry.MustRegister(NewDiscordExecutor())
 	registry.MustRegister(NewTwilioExecutor())
+	registry.MustRegister(NewTeamsExecutor())
+	registry.MustRegister(NewSendGridExecutor())
+	registry.MustRegister(NewMailgunExecutor())
 	registry.MustRegister(NewStorageExecutor())
 	registry.MustRegister(NewScriptExecutor())
 	registry.MustRegister(NewOutputExecutor())
 	registry.MustRegister(NewApprovalExecutor())
-	registry.MustRegister(NewLogicConditionExecutor())
 	registry.MustRegister(NewManualExecutor())
-	registry.MustRegister(NewAliasExecutor("trigger_schedule", NewManualExecutor()))
+	registry.MustRegister(NewSchemaValidateExecutor())
+	registry.MustRegister(NewAsyncCallbackExecutor())
 
 	return registry
 }
@@ -146,6 +241,32 @@ func (e *TransformExecutor) NodeType() string {
 	return "transform"
 }
 
+var transformInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["operation"],
+  "properties": {
+    "operation": {"type": "string", "enum": ["set", "rename", "delete", "filter"]},
+    "field": {"type": "string"},
+    "value": {},
+    "from_field": {"type": "string"},
+    "to_field": {"type": "string"}
+  }
+}`)
+
+// The transform node returns its input object with the operation applied.
+var transformOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "additionalProperties": true
+}`)
+
+func (e *TransformExecutor) InputSchema() json.RawMessage {
+	return transformInputSchema
+}
+
+func (e *TransformExecutor) OutputSchema() json.RawMessage {
+	return transformOutputSchema
+}
+
 func (e *TransformExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
 	start := time.Now()
 	logs := make([]LogEntry, 0)
@@ -273,7 +394,9 @@ func (e *TransformExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*
 }
 
 // LoopExecutor handles loop/iteration nodes.
-type LoopExecutor struct{}
+type LoopExecutor struct {
+	BaseExecutor
+}
 
 // LoopConfig represents the configuration for a loop node.
 type LoopConfig struct {
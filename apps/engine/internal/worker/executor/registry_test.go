@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// renamedExecutor registers an executor under another node type.
+type renamedExecutor struct {
+	Executor
+	nodeType string
+}
+
+func (e renamedExecutor) NodeType() string {
+	return e.nodeType
+}
+
+func TestRegistryDescribeIncludesSchemas(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	registry.MustRegister(NewHTTPExecutor())
+	registry.MustRegister(NewConditionExecutor())
+	registry.MustRegister(NewTransformExecutor())
+	registry.MustRegister(NewDelayExecutor())
+
+	infos := registry.Describe()
+	if len(infos) != 5 {
+		t.Fatalf("expected 5 node types, got %d", len(infos))
+	}
+
+	byType := make(map[string]NodeTypeInfo, len(infos))
+	for i, info := range infos {
+		if i > 0 && infos[i-1].NodeType >= info.NodeType {
+			t.Fatalf("expected node types sorted, got %q before %q", infos[i-1].NodeType, info.NodeType)
+		}
+		byType[info.NodeType] = info
+	}
+
+	for _, nodeType := range []string{"action_http_request", "condition", "transform", "logic_condition"} {
+		info := byType[nodeType]
+		if !json.Valid(info.InputSchema) || !json.Valid(info.OutputSchema) {
+			t.Fatalf("expected valid input and output schemas for %s", nodeType)
+		}
+	}
+
+	if alias := byType["logic_condition"].AliasOf; alias != "condition" {
+		t.Fatalf("expected logic_condition to be an alias of condition, got %q", alias)
+	}
+
+	if delay := byType["delay"]; delay.InputSchema != nil || delay.OutputSchema != nil {
+		t.Fatalf("expected no schemas for delay, got %+v", delay)
+	}
+}
+
+func TestRegistryAliasesShareInstanceAndRejectDuplicates(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	condition := NewConditionExecutor()
+	registry.MustRegister(condition)
+
+	got, ok := registry.Get("logic_condition")
+	if !ok || got != condition {
+		t.Fatalf("expected logic_condition to resolve to the condition instance, got %v", got)
+	}
+
+	if list := registry.List(); len(list) != 2 || list[0] != "condition" || list[1] != "logic_condition" {
+		t.Fatalf("unexpected node type list: %v", list)
+	}
+
+	err := registry.Register(renamedExecutor{Executor: NewTransformExecutor(), nodeType: "logic_condition"})
+	if !errors.Is(err, ErrDuplicateNodeType) {
+		t.Fatalf("expected ErrDuplicateNodeType for alias clash, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected MustRegister to panic on duplicate node type")
+		}
+	}()
+	registry.MustRegister(NewConditionExecutor())
+}
@@ -0,0 +1,255 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	renderFormatHTML = "html"
+	renderFormatPDF  = "pdf"
+
+	renderContentTypeHTML = "text/html; charset=utf-8"
+	renderContentTypePDF  = "application/pdf"
+)
+
+// pdfBinaryCandidates are the PDF backends FindPDFBinary looks for on PATH,
+// in order of preference.
+var pdfBinaryCandidates = []string{"wkhtmltopdf", "chromium", "chromium-browser", "google-chrome", "google-chrome-stable"}
+
+// RenderExecutor renders an HTML template against the node input to produce a
+// document such as an invoice or report. HTML is rendered in-process; PDF is
+// converted from the HTML by wkhtmltopdf or headless Chrome.
+type RenderExecutor struct {
+	BaseExecutor
+
+	pdfBinary string
+}
+
+// RenderConfig represents the configuration for a render_document node.
+type RenderConfig struct {
+	Template string `json:"template"` // html/template source; the node input is the template data
+	Format   string `json:"format"`   // "html" (default) or "pdf"
+	Filename string `json:"filename"` // Optional file name passed through to the output
+}
+
+// RenderResponse is the output of a render_document node.
+type RenderResponse struct {
+	Content     string `json:"content"` // Base64 encoded document
+	ContentType string `json:"content_type"`
+	Format      string `json:"format"`
+	Size        int    `json:"size"`
+	Filename    string `json:"filename,omitempty"`
+}
+
+var renderInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["template"],
+  "properties": {
+    "template": {"type": "string", "description": "HTML template rendered with the node input as data"},
+    "format": {"type": "string", "enum": ["html", "pdf"], "default": "html"},
+    "filename": {"type": "string"}
+  }
+}`)
+
+var renderOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["content", "content_type", "format", "size"],
+  "properties": {
+    "content": {"type": "string", "contentEncoding": "base64"},
+    "content_type": {"type": "string"},
+    "format": {"type": "string", "enum": ["html", "pdf"]},
+    "size": {"type": "integer", "description": "Document size in bytes"},
+    "filename": {"type": "string"}
+  }
+}`)
+
+// NewRenderExecutor creates a new render executor. It renders HTML only until
+// a PDF backend is set with WithPDFBinary.
+func NewRenderExecutor() *RenderExecutor {
+	return &RenderExecutor{}
+}
+
+// WithPDFBinary sets the wkhtmltopdf or headless Chrome binary used to
+// convert rendered HTML to PDF.
+func (e *RenderExecutor) WithPDFBinary(path string) *RenderExecutor {
+	e.pdfBinary = path
+	return e
+}
+
+// FindPDFBinary resolves the PDF backend for render_document nodes. A
+// configured path must exist; otherwise the first known backend on PATH is
+// used. It returns "" and no error when none is installed.
+func FindPDFBinary(configured string) (string, error) {
+	if configured != "" {
+		path, err := exec.LookPath(configured)
+		if err != nil {
+			return "", fmt.Errorf("PDF renderer %q not found: %w", configured, err)
+		}
+		return path, nil
+	}
+	for _, name := range pdfBinaryCandidates {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+func (e *RenderExecutor) NodeType() string {
+	return "render_document"
+}
+
+func (e *RenderExecutor) InputSchema() json.RawMessage {
+	return renderInputSchema
+}
+
+func (e *RenderExecutor) OutputSchema() json.RawMessage {
+	return renderOutputSchema
+}
+
+func (e *RenderExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: errorType},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config RenderConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse render config: %v", err), ErrorTypeNonRetryable)
+	}
+	if config.Template == "" {
+		return failed("template is required", ErrorTypeNonRetryable)
+	}
+	format := strings.ToLower(config.Format)
+	if format == "" {
+		format = renderFormatHTML
+	}
+	if format != renderFormatHTML && format != renderFormatPDF {
+		return failed(fmt.Sprintf("unsupported format %q; use html or pdf", config.Format), ErrorTypeNonRetryable)
+	}
+	// Fail before rendering so a missing backend is reported as such, not
+	// papered over with an HTML document.
+	if format == renderFormatPDF && e.pdfBinary == "" {
+		return failed("pdf rendering requires wkhtmltopdf or headless Chrome; install one or set RENDER_PDF_BINARY on the worker", ErrorTypeNonRetryable)
+	}
+
+	var data interface{}
+	if len(req.Input) > 0 {
+		if err := json.Unmarshal(req.Input, &data); err != nil {
+			return failed(fmt.Sprintf("failed to parse input: %v", err), ErrorTypeNonRetryable)
+		}
+	}
+
+	tmpl, err := template.New(req.NodeID).Option("missingkey=zero").Parse(config.Template)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to parse template: %v", err), ErrorTypeNonRetryable)
+	}
+	var html bytes.Buffer
+	if err := tmpl.Execute(&html, data); err != nil {
+		return failed(fmt.Sprintf("failed to render template: %v", err), ErrorTypeNonRetryable)
+	}
+
+	document := html.Bytes()
+	contentType := renderContentTypeHTML
+	if format == renderFormatPDF {
+		document, err = e.convertToPDF(ctx, document)
+		if err != nil {
+			errorType := ErrorTypeRetryable
+			if ctx.Err() != nil {
+				errorType = ErrorTypeTimeout
+			}
+			return failed(fmt.Sprintf("failed to convert document to pdf: %v", err), errorType)
+		}
+		contentType = renderContentTypePDF
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Rendered %s document (%d bytes)", format, len(document)),
+	})
+
+	output, err := json.Marshal(RenderResponse{
+		Content:     base64.StdEncoding.EncodeToString(document),
+		ContentType: contentType,
+		Format:      format,
+		Size:        len(document),
+		Filename:    config.Filename,
+	})
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err), ErrorTypeNonRetryable)
+	}
+
+	return &ExecuteResponse{
+		Output:   output,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// convertToPDF runs the configured backend over the rendered HTML.
+// wkhtmltopdf streams through stdin and stdout; Chrome needs the page and the
+// PDF on disk.
+func (e *RenderExecutor) convertToPDF(ctx context.Context, html []byte) ([]byte, error) {
+	if strings.Contains(filepath.Base(e.pdfBinary), "wkhtmltopdf") {
+		cmd := exec.CommandContext(ctx, e.pdfBinary, "--quiet", "-", "-")
+		cmd.Stdin = bytes.NewReader(html)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, commandError(err, stderr.String())
+		}
+		return stdout.Bytes(), nil
+	}
+
+	dir, err := os.MkdirTemp("", "render-document-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	page := filepath.Join(dir, "document.html")
+	pdf := filepath.Join(dir, "document.pdf")
+	if err := os.WriteFile(page, html, 0o600); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, e.pdfBinary,
+		"--headless",
+		"--disable-gpu",
+		"--no-sandbox",
+		"--no-pdf-header-footer",
+		"--print-to-pdf="+pdf,
+		"file://"+page,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, commandError(err, stderr.String())
+	}
+	return os.ReadFile(pdf)
+}
+
+func commandError(err error, stderr string) error {
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		return fmt.Errorf("%w: %s", err, stderr)
+	}
+	return err
+}
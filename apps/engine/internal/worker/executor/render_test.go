@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderExecutorRendersHTML(t *testing.T) {
+	resp, err := NewRenderExecutor().Execute(context.Background(), &ExecuteRequest{
+		NodeID: "invoice",
+		Config: json.RawMessage(`{"template": "<h1>Invoice {{.number}}</h1><p>{{.customer.name}}</p>", "filename": "invoice.html"}`),
+		Input:  json.RawMessage(`{"number": 42, "customer": {"name": "<Acme & Co>"}}`),
+	})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("Execute error: %v %+v", err, resp.Error)
+	}
+
+	var out RenderResponse
+	if err := json.Unmarshal(resp.Output, &out); err != nil {
+		t.Fatal(err)
+	}
+	document, err := base64.StdEncoding.DecodeString(out.Content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<h1>Invoice 42</h1><p>&lt;Acme &amp; Co&gt;</p>"
+	if string(document) != want || out.ContentType != "text/html; charset=utf-8" || out.Size != len(want) {
+		t.Fatalf("unexpected output %+v with document %q", out, document)
+	}
+}
+
+func TestRenderExecutorPDF(t *testing.T) {
+	req := &ExecuteRequest{
+		NodeID: "report",
+		Config: json.RawMessage(`{"template": "<p>{{.total}}</p>", "format": "pdf"}`),
+		Input:  json.RawMessage(`{"total": 10}`),
+	}
+
+	resp, err := NewRenderExecutor().Execute(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable || !strings.Contains(resp.Error.Message, "RENDER_PDF_BINARY") {
+		t.Fatalf("expected non-retryable error without a PDF backend, got %+v", resp.Error)
+	}
+
+	// A stand-in for wkhtmltopdf that wraps the HTML it reads from stdin.
+	backend := filepath.Join(t.TempDir(), "wkhtmltopdf")
+	script := "#!/bin/sh\nprintf '%%PDF-1.4 '\ncat\n"
+	if err := os.WriteFile(backend, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = NewRenderExecutor().WithPDFBinary(backend).Execute(context.Background(), req)
+	if err != nil || resp.Error != nil {
+		t.Fatalf("Execute error: %v %+v", err, resp.Error)
+	}
+	var out RenderResponse
+	if err := json.Unmarshal(resp.Output, &out); err != nil {
+		t.Fatal(err)
+	}
+	document, _ := base64.StdEncoding.DecodeString(out.Content)
+	if string(document) != "%PDF-1.4 <p>10</p>" || out.ContentType != "application/pdf" {
+		t.Fatalf("unexpected output %+v with document %q", out, document)
+	}
+}
@@ -0,0 +1,247 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRetryClassifier is returned for a retry_classifier that cannot be
+// applied. It fails the node without running it, since every attempt would
+// fail the same way.
+var ErrInvalidRetryClassifier = errors.New("invalid retry_classifier")
+
+// RetryClassifierConsumer is implemented by executors that apply the node's
+// retry_classifier themselves. The worker applies it to the results of other
+// connector executors when UsesRetryClassifier returns false.
+type RetryClassifierConsumer interface {
+	UsesRetryClassifier() bool
+}
+
+// RetryClassifier overrides how a node's result is classified, for APIs whose
+// failures the executor's defaults get wrong: a 400 that is really a rate
+// limit, or a 200 whose body reports an error. Rules are tried in order and
+// the first one that matches decides the error type.
+type RetryClassifier struct {
+	Rules []RetryClassifierRule `json:"rules"`
+}
+
+// RetryClassifierRule matches a result on every condition it sets; a rule
+// must set at least one.
+type RetryClassifierRule struct {
+	// StatusCodes matches the response status: exact codes (429), ranges
+	// ("400-499") or classes ("5xx").
+	StatusCodes []RetryStatusRange `json:"status_codes,omitempty"`
+
+	// BodyPath selects a value in the JSON response body, as "$.error.code"
+	// or "items[0].status". Without BodyEquals the rule matches if the value
+	// is present and not null.
+	BodyPath   string          `json:"body_path,omitempty"`
+	BodyEquals json.RawMessage `json:"body_equals,omitempty"`
+
+	// ErrorContains matches a substring of the executor's error message.
+	ErrorContains string `json:"error_contains,omitempty"`
+
+	ErrorType string `json:"error_type"`        // RETRYABLE, NON_RETRYABLE or TIMEOUT
+	Message   string `json:"message,omitempty"` // Error message when the rule fails a successful result
+}
+
+// RetryStatusRange is an inclusive range of HTTP status codes.
+type RetryStatusRange struct {
+	Min int
+	Max int
+}
+
+var retryStatusClass = regexp.MustCompile(`^([1-5])xx$`)
+
+func (r *RetryStatusRange) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err == nil {
+		r.Min, r.Max = code, code
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("status code must be a number or string: %s", data)
+	}
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if match := retryStatusClass.FindStringSubmatch(raw); match != nil {
+		class, _ := strconv.Atoi(match[1])
+		r.Min, r.Max = class*100, class*100+99
+		return nil
+	}
+	lo, hi, isRange := strings.Cut(raw, "-")
+	min, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return fmt.Errorf("invalid status code %q", raw)
+	}
+	max := min
+	if isRange {
+		if max, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil || max < min {
+			return fmt.Errorf("invalid status code range %q", raw)
+		}
+	}
+	r.Min, r.Max = min, max
+	return nil
+}
+
+func (r RetryStatusRange) MarshalJSON() ([]byte, error) {
+	if r.Min == r.Max {
+		return json.Marshal(r.Min)
+	}
+	return json.Marshal(fmt.Sprintf("%d-%d", r.Min, r.Max))
+}
+
+func (r RetryStatusRange) contains(code int) bool {
+	return code >= r.Min && code <= r.Max
+}
+
+// ParseRetryClassifier extracts the retry classifier from a node config, or
+// nil if none is set. Errors wrap ErrInvalidRetryClassifier.
+func ParseRetryClassifier(config json.RawMessage) (*RetryClassifier, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(config, &fields); err != nil || len(fields["retry_classifier"]) == 0 {
+		return nil, nil
+	}
+	classifier := &RetryClassifier{}
+	if err := json.Unmarshal(fields["retry_classifier"], classifier); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRetryClassifier, err)
+	}
+	if len(classifier.Rules) == 0 {
+		return nil, nil
+	}
+	for i := range classifier.Rules {
+		rule := &classifier.Rules[i]
+		rule.ErrorType = strings.ToUpper(rule.ErrorType)
+		switch rule.ErrorType {
+		case ErrorTypeRetryable, ErrorTypeNonRetryable, ErrorTypeTimeout:
+		default:
+			return nil, fmt.Errorf("%w: rule %d: error_type must be RETRYABLE, NON_RETRYABLE or TIMEOUT", ErrInvalidRetryClassifier, i)
+		}
+		if len(rule.StatusCodes) == 0 && rule.BodyPath == "" && rule.ErrorContains == "" {
+			return nil, fmt.Errorf("%w: rule %d matches nothing: set status_codes, body_path or error_contains", ErrInvalidRetryClassifier, i)
+		}
+		if len(rule.BodyEquals) > 0 && rule.BodyPath == "" {
+			return nil, fmt.Errorf("%w: rule %d: body_equals requires body_path", ErrInvalidRetryClassifier, i)
+		}
+	}
+	return classifier, nil
+}
+
+// Apply classifies the result of a connector executor by the status code of
+// its last connector attempt and its output. It does nothing for a nil
+// classifier or a result with no connector attempts.
+func (c *RetryClassifier) Apply(resp *ExecuteResponse) {
+	if c == nil || resp == nil || len(resp.ConnectorAttempts) == 0 {
+		return
+	}
+	statusCode := int(resp.ConnectorAttempts[len(resp.ConnectorAttempts)-1].StatusCode)
+	c.apply(resp, statusCode, resp.Output)
+}
+
+// applyHTTP classifies the result of an HTTP request by the response status
+// and body, which the output carries when a response was received.
+func (c *RetryClassifier) applyHTTP(resp *ExecuteResponse) {
+	if c == nil || resp == nil {
+		return
+	}
+	var httpResp HTTPResponse
+	if len(resp.Output) > 0 {
+		_ = json.Unmarshal(resp.Output, &httpResp)
+	}
+	c.apply(resp, httpResp.StatusCode, httpResp.Body)
+}
+
+// apply overrides the error type of resp with the first matching rule,
+// failing a successful result if need be. statusCode is 0 when no response
+// was received.
+func (c *RetryClassifier) apply(resp *ExecuteResponse, statusCode int, body json.RawMessage) {
+	var decoded interface{}
+	decodedBody := false
+	for i, rule := range c.Rules {
+		if rule.BodyPath != "" && !decodedBody {
+			decodedBody = true
+			if len(body) > 0 {
+				_ = json.Unmarshal(body, &decoded)
+			}
+		}
+		if !rule.matches(resp.Error, statusCode, decoded) {
+			continue
+		}
+
+		if resp.Error == nil {
+			message := rule.Message
+			if message == "" {
+				message = fmt.Sprintf("result matched retry_classifier rule %d", i)
+			}
+			resp.Error = &ExecutionError{Message: message}
+		}
+		resp.Error.Type = rule.ErrorType
+		if n := len(resp.ConnectorAttempts); n > 0 {
+			attempt := &resp.ConnectorAttempts[n-1]
+			if attempt.Meta == nil {
+				attempt.Meta = make(map[string]interface{})
+			}
+			attempt.Meta["retry_classifier_rule"] = i
+		}
+		resp.Logs = append(resp.Logs, LogEntry{
+			Timestamp: time.Now(),
+			Level:     "INFO",
+			Message:   fmt.Sprintf("Retry classifier rule %d classified the result as %s", i, rule.ErrorType),
+		})
+		return
+	}
+}
+
+func (r *RetryClassifierRule) matches(execErr *ExecutionError, statusCode int, body interface{}) bool {
+	if len(r.StatusCodes) > 0 {
+		matched := false
+		for _, codes := range r.StatusCodes {
+			if statusCode != 0 && codes.contains(statusCode) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if r.BodyPath != "" {
+		value := retryBodyValue(body, r.BodyPath)
+		if len(r.BodyEquals) == 0 {
+			if value == nil {
+				return false
+			}
+		} else {
+			var want interface{}
+			if err := json.Unmarshal(r.BodyEquals, &want); err != nil || !compareValues(value, want) {
+				return false
+			}
+		}
+	}
+	if r.ErrorContains != "" {
+		if execErr == nil || !strings.Contains(execErr.Message, r.ErrorContains) {
+			return false
+		}
+	}
+	return true
+}
+
+// retryBodyValue selects path from a decoded JSON body. Paths may start with
+// "$." and index arrays as items[0] or items.0.
+func retryBodyValue(body interface{}, path string) interface{} {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	if path == "" {
+		return body
+	}
+	if object, ok := body.(map[string]interface{}); ok {
+		return getFieldValue(object, path)
+	}
+	// An array body: let getFieldValue index it through a wrapper.
+	return getFieldValue(map[string]interface{}{"": body}, "."+path)
+}
@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRetryClassifierRules(t *testing.T) {
+	classifier, err := ParseRetryClassifier(json.RawMessage(`{"url":"https://example.com","retry_classifier":{"rules":[
+		{"status_codes":[400],"body_path":"$.error.code","body_equals":"rate_limited","error_type":"retryable"},
+		{"status_codes":["200-299"],"body_path":"$.ok","body_equals":false,"error_type":"NON_RETRYABLE","message":"API reported failure"},
+		{"status_codes":["5xx"],"body_path":"errors[0].fatal","error_type":"NON_RETRYABLE"},
+		{"error_contains":"connection reset","error_type":"NON_RETRYABLE"}
+	]}}`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	httpResult := func(status int, body string, errorType string) *ExecuteResponse {
+		resp := &ExecuteResponse{Output: json.RawMessage(fmt.Sprintf(`{"status_code":%d,"headers":{},"body":%s}`, status, body))}
+		if errorType != "" {
+			resp.Error = &ExecutionError{Message: fmt.Sprintf("status %d", status), Type: errorType}
+		}
+		return resp
+	}
+	for _, tc := range []struct {
+		name string
+		resp *ExecuteResponse
+		want string // error type, or "" for success
+	}{
+		{"rate limited 400", httpResult(400, `{"error":{"code":"rate_limited"}}`, ErrorTypeNonRetryable), ErrorTypeRetryable},
+		{"other 400", httpResult(400, `{"error":{"code":"bad_field"}}`, ErrorTypeNonRetryable), ErrorTypeNonRetryable},
+		{"200 with error body", httpResult(200, `{"ok":false}`, ""), ErrorTypeNonRetryable},
+		{"200 ok", httpResult(200, `{"ok":true}`, ""), ""},
+		{"fatal 503", httpResult(503, `{"errors":[{"fatal":true}]}`, ErrorTypeRetryable), ErrorTypeNonRetryable},
+		{"plain 503", httpResult(503, `{"errors":[]}`, ErrorTypeRetryable), ErrorTypeRetryable},
+		{"network error", &ExecuteResponse{Error: &ExecutionError{Message: "read: connection reset by peer", Type: ErrorTypeRetryable}}, ErrorTypeNonRetryable},
+	} {
+		classifier.applyHTTP(tc.resp)
+		got := ""
+		if tc.resp.Error != nil {
+			got = tc.resp.Error.Type
+		}
+		if got != tc.want {
+			t.Errorf("%s: classified as %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// Connector results are matched on their last attempt and their output.
+	resp := &ExecuteResponse{
+		Output:            json.RawMessage(`{"ok":false}`),
+		ConnectorAttempts: []ConnectorAttempt{{StatusCode: 200}},
+	}
+	classifier.Apply(resp)
+	if resp.Error == nil || resp.Error.Message != "API reported failure" || resp.ConnectorAttempts[0].Meta["retry_classifier_rule"] != 1 {
+		t.Fatalf("connector result = %+v, %+v", resp.Error, resp.ConnectorAttempts[0].Meta)
+	}
+
+	for _, config := range []string{
+		`{"retry_classifier":{"rules":[{"status_codes":[429]}]}}`,
+		`{"retry_classifier":{"rules":[{"error_type":"RETRYABLE"}]}}`,
+		`{"retry_classifier":{"rules":[{"status_codes":["4xx-5xx"],"error_type":"RETRYABLE"}]}}`,
+		`{"retry_classifier":{"rules":[{"body_equals":1,"error_type":"RETRYABLE"}]}}`,
+	} {
+		if _, err := ParseRetryClassifier(json.RawMessage(config)); !errors.Is(err, ErrInvalidRetryClassifier) {
+			t.Errorf("%s: err = %v, want invalid classifier", config, err)
+		}
+	}
+	if classifier, err := ParseRetryClassifier(json.RawMessage(`{"url":"https://example.com"}`)); classifier != nil || err != nil {
+		t.Fatalf("config without classifier = %+v, %v", classifier, err)
+	}
+}
+
+func TestHTTPExecutorAppliesRetryClassifier(t *testing.T) {
+	t.Parallel()
+
+	request, _ := json.Marshal(map[string]interface{}{
+		"method":  "GET",
+		"url":     "https://example.com/orders",
+		"headers": map[string]string{},
+		"body":    json.RawMessage(nil),
+	})
+	replay := &DeterministicContext{
+		Mode: "replay",
+		Fixtures: []DeterministicFixture{{
+			RequestFingerprint: fmt.Sprintf("%x", sha256.Sum256(request)),
+			Response:           json.RawMessage(`{"status_code":200,"headers":{},"body":{"status":"error","retry":true}}`),
+		}},
+	}
+	execute := func(config string) *ExecuteResponse {
+		resp, err := NewHTTPExecutor().Execute(context.Background(), &ExecuteRequest{
+			NodeType:      "action_http_request",
+			NodeID:        "orders",
+			Config:        json.RawMessage(config),
+			Attempt:       3,
+			Deterministic: replay,
+		})
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		return resp
+	}
+
+	resp := execute(`{"method":"GET","url":"https://example.com/orders","retry_classifier":{"rules":[
+		{"body_path":"status","body_equals":"error","error_type":"RETRYABLE","message":"orders API reported an error"}
+	]}}`)
+	if resp.Error == nil || resp.Error.Type != ErrorTypeRetryable || len(resp.Output) == 0 {
+		t.Fatalf("classified response = %+v", resp.Error)
+	}
+
+	// The retry budget sees the classified error, so it can still end the
+	// retries.
+	resp = execute(`{"method":"GET","url":"https://example.com/orders","retry_budget":{"max_attempts":3},"retry_classifier":{"rules":[
+		{"body_path":"status","body_equals":"error","error_type":"RETRYABLE"}
+	]}}`)
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("budgeted response = %+v, want exhausted", resp.Error)
+	}
+
+	resp = execute(`{"method":"GET","url":"https://example.com/orders","retry_classifier":{"rules":[{"error_type":"RETRYABLE"}]}}`)
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable || len(resp.ConnectorAttempts) != 0 {
+		t.Fatalf("invalid classifier response = %+v, want rejected before the request", resp.Error)
+	}
+}
@@ -0,0 +1,332 @@
+package executor
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// SchemaValidateExecutor validates node input against a JSON Schema and
+// passes it through unchanged when it conforms.
+type SchemaValidateExecutor struct {
+	BaseExecutor
+
+	client *http.Client
+
+	mu      sync.Mutex
+	schemas map[string]*list.Element
+	order   *list.List // least recently used at the front
+}
+
+// cachedSchema is a compiled schema in the executor's LRU cache.
+type cachedSchema struct {
+	key    string
+	schema *jsonschema.Schema
+}
+
+// SchemaValidateConfig represents the configuration for a validate_schema node.
+type SchemaValidateConfig struct {
+	Schema    json.RawMessage `json:"schema"`     // Inline JSON Schema document
+	SchemaURL string          `json:"schema_url"` // Remote JSON Schema document (alternative to schema)
+}
+
+// SchemaValidationFailure describes a single validation failure.
+type SchemaValidationFailure struct {
+	Path    string `json:"path"`
+	Keyword string `json:"keyword"`
+	Message string `json:"message"`
+}
+
+const (
+	maxSchemaDocumentSize = 1024 * 1024 // 1MB
+	maxCachedSchemas      = 256         // compiled schemas kept per executor
+)
+
+// NewSchemaValidateExecutor creates a new schema validation executor.
+func NewSchemaValidateExecutor() *SchemaValidateExecutor {
+	return &SchemaValidateExecutor{
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: newSSRFSafeTransport(),
+		},
+		schemas: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (e *SchemaValidateExecutor) NodeType() string {
+	return "validate_schema"
+}
+
+func (e *SchemaValidateExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Starting schema validation for node %s", req.NodeID),
+	})
+
+	var config SchemaValidateConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("failed to parse validate_schema config: %v", err),
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	if len(config.Schema) == 0 && config.SchemaURL == "" {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: "either schema or schema_url is required",
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	schemaDoc := []byte(config.Schema)
+	if len(schemaDoc) == 0 {
+		if err := validateSchemaURL(config.SchemaURL); err != nil {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: err.Error(),
+					Type:    ErrorTypeNonRetryable,
+				},
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+		fetched, err := e.fetchSchema(ctx, config.SchemaURL)
+		if err != nil {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: fmt.Sprintf("failed to fetch schema: %v", err),
+					Type:    ErrorTypeRetryable,
+				},
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+		schemaDoc = fetched
+	}
+
+	schema, cached, err := e.compile(schemaDoc)
+	if err != nil {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("invalid schema: %v", err),
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+	if cached {
+		logs = append(logs, LogEntry{
+			Timestamp: time.Now(),
+			Level:     "DEBUG",
+			Message:   "Using cached compiled schema",
+		})
+	}
+
+	input := req.Input
+	if len(input) == 0 {
+		input = json.RawMessage("null")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("failed to parse input data: %v", err),
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	if err := schema.Validate(instance); err != nil {
+		validationErr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: fmt.Sprintf("schema validation failed: %v", err),
+					Type:    ErrorTypeNonRetryable,
+				},
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+
+		failures := collectSchemaFailures(validationErr)
+		messages := make([]string, 0, len(failures))
+		for _, failure := range failures {
+			messages = append(messages, fmt.Sprintf("%s: %s", failure.Path, failure.Message))
+		}
+
+		logs = append(logs, LogEntry{
+			Timestamp: time.Now(),
+			Level:     "WARN",
+			Message:   fmt.Sprintf("Input failed schema validation with %d error(s)", len(failures)),
+		})
+
+		output, _ := json.Marshal(map[string]interface{}{
+			"valid":  false,
+			"errors": failures,
+		})
+
+		return &ExecuteResponse{
+			Output: output,
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("schema validation failed: %s", strings.Join(messages, "; ")),
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   "Input conforms to schema",
+	})
+
+	return &ExecuteResponse{
+		Output:   req.Input,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// compile returns the compiled schema for the given document, reusing a cached
+// copy when the same document has been compiled recently. At most
+// maxCachedSchemas are kept; the least recently used one is evicted first.
+func (e *SchemaValidateExecutor) compile(schemaDoc []byte) (*jsonschema.Schema, bool, error) {
+	sum := sha256.Sum256(schemaDoc)
+	key := hex.EncodeToString(sum[:])
+
+	e.mu.Lock()
+	if elem, ok := e.schemas[key]; ok {
+		e.order.MoveToBack(elem)
+		e.mu.Unlock()
+		return elem.Value.(*cachedSchema).schema, true, nil
+	}
+	e.mu.Unlock()
+
+	compiler := jsonschema.NewCompiler()
+	// Remote and file $refs are never resolved; schemas must be self-contained.
+	compiler.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("external schema reference %q is not allowed", s)
+	}
+
+	resourceURL := "mem://schemas/" + key + ".json"
+	if err := compiler.AddResource(resourceURL, bytes.NewReader(schemaDoc)); err != nil {
+		return nil, false, err
+	}
+
+	schema, err := compiler.Compile(resourceURL)
+	if err != nil {
+		return nil, false, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.schemas[key]; !ok {
+		for e.order.Len() >= maxCachedSchemas {
+			oldest := e.order.Front()
+			e.order.Remove(oldest)
+			delete(e.schemas, oldest.Value.(*cachedSchema).key)
+		}
+		e.schemas[key] = e.order.PushBack(&cachedSchema{key: key, schema: schema})
+	}
+
+	return schema, false, nil
+}
+
+// validateSchemaURL rejects schema URLs that are not http or https. Such a
+// URL can never be fetched, so retrying the node would not help.
+func validateSchemaURL(schemaURL string) error {
+	parsed, err := url.Parse(schemaURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("only http and https schema URLs are allowed")
+	}
+	return nil
+}
+
+func (e *SchemaValidateExecutor) fetchSchema(ctx context.Context, schemaURL string) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, schemaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/schema+json, application/json")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("schema URL returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSchemaDocumentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxSchemaDocumentSize {
+		return nil, fmt.Errorf("schema document exceeds %d bytes limit", maxSchemaDocumentSize)
+	}
+
+	return body, nil
+}
+
+// collectSchemaFailures flattens a validation error tree into its leaf failures.
+func collectSchemaFailures(err *jsonschema.ValidationError) []SchemaValidationFailure {
+	failures := make([]SchemaValidationFailure, 0)
+
+	var walk func(ve *jsonschema.ValidationError)
+	walk = func(ve *jsonschema.ValidationError) {
+		if len(ve.Causes) == 0 {
+			path := ve.InstanceLocation
+			if path == "" {
+				path = "/"
+			}
+			failures = append(failures, SchemaValidationFailure{
+				Path:    path,
+				Keyword: ve.KeywordLocation,
+				Message: ve.Message,
+			})
+			return
+		}
+		for _, cause := range ve.Causes {
+			walk(cause)
+		}
+	}
+	walk(err)
+
+	return failures
+}
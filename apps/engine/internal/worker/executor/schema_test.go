@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSchemaValidateExecutorPassesValidInput(t *testing.T) {
+	t.Parallel()
+
+	exec := NewSchemaValidateExecutor()
+	config := json.RawMessage(`{"schema":{"type":"object","required":["email"],"properties":{"email":{"type":"string"}}}}`)
+	input := json.RawMessage(`{"email":"user@example.com","age":42}`)
+
+	resp, err := exec.Execute(context.Background(), &ExecuteRequest{
+		NodeType: "validate_schema",
+		NodeID:   "node-1",
+		Config:   config,
+		Input:    input,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("expected no execute error, got: %+v", resp.Error)
+	}
+	if string(resp.Output) != string(input) {
+		t.Fatalf("expected input to pass through unchanged, got: %s", string(resp.Output))
+	}
+
+	// A second execution with the same schema must hit the compiled cache.
+	if _, err := exec.Execute(context.Background(), &ExecuteRequest{Config: config, Input: input}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exec.schemas) != 1 {
+		t.Fatalf("expected 1 cached schema, got %d", len(exec.schemas))
+	}
+}
+
+func TestSchemaValidateExecutorBoundsCompiledSchemas(t *testing.T) {
+	t.Parallel()
+
+	exec := NewSchemaValidateExecutor()
+	first := []byte(`{"type":"object","title":"schema-0"}`)
+	if _, _, err := exec.compile(first); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	for i := 1; i <= maxCachedSchemas; i++ {
+		if _, _, err := exec.compile([]byte(fmt.Sprintf(`{"type":"object","title":"schema-%d"}`, i))); err != nil {
+			t.Fatalf("compile: %v", err)
+		}
+	}
+	if len(exec.schemas) != maxCachedSchemas || exec.order.Len() != maxCachedSchemas {
+		t.Fatalf("cached %d schemas, want %d", len(exec.schemas), maxCachedSchemas)
+	}
+	if _, cached, _ := exec.compile(first); cached {
+		t.Fatal("least recently used schema was not evicted")
+	}
+}
+
+func TestSchemaValidateExecutorRejectsUnsupportedSchemaURL(t *testing.T) {
+	t.Parallel()
+
+	resp, err := NewSchemaValidateExecutor().Execute(context.Background(), &ExecuteRequest{
+		Config: json.RawMessage(`{"schema_url":"file:///etc/schema.json"}`),
+		Input:  json.RawMessage(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("expected a non-retryable error, got %+v", resp.Error)
+	}
+}
+
+func TestSchemaValidateExecutorReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	exec := NewSchemaValidateExecutor()
+	resp, err := exec.Execute(context.Background(), &ExecuteRequest{
+		NodeType: "validate_schema",
+		NodeID:   "node-2",
+		Config:   json.RawMessage(`{"schema":{"type":"object","required":["email"],"properties":{"age":{"type":"integer","minimum":0}}}}`),
+		Input:    json.RawMessage(`{"age":-1}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Error == nil {
+		t.Fatalf("expected validation error")
+	}
+	if resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("expected non-retryable error, got %s", resp.Error.Type)
+	}
+	if !strings.Contains(resp.Error.Message, "/age") {
+		t.Fatalf("expected failure path in message, got: %s", resp.Error.Message)
+	}
+
+	var output struct {
+		Errors []SchemaValidationFailure `json:"errors"`
+	}
+	if err := json.Unmarshal(resp.Output, &output); err != nil {
+		t.Fatalf("failed to decode output: %v", err)
+	}
+	if len(output.Errors) != 2 {
+		t.Fatalf("expected 2 validation failures, got %d: %+v", len(output.Errors), output.Errors)
+	}
+}
@@ -3,19 +3,36 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/linkflow/engine/internal/sandbox"
 )
 
-// ScriptExecutor handles action_script nodes.
-// This is an alias/wrapper that can execute JavaScript-like expressions.
-type ScriptExecutor struct{}
+// maxScriptStderr bounds how much of a failing script's stderr is copied into
+// the node error.
+const maxScriptStderr = 4 * 1024
+
+// ScriptExecutor handles action_script nodes by running their code in the
+// sandbox.
+type ScriptExecutor struct {
+	BaseExecutor
+	sandbox *sandbox.CodeExecutor
+}
 
 // NewScriptExecutor creates a new script executor.
 func NewScriptExecutor() *ScriptExecutor {
 	return &ScriptExecutor{}
 }
 
+// WithSandbox sets the sandbox scripts run in. Without one, script nodes fail.
+func (e *ScriptExecutor) WithSandbox(sb *sandbox.Sandbox) *ScriptExecutor {
+	e.sandbox = sandbox.NewCodeExecutorWithSandbox(sb)
+	return e
+}
+
 func (e *ScriptExecutor) NodeType() string {
 	return "action_script"
 }
@@ -33,7 +50,7 @@ func (e *ScriptExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exe
 	// Parse script configuration
 	var config struct {
 		Code     string `json:"code"`
-		Language string `json:"language"` // javascript, python (future)
+		Language string `json:"language"` // javascript, python, bash
 		Timeout  int    `json:"timeout"`  // seconds
 	}
 
@@ -52,18 +69,100 @@ func (e *ScriptExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exe
 		}, nil
 	}
 
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: errorType},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	if config.Code == "" {
+		return failed("script code is required", ErrorTypeNonRetryable)
+	}
+	if config.Language == "" {
+		config.Language = "javascript"
+	}
+	if e.sandbox == nil {
+		logs = append(logs, LogEntry{
+			Timestamp: time.Now(),
+			Level:     "warn",
+			Message:   fmt.Sprintf("no sandbox configured (language: %s, code length: %d chars)", config.Language, len(config.Code)),
+		})
+		return failed("script execution is not available — sandboxed runtime required", ErrorTypeNonRetryable)
+	}
+
+	input, err := scriptInput(req.Input)
+	if err != nil {
+		return failed(fmt.Sprintf("invalid script input: %v", err), ErrorTypeNonRetryable)
+	}
+
+	// The node timeout takes precedence over the activity timeout; the sandbox
+	// caps both at its own maximum.
+	timeout := req.Timeout
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Second
+	}
+
+	result, err := e.sandbox.Execute(ctx, config.Code, config.Language, input, timeout)
+	if err != nil {
+		if errors.Is(err, sandbox.ErrExecutionTimeout) {
+			return failed("script execution timed out", ErrorTypeTimeout)
+		}
+		return failed(fmt.Sprintf("script execution failed: %v", err), ErrorTypeNonRetryable)
+	}
+
 	logs = append(logs, LogEntry{
 		Timestamp: time.Now(),
-		Level:     "warn",
-		Message:   fmt.Sprintf("script executor not yet available (language: %s, code length: %d chars)", config.Language, len(config.Code)),
+		Level:     "info",
+		Message:   fmt.Sprintf("script exited with code %d in %s", result.ExitCode, result.Duration),
 	})
 
+	if result.ExitCode != 0 {
+		stderr := strings.TrimSpace(result.Stderr)
+		if len(stderr) > maxScriptStderr {
+			stderr = stderr[:maxScriptStderr] + "... (truncated)"
+		}
+		return failed(fmt.Sprintf("script exited with code %d: %s", result.ExitCode, stderr), ErrorTypeNonRetryable)
+	}
+
+	output, err := scriptOutput(result)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to encode script output: %v", err), ErrorTypeNonRetryable)
+	}
+
 	return &ExecuteResponse{
-		Error: &ExecutionError{
-			Message: "script execution is not yet available — sandboxed runtime required",
-			Type:    ErrorTypeNonRetryable,
-		},
+		Output:   output,
 		Logs:     logs,
 		Duration: time.Since(start),
 	}, nil
 }
+
+// scriptInput decodes the node input for the sandbox. Non-object inputs are
+// exposed to the script as input.value.
+func scriptInput(raw json.RawMessage) (map[string]interface{}, error) {
+	if len(raw) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	if m, ok := value.(map[string]interface{}); ok {
+		return m, nil
+	}
+	return map[string]interface{}{"value": value}, nil
+}
+
+// scriptOutput returns the value the script produced, falling back to its
+// stdout when it produced none.
+func scriptOutput(result *sandbox.ExecutionResult) (json.RawMessage, error) {
+	if result.Output != nil {
+		return json.Marshal(result.Output)
+	}
+	stdout := strings.TrimSpace(result.Stdout)
+	if json.Valid([]byte(stdout)) {
+		return json.RawMessage(stdout), nil
+	}
+	return json.Marshal(map[string]interface{}{"stdout": result.Stdout})
+}
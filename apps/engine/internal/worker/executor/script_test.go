@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/sandbox"
+)
+
+// fakeRuntime stands in for a language runtime so the executor can be tested
+// without node or docker on the host.
+type fakeRuntime struct {
+	run func(ctx context.Context, req *sandbox.ExecutionRequest) (*sandbox.ExecutionResult, error)
+}
+
+func (r *fakeRuntime) Language() string { return "fake" }
+func (r *fakeRuntime) Available() bool  { return true }
+func (r *fakeRuntime) Execute(ctx context.Context, req *sandbox.ExecutionRequest) (*sandbox.ExecutionResult, error) {
+	return r.run(ctx, req)
+}
+
+func scriptExecutorWith(t *testing.T, runtime *fakeRuntime) *ScriptExecutor {
+	t.Helper()
+	sb, err := sandbox.NewSandbox(sandbox.Config{
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		MaxExecutionTime: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewSandbox: %v", err)
+	}
+	sb.RegisterRuntime(runtime)
+	return NewScriptExecutor().WithSandbox(sb)
+}
+
+func scriptRequest(config string) *ExecuteRequest {
+	return &ExecuteRequest{
+		NodeType: "action_script",
+		NodeID:   "script-1",
+		Config:   json.RawMessage(config),
+		Input:    json.RawMessage(`{"name":"ada"}`),
+	}
+}
+
+func TestScriptExecutorReturnsSandboxOutput(t *testing.T) {
+	var got *sandbox.ExecutionRequest
+	e := scriptExecutorWith(t, &fakeRuntime{run: func(_ context.Context, req *sandbox.ExecutionRequest) (*sandbox.ExecutionResult, error) {
+		got = req
+		return &sandbox.ExecutionResult{Output: map[string]interface{}{"greeting": "hi " + req.Input["name"].(string)}}, nil
+	}})
+
+	resp, err := e.Execute(context.Background(), scriptRequest(`{"code":"return 1","language":"fake","timeout":5}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %s", resp.Error.Message)
+	}
+	if string(resp.Output) != `{"greeting":"hi ada"}` {
+		t.Fatalf("output = %s", resp.Output)
+	}
+	if got.Code != "return 1" || got.Timeout != 5*time.Second || got.MemoryLimit == 0 {
+		t.Fatalf("unexpected sandbox request %+v", got)
+	}
+}
+
+func TestScriptExecutorMapsFailures(t *testing.T) {
+	timeout := scriptExecutorWith(t, &fakeRuntime{run: func(context.Context, *sandbox.ExecutionRequest) (*sandbox.ExecutionResult, error) {
+		return &sandbox.ExecutionResult{}, sandbox.ErrExecutionTimeout
+	}})
+	resp, err := timeout.Execute(context.Background(), scriptRequest(`{"code":"for(;;){}","language":"fake"}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Type != ErrorTypeTimeout {
+		t.Fatalf("expected timeout error, got %+v", resp.Error)
+	}
+
+	exit := scriptExecutorWith(t, &fakeRuntime{run: func(context.Context, *sandbox.ExecutionRequest) (*sandbox.ExecutionResult, error) {
+		return &sandbox.ExecutionResult{ExitCode: 1, Stderr: "ReferenceError: x is not defined\n"}, nil
+	}})
+	resp, err = exit.Execute(context.Background(), scriptRequest(`{"code":"x","language":"fake"}`))
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable || !strings.Contains(resp.Error.Message, "ReferenceError") {
+		t.Fatalf("expected non-retryable error with stderr, got %+v", resp.Error)
+	}
+}
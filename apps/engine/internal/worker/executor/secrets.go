@@ -0,0 +1,286 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// SecretRefKey marks a config value that names a secret instead of holding
+// it: {"$secret": "twilio_auth_token"}.
+const SecretRefKey = "$secret"
+
+// redactedSecret replaces resolved secret values in node results.
+const redactedSecret = "[REDACTED]"
+
+// minRedactedSecretLength is the shortest secret value that is redacted from
+// results; shorter values would match unrelated text.
+const minRedactedSecretLength = 4
+
+var (
+	// ErrSecretNotFound is returned by a SecretStore for a name it does not
+	// hold. A node referencing a missing secret fails without retrying.
+	ErrSecretNotFound = errors.New("secret not found")
+	// ErrInvalidSecretRef is returned for a $secret reference whose name is
+	// not a non-empty string.
+	ErrInvalidSecretRef = errors.New("invalid secret reference")
+)
+
+// SecretStore looks up secrets by name for a namespace.
+type SecretStore interface {
+	GetSecret(ctx context.Context, namespace, name string) (string, error)
+}
+
+// SecretConsumer is implemented by executors whose config may reference
+// secrets. The worker resolves {"$secret": name} values in
+// ExecuteRequest.Config for them when UsesSecrets returns true, right before
+// Execute, and redacts the resolved values from the response.
+type SecretConsumer interface {
+	UsesSecrets() bool
+}
+
+// EnvSecretStore reads secrets from environment variables named by Prefix
+// and the upper-cased secret name, with characters other than letters and
+// digits replaced by underscores: with the default prefix, "twilio.token"
+// is read from LINKFLOW_SECRET_TWILIO_TOKEN. It serves every namespace alike.
+type EnvSecretStore struct {
+	Prefix string
+}
+
+// NewEnvSecretStore creates a store reading LINKFLOW_SECRET_* variables.
+func NewEnvSecretStore() *EnvSecretStore {
+	return &EnvSecretStore{Prefix: "LINKFLOW_SECRET_"}
+}
+
+func (s *EnvSecretStore) GetSecret(_ context.Context, _, name string) (string, error) {
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	value, ok := os.LookupEnv(s.Prefix + key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+// VaultSecretStore reads secrets from a HashiCorp Vault KV version 2 engine.
+// A secret name is read from <Mount>/data/<namespace>/<name> and its "value"
+// field is the secret, so each namespace sees only its own secrets.
+type VaultSecretStore struct {
+	client *http.Client
+	addr   string
+	token  string
+	mount  string
+}
+
+// VaultSecretStoreConfig configures a VaultSecretStore.
+type VaultSecretStoreConfig struct {
+	Addr  string
+	Token string
+	// Mount is the KV engine's mount path (default "secret").
+	Mount   string
+	Timeout time.Duration
+}
+
+// NewVaultSecretStore creates a store for the Vault server at cfg.Addr.
+func NewVaultSecretStore(cfg VaultSecretStoreConfig) *VaultSecretStore {
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &VaultSecretStore{
+		client: &http.Client{Timeout: cfg.Timeout},
+		addr:   strings.TrimRight(cfg.Addr, "/"),
+		token:  cfg.Token,
+		mount:  strings.Trim(cfg.Mount, "/"),
+	}
+}
+
+func (s *VaultSecretStore) GetSecret(ctx context.Context, namespace, name string) (string, error) {
+	path := fmt.Sprintf("%s/v1/%s/data/%s/%s", s.addr, s.mount, url.PathEscape(namespace), url.PathEscape(name))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+	httpReq.Header.Set("X-Vault-Token", s.token)
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	value, ok := secret.Data.Data["value"].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s has no string value field", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+// ResolveSecretRefs replaces every {"$secret": name} object in config with
+// the named secret from store. It returns config unchanged, and a nil
+// redactor, when there are no references.
+func ResolveSecretRefs(ctx context.Context, store SecretStore, namespace string, config json.RawMessage) (json.RawMessage, *SecretRedactor, error) {
+	if !bytes.Contains(config, []byte(`"`+SecretRefKey+`"`)) {
+		return config, nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		// Left for the executor to report as an invalid config.
+		return config, nil, nil
+	}
+
+	redactor := &SecretRedactor{}
+	resolved, err := resolveSecretValue(ctx, store, namespace, value, redactor)
+	if err != nil {
+		return nil, nil, err
+	}
+	if redactor.refs == 0 {
+		return config, nil, nil
+	}
+	out, err := json.Marshal(resolved)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode resolved config: %w", err)
+	}
+	return out, redactor, nil
+}
+
+func resolveSecretValue(ctx context.Context, store SecretStore, namespace string, value interface{}, redactor *SecretRedactor) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v[SecretRefKey]; ok && len(v) == 1 {
+			name, _ := ref.(string)
+			if name == "" {
+				return nil, fmt.Errorf("%w: %s must name a secret", ErrInvalidSecretRef, SecretRefKey)
+			}
+			if store == nil {
+				return nil, fmt.Errorf("%w: %s (no secret store is configured)", ErrSecretNotFound, name)
+			}
+			secret, err := store.GetSecret(ctx, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+			redactor.add(secret)
+			return secret, nil
+		}
+		for key, item := range v {
+			resolved, err := resolveSecretValue(ctx, store, namespace, item, redactor)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := resolveSecretValue(ctx, store, namespace, item, redactor)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// SecretRedactor masks resolved secret values, both as plain text and as
+// they appear inside JSON strings. A nil redactor masks nothing.
+type SecretRedactor struct {
+	refs     int
+	values   []string
+	replacer *strings.Replacer
+}
+
+func (r *SecretRedactor) add(secret string) {
+	r.refs++
+	if len(secret) < minRedactedSecretLength {
+		return
+	}
+	r.values = append(r.values, secret)
+	if encoded, err := json.Marshal(secret); err == nil {
+		if escaped := string(encoded[1 : len(encoded)-1]); escaped != secret {
+			r.values = append(r.values, escaped)
+		}
+	}
+	pairs := make([]string, 0, 2*len(r.values))
+	for _, value := range r.values {
+		pairs = append(pairs, value, redactedSecret)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// Redact masks the secrets in s.
+func (r *SecretRedactor) Redact(s string) string {
+	if r == nil || r.replacer == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
+
+func (r *SecretRedactor) redactJSON(raw json.RawMessage) json.RawMessage {
+	if r == nil || r.replacer == nil || len(raw) == 0 {
+		return raw
+	}
+	return json.RawMessage(r.replacer.Replace(string(raw)))
+}
+
+// RedactResponse masks the secrets in everything a response reports back:
+// its output, error, logs, connector attempts and deterministic fixtures.
+func (r *SecretRedactor) RedactResponse(resp *ExecuteResponse) {
+	if r == nil || r.replacer == nil || resp == nil {
+		return
+	}
+	resp.Output = r.redactJSON(resp.Output)
+	if resp.Error != nil {
+		resp.Error.Message = r.Redact(resp.Error.Message)
+		resp.Error.StackTrace = r.Redact(resp.Error.StackTrace)
+	}
+	for i := range resp.Logs {
+		resp.Logs[i].Message = r.Redact(resp.Logs[i].Message)
+	}
+	for i := range resp.ConnectorAttempts {
+		resp.ConnectorAttempts[i].ErrorMessage = r.Redact(resp.ConnectorAttempts[i].ErrorMessage)
+	}
+	for i := range resp.DeterministicFixtures {
+		resp.DeterministicFixtures[i].Request = r.redactJSON(resp.DeterministicFixtures[i].Request)
+		resp.DeterministicFixtures[i].Response = r.redactJSON(resp.DeterministicFixtures[i].Response)
+	}
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type mapSecretStore map[string]string
+
+func (s mapSecretStore) GetSecret(_ context.Context, namespace, name string) (string, error) {
+	value, ok := s[namespace+"/"+name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+func TestResolveSecretRefs(t *testing.T) {
+	ctx := context.Background()
+	store := mapSecretStore{"ws-1/twilio_token": `tok"en-123`}
+
+	config := json.RawMessage(`{"account_sid":"AC1","auth_token":{"$secret":"twilio_token"},"to":"+15550100","retries":3}`)
+	resolved, redactor, err := ResolveSecretRefs(ctx, store, "ws-1", config)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	var twilio TwilioConfig
+	if err := json.Unmarshal(resolved, &twilio); err != nil {
+		t.Fatalf("decode resolved config: %v", err)
+	}
+	if twilio.AuthToken != `tok"en-123` || twilio.AccountSID != "AC1" {
+		t.Fatalf("resolved config = %s", resolved)
+	}
+	if !strings.Contains(string(resolved), `"retries":3`) {
+		t.Fatalf("numbers were not preserved: %s", resolved)
+	}
+
+	// The secret is masked wherever the response reports it, raw or
+	// JSON-escaped.
+	resp := &ExecuteResponse{
+		Output: json.RawMessage(`{"echo":"tok\"en-123"}`),
+		Error:  &ExecutionError{Message: `401 for token tok"en-123`},
+		Logs:   []LogEntry{{Message: `using tok"en-123`}},
+		DeterministicFixtures: []DeterministicFixture{{
+			Request: json.RawMessage(`{"headers":{"Authorization":"tok\"en-123"}}`),
+		}},
+	}
+	redactor.RedactResponse(resp)
+	for _, got := range []string{string(resp.Output), resp.Error.Message, resp.Logs[0].Message, string(resp.DeterministicFixtures[0].Request)} {
+		if strings.Contains(got, "en-123") || !strings.Contains(got, redactedSecret) {
+			t.Errorf("secret not redacted from %q", got)
+		}
+	}
+
+	// Configs without references are passed through untouched.
+	plain := json.RawMessage(`{"url":"https://example.com"}`)
+	if got, redactor, err := ResolveSecretRefs(ctx, store, "ws-1", plain); err != nil || string(got) != string(plain) || redactor != nil {
+		t.Fatalf("plain config: got %s, %v, %v", got, redactor, err)
+	}
+
+	// Secrets are scoped to the namespace, and a bad reference is rejected.
+	if _, _, err := ResolveSecretRefs(ctx, store, "ws-2", config); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound from another namespace, got %v", err)
+	}
+	if _, _, err := ResolveSecretRefs(ctx, store, "ws-1", json.RawMessage(`{"password":{"$secret":""}}`)); !errors.Is(err, ErrInvalidSecretRef) {
+		t.Fatalf("expected ErrInvalidSecretRef, got %v", err)
+	}
+	if _, _, err := ResolveSecretRefs(ctx, nil, "ws-1", config); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound without a store, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linkflow/engine/internal/expression"
+)
+
+// SetVariableExecutor sets a workflow-scoped variable. History records the
+// node's output in the execution's mutable state, and later nodes read it as
+// $.vars.<name> in their expressions.
+type SetVariableExecutor struct {
+	expressions *expression.Engine
+}
+
+// SetVariableConfig represents the configuration for a set_variable node.
+type SetVariableConfig struct {
+	Name string `json:"name"`
+	// Value is a JSON literal, or a string expression when it starts with
+	// "$" or contains "{{ }}". Expressions see $.input (workflow input),
+	// $.nodes.<id>.output, $.vars.<name> and $.current (this node's input).
+	Value json.RawMessage `json:"value"`
+	// Operation is "set" (default), "append" to add the value to a list
+	// variable, or "increment" to add it to a numeric variable.
+	Operation string `json:"operation"`
+}
+
+// SetVariableResponse is the output of a set_variable node: the variable and
+// its new value.
+type SetVariableResponse struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+var setVariableInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["name"],
+  "properties": {
+    "name": {"type": "string", "minLength": 1},
+    "value": {"description": "JSON value, or an expression string starting with $ or containing {{ }}"},
+    "operation": {"type": "string", "enum": ["set", "append", "increment"], "default": "set"}
+  }
+}`)
+
+var setVariableOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["name", "value"],
+  "properties": {
+    "name": {"type": "string"},
+    "value": {}
+  }
+}`)
+
+// NewSetVariableExecutor creates a new set_variable executor.
+func NewSetVariableExecutor() *SetVariableExecutor {
+	return &SetVariableExecutor{expressions: expression.NewEngine()}
+}
+
+func (e *SetVariableExecutor) NodeType() string {
+	return "set_variable"
+}
+
+func (e *SetVariableExecutor) InputSchema() json.RawMessage {
+	return setVariableInputSchema
+}
+
+func (e *SetVariableExecutor) OutputSchema() json.RawMessage {
+	return setVariableOutputSchema
+}
+
+// UsesScope reports that set_variable evaluates its value over the whole
+// execution scope.
+func (e *SetVariableExecutor) UsesScope() bool {
+	return true
+}
+
+func (e *SetVariableExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+
+	failed := func(message string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: ErrorTypeNonRetryable},
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config SetVariableConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse set_variable config: %v", err))
+	}
+	if config.Name == "" {
+		return failed("name is required")
+	}
+
+	data := make(map[string]interface{}, len(req.Scope)+1)
+	for k, v := range req.Scope {
+		data[k] = v
+	}
+	var current interface{}
+	if len(req.Input) > 0 {
+		if err := json.Unmarshal(req.Input, &current); err != nil {
+			return failed(fmt.Sprintf("failed to parse input: %v", err))
+		}
+	}
+	data["current"] = current
+
+	value, err := e.resolveValue(config.Value, data)
+	if err != nil {
+		return failed(err.Error())
+	}
+
+	var existing interface{}
+	if vars, ok := data["vars"].(map[string]interface{}); ok {
+		existing = vars[config.Name]
+	}
+
+	switch config.Operation {
+	case "", "set":
+	case "append":
+		list, ok := existing.([]interface{})
+		if existing != nil && !ok {
+			return failed(fmt.Sprintf("variable %q is not a list", config.Name))
+		}
+		value = append(append([]interface{}{}, list...), value)
+	case "increment":
+		total, ok := existing.(float64)
+		if existing != nil && !ok {
+			return failed(fmt.Sprintf("variable %q is not a number", config.Name))
+		}
+		delta, ok := value.(float64)
+		if !ok {
+			return failed("increment value must be a number")
+		}
+		value = total + delta
+	default:
+		return failed(fmt.Sprintf("unsupported operation: %s", config.Operation))
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal value: %v", err))
+	}
+	output, err := json.Marshal(SetVariableResponse{Name: config.Name, Value: encoded})
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err))
+	}
+
+	return &ExecuteResponse{
+		Output: output,
+		Logs: []LogEntry{{
+			Timestamp: time.Now(),
+			Level:     "INFO",
+			Message:   fmt.Sprintf("Set variable %s", config.Name),
+		}},
+		Duration: time.Since(start),
+	}, nil
+}
+
+// resolveValue decodes raw and evaluates it when it is an expression string.
+func (e *SetVariableExecutor) resolveValue(raw json.RawMessage, data interface{}) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("invalid value: %v", err)
+	}
+	expr, ok := value.(string)
+	if !ok || !(strings.HasPrefix(expr, "$") || strings.Contains(expr, "{{")) {
+		return value, nil
+	}
+	result, err := e.expressions.Evaluate(expr, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate %q: %v", expr, err)
+	}
+	// Round-trip through JSON so results compare like stored variables.
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q: %v", expr, err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
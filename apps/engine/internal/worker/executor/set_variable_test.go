@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestSetVariableExecutor(t *testing.T) {
+	exec := NewSetVariableExecutor()
+	scope := map[string]interface{}{
+		"input": map[string]interface{}{"order_id": "o-1"},
+		"nodes": map[string]interface{}{
+			"fetch": map[string]interface{}{"output": map[string]interface{}{"total": float64(40)}},
+		},
+		"vars": map[string]interface{}{"seen": []interface{}{"a"}, "sum": float64(2)},
+	}
+
+	run := func(config string) (*ExecuteResponse, SetVariableResponse) {
+		t.Helper()
+		resp, err := exec.Execute(context.Background(), &ExecuteRequest{
+			NodeType: "set_variable",
+			Config:   json.RawMessage(config),
+			Input:    json.RawMessage(`{"item":"b"}`),
+			Scope:    scope,
+		})
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		var out SetVariableResponse
+		if resp.Error == nil {
+			if err := json.Unmarshal(resp.Output, &out); err != nil {
+				t.Fatalf("decode output: %v", err)
+			}
+		}
+		return resp, out
+	}
+
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{`{"name":"order","value":"$.input.order_id"}`, `"o-1"`},
+		{`{"name":"label","value":"Order {{$.input.order_id}}"}`, `"Order o-1"`},
+		{`{"name":"flag","value":{"on":true}}`, `{"on":true}`},
+		{`{"name":"plain","value":"hello"}`, `"hello"`},
+		{`{"name":"seen","value":"$.current.item","operation":"append"}`, `["a","b"]`},
+		{`{"name":"sum","value":"$.nodes.fetch.output.total","operation":"increment"}`, `42`},
+		{`{"name":"fresh","value":3,"operation":"increment"}`, `3`},
+	}
+	for _, tt := range tests {
+		resp, out := run(tt.config)
+		if resp.Error != nil {
+			t.Fatalf("%s: unexpected error %s", tt.config, resp.Error.Message)
+		}
+		if string(out.Value) != tt.want {
+			t.Fatalf("%s: value = %s, want %s", tt.config, out.Value, tt.want)
+		}
+	}
+
+	for _, config := range []string{
+		`{"value":1}`,
+		`{"name":"sum","value":"x","operation":"increment"}`,
+		`{"name":"sum","value":1,"operation":"append"}`,
+		`{"name":"x","value":1,"operation":"multiply"}`,
+	} {
+		if resp, _ := run(config); resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+			t.Fatalf("%s: expected a non-retryable error, got %+v", config, resp.Error)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	sheetsBaseURL = "https://sheets.googleapis.com/v4/spreadsheets"
+	sheetsScope   = "https://www.googleapis.com/auth/spreadsheets"
+	// maxSheetsResponseBytes caps how much of a response, such as the cells
+	// of a read, is read into the node output.
+	maxSheetsResponseBytes = 10 * 1024 * 1024
+)
+
+var (
+	// errInvalidSheetsValues is returned for values that are neither a row
+	// nor a list of rows.
+	errInvalidSheetsValues = errors.New("values must be a row or a list of rows")
+	// errInvalidGoogleCredentials is returned for credentials that cannot be
+	// loaded, such as a malformed service account key.
+	errInvalidGoogleCredentials = errors.New("invalid Google credentials")
+)
+
+// SheetsExecutor reads and writes Google Sheets cell ranges through the
+// Sheets API v4.
+type SheetsExecutor struct {
+	BaseExecutor
+
+	client  *http.Client
+	baseURL string
+	limiter *ConnectorRateLimiter
+}
+
+// SheetsConfig represents the configuration for a google_sheets node.
+type SheetsConfig struct {
+	Operation     string `json:"operation"` // append_row, read_range, update_range or clear_range
+	SpreadsheetID string `json:"spreadsheet_id"`
+	Range         string `json:"range"` // A1 notation, e.g. "Orders!A1:D"
+	// Values is one row, ["a", 1], or a list of rows, [["a", 1], ["b", 2]].
+	Values json.RawMessage `json:"values"`
+	// ValueInputOption is how written values are interpreted: USER_ENTERED
+	// (default) parses formulas, numbers and dates as if typed, RAW stores
+	// them as given.
+	ValueInputOption string `json:"value_input_option"`
+
+	// AccessToken is an OAuth access token with the spreadsheets scope.
+	// Otherwise CredentialsJSON, a service account key, is used, and
+	// Application Default Credentials when neither is set.
+	AccessToken     string `json:"access_token"`
+	CredentialsJSON string `json:"credentials_json"`
+
+	Timeout int `json:"timeout"` // seconds
+}
+
+// NewSheetsExecutor creates a new Google Sheets executor with connection pooling.
+func NewSheetsExecutor() *SheetsExecutor {
+	return &SheetsExecutor{
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: newSSRFSafeTransport(),
+		},
+		baseURL: sheetsBaseURL,
+		limiter: DefaultConnectorRateLimiter(),
+	}
+}
+
+// WithRateLimiter sets the limiter used to throttle Sheets calls.
+func (e *SheetsExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *SheetsExecutor {
+	e.limiter = limiter
+	return e
+}
+
+func (e *SheetsExecutor) NodeType() string {
+	return "google_sheets"
+}
+
+// UsesSecrets reports that access_token and credentials_json may reference
+// secrets.
+func (e *SheetsExecutor) UsesSecrets() bool {
+	return true
+}
+
+func (e *SheetsExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Starting Google Sheets execution for node %s", req.NodeID),
+	})
+
+	var config SheetsConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return incidentConfigError(fmt.Sprintf("failed to parse Google Sheets config: %v", err), logs, start), nil
+	}
+	if config.SpreadsheetID == "" {
+		return incidentConfigError("spreadsheet_id is required", logs, start), nil
+	}
+	if config.Range == "" {
+		return incidentConfigError("range is required", logs, start), nil
+	}
+	if config.ValueInputOption == "" {
+		config.ValueInputOption = "USER_ENTERED"
+	}
+	if config.ValueInputOption != "USER_ENTERED" && config.ValueInputOption != "RAW" {
+		return incidentConfigError(fmt.Sprintf("unsupported value_input_option: %s", config.ValueInputOption), logs, start), nil
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/values/%s", e.baseURL, url.PathEscape(config.SpreadsheetID), url.PathEscape(config.Range))
+	query := url.Values{}
+	var method string
+	var body interface{}
+	switch config.Operation {
+	case "append_row", "update_range":
+		rows, err := sheetsRows(config.Values)
+		if err != nil {
+			return incidentConfigError(err.Error(), logs, start), nil
+		}
+		query.Set("valueInputOption", config.ValueInputOption)
+		body = map[string]interface{}{"range": config.Range, "values": rows}
+		if config.Operation == "append_row" {
+			method = http.MethodPost
+			endpoint += ":append"
+			query.Set("insertDataOption", "INSERT_ROWS")
+		} else {
+			method = http.MethodPut
+		}
+	case "read_range":
+		method = http.MethodGet
+	case "clear_range":
+		method = http.MethodPost
+		endpoint += ":clear"
+		body = map[string]interface{}{}
+	default:
+		return incidentConfigError(fmt.Sprintf("unsupported operation: %s (supported: append_row, read_range, update_range, clear_range)", config.Operation), logs, start), nil
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	token, err := sheetsAccessToken(ctx, config)
+	if err != nil {
+		return sheetsAuthError(req, config.Operation, err, logs, start), nil
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return incidentConfigError(fmt.Sprintf("failed to marshal values: %v", err), logs, start), nil
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	waited, err := e.limiter.Acquire(ctx, "google_sheets")
+	if err != nil {
+		return rateLimitedResponse(req, "google_sheets", config.Operation, "google", waited, err, logs, start), nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Google Sheets %s on %s!%s", config.Operation, config.SpreadsheetID, config.Range),
+	})
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return incidentConfigError(fmt.Sprintf("failed to create request: %v", err), logs, start), nil
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	if reqBody != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return incidentNetworkError(req, "google_sheets", config.Operation, err, waited, logs, start), nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxSheetsResponseBytes))
+
+	attempt := newConnectorAttempt(req, "google_sheets", config.Operation, "google", "success", start, waited)
+	attempt.StatusCode = int32(resp.StatusCode)
+
+	if resp.StatusCode >= 400 {
+		return incidentProviderError(resp.StatusCode, "Google Sheets", sheetsErrorMessage(respBody), attempt, logs, start), nil
+	}
+
+	output, err := sheetsOutput(config, respBody)
+	if err != nil {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: err.Error(),
+				Type:    ErrorTypeRetryable,
+			},
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
+		}, nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Google Sheets %s completed successfully", config.Operation),
+	})
+
+	return &ExecuteResponse{
+		Output:            output,
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}, nil
+}
+
+// sheetsRows normalizes config values to a list of rows.
+func sheetsRows(raw json.RawMessage) ([][]interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, fmt.Errorf("values is required")
+	}
+	var cells []interface{}
+	if err := json.Unmarshal(raw, &cells); err != nil || len(cells) == 0 {
+		return nil, errInvalidSheetsValues
+	}
+
+	rows := make([][]interface{}, 0, len(cells))
+	for _, cell := range cells {
+		row, ok := cell.([]interface{})
+		if !ok {
+			// A flat list is a single row; a mix of rows and cells is neither.
+			if len(rows) > 0 {
+				return nil, errInvalidSheetsValues
+			}
+			return [][]interface{}{cells}, validateSheetsRow(cells)
+		}
+		if err := validateSheetsRow(row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// validateSheetsRow rejects rows with nested values, which a cell cannot hold.
+func validateSheetsRow(row []interface{}) error {
+	for _, cell := range row {
+		switch cell.(type) {
+		case []interface{}, map[string]interface{}:
+			return errInvalidSheetsValues
+		}
+	}
+	return nil
+}
+
+// sheetsAccessToken returns the bearer token for the node's credentials.
+func sheetsAccessToken(ctx context.Context, config SheetsConfig) (string, error) {
+	if config.AccessToken != "" {
+		return config.AccessToken, nil
+	}
+
+	var creds *google.Credentials
+	var err error
+	if config.CredentialsJSON != "" {
+		creds, err = google.CredentialsFromJSONWithType(ctx, []byte(config.CredentialsJSON), google.ServiceAccount, sheetsScope)
+	} else {
+		creds, err = google.FindDefaultCredentials(ctx, sheetsScope)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errInvalidGoogleCredentials, err)
+	}
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// sheetsAuthError reports credentials that could not produce a token. Bad or
+// rejected credentials fail the same way on every attempt; a token endpoint
+// that could not be reached or failed on its side may recover.
+func sheetsAuthError(req *ExecuteRequest, operation string, err error, logs []LogEntry, start time.Time) *ExecuteResponse {
+	errorType := ErrorTypeRetryable
+	var retrieveErr *oauth2.RetrieveError
+	switch {
+	case errors.As(err, &retrieveErr):
+		if retrieveErr.Response != nil && retrieveErr.Response.StatusCode < 500 {
+			errorType = ErrorTypeNonRetryable
+		}
+	case errors.Is(err, errInvalidGoogleCredentials):
+		errorType = ErrorTypeNonRetryable
+	}
+
+	attempt := newConnectorAttempt(req, "google_sheets", operation, "google", "client_error", start, 0)
+	attempt.ErrorCode = "UNAUTHORIZED"
+	attempt.ErrorMessage = err.Error()
+	return &ExecuteResponse{
+		Error: &ExecutionError{
+			Message: fmt.Sprintf("failed to authenticate with Google: %v", err),
+			Type:    errorType,
+		},
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}
+}
+
+// sheetsErrorMessage extracts the message of a Google API error body.
+func sheetsErrorMessage(body []byte) string {
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error.Message != "" {
+		return apiErr.Error.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// sheetsOutput builds the node output from the API response: the range and
+// the number of rows written, read or cleared.
+func sheetsOutput(config SheetsConfig, body []byte) (json.RawMessage, error) {
+	output := map[string]interface{}{
+		"success":        true,
+		"operation":      config.Operation,
+		"spreadsheet_id": config.SpreadsheetID,
+	}
+
+	switch config.Operation {
+	case "append_row", "update_range":
+		var update struct {
+			UpdatedRange   string `json:"updatedRange"`
+			UpdatedRows    int64  `json:"updatedRows"`
+			UpdatedColumns int64  `json:"updatedColumns"`
+			UpdatedCells   int64  `json:"updatedCells"`
+		}
+		if config.Operation == "append_row" {
+			var appended struct {
+				TableRange string          `json:"tableRange"`
+				Updates    json.RawMessage `json:"updates"`
+			}
+			if err := json.Unmarshal(body, &appended); err != nil {
+				return nil, fmt.Errorf("failed to decode Google Sheets response: %w", err)
+			}
+			output["table_range"] = appended.TableRange
+			body = appended.Updates
+		}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &update); err != nil {
+				return nil, fmt.Errorf("failed to decode Google Sheets response: %w", err)
+			}
+		}
+		output["updated_range"] = update.UpdatedRange
+		output["updated_rows"] = update.UpdatedRows
+		output["updated_columns"] = update.UpdatedColumns
+		output["updated_cells"] = update.UpdatedCells
+	case "read_range":
+		var read struct {
+			Range  string          `json:"range"`
+			Values [][]interface{} `json:"values"`
+		}
+		if err := json.Unmarshal(body, &read); err != nil {
+			return nil, fmt.Errorf("failed to decode Google Sheets response: %w", err)
+		}
+		if read.Values == nil {
+			read.Values = [][]interface{}{}
+		}
+		output["range"] = read.Range
+		output["values"] = read.Values
+		output["row_count"] = len(read.Values)
+	case "clear_range":
+		var cleared struct {
+			ClearedRange string `json:"clearedRange"`
+		}
+		if err := json.Unmarshal(body, &cleared); err != nil {
+			return nil, fmt.Errorf("failed to decode Google Sheets response: %w", err)
+		}
+		output["cleared_range"] = cleared.ClearedRange
+	}
+
+	return json.Marshal(output)
+}
@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSheetsExecutorOperations(t *testing.T) {
+	t.Parallel()
+
+	status := http.StatusOK
+	var method, path, query, auth string
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, query, auth = r.Method, r.URL.EscapedPath(), r.URL.RawQuery, r.Header.Get("Authorization")
+		received = nil
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+		switch {
+		case status >= 400:
+			_, _ = w.Write([]byte(`{"error":{"code":403,"message":"The caller does not have permission","status":"PERMISSION_DENIED"}}`))
+		case method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"range":"Orders!A1:B2","majorDimension":"ROWS","values":[["id","total"],["1","42"]]}`))
+		case method == http.MethodPut:
+			_, _ = w.Write([]byte(`{"updatedRange":"Orders!A2:B3","updatedRows":2,"updatedColumns":2,"updatedCells":4}`))
+		default:
+			_, _ = w.Write([]byte(`{"tableRange":"Orders!A1:B2","updates":{"updatedRange":"Orders!A3:B3","updatedRows":1,"updatedColumns":2,"updatedCells":2}}`))
+		}
+	}))
+	defer server.Close()
+
+	executor := NewSheetsExecutor()
+	executor.client = server.Client()
+	executor.baseURL = server.URL
+
+	run := func(config string) (*ExecuteResponse, map[string]interface{}) {
+		resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+			NodeType: "google_sheets",
+			NodeID:   "sheet",
+			Config:   json.RawMessage(config),
+			Attempt:  1,
+		})
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		var output map[string]interface{}
+		_ = json.Unmarshal(resp.Output, &output)
+		return resp, output
+	}
+
+	// A flat list of values appends a single row.
+	resp, output := run(`{"operation":"append_row","spreadsheet_id":"sheet-1","range":"Orders!A1","values":["3",99],"access_token":"tok"}`)
+	if resp.Error != nil {
+		t.Fatalf("append failed: %+v", resp.Error)
+	}
+	if method != http.MethodPost || path != "/sheet-1/values/Orders%21A1:append" || auth != "Bearer tok" {
+		t.Fatalf("append sent %s %s with %q", method, path, auth)
+	}
+	if query != "insertDataOption=INSERT_ROWS&valueInputOption=USER_ENTERED" {
+		t.Fatalf("append query = %s", query)
+	}
+	if rows, _ := received["values"].([]interface{}); len(rows) != 1 {
+		t.Fatalf("append sent values %v, want one row", received["values"])
+	}
+	if output["updated_range"] != "Orders!A3:B3" || output["updated_rows"] != float64(1) {
+		t.Fatalf("append output = %v", output)
+	}
+
+	resp, output = run(`{"operation":"update_range","spreadsheet_id":"sheet-1","range":"Orders!A2:B3","values":[["1","42"],["2","7"]],"value_input_option":"RAW","access_token":"tok"}`)
+	if resp.Error != nil || method != http.MethodPut || query != "valueInputOption=RAW" || output["updated_rows"] != float64(2) {
+		t.Fatalf("update = %+v, %s ?%s, output %v", resp.Error, method, query, output)
+	}
+
+	resp, output = run(`{"operation":"read_range","spreadsheet_id":"sheet-1","range":"Orders!A1:B2","access_token":"tok"}`)
+	if resp.Error != nil || method != http.MethodGet || output["row_count"] != float64(2) {
+		t.Fatalf("read = %+v, %s, output %v", resp.Error, method, output)
+	}
+
+	// Permission errors fail without retrying; quota and server errors retry.
+	for _, tc := range []struct {
+		status int
+		want   string
+	}{
+		{http.StatusForbidden, ErrorTypeNonRetryable},
+		{http.StatusTooManyRequests, ErrorTypeRetryable},
+		{http.StatusServiceUnavailable, ErrorTypeRetryable},
+	} {
+		status = tc.status
+		resp, _ := run(`{"operation":"clear_range","spreadsheet_id":"sheet-1","range":"Orders!A2:B","access_token":"tok"}`)
+		if resp.Error == nil || resp.Error.Type != tc.want {
+			t.Fatalf("status %d error = %+v, want %s", tc.status, resp.Error, tc.want)
+		}
+	}
+
+	// A malformed service account key is rejected before any request.
+	status = http.StatusOK
+	resp, _ = run(`{"operation":"read_range","spreadsheet_id":"sheet-1","range":"A1","credentials_json":"{}"}`)
+	if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("bad credentials error = %+v, want non-retryable", resp.Error)
+	}
+}
+
+func TestSheetsRows(t *testing.T) {
+	rows, err := sheetsRows(json.RawMessage(`[["a",1],["b",2]]`))
+	if err != nil || len(rows) != 2 || len(rows[1]) != 2 {
+		t.Fatalf("rows = %v, %v", rows, err)
+	}
+	for _, raw := range []string{`[]`, `"a"`, `[["a"],"b"]`, `[{"a":1}]`} {
+		if _, err := sheetsRows(json.RawMessage(raw)); !errors.Is(err, errInvalidSheetsValues) {
+			t.Errorf("sheetsRows(%s) = %v, want errInvalidSheetsValues", raw, err)
+		}
+	}
+}
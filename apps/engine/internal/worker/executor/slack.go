@@ -13,8 +13,11 @@ import (
 
 // SlackExecutor handles Slack message sending.
 type SlackExecutor struct {
+	BaseExecutor
+
 	client       *http.Client
 	defaultToken string
+	limiter      *ConnectorRateLimiter
 }
 
 // SlackConfig represents the configuration for a Slack node.
@@ -118,6 +121,7 @@ func NewSlackExecutor() *SlackExecutor {
 			Transport: transport,
 		},
 		defaultToken: defaultToken,
+		limiter:      DefaultConnectorRateLimiter(),
 	}
 }
 
@@ -127,6 +131,12 @@ func (e *SlackExecutor) WithDefaultToken(token string) *SlackExecutor {
 	return e
 }
 
+// WithRateLimiter sets the limiter used to throttle Slack calls.
+func (e *SlackExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *SlackExecutor {
+	e.limiter = limiter
+	return e
+}
+
 func (e *SlackExecutor) NodeType() string {
 	return "slack"
 }
@@ -181,8 +191,17 @@ func (e *SlackExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 		}, nil
 	}
 
+	operation := "chat.postMessage"
+	if config.WebhookURL != "" {
+		operation = "webhook"
+	}
+
+	waited, err := e.limiter.Acquire(ctx, "slack")
+	if err != nil {
+		return rateLimitedResponse(req, "slack", operation, "slack", waited, err, logs, start), nil
+	}
+
 	var slackResp SlackResponse
-	var err error
 
 	if config.WebhookURL != "" {
 		slackResp, err = e.sendWebhook(ctx, &config, &logs)
@@ -191,13 +210,17 @@ func (e *SlackExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 	}
 
 	if err != nil {
+		attempt := newConnectorAttempt(req, "slack", operation, "slack", "network_error", start, waited)
+		attempt.ErrorCode = "SLACK_REQUEST_FAILED"
+		attempt.ErrorMessage = err.Error()
 		return &ExecuteResponse{
 			Error: &ExecutionError{
 				Message: fmt.Sprintf("Slack API error: %v", err),
 				Type:    ErrorTypeRetryable,
 			},
-			Logs:     logs,
-			Duration: time.Since(start),
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
 		}, nil
 	}
 
@@ -216,13 +239,16 @@ func (e *SlackExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 			}
 		}
 
+		attempt := newConnectorAttempt(req, "slack", operation, "slack", "client_error", start, waited)
+		attempt.ErrorCode = slackResp.Error
 		return &ExecuteResponse{
 			Error: &ExecutionError{
 				Message: fmt.Sprintf("Slack error: %s", slackResp.Error),
 				Type:    errorType,
 			},
-			Logs:     logs,
-			Duration: time.Since(start),
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
 		}, nil
 	}
 
@@ -245,9 +271,10 @@ func (e *SlackExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Exec
 	}
 
 	return &ExecuteResponse{
-		Output:   output,
-		Logs:     logs,
-		Duration: time.Since(start),
+		Output:            output,
+		ConnectorAttempts: []ConnectorAttempt{newConnectorAttempt(req, "slack", operation, "slack", "success", start, waited)},
+		Logs:              logs,
+		Duration:          time.Since(start),
 	}, nil
 }
 
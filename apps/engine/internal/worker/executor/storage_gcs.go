@@ -0,0 +1,298 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+const (
+	// defaultGCSMaxKeys is the number of objects a list returns when max_keys
+	// is not set.
+	defaultGCSMaxKeys = 1000
+	// maxGCSDownloadBytes caps how much of an object a download reads into
+	// the node output.
+	maxGCSDownloadBytes = 100 * 1024 * 1024 // 100MB
+	// maxGCSObjectNameBytes is the longest object name GCS accepts.
+	maxGCSObjectNameBytes = 1024
+)
+
+// errInvalidGCSRequest is returned for gcs operations that cannot succeed as
+// configured, such as a missing bucket or an unsafe key.
+var errInvalidGCSRequest = errors.New("invalid gcs request")
+
+// executeGCS runs a storage operation against a Google Cloud Storage bucket.
+// Credentials come from credentials_json, a service account key, or else from
+// Application Default Credentials.
+func (e *StorageExecutor) executeGCS(ctx context.Context, config StorageConfig, logs *[]LogEntry) (StorageResponse, error) {
+	var response StorageResponse
+
+	if config.Bucket == "" {
+		return response, fmt.Errorf("%w: bucket is required", errInvalidGCSRequest)
+	}
+	// List addresses a prefix; every other operation addresses one object.
+	if config.Operation != "list" {
+		if err := validateGCSObjectName(config.Key); err != nil {
+			return response, err
+		}
+	}
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	opts := []option.ClientOption{}
+	if config.CredentialsJSON != "" {
+		opts = append(opts, option.WithAuthCredentialsJSON(option.ServiceAccount, []byte(config.CredentialsJSON)))
+	}
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return response, fmt.Errorf("failed to create gcs client: %w", err)
+	}
+	defer client.Close()
+
+	bucket := client.Bucket(config.Bucket)
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "DEBUG",
+		Message:   fmt.Sprintf("GCS storage %s: gs://%s/%s", config.Operation, config.Bucket, config.Key),
+	})
+
+	switch config.Operation {
+	case "upload", "write":
+		return e.gcsWrite(ctx, bucket.Object(config.Key), config, logs)
+	case "download", "read":
+		return e.gcsRead(ctx, bucket.Object(config.Key), config, logs)
+	case "delete":
+		return e.gcsDelete(ctx, bucket.Object(config.Key), logs)
+	case "list":
+		return e.gcsList(ctx, bucket, config, logs)
+	case "exists":
+		return e.gcsExists(ctx, bucket.Object(config.Key))
+	default:
+		return response, fmt.Errorf("unsupported gcs operation: %s", config.Operation)
+	}
+}
+
+func (e *StorageExecutor) gcsWrite(ctx context.Context, obj *storage.ObjectHandle, config StorageConfig, logs *[]LogEntry) (StorageResponse, error) {
+	var response StorageResponse
+
+	var data []byte
+	if config.Content != "" {
+		data = []byte(config.Content)
+	} else if config.ContentB64 != "" {
+		var err error
+		data, err = decodeBase64(config.ContentB64)
+		if err != nil {
+			return response, fmt.Errorf("invalid base64 content: %w", err)
+		}
+	} else if config.LocalPath != "" {
+		var err error
+		data, err = os.ReadFile(config.LocalPath)
+		if err != nil {
+			return response, fmt.Errorf("failed to read source file: %w", err)
+		}
+	} else {
+		return response, fmt.Errorf("content, content_base64, or local_path is required for write")
+	}
+
+	w := obj.NewWriter(ctx)
+	w.ContentType = config.ContentType
+	if config.Public {
+		w.PredefinedACL = "publicRead"
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return response, fmt.Errorf("failed to upload object: %w", err)
+	}
+	// The upload is only committed, and its errors only reported, on Close.
+	if err := w.Close(); err != nil {
+		return response, fmt.Errorf("failed to upload object: %w", err)
+	}
+
+	response.Key = config.Key
+	response.Size = int64(len(data))
+	response.ContentType = w.Attrs().ContentType
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Uploaded %d bytes to gs://%s/%s", len(data), obj.BucketName(), obj.ObjectName()),
+	})
+
+	return response, nil
+}
+
+func (e *StorageExecutor) gcsRead(ctx context.Context, obj *storage.ObjectHandle, config StorageConfig, logs *[]LogEntry) (StorageResponse, error) {
+	var response StorageResponse
+
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		return response, fmt.Errorf("failed to read object: %w", err)
+	}
+	defer r.Close()
+
+	if r.Attrs.Size > maxGCSDownloadBytes {
+		return response, fmt.Errorf("%w: object is %d bytes, over the %d bytes download limit", errInvalidGCSRequest, r.Attrs.Size, maxGCSDownloadBytes)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, maxGCSDownloadBytes+1))
+	if err != nil {
+		return response, fmt.Errorf("failed to read object: %w", err)
+	}
+	if len(data) > maxGCSDownloadBytes {
+		return response, fmt.Errorf("%w: object exceeds the %d bytes download limit", errInvalidGCSRequest, maxGCSDownloadBytes)
+	}
+
+	if config.LocalPath != "" {
+		if err := os.WriteFile(config.LocalPath, data, 0644); err != nil {
+			return response, fmt.Errorf("failed to write to destination: %w", err)
+		}
+	} else {
+		response.Content = string(data)
+	}
+
+	response.Key = config.Key
+	response.Size = int64(len(data))
+	response.ContentType = r.Attrs.ContentType
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Downloaded %d bytes from gs://%s/%s", len(data), obj.BucketName(), obj.ObjectName()),
+	})
+
+	return response, nil
+}
+
+func (e *StorageExecutor) gcsDelete(ctx context.Context, obj *storage.ObjectHandle, logs *[]LogEntry) (StorageResponse, error) {
+	var response StorageResponse
+
+	if err := obj.Delete(ctx); err != nil {
+		return response, fmt.Errorf("failed to delete object: %w", err)
+	}
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Deleted gs://%s/%s", obj.BucketName(), obj.ObjectName()),
+	})
+
+	return response, nil
+}
+
+func (e *StorageExecutor) gcsList(ctx context.Context, bucket *storage.BucketHandle, config StorageConfig, logs *[]LogEntry) (StorageResponse, error) {
+	var response StorageResponse
+
+	maxKeys := config.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = defaultGCSMaxKeys
+	}
+
+	it := bucket.Objects(ctx, &storage.Query{Prefix: config.Prefix, Delimiter: config.Delimiter})
+	response.Files = make([]StorageFileInfo, 0)
+	for len(response.Files) < maxKeys {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return response, fmt.Errorf("failed to list objects: %w", err)
+		}
+		// With a delimiter, "directories" come back as bare prefixes.
+		if attrs.Prefix != "" {
+			response.Files = append(response.Files, StorageFileInfo{Key: attrs.Prefix})
+			continue
+		}
+		response.Files = append(response.Files, StorageFileInfo{
+			Key:          attrs.Name,
+			Size:         attrs.Size,
+			LastModified: attrs.Updated.Format(time.RFC3339),
+		})
+	}
+
+	*logs = append(*logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Listed %d objects in gs://%s/%s", len(response.Files), config.Bucket, config.Prefix),
+	})
+
+	return response, nil
+}
+
+func (e *StorageExecutor) gcsExists(ctx context.Context, obj *storage.ObjectHandle) (StorageResponse, error) {
+	var response StorageResponse
+
+	attrs, err := obj.Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		response.Success = false
+		return response, nil
+	}
+	if err != nil {
+		return response, fmt.Errorf("failed to stat object: %w", err)
+	}
+
+	response.Success = true
+	response.Size = attrs.Size
+	response.ContentType = attrs.ContentType
+
+	return response, nil
+}
+
+// validateGCSObjectName rejects keys that could address something other than
+// the object named, mirroring the local provider's traversal check: relative
+// segments, leading slashes and control characters are refused.
+func validateGCSObjectName(key string) error {
+	if key == "" {
+		return fmt.Errorf("%w: key is required", errInvalidGCSRequest)
+	}
+	if len(key) > maxGCSObjectNameBytes {
+		return fmt.Errorf("%w: key exceeds %d bytes", errInvalidGCSRequest, maxGCSObjectNameBytes)
+	}
+	if strings.HasPrefix(key, "/") {
+		return fmt.Errorf("%w: key %q must not start with /", errInvalidGCSRequest, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("%w: key %q contains a relative path segment", errInvalidGCSRequest, key)
+		}
+	}
+	if strings.IndexFunc(key, unicode.IsControl) >= 0 {
+		return fmt.Errorf("%w: key %q contains control characters", errInvalidGCSRequest, key)
+	}
+	return nil
+}
+
+// gcsErrorType classifies GCS errors. Missing buckets and objects, invalid
+// requests and those the API refuses need a config change; throttling and
+// server errors are transient.
+func gcsErrorType(err error) string {
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) || errors.Is(err, errInvalidGCSRequest) {
+		return ErrorTypeNonRetryable
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code == http.StatusTooManyRequests || apiErr.Code == http.StatusRequestTimeout || apiErr.Code >= 500:
+			return ErrorTypeRetryable
+		case apiErr.Code >= 400:
+			return ErrorTypeNonRetryable
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTypeTimeout
+	}
+	return ErrorTypeRetryable
+}
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+)
+
+func TestValidateGCSObjectNameRejectsTraversal(t *testing.T) {
+	for _, key := range []string{"", "/etc/passwd", "../secret", "reports/../../secret", "reports/./a.csv", "a\x00b"} {
+		if err := validateGCSObjectName(key); !errors.Is(err, errInvalidGCSRequest) {
+			t.Errorf("validateGCSObjectName(%q) = %v, want errInvalidGCSRequest", key, err)
+		}
+	}
+	for _, key := range []string{"a.csv", "reports/2026/01/a..b.csv"} {
+		if err := validateGCSObjectName(key); err != nil {
+			t.Errorf("validateGCSObjectName(%q) = %v, want nil", key, err)
+		}
+	}
+}
+
+func TestGCSErrorType(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("failed to read object: %w", storage.ErrObjectNotExist), ErrorTypeNonRetryable},
+		{storage.ErrBucketNotExist, ErrorTypeNonRetryable},
+		{&googleapi.Error{Code: http.StatusForbidden}, ErrorTypeNonRetryable},
+		{&googleapi.Error{Code: http.StatusTooManyRequests}, ErrorTypeRetryable},
+		{fmt.Errorf("failed to upload object: %w", &googleapi.Error{Code: http.StatusServiceUnavailable}), ErrorTypeRetryable},
+		{context.DeadlineExceeded, ErrorTypeTimeout},
+		{errors.New("connection reset by peer"), ErrorTypeRetryable},
+	}
+	for _, tc := range cases {
+		if got := gcsErrorType(tc.err); got != tc.want {
+			t.Errorf("gcsErrorType(%v) = %s, want %s", tc.err, got, tc.want)
+		}
+	}
+}
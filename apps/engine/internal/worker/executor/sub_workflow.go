@@ -0,0 +1,202 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/linkflow/engine/api/gen/linkflow/api/v1"
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ChildWorkflowStarter starts a child workflow for a pending activity.
+// History completes the activity with the child's result when it closes.
+type ChildWorkflowStarter interface {
+	StartChildWorkflowForActivity(ctx context.Context, taskToken string, attrs *historyv1.StartChildWorkflowExecutionCommandAttributes) (*historyv1.StartChildWorkflowForActivityResponse, error)
+}
+
+// SubWorkflowExecutor calls another workflow and waits for its result. The
+// node stays pending while history runs the workflow as a child of this
+// execution; it completes with the child's output or fails with the child's
+// failure. A node that times out first cancels the child according to its
+// parent_close_policy, as does this execution closing.
+type SubWorkflowExecutor struct {
+	starter ChildWorkflowStarter
+}
+
+// SubWorkflowConfig represents the configuration for a sub_workflow node.
+type SubWorkflowConfig struct {
+	Workflow     WorkflowDefinition `json:"workflow"`
+	WorkflowID   string             `json:"workflow_id"`
+	WorkflowType string             `json:"workflow_type"`
+	TaskQueue    string             `json:"task_queue"`
+	Timeout      int                `json:"timeout"` // seconds (0 = worker default)
+	// ParentClosePolicy is "terminate" (default), "abandon" or
+	// "request_cancel": what happens to the child if the node times out or
+	// this workflow closes first.
+	ParentClosePolicy string `json:"parent_close_policy"`
+}
+
+// parentClosePolicies maps each parent_close_policy name to the policy the
+// child is started with. An empty name leaves it to history's default.
+var parentClosePolicies = map[string]historyv1.ParentClosePolicy{
+	"":               historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_UNSPECIFIED,
+	"terminate":      historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_TERMINATE,
+	"abandon":        historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_ABANDON,
+	"request_cancel": historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_REQUEST_CANCEL,
+}
+
+// NewSubWorkflowExecutor creates a sub_workflow executor that starts child
+// workflows through starter, normally the worker's history client.
+func NewSubWorkflowExecutor(starter ChildWorkflowStarter) *SubWorkflowExecutor {
+	return &SubWorkflowExecutor{starter: starter}
+}
+
+func (e *SubWorkflowExecutor) NodeType() string {
+	return "sub_workflow"
+}
+
+// UsesJobPayload reports that the child inherits the parent's credentials,
+// variables and deterministic context.
+func (e *SubWorkflowExecutor) UsesJobPayload() bool {
+	return true
+}
+
+var subWorkflowInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["workflow"],
+  "properties": {
+    "workflow": {
+      "type": "object",
+      "required": ["nodes"],
+      "properties": {
+        "nodes": {"type": "array", "minItems": 1},
+        "edges": {"type": "array"},
+        "settings": {"type": "object"}
+      }
+    },
+    "workflow_id": {"type": "string", "description": "Child workflow ID, defaults to <parent workflow ID>-<node ID>"},
+    "workflow_type": {"type": "string", "default": "sub_workflow"},
+    "task_queue": {"type": "string", "description": "Defaults to the parent's task queue"},
+    "timeout": {"type": "integer", "minimum": 0, "description": "Seconds to wait for the child, 0 for the worker default"},
+    "parent_close_policy": {"type": "string", "enum": ["terminate", "abandon", "request_cancel"], "default": "terminate"}
+  }
+}`)
+
+var subWorkflowOutputSchema = json.RawMessage(`{
+  "description": "Result the child workflow completed with"
+}`)
+
+func (e *SubWorkflowExecutor) InputSchema() json.RawMessage {
+	return subWorkflowInputSchema
+}
+
+func (e *SubWorkflowExecutor) OutputSchema() json.RawMessage {
+	return subWorkflowOutputSchema
+}
+
+func (e *SubWorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: message,
+				Type:    errorType,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config SubWorkflowConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse sub_workflow config: %v", err), ErrorTypeNonRetryable)
+	}
+	if len(config.Workflow.Nodes) == 0 {
+		return failed("workflow must have at least one node", ErrorTypeNonRetryable)
+	}
+	if config.Timeout < 0 {
+		return failed("timeout must not be negative", ErrorTypeNonRetryable)
+	}
+	if _, ok := parentClosePolicies[config.ParentClosePolicy]; !ok {
+		return failed(fmt.Sprintf("unknown parent_close_policy: %s", config.ParentClosePolicy), ErrorTypeNonRetryable)
+	}
+	if req.Job == nil {
+		return failed("execution payload is not available", ErrorTypeRetryable)
+	}
+
+	attrs, err := childWorkflowAttributes(req.NodeID, config, req.Input, req.Job)
+	if err != nil {
+		return failed(err.Error(), ErrorTypeNonRetryable)
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Node %s waiting for sub-workflow %s", req.NodeID, attrs.GetWorkflowType().GetName()),
+	})
+
+	return &ExecuteResponse{
+		Pending: &PendingActivity{
+			ScheduleToCloseTimeout: time.Duration(config.Timeout) * time.Second,
+			OnPending: func(ctx context.Context, taskToken string) error {
+				_, err := e.starter.StartChildWorkflowForActivity(ctx, taskToken, attrs)
+				return err
+			},
+		},
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// childWorkflowAttributes builds the StartChildWorkflowExecution attributes
+// for a sub_workflow node. The child inherits the parent's credentials,
+// variables and deterministic context, and receives the node input as its
+// trigger data.
+func childWorkflowAttributes(nodeID string, config SubWorkflowConfig, input json.RawMessage, parent *JobPayload) (*historyv1.StartChildWorkflowExecutionCommandAttributes, error) {
+	var triggerData map[string]interface{}
+	if len(input) > 0 {
+		if err := json.Unmarshal(input, &triggerData); err != nil {
+			triggerData = map[string]interface{}{"input": input}
+		}
+	}
+
+	childPayload, err := json.Marshal(JobPayload{
+		ExecutionID:   parent.ExecutionID,
+		WorkflowID:    parent.WorkflowID,
+		WorkspaceID:   parent.WorkspaceID,
+		Workflow:      config.Workflow,
+		TriggerData:   triggerData,
+		Credentials:   parent.Credentials,
+		Variables:     parent.Variables,
+		Deterministic: parent.Deterministic,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal child workflow payload: %w", err)
+	}
+
+	workflowType := config.WorkflowType
+	if workflowType == "" {
+		workflowType = "sub_workflow"
+	}
+
+	attrs := &historyv1.StartChildWorkflowExecutionCommandAttributes{
+		NodeId:       nodeID,
+		WorkflowId:   config.WorkflowID,
+		WorkflowType: &apiv1.WorkflowType{Name: workflowType},
+		TaskQueue:    config.TaskQueue,
+		Input: &commonv1.Payloads{
+			Payloads: []*commonv1.Payload{{Data: childPayload}},
+		},
+		ParentClosePolicy: parentClosePolicies[config.ParentClosePolicy],
+	}
+	if config.Timeout > 0 {
+		attrs.ExecutionTimeout = durationpb.New(time.Duration(config.Timeout) * time.Second)
+	}
+	return attrs, nil
+}
@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+)
+
+type recordingChildStarter struct {
+	token string
+	attrs *historyv1.StartChildWorkflowExecutionCommandAttributes
+}
+
+func (s *recordingChildStarter) StartChildWorkflowForActivity(_ context.Context, taskToken string, attrs *historyv1.StartChildWorkflowExecutionCommandAttributes) (*historyv1.StartChildWorkflowForActivityResponse, error) {
+	s.token = taskToken
+	s.attrs = attrs
+	return &historyv1.StartChildWorkflowForActivityResponse{NodeId: attrs.GetNodeId()}, nil
+}
+
+func TestSubWorkflowExecutorStartsChildWhenPending(t *testing.T) {
+	starter := &recordingChildStarter{}
+	exec := NewSubWorkflowExecutor(starter)
+
+	resp, err := exec.Execute(context.Background(), &ExecuteRequest{
+		NodeID: "charge",
+		Config: json.RawMessage(`{
+			"workflow": {"nodes": [{"id": "n1", "type": "http"}]},
+			"workflow_type": "billing",
+			"timeout": 60,
+			"parent_close_policy": "request_cancel"
+		}`),
+		Input: json.RawMessage(`{"amount": 10}`),
+		Job: &JobPayload{
+			ExecutionID: 7,
+			Credentials: map[string]interface{}{"stripe": "secret"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected failure: %s", resp.Error.Message)
+	}
+	if resp.Pending == nil || resp.Pending.ScheduleToCloseTimeout != time.Minute {
+		t.Fatalf("expected a pending result with a 1m timeout, got %+v", resp.Pending)
+	}
+	if starter.attrs != nil {
+		t.Fatal("child started before history recorded the node as pending")
+	}
+
+	if err := resp.Pending.OnPending(context.Background(), "token-1"); err != nil {
+		t.Fatalf("on pending: %v", err)
+	}
+	if starter.token != "token-1" {
+		t.Fatalf("child started for token %q", starter.token)
+	}
+	attrs := starter.attrs
+	if attrs.GetNodeId() != "charge" || attrs.GetWorkflowType().GetName() != "billing" || attrs.GetExecutionTimeout().AsDuration() != time.Minute {
+		t.Fatalf("unexpected child attributes %+v", attrs)
+	}
+	if policy := attrs.GetParentClosePolicy(); policy != historyv1.ParentClosePolicy_PARENT_CLOSE_POLICY_REQUEST_CANCEL {
+		t.Fatalf("parent close policy = %v", policy)
+	}
+	payload := attrs.GetInput().GetPayloads()[0]
+	var child JobPayload
+	if err := json.Unmarshal(payload.GetData(), &child); err != nil {
+		t.Fatalf("decode child payload: %v", err)
+	}
+	if child.ExecutionID != 7 || child.Credentials["stripe"] != "secret" || child.TriggerData["amount"] != float64(10) {
+		t.Fatalf("child payload does not carry the parent's context: %+v", child)
+	}
+}
+
+func TestSubWorkflowExecutorRejectsInvalidConfig(t *testing.T) {
+	exec := NewSubWorkflowExecutor(&recordingChildStarter{})
+	for name, config := range map[string]string{
+		"no nodes":       `{"workflow": {"nodes": []}}`,
+		"negative":       `{"workflow": {"nodes": [{"id": "n1"}]}, "timeout": -1}`,
+		"unknown policy": `{"workflow": {"nodes": [{"id": "n1"}]}, "parent_close_policy": "detach"}`,
+	} {
+		resp, err := exec.Execute(context.Background(), &ExecuteRequest{
+			NodeID: "charge",
+			Config: json.RawMessage(config),
+			Job:    &JobPayload{},
+		})
+		if err != nil {
+			t.Fatalf("%s: execute: %v", name, err)
+		}
+		if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+			t.Fatalf("%s: expected a non-retryable failure, got %+v", name, resp)
+		}
+	}
+}
+
+func TestSubWorkflowNodeWithInvalidConfigFailsWorkflow(t *testing.T) {
+	decider := NewWorkflowExecutor(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	payload, _ := json.Marshal(JobPayload{Workflow: WorkflowDefinition{Nodes: []Node{{
+		ID:   "child",
+		Type: "sub_workflow",
+		Data: json.RawMessage(`{"config": {"workflow": {"nodes": []}}}`),
+	}}}})
+	events := []*historyv1.HistoryEvent{{
+		EventId:   1,
+		EventType: commonv1.EventType_EVENT_TYPE_EXECUTION_STARTED,
+		Attributes: &historyv1.HistoryEvent_ExecutionStartedAttributes{
+			ExecutionStartedAttributes: &historyv1.ExecutionStartedEventAttributes{
+				Input: &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: payload}}},
+			},
+		},
+	}}
+
+	// The node is scheduled like any activity, so SubWorkflowExecutor can
+	// reject its config with a failed result instead of it never starting.
+	commands, err := decider.Replay(&ExecuteRequest{WorkflowID: "parent"}, events)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(commands) != 1 || commands[0].GetScheduleActivityTaskAttributes().GetNodeId() != "child" {
+		t.Fatalf("commands = %v, want the sub_workflow node scheduled", commands)
+	}
+
+	events = append(events,
+		&historyv1.HistoryEvent{
+			EventId:   2,
+			EventType: commonv1.EventType_EVENT_TYPE_NODE_SCHEDULED,
+			Attributes: &historyv1.HistoryEvent_NodeScheduledAttributes{
+				NodeScheduledAttributes: &historyv1.NodeScheduledEventAttributes{NodeId: "child"},
+			},
+		},
+		&historyv1.HistoryEvent{
+			EventId:   3,
+			EventType: commonv1.EventType_EVENT_TYPE_NODE_FAILED,
+			Attributes: &historyv1.HistoryEvent_NodeFailedAttributes{
+				NodeFailedAttributes: &historyv1.NodeFailedEventAttributes{ScheduledEventId: 2},
+			},
+		},
+	)
+	commands, err = decider.Replay(&ExecuteRequest{WorkflowID: "parent"}, events)
+	if err != nil {
+		t.Fatalf("replay after failure: %v", err)
+	}
+	if len(commands) != 1 || commands[0].GetCommandType() != historyv1.CommandType_COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION {
+		t.Fatalf("commands = %v, want the workflow failed", commands)
+	}
+}
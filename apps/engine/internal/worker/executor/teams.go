@@ -0,0 +1,275 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// TeamsExecutor handles Microsoft Teams incoming webhook messages.
+type TeamsExecutor struct {
+	BaseExecutor
+
+	client         *http.Client
+	defaultWebhook string
+	limiter        *ConnectorRateLimiter
+}
+
+// TeamsConfig represents the configuration for a Teams node.
+type TeamsConfig struct {
+	WebhookURL string          `json:"webhook_url"` // Incoming webhook URL
+	Title      string          `json:"title"`       // Message title (simple messages only)
+	Text       string          `json:"text"`        // Message text (markdown supported)
+	Card       json.RawMessage `json:"card"`        // Adaptive Card content (optional, overrides title/text)
+}
+
+// NewTeamsExecutor creates a new Teams executor with connection pooling.
+func NewTeamsExecutor() *TeamsExecutor {
+	// Configure SSRF-safe transport with connection pooling
+	transport := newSSRFSafeTransport()
+
+	// Get default webhook from environment
+	defaultWebhook := os.Getenv("TEAMS_WEBHOOK_URL")
+
+	return &TeamsExecutor{
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+		defaultWebhook: defaultWebhook,
+		limiter:        DefaultConnectorRateLimiter(),
+	}
+}
+
+// WithDefaultWebhook sets the default webhook URL.
+func (e *TeamsExecutor) WithDefaultWebhook(webhookURL string) *TeamsExecutor {
+	e.defaultWebhook = webhookURL
+	return e
+}
+
+// WithRateLimiter sets the limiter used to throttle Teams calls.
+func (e *TeamsExecutor) WithRateLimiter(limiter *ConnectorRateLimiter) *TeamsExecutor {
+	e.limiter = limiter
+	return e
+}
+
+func (e *TeamsExecutor) NodeType() string {
+	return "teams"
+}
+
+func (e *TeamsExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Starting Teams execution for node %s", req.NodeID),
+	})
+
+	var config TeamsConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("failed to parse Teams config: %v", err),
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	// Apply default webhook
+	if config.WebhookURL == "" {
+		config.WebhookURL = e.defaultWebhook
+	}
+
+	if config.WebhookURL == "" {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: "webhook_url is required",
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	if config.Text == "" && len(config.Card) == 0 {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: "text or card is required",
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	operation := "message"
+	var payload interface{}
+	if len(config.Card) > 0 {
+		if !json.Valid(config.Card) {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: "card must be valid JSON",
+					Type:    ErrorTypeNonRetryable,
+				},
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+		operation = "adaptive_card"
+		payload = map[string]interface{}{
+			"type": "message",
+			"attachments": []map[string]interface{}{
+				{
+					"contentType": "application/vnd.microsoft.card.adaptive",
+					"content":     config.Card,
+				},
+			},
+		}
+	} else {
+		message := map[string]interface{}{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"text":     config.Text,
+		}
+		if config.Title != "" {
+			message["title"] = config.Title
+			message["summary"] = config.Title
+		} else {
+			message["summary"] = config.Text
+		}
+		payload = message
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("failed to marshal payload: %v", err),
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	waited, err := e.limiter.Acquire(ctx, "teams")
+	if err != nil {
+		return rateLimitedResponse(req, "teams", operation, "teams", waited, err, logs, start), nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   "Sending Teams webhook message",
+	})
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("failed to create request: %v", err),
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		attempt := newConnectorAttempt(req, "teams", operation, "teams", "network_error", start, waited)
+		attempt.ErrorCode = "TEAMS_REQUEST_FAILED"
+		attempt.ErrorMessage = err.Error()
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("request failed: %v", err),
+				Type:    ErrorTypeRetryable,
+			},
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
+		}, nil
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1*1024*1024))
+	if err != nil {
+		logs = append(logs, LogEntry{
+			Timestamp: time.Now(),
+			Level:     "WARN",
+			Message:   fmt.Sprintf("failed to read response body: %v", err),
+		})
+	}
+	respText := strings.TrimSpace(string(respBody))
+
+	attempt := newConnectorAttempt(req, "teams", operation, "teams", "success", start, waited)
+	attempt.StatusCode = int32(resp.StatusCode)
+
+	if resp.StatusCode == 429 {
+		attempt.Status = "throttled"
+		attempt.ErrorCode = "RATE_LIMITED"
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: "rate limited by Teams",
+				Type:    ErrorTypeRetryable,
+			},
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
+		}, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errorType := ErrorTypeNonRetryable
+		attempt.Status = "client_error"
+		if resp.StatusCode >= 500 {
+			errorType = ErrorTypeRetryable
+			attempt.Status = "server_error"
+		}
+		attempt.ErrorMessage = respText
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: fmt.Sprintf("Teams error (status %d): %s", resp.StatusCode, respText),
+				Type:    errorType,
+			},
+			ConnectorAttempts: []ConnectorAttempt{attempt},
+			Logs:              logs,
+			Duration:          time.Since(start),
+		}, nil
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   "Teams message sent successfully",
+	})
+
+	// Depending on the webhook type Teams answers with an empty body, "1" or
+	// JSON; the body is passed on only when it is JSON.
+	result := map[string]interface{}{
+		"success":     true,
+		"status_code": resp.StatusCode,
+	}
+	if respText != "" && json.Valid(respBody) {
+		result["response"] = json.RawMessage(respBody)
+	}
+	output, _ := json.Marshal(result)
+
+	return &ExecuteResponse{
+		Output:            output,
+		ConnectorAttempts: []ConnectorAttempt{attempt},
+		Logs:              logs,
+		Duration:          time.Since(start),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTeamsExecutorResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantError string
+		response  string
+	}{
+		{name: "legacy connector ok", status: http.StatusOK, body: "1", response: "1"},
+		{name: "workflow accepted without body", status: http.StatusAccepted},
+		{name: "plain text body", status: http.StatusOK, body: "ok"},
+		{name: "json body", status: http.StatusOK, body: `{"id":"msg-1"}`, response: `{"id":"msg-1"}`},
+		{name: "throttled", status: http.StatusTooManyRequests, body: "slow down", wantError: ErrorTypeRetryable},
+		{name: "bad request", status: http.StatusBadRequest, body: "Invalid webhook request", wantError: ErrorTypeNonRetryable},
+		{name: "redirect", status: http.StatusFound, wantError: ErrorTypeNonRetryable},
+		{name: "server error", status: http.StatusBadGateway, wantError: ErrorTypeRetryable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			executor := NewTeamsExecutor().WithRateLimiter(NewConnectorRateLimiter(nil))
+			executor.client = server.Client()
+			resp, err := executor.Execute(context.Background(), &ExecuteRequest{
+				NodeType: "teams",
+				NodeID:   "notify",
+				Config:   json.RawMessage(`{"webhook_url":"` + server.URL + `","title":"Deploy","text":"done"}`),
+				Attempt:  1,
+			})
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if received["title"] != "Deploy" || received["text"] != "done" {
+				t.Fatalf("sent %v", received)
+			}
+
+			if tt.wantError != "" {
+				if resp.Error == nil || resp.Error.Type != tt.wantError {
+					t.Fatalf("error = %+v, want %s", resp.Error, tt.wantError)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %+v", resp.Error)
+			}
+			var output struct {
+				Success    bool            `json:"success"`
+				StatusCode int             `json:"status_code"`
+				Response   json.RawMessage `json:"response"`
+			}
+			if err := json.Unmarshal(resp.Output, &output); err != nil {
+				t.Fatalf("decode output: %v", err)
+			}
+			if !output.Success || output.StatusCode != tt.status || string(output.Response) != tt.response {
+				t.Fatalf("output = %s", resp.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	throttleKeyPrefix = "linkflow:throttle:"
+
+	throttleModeLeading  = "leading"
+	throttleModeTrailing = "trailing"
+
+	throttleWindowSliding = "sliding"
+	throttleWindowFixed   = "fixed"
+
+	// maxTrailingThrottleWindow bounds how long a trailing throttle node
+	// holds a worker slot waiting for its window to close.
+	maxTrailingThrottleWindow = 15 * time.Minute
+)
+
+// ThrottleExecutor coalesces bursts of events sharing a key so only one per
+// window proceeds. In leading mode the first event of a window is allowed
+// and the rest are suppressed straight away; in trailing mode every event
+// waits for the window to close and only the last one is allowed. A sliding
+// window restarts with every event (a debounce); a fixed window is aligned
+// to multiples of its length on the Redis clock, so all workers agree on it.
+type ThrottleExecutor struct {
+	BaseExecutor
+
+	client *redis.Client
+}
+
+// ThrottleConfig represents the configuration for a throttle node.
+type ThrottleConfig struct {
+	Fields     []string `json:"fields"`      // Input fields (dot notation) forming the key (default: whole input)
+	Scope      string   `json:"scope"`       // Optional key namespace, e.g. to throttle per workflow
+	Window     int      `json:"window"`      // Window length in seconds
+	Mode       string   `json:"mode"`        // "leading" (default) or "trailing"
+	WindowType string   `json:"window_type"` // "sliding" (default) or "fixed"
+}
+
+// ThrottleResponse is the output of a throttle node.
+type ThrottleResponse struct {
+	Allowed bool   `json:"allowed"`
+	Key     string `json:"key"`
+}
+
+var throttleOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["allowed", "key"],
+  "properties": {
+    "allowed": {"type": "boolean", "description": "True for the one event of the window that should proceed"},
+    "key": {"type": "string"}
+  }
+}`)
+
+// NewThrottleExecutor creates a new throttle executor. A Redis client must be
+// set with WithRedis before it can run.
+func NewThrottleExecutor() *ThrottleExecutor {
+	return &ThrottleExecutor{}
+}
+
+// WithRedis sets the Redis client used to track windows. The client's pool
+// is shared with the other Redis-backed executors the worker runs.
+func (e *ThrottleExecutor) WithRedis(client *redis.Client) *ThrottleExecutor {
+	e.client = client
+	return e
+}
+
+func (e *ThrottleExecutor) NodeType() string {
+	return "throttle"
+}
+
+func (e *ThrottleExecutor) OutputSchema() json.RawMessage {
+	return throttleOutputSchema
+}
+
+func (e *ThrottleExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message, errorType string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: errorType},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config ThrottleConfig
+	if len(req.Config) > 0 {
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			return failed(fmt.Sprintf("failed to parse throttle config: %v", err), ErrorTypeNonRetryable)
+		}
+	}
+	if config.Window <= 0 {
+		return failed("window must be a positive number of seconds", ErrorTypeNonRetryable)
+	}
+	window := time.Duration(config.Window) * time.Second
+	if config.Mode == "" {
+		config.Mode = throttleModeLeading
+	}
+	if config.Mode != throttleModeLeading && config.Mode != throttleModeTrailing {
+		return failed(fmt.Sprintf("unsupported mode %q; use leading or trailing", config.Mode), ErrorTypeNonRetryable)
+	}
+	if config.WindowType == "" {
+		config.WindowType = throttleWindowSliding
+	}
+	if config.WindowType != throttleWindowSliding && config.WindowType != throttleWindowFixed {
+		return failed(fmt.Sprintf("unsupported window_type %q; use sliding or fixed", config.WindowType), ErrorTypeNonRetryable)
+	}
+	if config.Mode == throttleModeTrailing {
+		if window > maxTrailingThrottleWindow {
+			return failed(fmt.Sprintf("trailing windows are limited to %s", maxTrailingThrottleWindow), ErrorTypeNonRetryable)
+		}
+		if req.Timeout > 0 && window >= req.Timeout {
+			return failed(fmt.Sprintf("trailing window %s does not fit in the node timeout %s", window, req.Timeout), ErrorTypeNonRetryable)
+		}
+	}
+	if e.client == nil {
+		return failed("throttle requires a Redis connection; set REDIS_URL on the worker", ErrorTypeNonRetryable)
+	}
+
+	hash, err := dedupeHash(req.Input, config.Fields)
+	if err != nil {
+		return failed(err.Error(), ErrorTypeNonRetryable)
+	}
+	key := throttleKeyPrefix + req.Namespace + ":"
+	if config.Scope != "" {
+		key += config.Scope + ":"
+	}
+	key += hash
+
+	// The run ID identifies this event, so a retried node finds its own
+	// record rather than another event's.
+	token := req.RunID + ":" + req.NodeID
+
+	var allowed bool
+	if config.Mode == throttleModeLeading {
+		allowed, err = e.leading(ctx, key, token, window, config.WindowType)
+	} else {
+		allowed, err = e.trailing(ctx, key, token, window, config.WindowType)
+	}
+	if err != nil {
+		errorType := ErrorTypeRetryable
+		if errors.Is(err, context.DeadlineExceeded) {
+			errorType = ErrorTypeTimeout
+		}
+		return failed(fmt.Sprintf("failed to throttle: %v", err), errorType)
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Throttle key %s (%s, %s %s window) allowed=%v", key, config.Mode, config.WindowType, window, allowed),
+	})
+
+	output, err := json.Marshal(ThrottleResponse{Allowed: allowed, Key: key})
+	if err != nil {
+		return failed(fmt.Sprintf("failed to marshal response: %v", err), ErrorTypeNonRetryable)
+	}
+
+	return &ExecuteResponse{
+		Output:   output,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// leading claims the window for token. A fixed window is claimed once; a
+// sliding window is extended by every event, so a steady stream stays
+// suppressed until it pauses for a whole window. SET with both NX and GET
+// needs Redis 7.
+func (e *ThrottleExecutor) leading(ctx context.Context, key, token string, window time.Duration, windowType string) (bool, error) {
+	args := redis.SetArgs{Get: true, TTL: window}
+	if windowType == throttleWindowFixed {
+		bucket, _, err := e.fixedBucket(ctx, window)
+		if err != nil {
+			return false, err
+		}
+		key += ":" + strconv.FormatInt(bucket, 10)
+		args.Mode = "NX"
+	}
+
+	previous, err := e.client.SetArgs(ctx, key, token, args).Result()
+	if errors.Is(err, redis.Nil) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return previous == token, nil
+}
+
+// trailing records token as the latest event of its window, waits for the
+// window to close, and reports whether no later event replaced it.
+func (e *ThrottleExecutor) trailing(ctx context.Context, key, token string, window time.Duration, windowType string) (bool, error) {
+	wait := window
+	if windowType == throttleWindowFixed {
+		bucket, remaining, err := e.fixedBucket(ctx, window)
+		if err != nil {
+			return false, err
+		}
+		key += ":" + strconv.FormatInt(bucket, 10)
+		wait = remaining
+	}
+
+	// Keep the record past the end of the window so the check below still
+	// finds it.
+	if err := e.client.Set(ctx, key, token, 2*window).Err(); err != nil {
+		return false, err
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-timer.C:
+	}
+
+	latest, err := e.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return latest == token, nil
+}
+
+// fixedBucket returns the index of the fixed window the Redis clock is in
+// and how long is left of it.
+func (e *ThrottleExecutor) fixedBucket(ctx context.Context, window time.Duration) (int64, time.Duration, error) {
+	now, err := e.client.Time(ctx).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	bucket, remaining := throttleBucket(now, window)
+	return bucket, remaining, nil
+}
+
+func throttleBucket(now time.Time, window time.Duration) (int64, time.Duration) {
+	elapsed := now.UnixNano()
+	return elapsed / int64(window), time.Duration(int64(window) - elapsed%int64(window))
+}
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThrottleExecutorValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		timeout time.Duration
+		want    string
+	}{
+		{"missing window", `{}`, 0, "window must be"},
+		{"unknown mode", `{"window": 5, "mode": "both"}`, 0, "unsupported mode"},
+		{"unknown window type", `{"window": 5, "window_type": "rolling"}`, 0, "unsupported window_type"},
+		{"trailing past timeout", `{"window": 60, "mode": "trailing"}`, 30 * time.Second, "does not fit"},
+		{"no redis", `{"window": 5}`, 0, "REDIS_URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewThrottleExecutor().Execute(context.Background(), &ExecuteRequest{
+				NodeType: "throttle",
+				Config:   json.RawMessage(tt.config),
+				Input:    json.RawMessage(`{"path":"/tmp/a"}`),
+				Timeout:  tt.timeout,
+			})
+			if err != nil {
+				t.Fatalf("Execute error: %v", err)
+			}
+			if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable || !strings.Contains(resp.Error.Message, tt.want) {
+				t.Fatalf("expected non-retryable error containing %q, got %+v", tt.want, resp.Error)
+			}
+		})
+	}
+}
+
+func TestThrottleBucketAlignsToWindow(t *testing.T) {
+	window := 10 * time.Second
+	bucket, remaining := throttleBucket(time.Unix(1_700_000_003, 0), window)
+	if bucket != 170_000_000 || remaining != 7*time.Second {
+		t.Fatalf("bucket = %d, remaining = %s, want 170000000 and 7s", bucket, remaining)
+	}
+	if next, _ := throttleBucket(time.Unix(1_700_000_010, 0), window); next != bucket+1 {
+		t.Fatalf("next bucket = %d, want %d", next, bucket+1)
+	}
+}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPTimerScheduler schedules activity timers through the timer service's
+// HTTP API.
+type HTTPTimerScheduler struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewHTTPTimerScheduler creates a scheduler for the timer service at baseURL
+// that authenticates with the service's bearer token.
+func NewHTTPTimerScheduler(baseURL, token string) *HTTPTimerScheduler {
+	return &HTTPTimerScheduler{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+	}
+}
+
+func (s *HTTPTimerScheduler) ScheduleActivityTimer(ctx context.Context, req *ActivityTimerRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal timer request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/timers", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create timer request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("timer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("timer service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
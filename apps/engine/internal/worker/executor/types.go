@@ -78,3 +78,15 @@ func (n *Node) GetOnError() string {
 func (n *Node) IsConditionType() bool {
 	return n.Type == "condition" || n.Type == "logic_condition"
 }
+
+// SelectsBranch returns true if the node's output names the outgoing branch
+// to take in its "output" field: condition nodes, wait_signal nodes, which
+// take "signal" or "timeout", and approval nodes, which take "approved" or
+// "rejected".
+func (n *Node) SelectsBranch() bool {
+	switch n.Type {
+	case "wait_signal", "approval", "action_approval":
+		return true
+	}
+	return n.IsConditionType()
+}
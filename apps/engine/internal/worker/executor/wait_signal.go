@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// waitSignalBranchTimeout is the branch a wait_signal node that timed out
+// with on_timeout "continue" takes. History reports "signal" for nodes the
+// signal completed.
+const waitSignalBranchTimeout = "timeout"
+
+// WaitSignalExecutor pauses a node until a named signal is sent to the
+// execution, for human-in-the-loop steps. History completes the node with the
+// signal's input when the signal arrives, or right away with the earliest
+// matching signal it buffered before the node started waiting; on timeout the
+// node fails, or with on_timeout "continue" completes and takes its "timeout"
+// branch.
+type WaitSignalExecutor struct{}
+
+// WaitSignalConfig represents the configuration for a wait_signal node.
+type WaitSignalConfig struct {
+	SignalName string `json:"signal_name"`
+	Timeout    int    `json:"timeout"`    // Seconds to wait (0 = worker default)
+	OnTimeout  string `json:"on_timeout"` // fail (default) or continue
+}
+
+// NewWaitSignalExecutor creates a new wait_signal executor.
+func NewWaitSignalExecutor() *WaitSignalExecutor {
+	return &WaitSignalExecutor{}
+}
+
+func (e *WaitSignalExecutor) NodeType() string {
+	return "wait_signal"
+}
+
+var waitSignalInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["signal_name"],
+  "properties": {
+    "signal_name": {"type": "string", "minLength": 1, "maxLength": 256},
+    "timeout": {"type": "integer", "minimum": 0, "description": "Seconds to wait for the signal, 0 for the worker default"},
+    "on_timeout": {"type": "string", "enum": ["fail", "continue"], "default": "fail"}
+  }
+}`)
+
+var waitSignalOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["output", "signal_name"],
+  "properties": {
+    "output": {"type": "string", "enum": ["signal", "timeout"], "description": "Output branch to take"},
+    "signal_name": {"type": "string"},
+    "payload": {"description": "Input sent with the signal"},
+    "timed_out": {"type": "boolean"}
+  }
+}`)
+
+func (e *WaitSignalExecutor) InputSchema() json.RawMessage {
+	return waitSignalInputSchema
+}
+
+func (e *WaitSignalExecutor) OutputSchema() json.RawMessage {
+	return waitSignalOutputSchema
+}
+
+func (e *WaitSignalExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	fail := func(message string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error: &ExecutionError{
+				Message: message,
+				Type:    ErrorTypeNonRetryable,
+			},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config WaitSignalConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return fail(fmt.Sprintf("failed to parse wait_signal config: %v", err))
+	}
+	if config.SignalName == "" {
+		return fail("signal_name is required")
+	}
+	if config.Timeout < 0 {
+		return fail("timeout must not be negative")
+	}
+
+	pending := &PendingActivity{
+		ScheduleToCloseTimeout: time.Duration(config.Timeout) * time.Second,
+		WaitSignal:             config.SignalName,
+	}
+	switch config.OnTimeout {
+	case "", "fail":
+	case "continue":
+		pending.TimeoutResult, _ = json.Marshal(map[string]interface{}{
+			"output":      waitSignalBranchTimeout,
+			"signal_name": config.SignalName,
+			"timed_out":   true,
+		})
+	default:
+		return fail(fmt.Sprintf("unknown on_timeout: %s", config.OnTimeout))
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("Node %s waiting for signal %q", req.NodeID, config.SignalName),
+	})
+
+	return &ExecuteResponse{
+		Pending:  pending,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
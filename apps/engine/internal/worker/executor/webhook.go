@@ -5,9 +5,11 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"net/url"
@@ -17,6 +19,8 @@ import (
 
 // WebhookExecutor handles webhook calls to external services.
 type WebhookExecutor struct {
+	BaseExecutor
+
 	client *http.Client
 }
 
@@ -40,6 +44,10 @@ type WebhookConfig struct {
 	HMACSecret   string `json:"hmac_secret"`    // for hmac signing
 	HMACHeader   string `json:"hmac_header"`    // header name for hmac signature
 
+	// Signing signs the request the way the engine signs its own callbacks,
+	// so receivers can verify it came from us.
+	Signing *WebhookSigning `json:"signing,omitempty"`
+
 	// Options
 	Timeout            int   `json:"timeout"` // in seconds
 	FollowRedirects    bool  `json:"follow_redirects"`
@@ -51,6 +59,19 @@ type WebhookConfig struct {
 	ResponseType string `json:"response_type"` // json, text, binary
 }
 
+// WebhookSigning configures the HMAC signature of outbound webhooks. The
+// signature is the hex HMAC of "<timestamp>.<body>", where timestamp is the
+// RFC 3339 UTC time sent in TimestampHeader. A receiver verifies a request by
+// recomputing the HMAC over the received timestamp header, a ".", and the raw
+// body, comparing it to the signature header in constant time, and rejecting
+// timestamps too far from its own clock to prevent replays.
+type WebhookSigning struct {
+	Secret          string `json:"secret"`           // Unsigned when empty
+	Algorithm       string `json:"algorithm"`        // sha256 (default), sha512
+	Header          string `json:"header"`           // Default X-LinkFlow-Signature
+	TimestampHeader string `json:"timestamp_header"` // Default X-LinkFlow-Timestamp
+}
+
 // WebhookResponse represents the result of a webhook call.
 type WebhookResponse struct {
 	StatusCode    int               `json:"status_code"`
@@ -156,7 +177,7 @@ func (e *WebhookExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 	}
 
 	// Build request body
-	var bodyReader io.Reader
+	var body []byte
 	var contentType string
 
 	if len(config.FormData) > 0 {
@@ -165,12 +186,16 @@ func (e *WebhookExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 		for k, v := range config.FormData {
 			formValues.Set(k, v)
 		}
-		bodyReader = strings.NewReader(formValues.Encode())
+		body = []byte(formValues.Encode())
 		contentType = "application/x-www-form-urlencoded"
 	} else if len(config.Body) > 0 {
-		bodyReader = bytes.NewReader(config.Body)
+		body = config.Body
 		contentType = "application/json"
 	}
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
 
 	// Create context with timeout
 	timeout := time.Duration(config.Timeout) * time.Second
@@ -201,6 +226,24 @@ func (e *WebhookExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*Ex
 	// Apply authentication
 	e.applyAuth(httpReq, &config, &logs)
 
+	if config.Signing != nil && config.Signing.Secret != "" {
+		if err := signWebhookRequest(httpReq, config.Signing, body, req.Deterministic.Now()); err != nil {
+			return &ExecuteResponse{
+				Error: &ExecutionError{
+					Message: err.Error(),
+					Type:    ErrorTypeNonRetryable,
+				},
+				Logs:     logs,
+				Duration: time.Since(start),
+			}, nil
+		}
+		logs = append(logs, LogEntry{
+			Timestamp: time.Now(),
+			Level:     "DEBUG",
+			Message:   "Signed webhook request",
+		})
+	}
+
 	logs = append(logs, LogEntry{
 		Timestamp: time.Now(),
 		Level:     "INFO",
@@ -390,3 +433,35 @@ func (e *WebhookExecutor) applyAuth(req *http.Request, config *WebhookConfig, lo
 		})
 	}
 }
+
+// signWebhookRequest sets the timestamp and signature headers of req.
+func signWebhookRequest(req *http.Request, signing *WebhookSigning, body []byte, now time.Time) error {
+	var newHash func() hash.Hash
+	switch signing.Algorithm {
+	case "", "sha256":
+		newHash = sha256.New
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return fmt.Errorf("unsupported signing algorithm: %s", signing.Algorithm)
+	}
+
+	header := signing.Header
+	if header == "" {
+		header = "X-LinkFlow-Signature"
+	}
+	timestampHeader := signing.TimestampHeader
+	if timestampHeader == "" {
+		timestampHeader = "X-LinkFlow-Timestamp"
+	}
+
+	ts := now.UTC().Format(time.RFC3339)
+	mac := hmac.New(newHash, []byte(signing.Secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	req.Header.Set(timestampHeader, ts)
+	req.Header.Set(header, hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
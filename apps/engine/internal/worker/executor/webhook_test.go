@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookExecutorSignsRequests(t *testing.T) {
+	t.Parallel()
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	exec := NewWebhookExecutor()
+	send := func(signing *WebhookSigning) received {
+		config, _ := json.Marshal(WebhookConfig{
+			URL:     server.URL,
+			Body:    json.RawMessage(`{"event":"order.paid"}`),
+			Signing: signing,
+		})
+		resp, err := exec.Execute(context.Background(), &ExecuteRequest{NodeID: "notify", Config: config})
+		if err != nil || resp.Error != nil {
+			t.Fatalf("execute: %v, %+v", err, resp.Error)
+		}
+		return <-requests
+	}
+
+	// The receiver's side of the verification recipe.
+	got := send(&WebhookSigning{Secret: "s3cret", Algorithm: "sha512", Header: "X-Signature"})
+	ts := got.header.Get("X-LinkFlow-Timestamp")
+	sent, err := time.Parse(time.RFC3339, ts)
+	if err != nil || time.Since(sent) > time.Minute {
+		t.Fatalf("timestamp header %q is not a current RFC 3339 time", ts)
+	}
+	mac := hmac.New(sha512.New, []byte("s3cret"))
+	mac.Write([]byte(ts + "." + string(got.body)))
+	want := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(got.header.Get("X-Signature")), []byte(want)) {
+		t.Fatalf("signature = %q, want %q", got.header.Get("X-Signature"), want)
+	}
+
+	got = send(&WebhookSigning{})
+	if got.header.Get("X-LinkFlow-Signature") != "" || got.header.Get("X-LinkFlow-Timestamp") != "" {
+		t.Fatalf("request without a secret was signed: %v", got.header)
+	}
+
+	config, _ := json.Marshal(WebhookConfig{URL: server.URL, Signing: &WebhookSigning{Secret: "s3cret", Algorithm: "md5"}})
+	resp, err := exec.Execute(context.Background(), &ExecuteRequest{NodeID: "notify", Config: config})
+	if err != nil || resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable {
+		t.Fatalf("unsupported algorithm error = %v, %+v, want non-retryable", err, resp.Error)
+	}
+}
@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
 	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
 	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
 	"github.com/linkflow/engine/internal/worker/adapter"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type WorkflowExecutor struct {
+	BaseExecutor
+
 	historyClient    *adapter.HistoryClient
 	logger           *slog.Logger
 	executorRegistry *Registry
@@ -51,6 +55,34 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 		return nil, fmt.Errorf("history is empty")
 	}
 
+	commands, err := e.decide(req, events)
+	if err != nil {
+		return nil, err
+	}
+	// Marshal commands to Output
+	outputBytes, err := json.Marshal(commands)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteResponse{
+		Output: outputBytes,
+	}, nil
+}
+
+// Replay returns the commands the workflow would send given events, its
+// history up to a recorded workflow task, without fetching history or
+// responding to it. It backs replay validation of workflow changes against
+// past runs.
+func (e *WorkflowExecutor) Replay(req *ExecuteRequest, events []*historyv1.HistoryEvent) ([]*historyv1.Command, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("history is empty")
+	}
+	return e.decide(req, events)
+}
+
+// decide replays events into the workflow's state and returns the commands
+// for its next steps.
+func (e *WorkflowExecutor) decide(req *ExecuteRequest, events []*historyv1.HistoryEvent) ([]*historyv1.Command, error) {
 	// TODO: Implement sticky execution caching to avoid full history replay.
 	// For now, log a warning when history is large to track performance impact.
 	if len(events) > 500 {
@@ -85,7 +117,11 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 	// 3. Replay History to build State
 	nodeStates := make(map[string]string) // NodeID -> Status
 	nodeOutputs := make(map[string][]byte)
+	continuations := make(map[string][]byte)
 	eventIDToNodeID := make(map[int64]string)
+	// versions lets the decision logic below branch on workflow changes with
+	// versions.GetVersion; markers it records are sent with the decision.
+	versions := newWorkflowVersions()
 
 	for _, event := range events {
 		switch event.GetEventType() {
@@ -101,6 +137,15 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 				if attr.GetResult() != nil && len(attr.GetResult().GetPayloads()) > 0 {
 					nodeOutputs[nodeID] = attr.GetResult().GetPayloads()[0].GetData()
 				}
+				// A continuation is not a final result: run the node
+				// again with it as input.
+				if _, ok := ParseNodeContinuation(nodeOutputs[nodeID]); ok {
+					nodeStates[nodeID] = ""
+					continuations[nodeID] = nodeOutputs[nodeID]
+					delete(nodeOutputs, nodeID)
+				} else {
+					delete(continuations, nodeID)
+				}
 			}
 
 		case commonv1.EventType_EVENT_TYPE_NODE_FAILED:
@@ -108,6 +153,29 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 			if nodeID, ok := eventIDToNodeID[attr.GetScheduledEventId()]; ok {
 				nodeStates[nodeID] = "Failed"
 			}
+
+		case commonv1.EventType_EVENT_TYPE_CHILD_WORKFLOW_STARTED:
+			attr := event.GetChildWorkflowStartedAttributes()
+			nodeStates[attr.GetNodeId()] = "Scheduled"
+
+		case commonv1.EventType_EVENT_TYPE_CHILD_WORKFLOW_COMPLETED:
+			attr := event.GetChildWorkflowCompletedAttributes()
+			// A child closing after its node already resolved, e.g. timed
+			// out, does not change the node.
+			if status := nodeStates[attr.GetNodeId()]; status == "Completed" || status == "Failed" {
+				continue
+			}
+			if attr.GetStatus() == commonv1.ExecutionStatus_EXECUTION_STATUS_COMPLETED {
+				nodeStates[attr.GetNodeId()] = "Completed"
+				if attr.GetResult() != nil && len(attr.GetResult().GetPayloads()) > 0 {
+					nodeOutputs[attr.GetNodeId()] = attr.GetResult().GetPayloads()[0].GetData()
+				}
+			} else {
+				nodeStates[attr.GetNodeId()] = "Failed"
+			}
+
+		case commonv1.EventType_EVENT_TYPE_MARKER_RECORDED:
+			versions.observe(event)
 		}
 	}
 
@@ -161,12 +229,7 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 			},
 		}
 		commands = append(commands, cmd)
-
-		outputBytes, err := json.Marshal(commands)
-		if err != nil {
-			return nil, err
-		}
-		return &ExecuteResponse{Output: outputBytes}, nil
+		return append(versions.Commands(), commands...), nil
 	}
 
 	allNodesDone := true
@@ -186,6 +249,12 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 			continue
 		}
 
+		if continuation, ok := continuations[node.ID]; ok {
+			nodesToSchedule = append(nodesToSchedule, node)
+			inputs[node.ID] = continuation
+			continue
+		}
+
 		// Determine if this is a trigger node
 		isTrigger := node.Type == "trigger_manual" || node.Type == "trigger_webhook" || node.Type == "trigger_schedule"
 
@@ -233,7 +302,7 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 
 			// Conditional branching: check if this edge should be taken
 			sourceNode := nodeMap[edge.Source]
-			if sourceNode != nil && sourceNode.IsConditionType() && edge.SourceHandle != "" {
+			if sourceNode != nil && sourceNode.SelectsBranch() && edge.SourceHandle != "" {
 				// Parse the condition node's output to get the selected branch
 				if output, ok := nodeOutputs[edge.Source]; ok {
 					var condResult struct {
@@ -280,6 +349,8 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 
 	// Generate ScheduleActivity Commands
 	for _, node := range nodesToSchedule {
+		// sub_workflow nodes run as activities too: SubWorkflowExecutor has
+		// history start the child and keeps the node pending until it closes.
 		cmd := e.buildScheduleCommand(node, inputs[node.ID], payload.Deterministic)
 		if cmd != nil {
 			commands = append(commands, cmd)
@@ -312,15 +383,7 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*E
 		commands = append(commands, cmd)
 	}
 
-	// Marshal commands to Output
-	outputBytes, err := json.Marshal(commands)
-	if err != nil {
-		return nil, err
-	}
-
-	return &ExecuteResponse{
-		Output: outputBytes,
-	}, nil
+	return append(versions.Commands(), commands...), nil
 }
 
 // skipDependents recursively marks all downstream nodes of a given node as skipped.
@@ -374,19 +437,36 @@ func (e *WorkflowExecutor) buildScheduleCommand(node Node, inputData json.RawMes
 		return nil
 	}
 
+	attrs := &historyv1.ScheduleActivityTaskCommandAttributes{
+		NodeId:   node.ID,
+		NodeType: node.Type,
+		Name:     node.GetName(),
+		Input: &commonv1.Payloads{
+			Payloads: []*commonv1.Payload{{Data: envelopeBytes}},
+		},
+		TaskQueue: "default",
+		Config:    configBytes,
+	}
+
+	// Timeouts are recorded on the scheduled event so every attempt, on any
+	// worker, measures against the same schedule time.
+	var timeouts struct {
+		StartToCloseTimeout    int `json:"start_to_close_timeout"`
+		ScheduleToCloseTimeout int `json:"schedule_to_close_timeout"`
+	}
+	if err := json.Unmarshal(configBytes, &timeouts); err == nil {
+		if timeouts.StartToCloseTimeout > 0 {
+			attrs.StartToCloseTimeout = durationpb.New(time.Duration(timeouts.StartToCloseTimeout) * time.Second)
+		}
+		if timeouts.ScheduleToCloseTimeout > 0 {
+			attrs.ScheduleToCloseTimeout = durationpb.New(time.Duration(timeouts.ScheduleToCloseTimeout) * time.Second)
+		}
+	}
+
 	return &historyv1.Command{
 		CommandType: historyv1.CommandType_COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK,
 		Attributes: &historyv1.Command_ScheduleActivityTaskAttributes{
-			ScheduleActivityTaskAttributes: &historyv1.ScheduleActivityTaskCommandAttributes{
-				NodeId:   node.ID,
-				NodeType: node.Type,
-				Name:     node.GetName(),
-				Input: &commonv1.Payloads{
-					Payloads: []*commonv1.Payload{{Data: envelopeBytes}},
-				},
-				TaskQueue: "default",
-				Config:    configBytes,
-			},
+			ScheduleActivityTaskAttributes: attrs,
 		},
 	}
 }
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+)
+
+// Version markers are recorded by history as MarkerRecorded events with these
+// names and detail keys.
+const (
+	versionMarkerName        = "Version"
+	versionMarkerChangeIDKey = "change_id"
+	versionMarkerVersionKey  = "version"
+)
+
+// ErrUnsupportedVersion is returned by GetVersion when history recorded a
+// version of a change that the running decider no longer supports.
+var ErrUnsupportedVersion = errors.New("recorded version is not supported")
+
+// WorkflowVersions lets decider logic branch on the version of a workflow
+// change deterministically. The first decision that reaches a change records
+// a version marker; every replay after that reads the recorded version back.
+type WorkflowVersions struct {
+	recorded map[string]int32
+	commands []*historyv1.Command
+}
+
+func newWorkflowVersions() *WorkflowVersions {
+	return &WorkflowVersions{recorded: make(map[string]int32)}
+}
+
+// observe records the version marker in event, if it is one. Only the first
+// marker for a change counts, matching history.
+func (v *WorkflowVersions) observe(event *historyv1.HistoryEvent) {
+	attrs := event.GetMarkerRecordedAttributes()
+	if attrs.GetMarkerName() != versionMarkerName {
+		return
+	}
+	changeID := string(attrs.GetDetails()[versionMarkerChangeIDKey])
+	version, err := strconv.ParseInt(string(attrs.GetDetails()[versionMarkerVersionKey]), 10, 32)
+	if changeID == "" || err != nil {
+		return
+	}
+	if _, ok := v.recorded[changeID]; !ok {
+		v.recorded[changeID] = int32(version)
+	}
+}
+
+// GetVersion returns the version to run for changeID. A version recorded in
+// history wins and must lie within [minSupported, maxSupported]; otherwise
+// maxSupported is chosen and a marker command is queued to record it.
+func (v *WorkflowVersions) GetVersion(changeID string, minSupported, maxSupported int32) (int32, error) {
+	if version, ok := v.recorded[changeID]; ok {
+		if version < minSupported || version > maxSupported {
+			return 0, fmt.Errorf("%w: change %q recorded version %d, supported range is %d to %d",
+				ErrUnsupportedVersion, changeID, version, minSupported, maxSupported)
+		}
+		return version, nil
+	}
+
+	v.recorded[changeID] = maxSupported
+	v.commands = append(v.commands, &historyv1.Command{
+		CommandType: historyv1.CommandType_COMMAND_TYPE_RECORD_VERSION_MARKER,
+		Attributes: &historyv1.Command_RecordVersionMarkerAttributes{
+			RecordVersionMarkerAttributes: &historyv1.RecordVersionMarkerCommandAttributes{
+				ChangeId: changeID,
+				Version:  maxSupported,
+			},
+		},
+	})
+	return maxSupported, nil
+}
+
+// Commands returns the marker commands for versions chosen in this decision.
+// They go ahead of the decision's other commands so the marker precedes the
+// work it versions in history.
+func (v *WorkflowVersions) Commands() []*historyv1.Command {
+	return v.commands
+}
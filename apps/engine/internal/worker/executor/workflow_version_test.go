@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+)
+
+func TestWorkflowVersionsReplaysRecordedVersion(t *testing.T) {
+	versions := newWorkflowVersions()
+	versions.observe(&historyv1.HistoryEvent{
+		EventType: commonv1.EventType_EVENT_TYPE_MARKER_RECORDED,
+		Attributes: &historyv1.HistoryEvent_MarkerRecordedAttributes{
+			MarkerRecordedAttributes: &historyv1.MarkerRecordedEventAttributes{
+				MarkerName: versionMarkerName,
+				Details: map[string][]byte{
+					versionMarkerChangeIDKey: []byte("retry-backoff"),
+					versionMarkerVersionKey:  []byte("1"),
+				},
+			},
+		},
+	})
+
+	version, err := versions.GetVersion("retry-backoff", 1, 2)
+	if err != nil || version != 1 {
+		t.Fatalf("GetVersion(recorded) = %d, %v; want 1", version, err)
+	}
+	if _, err := versions.GetVersion("retry-backoff", 2, 3); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("GetVersion(below supported) error = %v, want ErrUnsupportedVersion", err)
+	}
+
+	version, err = versions.GetVersion("new-change", 1, 4)
+	if err != nil || version != 4 {
+		t.Fatalf("GetVersion(new) = %d, %v; want 4", version, err)
+	}
+	// Asking again in the same decision returns the same version without a
+	// second marker.
+	if version, _ := versions.GetVersion("new-change", 1, 4); version != 4 {
+		t.Fatalf("GetVersion(new, again) = %d, want 4", version)
+	}
+	commands := versions.Commands()
+	if len(commands) != 1 || commands[0].GetRecordVersionMarkerAttributes().GetChangeId() != "new-change" {
+		t.Fatalf("commands = %v, want one marker for new-change", commands)
+	}
+}
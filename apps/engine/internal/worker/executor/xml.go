@@ -0,0 +1,564 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	maxXMLDocumentSize = 5 * 1024 * 1024 // 5MB
+	maxXMLDepth        = 256
+	xmlDefaultRoot     = "root"
+)
+
+// XMLExecutor converts between XML documents and JSON objects for partners
+// that only speak XML (SOAP endpoints, legacy feeds).
+//
+// Mapping conventions, shared by both modes so documents round-trip:
+//
+//   - An element becomes a key named after it, including its namespace
+//     prefix ("soap:Body"). from_xml returns the root as a single-key object.
+//   - Attributes become "@name" keys; namespace declarations are attributes
+//     too ("@xmlns", "@xmlns:soap").
+//   - An element with only text becomes a string; an empty one becomes "".
+//     Text next to attributes or child elements goes in "#text".
+//   - Repeated sibling elements become an array, as do the elements named in
+//     force_array, even when they occur once.
+//   - CDATA sections are read as text. to_xml writes "#cdata" keys as CDATA.
+//   - All values read from XML are strings. to_xml writes numbers and
+//     booleans as their JSON text and null as an empty element.
+//   - to_xml writes keys in input order.
+type XMLExecutor struct{}
+
+// XMLConfig represents the configuration for a transform_xml node.
+type XMLConfig struct {
+	Mode string `json:"mode"` // to_xml, from_xml
+
+	// to_xml
+	Data        json.RawMessage   `json:"data"`        // Object to convert (default: node input)
+	Root        string            `json:"root"`        // Root element (default: the only key of the object, else "root")
+	Namespace   string            `json:"namespace"`   // Default namespace declared on the root
+	Namespaces  map[string]string `json:"namespaces"`  // Prefix -> URI declared on the root
+	Declaration bool              `json:"declaration"` // Write an <?xml ...?> declaration
+	Indent      string            `json:"indent"`      // Indentation per level (default: none)
+
+	// from_xml
+	XML        *string  `json:"xml"`         // XML text (default: node input, a JSON string)
+	ForceArray []string `json:"force_array"` // Elements always read as arrays
+}
+
+// NewXMLExecutor creates a new XML executor.
+func NewXMLExecutor() *XMLExecutor {
+	return &XMLExecutor{}
+}
+
+func (e *XMLExecutor) NodeType() string {
+	return "transform_xml"
+}
+
+var xmlInputSchema = json.RawMessage(`{
+  "type": "object",
+  "required": ["mode"],
+  "properties": {
+    "mode": {"type": "string", "enum": ["to_xml", "from_xml"]},
+    "data": {"type": "object", "description": "Object to convert, defaults to the node input"},
+    "root": {"type": "string"},
+    "namespace": {"type": "string"},
+    "namespaces": {"type": "object", "additionalProperties": {"type": "string"}},
+    "declaration": {"type": "boolean", "default": false},
+    "indent": {"type": "string"},
+    "xml": {"type": "string", "description": "XML text to parse, defaults to the node input"},
+    "force_array": {"type": "array", "items": {"type": "string"}}
+  }
+}`)
+
+var xmlOutputSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "xml": {"type": "string"},
+    "data": {"type": "object"}
+  }
+}`)
+
+func (e *XMLExecutor) InputSchema() json.RawMessage {
+	return xmlInputSchema
+}
+
+func (e *XMLExecutor) OutputSchema() json.RawMessage {
+	return xmlOutputSchema
+}
+
+func (e *XMLExecutor) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	start := time.Now()
+	logs := make([]LogEntry, 0)
+
+	failed := func(message string) (*ExecuteResponse, error) {
+		return &ExecuteResponse{
+			Error:    &ExecutionError{Message: message, Type: ErrorTypeNonRetryable},
+			Logs:     logs,
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	var config XMLConfig
+	if err := json.Unmarshal(req.Config, &config); err != nil {
+		return failed(fmt.Sprintf("failed to parse transform_xml config: %v", err))
+	}
+
+	var output []byte
+	switch config.Mode {
+	case "to_xml":
+		data := config.Data
+		if len(data) == 0 {
+			data = req.Input
+		}
+		if len(data) > maxXMLDocumentSize {
+			return failed(fmt.Sprintf("data exceeds the %d byte limit", maxXMLDocumentSize))
+		}
+		text, err := jsonToXML(data, config)
+		if err != nil {
+			return failed(fmt.Sprintf("failed to generate xml: %v", err))
+		}
+		output, err = json.Marshal(map[string]string{"xml": text})
+		if err != nil {
+			return failed(fmt.Sprintf("failed to marshal output: %v", err))
+		}
+	case "from_xml":
+		text, err := config.text(req.Input)
+		if err != nil {
+			return failed(err.Error())
+		}
+		if len(text) > maxXMLDocumentSize {
+			return failed(fmt.Sprintf("xml exceeds the %d byte limit", maxXMLDocumentSize))
+		}
+		data, err := xmlToJSON(text, config.ForceArray)
+		if err != nil {
+			return failed(err.Error())
+		}
+		output, err = json.Marshal(map[string]interface{}{"data": data})
+		if err != nil {
+			return failed(fmt.Sprintf("failed to marshal output: %v", err))
+		}
+	default:
+		return failed(fmt.Sprintf("unknown mode: %s", config.Mode))
+	}
+
+	logs = append(logs, LogEntry{
+		Timestamp: time.Now(),
+		Level:     "INFO",
+		Message:   fmt.Sprintf("XML %s converted %d bytes for node %s", config.Mode, len(output), req.NodeID),
+	})
+
+	return &ExecuteResponse{
+		Output:   output,
+		Logs:     logs,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// text returns the XML to parse: the xml setting, or else the node input,
+// which must be a JSON string.
+func (c *XMLConfig) text(input json.RawMessage) (string, error) {
+	if c.XML != nil {
+		return *c.XML, nil
+	}
+	var text string
+	if err := json.Unmarshal(input, &text); err != nil {
+		return "", errors.New("xml is required when the node input is not a string")
+	}
+	return text, nil
+}
+
+// --- from_xml ---
+
+// xmlElement collects an element while it is being read.
+type xmlElement struct {
+	name     string
+	object   map[string]interface{}
+	text     strings.Builder
+	children int
+}
+
+// xmlToJSON parses text into nested objects following the XMLExecutor
+// mapping conventions. Errors report the line and column they were found at.
+func xmlToJSON(text string, forceArray []string) (map[string]interface{}, error) {
+	forced := make(map[string]bool, len(forceArray))
+	for _, name := range forceArray {
+		forced[name] = true
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(text))
+	parseError := func(err error) error {
+		message := err.Error()
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			message = syntaxErr.Msg
+		}
+		line, column := decoder.InputPos()
+		return fmt.Errorf("failed to parse xml at line %d, column %d: %s", line, column, message)
+	}
+
+	var stack []*xmlElement
+	var result map[string]interface{}
+	for {
+		// RawToken keeps namespace prefixes as written, so they round-trip;
+		// element nesting is checked against the stack below.
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, parseError(err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if result != nil {
+				return nil, parseError(errors.New("content after the root element"))
+			}
+			if len(stack) >= maxXMLDepth {
+				return nil, parseError(fmt.Errorf("elements nested deeper than %d levels", maxXMLDepth))
+			}
+			elem := &xmlElement{name: xmlQualifiedName(t.Name), object: make(map[string]interface{})}
+			for _, attr := range t.Attr {
+				elem.object["@"+xmlQualifiedName(attr.Name)] = attr.Value
+			}
+			stack = append(stack, elem)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, parseError(fmt.Errorf("unexpected end element </%s>", xmlQualifiedName(t.Name)))
+			}
+			elem := stack[len(stack)-1]
+			if name := xmlQualifiedName(t.Name); name != elem.name {
+				return nil, parseError(fmt.Errorf("element <%s> closed by </%s>", elem.name, name))
+			}
+			stack = stack[:len(stack)-1]
+			value := elem.value()
+			if len(stack) == 0 {
+				result = map[string]interface{}{elem.name: value}
+				continue
+			}
+			stack[len(stack)-1].addChild(elem.name, value, forced[elem.name])
+		case xml.CharData:
+			if len(stack) == 0 {
+				if len(bytes.TrimSpace(t)) > 0 {
+					return nil, parseError(errors.New("text outside the root element"))
+				}
+				continue
+			}
+			stack[len(stack)-1].text.Write(t)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, parseError(fmt.Errorf("element <%s> is not closed", stack[len(stack)-1].name))
+	}
+	if result == nil {
+		return nil, parseError(errors.New("document has no root element"))
+	}
+	return result, nil
+}
+
+func (e *xmlElement) addChild(name string, value interface{}, forceArray bool) {
+	e.children++
+	existing, exists := e.object[name]
+	switch {
+	case !exists && forceArray:
+		e.object[name] = []interface{}{value}
+	case !exists:
+		e.object[name] = value
+	default:
+		if list, ok := existing.([]interface{}); ok {
+			e.object[name] = append(list, value)
+		} else {
+			e.object[name] = []interface{}{existing, value}
+		}
+	}
+}
+
+// value returns the element's JSON form: a string when it holds only text,
+// otherwise an object with its attributes, children and "#text".
+func (e *xmlElement) value() interface{} {
+	text := e.text.String()
+	if len(e.object) == 0 {
+		return text
+	}
+	// Whitespace between child elements is layout, not content.
+	if trimmed := strings.TrimSpace(text); trimmed != "" {
+		if e.children > 0 {
+			text = trimmed
+		}
+		e.object["#text"] = text
+	}
+	return e.object
+}
+
+func xmlQualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// --- to_xml ---
+
+// xmlField is an object key and its value, kept in input order.
+type xmlField struct {
+	key   string
+	value interface{}
+}
+
+// jsonToXML writes data as XML following the XMLExecutor mapping
+// conventions.
+func jsonToXML(data json.RawMessage, config XMLConfig) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeOrderedJSON(decoder, 0)
+	if err != nil {
+		return "", fmt.Errorf("invalid data: %v", err)
+	}
+	fields, ok := value.([]xmlField)
+	if !ok {
+		return "", errors.New("data must be an object")
+	}
+
+	root := config.Root
+	var rootValue interface{} = fields
+	if root == "" {
+		root = xmlDefaultRoot
+		if len(fields) == 1 && !strings.HasPrefix(fields[0].key, "@") && !strings.HasPrefix(fields[0].key, "#") {
+			root, rootValue = fields[0].key, fields[0].value
+		}
+	}
+	if _, ok := rootValue.([]interface{}); ok {
+		return "", fmt.Errorf("root element <%s> cannot be an array", root)
+	}
+
+	var declarations []xmlField
+	if config.Namespace != "" {
+		declarations = append(declarations, xmlField{key: "@xmlns", value: config.Namespace})
+	}
+	prefixes := make([]string, 0, len(config.Namespaces))
+	for prefix := range config.Namespaces {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		declarations = append(declarations, xmlField{key: "@xmlns:" + prefix, value: config.Namespaces[prefix]})
+	}
+	if len(declarations) > 0 {
+		rootFields, ok := rootValue.([]xmlField)
+		if !ok {
+			rootFields = []xmlField{{key: "#text", value: rootValue}}
+		}
+		rootValue = append(declarations, rootFields...)
+	}
+
+	var buf bytes.Buffer
+	if config.Declaration {
+		buf.WriteString(xml.Header)
+	}
+	w := &xmlWriter{buf: &buf, indent: config.Indent}
+	if err := w.element(root, rootValue, 0); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// decodeOrderedJSON decodes the next JSON value, returning objects as
+// []xmlField so key order is kept.
+func decodeOrderedJSON(decoder *json.Decoder, depth int) (interface{}, error) {
+	if depth > maxXMLDepth {
+		return nil, fmt.Errorf("values nested deeper than %d levels", maxXMLDepth)
+	}
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+	switch delim {
+	case '{':
+		fields := make([]xmlField, 0)
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedJSON(decoder, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, xmlField{key: keyToken.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return fields, err
+	case '[':
+		items := make([]interface{}, 0)
+		for decoder.More() {
+			value, err := decodeOrderedJSON(decoder, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		_, err = decoder.Token()
+		return items, err
+	}
+	return nil, fmt.Errorf("unexpected delimiter %v", delim)
+}
+
+type xmlWriter struct {
+	buf    *bytes.Buffer
+	indent string
+}
+
+func (w *xmlWriter) newline(depth int) {
+	if w.indent == "" {
+		return
+	}
+	w.buf.WriteByte('\n')
+	w.buf.WriteString(strings.Repeat(w.indent, depth))
+}
+
+// element writes <name> for value. Arrays are handled by the caller, which
+// repeats the element for each item.
+func (w *xmlWriter) element(name string, value interface{}, depth int) error {
+	if !isXMLName(name) {
+		return fmt.Errorf("invalid element name %q", name)
+	}
+
+	fields, isObject := value.([]xmlField)
+	if !isObject {
+		w.buf.WriteString("<" + name)
+		if value == nil {
+			w.buf.WriteString("/>")
+			return nil
+		}
+		w.buf.WriteByte('>')
+		w.text(xmlScalar(value))
+		w.buf.WriteString("</" + name + ">")
+		return nil
+	}
+
+	w.buf.WriteString("<" + name)
+	var content []xmlField
+	for _, field := range fields {
+		if !strings.HasPrefix(field.key, "@") {
+			content = append(content, field)
+			continue
+		}
+		attr := strings.TrimPrefix(field.key, "@")
+		if !isXMLName(attr) {
+			return fmt.Errorf("invalid attribute name %q on <%s>", attr, name)
+		}
+		if _, nested := field.value.([]xmlField); nested {
+			return fmt.Errorf("attribute %q on <%s> must be a scalar", attr, name)
+		}
+		if _, list := field.value.([]interface{}); list {
+			return fmt.Errorf("attribute %q on <%s> must be a scalar", attr, name)
+		}
+		w.buf.WriteString(" " + attr + `="`)
+		_ = xml.EscapeText(w.buf, []byte(xmlScalar(field.value)))
+		w.buf.WriteByte('"')
+	}
+	if len(content) == 0 {
+		w.buf.WriteString("/>")
+		return nil
+	}
+	w.buf.WriteByte('>')
+
+	hasChildren := false
+	for _, field := range content {
+		switch field.key {
+		case "#text":
+			w.text(xmlScalar(field.value))
+		case "#cdata":
+			w.cdata(xmlScalar(field.value))
+		default:
+			items, isList := field.value.([]interface{})
+			if !isList {
+				items = []interface{}{field.value}
+			}
+			for _, item := range items {
+				if _, nestedList := item.([]interface{}); nestedList {
+					return fmt.Errorf("element <%s> cannot hold nested arrays", field.key)
+				}
+				hasChildren = true
+				w.newline(depth + 1)
+				if err := w.element(field.key, item, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if hasChildren {
+		w.newline(depth)
+	}
+	w.buf.WriteString("</" + name + ">")
+	return nil
+}
+
+func (w *xmlWriter) text(s string) {
+	_ = xml.EscapeText(w.buf, []byte(s))
+}
+
+// cdata writes s as a CDATA section, splitting it where s contains "]]>".
+func (w *xmlWriter) cdata(s string) {
+	w.buf.WriteString("<![CDATA[")
+	w.buf.WriteString(strings.ReplaceAll(s, "]]>", "]]]]><![CDATA[>"))
+	w.buf.WriteString("]]>")
+}
+
+// xmlScalar renders a decoded JSON scalar as XML text.
+func xmlScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// isXMLName reports whether name is a valid element or attribute name,
+// optionally with a single namespace prefix.
+func isXMLName(name string) bool {
+	if name == "" || strings.Count(name, ":") > 1 {
+		return false
+	}
+	for _, part := range strings.Split(name, ":") {
+		if part == "" {
+			return false
+		}
+		for i, r := range part {
+			if r == '_' || unicode.IsLetter(r) {
+				continue
+			}
+			if i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)) {
+				continue
+			}
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func runXML(t *testing.T, config, input string) (*ExecuteResponse, map[string]interface{}) {
+	t.Helper()
+	resp, err := NewXMLExecutor().Execute(context.Background(), &ExecuteRequest{
+		NodeID: "xml-1",
+		Config: json.RawMessage(config),
+		Input:  json.RawMessage(input),
+	})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	var output map[string]interface{}
+	if resp.Error == nil {
+		if err := json.Unmarshal(resp.Output, &output); err != nil {
+			t.Fatalf("decode output: %v", err)
+		}
+	}
+	return resp, output
+}
+
+func TestXMLExecutorFromXML(t *testing.T) {
+	doc, _ := json.Marshal(`<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <order id="42">
+      <item sku="a">Widget</item>
+      <item sku="b"/>
+      <note><![CDATA[<b>fragile</b>]]></note>
+      <tag>x</tag>
+    </order>
+  </soap:Body>
+</soap:Envelope>`)
+
+	resp, output := runXML(t, `{"mode":"from_xml","force_array":["tag"]}`, string(doc))
+	if resp.Error != nil {
+		t.Fatalf("from_xml failed: %s", resp.Error.Message)
+	}
+	envelope := output["data"].(map[string]interface{})["soap:Envelope"].(map[string]interface{})
+	if envelope["@xmlns:soap"] != "http://schemas.xmlsoap.org/soap/envelope/" {
+		t.Fatalf("namespace declaration not kept: %v", envelope)
+	}
+	order := envelope["soap:Body"].(map[string]interface{})["order"].(map[string]interface{})
+	items := order["item"].([]interface{})
+	if order["@id"] != "42" || len(items) != 2 || order["note"] != "<b>fragile</b>" {
+		t.Fatalf("order = %v", order)
+	}
+	first := items[0].(map[string]interface{})
+	if first["@sku"] != "a" || first["#text"] != "Widget" {
+		t.Fatalf("first item = %v", first)
+	}
+	if tags, ok := order["tag"].([]interface{}); !ok || len(tags) != 1 || tags[0] != "x" {
+		t.Fatalf("forced array tag = %v", order["tag"])
+	}
+}
+
+func TestXMLExecutorToXML(t *testing.T) {
+	input := `{"order":{"@id":42,"item":[{"@sku":"a","#text":"Widget & co"},"Gadget"],"note":{"#cdata":"<b>x</b>"},"gift":false,"memo":null}}`
+
+	resp, output := runXML(t, `{"mode":"to_xml","namespaces":{"ns":"urn:orders"}}`, input)
+	if resp.Error != nil {
+		t.Fatalf("to_xml failed: %s", resp.Error.Message)
+	}
+	want := `<order xmlns:ns="urn:orders" id="42"><item sku="a">Widget &amp; co</item><item>Gadget</item>` +
+		`<note><![CDATA[<b>x</b>]]></note><gift>false</gift><memo/></order>`
+	if output["xml"] != want {
+		t.Fatalf("xml = %s\nwant  %s", output["xml"], want)
+	}
+
+	// Converting back yields the same structure, as strings.
+	doc, _ := json.Marshal(output["xml"])
+	resp, parsed := runXML(t, `{"mode":"from_xml"}`, string(doc))
+	if resp.Error != nil {
+		t.Fatalf("round trip failed: %s", resp.Error.Message)
+	}
+	order := parsed["data"].(map[string]interface{})["order"].(map[string]interface{})
+	if order["@id"] != "42" || order["note"] != "<b>x</b>" || order["gift"] != "false" || order["memo"] != "" {
+		t.Fatalf("round trip = %v", order)
+	}
+
+	_, output = runXML(t, `{"mode":"to_xml","root":"Request","declaration":true,"indent":"  "}`, `{"a":"1","b":{"c":"2"}}`)
+	if xml := output["xml"].(string); !strings.HasPrefix(xml, "<?xml") || !strings.Contains(xml, "\n  <b>\n    <c>2</c>\n  </b>\n</Request>") {
+		t.Fatalf("indented xml = %s", xml)
+	}
+}
+
+func TestXMLExecutorErrors(t *testing.T) {
+	tests := []struct {
+		config string
+		input  string
+		want   string
+	}{
+		{`{"mode":"from_xml","xml":"<a>\n<b></a>"}`, `null`, "line 2"},
+		{`{"mode":"from_xml","xml":"<a></a><b/>"}`, `null`, "content after the root element"},
+		{`{"mode":"from_xml","xml":"<a>"}`, `null`, "not closed"},
+		{`{"mode":"from_xml"}`, `{"x":1}`, "xml is required"},
+		{`{"mode":"to_xml"}`, `[1,2]`, "data must be an object"},
+		{`{"mode":"to_xml"}`, `{"1bad":"x"}`, "invalid element name"},
+		{`{"mode":"to_xml"}`, `{"a":{"@b":{"c":1}}}`, "must be a scalar"},
+		{`{"mode":"transform"}`, `{}`, "unknown mode"},
+	}
+	for _, tt := range tests {
+		resp, _ := runXML(t, tt.config, tt.input)
+		if resp.Error == nil || resp.Error.Type != ErrorTypeNonRetryable || !strings.Contains(resp.Error.Message, tt.want) {
+			t.Fatalf("%s: error = %+v, want %q", tt.config, resp.Error, tt.want)
+		}
+	}
+}
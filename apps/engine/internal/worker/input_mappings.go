@@ -0,0 +1,294 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	"github.com/linkflow/engine/internal/expression"
+	"github.com/linkflow/engine/internal/worker/executor"
+	"github.com/linkflow/engine/internal/worker/poller"
+)
+
+// inputMappingsConfig holds the input_mappings key shared by all node
+// configs. Each entry maps a target field of the node input, dotted for
+// nested fields, to an expression over the mapping scope.
+type inputMappingsConfig struct {
+	InputMappings map[string]string `json:"input_mappings"`
+}
+
+// inputMappingScope is the data input mapping expressions are evaluated
+// against: $.input is the workflow input, $.nodes.<id>.output the output of
+// each completed node, $.vars.<name> each workflow variable and $.context
+// the execution's metadata.
+type inputMappingScope struct {
+	Input   interface{}                       `json:"input"`
+	Nodes   map[string]map[string]interface{} `json:"nodes"`
+	Vars    map[string]interface{}            `json:"vars"`
+	Context *executionContext                 `json:"context,omitempty"`
+}
+
+// executionContext is the read-only $.context object describing the
+// execution and the attempt running the node:
+//
+//	$.context.namespace            namespace of the execution
+//	$.context.workflow_id          workflow ID
+//	$.context.run_id               run ID
+//	$.context.node_id              ID of the node being run
+//	$.context.node_type            type of the node being run
+//	$.context.attempt              attempt number of the node's task
+//	$.context.started_at           when this attempt started (RFC 3339)
+//	$.context.workflow_started_at  when the run started (RFC 3339)
+//
+// Every field is the same when the node is replayed: the attempt comes from
+// the task, started_at from the deterministic time source and
+// workflow_started_at from history.
+type executionContext struct {
+	Namespace         string `json:"namespace"`
+	WorkflowID        string `json:"workflow_id"`
+	RunID             string `json:"run_id"`
+	NodeID            string `json:"node_id"`
+	NodeType          string `json:"node_type"`
+	Attempt           int32  `json:"attempt"`
+	StartedAt         string `json:"started_at"`
+	WorkflowStartedAt string `json:"workflow_started_at,omitempty"`
+}
+
+// newExecutionContext builds the $.context object for req. It reads the
+// deterministic clock, so it is called once per attempt.
+func newExecutionContext(req *executor.ExecuteRequest) *executionContext {
+	return &executionContext{
+		Namespace:  req.Namespace,
+		WorkflowID: req.WorkflowID,
+		RunID:      req.RunID,
+		NodeID:     req.NodeID,
+		NodeType:   req.NodeType,
+		Attempt:    req.Attempt,
+		StartedAt:  req.Deterministic.Now().UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// inputMappingError reports input mappings that cannot be applied to the
+// task's input.
+type inputMappingError struct {
+	target string
+	err    error
+}
+
+func (e *inputMappingError) Error() string {
+	if e.target == "" {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("input mapping %q: %v", e.target, e.err)
+}
+
+func (e *inputMappingError) Unwrap() error { return e.err }
+
+// taskInputMappings returns the input mappings declared by the task's node
+// config, if any.
+func taskInputMappings(task *poller.Task) map[string]string {
+	var config inputMappingsConfig
+	if len(task.Config) == 0 || json.Unmarshal(task.Config, &config) != nil {
+		return nil
+	}
+	return config.InputMappings
+}
+
+// applyInputMappings resolves the node's input mappings and returns the input
+// to execute it with. Tasks without mappings keep their input unchanged.
+// Mappings that cannot be resolved are reported as an *inputMappingError.
+func (s *Service) applyInputMappings(task *poller.Task, loadScope func() (*inputMappingScope, error)) ([]byte, error) {
+	mappings := taskInputMappings(task)
+	if len(mappings) == 0 {
+		return task.Input, nil
+	}
+
+	scope, err := loadScope()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load input mapping scope: %w", err)
+	}
+	return resolveInputMappings(s.expressions, mappings, task.Input, scope)
+}
+
+// scopeLoader returns a function that loads the input mapping scope of the
+// task executed by req on first use and returns the same scope afterwards.
+func (s *Service) scopeLoader(ctx context.Context, task *poller.Task, req *executor.ExecuteRequest, payload *executor.JobPayload) func() (*inputMappingScope, error) {
+	var scope *inputMappingScope
+	var err error
+	return func() (*inputMappingScope, error) {
+		if scope == nil && err == nil {
+			scope, err = s.loadInputMappingScope(ctx, task, req, payload)
+		}
+		return scope, err
+	}
+}
+
+// executorScope returns scope as the plain JSON values executors evaluate
+// expressions against.
+func executorScope(scope *inputMappingScope) (map[string]interface{}, error) {
+	scopeBytes, err := json.Marshal(scope)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(scopeBytes, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// loadInputMappingScope replays the execution's history for the outputs of
+// its completed nodes and child workflows and the workflow variables set by
+// set_variable nodes, in event order, and describes the execution in
+// $.context.
+func (s *Service) loadInputMappingScope(ctx context.Context, task *poller.Task, req *executor.ExecuteRequest, payload *executor.JobPayload) (*inputMappingScope, error) {
+	historyResp, err := s.historyClient.GetHistory(ctx, task.Namespace, task.WorkflowID, task.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := &inputMappingScope{
+		Nodes:   make(map[string]map[string]interface{}),
+		Vars:    make(map[string]interface{}),
+		Context: newExecutionContext(req),
+	}
+	if payload != nil {
+		scope.Input = payload.TriggerData
+	}
+
+	addOutput := func(nodeID string, result *commonv1.Payloads) {
+		var output interface{}
+		if result != nil && len(result.GetPayloads()) > 0 {
+			if err := json.Unmarshal(result.GetPayloads()[0].GetData(), &output); err != nil {
+				output = string(result.GetPayloads()[0].GetData())
+			}
+		}
+		scope.Nodes[nodeID] = map[string]interface{}{"output": output}
+	}
+
+	scheduledNodes := make(map[int64]string)
+	variableNodes := make(map[int64]bool)
+	for _, event := range historyResp.GetHistory().GetEvents() {
+		switch event.GetEventType() {
+		case commonv1.EventType_EVENT_TYPE_EXECUTION_STARTED:
+			if started := event.GetEventTime(); started != nil {
+				scope.Context.WorkflowStartedAt = started.AsTime().UTC().Format(time.RFC3339Nano)
+			}
+		case commonv1.EventType_EVENT_TYPE_NODE_SCHEDULED:
+			if attr := event.GetNodeScheduledAttributes(); attr != nil {
+				scheduledNodes[event.GetEventId()] = attr.GetNodeId()
+				variableNodes[event.GetEventId()] = attr.GetNodeType() == "set_variable"
+			}
+		case commonv1.EventType_EVENT_TYPE_NODE_COMPLETED:
+			attr := event.GetNodeCompletedAttributes()
+			if nodeID, ok := scheduledNodes[attr.GetScheduledEventId()]; ok {
+				addOutput(nodeID, attr.GetResult())
+			}
+			if variableNodes[attr.GetScheduledEventId()] {
+				setScopeVariable(scope.Vars, attr.GetResult())
+			}
+		case commonv1.EventType_EVENT_TYPE_CHILD_WORKFLOW_COMPLETED:
+			attr := event.GetChildWorkflowCompletedAttributes()
+			if attr.GetStatus() == commonv1.ExecutionStatus_EXECUTION_STATUS_COMPLETED {
+				addOutput(attr.GetNodeId(), attr.GetResult())
+			}
+		}
+	}
+	return scope, nil
+}
+
+// setScopeVariable merges the variable a set_variable node completed with
+// into vars, the same way history does for the execution's mutable state.
+func setScopeVariable(vars map[string]interface{}, result *commonv1.Payloads) {
+	if result == nil || len(result.GetPayloads()) == 0 {
+		return
+	}
+	var variable executor.SetVariableResponse
+	if err := json.Unmarshal(result.GetPayloads()[0].GetData(), &variable); err != nil || variable.Name == "" {
+		return
+	}
+	var value interface{}
+	if len(variable.Value) > 0 {
+		if err := json.Unmarshal(variable.Value, &value); err != nil {
+			return
+		}
+	}
+	vars[variable.Name] = value
+}
+
+// resolveInputMappings evaluates each mapping against scope and sets the
+// result on a copy of input, which must be empty or a JSON object. Mappings
+// are applied in target order so nested targets are set deterministically.
+func resolveInputMappings(engine *expression.Engine, mappings map[string]string, input []byte, scope *inputMappingScope) ([]byte, error) {
+	merged := make(map[string]interface{})
+	if len(input) > 0 && string(input) != "null" {
+		if err := json.Unmarshal(input, &merged); err != nil {
+			return nil, &inputMappingError{err: fmt.Errorf("input mappings require an object input: %w", err)}
+		}
+	}
+
+	// Evaluate against plain JSON values so paths resolve the same way they
+	// do for expressions elsewhere.
+	scopeBytes, err := json.Marshal(scope)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	if err := json.Unmarshal(scopeBytes, &data); err != nil {
+		return nil, err
+	}
+
+	targets := make([]string, 0, len(mappings))
+	for target := range mappings {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		expr := mappings[target]
+		value, err := engine.Evaluate(expr, data)
+		if err != nil {
+			if errors.Is(err, expression.ErrPathNotFound) {
+				err = fmt.Errorf("unresolved reference %q", expr)
+			} else {
+				err = fmt.Errorf("failed to evaluate %q: %w", expr, err)
+			}
+			return nil, &inputMappingError{target: target, err: err}
+		}
+		if err := setInputField(merged, target, value); err != nil {
+			return nil, &inputMappingError{target: target, err: err}
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// setInputField sets the dotted path target in input, creating intermediate
+// objects as needed.
+func setInputField(input map[string]interface{}, target string, value interface{}) error {
+	parts := strings.Split(target, ".")
+	current := input
+	for i, part := range parts {
+		if part == "" {
+			return fmt.Errorf("invalid target field %q", target)
+		}
+		if i == len(parts)-1 {
+			current[part] = value
+			return nil
+		}
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			if _, exists := current[part]; exists {
+				return fmt.Errorf("target field %q is not an object", strings.Join(parts[:i+1], "."))
+			}
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/expression"
+	"github.com/linkflow/engine/internal/worker/executor"
+)
+
+func TestResolveInputMappings(t *testing.T) {
+	engine := expression.NewEngine()
+	scope := &inputMappingScope{
+		Input: map[string]interface{}{"order_id": "o-1"},
+		Nodes: map[string]map[string]interface{}{
+			"fetch_user": {"output": map[string]interface{}{"email": "ada@example.com", "tier": "gold"}},
+		},
+		Vars: map[string]interface{}{"retries": float64(2)},
+	}
+
+	got, err := resolveInputMappings(engine, map[string]string{
+		"to":              "$.nodes.fetch_user.output.email",
+		"meta.order":      "$.input.order_id",
+		"meta.user_tier":  "$.nodes.fetch_user.output.tier",
+		"subject":         "Order {{$.input.order_id}}",
+		"existing.nested": "$.input.order_id",
+		"meta.retries":    "$.vars.retries",
+	}, []byte(`{"priority":"high","existing":{"kept":true}}`), scope)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	var input map[string]interface{}
+	if err := json.Unmarshal(got, &input); err != nil {
+		t.Fatalf("decode %s: %v", got, err)
+	}
+	meta, _ := input["meta"].(map[string]interface{})
+	existing, _ := input["existing"].(map[string]interface{})
+	if input["to"] != "ada@example.com" || input["subject"] != "Order o-1" || input["priority"] != "high" ||
+		meta["order"] != "o-1" || meta["user_tier"] != "gold" || meta["retries"] != float64(2) || existing["kept"] != true || existing["nested"] != "o-1" {
+		t.Fatalf("merged input = %s", got)
+	}
+
+	_, err = resolveInputMappings(engine, map[string]string{"to": "$.nodes.missing.output.email"}, nil, scope)
+	var mappingErr *inputMappingError
+	if !errors.As(err, &mappingErr) || !strings.Contains(err.Error(), `unresolved reference "$.nodes.missing.output.email"`) {
+		t.Fatalf("unresolved reference error = %v", err)
+	}
+
+	if _, err := resolveInputMappings(engine, map[string]string{"to": "$.input.order_id"}, []byte(`[1,2]`), scope); !errors.As(err, &mappingErr) {
+		t.Fatalf("array input error = %v, want an input mapping error", err)
+	}
+}
+
+func TestExecutionContextIsReplayed(t *testing.T) {
+	engine := expression.NewEngine()
+	mappings := map[string]string{
+		"run":     "$.context.run_id",
+		"attempt": "$.context.attempt",
+		"node":    "$.context.node_id",
+		"at":      "$.context.started_at",
+	}
+	resolve := func(deterministic *executor.DeterministicContext) map[string]interface{} {
+		t.Helper()
+		req := &executor.ExecuteRequest{
+			NodeType:      "http",
+			NodeID:        "fetch",
+			WorkflowID:    "order",
+			RunID:         "run-1",
+			Namespace:     "default",
+			Attempt:       3,
+			Deterministic: deterministic,
+		}
+		deterministic.BindNode(req.NodeID, req.NodeType)
+		got, err := resolveInputMappings(engine, mappings, nil, &inputMappingScope{Context: newExecutionContext(req)})
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		var input map[string]interface{}
+		if err := json.Unmarshal(got, &input); err != nil {
+			t.Fatalf("decode %s: %v", got, err)
+		}
+		return input
+	}
+
+	capture := &executor.DeterministicContext{Mode: "capture"}
+	captured := resolve(capture)
+	if captured["run"] != "run-1" || captured["attempt"] != float64(3) || captured["node"] != "fetch" || captured["at"] == "" {
+		t.Fatalf("context input = %v", captured)
+	}
+
+	fixture, ok := capture.GeneratedFixture()
+	if !ok {
+		t.Fatal("started_at was not recorded for replay")
+	}
+	time.Sleep(time.Millisecond)
+	replayed := resolve(&executor.DeterministicContext{Mode: "replay", Fixtures: []executor.DeterministicFixture{fixture}})
+	if replayed["at"] != captured["at"] {
+		t.Fatalf("replayed started_at = %v, want %v", replayed["at"], captured["at"])
+	}
+}
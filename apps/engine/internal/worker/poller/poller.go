@@ -21,6 +21,13 @@ type Task struct {
 	Attempt          int32                  `json:"attempt"`
 	TimeoutSec       int32                  `json:"timeout_sec"`
 	ScheduledEventID int64                  `json:"scheduled_event_id"`
+
+	// Activity timeouts recorded by history on the scheduled event. The
+	// start-to-close timeout bounds each attempt; the schedule-to-close
+	// timeout bounds all attempts, counted from ScheduledTime.
+	StartToCloseSec    int32     `json:"start_to_close_sec,omitempty"`
+	ScheduleToCloseSec int32     `json:"schedule_to_close_sec,omitempty"`
+	ScheduledTime      time.Time `json:"scheduled_time,omitempty"`
 }
 
 type TaskResult struct {
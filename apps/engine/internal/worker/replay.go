@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/linkflow/engine/internal/history/replay"
+	"github.com/linkflow/engine/internal/worker/executor"
+)
+
+// maxReplayDecisionBytes bounds the history a replay decision may carry.
+const maxReplayDecisionBytes = 64 << 20
+
+// ReplayDecisionHandler serves decisions in replay mode for history's replay
+// validation: it runs decider on the history in the request and returns the
+// commands it would send, without fetching history or responding to it.
+func ReplayDecisionHandler(decider *executor.WorkflowExecutor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req replay.DecisionRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReplayDecisionBytes)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid decision request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.Mode != replay.DecisionMode {
+			http.Error(w, fmt.Sprintf("unsupported decision mode %q", req.Mode), http.StatusBadRequest)
+			return
+		}
+		events, err := req.Events()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		commands, err := decider.Replay(&executor.ExecuteRequest{
+			NodeType:   "workflow",
+			WorkflowID: req.WorkflowID,
+			RunID:      req.RunID,
+			Namespace:  req.Namespace,
+		}, events)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		resp, err := replay.NewDecisionResponse(commands)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
+	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"github.com/linkflow/engine/internal/history/replay"
+	"github.com/linkflow/engine/internal/worker/executor"
+)
+
+func TestReplayDecisionHandlerDecidesFromRequestHistory(t *testing.T) {
+	payload, _ := json.Marshal(executor.JobPayload{Workflow: executor.WorkflowDefinition{
+		Nodes: []executor.Node{{ID: "start", Type: "trigger_manual"}, {ID: "fetch", Type: "action_http_request"}},
+		Edges: []executor.Edge{{Source: "start", Target: "fetch"}},
+	}})
+	started := &historyv1.HistoryEvent{
+		EventId:   1,
+		EventType: commonv1.EventType_EVENT_TYPE_EXECUTION_STARTED,
+		Attributes: &historyv1.HistoryEvent_ExecutionStartedAttributes{ExecutionStartedAttributes: &historyv1.ExecutionStartedEventAttributes{
+			Input: &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: payload}}},
+		}},
+	}
+	history, err := replay.EncodeHistory([]*historyv1.HistoryEvent{started})
+	if err != nil {
+		t.Fatalf("encode history: %v", err)
+	}
+
+	// The decider has no history client: a replay decision must not need one.
+	handler := ReplayDecisionHandler(executor.NewWorkflowExecutor(nil, slog.New(slog.NewTextHandler(io.Discard, nil))))
+	decide := func(req replay.DecisionRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/replay/decide", bytes.NewReader(body)))
+		return rec
+	}
+
+	rec := decide(replay.DecisionRequest{Mode: replay.DecisionMode, WorkflowID: "order", DecisionEventID: 2, History: history})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp replay.DecisionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	commands, err := resp.Decode()
+	if err != nil || len(commands) != 1 || commands[0].GetScheduleActivityTaskAttributes().GetNodeId() != "start" {
+		t.Fatalf("commands = %v, %v; want start scheduled", commands, err)
+	}
+
+	if rec := decide(replay.DecisionRequest{Mode: "live", History: history}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("live mode status = %d, want 400", rec.Code)
+	}
+}
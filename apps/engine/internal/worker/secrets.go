@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/linkflow/engine/internal/worker/executor"
+)
+
+// secretResolutionError is returned when a node's secret references cannot
+// be resolved; the node fails without running.
+type secretResolutionError struct {
+	err error
+}
+
+func (e *secretResolutionError) Error() string {
+	return fmt.Sprintf("failed to resolve secrets: %v", e.err)
+}
+
+func (e *secretResolutionError) Unwrap() error { return e.err }
+
+// errorType reports a missing or malformed reference as non-retryable, since
+// it fails the same way on every attempt, and a store outage as retryable.
+func (e *secretResolutionError) errorType() string {
+	if errors.Is(e.err, executor.ErrSecretNotFound) || errors.Is(e.err, executor.ErrInvalidSecretRef) {
+		return executor.ErrorTypeNonRetryable
+	}
+	return executor.ErrorTypeRetryable
+}
+
+// resolveSecrets replaces the secret references in req.Config for executors
+// that accept them. The returned redactor masks the resolved values in what
+// the node reports back; it is nil when nothing was resolved.
+func (s *Service) resolveSecrets(ctx context.Context, exec executor.Executor, req *executor.ExecuteRequest) (*executor.SecretRedactor, error) {
+	consumer, ok := exec.(executor.SecretConsumer)
+	if !ok || !consumer.UsesSecrets() {
+		return nil, nil
+	}
+	config, redactor, err := executor.ResolveSecretRefs(ctx, s.secretStore, req.Namespace, req.Config)
+	if err != nil {
+		return nil, &secretResolutionError{err: err}
+	}
+	req.Config = config
+	return redactor, nil
+}
+
+// redactError masks resolved secrets in an execution error.
+func redactError(redactor *executor.SecretRedactor, err error) error {
+	if err == nil || redactor == nil {
+		return err
+	}
+	if redacted := redactor.Redact(err.Error()); redacted != err.Error() {
+		return errors.New(redacted)
+	}
+	return err
+}
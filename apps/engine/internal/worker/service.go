@@ -7,17 +7,21 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/linkflow/engine/internal/execution/timeline"
+	"github.com/linkflow/engine/internal/expression"
+	"github.com/linkflow/engine/internal/observability/tracing"
 	"github.com/linkflow/engine/internal/worker/adapter"
 	"github.com/linkflow/engine/internal/worker/executor"
 	"github.com/linkflow/engine/internal/worker/poller"
@@ -25,16 +29,25 @@ import (
 
 	commonv1 "github.com/linkflow/engine/api/gen/linkflow/common/v1"
 	historyv1 "github.com/linkflow/engine/api/gen/linkflow/history/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type Service struct {
 	historyClient *adapter.HistoryClient
+	completions   *completionBatcher
 	matchingConn  *grpc.ClientConn
 	executors     map[string]executor.Executor
 	taskPollers   []*poller.Poller
 	retryPolicy   *retry.Policy
 	callbackHTTP  *http.Client
+	callbackRetry *retry.Policy
+	callbackSleep func(time.Duration)
 	callbackKey   string
+	identity      string
+	asyncTimeout  time.Duration
+	secretStore   executor.SecretStore
+	tracer        *tracing.Tracer
+	expressions   *expression.Engine
 	logger        *slog.Logger
 	wg            sync.WaitGroup
 	stopCh        chan struct{}
@@ -54,6 +67,26 @@ type Config struct {
 	CallbackTimeout time.Duration
 	Logger          *slog.Logger
 	HistoryClient   *adapter.HistoryClient
+
+	// CallbackRetryPolicy controls legacy callback delivery retries. Only
+	// MaximumAttempts, InitialInterval, BackoffCoefficient and MaximumInterval
+	// are used (default DefaultCallbackRetryPolicy).
+	CallbackRetryPolicy *retry.Policy
+
+	// AsyncActivityTimeout is the default ScheduleToClose timeout for activities
+	// that complete out-of-band (default 24h).
+	AsyncActivityTimeout time.Duration
+
+	// CompletionBatching sends activity completions to history in batches
+	// (disabled by default).
+	CompletionBatching CompletionBatchConfig
+
+	// SecretStore resolves {"$secret": name} references in the config of
+	// executors that accept them. Without one, such references fail the node.
+	SecretStore executor.SecretStore
+
+	// Tracer records a span for each node executed. Nil disables tracing.
+	Tracer *tracing.Tracer
 }
 
 // NewService creates a new worker service.
@@ -73,6 +106,12 @@ func NewService(cfg Config) (*Service, error) {
 	if cfg.CallbackTimeout <= 0 {
 		cfg.CallbackTimeout = 10 * time.Second
 	}
+	if cfg.AsyncActivityTimeout <= 0 {
+		cfg.AsyncActivityTimeout = 24 * time.Hour
+	}
+	if cfg.CallbackRetryPolicy == nil {
+		cfg.CallbackRetryPolicy = DefaultCallbackRetryPolicy()
+	}
 	if cfg.MatchingAddr == "" {
 		return nil, fmt.Errorf("matching service address is required")
 	}
@@ -117,9 +156,19 @@ func NewService(cfg Config) (*Service, error) {
 		callbackHTTP: &http.Client{
 			Timeout: cfg.CallbackTimeout,
 		},
-		callbackKey: cfg.CallbackKey,
-		logger:      cfg.Logger,
-		stopCh:      make(chan struct{}),
+		callbackRetry: cfg.CallbackRetryPolicy,
+		callbackKey:   cfg.CallbackKey,
+		identity:      cfg.Identity,
+		asyncTimeout:  cfg.AsyncActivityTimeout,
+		secretStore:   cfg.SecretStore,
+		tracer:        cfg.Tracer,
+		expressions:   expression.NewEngine(),
+		logger:        cfg.Logger,
+		stopCh:        make(chan struct{}),
+	}
+
+	if cfg.CompletionBatching.Enabled && cfg.HistoryClient != nil {
+		svc.completions = newCompletionBatcher(cfg.HistoryClient, cfg.CompletionBatching, cfg.Logger)
 	}
 
 	for _, p := range pollers {
@@ -134,6 +183,16 @@ func (s *Service) RegisterExecutor(exec executor.Executor) {
 	defer s.mu.Unlock()
 	s.executors[exec.NodeType()] = exec
 	s.logger.Info("registered executor", slog.String("node_type", exec.NodeType()))
+
+	if p, ok := exec.(executor.AliasProvider); ok {
+		for _, alias := range p.Aliases() {
+			s.executors[alias] = exec
+			s.logger.Info("registered executor alias",
+				slog.String("alias", alias),
+				slog.String("node_type", exec.NodeType()),
+			)
+		}
+	}
 }
 
 func (s *Service) Start(ctx context.Context) error {
@@ -146,6 +205,10 @@ func (s *Service) Start(ctx context.Context) error {
 	s.stopCh = make(chan struct{})
 	s.mu.Unlock()
 
+	if s.completions != nil {
+		s.completions.start()
+	}
+
 	for _, p := range s.taskPollers {
 		if err := p.Start(ctx); err != nil {
 			return fmt.Errorf("failed to start task poller: %w", err)
@@ -171,6 +234,10 @@ func (s *Service) Stop() error {
 	}
 	s.wg.Wait()
 
+	if s.completions != nil {
+		s.completions.stop()
+	}
+
 	if s.matchingConn != nil {
 		if err := s.matchingConn.Close(); err != nil {
 			s.logger.Warn("failed to close matching connection", slog.String("error", err.Error()))
@@ -228,7 +295,9 @@ func (s *Service) processWorkflowTask(ctx context.Context, task *poller.Task) (*
 		Timeout:    30 * time.Second,
 	}
 
-	resp, err := exec.Execute(ctx, req)
+	resp, err := s.executeTraced(ctx, req, func(ctx context.Context) (*executor.ExecuteResponse, error) {
+		return exec.Execute(ctx, req)
+	})
 	if err != nil {
 		s.logger.Error("workflow execution failed", slog.String("error", err.Error()))
 		// Respond failed
@@ -321,10 +390,69 @@ func (s *Service) processActivityTask(ctx context.Context, task *poller.Task) (*
 		Input:         task.Input,
 		Deterministic: deterministicFromTask(task.Deterministic),
 		Attempt:       task.Attempt,
-		Timeout:       time.Duration(task.TimeoutSec) * time.Second,
+		Progress:      s.partialProgressReporter(jobPayload, task.NodeID),
+	}
+	if consumer, ok := exec.(executor.JobPayloadConsumer); ok && consumer.UsesJobPayload() {
+		req.Job = jobPayload
+	}
+	req.Deterministic.BindNode(task.NodeID, task.NodeType)
+
+	var resp *executor.ExecuteResponse
+	var err error
+	var mappingErr *inputMappingError
+	var secretErr *secretResolutionError
+	var redactor *executor.SecretRedactor
+	loadScope := s.scopeLoader(ctx, task, req, jobPayload)
+	req.Input, err = s.applyInputMappings(task, loadScope)
+	if consumer, ok := exec.(executor.ScopeConsumer); ok && err == nil && consumer.UsesScope() {
+		var scope *inputMappingScope
+		if scope, err = loadScope(); err == nil {
+			req.Scope, err = executorScope(scope)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to load expression scope: %w", err)
+		}
+	}
+	if err == nil {
+		redactor, err = s.resolveSecrets(ctx, exec, req)
+	}
+	var classifier *executor.RetryClassifier
+	if consumer, ok := exec.(executor.RetryClassifierConsumer); err == nil && (!ok || !consumer.UsesRetryClassifier()) {
+		classifier, err = executor.ParseRetryClassifier(req.Config)
+	}
+	switch {
+	case errors.As(err, &mappingErr), errors.Is(err, executor.ErrInvalidRetryClassifier):
+		// A mapping that cannot be resolved or an invalid retry classifier
+		// fails the same way on every attempt, so the node fails without
+		// running.
+		resp = &executor.ExecuteResponse{
+			Error: &executor.ExecutionError{Message: err.Error(), Type: executor.ErrorTypeNonRetryable},
+		}
+		err = nil
+	case errors.As(err, &secretErr):
+		resp = &executor.ExecuteResponse{
+			Error: &executor.ExecutionError{Message: err.Error(), Type: secretErr.errorType()},
+		}
+		err = nil
+	case err == nil:
+		if !s.recordActivityStarted(ctx, task) {
+			return &poller.TaskResult{TaskID: task.TaskID, ErrorType: "node_not_pending"}, nil
+		}
+		resp, err = s.executeTraced(ctx, req, func(ctx context.Context) (*executor.ExecuteResponse, error) {
+			return executeWithTimeouts(ctx, exec, req, task)
+		})
+		if err == nil {
+			// Connector results are classified by the node's rules, if any,
+			// rather than only by the executor's defaults.
+			classifier.Apply(resp)
+		}
+		if fixture, ok := req.Deterministic.GeneratedFixture(); ok && resp != nil {
+			resp.DeterministicFixtures = append(resp.DeterministicFixtures, fixture)
+		}
+		// Resolved secrets stay out of history, callbacks and fixtures.
+		redactor.RedactResponse(resp)
+		err = redactError(redactor, err)
 	}
-
-	resp, err := exec.Execute(ctx, req)
 
 	// Handle execution result
 	if err != nil {
@@ -336,6 +464,7 @@ func (s *Service) processActivityTask(ctx context.Context, task *poller.Task) (*
 				RunId:      task.RunID,
 			},
 			ScheduledEventId: task.ScheduledEventID,
+			RequestId:        activityRequestID(task),
 			Failure: &commonv1.Failure{
 				Message:     err.Error(),
 				FailureType: commonv1.FailureType_FAILURE_TYPE_ACTIVITY,
@@ -345,7 +474,11 @@ func (s *Service) processActivityTask(ctx context.Context, task *poller.Task) (*
 	}
 
 	if resp.Error != nil {
-		// Logical error (API failure, etc.)
+		// Logical error (API failure, timeout, etc.)
+		failureType := commonv1.FailureType_FAILURE_TYPE_APPLICATION
+		if resp.Error.Type == executor.ErrorTypeTimeout {
+			failureType = commonv1.FailureType_FAILURE_TYPE_TIMEOUT
+		}
 		s.historyClient.RespondActivityTaskFailed(ctx, &historyv1.RespondActivityTaskFailedRequest{
 			Namespace: task.Namespace,
 			WorkflowExecution: &commonv1.WorkflowExecution{
@@ -353,9 +486,10 @@ func (s *Service) processActivityTask(ctx context.Context, task *poller.Task) (*
 				RunId:      task.RunID,
 			},
 			ScheduledEventId: task.ScheduledEventID,
+			RequestId:        activityRequestID(task),
 			Failure: &commonv1.Failure{
 				Message:     resp.Error.Message,
-				FailureType: commonv1.FailureType_FAILURE_TYPE_APPLICATION,
+				FailureType: failureType,
 			},
 		})
 
@@ -363,14 +497,20 @@ func (s *Service) processActivityTask(ctx context.Context, task *poller.Task) (*
 		return &poller.TaskResult{Error: resp.Error.Message}, nil
 	}
 
+	if resp.Pending != nil {
+		s.sendLegacyProgress(jobPayload, task.NodeID, 50, resp)
+		return s.recordActivityPending(ctx, task, resp)
+	}
+
 	// Success
-	_, err = s.historyClient.RespondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{
+	_, err = s.respondActivityTaskCompleted(ctx, &historyv1.RespondActivityTaskCompletedRequest{
 		Namespace: task.Namespace,
 		WorkflowExecution: &commonv1.WorkflowExecution{
 			WorkflowId: task.WorkflowID,
 			RunId:      task.RunID,
 		},
 		ScheduledEventId: task.ScheduledEventID,
+		RequestId:        activityRequestID(task),
 		Result: &commonv1.Payloads{
 			Payloads: []*commonv1.Payload{{Data: resp.Output}},
 		},
@@ -378,6 +518,18 @@ func (s *Service) processActivityTask(ctx context.Context, task *poller.Task) (*
 
 	s.sendLegacyProgress(jobPayload, task.NodeID, 80, resp)
 
+	if adapter.IsNodeNotPending(err) {
+		// The node closed before this result arrived, e.g. a redelivered
+		// task; drop it rather than have the task redelivered again.
+		s.logger.Warn("history rejected result for a node that is not pending",
+			slog.String("workflow_id", task.WorkflowID),
+			slog.String("node_id", task.NodeID),
+			slog.Int64("scheduled_event_id", task.ScheduledEventID),
+			slog.String("error", err.Error()),
+		)
+		return &poller.TaskResult{Output: resp.Output, ErrorType: "node_not_pending"}, nil
+	}
+
 	if err != nil {
 		s.sendLegacyCallback(jobPayload, "failed", time.Since(startedAt), map[string]interface{}{"message": err.Error()}, nil)
 	}
@@ -385,6 +537,113 @@ func (s *Service) processActivityTask(ctx context.Context, task *poller.Task) (*
 	return &poller.TaskResult{Output: resp.Output}, err
 }
 
+// recordActivityStarted records in history that this worker is beginning the
+// task's attempt, for schedule-to-start latency and retry visibility. It
+// returns false when the node has already closed, e.g. for a redelivered
+// task, which should then not run. Other failures are logged and the task
+// runs anyway.
+func (s *Service) recordActivityStarted(ctx context.Context, task *poller.Task) bool {
+	if task.ScheduledEventID == 0 || s.historyClient == nil {
+		return true
+	}
+	_, err := s.historyClient.RecordActivityTaskStarted(ctx, task.Namespace, &commonv1.WorkflowExecution{
+		WorkflowId: task.WorkflowID,
+		RunId:      task.RunID,
+	}, task.ScheduledEventID, task.Attempt, s.identity)
+	if adapter.IsNodeNotPending(err) {
+		s.logger.Warn("skipping task for a node that is not pending",
+			slog.String("workflow_id", task.WorkflowID),
+			slog.String("node_id", task.NodeID),
+			slog.Int64("scheduled_event_id", task.ScheduledEventID),
+		)
+		return false
+	}
+	if err != nil {
+		s.logger.Warn("failed to record activity start",
+			slog.String("workflow_id", task.WorkflowID),
+			slog.String("node_id", task.NodeID),
+			slog.String("error", err.Error()),
+		)
+	}
+	return true
+}
+
+// activityRequestID identifies the response to a scheduled activity, so
+// history applies a completion or failure for it at most once even when the
+// worker retries after a lost acknowledgement or the task is redelivered.
+func activityRequestID(task *poller.Task) string {
+	if task.ScheduledEventID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("activity/%d", task.ScheduledEventID)
+}
+
+// respondActivityTaskCompleted reports a completion to history, batched with
+// other completions when completion batching is enabled.
+func (s *Service) respondActivityTaskCompleted(ctx context.Context, req *historyv1.RespondActivityTaskCompletedRequest) (*historyv1.RespondActivityTaskCompletedResponse, error) {
+	if s.completions != nil {
+		return s.completions.Complete(ctx, req)
+	}
+	return s.historyClient.RespondActivityTaskCompleted(ctx, req)
+}
+
+// recordActivityPending leaves the activity open in history and hands its
+// completion token to the executor; the result arrives later through the
+// frontend async-activity endpoints.
+func (s *Service) recordActivityPending(ctx context.Context, task *poller.Task, resp *executor.ExecuteResponse) (*poller.TaskResult, error) {
+	timeout := resp.Pending.ScheduleToCloseTimeout
+	if timeout <= 0 {
+		timeout = s.asyncTimeout
+	}
+
+	pendingReq := &historyv1.RecordActivityTaskPendingRequest{
+		Namespace: task.Namespace,
+		WorkflowExecution: &commonv1.WorkflowExecution{
+			WorkflowId: task.WorkflowID,
+			RunId:      task.RunID,
+		},
+		ScheduledEventId:       task.ScheduledEventID,
+		NodeId:                 task.NodeID,
+		Identity:               s.identity,
+		ScheduleToCloseTimeout: durationpb.New(timeout),
+		WaitSignalName:         resp.Pending.WaitSignal,
+	}
+	if len(resp.Pending.TimeoutResult) > 0 {
+		pendingReq.TimeoutResult = &commonv1.Payloads{Payloads: []*commonv1.Payload{{Data: resp.Pending.TimeoutResult}}}
+	}
+	pendingResp, err := s.historyClient.RecordActivityTaskPending(ctx, pendingReq)
+	if err != nil {
+		s.logger.Error("failed to record pending activity",
+			slog.String("workflow_id", task.WorkflowID),
+			slog.String("node_id", task.NodeID),
+			slog.String("error", err.Error()),
+		)
+		return &poller.TaskResult{Error: err.Error()}, err
+	}
+
+	token := string(pendingResp.GetTaskToken())
+	if resp.Pending.OnPending != nil {
+		if err := resp.Pending.OnPending(ctx, token); err != nil {
+			// The activity stays pending; it can still be completed with the
+			// token or will time out.
+			s.logger.Warn("async activity notification failed",
+				slog.String("workflow_id", task.WorkflowID),
+				slog.String("node_id", task.NodeID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	s.logger.Info("activity waiting for async completion",
+		slog.String("workflow_id", task.WorkflowID),
+		slog.String("node_id", task.NodeID),
+		slog.Duration("schedule_to_close_timeout", timeout),
+	)
+
+	output, _ := json.Marshal(map[string]string{"status": "pending", "task_token": token})
+	return &poller.TaskResult{TaskID: task.TaskID, Output: output}, nil
+}
+
 func (s *Service) hydrateActivityTaskFromHistory(ctx context.Context, task *poller.Task) error {
 	historyResp, err := s.historyClient.GetHistory(ctx, task.Namespace, task.WorkflowID, task.RunID)
 	if err != nil {
@@ -407,6 +666,11 @@ func (s *Service) hydrateActivityTaskFromHistory(ctx context.Context, task *poll
 
 		task.NodeID = attr.GetNodeId()
 		task.NodeType = attr.GetNodeType()
+		task.StartToCloseSec = int32(attr.GetStartToCloseTimeout().AsDuration() / time.Second)
+		task.ScheduleToCloseSec = int32(attr.GetScheduleToCloseTimeout().AsDuration() / time.Second)
+		if event.GetEventTime() != nil {
+			task.ScheduledTime = event.GetEventTime().AsTime()
+		}
 
 		if input := attr.GetInput(); input != nil && len(input.GetPayloads()) > 0 {
 			raw := input.GetPayloads()[0].GetData()
@@ -501,87 +765,35 @@ func (s *Service) collectExecutionNodesForCallback(ctx context.Context, task *po
 		return nil, err
 	}
 
-	events := historyResp.GetHistory().GetEvents()
-	nodeByScheduledEventID := make(map[int64]map[string]interface{})
-	nodeByNodeID := make(map[string]map[string]interface{})
-
-	sequence := 0
-	for _, event := range events {
-		switch event.GetEventType() {
-		case commonv1.EventType_EVENT_TYPE_NODE_SCHEDULED:
-			attr := event.GetNodeScheduledAttributes()
-			if attr == nil {
-				continue
-			}
-
-			sequence++
-			node := map[string]interface{}{
-				"node_id":    attr.GetNodeId(),
-				"node_type":  attr.GetNodeType(),
-				"node_name":  attr.GetName(),
-				"status":     "running",
-				"started_at": event.GetEventTime().AsTime().UTC().Format(time.RFC3339Nano),
-				"sequence":   sequence,
-			}
-			nodeByScheduledEventID[event.GetEventId()] = node
-			nodeByNodeID[attr.GetNodeId()] = node
-
-		case commonv1.EventType_EVENT_TYPE_NODE_COMPLETED:
-			attr := event.GetNodeCompletedAttributes()
-			if attr == nil {
-				continue
-			}
-
-			node, ok := nodeByScheduledEventID[attr.GetScheduledEventId()]
-			if !ok {
-				continue
-			}
-
-			node["status"] = "completed"
-			node["completed_at"] = event.GetEventTime().AsTime().UTC().Format(time.RFC3339Nano)
-
-			if result := attr.GetResult(); result != nil && len(result.GetPayloads()) > 0 {
-				output := map[string]interface{}{}
-				if err := json.Unmarshal(result.GetPayloads()[0].GetData(), &output); err == nil {
-					node["output"] = output
-				}
-			}
-
-		case commonv1.EventType_EVENT_TYPE_NODE_FAILED:
-			attr := event.GetNodeFailedAttributes()
-			if attr == nil {
-				continue
-			}
-
-			node, ok := nodeByScheduledEventID[attr.GetScheduledEventId()]
-			if !ok {
-				continue
-			}
-
-			node["status"] = "failed"
-			node["completed_at"] = event.GetEventTime().AsTime().UTC().Format(time.RFC3339Nano)
-
-			errMsg := "node execution failed"
-			if failure := attr.GetFailure(); failure != nil && failure.GetMessage() != "" {
-				errMsg = failure.GetMessage()
+	timelineNodes := timeline.Build(historyResp.GetHistory().GetEvents())
+	nodes := make([]map[string]interface{}, 0, len(timelineNodes))
+	for _, tn := range timelineNodes {
+		node := map[string]interface{}{
+			"node_id":    tn.NodeID,
+			"node_type":  tn.NodeType,
+			"node_name":  tn.Name,
+			"status":     tn.Status,
+			"started_at": tn.StartedAt.Format(time.RFC3339Nano),
+			"sequence":   tn.Sequence,
+			"attempts":   tn.Attempts,
+		}
+		if tn.CompletedAt != nil {
+			node["completed_at"] = tn.CompletedAt.Format(time.RFC3339Nano)
+		}
+		if len(tn.Output) > 0 {
+			output := map[string]interface{}{}
+			if err := json.Unmarshal(tn.Output, &output); err == nil {
+				node["output"] = output
 			}
+		}
+		if tn.Error != "" {
 			node["error"] = map[string]interface{}{
-				"message": errMsg,
+				"message": tn.Error,
 			}
 		}
-	}
-
-	nodes := make([]map[string]interface{}, 0, len(nodeByNodeID))
-	for _, node := range nodeByNodeID {
 		nodes = append(nodes, node)
 	}
 
-	sort.Slice(nodes, func(i, j int) bool {
-		li, _ := nodes[i]["sequence"].(int)
-		lj, _ := nodes[j]["sequence"].(int)
-		return li < lj
-	})
-
 	return nodes, nil
 }
 
@@ -669,6 +881,7 @@ func (s *Service) sendLegacyCallback(payload *executor.JobPayload, status string
 		"job_id":         payload.JobID,
 		"callback_token": payload.CallbackToken,
 		"execution_id":   payload.ExecutionID,
+		"workspace_id":   payload.WorkspaceID,
 		"status":         status,
 		"duration_ms":    duration.Milliseconds(),
 	}
@@ -685,26 +898,7 @@ func (s *Service) sendLegacyCallback(payload *executor.JobPayload, status string
 		return
 	}
 
-	for attempt := 1; attempt <= 3; attempt++ {
-		reqCtx, cancel := context.WithTimeout(context.Background(), s.callbackHTTP.Timeout)
-		err = s.postLegacyCallback(reqCtx, payload.CallbackURL, bodyBytes)
-		cancel()
-
-		if err == nil {
-			return
-		}
-
-		s.logger.Warn("failed to send workflow callback",
-			slog.String("job_id", payload.JobID),
-			slog.String("status", status),
-			slog.Int("attempt", attempt),
-			slog.String("error", err.Error()),
-		)
-
-		if attempt < 3 {
-			time.Sleep(time.Duration(attempt) * time.Second)
-		}
-	}
+	s.deliverLegacyCallback(payload.CallbackURL, bodyBytes, payload.JobID, status)
 }
 
 func (s *Service) sendLegacyProgress(payload *executor.JobPayload, currentNode string, progress int, resp *executor.ExecuteResponse) {
@@ -718,6 +912,8 @@ func (s *Service) sendLegacyProgress(payload *executor.JobPayload, currentNode s
 	body := map[string]interface{}{
 		"job_id":         payload.JobID,
 		"callback_token": payload.CallbackToken,
+		"execution_id":   payload.ExecutionID,
+		"workspace_id":   payload.WorkspaceID,
 		"progress":       progress,
 		"current_node":   currentNode,
 	}
@@ -728,6 +924,40 @@ func (s *Service) sendLegacyProgress(payload *executor.JobPayload, currentNode s
 		body["deterministic_fixtures"] = resp.DeterministicFixtures
 	}
 
+	s.postLegacyProgress(payload.ProgressURL, body)
+}
+
+// partialProgressReporter returns the ExecuteRequest.Progress hook for a job,
+// or nil when the job has no progress callback. Reports are posted in the
+// background; one that arrives while the previous post is still in flight is
+// dropped, since a later report supersedes it anyway.
+func (s *Service) partialProgressReporter(payload *executor.JobPayload, currentNode string) func(int, string) {
+	if payload == nil || payload.ProgressURL == "" || payload.JobID == "" || payload.CallbackToken == "" {
+		return nil
+	}
+
+	var inFlight atomic.Bool
+	return func(progress int, partial string) {
+		if !inFlight.CompareAndSwap(false, true) {
+			return
+		}
+		body := map[string]interface{}{
+			"job_id":         payload.JobID,
+			"callback_token": payload.CallbackToken,
+			"execution_id":   payload.ExecutionID,
+			"workspace_id":   payload.WorkspaceID,
+			"progress":       progress,
+			"current_node":   currentNode,
+			"partial_output": partial,
+		}
+		go func() {
+			defer inFlight.Store(false)
+			s.postLegacyProgress(payload.ProgressURL, body)
+		}()
+	}
+}
+
+func (s *Service) postLegacyProgress(progressURL string, body map[string]interface{}) {
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
 		s.logger.Error("failed to marshal progress payload", slog.String("error", err.Error()))
@@ -737,7 +967,7 @@ func (s *Service) sendLegacyProgress(payload *executor.JobPayload, currentNode s
 	reqCtx, cancel := context.WithTimeout(context.Background(), s.callbackHTTP.Timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, payload.ProgressURL, bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, progressURL, bytes.NewReader(bodyBytes))
 	if err != nil {
 		s.logger.Warn("failed to build progress callback request", slog.String("error", err.Error()))
 		return
@@ -779,7 +1009,11 @@ func (s *Service) postLegacyCallback(ctx context.Context, callbackURL string, bo
 
 	if resp.StatusCode >= 400 {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("callback returned status %d: %s", resp.StatusCode, string(respBody))
+		return &callbackStatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Body:       string(respBody),
+		}
 	}
 
 	return nil
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/linkflow/engine/internal/worker/executor"
+	"github.com/linkflow/engine/internal/worker/poller"
+)
+
+// defaultStartToCloseTimeout bounds an attempt when neither the task nor the
+// node config sets a timeout.
+const defaultStartToCloseTimeout = 60 * time.Second
+
+var (
+	errStartToCloseTimeout    = errors.New("start-to-close timeout")
+	errScheduleToCloseTimeout = errors.New("schedule-to-close timeout")
+)
+
+// activityTimeoutConfig holds the timeout keys shared by all node configs.
+type activityTimeoutConfig struct {
+	StartToCloseTimeout    int `json:"start_to_close_timeout"`    // Seconds per attempt
+	ScheduleToCloseTimeout int `json:"schedule_to_close_timeout"` // Seconds across all attempts
+}
+
+// activityTimeouts returns the per-attempt and overall timeouts of an activity
+// task. Timeouts recorded by history on the scheduled event take precedence
+// over the node config; tasks without either fall back to TimeoutSec.
+func activityTimeouts(task *poller.Task) (startToClose, scheduleToClose time.Duration) {
+	startToClose = time.Duration(task.StartToCloseSec) * time.Second
+	scheduleToClose = time.Duration(task.ScheduleToCloseSec) * time.Second
+
+	var config activityTimeoutConfig
+	if len(task.Config) > 0 && json.Unmarshal(task.Config, &config) == nil {
+		if startToClose <= 0 && config.StartToCloseTimeout > 0 {
+			startToClose = time.Duration(config.StartToCloseTimeout) * time.Second
+		}
+		if scheduleToClose <= 0 && config.ScheduleToCloseTimeout > 0 {
+			scheduleToClose = time.Duration(config.ScheduleToCloseTimeout) * time.Second
+		}
+	}
+
+	if startToClose <= 0 {
+		startToClose = time.Duration(task.TimeoutSec) * time.Second
+	}
+	if startToClose <= 0 {
+		startToClose = defaultStartToCloseTimeout
+	}
+	return startToClose, scheduleToClose
+}
+
+// executeWithTimeouts runs an activity attempt under its start-to-close
+// timeout and, when history recorded when the activity was scheduled, under
+// its schedule-to-close deadline. An attempt cut short by either is reported
+// as an ErrorTypeTimeout failure naming the timeout that tripped.
+func executeWithTimeouts(ctx context.Context, exec executor.Executor, req *executor.ExecuteRequest, task *poller.Task) (*executor.ExecuteResponse, error) {
+	startToClose, scheduleToClose := activityTimeouts(task)
+	req.Timeout = startToClose
+
+	timeoutResponse := func(cause error) *executor.ExecuteResponse {
+		message := fmt.Sprintf("%s: attempt %d did not finish within %s", cause, req.Attempt, startToClose)
+		if errors.Is(cause, errScheduleToCloseTimeout) {
+			message = fmt.Sprintf("%s: activity did not finish within %s of being scheduled", cause, scheduleToClose)
+		}
+		return &executor.ExecuteResponse{
+			Error: &executor.ExecutionError{Message: message, Type: executor.ErrorTypeTimeout},
+		}
+	}
+
+	execCtx, cancel := context.WithTimeoutCause(ctx, startToClose, errStartToCloseTimeout)
+	defer cancel()
+
+	if scheduleToClose > 0 && !task.ScheduledTime.IsZero() {
+		deadline := task.ScheduledTime.Add(scheduleToClose)
+		if !time.Now().Before(deadline) {
+			// The activity ran out of time before this attempt started,
+			// e.g. while it waited in the queue or across earlier attempts.
+			return timeoutResponse(errScheduleToCloseTimeout), nil
+		}
+		var cancelDeadline context.CancelFunc
+		execCtx, cancelDeadline = context.WithDeadlineCause(execCtx, deadline, errScheduleToCloseTimeout)
+		defer cancelDeadline()
+	}
+
+	resp, err := exec.Execute(execCtx, req)
+	if execCtx.Err() == nil || (err == nil && resp != nil && resp.Error == nil) {
+		return resp, err
+	}
+
+	cause := context.Cause(execCtx)
+	if !errors.Is(cause, errStartToCloseTimeout) && !errors.Is(cause, errScheduleToCloseTimeout) {
+		return resp, err
+	}
+	timedOut := timeoutResponse(cause)
+	if resp != nil {
+		timedOut.Logs = resp.Logs
+		timedOut.Duration = resp.Duration
+	}
+	return timedOut, nil
+}
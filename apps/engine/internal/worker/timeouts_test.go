@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkflow/engine/internal/worker/executor"
+	"github.com/linkflow/engine/internal/worker/poller"
+)
+
+// blockingExecutor runs until its context is done, then fails like an
+// executor whose outbound call was cancelled.
+type blockingExecutor struct {
+	calls int
+}
+
+func (e *blockingExecutor) NodeType() string              { return "blocking" }
+func (e *blockingExecutor) OutputSchema() json.RawMessage { return nil }
+
+func (e *blockingExecutor) Execute(ctx context.Context, _ *executor.ExecuteRequest) (*executor.ExecuteResponse, error) {
+	e.calls++
+	<-ctx.Done()
+	return &executor.ExecuteResponse{
+		Error: &executor.ExecutionError{Message: ctx.Err().Error(), Type: executor.ErrorTypeRetryable},
+	}, nil
+}
+
+func TestActivityTimeoutsPrecedence(t *testing.T) {
+	task := &poller.Task{
+		TimeoutSec: 60,
+		Config:     json.RawMessage(`{"start_to_close_timeout": 30, "schedule_to_close_timeout": 600}`),
+	}
+	startToClose, scheduleToClose := activityTimeouts(task)
+	if startToClose != 30*time.Second || scheduleToClose != 10*time.Minute {
+		t.Fatalf("node config: got %s/%s", startToClose, scheduleToClose)
+	}
+
+	task.StartToCloseSec = 5
+	if startToClose, _ = activityTimeouts(task); startToClose != 5*time.Second {
+		t.Fatalf("history timeout should win, got %s", startToClose)
+	}
+
+	startToClose, scheduleToClose = activityTimeouts(&poller.Task{TimeoutSec: 60})
+	if startToClose != time.Minute || scheduleToClose != 0 {
+		t.Fatalf("legacy fallback: got %s/%s", startToClose, scheduleToClose)
+	}
+}
+
+func TestExecuteWithTimeoutsReportsWhichTimeoutTripped(t *testing.T) {
+	cases := []struct {
+		name string
+		task *poller.Task
+		want string
+	}{
+		{
+			name: "start-to-close",
+			task: &poller.Task{
+				Config:             json.RawMessage(`{"start_to_close_timeout": 1}`),
+				ScheduleToCloseSec: 60,
+				ScheduledTime:      time.Now(),
+			},
+			want: "start-to-close timeout: attempt 2 did not finish within 1s",
+		},
+		{
+			name: "schedule-to-close",
+			task: &poller.Task{
+				StartToCloseSec:    60,
+				ScheduleToCloseSec: 2,
+				ScheduledTime:      time.Now().Add(-time.Second),
+			},
+			want: "schedule-to-close timeout: activity did not finish within 2s of being scheduled",
+		},
+	}
+
+	for _, c := range cases {
+		exec := &blockingExecutor{}
+		resp, err := executeWithTimeouts(context.Background(), exec, &executor.ExecuteRequest{Attempt: 2}, c.task)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", c.name, err)
+		}
+		if resp.Error == nil || resp.Error.Type != executor.ErrorTypeTimeout {
+			t.Fatalf("%s: expected a timeout failure, got %+v", c.name, resp.Error)
+		}
+		if resp.Error.Message != c.want {
+			t.Fatalf("%s: message = %q, want %q", c.name, resp.Error.Message, c.want)
+		}
+	}
+}
+
+func TestExecuteWithTimeoutsSkipsExpiredActivity(t *testing.T) {
+	exec := &blockingExecutor{}
+	task := &poller.Task{
+		ScheduleToCloseSec: 1,
+		ScheduledTime:      time.Now().Add(-time.Minute),
+	}
+	resp, err := executeWithTimeouts(context.Background(), exec, &executor.ExecuteRequest{Attempt: 3}, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exec.calls != 0 {
+		t.Fatalf("executor ran %d times after the schedule-to-close deadline", exec.calls)
+	}
+	if resp.Error == nil || !strings.HasPrefix(resp.Error.Message, "schedule-to-close timeout") {
+		t.Fatalf("unexpected response %+v", resp.Error)
+	}
+}
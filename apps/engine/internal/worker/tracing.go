@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/linkflow/engine/internal/observability/tracing"
+	"github.com/linkflow/engine/internal/worker/executor"
+)
+
+// executeTraced runs execute in a span named after the node type, so the
+// time spent in each node of a run shows up in its trace. The executor sees
+// the span in its context and may add events to it, as the HTTP executor
+// does for connection timings. With no tracer it just runs execute.
+func (s *Service) executeTraced(ctx context.Context, req *executor.ExecuteRequest, execute func(context.Context) (*executor.ExecuteResponse, error)) (*executor.ExecuteResponse, error) {
+	ctx, span := s.tracer.Start(ctx, req.NodeType)
+	if span == nil {
+		return execute(ctx)
+	}
+	span.SetAttributes(map[string]interface{}{
+		"node.id":         req.NodeID,
+		"node.type":       req.NodeType,
+		"node.attempt":    req.Attempt,
+		"workflow.id":     req.WorkflowID,
+		"workflow.run_id": req.RunID,
+		"namespace":       req.Namespace,
+	})
+
+	resp, err := execute(ctx)
+	recordNodeOutcome(span, resp, err)
+	span.End()
+	return resp, err
+}
+
+// recordNodeOutcome records how the node ended on its span, which is still
+// open. A response without a duration takes the span's so the two agree.
+func recordNodeOutcome(span *tracing.Span, resp *executor.ExecuteResponse, err error) {
+	switch {
+	case err != nil:
+		span.SetAttribute("node.outcome", "system_error")
+		span.RecordError(err)
+		return
+	case resp == nil:
+		span.SetAttribute("node.outcome", "no_response")
+		return
+	}
+
+	if resp.Duration == 0 {
+		resp.Duration = span.Duration()
+	}
+	span.SetAttribute("node.duration_ms", resp.Duration.Milliseconds())
+
+	for _, attempt := range resp.ConnectorAttempts {
+		span.AddEvent("connector_attempt", map[string]interface{}{
+			"connector_key":       attempt.ConnectorKey,
+			"connector_operation": attempt.ConnectorOperation,
+			"status":              attempt.Status,
+			"status_code":         attempt.StatusCode,
+			"duration_ms":         attempt.DurationMS,
+			"attempt_no":          attempt.AttemptNo,
+		})
+	}
+
+	switch {
+	case resp.Error != nil:
+		span.SetAttribute("node.outcome", strings.ToLower(resp.Error.Type))
+		span.SetStatus(tracing.SpanStatusError, resp.Error.Message)
+	case resp.Pending != nil:
+		span.SetAttribute("node.outcome", "pending")
+		span.SetStatus(tracing.SpanStatusOK, "")
+	default:
+		span.SetAttribute("node.outcome", "success")
+		span.SetStatus(tracing.SpanStatusOK, "")
+	}
+}
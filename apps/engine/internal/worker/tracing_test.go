@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/linkflow/engine/internal/observability/tracing"
+	"github.com/linkflow/engine/internal/worker/executor"
+)
+
+func TestExecuteTracedRecordsNodeSpan(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	svc := &Service{tracer: tracing.NewTracer(tracing.TracerConfig{Exporter: exporter})}
+	req := &executor.ExecuteRequest{NodeType: "action_http_request", NodeID: "fetch", WorkflowID: "wf-1", RunID: "run-1", Attempt: 2}
+
+	resp, err := svc.executeTraced(context.Background(), req, func(ctx context.Context) (*executor.ExecuteResponse, error) {
+		if tracing.SpanFromContext(ctx) == nil {
+			t.Error("executor context carries no span")
+		}
+		return &executor.ExecuteResponse{
+			Error:             &executor.ExecutionError{Message: "server error: status 503", Type: executor.ErrorTypeRetryable},
+			ConnectorAttempts: []executor.ConnectorAttempt{{ConnectorKey: "action_http_request", Status: "server_error", StatusCode: 503, DurationMS: 12}},
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "action_http_request" || span.Attributes["node.id"] != "fetch" || span.Attributes["node.attempt"] != int32(2) {
+		t.Fatalf("span = %s %v", span.Name, span.Attributes)
+	}
+	if span.Attributes["node.outcome"] != "retryable" || span.Status != tracing.SpanStatusError {
+		t.Fatalf("outcome = %v, status %d", span.Attributes["node.outcome"], span.Status)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "connector_attempt" || span.Events[0].Attributes["duration_ms"] != int64(12) {
+		t.Fatalf("events = %+v", span.Events)
+	}
+	// The response takes the span's duration when it reports none.
+	if resp.Duration <= 0 || resp.Duration > span.Duration() {
+		t.Fatalf("response duration %v, span %v", resp.Duration, span.Duration())
+	}
+
+	_, _ = svc.executeTraced(context.Background(), req, func(context.Context) (*executor.ExecuteResponse, error) {
+		return nil, errors.New("worker crashed")
+	})
+	if span := exporter.Spans()[1]; span.Attributes["node.outcome"] != "system_error" || span.StatusMsg != "worker crashed" {
+		t.Fatalf("system error span = %v, %q", span.Attributes, span.StatusMsg)
+	}
+}
+
+func TestExecuteTracedWithoutTracer(t *testing.T) {
+	svc := &Service{}
+	resp, err := svc.executeTraced(context.Background(), &executor.ExecuteRequest{NodeType: "delay"}, func(ctx context.Context) (*executor.ExecuteResponse, error) {
+		if tracing.SpanFromContext(ctx) != nil {
+			t.Error("span started without a tracer")
+		}
+		return &executor.ExecuteResponse{}, nil
+	})
+	if err != nil || resp == nil || resp.Duration != 0 {
+		t.Fatalf("untraced execute = %+v, %v", resp, err)
+	}
+}
@@ -0,0 +1,3 @@
+-- Rollback saved search queries
+
+DROP TABLE IF EXISTS saved_search_queries;
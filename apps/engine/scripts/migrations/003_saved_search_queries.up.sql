@@ -0,0 +1,11 @@
+-- =============================================================================
+-- SAVED_SEARCH_QUERIES (named visibility queries per namespace)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS saved_search_queries (
+    namespace_id    VARCHAR(255) NOT NULL,
+    name            VARCHAR(255) NOT NULL,
+    query           TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace_id, name)
+);
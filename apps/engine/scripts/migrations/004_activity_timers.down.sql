@@ -0,0 +1,4 @@
+-- Rollback activity timers
+
+ALTER TABLE timers DROP COLUMN IF EXISTS result;
+ALTER TABLE timers DROP COLUMN IF EXISTS task_token;
@@ -0,0 +1,5 @@
+-- =============================================================================
+-- TIMERS (activity timers that complete a pending delay node when fired)
+-- =============================================================================
+ALTER TABLE timers ADD COLUMN IF NOT EXISTS task_token TEXT NOT NULL DEFAULT '';
+ALTER TABLE timers ADD COLUMN IF NOT EXISTS result BYTEA;
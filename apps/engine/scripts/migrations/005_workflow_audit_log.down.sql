@@ -0,0 +1,5 @@
+-- Rollback workflow audit log
+
+DROP TRIGGER IF EXISTS tr_workflow_audit_log_immutable ON workflow_audit_log;
+DROP FUNCTION IF EXISTS reject_audit_log_change();
+DROP TABLE IF EXISTS workflow_audit_log;
@@ -0,0 +1,28 @@
+-- =============================================================================
+-- WORKFLOW_AUDIT_LOG (append-only record of execution lifecycle transitions)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS workflow_audit_log (
+    id              BIGSERIAL PRIMARY KEY,
+    namespace_id    VARCHAR(255) NOT NULL,
+    workflow_id     VARCHAR(255) NOT NULL,
+    run_id          VARCHAR(64) NOT NULL,
+    event_id        BIGINT NOT NULL,
+    event_type      VARCHAR(64) NOT NULL,
+    event_time      TIMESTAMPTZ NOT NULL,
+    recorded_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_audit_log_execution ON workflow_audit_log (namespace_id, workflow_id, run_id, event_id);
+CREATE INDEX IF NOT EXISTS idx_workflow_audit_log_time ON workflow_audit_log (event_time);
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'workflow_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tr_workflow_audit_log_immutable
+    BEFORE UPDATE OR DELETE ON workflow_audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_audit_log_change();
@@ -0,0 +1,3 @@
+-- Rollback workflow schedules
+
+DROP TABLE IF EXISTS workflow_schedules;
@@ -0,0 +1,25 @@
+-- =============================================================================
+-- WORKFLOW_SCHEDULES (cron schedules that start workflow executions)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS workflow_schedules (
+    namespace_id    VARCHAR(255) NOT NULL,
+    schedule_id     VARCHAR(255) NOT NULL,
+    workflow_id     VARCHAR(255) NOT NULL,
+    cron_expression VARCHAR(255) NOT NULL,
+    timezone        VARCHAR(64) NOT NULL DEFAULT '',
+    task_queue      VARCHAR(255) NOT NULL DEFAULT '',
+    input           BYTEA,
+    overlap_policy  VARCHAR(16) NOT NULL DEFAULT 'skip',
+    paused          BOOLEAN NOT NULL DEFAULT FALSE,
+    last_fire_time  TIMESTAMPTZ,
+    next_fire_time  TIMESTAMPTZ,
+    last_run_id     VARCHAR(64) NOT NULL DEFAULT '',
+    buffered_starts INTEGER NOT NULL DEFAULT 0,
+    version         BIGINT NOT NULL DEFAULT 1,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace_id, schedule_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_schedules_due ON workflow_schedules (next_fire_time) WHERE paused = FALSE;
+CREATE INDEX IF NOT EXISTS idx_workflow_schedules_workflow ON workflow_schedules (namespace_id, workflow_id);
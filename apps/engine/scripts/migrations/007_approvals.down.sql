@@ -0,0 +1,3 @@
+-- Rollback approvals
+
+DROP TABLE IF EXISTS approvals;
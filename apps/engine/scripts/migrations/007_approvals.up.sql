@@ -0,0 +1,25 @@
+-- =============================================================================
+-- APPROVALS (decisions requested by approval nodes)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS approvals (
+    id              VARCHAR(64) PRIMARY KEY,
+    namespace_id    VARCHAR(255) NOT NULL,
+    workflow_id     VARCHAR(255) NOT NULL,
+    run_id          VARCHAR(64) NOT NULL,
+    node_id         VARCHAR(255) NOT NULL,
+    task_token      TEXT NOT NULL,
+    title           VARCHAR(255) NOT NULL DEFAULT '',
+    reason          TEXT NOT NULL DEFAULT '',
+    approvers       TEXT[] NOT NULL DEFAULT '{}',
+    payload         JSONB,
+    on_reject       VARCHAR(16) NOT NULL DEFAULT 'fail',
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ,
+    decided_by      VARCHAR(255) NOT NULL DEFAULT '',
+    comment         TEXT NOT NULL DEFAULT '',
+    decided_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_approvals_status ON approvals (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_approvals_execution ON approvals (namespace_id, workflow_id, run_id);
@@ -0,0 +1,3 @@
+-- Rollback role assignments
+
+DROP TABLE IF EXISTS role_assignments;
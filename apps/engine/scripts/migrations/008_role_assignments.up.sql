@@ -0,0 +1,9 @@
+-- =============================================================================
+-- ROLE ASSIGNMENTS (control plane RBAC for admin endpoints)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS role_assignments (
+    identity        VARCHAR(255) PRIMARY KEY,
+    role            VARCHAR(32) NOT NULL,
+    assigned_by     VARCHAR(255) NOT NULL DEFAULT '',
+    assigned_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
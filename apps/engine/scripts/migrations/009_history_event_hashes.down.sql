@@ -0,0 +1,4 @@
+-- Rollback history event hash chain
+
+ALTER TABLE history_events DROP COLUMN IF EXISTS hash;
+ALTER TABLE history_events DROP COLUMN IF EXISTS prev_hash;
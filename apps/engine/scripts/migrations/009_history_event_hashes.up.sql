@@ -0,0 +1,7 @@
+-- =============================================================================
+-- HISTORY EVENT HASH CHAIN (tamper evidence for opted-in namespaces)
+-- =============================================================================
+-- hash = SHA-256(prev_hash || canonical event); both are NULL for events of
+-- namespaces that do not chain hashes and for events written before this.
+ALTER TABLE history_events ADD COLUMN IF NOT EXISTS prev_hash BYTEA;
+ALTER TABLE history_events ADD COLUMN IF NOT EXISTS hash BYTEA;
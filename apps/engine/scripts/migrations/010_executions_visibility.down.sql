@@ -0,0 +1,3 @@
+-- Rollback executions visibility
+
+DROP TABLE IF EXISTS executions_visibility;
@@ -0,0 +1,23 @@
+-- =============================================================================
+-- EXECUTIONS VISIBILITY (history service listing and child lookups)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS executions_visibility (
+    namespace_id        VARCHAR(64) NOT NULL,
+    workflow_id         VARCHAR(255) NOT NULL,
+    run_id              VARCHAR(64) NOT NULL,
+    workflow_type       VARCHAR(255) NOT NULL,
+    start_time          TIMESTAMP NOT NULL,
+    close_time          TIMESTAMP,
+    status              INT NOT NULL,
+    history_length      BIGINT,
+    memo                BYTEA,
+    PRIMARY KEY (namespace_id, run_id)
+);
+
+-- Tables created before child workflows lack these; '' marks a top-level run.
+ALTER TABLE executions_visibility ADD COLUMN IF NOT EXISTS parent_workflow_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE executions_visibility ADD COLUMN IF NOT EXISTS parent_run_id VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_visibility_open ON executions_visibility (namespace_id, start_time DESC) WHERE status = 1;
+CREATE INDEX IF NOT EXISTS idx_visibility_closed ON executions_visibility (namespace_id, close_time DESC) WHERE status != 1;
+CREATE INDEX IF NOT EXISTS idx_visibility_parent ON executions_visibility (namespace_id, parent_workflow_id, parent_run_id) WHERE parent_workflow_id != '';
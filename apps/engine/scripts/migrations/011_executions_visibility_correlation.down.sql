@@ -0,0 +1,4 @@
+-- Rollback executions visibility correlation IDs
+
+DROP INDEX IF EXISTS idx_visibility_correlation;
+ALTER TABLE executions_visibility DROP COLUMN IF EXISTS correlation_id;
@@ -0,0 +1,7 @@
+-- =============================================================================
+-- EXECUTIONS VISIBILITY CORRELATION IDS
+-- =============================================================================
+-- '' marks a run started without a correlation ID.
+ALTER TABLE executions_visibility ADD COLUMN IF NOT EXISTS correlation_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_visibility_correlation ON executions_visibility (namespace_id, correlation_id, start_time DESC, run_id DESC) WHERE correlation_id != '';
@@ -59,6 +59,8 @@ CREATE TABLE IF NOT EXISTS history_events (
     version         BIGINT NOT NULL,
     timestamp       TIMESTAMPTZ NOT NULL,
     data            BYTEA NOT NULL,
+    prev_hash       BYTEA,
+    hash            BYTEA,
     PRIMARY KEY (shard_id, namespace_id, workflow_id, run_id, event_id)
 );
 
@@ -117,6 +119,8 @@ CREATE TABLE IF NOT EXISTS timers (
     version         BIGINT NOT NULL DEFAULT 1,
     created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     fired_at        TIMESTAMPTZ,
+    task_token      TEXT NOT NULL DEFAULT '',
+    result          BYTEA,
     PRIMARY KEY (shard_id, namespace_id, workflow_id, run_id, timer_id)
 );
 
@@ -160,6 +164,121 @@ CREATE TABLE IF NOT EXISTS task_queues (
 
 CREATE INDEX idx_task_queues_namespace ON task_queues (namespace_id, name);
 
+-- =============================================================================
+-- SAVED_SEARCH_QUERIES (named visibility queries per namespace)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS saved_search_queries (
+    namespace_id    VARCHAR(255) NOT NULL,
+    name            VARCHAR(255) NOT NULL,
+    query           TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace_id, name)
+);
+
+-- =============================================================================
+-- WORKFLOW_AUDIT_LOG (append-only record of execution lifecycle transitions)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS workflow_audit_log (
+    id              BIGSERIAL PRIMARY KEY,
+    namespace_id    VARCHAR(255) NOT NULL,
+    workflow_id     VARCHAR(255) NOT NULL,
+    run_id          VARCHAR(64) NOT NULL,
+    event_id        BIGINT NOT NULL,
+    event_type      VARCHAR(64) NOT NULL,
+    event_time      TIMESTAMPTZ NOT NULL,
+    recorded_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_workflow_audit_log_execution ON workflow_audit_log (namespace_id, workflow_id, run_id, event_id);
+CREATE INDEX idx_workflow_audit_log_time ON workflow_audit_log (event_time);
+
+-- =============================================================================
+-- WORKFLOW_SCHEDULES (cron schedules that start workflow executions)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS workflow_schedules (
+    namespace_id    VARCHAR(255) NOT NULL,
+    schedule_id     VARCHAR(255) NOT NULL,
+    workflow_id     VARCHAR(255) NOT NULL,
+    cron_expression VARCHAR(255) NOT NULL,
+    timezone        VARCHAR(64) NOT NULL DEFAULT '',
+    task_queue      VARCHAR(255) NOT NULL DEFAULT '',
+    input           BYTEA,
+    overlap_policy  VARCHAR(16) NOT NULL DEFAULT 'skip',
+    paused          BOOLEAN NOT NULL DEFAULT FALSE,
+    last_fire_time  TIMESTAMPTZ,
+    next_fire_time  TIMESTAMPTZ,
+    last_run_id     VARCHAR(64) NOT NULL DEFAULT '',
+    buffered_starts INTEGER NOT NULL DEFAULT 0,
+    version         BIGINT NOT NULL DEFAULT 1,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (namespace_id, schedule_id)
+);
+
+CREATE INDEX idx_workflow_schedules_due ON workflow_schedules (next_fire_time) WHERE paused = FALSE;
+CREATE INDEX idx_workflow_schedules_workflow ON workflow_schedules (namespace_id, workflow_id);
+
+-- =============================================================================
+-- APPROVALS (decisions requested by approval nodes)
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS approvals (
+    id              VARCHAR(64) PRIMARY KEY,
+    namespace_id    VARCHAR(255) NOT NULL,
+    workflow_id     VARCHAR(255) NOT NULL,
+    run_id          VARCHAR(64) NOT NULL,
+    node_id         VARCHAR(255) NOT NULL,
+    task_token      TEXT NOT NULL,
+    title           VARCHAR(255) NOT NULL DEFAULT '',
+    reason          TEXT NOT NULL DEFAULT '',
+    approvers       TEXT[] NOT NULL DEFAULT '{}',
+    payload         JSONB,
+    on_reject       VARCHAR(16) NOT NULL DEFAULT 'fail',
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ,
+    decided_by      VARCHAR(255) NOT NULL DEFAULT '',
+    comment         TEXT NOT NULL DEFAULT '',
+    decided_at      TIMESTAMPTZ
+);
+
+CREATE INDEX idx_approvals_status ON approvals (status, created_at);
+CREATE INDEX idx_approvals_execution ON approvals (namespace_id, workflow_id, run_id);
+
+-- =============================================================================
+-- ROLE ASSIGNMENTS (control plane RBAC for admin endpoints)
+-- =============================================================================
+CREATE TABLE role_assignments (
+    identity        VARCHAR(255) PRIMARY KEY,
+    role            VARCHAR(32) NOT NULL,
+    assigned_by     VARCHAR(255) NOT NULL DEFAULT '',
+    assigned_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- =============================================================================
+-- EXECUTIONS VISIBILITY (history service listing, child and correlation lookups)
+-- =============================================================================
+CREATE TABLE executions_visibility (
+    namespace_id        VARCHAR(64) NOT NULL,
+    workflow_id         VARCHAR(255) NOT NULL,
+    run_id              VARCHAR(64) NOT NULL,
+    workflow_type       VARCHAR(255) NOT NULL,
+    start_time          TIMESTAMP NOT NULL,
+    close_time          TIMESTAMP,
+    status              INT NOT NULL,
+    history_length      BIGINT,
+    memo                BYTEA,
+    parent_workflow_id  VARCHAR(255) NOT NULL DEFAULT '',
+    parent_run_id       VARCHAR(64) NOT NULL DEFAULT '',
+    correlation_id      VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (namespace_id, run_id)
+);
+
+CREATE INDEX idx_visibility_open ON executions_visibility (namespace_id, start_time DESC) WHERE status = 1;
+CREATE INDEX idx_visibility_closed ON executions_visibility (namespace_id, close_time DESC) WHERE status != 1;
+CREATE INDEX idx_visibility_parent ON executions_visibility (namespace_id, parent_workflow_id, parent_run_id) WHERE parent_workflow_id != '';
+CREATE INDEX idx_visibility_correlation ON executions_visibility (namespace_id, correlation_id, start_time DESC, run_id DESC) WHERE correlation_id != '';
+
 -- =============================================================================
 -- TRIGGERS
 -- =============================================================================
@@ -175,3 +294,15 @@ CREATE TRIGGER tr_namespaces_updated_at
     BEFORE UPDATE ON namespaces
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_at();
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'workflow_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tr_workflow_audit_log_immutable
+    BEFORE UPDATE OR DELETE ON workflow_audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_audit_log_change();